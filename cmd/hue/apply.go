@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/plan"
+)
+
+// runApply implements `hue apply plan.yaml`
+func runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	autoApprove := fs.Bool("yes", false, "apply without asking for confirmation")
+	fs.BoolVar(autoApprove, "y", false, "shorthand for -yes")
+	planOnly := fs.Bool("plan", false, "only print the plan")
+	dryRun := fs.Bool("dry-run", false, "print the commands to the bridge instead of sending them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue apply [-y] [-plan] [-dry-run] plan.yaml")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one plan file")
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+	spec, err := plan.ParseSpec(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bridge, err := connectBridge(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	state, err := plan.FetchState(ctx, bridge)
+	if err != nil {
+		return err
+	}
+	p, err := plan.Build(spec, state)
+	if err != nil {
+		return err
+	}
+
+	return confirmAndApply(ctx, commandsTo(bridge, *dryRun), p, *planOnly, *autoApprove)
+}
+
+// confirmAndApply prints the plan, asks for confirmation and applies it
+func confirmAndApply(ctx context.Context, bridge plan.Bridge, p *plan.Plan, planOnly, autoApprove bool) error {
+	p.Write(os.Stdout)
+	if p.Empty() || planOnly {
+		return nil
+	}
+
+	if !autoApprove {
+		fmt.Print("\nApply these changes? Only 'yes' will be accepted: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "yes" {
+			fmt.Println("Apply cancelled.")
+			return nil
+		}
+	}
+
+	fmt.Println()
+	err := p.Apply(ctx, bridge, func(c *plan.Change, err error) {
+		status := "done"
+		if err != nil {
+			status = "failed"
+		}
+		fmt.Printf("  %s %q: %s\n", c.Kind, c.Name, status)
+	})
+	if err != nil {
+		return err
+	}
+
+	create, update := p.Counts()
+	fmt.Printf("\nApply complete! %d created, %d changed.\n", create, update)
+	return nil
+}
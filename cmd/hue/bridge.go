@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/plan"
+)
+
+// commander is a bridge taking the commands of both the UI and plans
+type commander interface {
+	api.BridgeClient
+	plan.Bridge
+}
+
+// commandsTo returns where the commands for a bridge go: the bridge itself,
+// or a dry run printing them
+func commandsTo(bridge *api.HueBridge, dryRun bool) commander {
+	if dryRun {
+		return api.NewDryRunBridge(bridge, os.Stdout)
+	}
+	return bridge
+}
+
+// connectBridge creates a client for the last used bridge
+func connectBridge(cfg *config.Config) (*api.HueBridge, error) {
+	bridgeCfg, err := cfg.GetLastBridge()
+	if err != nil {
+		return nil, fmt.Errorf("%w (run hue without arguments to pair a bridge)", err)
+	}
+
+	// Commands use CLIP v2 features such as the event stream
+	if bridgeCfg.APIVersion == api.APIVersionV1 {
+		return nil, fmt.Errorf("bridge %s only has the V1 API, which only the interactive UI supports", bridgeCfg.Host)
+	}
+
+	bridge := api.NewHueBridge(bridgeCfg.Host, bridgeCfg.Username, bridgeCfg.BridgeID)
+	policy, err := api.PolicyFor(bridgeCfg.TLSMode, bridgeCfg.CertFingerprint, cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	bridge.SetTLSPolicy(policy)
+	if bridgeCfg.IsRemote() {
+		if bridgeCfg.Remote == nil {
+			return nil, fmt.Errorf("bridge %s has a remote connection but no remote credentials", bridgeCfg.BridgeID)
+		}
+		auth := api.NewRemoteAuth(bridgeCfg.Remote.ClientID, bridgeCfg.Remote.ClientSecret, api.RemoteToken{
+			AccessToken:  bridgeCfg.Remote.AccessToken,
+			RefreshToken: bridgeCfg.Remote.RefreshToken,
+			ExpiresAt:    bridgeCfg.Remote.ExpiresAt,
+		})
+		// Each refresh also replaces the refresh token, so the new one is
+		// saved right away
+		auth.SetTokenHandler(func(token api.RemoteToken) {
+			bridgeCfg.Remote.AccessToken = token.AccessToken
+			bridgeCfg.Remote.RefreshToken = token.RefreshToken
+			bridgeCfg.Remote.ExpiresAt = token.ExpiresAt
+			if err := cfg.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to save the remote API token: %v\n", err)
+			}
+		})
+		bridge.SetRemote(auth)
+	}
+	if cfg.AuditLog {
+		audit, err := openAuditLog()
+		if err != nil {
+			return nil, err
+		}
+		bridge.SetAuditLog(audit)
+	}
+	return bridge, nil
+}
+
+// The audit log is opened once per process, by the first bridge connected
+// to, and closed on exit by closeAuditLog
+var (
+	auditLog  *api.AuditLog
+	auditFile *os.File
+)
+
+// openAuditLog returns the audit log of the process, opening it if needed
+func openAuditLog() (*api.AuditLog, error) {
+	if auditLog != nil {
+		return auditLog, nil
+	}
+	f, err := config.OpenAuditLog()
+	if err != nil {
+		return nil, err
+	}
+	auditLog, auditFile = api.NewAuditLog(f), f
+	return auditLog, nil
+}
+
+// closeAuditLog closes the audit log, if it was opened
+func closeAuditLog() error {
+	if auditFile == nil {
+		return nil
+	}
+	err := auditFile.Close()
+	auditLog, auditFile = nil, nil
+	if err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/angristan/hue-tui/internal/config"
+)
+
+// runConfig implements `hue config export-prefs > prefs.json` and
+// `hue config import-prefs prefs.json`
+func runConfig(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "export-prefs":
+			return runExportPrefs(args[1:])
+		case "import-prefs":
+			return runImportPrefs(args[1:])
+		}
+	}
+	return fmt.Errorf("usage: hue config export-prefs > prefs.json, or hue config import-prefs prefs.json")
+}
+
+// runExportPrefs prints the preferences without the bridge credentials
+func runExportPrefs(args []string) error {
+	fs := flag.NewFlagSet("config export-prefs", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue config export-prefs > prefs.json")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cfg.ExportPreferences()); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+	return nil
+}
+
+// runImportPrefs replaces the preferences with those of a file written by
+// export-prefs, keeping the paired bridges
+func runImportPrefs(args []string) error {
+	fs := flag.NewFlagSet("config import-prefs", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue config import-prefs prefs.json")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one preferences file")
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read preferences: %w", err)
+	}
+	var prefs config.PreferencesFile
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return fmt.Errorf("%s: invalid preferences: %w", fs.Arg(0), err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, id := range cfg.ImportPreferences(prefs) {
+		fmt.Fprintf(os.Stderr, "Warning: skipped the preferences of bridge %s, which is not paired here\n", id)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println("Imported preferences from " + fs.Arg(0))
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/plan"
+)
+
+// runImport implements `hue import snapshot.json`
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	autoApprove := fs.Bool("yes", false, "apply without asking for confirmation")
+	fs.BoolVar(autoApprove, "y", false, "shorthand for -yes")
+	planOnly := fs.Bool("plan", false, "only print the plan")
+	dryRun := fs.Bool("dry-run", false, "print the commands to the bridge instead of sending them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue import [-y] [-plan] [-dry-run] snapshot.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one snapshot file")
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap plan.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("%s: invalid snapshot: %w", fs.Arg(0), err)
+	}
+	if snap.Version != plan.SnapshotVersion {
+		return fmt.Errorf("%s: unsupported snapshot version %d", fs.Arg(0), snap.Version)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bridge, err := connectBridge(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	state, err := plan.FetchState(ctx, bridge)
+	if err != nil {
+		return err
+	}
+	addresses, err := bridge.GetDeviceAddresses(ctx)
+	if err != nil {
+		return err
+	}
+
+	spec, warnings := snap.Spec(state, addresses)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	p, err := plan.Build(spec, state)
+	if err != nil {
+		return err
+	}
+	return confirmAndApply(ctx, commandsTo(bridge, *dryRun), p, *planOnly, *autoApprove)
+}
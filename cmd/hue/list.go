@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/plan"
+)
+
+// runList implements `hue list --ids [lights|rooms|zones|scenes]`
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	ids := fs.Bool("ids", false, "include the bridge ID of each resource")
+	asJSON := fs.Bool("json", false, "print a JSON array, with IDs")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue list [-ids] [-json] [lights|rooms|zones|scenes...]")
+		fmt.Fprintln(fs.Output(), "Prints one tab-separated line per resource: kind, ID with -ids, name, and the room or zone it belongs to.")
+		fs.PrintDefaults()
+	}
+
+	names, err := parseNames(fs, args)
+	if err != nil {
+		return err
+	}
+	var kinds []string
+	for _, name := range names {
+		kind := strings.TrimSuffix(strings.ToLower(name), "s")
+		if !slices.Contains(plan.Kinds, kind) {
+			fs.Usage()
+			return fmt.Errorf("unknown kind %q", name)
+		}
+		kinds = append(kinds, kind)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bridge, err := connectBridge(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	state, err := plan.FetchState(ctx, bridge)
+	if err != nil {
+		return err
+	}
+
+	entries := []plan.Entry{}
+	for _, entry := range plan.List(state) {
+		if len(kinds) == 0 || slices.Contains(kinds, entry.Kind) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return fmt.Errorf("failed to write list: %w", err)
+		}
+		return nil
+	}
+	for _, entry := range entries {
+		fields := []string{entry.Kind}
+		if *ids {
+			fields = append(fields, entry.ID)
+		}
+		fields = append(fields, entry.Name, entry.Group)
+		fmt.Println(strings.Join(fields, "\t"))
+	}
+	return nil
+}
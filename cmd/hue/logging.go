@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/logging"
+)
+
+// startLogging sets up the log from the config, with HUE_DEBUG for the
+// debug level. A level or file given on the command line wins. It returns
+// the function closing the log, to call on exit.
+func startLogging(level, file string) (func() error, error) {
+	var opts logging.Options
+	if cfg, err := config.Load(); err == nil && cfg.Log != nil {
+		opts = logging.Options{
+			Level:     cfg.Log.Level,
+			Path:      cfg.Log.File,
+			MaxSizeMB: cfg.Log.MaxSizeMB,
+			MaxFiles:  cfg.Log.MaxFiles,
+		}
+	}
+	if os.Getenv("HUE_DEBUG") != "" {
+		opts.Level = logging.LevelDebug
+	}
+	if level != "" {
+		opts.Level = level
+	}
+	if file != "" {
+		opts.Path = file
+		if opts.Level == "" || opts.Level == logging.LevelOff {
+			opts.Level = logging.LevelInfo
+		}
+	}
+	return logging.Close, logging.Setup(opts)
+}
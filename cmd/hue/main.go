@@ -3,22 +3,158 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/angristan/hue-tui/internal/api"
 	"github.com/angristan/hue-tui/internal/config"
 	"github.com/angristan/hue-tui/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	// Check for demo mode
+	os.Exit(run())
+}
+
+// run runs hue and returns its exit status, so that its deferred calls run
+// before the process exits
+func run() int {
+	// Subcommands log as configured, the TUI flags below can override it
+	closeLog, err := startLogging("", "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	defer func() {
+		if err := closeLog(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
+
+	defer func() {
+		if err := closeAuditLog(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
+
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "apply":
+			if err := runApply(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "export":
+			if err := runExport(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "import":
+			if err := runImport(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "config":
+			if err := runConfig(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "list":
+			if err := runList(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "scene":
+			if err := runScene(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "watch":
+			if err := runWatch(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "serve":
+			if err := runServe(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		case "version", "--version":
+			if err := runVersion(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	// Check for demo mode and event log flags
 	demoMode := os.Getenv("HUE_DEMO") != ""
-	for _, arg := range os.Args[1:] {
-		if arg == "--demo" || arg == "-demo" {
+	var recordPath, replayPath, logLevel, logFile, room string
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--demo", "-demo":
 			demoMode = true
-			break
+		case "--record", "-record", "--replay", "-replay":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a file\n", arg)
+				return 1
+			}
+			i++
+			if strings.HasSuffix(arg, "record") {
+				recordPath = args[i]
+			} else {
+				replayPath = args[i]
+			}
+		case "--room", "-room":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a room name\n", arg)
+				return 1
+			}
+			i++
+			room = args[i]
+		case "--log-level", "-log-level", "--log-file", "-log-file":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a value\n", arg)
+				return 1
+			}
+			i++
+			if strings.HasSuffix(arg, "level") {
+				logLevel = args[i]
+			} else {
+				logFile = args[i]
+			}
+		}
+	}
+
+	if logLevel != "" || logFile != "" {
+		// The log closed on return is the one set up last
+		if _, err := startLogging(logLevel, logFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
 		}
 	}
 
+	// Replays run against the demo data
+	var replay []api.EventLogEntry
+	if replayPath != "" {
+		entries, err := readEventLog(replayPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		replay = entries
+		demoMode = true
+	}
+
 	if demoMode {
 		fmt.Fprintln(os.Stderr, "[hue] Demo mode enabled")
 	}
@@ -27,19 +163,62 @@ func main() {
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
 
 	// Create and run the application
 	model := tui.NewModel(cfg, demoMode)
+	model.SetVersion(version)
+	if room != "" {
+		model.FocusRoom(room)
+	}
+	if replay != nil {
+		model.ReplayEvents(replay)
+	}
+	var recordFile *os.File
+	if recordPath != "" {
+		recordFile, err = os.Create(recordPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating event log: %v\n", err)
+			return 1
+		}
+		model.RecordEvents(api.NewEventRecorder(recordFile))
+	}
 	p := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
+		tea.WithReportFocus(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if m, ok := final.(tui.Model); ok {
+		if closeErr := m.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if recordFile != nil {
+		if closeErr := recordFile.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close event log: %w", closeErr)
+		}
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running app: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
+	return 0
+}
+
+// readEventLog loads an event log recorded with --record
+func readEventLog(path string) (entries []api.EventLogEntry, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close event log: %w", closeErr)
+		}
+	}()
+	return api.ReadEventLog(f)
 }
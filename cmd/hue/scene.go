@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/plan"
+)
+
+// runScene implements `hue scene create "Dinner" --room Kitchen --capture`
+// and `hue scene recall "Dinner"`
+func runScene(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "create":
+			return runSceneCreate(args[1:])
+		case "recall":
+			return runSceneRecall(args[1:])
+		}
+	}
+	return fmt.Errorf("usage: hue scene create NAME (-room ROOM | -zone ZONE) -capture, or hue scene recall NAME [-room ROOM]")
+}
+
+// parseNames parses flags placed before or after the positional names,
+// which the flag package alone stops at
+func parseNames(fs *flag.FlagSet, args []string) ([]string, error) {
+	var names []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return names, nil
+		}
+		names = append(names, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// runSceneCreate creates a scene from the current state of a room or zone
+func runSceneCreate(args []string) error {
+	fs := flag.NewFlagSet("scene create", flag.ContinueOnError)
+	room := fs.String("room", "", "room to create the scene in")
+	zone := fs.String("zone", "", "zone to create the scene in")
+	capture := fs.Bool("capture", false, "use the current state of the lights")
+	dryRun := fs.Bool("dry-run", false, "print the commands to the bridge instead of sending them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue scene create NAME (-room ROOM | -zone ZONE) -capture [-dry-run]")
+		fs.PrintDefaults()
+	}
+
+	names, err := parseNames(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(names) != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one scene name")
+	}
+	if (*room == "") == (*zone == "") {
+		fs.Usage()
+		return fmt.Errorf("expected either -room or -zone")
+	}
+	if !*capture {
+		return fmt.Errorf("nothing to create the scene from: pass -capture to use the current state of the lights")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bridge, err := connectBridge(cfg)
+	if err != nil {
+		return err
+	}
+	commands := commandsTo(bridge, *dryRun)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	state, err := plan.FetchState(ctx, bridge)
+	if err != nil {
+		return err
+	}
+
+	groupType, groupName, groups := "room", *room, state.Rooms
+	if *zone != "" {
+		groupType, groupName, groups = "zone", *zone, state.Zones
+	}
+	var group *models.Room
+	for _, g := range groups {
+		if strings.EqualFold(g.Name, groupName) {
+			group = g
+		}
+	}
+	if group == nil {
+		return fmt.Errorf("%s %q not found", groupType, groupName)
+	}
+
+	// Rooms contain devices, zones contain lights
+	var lights []*models.Light
+	for _, light := range state.Lights {
+		if slices.Contains(group.DeviceIDs, light.DeviceID) || slices.Contains(group.LightIDs, light.ID) {
+			lights = append(lights, light)
+		}
+	}
+	if len(lights) == 0 {
+		return fmt.Errorf("%s %q has no lights", groupType, group.Name)
+	}
+
+	if _, err := commands.CreateScene(ctx, names[0], group.ID, groupType, api.CaptureSceneActions(lights)); err != nil {
+		return fmt.Errorf("failed to create scene: %w", err)
+	}
+	fmt.Printf("Created scene %q in %s with %d lights\n", names[0], group.Name, len(lights))
+	return nil
+}
+
+// runSceneRecall activates a scene by name, over the recall duration set
+// for it in the TUI when there is one
+func runSceneRecall(args []string) error {
+	fs := flag.NewFlagSet("scene recall", flag.ContinueOnError)
+	room := fs.String("room", "", "room or zone of the scene, when several have its name")
+	dryRun := fs.Bool("dry-run", false, "print the commands to the bridge instead of sending them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue scene recall NAME [-room ROOM] [-dry-run]")
+		fs.PrintDefaults()
+	}
+
+	names, err := parseNames(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(names) != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one scene name")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bridgeCfg, err := cfg.GetLastBridge()
+	if err != nil {
+		return fmt.Errorf("%w (run hue without arguments to pair a bridge)", err)
+	}
+	bridge, err := connectBridge(cfg)
+	if err != nil {
+		return err
+	}
+	commands := commandsTo(bridge, *dryRun)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	state, err := plan.FetchState(ctx, bridge)
+	if err != nil {
+		return err
+	}
+	groupNames := make(map[string]string)
+	for _, group := range append(state.Rooms, state.Zones...) {
+		groupNames[group.ID] = group.Name
+	}
+	var matches []*models.Scene
+	for _, scene := range state.Scenes {
+		scene.RoomName = groupNames[scene.RoomID]
+		if strings.EqualFold(scene.Name, names[0]) && (*room == "" || strings.EqualFold(scene.RoomName, *room)) {
+			matches = append(matches, scene)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("scene %q not found", names[0])
+	case len(matches) > 1:
+		var rooms []string
+		for _, scene := range matches {
+			rooms = append(rooms, strconv.Quote(scene.RoomName))
+		}
+		return fmt.Errorf("several scenes are named %q, pick one with -room: %s", names[0], strings.Join(rooms, ", "))
+	}
+	scene := matches[0]
+
+	if scene.IsSmart {
+		if err := commands.SetSmartSceneActive(ctx, scene.ID, true); err != nil {
+			return err
+		}
+		fmt.Printf("Started smart scene %q in %s\n", scene.Name, scene.RoomName)
+		return nil
+	}
+	if ms, ok := bridgeCfg.SceneTransitions[scene.ID]; ok {
+		duration := time.Duration(ms) * time.Millisecond
+		if err := commands.ActivateSceneOver(ctx, scene.ID, duration); err != nil {
+			return err
+		}
+		fmt.Printf("Recalled %q in %s over %s\n", scene.Name, scene.RoomName, duration)
+		return nil
+	}
+	if err := commands.ActivateScene(ctx, scene.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Recalled %q in %s\n", scene.Name, scene.RoomName)
+	return nil
+}
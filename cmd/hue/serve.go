@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/server"
+)
+
+// runServe implements `hue serve`
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on")
+	demo := fs.Bool("demo", false, "serve the demo bridge")
+	readOnly := fs.Bool("read-only", false, "only serve the dashboard and state, reject changes")
+	dryRun := fs.Bool("dry-run", false, "print the commands to the bridge instead of sending them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue serve [-addr host:port] [-read-only] [-dry-run] [-demo]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var srv *server.Server
+	if *demo || os.Getenv("HUE_DEMO") != "" {
+		srv = server.New(api.NewDemoBridge())
+	} else {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		bridge, err := connectBridge(cfg)
+		if err != nil {
+			return err
+		}
+		srv = server.New(commandsTo(bridge, *dryRun))
+
+		events := api.NewEventSubscription(bridge, srv.Publish)
+		events.SetIdleTimeout(time.Duration(cfg.EventIdleSeconds) * time.Second)
+		if err := events.Start(ctx); err != nil {
+			return fmt.Errorf("failed to subscribe to bridge events: %w", err)
+		}
+		defer func() { _ = events.Stop() }()
+	}
+
+	srv.SetReadOnly(*readOnly)
+	if err := srv.SetAddr(*addr); err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "[hue] Serving on http://%s\n", *addr)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// SSE clients never finish on their own, so close them after the timeout
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
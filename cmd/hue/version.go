@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/angristan/hue-tui/internal/update"
+)
+
+// Set at build time by goreleaser
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// runVersion implements `hue version [-check]`
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	check := fs.Bool("check", false, "check GitHub for a newer release")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue version [-check]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Printf("hue %s (commit %s, built %s)\n", version, commit, date)
+	if !*check {
+		return nil
+	}
+
+	release, err := update.New().Latest(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !update.IsRelease(version):
+		fmt.Printf("Latest release is %s (this is a development build)\n", release.Version)
+	case update.Newer(version, release.Version):
+		fmt.Println(release.Hint())
+	default:
+		fmt.Println("Up to date")
+	}
+	return nil
+}
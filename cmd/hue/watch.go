@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/watch"
+)
+
+// runWatch implements `hue watch --format json`
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	format := fs.String("format", watch.FormatJSON, "output format, json or text")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hue watch [-format json|text]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bridge, err := connectBridge(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	rooms, _, err := bridge.FetchAll(fetchCtx)
+	cancel()
+	// Scenes aren't needed to watch lights
+	if err != nil && !errors.Is(err, api.ErrScenesUnavailable) {
+		return err
+	}
+
+	timeFormat, err := locale.New(cfg.Locale)
+	if err != nil {
+		return err
+	}
+	watcher, err := watch.New(os.Stdout, *format, rooms)
+	if err != nil {
+		return err
+	}
+	watcher.SetLocale(timeFormat)
+	events := api.NewEventSubscription(bridge, watcher.Handle)
+	events.SetIdleTimeout(time.Duration(cfg.EventIdleSeconds) * time.Second)
+	if err := events.Start(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to bridge events: %w", err)
+	}
+	defer func() { _ = events.Stop() }()
+
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,180 @@
+// Package ansi converts styled terminal output to plain text or HTML
+package ansi
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// escapeRe matches CSI sequences (colors, cursor movement) and OSC
+// sequences (hyperlinks, titles)
+var escapeRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Strip removes escape sequences and trailing spaces from each line
+func Strip(s string) string {
+	lines := strings.Split(escapeRe.ReplaceAllString(s, ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// style is the SGR state applied to a run of text
+type style struct {
+	fg, bg                         string
+	bold, faint, italic, underline bool
+}
+
+func (s style) css() string {
+	var parts []string
+	if s.fg != "" {
+		parts = append(parts, "color:"+s.fg)
+	}
+	if s.bg != "" {
+		parts = append(parts, "background:"+s.bg)
+	}
+	if s.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if s.faint {
+		parts = append(parts, "opacity:0.6")
+	}
+	if s.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	if s.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	return strings.Join(parts, ";")
+}
+
+// apply updates the style with the parameters of an SGR sequence
+func (s *style) apply(params string) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			code = 0 // "\x1b[m" is a reset
+		}
+		switch {
+		case code == 0:
+			*s = style{}
+		case code == 1:
+			s.bold = true
+		case code == 2:
+			s.faint = true
+		case code == 3:
+			s.italic = true
+		case code == 4:
+			s.underline = true
+		case code == 22:
+			s.bold, s.faint = false, false
+		case code == 23:
+			s.italic = false
+		case code == 24:
+			s.underline = false
+		case code >= 30 && code <= 37:
+			s.fg = basicColors[code-30]
+		case code >= 90 && code <= 97:
+			s.fg = basicColors[code-90+8]
+		case code == 39:
+			s.fg = ""
+		case code >= 40 && code <= 47:
+			s.bg = basicColors[code-40]
+		case code >= 100 && code <= 107:
+			s.bg = basicColors[code-100+8]
+		case code == 49:
+			s.bg = ""
+		case code == 38 || code == 48:
+			color, used := extendedColor(codes[i+1:])
+			i += used
+			if code == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+		}
+	}
+}
+
+// basicColors are the 16 standard terminal colors (xterm defaults)
+var basicColors = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// extendedColor parses the arguments of a 38/48 sequence, either "5;n" for
+// the 256 color palette or "2;r;g;b", and returns how many it consumed
+func extendedColor(args []string) (string, int) {
+	num := func(i int) int {
+		if i >= len(args) {
+			return 0
+		}
+		n, _ := strconv.Atoi(args[i])
+		return n
+	}
+	if len(args) == 0 {
+		return "", 0
+	}
+	switch args[0] {
+	case "5":
+		return paletteColor(num(1)), 2
+	case "2":
+		return fmt.Sprintf("#%02x%02x%02x", num(1), num(2), num(3)), 4
+	}
+	return "", 1
+}
+
+// paletteColor converts an xterm 256 color index to hex
+func paletteColor(n int) string {
+	switch {
+	case n < 16:
+		return basicColors[n]
+	case n < 232:
+		n -= 16
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	case n < 256:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+	return ""
+}
+
+// ToHTML renders styled terminal output as a standalone HTML page, keeping
+// colors and text attributes
+func ToHTML(s, title string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString("</head>\n<body style=\"background:#1a1b26;color:#c0caf5\">\n<pre style=\"font-family:monospace;line-height:1.2\">")
+
+	var cur style
+	write := func(text string) {
+		if text == "" {
+			return
+		}
+		if css := cur.css(); css != "" {
+			fmt.Fprintf(&b, "<span style=\"%s\">%s</span>", css, html.EscapeString(text))
+		} else {
+			b.WriteString(html.EscapeString(text))
+		}
+	}
+
+	last := 0
+	for _, loc := range escapeRe.FindAllStringIndex(s, -1) {
+		write(s[last:loc[0]])
+		last = loc[1]
+		seq := s[loc[0]:loc[1]]
+		if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+			cur.apply(seq[2 : len(seq)-1])
+		}
+	}
+	write(s[last:])
+
+	b.WriteString("</pre>\n</body>\n</html>\n")
+	return b.String()
+}
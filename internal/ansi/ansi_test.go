@@ -0,0 +1,50 @@
+package ansi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	in := "\x1b[1;38;2;255;0;0mHUE\x1b[0m   \n\x1b]8;;http://x\x07link\x1b]8;;\x07 ● on\x1b[m"
+	want := "HUE\nlink ● on"
+	if got := Strip(in); got != want {
+		t.Errorf("Strip() = %q, want %q", got, want)
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	in := "\x1b[1;38;2;255;0;0mHot\x1b[0m <b>\x1b[38;5;21mblue\x1b[39m \x1b[92mgreen\x1b[0m"
+	out := ToHTML(in, "Snapshot & co")
+
+	for _, want := range []string{
+		"<title>Snapshot &amp; co</title>",
+		`<span style="color:#ff0000;font-weight:bold">Hot</span>`,
+		" &lt;b&gt;",
+		`<span style="color:#0000ff">blue</span>`,
+		`<span style="color:#00ff00">green</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ToHTML() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b") {
+		t.Error("ToHTML() left escape sequences in the output")
+	}
+}
+
+func TestPaletteColor(t *testing.T) {
+	tests := map[int]string{
+		1:   "#cd0000",
+		16:  "#000000",
+		196: "#ff0000",
+		231: "#ffffff",
+		232: "#080808",
+		255: "#eeeeee",
+	}
+	for n, want := range tests {
+		if got := paletteColor(n); got != want {
+			t.Errorf("paletteColor(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry is one mutating request sent to the bridge
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Bridge string    `json:"bridge"`
+	Method string    `json:"method"`
+	// Resource path, such as "light/<id>"
+	Resource string          `json:"resource"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	// HTTP status, 0 when the bridge couldn't be reached
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AuditLog appends every mutating request to a log, one JSON object per
+// line, to trace what was done to the lights
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog creates an audit log writing to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record appends an entry to the log
+func (a *AuditLog) Record(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// SetAuditLog records the mutating requests of this bridge to log (nil
+// stops recording)
+func (p *requestPipeline) SetAuditLog(log *AuditLog) {
+	p.audit = log
+}
+
+// auditRequest records a mutating request once its response is known. The
+// response body is read to pick up API errors and replaced for the caller.
+func (p *requestPipeline) auditRequest(method, path string, payload []byte, resp *http.Response, reqErr error) {
+	entry := AuditEntry{
+		Time:     time.Now(),
+		Bridge:   p.bridgeID,
+		Method:   method,
+		Resource: strings.TrimPrefix(strings.TrimPrefix(path, "/clip/v2/resource"), "/"),
+	}
+	if json.Valid(payload) {
+		entry.Payload = payload
+	}
+
+	switch {
+	case reqErr != nil:
+		entry.Error = reqErr.Error()
+	case resp != nil:
+		entry.Status = resp.StatusCode
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close() // Error ignored: the body was fully read
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		var apiResp apiResponse
+		var v1Results []v1Error
+		switch {
+		case err != nil:
+			entry.Error = err.Error()
+		case json.Unmarshal(data, &apiResp) == nil && len(apiResp.Errors) > 0:
+			entry.Error = apiResp.Errors[0].Description
+		case json.Unmarshal(data, &v1Results) == nil && len(v1Results) > 0 && v1Results[0].Error != nil:
+			entry.Error = v1Results[0].Error.Description
+		case resp.StatusCode >= 400:
+			entry.Error = resp.Status
+		}
+	}
+
+	if err := p.audit.Record(entry); err != nil {
+		apiLog.Warnf("%v", err)
+	}
+}
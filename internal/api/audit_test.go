@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogRecordsCommands(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"data": [], "errors": []}`))
+		case strings.HasSuffix(r.URL.Path, "/light-2"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"data": [], "errors": [{"description": "device unreachable"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": [{"rid": "light-1"}], "errors": []}`))
+		}
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+	b.SetAuditLog(NewAuditLog(&buf))
+
+	ctx := context.Background()
+	if _, err := b.GetLights(ctx); err != nil {
+		t.Fatalf("GetLights failed: %v", err)
+	}
+	if err := b.SetLightOn(ctx, "light-1", true); err != nil {
+		t.Fatalf("SetLightOn failed: %v", err)
+	}
+	if err := b.SetLightBrightness(ctx, "light-2", 40); err == nil {
+		t.Fatal("Expected the API error to be returned")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected only the 2 commands to be logged, got %q", buf.String())
+	}
+	var entries []AuditEntry
+	for _, line := range lines {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid audit line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	on := entries[0]
+	if on.Method != "PUT" || on.Resource != "light/light-1" || on.Bridge != "bridge-1" || on.Status != 200 || on.Error != "" {
+		t.Errorf("Unexpected entry for the successful command: %+v", on)
+	}
+	if !strings.Contains(string(on.Payload), `"on":true`) || on.Time.IsZero() {
+		t.Errorf("Expected the payload and time to be logged, got %+v", on)
+	}
+	if entries[1].Status != http.StatusServiceUnavailable || entries[1].Error != "device unreachable" {
+		t.Errorf("Expected the API error to be logged, got %+v", entries[1])
+	}
+}
@@ -2,15 +2,44 @@ package api
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/angristan/hue-tui/internal/models"
 )
 
+// Resource types loaded by FetchAll
+const (
+	FetchRooms   = "rooms"
+	FetchLights  = "lights"
+	FetchDevices = "devices"
+	FetchScenes  = "scenes"
+)
+
+// FetchResources lists the resource types loaded by FetchAll, in display order
+var FetchResources = []string{FetchRooms, FetchLights, FetchDevices, FetchScenes}
+
+// ErrScenesUnavailable is returned by FetchAll along with the rooms when
+// only the scenes could not be loaded
+var ErrScenesUnavailable = errors.New("scenes unavailable")
+
+// FetchProgress is called as each resource type finishes loading, with the
+// number of items loaded. Calls are serialized.
+type FetchProgress func(resource string, count int)
+
 // BridgeClient defines the interface for interacting with a Hue bridge.
 // This abstraction allows for both real bridge connections and demo mode.
 type BridgeClient interface {
 	// FetchAll retrieves all rooms and scenes from the bridge
 	FetchAll(ctx context.Context) ([]*models.Room, []*models.Scene, error)
+	// FetchAllProgress is FetchAll, reporting each resource type as it loads
+	FetchAllProgress(ctx context.Context, progress FetchProgress) ([]*models.Room, []*models.Scene, error)
+
+	// GetLight retrieves the current state of a single light
+	GetLight(ctx context.Context, lightID string) (*models.Light, error)
+	// GetDeviceName returns the name of a device, such as the one owning a
+	// light added since the last fetch
+	GetDeviceName(ctx context.Context, deviceID string) (string, error)
 
 	// Light control methods
 	SetLightOn(ctx context.Context, lightID string, on bool) error
@@ -18,12 +47,67 @@ type BridgeClient interface {
 	SetLightColorTemp(ctx context.Context, lightID string, mirek int) error
 	SetLightColorXY(ctx context.Context, lightID string, x, y float64) error
 	SetLightColorHS(ctx context.Context, lightID string, hue uint16, sat uint8) error
+	// SetLightGradient sets the color points of a gradient light
+	SetLightGradient(ctx context.Context, lightID string, points [][2]float64) error
+	// IdentifyLight blinks a light so it can be found physically
+	IdentifyLight(ctx context.Context, lightID string) error
+	// SetLightArchetype sets the type of a light, one of
+	// models.LightArchetypes
+	SetLightArchetype(ctx context.Context, lightID, archetype string) error
+	// RenameLight changes the name of a light
+	RenameLight(ctx context.Context, lightID, name string) error
 
 	// Group control
+	// HomeGroupedLightID returns the grouped light of every light on the
+	// bridge
+	HomeGroupedLightID(ctx context.Context) (string, error)
 	SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) error
+	// FadeGroupedLightOn turns a group of lights on or off over duration
+	FadeGroupedLightOn(ctx context.Context, groupedLightID string, on bool, duration time.Duration) error
+	// SetGroupedLightBrightness sets every light of a group to brightness
+	// percent (0-100) in one command
+	SetGroupedLightBrightness(ctx context.Context, groupedLightID string, brightness int) error
 
 	// Scene control
+	GetScenes(ctx context.Context) ([]*models.Scene, error)
 	ActivateScene(ctx context.Context, sceneID string) error
+	// ActivateSceneOver activates a scene, fading to it over duration
+	ActivateSceneOver(ctx context.Context, sceneID string, duration time.Duration) error
+	// StopSceneDynamics freezes a dynamic scene on its current colors
+	StopSceneDynamics(ctx context.Context, sceneID string) error
+	// SetSmartSceneActive starts or stops a smart scene
+	SetSmartSceneActive(ctx context.Context, sceneID string, active bool) error
+	// CreateScene creates a scene for a room or zone and returns its ID
+	CreateScene(ctx context.Context, name, groupID, groupType string, actions []SceneAction) (string, error)
+	DeleteScene(ctx context.Context, sceneID string) error
+
+	// Rooms and zones. Rooms group devices, zones group lights. Rooms are
+	// returned without their lights, with the device IDs they contain.
+	GetRooms(ctx context.Context) ([]*models.Room, error)
+	GetZones(ctx context.Context) ([]*models.Room, error)
+	CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error)
+	UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error
+	DeleteRoom(ctx context.Context, roomID string) error
+	CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error)
+	UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error
+	DeleteZone(ctx context.Context, zoneID string) error
+
+	// GetBatteryDevices returns the battery powered devices, like switches
+	// and motion sensors
+	GetBatteryDevices(ctx context.Context) ([]*models.Device, error)
+
+	// Entertainment areas
+	GetEntertainmentAreas(ctx context.Context) ([]*models.EntertainmentArea, error)
+	SetEntertainmentActive(ctx context.Context, areaID string, active bool) error
+
+	// Schedules (behavior instances and smart scenes)
+	GetSchedules(ctx context.Context) ([]*models.Schedule, error)
+	CreateSchedule(ctx context.Context, spec ScheduleSpec) (string, error)
+	DeleteSchedule(ctx context.Context, resource, id string) error
+
+	// GetBridgeInfo returns the firmware, model and zigbee channel of the
+	// bridge, and how long the bridge took to answer
+	GetBridgeInfo(ctx context.Context) (*models.BridgeInfo, error)
 
 	// Metadata
 	Host() string
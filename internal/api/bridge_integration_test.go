@@ -0,0 +1,222 @@
+//go:build bridge
+
+package api
+
+// Integration tests against a real bridge, run with
+//
+//	HUE_BRIDGE_HOST=192.168.1.2 HUE_BRIDGE_KEY=<app key> go test -tags=bridge ./internal/api
+//
+// They only read from the bridge unless HUE_BRIDGE_MUTATE=1 is set, which
+// also changes the lights of the room named "Test" (or HUE_BRIDGE_TEST_ROOM)
+// and restores them afterwards.
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// maxBridgeLatency is the slowest round trip accepted from a bridge on the
+// local network
+const maxBridgeLatency = time.Second
+
+// testBridge connects to the bridge given in the environment, skipping the
+// test when there is none
+func testBridge(t *testing.T) *HueBridge {
+	t.Helper()
+	host, key := os.Getenv("HUE_BRIDGE_HOST"), os.Getenv("HUE_BRIDGE_KEY")
+	if host == "" || key == "" {
+		t.Skip("HUE_BRIDGE_HOST and HUE_BRIDGE_KEY are not set")
+	}
+	return NewHueBridge(host, key, os.Getenv("HUE_BRIDGE_ID"))
+}
+
+func TestBridgeFetch(t *testing.T) {
+	b := testBridge(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rooms, scenes, err := b.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	lights := 0
+	for _, room := range rooms {
+		if room.ID == "" || room.Name == "" {
+			t.Errorf("Room without ID or name: %+v", room)
+		}
+		for _, light := range room.Lights {
+			if light.ID == "" || light.Name == "" {
+				t.Errorf("Light without ID or name in %q: %+v", room.Name, light)
+			}
+			lights++
+		}
+	}
+	if lights == 0 {
+		t.Fatal("The bridge has no lights")
+	}
+	for _, scene := range scenes {
+		if scene.ID == "" || scene.Name == "" {
+			t.Errorf("Scene without ID or name: %+v", scene)
+		}
+	}
+	t.Logf("Fetched %d lights, %d rooms and %d scenes", lights, len(rooms), len(scenes))
+}
+
+func TestBridgeLatency(t *testing.T) {
+	b := testBridge(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var latencies []time.Duration
+	for range 5 {
+		info, err := b.GetBridgeInfo(ctx)
+		if err != nil {
+			t.Fatalf("GetBridgeInfo: %v", err)
+		}
+		latencies = append(latencies, info.Latency)
+	}
+	slices.Sort(latencies)
+	median := latencies[len(latencies)/2]
+	t.Logf("Latency: median %s, max %s", median, latencies[len(latencies)-1])
+	if median <= 0 || median > maxBridgeLatency {
+		t.Errorf("Median latency %s, want up to %s", median, maxBridgeLatency)
+	}
+}
+
+func TestBridgeEventStream(t *testing.T) {
+	b := testBridge(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	connected := make(chan bool, 1)
+	events := NewEventSubscription(b, func([]Event) {})
+	events.SetStreamHandler(func(ok bool) {
+		select {
+		case connected <- ok:
+		default:
+		}
+	})
+	if err := events.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = events.Stop() }()
+
+	select {
+	case ok := <-connected:
+		if !ok {
+			t.Fatal("The event stream failed to connect")
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("The event stream didn't connect within 15s")
+	}
+	if stats := events.Stats(); stats.Connects != 1 {
+		t.Errorf("Connects = %d, want 1", stats.Connects)
+	}
+}
+
+// TestBridgeLightCommands changes a light of the test room and waits for
+// the bridge to echo it on the event stream
+func TestBridgeLightCommands(t *testing.T) {
+	if os.Getenv("HUE_BRIDGE_MUTATE") != "1" {
+		t.Skip("HUE_BRIDGE_MUTATE=1 is not set")
+	}
+	b := testBridge(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	roomName := os.Getenv("HUE_BRIDGE_TEST_ROOM")
+	if roomName == "" {
+		roomName = "Test"
+	}
+	rooms, _, err := b.FetchAll(ctx)
+	if err != nil && !errors.Is(err, ErrScenesUnavailable) {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	var light *models.Light
+	for _, room := range rooms {
+		if !strings.EqualFold(room.Name, roomName) {
+			continue
+		}
+		for _, l := range room.Lights {
+			if l.Reachable && light == nil {
+				light = l
+			}
+		}
+	}
+	if light == nil {
+		t.Skipf("No reachable light in a room named %q", roomName)
+	}
+
+	// Put the light back as it was
+	on, brightness := light.On, light.BrightnessPct()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := b.SetLightBrightness(ctx, light.ID, brightness); err != nil {
+			t.Errorf("Restoring brightness: %v", err)
+		}
+		if err := b.SetLightOn(ctx, light.ID, on); err != nil {
+			t.Errorf("Restoring on: %v", err)
+		}
+	})
+
+	echoed := make(chan float64, 16)
+	events := NewEventSubscription(b, func(batch []Event) {
+		for _, event := range batch {
+			update, err := ParseLightUpdate(event)
+			if err != nil || update.ID != light.ID || update.Brightness == nil {
+				continue
+			}
+			select {
+			case echoed <- *update.Brightness:
+			default:
+			}
+		}
+	})
+	connected := make(chan bool, 1)
+	events.SetStreamHandler(func(ok bool) {
+		select {
+		case connected <- ok:
+		default:
+		}
+	})
+	if err := events.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = events.Stop() }()
+	if ok := <-connected; !ok {
+		t.Fatal("The event stream failed to connect")
+	}
+
+	target := 30
+	if brightness == target {
+		target = 60
+	}
+	if err := b.SetLightOn(ctx, light.ID, true); err != nil {
+		t.Fatalf("SetLightOn: %v", err)
+	}
+	if err := b.SetLightBrightness(ctx, light.ID, target); err != nil {
+		t.Fatalf("SetLightBrightness: %v", err)
+	}
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case got := <-echoed:
+			if math.Abs(got-float64(target)) <= 1 {
+				t.Logf("Light %q echoed %.1f%%", light.Name, got)
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Light %q didn't echo %d%% within 10s", light.Name, target)
+		}
+	}
+}
@@ -8,41 +8,72 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/angristan/hue-tui/internal/logging"
 	"github.com/angristan/hue-tui/internal/models"
 )
 
+// apiLog is the log of the requests to the bridge
+var apiLog = logging.For("api")
+
 // HueBridge represents a connection to a Philips Hue bridge
 type HueBridge struct {
-	host     string
-	appKey   string
-	bridgeID string
-	client   *http.Client
+	requestPipeline
+
+	host   string
+	appKey string
+	client *http.Client
+
+	// TLS validation for bridge connections (REST and event stream)
+	tlsConfig *tls.Config
+	certSeen  *certObserver
 
 	// Device name cache for resolving light owners
 	deviceNames map[string]string
 	deviceMu    sync.RWMutex
+
+	// Set when the bridge is reached through the Hue remote API
+	remote *RemoteAuth
 }
 
 // NewHueBridge creates a new bridge client
 func NewHueBridge(host, appKey, bridgeID string) *HueBridge {
-	return &HueBridge{
-		host:        host,
-		appKey:      appKey,
-		bridgeID:    bridgeID,
-		deviceNames: make(map[string]string),
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
+	b := &HueBridge{
+		requestPipeline: newRequestPipeline(bridgeID),
+		host:            host,
+		appKey:          appKey,
+		deviceNames:     make(map[string]string),
+		certSeen:        &certObserver{},
+	}
+	b.SetTLSPolicy(TLSPolicy{Mode: TLSModeInsecure})
+	return b
+}
+
+// SetTLSPolicy changes how the bridge certificate is validated
+func (b *HueBridge) SetTLSPolicy(policy TLSPolicy) {
+	// The remote API isn't the bridge and has no bridge certificate
+	if b.remote != nil {
+		return
+	}
+	b.tlsConfig = newTLSConfig(policy, b.bridgeID, b.certSeen)
+	b.client = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: b.tlsConfig,
 		},
 	}
 }
 
+// CertFingerprint returns the SHA-256 fingerprint of the certificate seen
+// during the last handshake, or "" if no connection was made yet
+func (b *HueBridge) CertFingerprint() string {
+	return b.certSeen.get()
+}
+
 // Host returns the bridge host
 func (b *HueBridge) Host() string {
 	return b.host
@@ -55,19 +86,19 @@ func (b *HueBridge) BridgeID() string {
 
 // doRequest performs an authenticated API request
 func (b *HueBridge) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
-	url := fmt.Sprintf("https://%s%s", b.host, path)
-
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("hue-application-key", b.appKey)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	return b.client.Do(req)
+	return b.send(ctx, b.client, method, path, body, func(body io.Reader) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, b.url(path), body)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.authorize(ctx, req); err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
 }
 
 // apiResponse wraps the V2 API response format
@@ -112,6 +143,38 @@ func (b *HueBridge) GetLights(ctx context.Context) (lights []*models.Light, err
 	return result, nil
 }
 
+// GetLight retrieves the current state of a single light
+func (b *HueBridge) GetLight(ctx context.Context, lightID string) (light *models.Light, err error) {
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/light/"+lightID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get light: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode light response: %w", err)
+	}
+
+	if len(apiResp.Errors) > 0 {
+		return nil, fmt.Errorf("API error: %s", apiResp.Errors[0].Description)
+	}
+
+	var rawLights []lightResource
+	if err := json.Unmarshal(apiResp.Data, &rawLights); err != nil {
+		return nil, fmt.Errorf("failed to parse light: %w", err)
+	}
+	if len(rawLights) == 0 {
+		return nil, fmt.Errorf("light %s not found", lightID)
+	}
+
+	return rawLights[0].toModel(), nil
+}
+
 // lightResource represents the V2 API light resource
 type lightResource struct {
 	ID       string `json:"id"`
@@ -143,13 +206,70 @@ type lightResource struct {
 			Green struct{ X, Y float64 } `json:"green"`
 			Blue  struct{ X, Y float64 } `json:"blue"`
 		} `json:"gamut"`
+		GamutType string `json:"gamut_type"`
 	} `json:"color"`
-	Owner struct {
+	Gradient *gradientJSON `json:"gradient"`
+	Owner    struct {
 		Rid   string `json:"rid"`
 		Rtype string `json:"rtype"`
 	} `json:"owner"`
 }
 
+// gradientJSON is the gradient of a light, in resources and commands
+type gradientJSON struct {
+	Points []gradientPointJSON `json:"points"`
+	// Only sent by the bridge
+	PointsCapable int `json:"points_capable,omitempty"`
+}
+
+type gradientPointJSON struct {
+	Color struct {
+		XY struct {
+			X float64 `json:"x"`
+			Y float64 `json:"y"`
+		} `json:"xy"`
+	} `json:"color"`
+}
+
+// zigbeeConnectivityResource represents the V2 API zigbee_connectivity
+// resource, which reports whether a device answers on the Zigbee network
+type zigbeeConnectivityResource struct {
+	ID         string      `json:"id"`
+	Owner      resourceRef `json:"owner"`
+	Status     string      `json:"status"`
+	MacAddress string      `json:"mac_address"`
+}
+
+// GetDeviceAddresses returns the Zigbee MAC address of each device, by
+// device ID. Unlike resource IDs, addresses stay the same across bridges.
+func (b *HueBridge) GetDeviceAddresses(ctx context.Context) (map[string]string, error) {
+	var connectivity []zigbeeConnectivityResource
+	if err := b.listResources(ctx, "zigbee_connectivity", &connectivity); err != nil {
+		return nil, err
+	}
+	addresses := make(map[string]string)
+	for _, c := range connectivity {
+		if c.MacAddress != "" {
+			addresses[c.Owner.Rid] = c.MacAddress
+		}
+	}
+	return addresses, nil
+}
+
+// applyConnectivity sets Reachable on lights from the connectivity of their
+// device. Lights of devices without a connectivity resource stay reachable.
+func applyConnectivity(lights []*models.Light, connectivity []zigbeeConnectivityResource) {
+	status := make(map[string]string)
+	for _, c := range connectivity {
+		status[c.Owner.Rid] = c.Status
+	}
+	for _, light := range lights {
+		if s, ok := status[light.DeviceID]; ok {
+			light.Reachable = s == "connected"
+		}
+	}
+}
+
 func (r *lightResource) toModel() *models.Light {
 	light := &models.Light{
 		ID:                r.ID,
@@ -159,11 +279,13 @@ func (r *lightResource) toModel() *models.Light {
 		DeviceID:          r.Owner.Rid,
 		SupportsColor:     r.Color != nil,
 		SupportsColorTemp: r.ColorTemperature != nil,
+		OnOffOnly:         r.Dimming == nil,
+		Archetype:         r.Metadata.Archetype,
 	}
 
 	// Brightness
 	if r.Dimming != nil {
-		light.Brightness = uint8(r.Dimming.Brightness / 100.0 * 254)
+		light.Brightness = models.PctToLevel(r.Dimming.Brightness)
 	}
 
 	// Color
@@ -173,6 +295,14 @@ func (r *lightResource) toModel() *models.Light {
 			brightness = 254
 		}
 		light.Color = models.NewColorFromXY(r.Color.XY.X, r.Color.XY.Y, brightness)
+		if g := r.Color.Gamut; g != nil {
+			light.Gamut = &models.Gamut{
+				Type:  r.Color.GamutType,
+				Red:   models.GamutPoint{X: g.Red.X, Y: g.Red.Y},
+				Green: models.GamutPoint{X: g.Green.X, Y: g.Green.Y},
+				Blue:  models.GamutPoint{X: g.Blue.X, Y: g.Blue.Y},
+			}
+		}
 	} else if r.ColorTemperature != nil && r.ColorTemperature.Mirek != nil {
 		brightness := light.Brightness
 		if brightness == 0 {
@@ -180,6 +310,17 @@ func (r *lightResource) toModel() *models.Light {
 		}
 		light.Color = models.NewColorFromMirek(uint16(*r.ColorTemperature.Mirek), brightness)
 	}
+	if ct := r.ColorTemperature; ct != nil {
+		light.MirekMin = ct.MirekSchema.MirekMinimum
+		light.MirekMax = ct.MirekSchema.MirekMaximum
+	}
+
+	if g := r.Gradient; g != nil && g.PointsCapable > 0 {
+		light.Gradient = &models.Gradient{PointsCapable: g.PointsCapable}
+		for _, p := range g.Points {
+			light.Gradient.Points = append(light.Gradient.Points, models.NewColorFromXY(p.Color.XY.X, p.Color.XY.Y, 254))
+		}
+	}
 
 	return light
 }
@@ -237,8 +378,9 @@ type roomResource struct {
 
 func (r *roomResource) toModel() *models.Room {
 	room := &models.Room{
-		ID:   r.ID,
-		Name: r.Metadata.Name,
+		ID:        r.ID,
+		Name:      r.Metadata.Name,
+		Archetype: r.Metadata.Archetype,
 	}
 
 	// Find grouped_light service for room-level control
@@ -290,6 +432,16 @@ func (b *HueBridge) GetScenes(ctx context.Context) (scenes []*models.Scene, err
 		result[i] = raw.toModel()
 	}
 
+	// Smart scenes are listed along, bridges without them only lose those
+	var smartScenes []smartSceneResource
+	if err := b.listResources(ctx, "smart_scene", &smartScenes); err != nil {
+		apiLog.Warnf("Smart scenes unavailable: %v", err)
+		return result, nil
+	}
+	for _, raw := range smartScenes {
+		result = append(result, raw.toScene())
+	}
+
 	return result, nil
 }
 
@@ -305,15 +457,89 @@ type sceneResource struct {
 	} `json:"group"`
 	Speed     float64 `json:"speed"`
 	AutoDynac bool    `json:"auto_dynamic"`
+	Status    struct {
+		Active     string `json:"active"`
+		LastRecall string `json:"last_recall"`
+	} `json:"status"`
+	Palette struct {
+		Color []struct {
+			Color struct {
+				XY struct {
+					X float64 `json:"x"`
+					Y float64 `json:"y"`
+				} `json:"xy"`
+			} `json:"color"`
+		} `json:"color"`
+		ColorTemperature []struct {
+			ColorTemperature struct {
+				Mirek int `json:"mirek"`
+			} `json:"color_temperature"`
+		} `json:"color_temperature"`
+	} `json:"palette"`
+	Actions []struct {
+		Target resourceRef `json:"target"`
+		Action struct {
+			On *struct {
+				On bool `json:"on"`
+			} `json:"on"`
+			Dimming *struct {
+				Brightness float64 `json:"brightness"`
+			} `json:"dimming"`
+			Color *struct {
+				XY struct {
+					X float64 `json:"x"`
+					Y float64 `json:"y"`
+				} `json:"xy"`
+			} `json:"color"`
+			ColorTemperature *struct {
+				Mirek int `json:"mirek"`
+			} `json:"color_temperature"`
+		} `json:"action"`
+	} `json:"actions"`
 }
 
 func (r *sceneResource) toModel() *models.Scene {
-	return &models.Scene{
+	scene := &models.Scene{
 		ID:        r.ID,
 		Name:      r.Metadata.Name,
 		RoomID:    r.Group.Rid,
 		IsDynamic: r.AutoDynac,
+		Status:    r.Status.Active,
+	}
+	if t, err := time.Parse(time.RFC3339, r.Status.LastRecall); err == nil {
+		scene.LastRecalled = t
+	}
+
+	for _, c := range r.Palette.Color {
+		scene.Palette = append(scene.Palette, models.NewColorFromXY(c.Color.XY.X, c.Color.XY.Y, 254))
+	}
+	for _, c := range r.Palette.ColorTemperature {
+		if c.ColorTemperature.Mirek > 0 {
+			scene.Palette = append(scene.Palette, models.NewColorFromMirek(uint16(c.ColorTemperature.Mirek), 254))
+		}
+	}
+
+	for _, a := range r.Actions {
+		if a.Target.Rtype != "light" {
+			continue
+		}
+		action := models.SceneAction{
+			LightID:    a.Target.Rid,
+			On:         a.Action.On != nil && a.Action.On.On,
+			Brightness: 254,
+		}
+		if a.Action.Dimming != nil {
+			action.Brightness = models.PctToLevel(a.Action.Dimming.Brightness)
+		}
+		if c := a.Action.Color; c != nil {
+			action.Color = models.NewColorFromXY(c.XY.X, c.XY.Y, action.Brightness)
+		} else if ct := a.Action.ColorTemperature; ct != nil && ct.Mirek > 0 {
+			action.Color = models.NewColorFromMirek(uint16(ct.Mirek), action.Brightness)
+		}
+		scene.Actions = append(scene.Actions, action)
 	}
+
+	return scene
 }
 
 // GetDevices retrieves all devices and caches their names
@@ -352,6 +578,53 @@ func (b *HueBridge) GetDevices(ctx context.Context) (err error) {
 	return nil
 }
 
+// GetDeviceName returns the name of a device, from the names cached by the
+// last fetch when known
+func (b *HueBridge) GetDeviceName(ctx context.Context, deviceID string) (name string, err error) {
+	b.deviceMu.RLock()
+	name, ok := b.deviceNames[deviceID]
+	b.deviceMu.RUnlock()
+	if ok {
+		return name, nil
+	}
+
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/device/"+deviceID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get device: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return "", fmt.Errorf("failed to decode device response: %w", err)
+	}
+	if len(apiResp.Errors) > 0 {
+		return "", fmt.Errorf("API error: %s", apiResp.Errors[0].Description)
+	}
+
+	var devices []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(apiResp.Data, &devices); err != nil {
+		return "", fmt.Errorf("failed to parse device: %w", err)
+	}
+	if len(devices) == 0 {
+		return "", fmt.Errorf("device %s not found", deviceID)
+	}
+
+	name = devices[0].Metadata.Name
+	b.deviceMu.Lock()
+	b.deviceNames[deviceID] = name
+	b.deviceMu.Unlock()
+	return name, nil
+}
+
 // SetLightOn turns a light on or off
 func (b *HueBridge) SetLightOn(ctx context.Context, lightID string, on bool) error {
 	body := fmt.Sprintf(`{"on":{"on":%t}}`, on)
@@ -388,6 +661,24 @@ func (b *HueBridge) SetLightColorXY(ctx context.Context, lightID string, x, y fl
 	return b.setLightState(ctx, lightID, body)
 }
 
+// SetLightGradient sets the color points of a gradient light, from one end
+// to the other
+func (b *HueBridge) SetLightGradient(ctx context.Context, lightID string, points [][2]float64) error {
+	if len(points) < models.MinGradientPoints {
+		return fmt.Errorf("a gradient needs at least %d points", models.MinGradientPoints)
+	}
+	gradient := gradientJSON{Points: make([]gradientPointJSON, len(points))}
+	for i, p := range points {
+		gradient.Points[i].Color.XY.X = p[0]
+		gradient.Points[i].Color.XY.Y = p[1]
+	}
+	body, err := json.Marshal(map[string]interface{}{"gradient": gradient})
+	if err != nil {
+		return fmt.Errorf("failed to encode gradient: %w", err)
+	}
+	return b.setLightState(ctx, lightID, string(body))
+}
+
 // HSToXY converts Hue/Saturation values to XY color space coordinates.
 // hue is in range 0-65535, sat is in range 0-254.
 // Returns x, y coordinates in CIE 1931 color space.
@@ -443,6 +734,25 @@ func (b *HueBridge) SetLightColorHS(ctx context.Context, lightID string, hue uin
 	return b.setLightState(ctx, lightID, body)
 }
 
+// IdentifyLight makes a light breathe briefly so the physical bulb can be
+// spotted. It doesn't change the light's state.
+func (b *HueBridge) IdentifyLight(ctx context.Context, lightID string) error {
+	return b.setLightState(ctx, lightID, `{"alert":{"action":"breathe"}}`)
+}
+
+// SetLightArchetype sets the type of a light, which picks its icon in the
+// Hue app
+func (b *HueBridge) SetLightArchetype(ctx context.Context, lightID, archetype string) error {
+	if !slices.Contains(models.LightArchetypes, archetype) {
+		return fmt.Errorf("unknown light archetype %q", archetype)
+	}
+	body, err := json.Marshal(map[string]interface{}{"metadata": resourceMetadata{Archetype: archetype}})
+	if err != nil {
+		return fmt.Errorf("failed to encode archetype: %w", err)
+	}
+	return b.setLightState(ctx, lightID, string(body))
+}
+
 func abs64(x float64) float64 {
 	if x < 0 {
 		return -x
@@ -488,8 +798,25 @@ func (b *HueBridge) setLightState(ctx context.Context, lightID, bodyStr string)
 }
 
 // SetGroupedLightOn turns all lights in a group on or off
-func (b *HueBridge) SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) (err error) {
-	body := fmt.Sprintf(`{"on":{"on":%t}}`, on)
+func (b *HueBridge) SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) error {
+	return b.setGroupedLightState(ctx, groupedLightID, fmt.Sprintf(`{"on":{"on":%t}}`, on))
+}
+
+// FadeGroupedLightOn turns a group of lights on or off over duration
+func (b *HueBridge) FadeGroupedLightOn(ctx context.Context, groupedLightID string, on bool, duration time.Duration) error {
+	body := fmt.Sprintf(`{"on":{"on":%t},"dynamics":{"duration":%d}}`, on, duration.Milliseconds())
+	return b.setGroupedLightState(ctx, groupedLightID, body)
+}
+
+// SetGroupedLightBrightness sets every light of a group to brightness
+// percent (0-100) in one command
+func (b *HueBridge) SetGroupedLightBrightness(ctx context.Context, groupedLightID string, brightness int) error {
+	brightness = max(0, min(100, brightness))
+	return b.setGroupedLightState(ctx, groupedLightID, fmt.Sprintf(`{"dimming":{"brightness":%d}}`, brightness))
+}
+
+// setGroupedLightState sends a state update to a grouped light
+func (b *HueBridge) setGroupedLightState(ctx context.Context, groupedLightID, body string) (err error) {
 	path := fmt.Sprintf("/clip/v2/resource/grouped_light/%s", groupedLightID)
 	resp, err := b.doRequest(ctx, "PUT", path, strings.NewReader(body))
 	if err != nil {
@@ -510,12 +837,39 @@ func (b *HueBridge) SetGroupedLightOn(ctx context.Context, groupedLightID string
 }
 
 // ActivateScene activates a scene
-func (b *HueBridge) ActivateScene(ctx context.Context, sceneID string) (err error) {
-	body := `{"recall":{"action":"active"}}`
+func (b *HueBridge) ActivateScene(ctx context.Context, sceneID string) error {
+	if err := b.recallScene(ctx, sceneID, `{"action":"active"}`); err != nil {
+		return fmt.Errorf("failed to activate scene: %w", err)
+	}
+	return nil
+}
+
+// ActivateSceneOver activates a scene, fading to it over duration
+func (b *HueBridge) ActivateSceneOver(ctx context.Context, sceneID string, duration time.Duration) error {
+	recall := fmt.Sprintf(`{"action":"active","duration":%d}`, duration.Milliseconds())
+	if err := b.recallScene(ctx, sceneID, recall); err != nil {
+		return fmt.Errorf("failed to activate scene: %w", err)
+	}
+	return nil
+}
+
+// StopSceneDynamics stops a dynamic scene from cycling, keeping the
+// current colors
+func (b *HueBridge) StopSceneDynamics(ctx context.Context, sceneID string) error {
+	if err := b.recallScene(ctx, sceneID, `{"action":"static"}`); err != nil {
+		return fmt.Errorf("failed to stop scene dynamics: %w", err)
+	}
+	return nil
+}
+
+// recallScene recalls a scene with the given recall object, such as
+// {"action":"static"}
+func (b *HueBridge) recallScene(ctx context.Context, sceneID, recall string) (err error) {
+	body := fmt.Sprintf(`{"recall":%s}`, recall)
 	path := fmt.Sprintf("/clip/v2/resource/scene/%s", sceneID)
 	resp, err := b.doRequest(ctx, "PUT", path, strings.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to activate scene: %w", err)
+		return err
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil && err == nil {
@@ -531,8 +885,48 @@ func (b *HueBridge) ActivateScene(ctx context.Context, sceneID string) (err erro
 	return nil
 }
 
+// OtherRoomID is the ID of the synthetic room holding lights without a room
+const OtherRoomID = "other"
+
+// HomeRoomID is the ID of the synthetic room holding every light
+const HomeRoomID = "home"
+
+// HomeGroupedLightID returns the grouped light of the bridge_home resource,
+// which holds every light on the bridge
+func (b *HueBridge) HomeGroupedLightID(ctx context.Context) (string, error) {
+	var homes []struct {
+		Services []resourceRef `json:"services"`
+	}
+	if err := b.listResources(ctx, "bridge_home", &homes); err != nil {
+		return "", err
+	}
+	for _, home := range homes {
+		for _, svc := range home.Services {
+			if svc.Rtype == "grouped_light" {
+				return svc.Rid, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to get bridge_home: no grouped_light service")
+}
+
+// NewOtherRoom creates the synthetic "Other Lights" room
+func NewOtherRoom() *models.Room {
+	return &models.Room{
+		ID:   OtherRoomID,
+		Name: "Other Lights",
+	}
+}
+
 // AssignLightsToRooms assigns lights to rooms based on device ownership
 func (b *HueBridge) AssignLightsToRooms(lights []*models.Light, rooms []*models.Room) []*models.Room {
+	return assignLightsToRooms(lights, rooms)
+}
+
+// assignLightsToRooms assigns lights to the rooms holding their device,
+// grouping the others in an "Other Lights" room. Rooms without lights are
+// left out.
+func assignLightsToRooms(lights []*models.Light, rooms []*models.Room) []*models.Room {
 	// Build device to room mapping from room.DeviceIDs
 	deviceToRoom := make(map[string]*models.Room)
 	for _, room := range rooms {
@@ -543,10 +937,7 @@ func (b *HueBridge) AssignLightsToRooms(lights []*models.Light, rooms []*models.
 	}
 
 	// Create "Other Lights" room for ungrouped lights
-	otherRoom := &models.Room{
-		ID:   "other",
-		Name: "Other Lights",
-	}
+	otherRoom := NewOtherRoom()
 
 	// Assign lights to rooms based on device ID
 	for _, light := range lights {
@@ -577,68 +968,108 @@ func (b *HueBridge) AssignLightsToRooms(lights []*models.Light, rooms []*models.
 
 // FetchAll retrieves all resources from the bridge
 func (b *HueBridge) FetchAll(ctx context.Context) ([]*models.Room, []*models.Scene, error) {
-	// Fetch rooms (includes device IDs in children)
-	rooms, err := b.GetRooms(ctx)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to fetch rooms: %w", err)
-	}
+	return b.FetchAllProgress(ctx, nil)
+}
 
-	// Build room lookup for scene assignment
-	roomByID := make(map[string]*models.Room)
-	for _, room := range rooms {
-		roomByID[room.ID] = room
+// FetchAllProgress retrieves all resources from the bridge, loading each
+// resource type concurrently and reporting it to progress as it completes
+func (b *HueBridge) FetchAllProgress(ctx context.Context, progress FetchProgress) ([]*models.Room, []*models.Scene, error) {
+	var (
+		wg           sync.WaitGroup
+		progressMu   sync.Mutex
+		rooms        []*models.Room
+		lights       []*models.Light
+		scenes       []*models.Scene
+		devices      []deviceResource
+		connectivity []zigbeeConnectivityResource
+		roomsErr     error
+		lightsErr    error
+		scenesErr    error
+		devicesErr   error
+	)
+	report := func(resource string, count int) {
+		if progress == nil {
+			return
+		}
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		progress(resource, count)
+	}
+	fetch := func(resource string, load func() (int, error)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if count, err := load(); err == nil {
+				report(resource, count)
+			}
+		}()
 	}
 
-	// Fetch lights
-	lights, err := b.GetLights(ctx)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to fetch lights: %w", err)
+	// Rooms include device IDs in their children
+	fetch(FetchRooms, func() (int, error) {
+		rooms, roomsErr = b.GetRooms(ctx)
+		return len(rooms), roomsErr
+	})
+	fetch(FetchLights, func() (int, error) {
+		lights, lightsErr = b.GetLights(ctx)
+		return len(lights), lightsErr
+	})
+	// Devices map lights to rooms, connectivity flags unreachable lights.
+	// Both are optional.
+	fetch(FetchDevices, func() (int, error) {
+		devicesErr = b.listResources(ctx, "device", &devices)
+		if devicesErr == nil {
+			_ = b.listResources(ctx, "zigbee_connectivity", &connectivity) // Error ignored: optional
+		}
+		return len(devices), devicesErr
+	})
+	fetch(FetchScenes, func() (int, error) {
+		scenes, scenesErr = b.GetScenes(ctx)
+		return len(scenes), scenesErr
+	})
+	wg.Wait()
+
+	if roomsErr != nil {
+		return nil, nil, fmt.Errorf("failed to fetch rooms: %w", roomsErr)
+	}
+	if lightsErr != nil {
+		return nil, nil, fmt.Errorf("failed to fetch lights: %w", lightsErr)
 	}
 
-	// Fetch devices to map light ID -> device ID
-	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/device", nil)
-	if err == nil {
-		func() {
-			defer func() {
-				_ = resp.Body.Close() // Error ignored: optional device fetch
-			}()
-
-			var apiResp apiResponse
-			if json.NewDecoder(resp.Body).Decode(&apiResp) == nil {
-				var devices []struct {
-					ID       string `json:"id"`
-					Services []struct {
-						Rid   string `json:"rid"`
-						Rtype string `json:"rtype"`
-					} `json:"services"`
-				}
-				if json.Unmarshal(apiResp.Data, &devices) == nil {
-					// Map light ID to device ID
-					for _, device := range devices {
-						for _, svc := range device.Services {
-							if svc.Rtype == "light" {
-								// Find the light and set its device ID
-								for _, light := range lights {
-									if light.ID == svc.Rid {
-										light.DeviceID = device.ID
-										break
-									}
-								}
-							}
-						}
-					}
+	// Map light ID to device ID and cache device names
+	if devicesErr == nil {
+		lightByID := make(map[string]*models.Light, len(lights))
+		for _, light := range lights {
+			lightByID[light.ID] = light
+		}
+		b.deviceMu.Lock()
+		for _, device := range devices {
+			b.deviceNames[device.ID] = device.Metadata.Name
+			for _, svc := range device.Services {
+				if light, ok := lightByID[svc.Rid]; ok && svc.Rtype == "light" {
+					light.DeviceID = device.ID
+					light.DeviceName = device.Metadata.Name
 				}
 			}
-		}()
+		}
+		b.deviceMu.Unlock()
+	}
+
+	// Mark lights of disconnected devices as unreachable
+	applyConnectivity(lights, connectivity)
+
+	// Build room lookup for scene assignment
+	roomByID := make(map[string]*models.Room)
+	for _, room := range rooms {
+		roomByID[room.ID] = room
 	}
 
 	// Assign lights to rooms using device IDs
 	rooms = b.AssignLightsToRooms(lights, rooms)
 
-	// Fetch scenes
-	scenes, err := b.GetScenes(ctx)
-	if err != nil {
-		return rooms, nil, fmt.Errorf("failed to fetch scenes: %w", err)
+	// Rooms and lights are usable without scenes
+	if scenesErr != nil {
+		return rooms, nil, fmt.Errorf("%w: %w", ErrScenesUnavailable, scenesErr)
 	}
 
 	// Add room names to scenes
@@ -1,8 +1,17 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
 )
 
 func TestHSToXY(t *testing.T) {
@@ -120,3 +129,317 @@ func TestHSToXY_HueWrap(t *testing.T) {
 			x0, y0, xMax, yMax)
 	}
 }
+
+func TestSceneResourceActions(t *testing.T) {
+	data := `{
+		"id": "scene-1",
+		"metadata": {"name": "Savanna Sunset"},
+		"group": {"rid": "room-1", "rtype": "room"},
+		"status": {"active": "inactive", "last_recall": "2026-03-01T20:15:00.000Z"},
+		"actions": [
+			{"target": {"rid": "light-1", "rtype": "light"},
+			 "action": {"on": {"on": true}, "dimming": {"brightness": 50}, "color": {"xy": {"x": 0.6, "y": 0.38}}}},
+			{"target": {"rid": "light-2", "rtype": "light"},
+			 "action": {"on": {"on": true}, "color_temperature": {"mirek": 400}}},
+			{"target": {"rid": "light-3", "rtype": "light"},
+			 "action": {"on": {"on": false}}}
+		]
+	}`
+
+	var raw sceneResource
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatalf("Failed to parse scene: %v", err)
+	}
+	scene := raw.toModel()
+
+	if len(scene.Actions) != 3 {
+		t.Fatalf("Expected 3 actions, got %d", len(scene.Actions))
+	}
+	if want := time.Date(2026, 3, 1, 20, 15, 0, 0, time.UTC); !scene.LastRecalled.Equal(want) {
+		t.Errorf("Expected last recall %v, got %v", want, scene.LastRecalled)
+	}
+	first := scene.Actions[0]
+	if first.LightID != "light-1" || !first.On || first.Brightness != 127 || first.Color == nil || first.Color.X != 0.6 {
+		t.Errorf("Unexpected first action: %+v", first)
+	}
+	if c := scene.Actions[1].Color; c == nil || c.Mirek != 400 {
+		t.Errorf("Expected color temperature action, got %+v", scene.Actions[1])
+	}
+	if scene.Actions[2].On {
+		t.Error("Expected third light to be off")
+	}
+
+	// Only lights turned on get a swatch, shown at full brightness
+	swatches := scene.Swatches()
+	if len(swatches) != 2 {
+		t.Fatalf("Expected 2 swatches, got %d", len(swatches))
+	}
+	if swatches[0].Brightness != 254 || first.Color.Brightness != 127 {
+		t.Error("Expected swatches at full brightness without changing the action color")
+	}
+}
+
+func TestLightResourceGradient(t *testing.T) {
+	data := `{
+		"id": "light-1",
+		"metadata": {"name": "Play Strip"},
+		"on": {"on": true},
+		"color": {"xy": {"x": 0.15, "y": 0.06}},
+		"gradient": {
+			"points": [
+				{"color": {"xy": {"x": 0.15, "y": 0.06}}},
+				{"color": {"xy": {"x": 0.64, "y": 0.33}}}
+			],
+			"points_capable": 5
+		}
+	}`
+
+	var raw lightResource
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	light := raw.toModel()
+
+	if light.Gradient == nil {
+		t.Fatal("Expected a gradient")
+	}
+	if light.Gradient.PointsCapable != 5 || len(light.Gradient.Points) != 2 {
+		t.Errorf("Unexpected gradient: %+v", light.Gradient)
+	}
+	if p := light.Gradient.Points[1]; p.X != 0.64 || p.Y != 0.33 {
+		t.Errorf("Expected second point {0.64, 0.33}, got {%f, %f}", p.X, p.Y)
+	}
+
+	// Lights without gradient support have none
+	var plain lightResource
+	if err := json.Unmarshal([]byte(`{"id": "light-2", "on": {"on": true}}`), &plain); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	if plain.toModel().Gradient != nil {
+		t.Error("Expected no gradient")
+	}
+}
+
+func TestLightResourceOnOffOnly(t *testing.T) {
+	var plug lightResource
+	if err := json.Unmarshal([]byte(`{"id": "plug-1", "metadata": {"name": "Plug"}, "on": {"on": true}}`), &plug); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	if !plug.toModel().OnOffOnly {
+		t.Error("Expected a light without dimming to be on/off only")
+	}
+
+	var bulb lightResource
+	if err := json.Unmarshal([]byte(`{"id": "bulb-1", "on": {"on": true}, "dimming": {"brightness": 50}}`), &bulb); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	if bulb.toModel().OnOffOnly {
+		t.Error("Expected a dimmable light not to be on/off only")
+	}
+}
+
+func TestSetLightArchetype(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = r.Method + " " + r.URL.Path + " " + string(data)
+		_, _ = w.Write([]byte(`{"data": [{"rid": "light-1"}], "errors": []}`))
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+
+	if err := b.SetLightArchetype(context.Background(), "light-1", "candle_bulb"); err != nil {
+		t.Fatalf("SetLightArchetype failed: %v", err)
+	}
+	if want := `PUT /clip/v2/resource/light/light-1 {"metadata":{"archetype":"candle_bulb"}}`; body != want {
+		t.Errorf("Sent %q, want %q", body, want)
+	}
+
+	body = ""
+	if err := b.SetLightArchetype(context.Background(), "light-1", "lava_lamp"); err == nil || body != "" {
+		t.Error("Expected an unknown archetype to be refused before sending")
+	}
+
+	var light lightResource
+	if err := json.Unmarshal([]byte(`{"id": "light-1", "metadata": {"name": "Desk", "archetype": "table_shade"}}`), &light); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	if got := light.toModel().Archetype; got != "table_shade" {
+		t.Errorf("Archetype = %q, want table_shade", got)
+	}
+}
+
+func TestActivateSceneOver(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = r.Method + " " + r.URL.Path + " " + string(data)
+		_, _ = w.Write([]byte(`{"data": [{"rid": "scene-1"}], "errors": []}`))
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+
+	if err := b.ActivateSceneOver(context.Background(), "scene-1", 5*time.Second); err != nil {
+		t.Fatalf("ActivateSceneOver failed: %v", err)
+	}
+	if want := `PUT /clip/v2/resource/scene/scene-1 {"recall":{"action":"active","duration":5000}}`; body != want {
+		t.Errorf("Sent %q, want %q", body, want)
+	}
+
+	if err := b.ActivateScene(context.Background(), "scene-1"); err != nil {
+		t.Fatalf("ActivateScene failed: %v", err)
+	}
+	if want := `PUT /clip/v2/resource/scene/scene-1 {"recall":{"action":"active"}}`; body != want {
+		t.Errorf("Sent %q, want %q", body, want)
+	}
+}
+
+func TestSetLightState(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = r.Method + " " + r.URL.Path + " " + string(data)
+		_, _ = w.Write([]byte(`{"data": [], "errors": []}`))
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+
+	on, brightness, mirek := true, 40.0, 370
+	if err := b.SetLightState(context.Background(), SceneAction{LightID: "light-1", On: &on, Brightness: &brightness}); err != nil {
+		t.Fatalf("SetLightState failed: %v", err)
+	}
+	if want := `PUT /clip/v2/resource/light/light-1 {"dimming":{"brightness":40},"on":{"on":true}}`; body != want {
+		t.Errorf("Sent %q, want %q", body, want)
+	}
+
+	if err := b.SetGroupedLightState(context.Background(), "group-1", SceneAction{Mirek: &mirek}); err != nil {
+		t.Fatalf("SetGroupedLightState failed: %v", err)
+	}
+	if want := `PUT /clip/v2/resource/grouped_light/group-1 {"color_temperature":{"mirek":370}}`; body != want {
+		t.Errorf("Sent %q, want %q", body, want)
+	}
+}
+
+func TestSmartScenes(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/clip/v2/resource/scene":
+			_, _ = w.Write([]byte(`{"data": [{"id": "scene-1", "metadata": {"name": "Relax"}, "group": {"rid": "room-1", "rtype": "room"}}], "errors": []}`))
+		case r.Method == "GET" && r.URL.Path == "/clip/v2/resource/smart_scene":
+			_, _ = w.Write([]byte(`{"data": [{"id": "smart-1", "metadata": {"name": "Natural light"}, "group": {"rid": "room-1", "rtype": "room"}, "state": "active"}], "errors": []}`))
+		default:
+			data, _ := io.ReadAll(r.Body)
+			body = r.Method + " " + r.URL.Path + " " + string(data)
+			_, _ = w.Write([]byte(`{"data": [{"rid": "smart-1"}], "errors": []}`))
+		}
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+
+	scenes, err := b.GetScenes(context.Background())
+	if err != nil {
+		t.Fatalf("GetScenes failed: %v", err)
+	}
+	if len(scenes) != 2 {
+		t.Fatalf("Got %d scenes, want the scene and the smart scene", len(scenes))
+	}
+	smart := scenes[1]
+	if !smart.IsSmart || smart.RoomID != "room-1" || !smart.IsActive() || scenes[0].IsSmart {
+		t.Errorf("Smart scene = %+v", smart)
+	}
+
+	if err := b.SetSmartSceneActive(context.Background(), "smart-1", false); err != nil {
+		t.Fatalf("SetSmartSceneActive failed: %v", err)
+	}
+	if want := `PUT /clip/v2/resource/smart_scene/smart-1 {"recall":{"action":"deactivate"}}`; body != want {
+		t.Errorf("Sent %q, want %q", body, want)
+	}
+}
+
+func TestLightResourceMirekRange(t *testing.T) {
+	var light lightResource
+	data := `{"id": "light-1", "color_temperature": {"mirek": 366, "mirek_valid": true, "mirek_schema": {"mirek_minimum": 153, "mirek_maximum": 454}}}`
+	if err := json.Unmarshal([]byte(data), &light); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	if lo, hi := light.toModel().MirekRange(); lo != 153 || hi != 454 {
+		t.Errorf("MirekRange() = %d-%d, want 153-454", lo, hi)
+	}
+}
+
+func TestLightResourceGamut(t *testing.T) {
+	var light lightResource
+	data := `{"id": "light-1", "color": {"xy": {"x": 0.3, "y": 0.3}, "gamut_type": "C", "gamut": {"red": {"x": 0.6915, "y": 0.3083}, "green": {"x": 0.17, "y": 0.7}, "blue": {"x": 0.1532, "y": 0.0475}}}}`
+	if err := json.Unmarshal([]byte(data), &light); err != nil {
+		t.Fatalf("Failed to parse light: %v", err)
+	}
+	gamut := light.toModel().Gamut
+	if gamut == nil || *gamut != models.GamutC {
+		t.Errorf("Gamut = %+v, want gamut C", gamut)
+	}
+}
+
+func TestApplyConnectivity(t *testing.T) {
+	lights := []*models.Light{
+		{ID: "l1", DeviceID: "d1", Reachable: true},
+		{ID: "l2", DeviceID: "d2", Reachable: true},
+		{ID: "l3", DeviceID: "d3", Reachable: true},
+	}
+	applyConnectivity(lights, []zigbeeConnectivityResource{
+		{ID: "z1", Owner: resourceRef{Rid: "d1", Rtype: "device"}, Status: "connected"},
+		{ID: "z2", Owner: resourceRef{Rid: "d2", Rtype: "device"}, Status: "connectivity_issue"},
+	})
+
+	if !lights[0].Reachable || lights[1].Reachable || !lights[2].Reachable {
+		t.Errorf("Unexpected reachability: %v %v %v", lights[0].Reachable, lights[1].Reachable, lights[2].Reachable)
+	}
+}
+
+func TestHomeGroupedLightID(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/clip/v2/resource/bridge_home" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "home-1", "services": [{"rid": "room-1", "rtype": "room"}, {"rid": "group-0", "rtype": "grouped_light"}]}], "errors": []}`))
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+
+	id, err := b.HomeGroupedLightID(context.Background())
+	if err != nil {
+		t.Fatalf("HomeGroupedLightID failed: %v", err)
+	}
+	if id != "group-0" {
+		t.Errorf("HomeGroupedLightID = %q, want group-0", id)
+	}
+}
+
+func TestGetDeviceName(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/clip/v2/resource/device/device-1" {
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+		_, _ = w.Write([]byte(`{"data": [{"id": "device-1", "metadata": {"name": "Hue go"}}], "errors": []}`))
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+
+	// The second lookup comes from the cache
+	for i := 0; i < 2; i++ {
+		name, err := b.GetDeviceName(context.Background(), "device-1")
+		if err != nil {
+			t.Fatalf("GetDeviceName failed: %v", err)
+		}
+		if name != "Hue go" {
+			t.Errorf("GetDeviceName = %q, want Hue go", name)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
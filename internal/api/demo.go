@@ -2,6 +2,9 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,14 +16,28 @@ import (
 type DemoBridge struct {
 	rooms  []*models.Room
 	scenes []*models.Scene
-	lights map[string]*models.Light // ID -> Light for quick lookup
-	mu     sync.RWMutex
+	areas  []*models.EntertainmentArea
+	// Battery powered switches and sensors
+	devices []*models.Device
+	lights  map[string]*models.Light // ID -> Light for quick lookup
+	mu      sync.RWMutex
+
+	schedules      []*models.Schedule
+	nextScheduleID int
+
+	// Rooms may be empty here, FetchAll leaves them out
+	zones       []*models.Room
+	nextGroupID int
+
+	// Presets of the scenes created in demo mode, by scene ID
+	createdScenes map[string]map[string]lightState
 }
 
 // NewDemoBridge creates a demo bridge with sample data
 func NewDemoBridge() *DemoBridge {
 	d := &DemoBridge{
-		lights: make(map[string]*models.Light),
+		lights:        make(map[string]*models.Light),
+		createdScenes: make(map[string]map[string]lightState),
 	}
 	d.initializeDemoData()
 	// Verify data was initialized (will panic if not, for debugging)
@@ -42,20 +59,94 @@ func (d *DemoBridge) BridgeID() string {
 
 // FetchAll returns the demo rooms and scenes
 func (d *DemoBridge) FetchAll(ctx context.Context) ([]*models.Room, []*models.Scene, error) {
+	return d.FetchAllProgress(ctx, nil)
+}
+
+// FetchAllProgress returns the demo rooms and scenes, reporting each
+// resource type as if it came from a bridge
+func (d *DemoBridge) FetchAllProgress(ctx context.Context, progress FetchProgress) ([]*models.Room, []*models.Scene, error) {
+	d.mu.RLock()
+	counts := map[string]int{
+		FetchRooms:  len(d.rooms),
+		FetchScenes: len(d.scenes),
+	}
+	for _, room := range d.rooms {
+		counts[FetchLights] += len(room.Lights)
+		counts[FetchDevices] += len(room.Lights)
+	}
+	d.mu.RUnlock()
+
 	// Simulate network delay for realistic demo experience
-	time.Sleep(1 * time.Second)
+	for _, resource := range FetchResources {
+		time.Sleep(250 * time.Millisecond)
+		if progress != nil {
+			progress(resource, counts[resource])
+		}
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	// Return copies to avoid external modification. Like the bridge, rooms
+	// without lights are left out and lights without a room are grouped.
+	rooms := make([]*models.Room, 0, len(d.rooms)+1)
+	inRoom := make(map[string]bool)
+	for _, room := range d.rooms {
+		if len(room.Lights) > 0 {
+			rooms = append(rooms, room)
+		}
+		for _, light := range room.Lights {
+			inRoom[light.ID] = true
+		}
+	}
+	other := NewOtherRoom()
+	for _, light := range d.lights {
+		if !inRoom[light.ID] {
+			other.Lights = append(other.Lights, light)
+		}
+	}
+	if len(other.Lights) > 0 {
+		slices.SortFunc(other.Lights, func(a, b *models.Light) int { return strings.Compare(a.ID, b.ID) })
+		other.UpdateState()
+		rooms = append(rooms, other)
+	}
+
+	return rooms, d.listScenes(), nil
+}
 
+// GetDeviceName returns the name of the device of a demo light or sensor
+func (d *DemoBridge) GetDeviceName(ctx context.Context, deviceID string) (string, error) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	// Return copies to avoid external modification
-	rooms := make([]*models.Room, len(d.rooms))
-	copy(rooms, d.rooms)
+	for _, light := range d.lights {
+		if light.DeviceID == deviceID {
+			return light.DeviceName, nil
+		}
+	}
+	for _, device := range d.devices {
+		if device.ID == deviceID {
+			return device.Name, nil
+		}
+	}
+	return "", fmt.Errorf("device %s not found", deviceID)
+}
 
-	scenes := make([]*models.Scene, len(d.scenes))
-	copy(scenes, d.scenes)
+// GetLight returns a copy of a demo light
+func (d *DemoBridge) GetLight(ctx context.Context, lightID string) (*models.Light, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-	return rooms, scenes, nil
+	light, ok := d.lights[lightID]
+	if !ok {
+		return nil, fmt.Errorf("light %s not found", lightID)
+	}
+	copied := *light
+	if light.Color != nil {
+		color := *light.Color
+		copied.Color = &color
+	}
+	return &copied, nil
 }
 
 // SetLightOn turns a demo light on or off
@@ -91,13 +182,7 @@ func (d *DemoBridge) SetLightColorTemp(ctx context.Context, lightID string, mire
 	defer d.mu.Unlock()
 
 	if light, ok := d.lights[lightID]; ok && light.Color != nil {
-		if mirek < 153 {
-			mirek = 153
-		}
-		if mirek > 500 {
-			mirek = 500
-		}
-		light.Color.Mirek = uint16(mirek)
+		light.Color.Mirek = uint16(light.ClampMirek(mirek))
 		light.Color.Mode = models.ColorModeColorTemp
 		light.Color.InvalidateCache()
 	}
@@ -118,6 +203,28 @@ func (d *DemoBridge) SetLightColorXY(ctx context.Context, lightID string, x, y f
 	return nil
 }
 
+// SetLightGradient sets the points of a demo gradient light
+func (d *DemoBridge) SetLightGradient(ctx context.Context, lightID string, points [][2]float64) error {
+	if len(points) < models.MinGradientPoints {
+		return fmt.Errorf("a gradient needs at least %d points", models.MinGradientPoints)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	light, ok := d.lights[lightID]
+	if !ok || light.Gradient == nil {
+		return fmt.Errorf("light %s has no gradient", lightID)
+	}
+	if len(points) > light.Gradient.PointsCapable {
+		return fmt.Errorf("light %s takes at most %d gradient points", lightID, light.Gradient.PointsCapable)
+	}
+	light.Gradient.Points = nil
+	for _, p := range points {
+		light.Gradient.Points = append(light.Gradient.Points, models.NewColorFromXY(p[0], p[1], 254))
+	}
+	return nil
+}
+
 // SetLightColorHS sets a demo light's color using Hue/Saturation
 func (d *DemoBridge) SetLightColorHS(ctx context.Context, lightID string, hue uint16, sat uint8) error {
 	d.mu.Lock()
@@ -136,11 +243,67 @@ func (d *DemoBridge) SetLightColorHS(ctx context.Context, lightID string, hue ui
 	return nil
 }
 
+// IdentifyLight does nothing in demo mode, there is no bulb to blink
+func (d *DemoBridge) IdentifyLight(ctx context.Context, lightID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.lights[lightID]; !ok {
+		return fmt.Errorf("light %s not found", lightID)
+	}
+	return nil
+}
+
+// SetLightArchetype sets the type of a demo light
+func (d *DemoBridge) SetLightArchetype(ctx context.Context, lightID, archetype string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	light, ok := d.lights[lightID]
+	if !ok {
+		return fmt.Errorf("light %s not found", lightID)
+	}
+	if !slices.Contains(models.LightArchetypes, archetype) {
+		return fmt.Errorf("unknown light archetype %q", archetype)
+	}
+	light.Archetype = archetype
+	return nil
+}
+
+// RenameLight changes the name of a demo light
+func (d *DemoBridge) RenameLight(ctx context.Context, lightID, name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	light, ok := d.lights[lightID]
+	if !ok {
+		return fmt.Errorf("light %s not found", lightID)
+	}
+	light.Name = name
+	return nil
+}
+
+// demoHomeGroupID is the grouped light of every demo light
+const demoHomeGroupID = "group-home"
+
+// HomeGroupedLightID returns the demo grouped light of every light
+func (d *DemoBridge) HomeGroupedLightID(ctx context.Context) (string, error) {
+	return demoHomeGroupID, nil
+}
+
 // SetGroupedLightOn turns all lights in a demo group on or off
 func (d *DemoBridge) SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if groupedLightID == demoHomeGroupID {
+		for _, light := range d.lights {
+			light.On = on
+		}
+		d.updateRoomStates()
+		return nil
+	}
+
 	// Find room by grouped light ID and update all lights
 	for _, room := range d.rooms {
 		if room.GroupedLightID == groupedLightID {
@@ -154,16 +317,102 @@ func (d *DemoBridge) SetGroupedLightOn(ctx context.Context, groupedLightID strin
 	return nil
 }
 
+// FadeGroupedLightOn switches a demo room at once, as demo lights have
+// no transitions
+func (d *DemoBridge) FadeGroupedLightOn(ctx context.Context, groupedLightID string, on bool, duration time.Duration) error {
+	return d.SetGroupedLightOn(ctx, groupedLightID, on)
+}
+
+// SetGroupedLightBrightness sets every light of a demo group to
+// brightness percent
+func (d *DemoBridge) SetGroupedLightBrightness(ctx context.Context, groupedLightID string, brightness int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var lights []*models.Light
+	if groupedLightID == demoHomeGroupID {
+		for _, light := range d.lights {
+			lights = append(lights, light)
+		}
+	} else {
+		for _, room := range d.rooms {
+			if room.GroupedLightID == groupedLightID {
+				lights = room.Lights
+				break
+			}
+		}
+	}
+	for _, light := range lights {
+		light.SetBrightnessPct(brightness)
+		if light.Color != nil {
+			light.Color.Brightness = light.Brightness
+			light.Color.InvalidateCache()
+		}
+	}
+	d.updateRoomStates()
+	return nil
+}
+
+// ActivateSceneOver activates a demo scene. Demo lights change at once.
+func (d *DemoBridge) ActivateSceneOver(ctx context.Context, sceneID string, duration time.Duration) error {
+	return d.ActivateScene(ctx, sceneID)
+}
+
+// GetScenes returns the demo scenes
+func (d *DemoBridge) GetScenes(ctx context.Context) ([]*models.Scene, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.listScenes(), nil
+}
+
+// listScenes copies the scenes, with the smart scene schedules listed as
+// smart scenes. The caller holds the lock.
+func (d *DemoBridge) listScenes() []*models.Scene {
+	scenes := make([]*models.Scene, len(d.scenes))
+	copy(scenes, d.scenes)
+	for _, s := range d.schedules {
+		if s.Kind != models.ScheduleSmartScene {
+			continue
+		}
+		status := "inactive"
+		if s.Enabled {
+			status = "active"
+		}
+		scenes = append(scenes, &models.Scene{ID: s.ID, Name: s.Name, RoomID: s.GroupID, Status: status, IsSmart: true})
+	}
+	return scenes
+}
+
 // ActivateScene activates a demo scene with preset light states
 func (d *DemoBridge) ActivateScene(ctx context.Context, sceneID string) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	preset, ok := demoScenePresets[sceneID]
+	preset, ok := d.preset(sceneID)
 	if !ok {
 		return nil
 	}
 
+	// Recalling a scene deactivates the others of its room
+	var roomID string
+	for _, scene := range d.scenes {
+		if scene.ID == sceneID {
+			roomID = scene.RoomID
+			scene.LastRecalled = time.Now()
+		}
+	}
+	for _, scene := range d.scenes {
+		switch {
+		case scene.ID == sceneID && scene.IsDynamic:
+			scene.Status = "dynamic_palette"
+		case scene.ID == sceneID:
+			scene.Status = "static"
+		case scene.RoomID == roomID:
+			scene.Status = "inactive"
+		}
+	}
+
 	for lightID, state := range preset {
 		if light, ok := d.lights[lightID]; ok {
 			light.On = state.On
@@ -189,6 +438,406 @@ func (d *DemoBridge) ActivateScene(ctx context.Context, sceneID string) error {
 	return nil
 }
 
+// StopSceneDynamics stops a cycling demo scene
+func (d *DemoBridge) StopSceneDynamics(ctx context.Context, sceneID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, scene := range d.scenes {
+		if scene.ID == sceneID && scene.IsCycling() {
+			scene.Status = "static"
+		}
+	}
+	return nil
+}
+
+// SetSmartSceneActive starts or stops a demo smart scene
+func (d *DemoBridge) SetSmartSceneActive(ctx context.Context, sceneID string, active bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, s := range d.schedules {
+		if s.ID == sceneID && s.Kind == models.ScheduleSmartScene {
+			s.Enabled = active
+		}
+	}
+	return nil
+}
+
+// GetEntertainmentAreas returns the demo entertainment areas
+func (d *DemoBridge) GetEntertainmentAreas(ctx context.Context) ([]*models.EntertainmentArea, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	areas := make([]*models.EntertainmentArea, len(d.areas))
+	for i, area := range d.areas {
+		clone := *area
+		areas[i] = &clone
+	}
+	return areas, nil
+}
+
+// GetBridgeInfo returns made-up details of a square bridge
+func (d *DemoBridge) GetBridgeInfo(ctx context.Context) (*models.BridgeInfo, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	resources := len(d.rooms) + len(d.zones) + len(d.lights) + len(d.scenes) + len(d.devices)
+	return &models.BridgeInfo{
+		Name:            "Demo Bridge",
+		ModelID:         "BSB002",
+		SoftwareVersion: "1967054020",
+		APIVersion:      "1.65.0",
+		ZigbeeChannel:   25,
+		Resources:       resources,
+		UpdateState:     "noupdates",
+		TimeZone:        "Europe/Paris",
+		Latency:         12 * time.Millisecond,
+	}, nil
+}
+
+// GetBatteryDevices returns the demo switches and sensors
+func (d *DemoBridge) GetBatteryDevices(ctx context.Context) ([]*models.Device, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	devices := make([]*models.Device, len(d.devices))
+	for i, device := range d.devices {
+		clone := *device
+		devices[i] = &clone
+	}
+	return devices, nil
+}
+
+// SetEntertainmentActive starts or stops a demo entertainment session
+func (d *DemoBridge) SetEntertainmentActive(ctx context.Context, areaID string, active bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, area := range d.areas {
+		if area.ID == areaID {
+			area.Status = "inactive"
+			if active {
+				area.Status = "active"
+			}
+		}
+	}
+	return nil
+}
+
+// sceneActions builds a scene's actions from its preset, in room light order
+func (d *DemoBridge) sceneActions(scene *models.Scene) []models.SceneAction {
+	preset, _ := d.preset(scene.ID)
+	var actions []models.SceneAction
+	for _, room := range d.rooms {
+		if room.ID != scene.RoomID {
+			continue
+		}
+		for _, light := range room.Lights {
+			state, ok := preset[light.ID]
+			if !ok {
+				continue
+			}
+			action := models.SceneAction{LightID: light.ID, On: state.On, Brightness: state.Brightness}
+			if state.Mirek > 0 {
+				action.Color = models.NewColorFromMirek(state.Mirek, state.Brightness)
+			} else if state.X > 0 || state.Y > 0 {
+				action.Color = models.NewColorFromXY(state.X, state.Y, state.Brightness)
+			}
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// preset returns the light states of a demo scene
+func (d *DemoBridge) preset(sceneID string) (map[string]lightState, bool) {
+	if preset, ok := demoScenePresets[sceneID]; ok {
+		return preset, true
+	}
+	preset, ok := d.createdScenes[sceneID]
+	return preset, ok
+}
+
+// CreateScene creates a demo scene for a room
+func (d *DemoBridge) CreateScene(ctx context.Context, name, groupID, groupType string, actions []SceneAction) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var room *models.Room
+	for _, r := range d.rooms {
+		if r.ID == groupID {
+			room = r
+		}
+	}
+	if room == nil {
+		return "", fmt.Errorf("%s %s not found", groupType, groupID)
+	}
+
+	preset := make(map[string]lightState)
+	for _, a := range actions {
+		var state lightState
+		if a.On != nil {
+			state.On = *a.On
+		}
+		if a.Brightness != nil {
+			state.Brightness = models.PctToLevel(*a.Brightness)
+		}
+		if a.Mirek != nil {
+			state.Mirek = uint16(*a.Mirek)
+		} else if a.XY != nil {
+			state.X, state.Y = a.XY[0], a.XY[1]
+		}
+		preset[a.LightID] = state
+	}
+
+	id := fmt.Sprintf("scene-custom-%d", len(d.createdScenes)+1)
+	d.createdScenes[id] = preset
+	scene := &models.Scene{ID: id, Name: name, RoomID: room.ID, RoomName: room.Name}
+	scene.Actions = d.sceneActions(scene)
+	d.scenes = append(d.scenes, scene)
+	return id, nil
+}
+
+// DeleteScene removes a demo scene
+func (d *DemoBridge) DeleteScene(ctx context.Context, sceneID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := len(d.scenes)
+	d.scenes = slices.DeleteFunc(d.scenes, func(s *models.Scene) bool { return s.ID == sceneID })
+	if len(d.scenes) == n {
+		return fmt.Errorf("scene %s not found", sceneID)
+	}
+	return nil
+}
+
+// GetSchedules returns the demo schedules
+func (d *DemoBridge) GetSchedules(ctx context.Context) ([]*models.Schedule, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	schedules := make([]*models.Schedule, len(d.schedules))
+	for i, s := range d.schedules {
+		clone := *s
+		schedules[i] = &clone
+	}
+	return schedules, nil
+}
+
+// CreateSchedule adds a demo schedule
+func (d *DemoBridge) CreateSchedule(ctx context.Context, spec ScheduleSpec) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextScheduleID++
+	id := fmt.Sprintf("schedule-%d", d.nextScheduleID)
+	d.schedules = append(d.schedules, &models.Schedule{
+		ID:       id,
+		Resource: "behavior_instance",
+		Name:     spec.Name,
+		Kind:     spec.Kind,
+		Enabled:  true,
+		Hour:     spec.Hour,
+		Minute:   spec.Minute,
+		HasTime:  true,
+		Days:     spec.Days,
+		Fade:     spec.Fade,
+		GroupID:  spec.GroupID,
+		LightIDs: spec.LightIDs,
+	})
+	return id, nil
+}
+
+// DeleteSchedule removes a demo schedule
+func (d *DemoBridge) DeleteSchedule(ctx context.Context, resource, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, s := range d.schedules {
+		if s.ID == id {
+			d.schedules = append(d.schedules[:i], d.schedules[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// GetRooms returns the demo rooms with their device IDs
+func (d *DemoBridge) GetRooms(ctx context.Context) ([]*models.Room, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return cloneGroups(d.rooms), nil
+}
+
+// GetZones returns the demo zones with their light IDs
+func (d *DemoBridge) GetZones(ctx context.Context) ([]*models.Room, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return cloneGroups(d.zones), nil
+}
+
+// cloneGroups copies rooms or zones without their lights
+func cloneGroups(groups []*models.Room) []*models.Room {
+	result := make([]*models.Room, len(groups))
+	for i, g := range groups {
+		result[i] = &models.Room{
+			ID:             g.ID,
+			Name:           g.Name,
+			Archetype:      g.Archetype,
+			GroupedLightID: g.GroupedLightID,
+			DeviceIDs:      slices.Clone(g.DeviceIDs),
+			LightIDs:       slices.Clone(g.LightIDs),
+		}
+	}
+	return result
+}
+
+// CreateRoom adds a demo room, moving the given devices into it
+func (d *DemoBridge) CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextGroupID++
+	room := &models.Room{
+		ID:             fmt.Sprintf("room-custom-%d", d.nextGroupID),
+		Name:           name,
+		Archetype:      archetype,
+		GroupedLightID: fmt.Sprintf("group-custom-%d", d.nextGroupID),
+	}
+	d.rooms = append(d.rooms, room)
+	d.assignDevices(room, deviceIDs)
+	return room.ID, nil
+}
+
+// UpdateRoom renames a demo room and/or replaces its devices
+func (d *DemoBridge) UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	room := findGroup(d.rooms, roomID)
+	if room == nil {
+		return fmt.Errorf("room %s not found", roomID)
+	}
+	if name != "" {
+		room.Name = name
+	}
+	if deviceIDs != nil {
+		d.assignDevices(room, deviceIDs)
+	}
+	return nil
+}
+
+// DeleteRoom removes a demo room, leaving its lights without a room
+func (d *DemoBridge) DeleteRoom(ctx context.Context, roomID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if findGroup(d.rooms, roomID) == nil {
+		return fmt.Errorf("room %s not found", roomID)
+	}
+	d.rooms = slices.DeleteFunc(d.rooms, func(r *models.Room) bool { return r.ID == roomID })
+	for _, light := range d.lights {
+		if light.RoomID == roomID {
+			light.RoomID = ""
+		}
+	}
+	return nil
+}
+
+// assignDevices makes deviceIDs the devices of room. A device belongs to
+// a single room, so it leaves the room it was in.
+func (d *DemoBridge) assignDevices(room *models.Room, deviceIDs []string) {
+	for _, r := range d.rooms {
+		if r == room {
+			continue
+		}
+		r.DeviceIDs = slices.DeleteFunc(slices.Clone(r.DeviceIDs), func(id string) bool {
+			return slices.Contains(deviceIDs, id)
+		})
+		r.Lights = slices.DeleteFunc(slices.Clone(r.Lights), func(l *models.Light) bool {
+			return slices.Contains(deviceIDs, l.DeviceID)
+		})
+	}
+
+	// Lights staying in the room keep their order, new ones go last
+	lights := slices.DeleteFunc(slices.Clone(room.Lights), func(l *models.Light) bool {
+		if !slices.Contains(deviceIDs, l.DeviceID) {
+			l.RoomID = ""
+			return true
+		}
+		return false
+	})
+	for _, id := range deviceIDs {
+		for _, light := range d.lights {
+			if light.DeviceID == id && !slices.Contains(lights, light) {
+				lights = append(lights, light)
+			}
+		}
+	}
+	for _, light := range lights {
+		light.RoomID = room.ID
+	}
+	room.DeviceIDs = slices.Clone(deviceIDs)
+	room.Lights = lights
+	d.updateRoomStates()
+}
+
+// CreateZone adds a demo zone
+func (d *DemoBridge) CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextGroupID++
+	zone := &models.Room{
+		ID:        fmt.Sprintf("zone-custom-%d", d.nextGroupID),
+		Name:      name,
+		Archetype: archetype,
+		LightIDs:  slices.Clone(lightIDs),
+	}
+	d.zones = append(d.zones, zone)
+	return zone.ID, nil
+}
+
+// UpdateZone renames a demo zone and/or replaces its lights
+func (d *DemoBridge) UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	zone := findGroup(d.zones, zoneID)
+	if zone == nil {
+		return fmt.Errorf("zone %s not found", zoneID)
+	}
+	if name != "" {
+		zone.Name = name
+	}
+	if lightIDs != nil {
+		zone.LightIDs = slices.Clone(lightIDs)
+	}
+	return nil
+}
+
+// DeleteZone removes a demo zone
+func (d *DemoBridge) DeleteZone(ctx context.Context, zoneID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if findGroup(d.zones, zoneID) == nil {
+		return fmt.Errorf("zone %s not found", zoneID)
+	}
+	d.zones = slices.DeleteFunc(d.zones, func(z *models.Room) bool { return z.ID == zoneID })
+	return nil
+}
+
+func findGroup(groups []*models.Room, id string) *models.Room {
+	for _, g := range groups {
+		if g.ID == id {
+			return g
+		}
+	}
+	return nil
+}
+
 // updateRoomStates recalculates the state for all rooms
 func (d *DemoBridge) updateRoomStates() {
 	for _, room := range d.rooms {
@@ -253,6 +902,11 @@ var demoScenePresets = map[string]map[string]lightState{
 	},
 }
 
+// gamutOf returns a copy of a gamut for a demo light
+func gamutOf(g models.Gamut) *models.Gamut {
+	return &g
+}
+
 // initializeDemoData creates the demo rooms, lights, and scenes
 func (d *DemoBridge) initializeDemoData() {
 	// Living Room lights
@@ -283,6 +937,15 @@ func (d *DemoBridge) initializeDemoData() {
 			SupportsColor:     true,
 			SupportsColorTemp: false,
 			Color:             models.NewColorFromXY(0.15, 0.06, 101), // Blue
+			// Play gradient strip: blue to purple to pink
+			Gradient: &models.Gradient{
+				PointsCapable: 5,
+				Points: []*models.Color{
+					models.NewColorFromXY(0.15, 0.06, 254),
+					models.NewColorFromXY(0.25, 0.10, 254),
+					models.NewColorFromXY(0.42, 0.20, 254),
+				},
+			},
 		},
 		{
 			ID:                "light-lr-accent",
@@ -323,6 +986,7 @@ func (d *DemoBridge) initializeDemoData() {
 			SupportsColor:     false,
 			SupportsColorTemp: true,
 			Color:             models.NewColorFromMirek(326, 254),
+			Archetype:         "ceiling_round",
 		},
 	}
 
@@ -336,6 +1000,8 @@ func (d *DemoBridge) initializeDemoData() {
 			SupportsColor:     false,
 			SupportsColorTemp: true,
 			Color:             models.NewColorFromMirek(233, 254), // Cool white
+			MirekMin:          153,
+			MirekMax:          454,
 		},
 		{
 			ID:                "light-kt-cabinet",
@@ -345,6 +1011,8 @@ func (d *DemoBridge) initializeDemoData() {
 			SupportsColor:     false,
 			SupportsColorTemp: true,
 			Color:             models.NewColorFromMirek(250, 178),
+			MirekMin:          153,
+			MirekMax:          454,
 		},
 	}
 
@@ -358,6 +1026,7 @@ func (d *DemoBridge) initializeDemoData() {
 			SupportsColor:     true,
 			SupportsColorTemp: true,
 			Color:             models.NewColorFromMirek(300, 229), // Neutral
+			Archetype:         "table_shade",
 		},
 		{
 			ID:                "light-of-monitor",
@@ -376,6 +1045,16 @@ func (d *DemoBridge) initializeDemoData() {
 			SupportsColor:     true,
 			SupportsColorTemp: false,
 			Color:             models.NewColorFromXY(0.32, 0.15, 101), // Purple
+			// First generation bulb, with the narrower gamut
+			Gamut: gamutOf(models.GamutB),
+		},
+		{
+			// Smart plug powering a lamp, on/off only
+			ID:        "light-of-plug",
+			Name:      "Salt Lamp",
+			On:        true,
+			OnOffOnly: true,
+			Archetype: "plug",
 		},
 	}
 
@@ -407,21 +1086,37 @@ func (d *DemoBridge) initializeDemoData() {
 		},
 	}
 
-	// Build light lookup map and set room IDs
+	// Build light lookup map and set room IDs. Each light is its own
+	// device, as with most bulbs.
 	for _, room := range d.rooms {
 		for _, light := range room.Lights {
 			light.RoomID = room.ID
+			light.DeviceID = "device-" + strings.TrimPrefix(light.ID, "light-")
+			light.Reachable = true
+			if light.SupportsColor && light.Gamut == nil {
+				light.Gamut = gamutOf(models.GamutC)
+			}
+			room.DeviceIDs = append(room.DeviceIDs, light.DeviceID)
 			d.lights[light.ID] = light
 		}
 		room.UpdateState()
 	}
 
+	// Create zones
+	d.zones = []*models.Room{
+		{
+			ID:       "zone-downstairs",
+			Name:     "Downstairs",
+			LightIDs: []string{"light-lr-ceiling", "light-lr-floor", "light-kt-main"},
+		},
+	}
+
 	// Create scenes
 	d.scenes = []*models.Scene{
 		// Living Room scenes
 		{ID: "scene-movie-night", Name: "Movie Night", RoomID: "room-living", RoomName: "Living Room"},
 		{ID: "scene-energize", Name: "Energize", RoomID: "room-living", RoomName: "Living Room"},
-		{ID: "scene-relax", Name: "Relax", RoomID: "room-living", RoomName: "Living Room"},
+		{ID: "scene-relax", Name: "Relax", RoomID: "room-living", RoomName: "Living Room", IsDynamic: true},
 		// Bedroom scenes
 		{ID: "scene-sleep", Name: "Sleep", RoomID: "room-bedroom", RoomName: "Bedroom"},
 		{ID: "scene-reading", Name: "Reading", RoomID: "room-bedroom", RoomName: "Bedroom"},
@@ -431,6 +1126,57 @@ func (d *DemoBridge) initializeDemoData() {
 		// Office scenes
 		{ID: "scene-focus", Name: "Focus", RoomID: "room-office", RoomName: "Office"},
 	}
+	for _, scene := range d.scenes {
+		scene.Actions = d.sceneActions(scene)
+	}
+	// Some scenes are used every day, some were long forgotten
+	lastUsed := map[string]int{
+		"scene-movie-night": 3, "scene-energize": 200, "scene-relax": 10, "scene-sleep": 1,
+		"scene-reading": 120, "scene-cooking": 2, "scene-focus": 400,
+	}
+	for _, scene := range d.scenes {
+		if days, ok := lastUsed[scene.ID]; ok {
+			scene.LastRecalled = time.Now().AddDate(0, 0, -days)
+		}
+	}
+
+	// Create schedules
+	d.schedules = []*models.Schedule{
+		{
+			ID: "schedule-wake", Resource: "behavior_instance", Name: "Weekday wake up",
+			Kind: models.ScheduleWakeUp, Enabled: true, Hour: 7, Minute: 0, HasTime: true,
+			Days: models.Weekdays, Fade: 30 * time.Minute, GroupID: "room-bedroom",
+		},
+		{
+			ID: "schedule-natural", Resource: "smart_scene", Name: "Natural light",
+			Kind: models.ScheduleSmartScene, Enabled: false, GroupID: "room-living",
+		},
+	}
+
+	// Create entertainment areas
+	d.areas = []*models.EntertainmentArea{
+		{
+			ID:     "ent-tv",
+			Name:   "TV Area",
+			Type:   "screen",
+			Status: "inactive",
+			Channels: []models.EntertainmentChannel{
+				{ID: 0, X: -0.8, Y: 0.8, Z: 0, LightIDs: []string{"light-lr-floor"}},
+				{ID: 1, X: 0, Y: 1, Z: 0, LightIDs: []string{"light-lr-tv-bias"}},
+				{ID: 2, X: 0.8, Y: 0.8, Z: 0, LightIDs: []string{"light-lr-accent"}},
+				{ID: 3, X: 0, Y: 0, Z: 1, LightIDs: []string{"light-lr-ceiling"}},
+			},
+			LightIDs: []string{"light-lr-floor", "light-lr-tv-bias", "light-lr-accent", "light-lr-ceiling"},
+		},
+	}
+
+	// Battery powered accessories, one of them running low
+	d.devices = []*models.Device{
+		{ID: "device-bedroom-dimmer", Name: "Bedroom Dimmer", Product: "Hue dimmer switch", Kind: models.DeviceSwitch, BatteryLevel: 12, BatteryState: "low"},
+		{ID: "device-hallway-sensor", Name: "Hallway Sensor", Product: "Hue motion sensor", Kind: models.DeviceMotionSensor, BatteryLevel: 64, BatteryState: "normal"},
+		{ID: "device-kitchen-button", Name: "Kitchen Button", Product: "Hue Smart button", Kind: models.DeviceButton, BatteryLevel: 91, BatteryState: "normal"},
+		{ID: "device-living-dial", Name: "Living Room Dial", Product: "Hue tap dial switch", Kind: models.DeviceSwitch, BatteryLevel: 100, BatteryState: "normal"},
+	}
 }
 
 // Compile-time check that DemoBridge implements BridgeClient
@@ -30,3 +30,139 @@ func TestDemoBridgeData(t *testing.T) {
 		t.Error("No scenes returned")
 	}
 }
+
+func TestDemoCreateSceneFromCapture(t *testing.T) {
+	d := NewDemoBridge()
+	ctx := context.Background()
+	rooms, _, err := d.FetchAll(ctx)
+	if err != nil {
+		t.Fatalf("FetchAll returned error: %v", err)
+	}
+	room := rooms[0]
+
+	actions := CaptureSceneActions(room.Lights)
+	if len(actions) != len(room.Lights) {
+		t.Fatalf("Expected one action per light, got %d", len(actions))
+	}
+	for i, a := range actions {
+		light := room.Lights[i]
+		if a.On == nil || *a.On != light.On {
+			t.Errorf("Expected %s on=%v", light.Name, light.On)
+		}
+		if !light.On && (a.Brightness != nil || a.Mirek != nil || a.XY != nil) {
+			t.Errorf("Expected only on/off for %s, got %+v", light.Name, a)
+		}
+	}
+
+	id, err := d.CreateScene(ctx, "Dinner", room.ID, "room", actions)
+	if err != nil {
+		t.Fatalf("CreateScene returned error: %v", err)
+	}
+
+	// Recalling the scene restores the captured state
+	onBefore := room.Lights[0].On
+	if err := d.SetLightOn(ctx, room.Lights[0].ID, !onBefore); err != nil {
+		t.Fatalf("SetLightOn returned error: %v", err)
+	}
+	if err := d.ActivateScene(ctx, id); err != nil {
+		t.Fatalf("ActivateScene returned error: %v", err)
+	}
+	rooms, scenes, _ := d.FetchAll(ctx)
+	if rooms[0].Lights[0].On != onBefore {
+		t.Error("Expected the scene to restore the captured state")
+	}
+	found := false
+	for _, s := range scenes {
+		if s.ID == id && s.Name == "Dinner" && s.RoomID == room.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the new scene in the scene list")
+	}
+
+	if _, err := d.CreateScene(ctx, "Dinner", "missing", "room", actions); err == nil {
+		t.Error("Expected an error for an unknown room")
+	}
+}
+
+func TestDemoManageRooms(t *testing.T) {
+	d := NewDemoBridge()
+	ctx := context.Background()
+
+	roomOf := func(lightID string) string {
+		rooms, _, err := d.FetchAll(ctx)
+		if err != nil {
+			t.Fatalf("FetchAll returned error: %v", err)
+		}
+		for _, room := range rooms {
+			if room.LightByID(lightID) != nil {
+				return room.Name
+			}
+		}
+		return ""
+	}
+
+	// A device moves out of the room it was in
+	id, err := d.CreateRoom(ctx, "Reading Nook", "reading", []string{"device-lr-floor"})
+	if err != nil {
+		t.Fatalf("CreateRoom returned error: %v", err)
+	}
+	if got := roomOf("light-lr-floor"); got != "Reading Nook" {
+		t.Errorf("Expected the floor lamp in the new room, got %q", got)
+	}
+	if got := roomOf("light-lr-ceiling"); got != "Living Room" {
+		t.Errorf("Expected the ceiling light to stay in the living room, got %q", got)
+	}
+
+	if err := d.UpdateRoom(ctx, id, "Nook", nil); err != nil {
+		t.Fatalf("UpdateRoom returned error: %v", err)
+	}
+	if got := roomOf("light-lr-floor"); got != "Nook" {
+		t.Errorf("Expected the room to be renamed, got %q", got)
+	}
+
+	// Lights of a deleted room have no room
+	if err := d.DeleteRoom(ctx, id); err != nil {
+		t.Fatalf("DeleteRoom returned error: %v", err)
+	}
+	if got := roomOf("light-lr-floor"); got != "Other Lights" {
+		t.Errorf("Expected the floor lamp without a room, got %q", got)
+	}
+	rooms, err := d.GetRooms(ctx)
+	if err != nil {
+		t.Fatalf("GetRooms returned error: %v", err)
+	}
+	for _, room := range rooms {
+		if room.ID == id {
+			t.Error("Expected the room to be deleted")
+		}
+	}
+
+	zoneID, err := d.CreateZone(ctx, "Lamps", "", []string{"light-lr-floor", "light-br-left"})
+	if err != nil {
+		t.Fatalf("CreateZone returned error: %v", err)
+	}
+	if err := d.UpdateZone(ctx, zoneID, "", []string{"light-br-left"}); err != nil {
+		t.Fatalf("UpdateZone returned error: %v", err)
+	}
+	zones, _ := d.GetZones(ctx)
+	found := false
+	for _, zone := range zones {
+		if zone.ID == zoneID {
+			found = true
+			if zone.Name != "Lamps" || len(zone.LightIDs) != 1 {
+				t.Errorf("Unexpected zone %+v", zone)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected the new zone")
+	}
+	if err := d.DeleteZone(ctx, zoneID); err != nil {
+		t.Fatalf("DeleteZone returned error: %v", err)
+	}
+	if err := d.DeleteZone(ctx, zoneID); err == nil {
+		t.Error("Expected an error for a deleted zone")
+	}
+}
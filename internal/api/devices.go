@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// deviceResource is a physical device and the services it provides
+type deviceResource struct {
+	ID       string `json:"id"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	ProductData struct {
+		ProductName string `json:"product_name"`
+	} `json:"product_data"`
+	Services []resourceRef `json:"services"`
+}
+
+// devicePowerResource represents the V2 API device_power resource, the
+// battery of a device
+type devicePowerResource struct {
+	ID         string      `json:"id"`
+	Owner      resourceRef `json:"owner"`
+	PowerState struct {
+		BatteryState string `json:"battery_state"`
+		BatteryLevel *int   `json:"battery_level"`
+	} `json:"power_state"`
+}
+
+// kind tells switches, motion sensors and buttons apart by their services
+func (r *deviceResource) kind() models.DeviceKind {
+	buttons := 0
+	for _, svc := range r.Services {
+		switch svc.Rtype {
+		case "motion", "camera_motion":
+			return models.DeviceMotionSensor
+		case "relative_rotary":
+			return models.DeviceSwitch
+		case "button":
+			buttons++
+		}
+	}
+	switch {
+	case buttons > 1:
+		return models.DeviceSwitch
+	case buttons == 1:
+		return models.DeviceButton
+	}
+	return models.DeviceOther
+}
+
+// batteryDevices pairs battery states with their devices. Devices without a
+// battery are left out.
+func batteryDevices(devices []deviceResource, powers []devicePowerResource) []*models.Device {
+	byID := make(map[string]*deviceResource, len(devices))
+	for i := range devices {
+		byID[devices[i].ID] = &devices[i]
+	}
+
+	var result []*models.Device
+	for _, power := range powers {
+		res, ok := byID[power.Owner.Rid]
+		if !ok || power.PowerState.BatteryLevel == nil {
+			continue
+		}
+		result = append(result, &models.Device{
+			ID:           res.ID,
+			Name:         res.Metadata.Name,
+			Product:      res.ProductData.ProductName,
+			Kind:         res.kind(),
+			BatteryLevel: *power.PowerState.BatteryLevel,
+			BatteryState: power.PowerState.BatteryState,
+		})
+	}
+	sortDevices(result)
+	return result
+}
+
+// sortDevices sorts devices by name
+func sortDevices(devices []*models.Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		return strings.ToLower(devices[i].Name) < strings.ToLower(devices[j].Name)
+	})
+}
+
+// GetBatteryDevices retrieves the battery powered devices, like switches
+// and motion sensors, with their battery level
+func (b *HueBridge) GetBatteryDevices(ctx context.Context) ([]*models.Device, error) {
+	var devices []deviceResource
+	if err := b.listResources(ctx, "device", &devices); err != nil {
+		return nil, err
+	}
+	var powers []devicePowerResource
+	if err := b.listResources(ctx, "device_power", &powers); err != nil {
+		return nil, err
+	}
+	return batteryDevices(devices, powers), nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func TestBatteryDevices(t *testing.T) {
+	devicesJSON := `[
+		{"id": "dev-motion", "metadata": {"name": "Hallway"}, "product_data": {"product_name": "Hue motion sensor"},
+		 "services": [{"rid": "m1", "rtype": "motion"}, {"rid": "p1", "rtype": "device_power"}]},
+		{"id": "dev-dimmer", "metadata": {"name": "bedroom dimmer"}, "product_data": {"product_name": "Hue dimmer switch"},
+		 "services": [{"rid": "b1", "rtype": "button"}, {"rid": "b2", "rtype": "button"}, {"rid": "b3", "rtype": "button"}, {"rid": "b4", "rtype": "button"}]},
+		{"id": "dev-button", "metadata": {"name": "Smart button"}, "services": [{"rid": "b5", "rtype": "button"}]},
+		{"id": "dev-bulb", "metadata": {"name": "Ceiling"}, "services": [{"rid": "l1", "rtype": "light"}]}
+	]`
+	powersJSON := `[
+		{"id": "p1", "owner": {"rid": "dev-motion", "rtype": "device"}, "power_state": {"battery_state": "normal", "battery_level": 80}},
+		{"id": "p2", "owner": {"rid": "dev-dimmer", "rtype": "device"}, "power_state": {"battery_state": "low", "battery_level": 9}},
+		{"id": "p3", "owner": {"rid": "dev-button", "rtype": "device"}, "power_state": {"battery_state": "normal", "battery_level": 55}},
+		{"id": "p4", "owner": {"rid": "dev-bulb", "rtype": "device"}, "power_state": {}}
+	]`
+
+	var devices []deviceResource
+	if err := json.Unmarshal([]byte(devicesJSON), &devices); err != nil {
+		t.Fatalf("Failed to unmarshal devices: %v", err)
+	}
+	var powers []devicePowerResource
+	if err := json.Unmarshal([]byte(powersJSON), &powers); err != nil {
+		t.Fatalf("Failed to unmarshal powers: %v", err)
+	}
+
+	got := batteryDevices(devices, powers)
+	if len(got) != 3 {
+		t.Fatalf("Expected the 3 battery devices, got %d", len(got))
+	}
+
+	want := []struct {
+		name  string
+		kind  models.DeviceKind
+		level int
+		low   bool
+	}{
+		{"bedroom dimmer", models.DeviceSwitch, 9, true},
+		{"Hallway", models.DeviceMotionSensor, 80, false},
+		{"Smart button", models.DeviceButton, 55, false},
+	}
+	for i, w := range want {
+		d := got[i]
+		if d.Name != w.name || d.Kind != w.kind || d.BatteryLevel != w.level || d.LowBattery() != w.low {
+			t.Errorf("Device %d = %+v, want %+v", i, d, w)
+		}
+	}
+	if got[1].Product != "Hue motion sensor" {
+		t.Errorf("Expected the product name, got %q", got[1].Product)
+	}
+}
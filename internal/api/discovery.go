@@ -14,7 +14,7 @@ import (
 
 // DiscoveredBridge represents a Hue bridge found during discovery
 type DiscoveredBridge struct {
-	// IP address of the bridge
+	// IP address or hostname of the bridge
 	Host string
 	// Unique bridge identifier
 	BridgeID string
@@ -36,9 +36,12 @@ func DiscoverMDNS(ctx context.Context, timeout time.Duration) ([]DiscoveredBridg
 	go func() {
 		for entry := range entriesCh {
 			bridge := DiscoveredBridge{
-				Host: entry.AddrV4.String(),
+				Host: entryHost(entry),
 				Name: entry.Name,
 			}
+			if bridge.Host == "" {
+				continue
+			}
 
 			// Parse bridge ID from TXT records
 			for _, txt := range entry.InfoFields {
@@ -65,7 +68,6 @@ func DiscoverMDNS(ctx context.Context, timeout time.Duration) ([]DiscoveredBridg
 	params := mdns.DefaultParams("_hue._tcp")
 	params.Entries = entriesCh
 	params.Timeout = timeout
-	params.DisableIPv6 = true
 
 	// Run the query
 	err := mdns.Query(params)
@@ -78,6 +80,20 @@ func DiscoverMDNS(ctx context.Context, timeout time.Duration) ([]DiscoveredBridg
 	return bridges, nil
 }
 
+// entryHost returns the address to reach a bridge found by mDNS: its IPv4
+// address, else its IPv6 address, else its hostname
+func entryHost(entry *mdns.ServiceEntry) string {
+	switch {
+	case entry.AddrV4 != nil:
+		return entry.AddrV4.String()
+	case entry.AddrV6IPAddr != nil:
+		return entry.AddrV6IPAddr.String()
+	case entry.AddrV6 != nil:
+		return entry.AddrV6.String()
+	}
+	return strings.TrimSuffix(entry.Host, ".")
+}
+
 // nupnpResponse represents the response from Hue cloud discovery
 type nupnpResponse struct {
 	ID                string `json:"id"`
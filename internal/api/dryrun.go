@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DryRunBridge prints the commands sent to a bridge instead of sending
+// them, one per line with their arguments. Reads still go to the bridge,
+// so plans are computed against its real state.
+type DryRunBridge struct {
+	BridgeClient
+	w       io.Writer
+	created atomic.Int64
+}
+
+// Compile-time check that DryRunBridge implements BridgeClient
+var _ BridgeClient = (*DryRunBridge)(nil)
+
+// NewDryRunBridge wraps a bridge so that its commands are printed to w
+func NewDryRunBridge(bridge BridgeClient, w io.Writer) *DryRunBridge {
+	return &DryRunBridge{BridgeClient: bridge, w: w}
+}
+
+// print prints a command instead of sending it
+func (b *DryRunBridge) print(command string, args ...interface{}) error {
+	parts := []string{"[dry-run]", command}
+	for _, arg := range args {
+		if d, ok := arg.(time.Duration); ok {
+			parts = append(parts, d.String())
+			continue
+		}
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", command, err)
+		}
+		parts = append(parts, string(data))
+	}
+	line := strings.Join(parts, " ")
+	if _, err := fmt.Fprintln(b.w, line); err != nil {
+		return err
+	}
+	apiLog.Debugf("Dry run: %s", line)
+	return nil
+}
+
+// create prints a command creating a resource, and returns a made-up ID
+// for it so later commands can refer to it
+func (b *DryRunBridge) create(command string, args ...interface{}) (string, error) {
+	if err := b.print(command, args...); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("dry-run-%d", b.created.Add(1)), nil
+}
+
+func (b *DryRunBridge) SetLightOn(ctx context.Context, lightID string, on bool) error {
+	return b.print("SetLightOn", lightID, on)
+}
+
+func (b *DryRunBridge) SetLightBrightness(ctx context.Context, lightID string, brightness int) error {
+	return b.print("SetLightBrightness", lightID, brightness)
+}
+
+func (b *DryRunBridge) SetLightColorTemp(ctx context.Context, lightID string, mirek int) error {
+	return b.print("SetLightColorTemp", lightID, mirek)
+}
+
+func (b *DryRunBridge) SetLightColorXY(ctx context.Context, lightID string, x, y float64) error {
+	return b.print("SetLightColorXY", lightID, x, y)
+}
+
+func (b *DryRunBridge) SetLightColorHS(ctx context.Context, lightID string, hue uint16, sat uint8) error {
+	return b.print("SetLightColorHS", lightID, hue, sat)
+}
+
+func (b *DryRunBridge) SetLightGradient(ctx context.Context, lightID string, points [][2]float64) error {
+	return b.print("SetLightGradient", lightID, points)
+}
+
+func (b *DryRunBridge) IdentifyLight(ctx context.Context, lightID string) error {
+	return b.print("IdentifyLight", lightID)
+}
+
+func (b *DryRunBridge) SetLightArchetype(ctx context.Context, lightID, archetype string) error {
+	return b.print("SetLightArchetype", lightID, archetype)
+}
+
+func (b *DryRunBridge) RenameLight(ctx context.Context, lightID, name string) error {
+	return b.print("RenameLight", lightID, name)
+}
+
+// SetLightState prints a light state change of a plan
+func (b *DryRunBridge) SetLightState(ctx context.Context, action SceneAction) error {
+	return b.print("SetLightState", action)
+}
+
+func (b *DryRunBridge) SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) error {
+	return b.print("SetGroupedLightOn", groupedLightID, on)
+}
+
+func (b *DryRunBridge) FadeGroupedLightOn(ctx context.Context, groupedLightID string, on bool, duration time.Duration) error {
+	return b.print("FadeGroupedLightOn", groupedLightID, on, duration)
+}
+
+func (b *DryRunBridge) SetGroupedLightBrightness(ctx context.Context, groupedLightID string, brightness int) error {
+	return b.print("SetGroupedLightBrightness", groupedLightID, brightness)
+}
+
+// SetGroupedLightState prints a group state change of a plan
+func (b *DryRunBridge) SetGroupedLightState(ctx context.Context, groupedLightID string, action SceneAction) error {
+	return b.print("SetGroupedLightState", groupedLightID, action)
+}
+
+func (b *DryRunBridge) ActivateScene(ctx context.Context, sceneID string) error {
+	return b.print("ActivateScene", sceneID)
+}
+
+func (b *DryRunBridge) ActivateSceneOver(ctx context.Context, sceneID string, duration time.Duration) error {
+	return b.print("ActivateSceneOver", sceneID, duration)
+}
+
+func (b *DryRunBridge) StopSceneDynamics(ctx context.Context, sceneID string) error {
+	return b.print("StopSceneDynamics", sceneID)
+}
+
+func (b *DryRunBridge) SetSmartSceneActive(ctx context.Context, sceneID string, active bool) error {
+	return b.print("SetSmartSceneActive", sceneID, active)
+}
+
+func (b *DryRunBridge) CreateScene(ctx context.Context, name, groupID, groupType string, actions []SceneAction) (string, error) {
+	return b.create("CreateScene", name, groupID, groupType, actions)
+}
+
+func (b *DryRunBridge) DeleteScene(ctx context.Context, sceneID string) error {
+	return b.print("DeleteScene", sceneID)
+}
+
+func (b *DryRunBridge) CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error) {
+	return b.create("CreateRoom", name, archetype, deviceIDs)
+}
+
+func (b *DryRunBridge) UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error {
+	return b.print("UpdateRoom", roomID, name, deviceIDs)
+}
+
+func (b *DryRunBridge) DeleteRoom(ctx context.Context, roomID string) error {
+	return b.print("DeleteRoom", roomID)
+}
+
+func (b *DryRunBridge) CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error) {
+	return b.create("CreateZone", name, archetype, lightIDs)
+}
+
+func (b *DryRunBridge) UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error {
+	return b.print("UpdateZone", zoneID, name, lightIDs)
+}
+
+func (b *DryRunBridge) DeleteZone(ctx context.Context, zoneID string) error {
+	return b.print("DeleteZone", zoneID)
+}
+
+func (b *DryRunBridge) SetEntertainmentActive(ctx context.Context, areaID string, active bool) error {
+	return b.print("SetEntertainmentActive", areaID, active)
+}
+
+func (b *DryRunBridge) CreateSchedule(ctx context.Context, spec ScheduleSpec) (string, error) {
+	return b.create("CreateSchedule", spec)
+}
+
+func (b *DryRunBridge) DeleteSchedule(ctx context.Context, resource, id string) error {
+	return b.print("DeleteSchedule", resource, id)
+}
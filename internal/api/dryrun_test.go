@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var sent []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(`{"data": [], "errors": []}`))
+	}))
+	defer server.Close()
+	hue := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+	var out strings.Builder
+	b := NewDryRunBridge(hue, &out)
+
+	if err := b.SetLightOn(context.Background(), "light-1", true); err != nil {
+		t.Fatalf("SetLightOn failed: %v", err)
+	}
+	id, err := b.CreateZone(context.Background(), "Desk", "office", []string{"light-1"})
+	if err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+	if id != "dry-run-1" {
+		t.Errorf("Created zone ID = %q, want dry-run-1", id)
+	}
+	if _, err := b.GetScenes(context.Background()); err != nil {
+		t.Fatalf("GetScenes failed: %v", err)
+	}
+
+	// Only the reads reach the bridge
+	if len(sent) == 0 || slices.ContainsFunc(sent, func(s string) bool { return !strings.HasPrefix(s, "GET ") }) {
+		t.Errorf("Sent %q, want only the reads", sent)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Printed %q, want 2 commands", out.String())
+	}
+	if want := `[dry-run] SetLightOn "light-1" true`; lines[0] != want {
+		t.Errorf("Printed %q, want %q", lines[0], want)
+	}
+	if want := `[dry-run] CreateZone "Desk" "office" ["light-1"]`; lines[1] != want {
+		t.Errorf("Printed %q, want %q", lines[1], want)
+	}
+}
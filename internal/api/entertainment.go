@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// entertainmentConfigurationResource represents the V2 API entertainment_configuration resource
+type entertainmentConfigurationResource struct {
+	ID       string `json:"id"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	ConfigurationType string `json:"configuration_type"`
+	Status            string `json:"status"`
+	Channels          []struct {
+		ChannelID int `json:"channel_id"`
+		Position  struct {
+			X float64 `json:"x"`
+			Y float64 `json:"y"`
+			Z float64 `json:"z"`
+		} `json:"position"`
+		Members []struct {
+			Service struct {
+				Rid   string `json:"rid"`
+				Rtype string `json:"rtype"`
+			} `json:"service"`
+			Index int `json:"index"`
+		} `json:"members"`
+	} `json:"channels"`
+	LightServices []struct {
+		Rid   string `json:"rid"`
+		Rtype string `json:"rtype"`
+	} `json:"light_services"`
+}
+
+// toModel converts the resource, resolving channel members (entertainment
+// services) to light IDs through serviceToLight
+func (r *entertainmentConfigurationResource) toModel(serviceToLight map[string]string) *models.EntertainmentArea {
+	area := &models.EntertainmentArea{
+		ID:     r.ID,
+		Name:   r.Metadata.Name,
+		Type:   r.ConfigurationType,
+		Status: r.Status,
+	}
+
+	for _, svc := range r.LightServices {
+		if svc.Rtype == "light" {
+			area.LightIDs = append(area.LightIDs, svc.Rid)
+		}
+	}
+
+	for _, ch := range r.Channels {
+		channel := models.EntertainmentChannel{
+			ID: ch.ChannelID,
+			X:  ch.Position.X,
+			Y:  ch.Position.Y,
+			Z:  ch.Position.Z,
+		}
+		for _, member := range ch.Members {
+			if lightID, ok := serviceToLight[member.Service.Rid]; ok {
+				channel.LightIDs = append(channel.LightIDs, lightID)
+			}
+		}
+		area.Channels = append(area.Channels, channel)
+	}
+
+	return area
+}
+
+// GetEntertainmentAreas retrieves all entertainment configurations
+func (b *HueBridge) GetEntertainmentAreas(ctx context.Context) (areas []*models.EntertainmentArea, err error) {
+	// Entertainment services map channel members to the lights rendering them
+	serviceToLight, err := b.entertainmentServiceLights(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/entertainment_configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entertainment areas: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode entertainment areas response: %w", err)
+	}
+
+	if len(apiResp.Errors) > 0 {
+		return nil, fmt.Errorf("API error: %s", apiResp.Errors[0].Description)
+	}
+
+	var rawAreas []entertainmentConfigurationResource
+	if err := json.Unmarshal(apiResp.Data, &rawAreas); err != nil {
+		return nil, fmt.Errorf("failed to parse entertainment areas: %w", err)
+	}
+
+	result := make([]*models.EntertainmentArea, len(rawAreas))
+	for i, raw := range rawAreas {
+		result[i] = raw.toModel(serviceToLight)
+	}
+
+	return result, nil
+}
+
+// entertainmentServiceLights maps entertainment service IDs to light IDs
+func (b *HueBridge) entertainmentServiceLights(ctx context.Context) (mapping map[string]string, err error) {
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/entertainment", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entertainment services: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode entertainment services response: %w", err)
+	}
+
+	var services []struct {
+		ID                string `json:"id"`
+		RendererReference *struct {
+			Rid   string `json:"rid"`
+			Rtype string `json:"rtype"`
+		} `json:"renderer_reference"`
+	}
+	if err := json.Unmarshal(apiResp.Data, &services); err != nil {
+		return nil, fmt.Errorf("failed to parse entertainment services: %w", err)
+	}
+
+	mapping = make(map[string]string)
+	for _, svc := range services {
+		if svc.RendererReference != nil && svc.RendererReference.Rtype == "light" {
+			mapping[svc.ID] = svc.RendererReference.Rid
+		}
+	}
+	return mapping, nil
+}
+
+// SetEntertainmentActive starts or stops an entertainment session.
+// Starting without a DTLS stream makes the bridge reserve the lights for
+// streaming until the session is stopped or times out.
+func (b *HueBridge) SetEntertainmentActive(ctx context.Context, areaID string, active bool) (err error) {
+	action := "stop"
+	if active {
+		action = "start"
+	}
+	body := fmt.Sprintf(`{"action":"%s"}`, action)
+	path := fmt.Sprintf("/clip/v2/resource/entertainment_configuration/%s", areaID)
+	resp, err := b.doRequest(ctx, "PUT", path, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to %s entertainment session: %w", action, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEntertainmentConfigurationToModel(t *testing.T) {
+	data := `{
+		"id": "ent-1",
+		"metadata": {"name": "TV Area"},
+		"configuration_type": "screen",
+		"status": "active",
+		"channels": [
+			{
+				"channel_id": 0,
+				"position": {"x": -0.5, "y": 0.8, "z": 0},
+				"members": [{"service": {"rid": "svc-a", "rtype": "entertainment"}, "index": 0}]
+			},
+			{
+				"channel_id": 1,
+				"position": {"x": 0.5, "y": 0.8, "z": 0},
+				"members": [{"service": {"rid": "svc-unknown", "rtype": "entertainment"}, "index": 0}]
+			}
+		],
+		"light_services": [{"rid": "light-a", "rtype": "light"}]
+	}`
+
+	var raw entertainmentConfigurationResource
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	area := raw.toModel(map[string]string{"svc-a": "light-a"})
+
+	if area.Name != "TV Area" || area.Type != "screen" {
+		t.Errorf("Unexpected area metadata: %+v", area)
+	}
+	if !area.IsActive() {
+		t.Error("Expected area to be active")
+	}
+	if len(area.Channels) != 2 {
+		t.Fatalf("Expected 2 channels, got %d", len(area.Channels))
+	}
+	if got := area.Channels[0].LightIDs; len(got) != 1 || got[0] != "light-a" {
+		t.Errorf("Expected channel 0 to map to light-a, got %v", got)
+	}
+	if got := area.Channels[1].LightIDs; len(got) != 0 {
+		t.Errorf("Expected unknown service to be skipped, got %v", got)
+	}
+	if area.Channels[0].X != -0.5 || area.Channels[0].Y != 0.8 {
+		t.Errorf("Unexpected channel position: %+v", area.Channels[0])
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventLogEntry is one raw SSE message from an event log
+type EventLogEntry struct {
+	// Time since the recording started
+	Offset time.Duration
+	// Raw "data:" payload, as sent by the bridge
+	Data json.RawMessage
+}
+
+// eventLogLine is the on-disk format of an entry: one JSON object per line
+type eventLogLine struct {
+	OffsetMs int64           `json:"t_ms"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// EventRecorder writes the raw SSE messages of a subscription to an event
+// log, which can later be replayed with NewEventReplay
+type EventRecorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+// NewEventRecorder creates a recorder writing to w
+func NewEventRecorder(w io.Writer) *EventRecorder {
+	return &EventRecorder{w: w, start: time.Now()}
+}
+
+// Record appends a raw SSE payload to the log
+func (r *EventRecorder) Record(payload []byte) error {
+	if !json.Valid(payload) {
+		return fmt.Errorf("invalid event payload")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	line, err := json.Marshal(eventLogLine{
+		OffsetMs: time.Since(r.start).Milliseconds(),
+		Data:     payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return nil
+}
+
+// ReadEventLog reads an event log written by an EventRecorder
+func ReadEventLog(r io.Reader) ([]EventLogEntry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var entries []EventLogEntry
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line eventLogLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("failed to parse event log line %d: %w", n, err)
+		}
+		entries = append(entries, EventLogEntry{
+			Offset: time.Duration(line.OffsetMs) * time.Millisecond,
+			Data:   line.Data,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return entries, nil
+}
+
+// NewEventReplay creates a subscription that replays recorded messages at
+// their original pace instead of connecting to a bridge. Events go through
+// the same parsing and batching as a live stream.
+func NewEventReplay(entries []EventLogEntry, handler EventHandler) *EventSubscription {
+	s := NewEventSubscription(nil, handler)
+	// A non-nil slice marks the subscription as a replay, even when empty
+	s.replay = append([]EventLogEntry{}, entries...)
+	return s
+}
+
+// replayLoop delivers the recorded messages, then stops
+func (s *EventSubscription) replayLoop(ctx context.Context) {
+	start := time.Now()
+	for _, entry := range s.replay {
+		if wait := time.Until(start.Add(entry.Offset)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			}
+		}
+
+		eventsLog.Debugf("Replay: message at %s (%d bytes)", entry.Offset, len(entry.Data))
+		if events := s.parseMessage(entry.Data); len(events) > 0 {
+			s.batchEvents(events)
+		}
+	}
+	eventsLog.Debugf("Replay: done (%d messages)", len(s.replay))
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventLogRoundTrip(t *testing.T) {
+	messages := []string{
+		`[{"type":"update","data":[{"id":"light-1","type":"light","on":{"on":true}}]}]`,
+		`[{"type":"update","data":[{"id":"light-1","type":"light","dimming":{"brightness":40}}]}]`,
+	}
+
+	var buf bytes.Buffer
+	recorder := NewEventRecorder(&buf)
+	for _, msg := range messages {
+		if err := recorder.Record([]byte(msg)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if err := recorder.Record([]byte("not json")); err == nil {
+		t.Error("Expected invalid payloads to be rejected")
+	}
+
+	entries, err := ReadEventLog(&buf)
+	if err != nil {
+		t.Fatalf("ReadEventLog failed: %v", err)
+	}
+	if len(entries) != len(messages) {
+		t.Fatalf("Expected %d entries, got %d", len(messages), len(entries))
+	}
+	for i, entry := range entries {
+		if string(entry.Data) != messages[i] {
+			t.Errorf("Entry %d: expected %s, got %s", i, messages[i], entry.Data)
+		}
+		if i > 0 && entry.Offset < entries[i-1].Offset {
+			t.Errorf("Entry %d: offsets should not go backwards", i)
+		}
+	}
+}
+
+func TestReadEventLogInvalidLine(t *testing.T) {
+	_, err := ReadEventLog(strings.NewReader("{\"t_ms\":0,\"data\":[]}\n\ngarbage\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected an error on line 3, got %v", err)
+	}
+}
+
+func TestEventReplay(t *testing.T) {
+	entries := []EventLogEntry{
+		{Offset: 0, Data: []byte(`[{"type":"update","data":[{"id":"light-1","type":"light","on":{"on":false}}]}]`)},
+		{Offset: 10 * time.Millisecond, Data: []byte(`[{"type":"delete","data":[{"id":"scene-1","type":"scene"}]}]`)},
+	}
+
+	received := make(chan []Event, 10)
+	replay := NewEventReplay(entries, func(events []Event) {
+		received <- events
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := replay.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = replay.Stop() }()
+
+	var events []Event
+	for len(events) < 2 {
+		select {
+		case batch := <-received:
+			events = append(events, batch...)
+		case <-ctx.Done():
+			t.Fatalf("Timed out after %d events", len(events))
+		}
+	}
+	if events[0].ResourceID != "light-1" || events[1].Type != EventTypeDelete {
+		t.Errorf("Unexpected replayed events: %+v", events)
+	}
+}
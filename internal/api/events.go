@@ -3,36 +3,21 @@ package api
 import (
 	"bufio"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
-	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-)
-
-var eventsDebug = os.Getenv("HUE_DEBUG") != ""
-var eventsLog *log.Logger
 
-func init() {
-	if eventsDebug {
-		f, err := os.OpenFile("hue-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-		if err != nil {
-			eventsLog = log.New(os.Stderr, "[EVENTS] ", log.LstdFlags|log.Lmicroseconds)
-		} else {
-			eventsLog = log.New(f, "[EVENTS] ", log.LstdFlags|log.Lmicroseconds)
-		}
-	}
-}
+	"github.com/angristan/hue-tui/internal/logging"
+	"github.com/angristan/hue-tui/internal/models"
+)
 
-func eventsDebugf(format string, args ...interface{}) {
-	if eventsDebug && eventsLog != nil {
-		eventsLog.Printf(format, args...)
-	}
-}
+// eventsLog is the log of the event stream
+var eventsLog = logging.For("events")
 
 // EventType represents the type of event from the bridge
 type EventType string
@@ -61,11 +46,68 @@ type LightUpdateEvent struct {
 	ColorXY    *struct {
 		X, Y float64
 	}
+	// XY color points of a gradient light
+	Gradient [][2]float64
+}
+
+// GroupedLightUpdateEvent contains updated grouped light (room/zone) state
+type GroupedLightUpdateEvent struct {
+	ID         string
+	On         *bool
+	Brightness *float64
+}
+
+// RoomUpdateEvent contains updated room metadata
+type RoomUpdateEvent struct {
+	ID   string
+	Name *string
+	// ChildrenChanged is true when the event carries a new children list
+	ChildrenChanged bool
+}
+
+// SceneUpdateEvent contains updated scene metadata and status
+type SceneUpdateEvent struct {
+	ID     string
+	Name   *string
+	Status *string // "inactive", "static" or "dynamic_palette"
+}
+
+// ConnectivityUpdateEvent contains the updated Zigbee connectivity of a device
+type ConnectivityUpdateEvent struct {
+	DeviceID  string
+	Reachable bool
+}
+
+// MotionUpdateEvent contains the updated state of a motion sensor
+type MotionUpdateEvent struct {
+	ID     string
+	Motion bool
 }
 
 // EventHandler is called when an event is received
 type EventHandler func(events []Event)
 
+// StreamHandler is called when the event stream connects, or fails to
+type StreamHandler func(connected bool)
+
+// DefaultIdleTimeout is how long the event stream may stay silent before
+// it is assumed stuck and reconnected
+const DefaultIdleTimeout = 5 * time.Minute
+
+// EventStreamStats counts what happened to the event stream, for debugging
+type EventStreamStats struct {
+	// Successful connections, the first one included
+	Connects int
+	// Connections that ended, whatever the reason
+	Drops int
+	// Connections dropped by the idle watchdog
+	IdleDrops int
+	// Last time bytes, events or keep-alives, arrived
+	LastData time.Time
+	// When the current connection was made, zero while disconnected
+	ConnectedAt time.Time
+}
+
 // EventSubscription manages an SSE connection to the bridge for events
 type EventSubscription struct {
 	bridge  *HueBridge
@@ -80,6 +122,23 @@ type EventSubscription struct {
 	batchMu      sync.Mutex
 	batchTimer   *time.Timer
 	batchTimeout time.Duration
+
+	// Raw messages are written here when set
+	recorder *EventRecorder
+	// Recorded messages to replay instead of connecting
+	replay []EventLogEntry
+
+	// The connection is dropped when nothing arrives for this long
+	idleTimeout time.Duration
+	// Wait before connecting again after a failed attempt
+	retryDelay time.Duration
+	stats      EventStreamStats
+
+	streamHandler StreamHandler
+	// Whether the handler was told the stream is connected, once it was
+	// told anything
+	notified  bool
+	connected bool
 }
 
 // NewEventSubscription creates a new event subscription
@@ -89,6 +148,8 @@ func NewEventSubscription(bridge *HueBridge, handler EventHandler) *EventSubscri
 		handler:      handler,
 		done:         make(chan struct{}),
 		batchTimeout: 50 * time.Millisecond,
+		idleTimeout:  DefaultIdleTimeout,
+		retryDelay:   5 * time.Second,
 	}
 }
 
@@ -106,6 +167,49 @@ func (s *EventSubscription) Start(ctx context.Context) error {
 	return nil
 }
 
+// SetRecorder records every raw message received from the bridge
+func (s *EventSubscription) SetRecorder(r *EventRecorder) {
+	s.recorder = r
+}
+
+// SetIdleTimeout sets how long the stream may stay silent before it is
+// reconnected. Zero keeps the default.
+func (s *EventSubscription) SetIdleTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultIdleTimeout
+	}
+	s.idleTimeout = d
+}
+
+// SetStreamHandler registers a handler for the stream connecting or
+// failing to. Failures are then reported there only, rather than marking
+// the bridge unreachable: some networks block the stream but not requests.
+func (s *EventSubscription) SetStreamHandler(handler StreamHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streamHandler = handler
+}
+
+// setConnected tells the stream handler when the stream state changes
+func (s *EventSubscription) setConnected(connected bool) {
+	s.mu.Lock()
+	changed := !s.notified || s.connected != connected
+	s.notified, s.connected = true, connected
+	handler := s.streamHandler
+	s.mu.Unlock()
+
+	if changed && handler != nil {
+		handler(connected)
+	}
+}
+
+// Stats returns the connection counters of the event stream
+func (s *EventSubscription) Stats() EventStreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
 // Stop stops the event subscription
 func (s *EventSubscription) Stop() error {
 	s.mu.Lock()
@@ -126,6 +230,11 @@ func (s *EventSubscription) Stop() error {
 
 // run is the main event loop
 func (s *EventSubscription) run(ctx context.Context) {
+	if s.replay != nil {
+		s.replayLoop(ctx)
+		return
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -137,10 +246,20 @@ func (s *EventSubscription) run(ctx context.Context) {
 
 		err := s.connect(ctx)
 		if err != nil {
-			eventsDebugf("Connection error: %v, reconnecting in 5s", err)
+			eventsLog.Warnf("Connection error: %v, reconnecting in %s", err, s.retryDelay)
+			if ctx.Err() == nil {
+				s.mu.Lock()
+				handler := s.streamHandler
+				s.mu.Unlock()
+				if handler != nil {
+					s.setConnected(false)
+				} else {
+					s.bridge.health.failed(err)
+				}
+			}
 			// Wait before reconnecting
 			select {
-			case <-time.After(5 * time.Second):
+			case <-time.After(s.retryDelay):
 			case <-ctx.Done():
 				return
 			case <-s.done:
@@ -149,7 +268,7 @@ func (s *EventSubscription) run(ctx context.Context) {
 			continue
 		}
 
-		s.readLoop(ctx)
+		idle := s.readLoop(ctx)
 
 		// Connection lost, close and reconnect
 		s.mu.Lock()
@@ -157,68 +276,98 @@ func (s *EventSubscription) run(ctx context.Context) {
 			_ = s.resp.Body.Close()
 			s.resp = nil
 		}
+		s.stats.Drops++
+		s.stats.ConnectedAt = time.Time{}
+		if idle {
+			s.stats.IdleDrops++
+		}
+		stats := s.stats
 		s.mu.Unlock()
 
-		eventsDebugf("Connection lost, reconnecting...")
+		eventsLog.Warnf("Connection lost, reconnecting... (%d connects, %d drops, %d idle)",
+			stats.Connects, stats.Drops, stats.IdleDrops)
 	}
 }
 
 // connect establishes the SSE connection
 func (s *EventSubscription) connect(ctx context.Context) error {
-	url := fmt.Sprintf("https://%s/eventstream/clip/v2", s.bridge.host)
-	eventsDebugf("Connecting to SSE: %s", url)
+	url := s.bridge.url("/eventstream/clip/v2")
+	eventsLog.Debugf("Connecting to SSE: %s", url)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("hue-application-key", s.bridge.appKey)
+	if err := s.bridge.authorize(ctx, req); err != nil {
+		return err
+	}
 	req.Header.Set("Accept", "text/event-stream")
 
 	client := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			TLSClientConfig: s.bridge.tlsConfig,
 		},
 		Timeout: 0, // No timeout for SSE
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		eventsDebugf("SSE connection failed: %v", err)
+		eventsLog.Warnf("SSE connection failed: %v", err)
 		return fmt.Errorf("failed to connect to event stream: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		_ = resp.Body.Close()
-		eventsDebugf("SSE bad status: %s", resp.Status)
+		eventsLog.Warnf("SSE bad status: %s", resp.Status)
 		return fmt.Errorf("unexpected status: %s", resp.Status)
 	}
 
-	eventsDebugf("SSE connected successfully (status: %s, content-type: %s)",
+	eventsLog.Infof("SSE connected successfully (status: %s, content-type: %s)",
 		resp.Status, resp.Header.Get("Content-Type"))
 
 	s.mu.Lock()
 	s.resp = resp
+	s.stats.Connects++
+	s.stats.LastData = time.Now()
+	s.stats.ConnectedAt = s.stats.LastData
 	s.mu.Unlock()
 
+	s.bridge.health.succeeded()
+	s.setConnected(true)
 	return nil
 }
 
-// readLoop reads events from the SSE stream
-func (s *EventSubscription) readLoop(ctx context.Context) {
-	eventsDebugf("Starting SSE read loop")
+// readLoop reads events from the SSE stream. It returns true when the
+// stream was dropped by the idle watchdog.
+func (s *EventSubscription) readLoop(ctx context.Context) bool {
+	eventsLog.Debugf("Starting SSE read loop")
 
 	s.mu.Lock()
 	resp := s.resp
 	s.mu.Unlock()
 
 	if resp == nil {
-		eventsDebugf("Read loop: response is nil")
-		return
+		eventsLog.Debugf("Read loop: response is nil")
+		return false
 	}
 
-	scanner := bufio.NewScanner(resp.Body)
+	// A stream can stay open without delivering anything, after a NAT
+	// timeout for example: closing the body unblocks the scanner
+	var stalled atomic.Bool
+	watchdog := time.AfterFunc(s.idleTimeout, func() {
+		eventsLog.Warnf("Read loop: nothing received for %s, dropping the connection", s.idleTimeout)
+		stalled.Store(true)
+		_ = resp.Body.Close()
+	})
+	defer watchdog.Stop()
+
+	scanner := bufio.NewScanner(&idleReader{r: resp.Body, read: func() {
+		watchdog.Reset(s.idleTimeout)
+		s.mu.Lock()
+		s.stats.LastData = time.Now()
+		s.mu.Unlock()
+	}})
 	// Increase buffer size for large events
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024)
@@ -228,11 +377,11 @@ func (s *EventSubscription) readLoop(ctx context.Context) {
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
-			eventsDebugf("Read loop: context done")
-			return
+			eventsLog.Debugf("Read loop: context done")
+			return false
 		case <-s.done:
-			eventsDebugf("Read loop: done signal received")
-			return
+			eventsLog.Debugf("Read loop: done signal received")
+			return false
 		default:
 		}
 
@@ -249,9 +398,14 @@ func (s *EventSubscription) readLoop(ctx context.Context) {
 			eventData := dataBuffer.String()
 			dataBuffer.Reset()
 
-			eventsDebugf("Read loop: received event (%d bytes)", len(eventData))
+			eventsLog.Debugf("Read loop: received event (%d bytes)", len(eventData))
+			if s.recorder != nil {
+				if err := s.recorder.Record([]byte(eventData)); err != nil {
+					eventsLog.Warnf("Read loop: failed to record event: %v", err)
+				}
+			}
 			events := s.parseMessage([]byte(eventData))
-			eventsDebugf("Read loop: parsed %d events", len(events))
+			eventsLog.Debugf("Read loop: parsed %d events", len(events))
 			if len(events) > 0 {
 				s.batchEvents(events)
 			}
@@ -260,45 +414,38 @@ func (s *EventSubscription) readLoop(ctx context.Context) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		eventsDebugf("Read loop: scanner error: %v", err)
+		eventsLog.Warnf("Read loop: scanner error: %v", err)
 	} else {
-		eventsDebugf("Read loop: stream ended")
+		eventsLog.Debugf("Read loop: stream ended")
 	}
+	return stalled.Load()
+}
+
+// idleReader calls read whenever bytes arrive
+type idleReader struct {
+	r    io.Reader
+	read func()
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.read()
+	}
+	return n, err
 }
 
 // parseMessage parses an SSE data payload into events
 func (s *EventSubscription) parseMessage(message []byte) []Event {
 	var rawEvents []struct {
-		CreationTime string `json:"creationtime"`
-		Data         []struct {
-			ID    string `json:"id"`
-			Type  string `json:"type"`
-			Owner *struct {
-				Rid   string `json:"rid"`
-				Rtype string `json:"rtype"`
-			} `json:"owner"`
-			On *struct {
-				On bool `json:"on"`
-			} `json:"on"`
-			Dimming *struct {
-				Brightness float64 `json:"brightness"`
-			} `json:"dimming"`
-			ColorTemperature *struct {
-				Mirek int `json:"mirek"`
-			} `json:"color_temperature"`
-			Color *struct {
-				XY struct {
-					X float64 `json:"x"`
-					Y float64 `json:"y"`
-				} `json:"xy"`
-			} `json:"color"`
-		} `json:"data"`
-		ID   string `json:"id"`
-		Type string `json:"type"`
+		CreationTime string            `json:"creationtime"`
+		Data         []json.RawMessage `json:"data"`
+		ID           string            `json:"id"`
+		Type         string            `json:"type"`
 	}
 
 	if err := json.Unmarshal(message, &rawEvents); err != nil {
-		eventsDebugf("Parse error: %v (data: %s)", err, string(message[:min(200, len(message))]))
+		eventsLog.Warnf("Parse error: %v (data: %s)", err, string(message[:min(200, len(message))]))
 		return nil
 	}
 
@@ -306,17 +453,22 @@ func (s *EventSubscription) parseMessage(message []byte) []Event {
 	for _, rawEvent := range rawEvents {
 		eventType := EventType(rawEvent.Type)
 		for _, data := range rawEvent.Data {
-			event := Event{
-				Type:       eventType,
-				ResourceID: data.ID,
-				Resource:   data.Type,
+			var header struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(data, &header); err != nil {
+				eventsLog.Warnf("Parse error in event data: %v", err)
+				continue
 			}
 
-			// Re-marshal the data for the handler
-			dataBytes, _ := json.Marshal(data)
-			event.Data = dataBytes
-
-			events = append(events, event)
+			// Keep the raw payload so resource-specific parsers see every field
+			events = append(events, Event{
+				Type:       eventType,
+				ResourceID: header.ID,
+				Resource:   header.Type,
+				Data:       data,
+			})
 		}
 	}
 
@@ -328,7 +480,7 @@ func (s *EventSubscription) batchEvents(events []Event) {
 	s.batchMu.Lock()
 	defer s.batchMu.Unlock()
 
-	eventsDebugf("Batching %d events (batch size now: %d)", len(events), len(s.eventBatch)+len(events))
+	eventsLog.Debugf("Batching %d events (batch size now: %d)", len(events), len(s.eventBatch)+len(events))
 	s.eventBatch = append(s.eventBatch, events...)
 
 	// Cancel existing timer and create new one
@@ -348,7 +500,7 @@ func (s *EventSubscription) deliverBatch() {
 	s.eventBatch = nil
 	s.batchMu.Unlock()
 
-	eventsDebugf("Delivering batch of %d events", len(batch))
+	eventsLog.Debugf("Delivering batch of %d events", len(batch))
 	if len(batch) > 0 && s.handler != nil {
 		s.handler(batch)
 	}
@@ -377,6 +529,7 @@ func ParseLightUpdate(event Event) (*LightUpdateEvent, error) {
 				Y float64 `json:"y"`
 			} `json:"xy"`
 		} `json:"color"`
+		Gradient *gradientJSON `json:"gradient"`
 	}
 
 	if err := json.Unmarshal(event.Data, &data); err != nil {
@@ -399,6 +552,190 @@ func ParseLightUpdate(event Event) (*LightUpdateEvent, error) {
 	if data.Color != nil {
 		update.ColorXY = &struct{ X, Y float64 }{data.Color.XY.X, data.Color.XY.Y}
 	}
+	if data.Gradient != nil {
+		for _, p := range data.Gradient.Points {
+			update.Gradient = append(update.Gradient, [2]float64{p.Color.XY.X, p.Color.XY.Y})
+		}
+	}
 
 	return update, nil
 }
+
+// ParseGroupedLightUpdate parses a grouped_light update event
+func ParseGroupedLightUpdate(event Event) (*GroupedLightUpdateEvent, error) {
+	if event.Resource != "grouped_light" {
+		return nil, fmt.Errorf("not a grouped_light event")
+	}
+
+	var data struct {
+		ID string `json:"id"`
+		On *struct {
+			On bool `json:"on"`
+		} `json:"on"`
+		Dimming *struct {
+			Brightness float64 `json:"brightness"`
+		} `json:"dimming"`
+	}
+
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return nil, err
+	}
+
+	update := &GroupedLightUpdateEvent{
+		ID: data.ID,
+	}
+
+	if data.On != nil {
+		update.On = &data.On.On
+	}
+	if data.Dimming != nil {
+		update.Brightness = &data.Dimming.Brightness
+	}
+
+	return update, nil
+}
+
+// ParseRoomUpdate parses a room update event
+func ParseRoomUpdate(event Event) (*RoomUpdateEvent, error) {
+	if event.Resource != "room" {
+		return nil, fmt.Errorf("not a room event")
+	}
+
+	var data struct {
+		ID       string `json:"id"`
+		Metadata *struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Children *json.RawMessage `json:"children"`
+	}
+
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return nil, err
+	}
+
+	update := &RoomUpdateEvent{
+		ID:              data.ID,
+		ChildrenChanged: data.Children != nil,
+	}
+
+	if data.Metadata != nil && data.Metadata.Name != "" {
+		update.Name = &data.Metadata.Name
+	}
+
+	return update, nil
+}
+
+// ParseSceneUpdate parses a scene update event
+func ParseSceneUpdate(event Event) (*SceneUpdateEvent, error) {
+	if event.Resource != "scene" {
+		return nil, fmt.Errorf("not a scene event")
+	}
+
+	var data struct {
+		ID       string `json:"id"`
+		Metadata *struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status *struct {
+			Active string `json:"active"`
+		} `json:"status"`
+	}
+
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return nil, err
+	}
+
+	update := &SceneUpdateEvent{
+		ID: data.ID,
+	}
+
+	if data.Metadata != nil && data.Metadata.Name != "" {
+		update.Name = &data.Metadata.Name
+	}
+	if data.Status != nil && data.Status.Active != "" {
+		update.Status = &data.Status.Active
+	}
+
+	return update, nil
+}
+
+// ParseConnectivityUpdate parses a zigbee_connectivity update event
+func ParseConnectivityUpdate(event Event) (*ConnectivityUpdateEvent, error) {
+	if event.Resource != "zigbee_connectivity" {
+		return nil, fmt.Errorf("not a zigbee_connectivity event")
+	}
+
+	var data zigbeeConnectivityResource
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return nil, err
+	}
+	if data.Owner.Rid == "" || data.Status == "" {
+		return nil, fmt.Errorf("connectivity event without owner or status")
+	}
+
+	return &ConnectivityUpdateEvent{
+		DeviceID:  data.Owner.Rid,
+		Reachable: data.Status == "connected",
+	}, nil
+}
+
+// ParseMotionUpdate parses a motion update event
+func ParseMotionUpdate(event Event) (*MotionUpdateEvent, error) {
+	if event.Resource != "motion" {
+		return nil, fmt.Errorf("not a motion event")
+	}
+
+	var data struct {
+		ID     string `json:"id"`
+		Motion *struct {
+			Motion bool `json:"motion"`
+		} `json:"motion"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return nil, err
+	}
+	if data.Motion == nil {
+		return nil, fmt.Errorf("motion event without motion state")
+	}
+
+	return &MotionUpdateEvent{ID: data.ID, Motion: data.Motion.Motion}, nil
+}
+
+// ParseLightResource parses the full light resource carried by an add event
+func ParseLightResource(event Event) (*models.Light, error) {
+	if event.Resource != "light" {
+		return nil, fmt.Errorf("not a light event")
+	}
+
+	var raw lightResource
+	if err := json.Unmarshal(event.Data, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toModel(), nil
+}
+
+// ParseRoomResource parses the full room resource carried by an add event
+func ParseRoomResource(event Event) (*models.Room, error) {
+	if event.Resource != "room" {
+		return nil, fmt.Errorf("not a room event")
+	}
+
+	var raw roomResource
+	if err := json.Unmarshal(event.Data, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toModel(), nil
+}
+
+// ParseSceneResource parses the full scene resource carried by an add event
+func ParseSceneResource(event Event) (*models.Scene, error) {
+	if event.Resource != "scene" {
+		return nil, fmt.Errorf("not a scene event")
+	}
+
+	var raw sceneResource
+	if err := json.Unmarshal(event.Data, &raw); err != nil {
+		return nil, err
+	}
+	return raw.toModel(), nil
+}
@@ -1,8 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestParseMessage_LightUpdate(t *testing.T) {
@@ -273,3 +280,273 @@ func TestEventTypes(t *testing.T) {
 		t.Errorf("Expected EventTypeError to be 'error'")
 	}
 }
+
+func TestParseMessage_PreservesRawData(t *testing.T) {
+	message := `[{
+		"id": "event-123",
+		"type": "update",
+		"data": [{"id": "scene-1", "type": "scene", "status": {"active": "static"}}]
+	}]`
+
+	sub := &EventSubscription{}
+	events := sub.parseMessage([]byte(message))
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+
+	update, err := ParseSceneUpdate(events[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if update.Status == nil || *update.Status != "static" {
+		t.Errorf("Expected status 'static' to survive parsing, got %v", update.Status)
+	}
+}
+
+func TestParseGroupedLightUpdate(t *testing.T) {
+	event := Event{
+		Type:       EventTypeUpdate,
+		ResourceID: "group-1",
+		Resource:   "grouped_light",
+		Data:       json.RawMessage(`{"id": "group-1", "on": {"on": false}, "dimming": {"brightness": 42}}`),
+	}
+
+	update, err := ParseGroupedLightUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if update.On == nil || *update.On != false {
+		t.Error("Expected On to be false")
+	}
+	if update.Brightness == nil || *update.Brightness != 42 {
+		t.Error("Expected Brightness to be 42")
+	}
+
+	if _, err := ParseGroupedLightUpdate(Event{Resource: "light"}); err == nil {
+		t.Error("Expected error for non-grouped_light event")
+	}
+}
+
+func TestParseRoomUpdate(t *testing.T) {
+	event := Event{
+		Type:       EventTypeUpdate,
+		ResourceID: "room-1",
+		Resource:   "room",
+		Data:       json.RawMessage(`{"id": "room-1", "metadata": {"name": "Den"}}`),
+	}
+
+	update, err := ParseRoomUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if update.Name == nil || *update.Name != "Den" {
+		t.Error("Expected Name to be 'Den'")
+	}
+	if update.ChildrenChanged {
+		t.Error("Expected ChildrenChanged to be false")
+	}
+
+	event.Data = json.RawMessage(`{"id": "room-1", "children": [{"rid": "dev-1", "rtype": "device"}]}`)
+	update, err = ParseRoomUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !update.ChildrenChanged {
+		t.Error("Expected ChildrenChanged to be true")
+	}
+	if update.Name != nil {
+		t.Error("Expected Name to be nil")
+	}
+}
+
+func TestParseSceneUpdate(t *testing.T) {
+	event := Event{
+		Type:       EventTypeUpdate,
+		ResourceID: "scene-1",
+		Resource:   "scene",
+		Data:       json.RawMessage(`{"id": "scene-1", "metadata": {"name": "Relax"}, "status": {"active": "dynamic_palette"}}`),
+	}
+
+	update, err := ParseSceneUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if update.Name == nil || *update.Name != "Relax" {
+		t.Error("Expected Name to be 'Relax'")
+	}
+	if update.Status == nil || *update.Status != "dynamic_palette" {
+		t.Error("Expected Status to be 'dynamic_palette'")
+	}
+}
+
+func TestParseConnectivityUpdate(t *testing.T) {
+	event := Event{
+		Type:       EventTypeUpdate,
+		ResourceID: "zc-1",
+		Resource:   "zigbee_connectivity",
+		Data:       json.RawMessage(`{"id": "zc-1", "owner": {"rid": "device-1", "rtype": "device"}, "status": "connectivity_issue"}`),
+	}
+
+	update, err := ParseConnectivityUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if update.DeviceID != "device-1" || update.Reachable {
+		t.Errorf("Expected device-1 unreachable, got %+v", update)
+	}
+
+	// Updates without a status are ignored
+	event.Data = json.RawMessage(`{"id": "zc-1", "owner": {"rid": "device-1", "rtype": "device"}}`)
+	if _, err := ParseConnectivityUpdate(event); err == nil {
+		t.Error("Expected an error without status")
+	}
+}
+
+func TestParseMotionUpdate(t *testing.T) {
+	event := Event{
+		Type:       EventTypeUpdate,
+		ResourceID: "motion-1",
+		Resource:   "motion",
+		Data:       json.RawMessage(`{"id": "motion-1", "motion": {"motion": true, "motion_valid": true}}`),
+	}
+
+	update, err := ParseMotionUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if update.ID != "motion-1" || !update.Motion {
+		t.Errorf("Expected motion on motion-1, got %+v", update)
+	}
+
+	// Updates without a motion state are ignored
+	event.Data = json.RawMessage(`{"id": "motion-1", "enabled": false}`)
+	if _, err := ParseMotionUpdate(event); err == nil {
+		t.Error("Expected an error without motion state")
+	}
+}
+
+func TestParseLightUpdate_Gradient(t *testing.T) {
+	event := Event{
+		Type:       EventTypeUpdate,
+		ResourceID: "light-123",
+		Resource:   "light",
+		Data: json.RawMessage(`{"id": "light-123", "gradient": {"points": [
+			{"color": {"xy": {"x": 0.15, "y": 0.06}}},
+			{"color": {"xy": {"x": 0.42, "y": 0.2}}},
+			{"color": {"xy": {"x": 0.64, "y": 0.33}}}
+		]}}`),
+	}
+
+	update, err := ParseLightUpdate(event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(update.Gradient) != 3 {
+		t.Fatalf("Expected 3 gradient points, got %d", len(update.Gradient))
+	}
+	if update.Gradient[2] != [2]float64{0.64, 0.33} {
+		t.Errorf("Expected last point {0.64, 0.33}, got %v", update.Gradient[2])
+	}
+}
+
+func TestEventSubscription_IdleWatchdog(t *testing.T) {
+	var connects atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := connects.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		// A keep-alive, then one event, then silence
+		_, _ = fmt.Fprintf(w, ": hi\n\n")
+		_, _ = fmt.Fprintf(w, "data: [{\"type\": \"update\", \"data\": [{\"id\": \"light-%d\", \"type\": \"light\"}]}]\n\n", n)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	received := make(chan string, 10)
+	bridge := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+	sub := NewEventSubscription(bridge, func(events []Event) {
+		for _, event := range events {
+			received <- event.ResourceID
+		}
+	})
+	sub.SetIdleTimeout(200 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := sub.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = sub.Stop() }()
+
+	for _, want := range []string{"light-1", "light-2"} {
+		select {
+		case id := <-received:
+			if id != want {
+				t.Errorf("Received an event for %s, want %s", id, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("No event for %s: the silent stream was not reconnected", want)
+		}
+	}
+
+	stats := sub.Stats()
+	if stats.Connects < 2 || stats.IdleDrops < 1 || stats.Drops < stats.IdleDrops {
+		t.Errorf("Stats = %+v, want at least 2 connects and 1 idle drop", stats)
+	}
+	if stats.LastData.IsZero() {
+		t.Error("Expected the time of the last data to be recorded")
+	}
+}
+
+func TestEventSubscription_StreamHandler(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt is blocked, as by a firewall in front of the stream
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	bridge := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+	var offline atomic.Bool
+	bridge.SetConnectionHandler(func(status ConnectionStatus) {
+		if !status.Online {
+			offline.Store(true)
+		}
+	})
+	sub := NewEventSubscription(bridge, nil)
+	sub.retryDelay = 10 * time.Millisecond
+	states := make(chan bool, 10)
+	sub.SetStreamHandler(func(connected bool) { states <- connected })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := sub.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = sub.Stop() }()
+
+	for _, want := range []bool{false, true} {
+		select {
+		case connected := <-states:
+			if connected != want {
+				t.Errorf("Stream connected = %v, want %v", connected, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Stream state %v was never reported", want)
+		}
+	}
+	if offline.Load() {
+		t.Error("Expected a blocked stream not to mark the bridge unreachable")
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBridgeUnreachable is returned for commands sent while the bridge is
+// known to be offline, instead of letting each one time out
+var ErrBridgeUnreachable = errors.New("bridge unreachable")
+
+// ConnectionStatus describes whether the bridge can currently be reached
+type ConnectionStatus struct {
+	Online bool
+	// Why the bridge went offline
+	Err error
+	// Last time a request got a response
+	LastSuccess time.Time
+}
+
+// ConnectionHandler is called when the bridge goes offline or comes back
+type ConnectionHandler func(status ConnectionStatus)
+
+// connectionHealth tracks reachability from both the event stream and
+// regular requests
+type connectionHealth struct {
+	mu          sync.Mutex
+	offline     bool
+	lastSuccess time.Time
+	handler     ConnectionHandler
+}
+
+// succeeded records a request or event stream connection that got through
+func (h *connectionHealth) succeeded() {
+	h.mu.Lock()
+	h.lastSuccess = time.Now()
+	changed := h.offline
+	h.offline = false
+	status := ConnectionStatus{Online: true, LastSuccess: h.lastSuccess}
+	handler := h.handler
+	h.mu.Unlock()
+
+	if changed && handler != nil {
+		handler(status)
+	}
+}
+
+// failed records a request or event stream connection that couldn't reach
+// the bridge
+func (h *connectionHealth) failed(err error) {
+	h.mu.Lock()
+	changed := !h.offline
+	h.offline = true
+	status := ConnectionStatus{Err: err, LastSuccess: h.lastSuccess}
+	handler := h.handler
+	h.mu.Unlock()
+
+	if changed && handler != nil {
+		handler(status)
+	}
+}
+
+func (h *connectionHealth) isOffline() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.offline
+}
+
+// SetConnectionHandler registers a handler for connectivity changes
+func (p *requestPipeline) SetConnectionHandler(handler ConnectionHandler) {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+	p.health.handler = handler
+}
+
+// Connection returns the current connection status
+func (p *requestPipeline) Connection() ConnectionStatus {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+	return ConnectionStatus{Online: !p.health.offline, LastSuccess: p.health.lastSuccess}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestConnectionHealthTransitions(t *testing.T) {
+	var h connectionHealth
+	var statuses []ConnectionStatus
+	h.handler = func(s ConnectionStatus) { statuses = append(statuses, s) }
+
+	h.succeeded()
+	h.failed(errors.New("timeout"))
+	h.failed(errors.New("timeout again"))
+	h.succeeded()
+	h.succeeded()
+
+	if len(statuses) != 2 {
+		t.Fatalf("Expected only the two transitions to be reported, got %+v", statuses)
+	}
+	if statuses[0].Online || statuses[0].Err == nil || statuses[0].LastSuccess.IsZero() {
+		t.Errorf("Expected an offline status with the error and last success, got %+v", statuses[0])
+	}
+	if !statuses[1].Online {
+		t.Errorf("Expected the bridge back online, got %+v", statuses[1])
+	}
+}
+
+func TestCommandsFailFastWhenOffline(t *testing.T) {
+	// Nothing listens on port 1
+	b := NewHueBridge("127.0.0.1:1", "key", "bridge")
+	var status *ConnectionStatus
+	b.SetConnectionHandler(func(s ConnectionStatus) { status = &s })
+
+	ctx := context.Background()
+	if _, err := b.GetLights(ctx); err == nil {
+		t.Fatal("Expected the request to fail")
+	}
+	if status == nil || status.Online || b.Connection().Online {
+		t.Fatalf("Expected the bridge to be reported offline, got %+v", status)
+	}
+
+	if err := b.SetLightOn(ctx, "light-1", true); !errors.Is(err, ErrBridgeUnreachable) {
+		t.Errorf("Expected commands to fail fast with ErrBridgeUnreachable, got %v", err)
+	}
+}
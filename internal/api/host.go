@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidHost is returned for a bridge address that is neither an IP
+// address nor a hostname
+var ErrInvalidHost = errors.New("invalid bridge address")
+
+// NormalizeHost cleans up a bridge address typed by hand: an IPv4 or IPv6
+// address, optionally bracketed or with a port, or a hostname like
+// Philips-hue.local. A pasted URL is reduced to its host.
+func NormalizeHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if u, err := url.Parse(host); err == nil && u.Scheme != "" && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.TrimSuffix(host, "/")
+	if host == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidHost)
+	}
+
+	// Bare IPv6 addresses have colons without a port
+	if ip := parseIP(host); ip != "" {
+		return ip, nil
+	}
+	name, port, err := net.SplitHostPort(host)
+	if err != nil {
+		name, port = strings.Trim(host, "[]"), ""
+	}
+	if ip := parseIP(name); ip != "" {
+		name = ip
+	} else if !validHostname(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidHost, host)
+	}
+	if port == "" {
+		return name, nil
+	}
+	return net.JoinHostPort(name, port), nil
+}
+
+// parseIP returns host as an IP address, with its IPv6 zone, or "" when it
+// isn't one
+func parseIP(host string) string {
+	addr, zone, _ := strings.Cut(host, "%")
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return ""
+	}
+	if zone != "" && ip.To4() == nil {
+		return ip.String() + "%" + zone
+	}
+	return ip.String()
+}
+
+// validHostname reports whether name is a DNS name, trailing dot allowed
+func validHostname(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// urlHost returns host as the host part of a URL: IPv6 addresses are
+// bracketed, with their zone escaped
+func urlHost(host string) string {
+	if name, port, err := net.SplitHostPort(host); err == nil {
+		return net.JoinHostPort(strings.Replace(name, "%", "%25", 1), port)
+	}
+	if ip := parseIP(strings.Trim(host, "[]")); strings.Contains(ip, ":") {
+		return "[" + strings.Replace(ip, "%", "%25", 1) + "]"
+	}
+	return host
+}
+
+// bridgeURL builds the URL of path on a bridge
+func bridgeURL(scheme, host, path string) string {
+	return scheme + "://" + urlHost(host) + path
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"192.168.1.20", "192.168.1.20"},
+		{" 192.168.1.20:8443 ", "192.168.1.20:8443"},
+		{"fd00::1", "fd00::1"},
+		{"[fd00::1]", "fd00::1"},
+		{"[fd00::1]:443", "[fd00::1]:443"},
+		{"fe80::1%en0", "fe80::1%en0"},
+		{"FD00:0:0::1", "fd00::1"},
+		{"Philips-hue.local", "Philips-hue.local"},
+		{"philips-hue.local.", "philips-hue.local."},
+		{"https://192.168.1.20/", "192.168.1.20"},
+		{"https://[fd00::1]/api", "fd00::1"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeHost(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeHost(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "hue bridge", "-hue.local", "hue..local", "192.168.1.20:443:1"} {
+		if _, err := NormalizeHost(bad); !errors.Is(err, ErrInvalidHost) {
+			t.Errorf("NormalizeHost(%q) = %v, want ErrInvalidHost", bad, err)
+		}
+	}
+}
+
+func TestBridgeURL(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"192.168.1.20", "https://192.168.1.20/api"},
+		{"127.0.0.1:8443", "https://127.0.0.1:8443/api"},
+		{"fd00::1", "https://[fd00::1]/api"},
+		{"[fd00::1]:443", "https://[fd00::1]:443/api"},
+		{"fe80::1%en0", "https://[fe80::1%25en0]/api"},
+		{"Philips-hue.local", "https://Philips-hue.local/api"},
+	}
+	for _, tt := range tests {
+		if got := bridgeURL("https", tt.host, "/api"); got != tt.want {
+			t.Errorf("bridgeURL(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// v1Config is the configuration of a bridge in the V1 API, which has the
+// details CLIP v2 doesn't expose, like the zigbee channel
+type v1Config struct {
+	Name          string `json:"name"`
+	ModelID       string `json:"modelid"`
+	SWVersion     string `json:"swversion"`
+	APIVersion    string `json:"apiversion"`
+	ZigbeeChannel int    `json:"zigbeechannel"`
+	TimeZone      string `json:"timezone"`
+	SWUpdate2     struct {
+		State string `json:"state"`
+	} `json:"swupdate2"`
+}
+
+func (c *v1Config) toModel() *models.BridgeInfo {
+	return &models.BridgeInfo{
+		Name:            c.Name,
+		ModelID:         c.ModelID,
+		SoftwareVersion: c.SWVersion,
+		APIVersion:      c.APIVersion,
+		ZigbeeChannel:   c.ZigbeeChannel,
+		UpdateState:     c.SWUpdate2.State,
+		TimeZone:        c.TimeZone,
+	}
+}
+
+// GetBridgeInfo returns the firmware, model and zigbee channel of the
+// bridge from /api/<key>/config, and the number of its resources from
+// /clip/v2/resource. Latency is the round trip of the config request.
+func (b *HueBridge) GetBridgeInfo(ctx context.Context) (*models.BridgeInfo, error) {
+	start := time.Now()
+	var cfg v1Config
+	if err := b.getJSON(ctx, "/api/"+b.appKey+"/config", &cfg); err != nil {
+		return nil, fmt.Errorf("failed to get bridge config: %w", err)
+	}
+	info := cfg.toModel()
+	info.Latency = time.Since(start)
+
+	var resources apiResponse
+	if err := b.getJSON(ctx, "/clip/v2/resource", &resources); err != nil {
+		return nil, fmt.Errorf("failed to get resources: %w", err)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(resources.Data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse resources: %w", err)
+	}
+	info.Resources = len(items)
+	return info, nil
+}
+
+// getJSON decodes the response of a GET request into out, reporting the
+// error lists of the V1 API
+func (b *HueBridge) getJSON(ctx context.Context, path string, out interface{}) (err error) {
+	resp, err := b.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+	var results []v1Error
+	if json.Unmarshal(data, &results) == nil {
+		for _, r := range results {
+			if r.Error != nil {
+				return fmt.Errorf("API error: %s", r.Error.Description)
+			}
+		}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// v1Datastore is the whole state of a V1 bridge, counted by resource
+type v1Datastore struct {
+	Config        v1Config                   `json:"config"`
+	Lights        map[string]json.RawMessage `json:"lights"`
+	Groups        map[string]json.RawMessage `json:"groups"`
+	Scenes        map[string]json.RawMessage `json:"scenes"`
+	Sensors       map[string]json.RawMessage `json:"sensors"`
+	Rules         map[string]json.RawMessage `json:"rules"`
+	Schedules     map[string]json.RawMessage `json:"schedules"`
+	ResourceLinks map[string]json.RawMessage `json:"resourcelinks"`
+}
+
+// GetBridgeInfo returns the bridge configuration and the number of its
+// resources, from the whole datastore in one request
+func (b *V1Bridge) GetBridgeInfo(ctx context.Context) (*models.BridgeInfo, error) {
+	start := time.Now()
+	var store v1Datastore
+	if err := b.do(ctx, "GET", "", nil, &store); err != nil {
+		return nil, fmt.Errorf("failed to get bridge config: %w", err)
+	}
+	info := store.Config.toModel()
+	info.Latency = time.Since(start)
+	info.Resources = len(store.Lights) + len(store.Groups) + len(store.Scenes) + len(store.Sensors) +
+		len(store.Rules) + len(store.Schedules) + len(store.ResourceLinks)
+	return info, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const configJSON = `{"name": "Hue Bridge", "modelid": "BSB002", "swversion": "1967054020", "apiversion": "1.65.0",
+	"zigbeechannel": 25, "timezone": "Europe/Paris", "swupdate2": {"state": "anyreadytoinstall"}}`
+
+func TestGetBridgeInfo(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/key/config":
+			_, _ = w.Write([]byte(configJSON))
+		case "/clip/v2/resource":
+			_, _ = w.Write([]byte(`{"errors": [], "data": [{"id": "a", "type": "light"}, {"id": "b", "type": "room"}, {"id": "c", "type": "bridge"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+	info, err := b.GetBridgeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetBridgeInfo: %v", err)
+	}
+	if info.ModelID != "BSB002" || info.SoftwareVersion != "1967054020" || info.ZigbeeChannel != 25 || info.Resources != 3 {
+		t.Errorf("Unexpected info %+v", info)
+	}
+	if got := info.UpdateStatus(); got != "Update ready to install" {
+		t.Errorf("UpdateStatus = %q", got)
+	}
+	if info.Latency <= 0 {
+		t.Errorf("Expected the latency to be measured, got %s", info.Latency)
+	}
+}
+
+func TestV1GetBridgeInfo(t *testing.T) {
+	b, _ := v1Server(t, map[string]string{
+		"GET ": `{"config": ` + configJSON + `,
+			"lights": {"1": {}, "2": {}}, "groups": {"1": {}}, "scenes": {"abc": {}},
+			"sensors": {"1": {}, "2": {}, "3": {}}, "rules": {}, "schedules": {"1": {}}}`,
+	})
+	info, err := b.GetBridgeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetBridgeInfo: %v", err)
+	}
+	if info.Name != "Hue Bridge" || info.TimeZone != "Europe/Paris" || info.Resources != 8 {
+		t.Errorf("Unexpected info %+v", info)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import "github.com/angristan/hue-tui/internal/models"
+
+// ScenePreset is a brightness and white temperature for every light of a
+// group
+type ScenePreset struct {
+	Name string
+	// Brightness in percent
+	Brightness float64
+	// Color temperature in mirek
+	Mirek int
+}
+
+// NaturalLightPresets follow daylight through the day: cool and bright
+// around noon, warm and dim at night
+var NaturalLightPresets = []ScenePreset{
+	{Name: "Morning", Brightness: 70, Mirek: 233}, // 4300K
+	{Name: "Day", Brightness: 100, Mirek: 200},    // 5000K
+	{Name: "Evening", Brightness: 60, Mirek: 370}, // 2700K
+	{Name: "Night", Brightness: 15, Mirek: 454},   // 2200K
+}
+
+// PresetSceneActions returns the actions applying a preset to lights.
+// Color lights without white ambiance get the xy color of the temperature,
+// on/off-only lights are just turned on.
+func PresetSceneActions(lights []*models.Light, preset ScenePreset) []SceneAction {
+	actions := make([]SceneAction, 0, len(lights))
+	for _, light := range lights {
+		on := true
+		action := SceneAction{LightID: light.ID, On: &on}
+		if light.OnOffOnly {
+			actions = append(actions, action)
+			continue
+		}
+		brightness := preset.Brightness
+		action.Brightness = &brightness
+		switch {
+		case light.SupportsColorTemp:
+			mirek := preset.Mirek
+			action.Mirek = &mirek
+		case light.SupportsColor:
+			x, y := models.RGBToXY(models.NewColorFromMirek(uint16(preset.Mirek), 254).RGB())
+			action.XY = &[2]float64{x, y}
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
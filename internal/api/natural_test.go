@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func TestPresetSceneActions(t *testing.T) {
+	lights := []*models.Light{
+		{ID: "ambiance", SupportsColorTemp: true},
+		{ID: "color", SupportsColor: true},
+		{ID: "plug", OnOffOnly: true},
+	}
+	preset := ScenePreset{Name: "Evening", Brightness: 60, Mirek: 370}
+	actions := PresetSceneActions(lights, preset)
+	if len(actions) != 3 {
+		t.Fatalf("Expected an action per light, got %d", len(actions))
+	}
+
+	ambiance, color, plug := actions[0], actions[1], actions[2]
+	if ambiance.Mirek == nil || *ambiance.Mirek != 370 || *ambiance.Brightness != 60 || !*ambiance.On {
+		t.Errorf("Expected the ambiance light at 370 mirek and 60%%, got %+v", ambiance)
+	}
+	// Warm white in xy sits right of the neutral point
+	if color.Mirek != nil || color.XY == nil || color.XY[0] < 0.4 {
+		t.Errorf("Expected the color light to get a warm xy color, got %+v", color)
+	}
+	if plug.Brightness != nil || plug.On == nil || !*plug.On {
+		t.Errorf("Expected the plug to only be turned on, got %+v", plug)
+	}
+}
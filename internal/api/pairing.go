@@ -46,7 +46,7 @@ func CreateAppKey(ctx context.Context, host string, appName string, timeout time
 		},
 	}
 
-	url := fmt.Sprintf("https://%s/api", host)
+	url := bridgeURL("https", host, "/api")
 
 	body := pairingRequest{
 		DeviceType:        appName,
@@ -125,7 +125,7 @@ func GetBridgeID(ctx context.Context, host string) (bridgeID string, err error)
 		},
 	}
 
-	url := fmt.Sprintf("https://%s/api/0/config", host)
+	url := bridgeURL("https", host, "/api/0/config")
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// requestPipeline is what the requests to a bridge go through, whatever
+// its API: commands fail fast while the bridge is offline and wait for
+// the rate limits, mutating requests are audited, and every response
+// feeds the connection health.
+type requestPipeline struct {
+	bridgeID string
+
+	// Command rate limits shared by every caller of this bridge
+	limits *rateLimits
+
+	// Reachability, fed by requests and the event stream
+	health connectionHealth
+
+	// Optional log of mutating requests
+	audit *AuditLog
+}
+
+func newRequestPipeline(bridgeID string) requestPipeline {
+	return requestPipeline{bridgeID: bridgeID, limits: newRateLimits()}
+}
+
+// send performs a request with client. path is what the rate limits and
+// the audit log go by, and newRequest builds the request to send with the
+// body.
+func (p *requestPipeline) send(ctx context.Context, client *http.Client, method, path string, body io.Reader, newRequest func(body io.Reader) (*http.Request, error)) (resp *http.Response, err error) {
+	// Keep a copy of the payload of commands for the audit log
+	var payload []byte
+	if p.audit != nil && method != "GET" {
+		if body != nil {
+			data, err := io.ReadAll(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body: %w", err)
+			}
+			payload, body = data, bytes.NewReader(data)
+		}
+		defer func() { p.auditRequest(method, path, payload, resp, err) }()
+	}
+
+	// Commands fail fast while the bridge is offline, reads still go
+	// through so a refresh can detect that it is back
+	if method != "GET" && p.health.isOffline() {
+		return nil, ErrBridgeUnreachable
+	}
+
+	// Commands wait their turn instead of tripping the bridge's rate limits
+	if err := p.limits.Wait(ctx, method, path); err != nil {
+		return nil, fmt.Errorf("rate limited: %w", err)
+	}
+
+	req, err := newRequest(body)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err = client.Do(req)
+	if err != nil {
+		// Cancelled requests say nothing about the bridge
+		if ctx.Err() == nil {
+			apiLog.Warnf("%s %s failed: %v", method, path, withoutURL(err))
+			p.health.failed(withoutURL(err))
+		}
+		return nil, err
+	}
+	apiLog.Debugf("%s %s: %s in %s", method, path, resp.Status, time.Since(start).Round(time.Millisecond))
+	p.health.succeeded()
+	return resp, nil
+}
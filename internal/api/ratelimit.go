@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Command rate limits documented by Hue: about 10 commands per second to
+// lights and 1 per second to groups. Scene recalls fan out to every light of
+// the group, so they count as group commands.
+const (
+	lightCommandsPerSecond = 10
+	lightCommandBurst      = 10
+	groupCommandsPerSecond = 1
+	groupCommandBurst      = 2
+)
+
+// tokenBucket is a token bucket rate limiter. Callers reserve a token and
+// wait until it is available, so concurrent callers are served in order.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (tb *tokenBucket) reserve() time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that won't be used
+func (tb *tokenBucket) cancel() {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.tokens++
+}
+
+// Wait blocks until a command may be sent, or the context is done
+func (tb *tokenBucket) Wait(ctx context.Context) error {
+	return tb.sleep(ctx, tb.reserve())
+}
+
+// sleep waits for a reserved token, giving it back if the context is done
+func (tb *tokenBucket) sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		tb.cancel()
+		return ctx.Err()
+	}
+}
+
+// ThrottleHandler is called when the number of commands waiting for the
+// rate limits changes
+type ThrottleHandler func(queued int)
+
+// rateLimits holds the limiters shared by every command sent to a bridge
+type rateLimits struct {
+	lights *tokenBucket
+	groups *tokenBucket
+
+	// Commands waiting for a token
+	mu      sync.Mutex
+	queued  int
+	handler ThrottleHandler
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{
+		lights: newTokenBucket(lightCommandsPerSecond, lightCommandBurst),
+		groups: newTokenBucket(groupCommandsPerSecond, groupCommandBurst),
+	}
+}
+
+// Wait blocks until a command may be sent. Commands that have to wait are
+// counted, to show that they are being throttled.
+func (rl *rateLimits) Wait(ctx context.Context, method, path string) error {
+	limiter := rl.limiterFor(method, path)
+	if limiter == nil {
+		return nil
+	}
+	delay := limiter.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	rl.addQueued(1)
+	defer rl.addQueued(-1)
+	return limiter.sleep(ctx, delay)
+}
+
+func (rl *rateLimits) addQueued(delta int) {
+	rl.mu.Lock()
+	rl.queued += delta
+	queued, handler := rl.queued, rl.handler
+	rl.mu.Unlock()
+
+	if handler != nil {
+		handler(queued)
+	}
+}
+
+// SetThrottleHandler registers a handler for the number of commands
+// waiting for the bridge's rate limits
+func (p *requestPipeline) SetThrottleHandler(handler ThrottleHandler) {
+	p.limits.mu.Lock()
+	defer p.limits.mu.Unlock()
+	p.limits.handler = handler
+}
+
+// limiterFor returns the limiter a request counts against, or nil for
+// requests that aren't throttled (reads and configuration changes). V1
+// paths have no /clip prefix, and V1 scenes are recalled through their
+// group's action.
+func (rl *rateLimits) limiterFor(method, path string) *tokenBucket {
+	if method != "PUT" {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(path, "/clip/v2/resource/light/"),
+		strings.HasPrefix(path, "/lights/"):
+		return rl.lights
+	case strings.HasPrefix(path, "/clip/v2/resource/grouped_light/"),
+		strings.HasPrefix(path, "/clip/v2/resource/scene/"),
+		strings.HasPrefix(path, "/clip/v2/resource/smart_scene/"),
+		strings.HasPrefix(path, "/groups/") && strings.HasSuffix(path, "/action"):
+		return rl.groups
+	}
+	return nil
+}
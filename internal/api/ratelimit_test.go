@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurstThenRate(t *testing.T) {
+	tb := newTokenBucket(20, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := tb.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Expected the burst to go through immediately, took %s", elapsed)
+	}
+
+	// The next two tokens come in at 20 per second
+	for i := 0; i < 2; i++ {
+		if err := tb.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected commands past the burst to be spaced out, took %s", elapsed)
+	}
+}
+
+func TestTokenBucketContextCancel(t *testing.T) {
+	tb := newTokenBucket(1, 1)
+	if err := tb.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tb.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	// The cancelled reservation is handed back
+	tb.mu.Lock()
+	tokens := tb.tokens
+	tb.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("Expected the cancelled token to be returned, have %.2f", tokens)
+	}
+}
+
+func TestRateLimitsLimiterFor(t *testing.T) {
+	rl := newRateLimits()
+	tests := []struct {
+		method string
+		path   string
+		want   *tokenBucket
+	}{
+		{"PUT", "/clip/v2/resource/light/abc", rl.lights},
+		{"PUT", "/clip/v2/resource/grouped_light/abc", rl.groups},
+		{"PUT", "/clip/v2/resource/scene/abc", rl.groups},
+		{"GET", "/clip/v2/resource/light", nil},
+		{"POST", "/clip/v2/resource/room", nil},
+		{"PUT", "/clip/v2/resource/room/abc", nil},
+		{"PUT", "/lights/1/state", rl.lights},
+		{"PUT", "/groups/1/action", rl.groups},
+		{"PUT", "/groups/1", nil},
+	}
+	for _, tt := range tests {
+		if got := rl.limiterFor(tt.method, tt.path); got != tt.want {
+			t.Errorf("limiterFor(%s, %s) picked the wrong limiter", tt.method, tt.path)
+		}
+	}
+}
+
+func TestRateLimitsReportQueuedCommands(t *testing.T) {
+	rl := &rateLimits{lights: newTokenBucket(20, 1), groups: newTokenBucket(20, 1)}
+	var mu sync.Mutex
+	var reported []int
+	rl.handler = func(queued int) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, queued)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := rl.Wait(ctx, "PUT", "/clip/v2/resource/light/l1"); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	// Reads are never throttled
+	if err := rl.Wait(ctx, "GET", "/clip/v2/resource/light"); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 2 || reported[0] != 1 || reported[1] != 0 {
+		t.Errorf("Expected the throttled command to be reported queued then sent, got %v", reported)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemoteAPIURL is the Hue remote API, which relays requests to a bridge
+// through the cloud when it isn't on the same network
+const RemoteAPIURL = "https://api.meethue.com"
+
+// remoteTokenMargin is how long before it expires the access token is
+// refreshed, so requests in flight don't fail with it
+const remoteTokenMargin = time.Minute
+
+// ErrRemoteTokenExpired is returned when the access token of the remote API
+// expired and can't be refreshed
+var ErrRemoteTokenExpired = errors.New("remote API token expired, authorize hue-tui again")
+
+// RemoteToken is an OAuth token of the Hue remote API
+type RemoteToken struct {
+	AccessToken  string
+	RefreshToken string
+	// When the access token expires, zero if unknown
+	ExpiresAt time.Time
+}
+
+// TokenHandler is called with the new token after each refresh, to save it
+type TokenHandler func(token RemoteToken)
+
+// RemoteAuth authorizes requests to the Hue remote API with the OAuth token
+// of an app, refreshing it as it expires
+type RemoteAuth struct {
+	clientID     string
+	clientSecret string
+	baseURL      string
+	client       *http.Client
+
+	mu      sync.Mutex
+	token   RemoteToken
+	handler TokenHandler
+}
+
+// NewRemoteAuth creates the authorization of a remote API app. Without a
+// refresh token, the access token is used until it expires.
+func NewRemoteAuth(clientID, clientSecret string, token RemoteToken) *RemoteAuth {
+	return &RemoteAuth{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		baseURL:      RemoteAPIURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		token:        token,
+	}
+}
+
+// SetTokenHandler registers a handler for refreshed tokens
+func (a *RemoteAuth) SetTokenHandler(handler TokenHandler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.handler = handler
+}
+
+// Token returns the current token
+func (a *RemoteAuth) Token() RemoteToken {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+// accessToken returns a valid access token, refreshing it first if it
+// expires soon
+func (a *RemoteAuth) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token.ExpiresAt.IsZero() || time.Until(a.token.ExpiresAt) > remoteTokenMargin {
+		return a.token.AccessToken, nil
+	}
+	if a.token.RefreshToken == "" {
+		if time.Now().Before(a.token.ExpiresAt) {
+			return a.token.AccessToken, nil
+		}
+		return "", ErrRemoteTokenExpired
+	}
+	if err := a.refresh(ctx); err != nil {
+		return "", err
+	}
+	return a.token.AccessToken, nil
+}
+
+// refresh exchanges the refresh token for a new token. Must be called with
+// the lock held.
+func (a *RemoteAuth) refresh(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {a.token.RefreshToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/v2/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(a.clientID, a.clientSecret)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to refresh remote API token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }() // Error ignored: body already read
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		return ErrRemoteTokenExpired
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("failed to refresh remote API token: %s", resp.Status)
+	}
+
+	var result struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode remote API token: %w", err)
+	}
+	if result.AccessToken == "" {
+		return fmt.Errorf("failed to refresh remote API token: no access token in response")
+	}
+
+	token := RemoteToken{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = a.token.RefreshToken
+	}
+	if result.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	a.token = token
+	apiLog.Infof("Refreshed remote API token, valid until %s", token.ExpiresAt.Format(time.RFC3339))
+	if a.handler != nil {
+		a.handler(token)
+	}
+	return nil
+}
+
+// SetRemote makes the bridge reached through the Hue remote API. Requests
+// then go to the cloud, which has a certificate from a public authority, so
+// the bridge certificate policy no longer applies.
+func (b *HueBridge) SetRemote(auth *RemoteAuth) {
+	b.remote = auth
+	b.tlsConfig = nil
+	b.client = &http.Client{Timeout: 10 * time.Second}
+}
+
+// Remote returns the remote API authorization of the bridge, or nil when it
+// is reached on the local network
+func (b *HueBridge) Remote() *RemoteAuth {
+	return b.remote
+}
+
+// url builds the URL of an API path, on the bridge or through the remote API
+func (b *HueBridge) url(path string) string {
+	if b.remote != nil {
+		return b.remote.baseURL + "/route" + path
+	}
+	return bridgeURL("https", b.host, path)
+}
+
+// authorize sets the authentication headers of a request
+func (b *HueBridge) authorize(ctx context.Context, req *http.Request) error {
+	req.Header.Set("hue-application-key", b.appKey)
+	if b.remote == nil {
+		return nil
+	}
+	token, err := b.remote.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
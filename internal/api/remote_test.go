@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRemoteBridge(t *testing.T) {
+	var requests []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/oauth2/token":
+			user, pass, _ := r.BasicAuth()
+			_ = r.ParseForm()
+			requests = append(requests, "refresh "+user+":"+pass+" "+r.Form.Get("refresh_token"))
+			_, _ = w.Write([]byte(`{"access_token": "new-access", "refresh_token": "new-refresh", "expires_in": 604800}`))
+		case "/route/clip/v2/resource/light/light-1":
+			requests = append(requests, r.Method+" "+r.Header.Get("Authorization")+" "+r.Header.Get("hue-application-key"))
+			_, _ = w.Write([]byte(`{"data": [{"rid": "light-1"}], "errors": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	auth := NewRemoteAuth("client", "secret", RemoteToken{
+		AccessToken:  "old-access",
+		RefreshToken: "old-refresh",
+		ExpiresAt:    time.Now().Add(30 * time.Second),
+	})
+	auth.baseURL = server.URL
+	auth.client = server.Client()
+	var saved RemoteToken
+	auth.SetTokenHandler(func(token RemoteToken) { saved = token })
+
+	b := NewHueBridge("192.168.1.2", "key", "bridge-1")
+	b.SetRemote(auth)
+	b.client = server.Client()
+	b.SetTLSPolicy(TLSPolicy{Mode: TLSModeTOFU})
+
+	if err := b.SetLightOn(context.Background(), "light-1", true); err != nil {
+		t.Fatalf("SetLightOn failed: %v", err)
+	}
+	if err := b.SetLightOn(context.Background(), "light-1", false); err != nil {
+		t.Fatalf("SetLightOn failed: %v", err)
+	}
+
+	want := []string{
+		"refresh client:secret old-refresh",
+		"PUT Bearer new-access key",
+		"PUT Bearer new-access key",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("Requests = %q, want %q", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("Request %d = %q, want %q", i, requests[i], want[i])
+		}
+	}
+	if saved.AccessToken != "new-access" || saved.RefreshToken != "new-refresh" || time.Until(saved.ExpiresAt) < 6*24*time.Hour {
+		t.Errorf("Saved token %+v, want the refreshed one", saved)
+	}
+	if auth.Token() != saved {
+		t.Errorf("Token() = %+v, want %+v", auth.Token(), saved)
+	}
+}
+
+func TestRemoteTokenExpired(t *testing.T) {
+	auth := NewRemoteAuth("client", "secret", RemoteToken{
+		AccessToken: "access",
+		ExpiresAt:   time.Now().Add(-time.Hour),
+	})
+	if _, err := auth.accessToken(context.Background()); !errors.Is(err, ErrRemoteTokenExpired) {
+		t.Errorf("accessToken error = %v, want ErrRemoteTokenExpired", err)
+	}
+
+	// Tokens without an expiry are used as is
+	auth = NewRemoteAuth("client", "secret", RemoteToken{AccessToken: "access"})
+	if token, err := auth.accessToken(context.Background()); err != nil || token != "access" {
+		t.Errorf("accessToken = %q, %v, want the configured token", token, err)
+	}
+}
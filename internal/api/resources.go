@@ -0,0 +1,366 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// resourceRef is a reference to another resource in the V2 API
+type resourceRef struct {
+	Rid   string `json:"rid"`
+	Rtype string `json:"rtype"`
+}
+
+// resourceMetadata is the writable metadata of rooms, zones and scenes
+type resourceMetadata struct {
+	Name      string `json:"name,omitempty"`
+	Archetype string `json:"archetype,omitempty"`
+}
+
+// groupBody is the request body for creating a room or zone
+type groupBody struct {
+	Metadata *resourceMetadata `json:"metadata,omitempty"`
+	Children []resourceRef     `json:"children,omitempty"`
+}
+
+// SceneAction describes the state a light takes when a scene is recalled
+type SceneAction struct {
+	LightID string
+	On      *bool
+	// Brightness in percent (0-100)
+	Brightness *float64
+	// Color temperature in mirek
+	Mirek *int
+	// CIE xy color, used when Mirek is nil
+	XY *[2]float64
+}
+
+// CaptureSceneActions returns the actions recreating the current state of
+// lights, used to save it as a scene
+func CaptureSceneActions(lights []*models.Light) []SceneAction {
+	actions := make([]SceneAction, 0, len(lights))
+	for _, light := range lights {
+		on := light.On
+		action := SceneAction{LightID: light.ID, On: &on}
+		if light.On {
+			brightness := models.LevelToPctPrecise(light.Brightness)
+			action.Brightness = &brightness
+			if c := light.Color; c != nil {
+				switch c.Mode {
+				case models.ColorModeColorTemp:
+					mirek := int(c.Mirek)
+					action.Mirek = &mirek
+				case models.ColorModeXY:
+					action.XY = &[2]float64{c.X, c.Y}
+				case models.ColorModeHS:
+					x, y := HSToXY(c.Hue, c.Saturation)
+					action.XY = &[2]float64{x, y}
+				}
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// GetZones retrieves all zones from the bridge. Zone children are lights
+// rather than devices, so their membership is reported in LightIDs.
+func (b *HueBridge) GetZones(ctx context.Context) (zones []*models.Room, err error) {
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/zone", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zones: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode zones response: %w", err)
+	}
+
+	if len(apiResp.Errors) > 0 {
+		return nil, fmt.Errorf("API error: %s", apiResp.Errors[0].Description)
+	}
+
+	var rawZones []roomResource
+	if err := json.Unmarshal(apiResp.Data, &rawZones); err != nil {
+		return nil, fmt.Errorf("failed to parse zones: %w", err)
+	}
+
+	result := make([]*models.Room, len(rawZones))
+	for i, raw := range rawZones {
+		zone := raw.toModel()
+		for _, child := range raw.Children {
+			if child.Rtype == "light" {
+				zone.LightIDs = append(zone.LightIDs, child.Rid)
+			}
+		}
+		result[i] = zone
+	}
+
+	return result, nil
+}
+
+// RenameLight changes the name of a light
+func (b *HueBridge) RenameLight(ctx context.Context, lightID, name string) error {
+	body := struct {
+		Metadata resourceMetadata `json:"metadata"`
+	}{Metadata: resourceMetadata{Name: name}}
+	return b.updateResource(ctx, "light", lightID, body)
+}
+
+// CreateRoom creates a room containing the given devices and returns its ID
+func (b *HueBridge) CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error) {
+	if archetype == "" {
+		archetype = "other"
+	}
+	body := groupBody{
+		Metadata: &resourceMetadata{Name: name, Archetype: archetype},
+		Children: refs(deviceIDs, "device"),
+	}
+	return b.createResource(ctx, "room", body)
+}
+
+// UpdateRoom renames a room and/or replaces its devices.
+// An empty name or nil device list leaves that part unchanged.
+func (b *HueBridge) UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error {
+	return b.updateResource(ctx, "room", roomID, groupUpdate(name, deviceIDs, "device"))
+}
+
+// DeleteRoom deletes a room. Its devices stay on the bridge without a room.
+func (b *HueBridge) DeleteRoom(ctx context.Context, roomID string) error {
+	return b.deleteResource(ctx, "room", roomID)
+}
+
+// CreateZone creates a zone containing the given lights and returns its ID
+func (b *HueBridge) CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error) {
+	if archetype == "" {
+		archetype = "other"
+	}
+	body := groupBody{
+		Metadata: &resourceMetadata{Name: name, Archetype: archetype},
+		Children: refs(lightIDs, "light"),
+	}
+	return b.createResource(ctx, "zone", body)
+}
+
+// UpdateZone renames a zone and/or replaces its lights.
+// An empty name or nil light list leaves that part unchanged.
+func (b *HueBridge) UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error {
+	return b.updateResource(ctx, "zone", zoneID, groupUpdate(name, lightIDs, "light"))
+}
+
+// DeleteZone deletes a zone
+func (b *HueBridge) DeleteZone(ctx context.Context, zoneID string) error {
+	return b.deleteResource(ctx, "zone", zoneID)
+}
+
+// CreateScene creates a scene for a room or zone and returns its ID.
+// groupType is "room" or "zone".
+func (b *HueBridge) CreateScene(ctx context.Context, name, groupID, groupType string, actions []SceneAction) (string, error) {
+	type actionBody struct {
+		Target resourceRef            `json:"target"`
+		Action map[string]interface{} `json:"action"`
+	}
+	body := struct {
+		Metadata resourceMetadata `json:"metadata"`
+		Group    resourceRef      `json:"group"`
+		Actions  []actionBody     `json:"actions"`
+	}{
+		Metadata: resourceMetadata{Name: name},
+		Group:    resourceRef{Rid: groupID, Rtype: groupType},
+		Actions:  make([]actionBody, 0, len(actions)),
+	}
+	for _, a := range actions {
+		body.Actions = append(body.Actions, actionBody{
+			Target: resourceRef{Rid: a.LightID, Rtype: "light"},
+			Action: a.state(),
+		})
+	}
+	return b.createResource(ctx, "scene", body)
+}
+
+// state returns the action as a light state body, without its nil fields
+func (a SceneAction) state() map[string]interface{} {
+	state := make(map[string]interface{})
+	if a.On != nil {
+		state["on"] = map[string]bool{"on": *a.On}
+	}
+	if a.Brightness != nil {
+		state["dimming"] = map[string]float64{"brightness": *a.Brightness}
+	}
+	if a.Mirek != nil {
+		state["color_temperature"] = map[string]int{"mirek": *a.Mirek}
+	} else if a.XY != nil {
+		state["color"] = map[string]interface{}{
+			"xy": map[string]float64{"x": a.XY[0], "y": a.XY[1]},
+		}
+	}
+	return state
+}
+
+// SetLightState sets the on state, brightness and color of the light of an
+// action in one command, leaving its nil fields unchanged
+func (b *HueBridge) SetLightState(ctx context.Context, action SceneAction) error {
+	data, err := json.Marshal(action.state())
+	if err != nil {
+		return fmt.Errorf("failed to encode light state: %w", err)
+	}
+	return b.setLightState(ctx, action.LightID, string(data))
+}
+
+// SetGroupedLightState sets every light of a group to the state of an
+// action in one command. The action's light ID is ignored.
+func (b *HueBridge) SetGroupedLightState(ctx context.Context, groupedLightID string, action SceneAction) error {
+	data, err := json.Marshal(action.state())
+	if err != nil {
+		return fmt.Errorf("failed to encode grouped light state: %w", err)
+	}
+	return b.setGroupedLightState(ctx, groupedLightID, string(data))
+}
+
+// DeleteScene deletes a scene
+func (b *HueBridge) DeleteScene(ctx context.Context, sceneID string) error {
+	return b.deleteResource(ctx, "scene", sceneID)
+}
+
+func refs(ids []string, rtype string) []resourceRef {
+	result := make([]resourceRef, len(ids))
+	for i, id := range ids {
+		result[i] = resourceRef{Rid: id, Rtype: rtype}
+	}
+	return result
+}
+
+// groupUpdate builds a partial update body. A map is used so that an empty
+// (but non-nil) child list is still sent to clear the group.
+func groupUpdate(name string, childIDs []string, rtype string) map[string]interface{} {
+	body := make(map[string]interface{})
+	if name != "" {
+		body["metadata"] = resourceMetadata{Name: name}
+	}
+	if childIDs != nil {
+		body["children"] = refs(childIDs, rtype)
+	}
+	return body
+}
+
+// createResource POSTs a new resource and returns the ID assigned by the bridge
+func (b *HueBridge) createResource(ctx context.Context, rtype string, payload interface{}) (id string, err error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", rtype, err)
+	}
+
+	resp, err := b.doRequest(ctx, "POST", "/clip/v2/resource/"+rtype, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", rtype, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return "", fmt.Errorf("failed to decode %s response: %w", rtype, err)
+	}
+	if len(apiResp.Errors) > 0 {
+		return "", fmt.Errorf("API error: %s", apiResp.Errors[0].Description)
+	}
+
+	var created []resourceRef
+	if err := json.Unmarshal(apiResp.Data, &created); err != nil || len(created) == 0 {
+		return "", fmt.Errorf("bridge did not return the new %s ID", rtype)
+	}
+
+	return created[0].Rid, nil
+}
+
+// updateResource PUTs a partial update to an existing resource
+func (b *HueBridge) updateResource(ctx context.Context, rtype, id string, payload interface{}) (err error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", rtype, err)
+	}
+
+	path := fmt.Sprintf("/clip/v2/resource/%s/%s", rtype, id)
+	resp, err := b.doRequest(ctx, "PUT", path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", rtype, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// deleteResource DELETEs an existing resource
+func (b *HueBridge) deleteResource(ctx context.Context, rtype, id string) (err error) {
+	path := fmt.Sprintf("/clip/v2/resource/%s/%s", rtype, id)
+	resp, err := b.doRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", rtype, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// listResources GETs every resource of a type and decodes them into out
+func (b *HueBridge) listResources(ctx context.Context, rtype string, out interface{}) (err error) {
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/"+rtype, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", rtype, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", rtype, err)
+	}
+	if len(apiResp.Errors) > 0 {
+		return fmt.Errorf("API error: %s", apiResp.Errors[0].Description)
+	}
+
+	if err := json.Unmarshal(apiResp.Data, out); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", rtype, err)
+	}
+	return nil
+}
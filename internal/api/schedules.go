@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// IDs of the built-in behavior scripts used for schedules. They are the
+// same on every bridge.
+const (
+	wakeUpScriptID    = "ff8957e3-2eb9-4699-a0c8-ad2cb3ede704"
+	goToSleepScriptID = "7e571ac6-f363-42e1-809a-4cbf6523ed72"
+)
+
+// ScheduleSpec describes a wake-up, sleep or turn on schedule to create
+type ScheduleSpec struct {
+	// models.ScheduleWakeUp, models.ScheduleSleep or models.ScheduleTurnOn
+	Kind models.ScheduleKind
+	Name string
+	// Time of day the lights reach full brightness (wake up) or turn off (sleep)
+	Hour, Minute int
+	// Sunrise or sunset to use instead of Hour and Minute, with an offset
+	Sun       models.SunEvent
+	SunOffset time.Duration
+	// Days to repeat on (empty = run once)
+	Days []time.Weekday
+	Fade time.Duration
+	// Room or zone to act on
+	GroupID   string
+	GroupType string // "room" or "zone"
+	// Lights within the group (empty = the whole group)
+	LightIDs []string
+}
+
+// Local reports whether the schedule must be run by hue-tui: behavior
+// scripts only take clock times and can't simply turn lights on
+func (spec ScheduleSpec) Local() bool {
+	return spec.Sun != "" || spec.Kind == models.ScheduleTurnOn
+}
+
+// durationJSON is the {"seconds": n} duration format used by behavior scripts
+type durationJSON struct {
+	Seconds int `json:"seconds"`
+}
+
+// behaviorWhere is a group, optionally restricted to some of its lights
+type behaviorWhere struct {
+	Group resourceRef   `json:"group"`
+	Items []resourceRef `json:"items,omitempty"`
+}
+
+// behaviorWhen is a time of day with optional recurrence
+type behaviorWhen struct {
+	TimePoint struct {
+		Type string        `json:"type"`
+		Time *behaviorTime `json:"time,omitempty"`
+	} `json:"time_point"`
+	RecurrenceDays []string `json:"recurrence_days,omitempty"`
+}
+
+type behaviorTime struct {
+	Hour   int `json:"hour"`
+	Minute int `json:"minute"`
+}
+
+// behaviorInstanceResource represents the V2 API behavior_instance resource
+type behaviorInstanceResource struct {
+	ID       string `json:"id"`
+	ScriptID string `json:"script_id"`
+	Enabled  bool   `json:"enabled"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Configuration struct {
+		When            *behaviorWhen   `json:"when"`
+		FadeInDuration  *durationJSON   `json:"fade_in_duration"`
+		FadeOutDuration *durationJSON   `json:"fade_out_duration"`
+		Where           []behaviorWhere `json:"where"`
+	} `json:"configuration"`
+}
+
+func (r *behaviorInstanceResource) toModel() *models.Schedule {
+	s := &models.Schedule{
+		ID:       r.ID,
+		Resource: "behavior_instance",
+		Name:     r.Metadata.Name,
+		Kind:     models.ScheduleOther,
+		Enabled:  r.Enabled,
+	}
+	switch r.ScriptID {
+	case wakeUpScriptID:
+		s.Kind = models.ScheduleWakeUp
+	case goToSleepScriptID:
+		s.Kind = models.ScheduleSleep
+	}
+
+	cfg := r.Configuration
+	if when := cfg.When; when != nil {
+		if t := when.TimePoint.Time; t != nil {
+			s.Hour, s.Minute, s.HasTime = t.Hour, t.Minute, true
+		}
+		for _, day := range when.RecurrenceDays {
+			if d, ok := weekdayByName[day]; ok {
+				s.Days = append(s.Days, d)
+			}
+		}
+	}
+	if cfg.FadeInDuration != nil {
+		s.Fade = time.Duration(cfg.FadeInDuration.Seconds) * time.Second
+	} else if cfg.FadeOutDuration != nil {
+		s.Fade = time.Duration(cfg.FadeOutDuration.Seconds) * time.Second
+	}
+	if len(cfg.Where) > 0 {
+		s.GroupID = cfg.Where[0].Group.Rid
+		for _, item := range cfg.Where[0].Items {
+			if item.Rtype == "light" {
+				s.LightIDs = append(s.LightIDs, item.Rid)
+			}
+		}
+	}
+	return s
+}
+
+// smartSceneResource represents the V2 API smart_scene resource
+type smartSceneResource struct {
+	ID       string `json:"id"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Group resourceRef `json:"group"`
+	State string      `json:"state"`
+}
+
+func (r *smartSceneResource) toModel() *models.Schedule {
+	return &models.Schedule{
+		ID:       r.ID,
+		Resource: "smart_scene",
+		Name:     r.Metadata.Name,
+		Kind:     models.ScheduleSmartScene,
+		Enabled:  r.State == "active",
+		GroupID:  r.Group.Rid,
+	}
+}
+
+// toScene lists the smart scene with the scenes of its group
+func (r *smartSceneResource) toScene() *models.Scene {
+	status := "inactive"
+	if r.State == "active" {
+		status = "active"
+	}
+	return &models.Scene{
+		ID:      r.ID,
+		Name:    r.Metadata.Name,
+		RoomID:  r.Group.Rid,
+		Status:  status,
+		IsSmart: true,
+	}
+}
+
+// SetSmartSceneActive activates or deactivates a smart scene
+func (b *HueBridge) SetSmartSceneActive(ctx context.Context, sceneID string, active bool) error {
+	action := "deactivate"
+	if active {
+		action = "activate"
+	}
+	body := map[string]interface{}{"recall": map[string]string{"action": action}}
+	if err := b.updateResource(ctx, "smart_scene", sceneID, body); err != nil {
+		return fmt.Errorf("failed to %s smart scene: %w", action, err)
+	}
+	return nil
+}
+
+var weekdayByName = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// GetSchedules retrieves behavior instances and smart scenes
+func (b *HueBridge) GetSchedules(ctx context.Context) ([]*models.Schedule, error) {
+	var behaviors []behaviorInstanceResource
+	if err := b.listResources(ctx, "behavior_instance", &behaviors); err != nil {
+		return nil, err
+	}
+	var smartScenes []smartSceneResource
+	if err := b.listResources(ctx, "smart_scene", &smartScenes); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*models.Schedule, 0, len(behaviors)+len(smartScenes))
+	for _, raw := range behaviors {
+		schedules = append(schedules, raw.toModel())
+	}
+	for _, raw := range smartScenes {
+		schedules = append(schedules, raw.toModel())
+	}
+	return schedules, nil
+}
+
+// CreateSchedule creates a wake-up or sleep behavior instance
+func (b *HueBridge) CreateSchedule(ctx context.Context, spec ScheduleSpec) (string, error) {
+	body, err := spec.behaviorBody()
+	if err != nil {
+		return "", err
+	}
+	return b.createResource(ctx, "behavior_instance", body)
+}
+
+// DeleteSchedule deletes a behavior instance or smart scene
+func (b *HueBridge) DeleteSchedule(ctx context.Context, resource, id string) error {
+	if resource != "behavior_instance" && resource != "smart_scene" {
+		return fmt.Errorf("cannot delete %s as a schedule", resource)
+	}
+	return b.deleteResource(ctx, resource, id)
+}
+
+// behaviorBody builds the behavior_instance creation request
+func (spec ScheduleSpec) behaviorBody() (map[string]interface{}, error) {
+	if spec.Sun != "" {
+		return nil, fmt.Errorf("cannot create a schedule relative to %s on the bridge", spec.Sun)
+	}
+
+	when := behaviorWhen{}
+	when.TimePoint.Type = "time"
+	when.TimePoint.Time = &behaviorTime{Hour: spec.Hour, Minute: spec.Minute}
+	for _, d := range spec.Days {
+		when.RecurrenceDays = append(when.RecurrenceDays, strings.ToLower(d.String()))
+	}
+
+	where := behaviorWhere{
+		Group: resourceRef{Rid: spec.GroupID, Rtype: spec.GroupType},
+		Items: refs(spec.LightIDs, "light"),
+	}
+	fade := durationJSON{Seconds: int(spec.Fade.Seconds())}
+
+	config := map[string]interface{}{
+		"when":  when,
+		"where": []behaviorWhere{where},
+	}
+	var scriptID string
+	switch spec.Kind {
+	case models.ScheduleWakeUp:
+		scriptID = wakeUpScriptID
+		config["fade_in_duration"] = fade
+		config["end_brightness"] = 100
+		config["style"] = "basic"
+	case models.ScheduleSleep:
+		scriptID = goToSleepScriptID
+		config["fade_out_duration"] = fade
+		config["end_state"] = "turn_off"
+	default:
+		return nil, fmt.Errorf("cannot create a %q schedule", spec.Kind)
+	}
+
+	return map[string]interface{}{
+		"script_id":     scriptID,
+		"enabled":       true,
+		"metadata":      resourceMetadata{Name: spec.Name},
+		"configuration": config,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func TestBehaviorInstanceToModel(t *testing.T) {
+	data := `{
+		"id": "b1",
+		"script_id": "ff8957e3-2eb9-4699-a0c8-ad2cb3ede704",
+		"enabled": true,
+		"metadata": {"name": "Wake up"},
+		"configuration": {
+			"end_brightness": 100,
+			"fade_in_duration": {"seconds": 1800},
+			"when": {
+				"recurrence_days": ["monday", "friday"],
+				"time_point": {"type": "time", "time": {"hour": 6, "minute": 45}}
+			},
+			"where": [{"group": {"rid": "room-1", "rtype": "room"}, "items": [{"rid": "light-1", "rtype": "light"}]}]
+		}
+	}`
+
+	var raw behaviorInstanceResource
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatalf("Failed to parse behavior instance: %v", err)
+	}
+	got := raw.toModel()
+
+	want := &models.Schedule{
+		ID:       "b1",
+		Resource: "behavior_instance",
+		Name:     "Wake up",
+		Kind:     models.ScheduleWakeUp,
+		Enabled:  true,
+		Hour:     6,
+		Minute:   45,
+		HasTime:  true,
+		Days:     []time.Weekday{time.Monday, time.Friday},
+		Fade:     30 * time.Minute,
+		GroupID:  "room-1",
+		LightIDs: []string{"light-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toModel() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScheduleSpecBehaviorBody(t *testing.T) {
+	spec := ScheduleSpec{
+		Kind:      models.ScheduleSleep,
+		Name:      "Lights out",
+		Hour:      23,
+		Days:      models.Weekends,
+		Fade:      time.Minute,
+		GroupID:   "room-1",
+		GroupType: "room",
+	}
+	body, err := spec.behaviorBody()
+	if err != nil {
+		t.Fatalf("behaviorBody failed: %v", err)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Failed to encode body: %v", err)
+	}
+
+	// Round-trip through the resource type used to read schedules back
+	var raw behaviorInstanceResource
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Failed to parse body: %v", err)
+	}
+	got := raw.toModel()
+	if got.Kind != models.ScheduleSleep || got.TimeString() != "23:00" || got.Fade != time.Minute {
+		t.Errorf("Unexpected schedule from body: %+v", got)
+	}
+	if models.DaysString(got.Days) != "weekends" || got.GroupID != "room-1" || len(got.LightIDs) != 0 {
+		t.Errorf("Unexpected days or target from body: %+v", got)
+	}
+
+	spec.Kind = models.ScheduleSmartScene
+	if _, err := spec.behaviorBody(); err == nil {
+		t.Error("Expected smart scenes to be rejected")
+	}
+
+	spec.Kind, spec.Sun = models.ScheduleSleep, models.Sunset
+	if !spec.Local() {
+		t.Error("Expected sunset schedules to be local")
+	}
+	if _, err := spec.behaviorBody(); err == nil {
+		t.Error("Expected sunset schedules to be rejected by the bridge")
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	_ "embed"
+
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// TLSMode selects how the bridge certificate is validated
+type TLSMode string
+
+const (
+	// TLSModeInsecure accepts any certificate
+	TLSModeInsecure TLSMode = "insecure"
+	// TLSModeCA verifies the chain against the Signify root CA and
+	// requires the certificate CN to be the bridge ID
+	TLSModeCA TLSMode = "ca"
+	// TLSModeTOFU pins the certificate fingerprint seen on first use.
+	// This is the default for paired bridges.
+	TLSModeTOFU TLSMode = "tofu"
+)
+
+var (
+	ErrCertificateMismatch = errors.New("bridge certificate does not match the pinned fingerprint")
+	ErrCertificateBridgeID = errors.New("bridge certificate CN does not match the bridge ID")
+)
+
+// TLSPolicy describes how to validate a bridge's certificate
+type TLSPolicy struct {
+	Mode TLSMode
+	// RootCAs holds the Signify root CA (required for TLSModeCA)
+	RootCAs *x509.CertPool
+	// Fingerprint is the pinned SHA-256 fingerprint (TLSModeTOFU).
+	// Empty means nothing is pinned yet and the first certificate is trusted.
+	Fingerprint string
+}
+
+// signifyRootCA is the root CA of the Hue bridge certificates, published
+// by Signify (CN=root-bridge, valid until 2038)
+//
+//go:embed signify_root_ca.pem
+var signifyRootCA []byte
+
+// SignifyRootCAs returns a pool with the bundled Signify root CA
+func SignifyRootCAs() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(signifyRootCA)
+	return pool
+}
+
+// PolicyFor builds the TLS policy for a configured mode. In CA mode the
+// bundled Signify root CA is used unless caFile replaces it, and
+// fingerprint is only used in TOFU mode.
+func PolicyFor(mode, fingerprint, caFile string) (TLSPolicy, error) {
+	switch TLSMode(mode) {
+	case TLSModeInsecure:
+		return TLSPolicy{Mode: TLSModeInsecure}, nil
+	case "", TLSModeTOFU:
+		return TLSPolicy{Mode: TLSModeTOFU, Fingerprint: fingerprint}, nil
+	case TLSModeCA:
+		if caFile == "" {
+			return TLSPolicy{Mode: TLSModeCA, RootCAs: SignifyRootCAs()}, nil
+		}
+		pool, err := LoadRootCAs(caFile)
+		if err != nil {
+			return TLSPolicy{}, err
+		}
+		return TLSPolicy{Mode: TLSModeCA, RootCAs: pool}, nil
+	}
+	return TLSPolicy{}, fmt.Errorf("unknown tls_mode %q", mode)
+}
+
+// LoadRootCAs reads PEM encoded CA certificates from a file
+func LoadRootCAs(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// CertFingerprint returns the hex SHA-256 fingerprint of a DER certificate
+func CertFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// certObserver remembers the leaf fingerprint of the last handshake
+type certObserver struct {
+	mu          sync.Mutex
+	fingerprint string
+}
+
+func (o *certObserver) set(fp string) {
+	o.mu.Lock()
+	o.fingerprint = fp
+	o.mu.Unlock()
+}
+
+func (o *certObserver) get() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.fingerprint
+}
+
+// newTLSConfig builds a tls.Config enforcing the policy for a bridge.
+// Bridges are addressed by IP and their certificates name the bridge ID,
+// so standard hostname verification is replaced by a custom check.
+func newTLSConfig(policy TLSPolicy, bridgeID string, observer *certObserver) *tls.Config {
+	// In TOFU mode with nothing pinned yet, the first certificate seen
+	// becomes the pin for the lifetime of this config
+	var pinMu sync.Mutex
+	return &tls.Config{
+		// Verification is done in VerifyPeerCertificate below
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("bridge presented no certificate")
+			}
+			fp := CertFingerprint(rawCerts[0])
+			if observer != nil {
+				observer.set(fp)
+			}
+			if policy.Mode == TLSModeTOFU {
+				pinMu.Lock()
+				defer pinMu.Unlock()
+				if policy.Fingerprint == "" {
+					policy.Fingerprint = fp
+				}
+			}
+			return verifyBridgeCert(policy, bridgeID, rawCerts, fp)
+		},
+	}
+}
+
+// verifyBridgeCert validates the presented chain according to the policy
+func verifyBridgeCert(policy TLSPolicy, bridgeID string, rawCerts [][]byte, fingerprint string) error {
+	switch policy.Mode {
+	case TLSModeTOFU:
+		if policy.Fingerprint != "" && !strings.EqualFold(policy.Fingerprint, fingerprint) {
+			return ErrCertificateMismatch
+		}
+		return nil
+
+	case TLSModeCA:
+		if policy.RootCAs == nil {
+			return errors.New("CA verification requested but no root CA is configured")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse bridge certificate: %w", err)
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         policy.RootCAs,
+			Intermediates: intermediates,
+		}); err != nil {
+			return fmt.Errorf("bridge certificate verification failed: %w", err)
+		}
+		if bridgeID != "" && !strings.EqualFold(certs[0].Subject.CommonName, bridgeID) {
+			return ErrCertificateBridgeID
+		}
+		return nil
+	}
+
+	return nil
+}
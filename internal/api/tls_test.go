@@ -0,0 +1,101 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCert(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return cert, key, der
+}
+
+func TestVerifyBridgeCert_CA(t *testing.T) {
+	root, rootKey, _ := newTestCert(t, "root-bridge", nil, nil, true)
+	_, _, leaf := newTestCert(t, "001788fffe123456", root, rootKey, false)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(root)
+	policy := TLSPolicy{Mode: TLSModeCA, RootCAs: pool}
+
+	if err := verifyBridgeCert(policy, "001788FFFE123456", [][]byte{leaf}, CertFingerprint(leaf)); err != nil {
+		t.Errorf("Expected valid certificate, got %v", err)
+	}
+
+	err := verifyBridgeCert(policy, "001788FFFE999999", [][]byte{leaf}, CertFingerprint(leaf))
+	if !errors.Is(err, ErrCertificateBridgeID) {
+		t.Errorf("Expected ErrCertificateBridgeID, got %v", err)
+	}
+
+	// A self-signed certificate is not trusted
+	_, _, rogue := newTestCert(t, "001788fffe123456", nil, nil, false)
+	if err := verifyBridgeCert(policy, "001788FFFE123456", [][]byte{rogue}, CertFingerprint(rogue)); err == nil {
+		t.Error("Expected untrusted certificate to be rejected")
+	}
+}
+
+func TestPolicyForBundledCA(t *testing.T) {
+	policy, err := PolicyFor("ca", "", "")
+	if err != nil {
+		t.Fatalf("Expected the bundled CA without ca_file, got %v", err)
+	}
+	if policy.Mode != TLSModeCA || policy.RootCAs == nil {
+		t.Fatalf("Expected a CA policy with root CAs, got %+v", policy)
+	}
+
+	if _, err := PolicyFor("ca", "", "/nonexistent/ca.pem"); err == nil {
+		t.Error("Expected an error for a missing ca_file")
+	}
+}
+
+func TestVerifyBridgeCert_TOFU(t *testing.T) {
+	_, _, cert := newTestCert(t, "bridge", nil, nil, false)
+	_, _, other := newTestCert(t, "bridge", nil, nil, false)
+	fp := CertFingerprint(cert)
+
+	// Nothing pinned yet: anything is accepted
+	if err := verifyBridgeCert(TLSPolicy{Mode: TLSModeTOFU}, "", [][]byte{other}, CertFingerprint(other)); err != nil {
+		t.Errorf("Expected first use to be trusted, got %v", err)
+	}
+
+	policy := TLSPolicy{Mode: TLSModeTOFU, Fingerprint: fp}
+	if err := verifyBridgeCert(policy, "", [][]byte{cert}, fp); err != nil {
+		t.Errorf("Expected pinned certificate to match, got %v", err)
+	}
+	err := verifyBridgeCert(policy, "", [][]byte{other}, CertFingerprint(other))
+	if !errors.Is(err, ErrCertificateMismatch) {
+		t.Errorf("Expected ErrCertificateMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,754 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// API versions of a bridge
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// ErrUnsupportedV1 is returned for features the V1 API doesn't have
+var ErrUnsupportedV1 = errors.New("not supported by the bridge's V1 API")
+
+// DetectAPIVersion probes the bridge for the CLIP v2 API. Only bridges
+// that answer 404 on it have nothing but the V1 API: a bridge that can't be
+// reached is an error, not a reason to fall back to plain HTTP.
+func (b *HueBridge) DetectAPIVersion(ctx context.Context) (string, error) {
+	resp, err := b.doRequest(ctx, "GET", "/clip/v2/resource/bridge", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to probe API version: %w", err)
+	}
+	_ = resp.Body.Close() // Error ignored: only the status matters
+	if resp.StatusCode == http.StatusNotFound {
+		return APIVersionV1, nil
+	}
+	return APIVersionV2, nil
+}
+
+// V1Bridge is a connection to a bridge through the V1 API, for old round
+// bridges without CLIP v2. It has no event stream, entertainment areas or
+// gradients, and lights are their own devices.
+type V1Bridge struct {
+	requestPipeline
+
+	host     string
+	username string
+	client   *http.Client
+}
+
+// Compile-time check that V1Bridge implements BridgeClient
+var _ BridgeClient = (*V1Bridge)(nil)
+
+// NewV1Bridge creates a V1 API client
+func NewV1Bridge(host, username, bridgeID string) *V1Bridge {
+	return &V1Bridge{
+		requestPipeline: newRequestPipeline(bridgeID),
+		host:            host,
+		username:        username,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Host returns the bridge host
+func (b *V1Bridge) Host() string {
+	return b.host
+}
+
+// BridgeID returns the bridge identifier
+func (b *V1Bridge) BridgeID() string {
+	return b.bridgeID
+}
+
+// v1Error is an error entry of a V1 response
+type v1Error struct {
+	Error *struct {
+		Type        int    `json:"type"`
+		Address     string `json:"address"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+// do performs a request on /api/<username><path> and decodes the response
+// into out. The V1 API reports errors as a list in place of the response.
+func (b *V1Bridge) do(ctx context.Context, method, path string, payload, out interface{}) (err error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	resp, err := b.send(ctx, b.client, method, path, body, func(body io.Reader) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, bridgeURL("http", b.host, "/api/"+b.username+path), body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", withoutURL(err))
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reach bridge: %w", withoutURL(err))
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	var results []v1Error
+	if json.Unmarshal(data, &results) == nil {
+		for _, r := range results {
+			if r.Error != nil {
+				return fmt.Errorf("API error: %s", r.Error.Description)
+			}
+		}
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// withoutURL strips the URL from a request error: it holds the username,
+// which must not end up in error messages and logs
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// v1Light is a light of the V1 API
+type v1Light struct {
+	Name  string `json:"name"`
+	State struct {
+		On        bool      `json:"on"`
+		Bri       *uint8    `json:"bri"`
+		Hue       *uint16   `json:"hue"`
+		Sat       *uint8    `json:"sat"`
+		XY        []float64 `json:"xy"`
+		CT        *uint16   `json:"ct"`
+		ColorMode string    `json:"colormode"`
+		Reachable bool      `json:"reachable"`
+	} `json:"state"`
+	Capabilities struct {
+		Control struct {
+			CT *struct {
+				Min int `json:"min"`
+				Max int `json:"max"`
+			} `json:"ct"`
+			ColorGamutType string       `json:"colorgamuttype"`
+			ColorGamut     [][2]float64 `json:"colorgamut"`
+		} `json:"control"`
+	} `json:"capabilities"`
+}
+
+// toModel converts a V1 light to a models.Light. Lights are their own
+// device, as the V1 API has no devices.
+func (l *v1Light) toModel(id string) *models.Light {
+	s := l.State
+	light := &models.Light{
+		ID:                id,
+		Name:              l.Name,
+		On:                s.On,
+		Reachable:         s.Reachable,
+		DeviceID:          id,
+		SupportsColor:     s.XY != nil || s.Hue != nil,
+		SupportsColorTemp: s.CT != nil,
+		OnOffOnly:         s.Bri == nil,
+	}
+	if s.Bri != nil {
+		light.Brightness = *s.Bri
+	}
+	if ct := l.Capabilities.Control.CT; ct != nil {
+		light.MirekMin = ct.Min
+		light.MirekMax = ct.Max
+	}
+	if g := l.Capabilities.Control.ColorGamut; len(g) == 3 {
+		light.Gamut = &models.Gamut{
+			Type:  l.Capabilities.Control.ColorGamutType,
+			Red:   models.GamutPoint{X: g[0][0], Y: g[0][1]},
+			Green: models.GamutPoint{X: g[1][0], Y: g[1][1]},
+			Blue:  models.GamutPoint{X: g[2][0], Y: g[2][1]},
+		}
+	}
+
+	brightness := light.Brightness
+	if brightness == 0 {
+		brightness = 254
+	}
+	switch {
+	case s.ColorMode == "ct" && s.CT != nil:
+		light.Color = models.NewColorFromMirek(*s.CT, brightness)
+	case s.ColorMode == "hs" && s.Hue != nil && s.Sat != nil:
+		light.Color = models.NewColorFromHS(*s.Hue, *s.Sat, brightness)
+	case len(s.XY) == 2:
+		light.Color = models.NewColorFromXY(s.XY[0], s.XY[1], brightness)
+	case s.CT != nil:
+		light.Color = models.NewColorFromMirek(*s.CT, brightness)
+	}
+	return light
+}
+
+// v1Group is a room, zone or other group of lights
+type v1Group struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Class  string   `json:"class"`
+	Lights []string `json:"lights"`
+}
+
+// toModel converts a V1 group to a models.Room. The V1 class "Living room"
+// becomes the archetype "living_room".
+func (g *v1Group) toModel(id string) *models.Room {
+	room := &models.Room{
+		ID:             id,
+		Name:           g.Name,
+		Archetype:      strings.ReplaceAll(strings.ToLower(g.Class), " ", "_"),
+		GroupedLightID: id,
+	}
+	if g.Type == "Zone" {
+		room.LightIDs = g.Lights
+	} else {
+		room.DeviceIDs = g.Lights
+	}
+	return room
+}
+
+// v1Scene is a scene of the V1 API
+type v1Scene struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Group string `json:"group"`
+}
+
+// sortedKeys returns the keys of a V1 resource map in ID order, numeric IDs
+// sorting as numbers
+func sortedKeys[T interface{}](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// GetLights retrieves all lights from the bridge
+func (b *V1Bridge) GetLights(ctx context.Context) ([]*models.Light, error) {
+	var raw map[string]*v1Light
+	if err := b.do(ctx, "GET", "/lights", nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to get lights: %w", err)
+	}
+	lights := make([]*models.Light, 0, len(raw))
+	for _, id := range sortedKeys(raw) {
+		lights = append(lights, raw[id].toModel(id))
+	}
+	return lights, nil
+}
+
+// GetLight retrieves the current state of a single light
+func (b *V1Bridge) GetLight(ctx context.Context, lightID string) (*models.Light, error) {
+	var raw v1Light
+	if err := b.do(ctx, "GET", "/lights/"+lightID, nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to get light: %w", err)
+	}
+	return raw.toModel(lightID), nil
+}
+
+// GetDeviceName returns no name, as V1 lights are their own device
+func (b *V1Bridge) GetDeviceName(ctx context.Context, deviceID string) (string, error) {
+	return "", nil
+}
+
+// getGroups retrieves the groups of a V1 type ("Room" or "Zone")
+func (b *V1Bridge) getGroups(ctx context.Context, groupType string) ([]*models.Room, error) {
+	var raw map[string]*v1Group
+	if err := b.do(ctx, "GET", "/groups", nil, &raw); err != nil {
+		return nil, err
+	}
+	var rooms []*models.Room
+	for _, id := range sortedKeys(raw) {
+		if raw[id].Type == groupType {
+			rooms = append(rooms, raw[id].toModel(id))
+		}
+	}
+	return rooms, nil
+}
+
+// GetRooms retrieves all rooms from the bridge. The device IDs of V1 rooms
+// are their light IDs.
+func (b *V1Bridge) GetRooms(ctx context.Context) ([]*models.Room, error) {
+	rooms, err := b.getGroups(ctx, "Room")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms: %w", err)
+	}
+	return rooms, nil
+}
+
+// GetZones retrieves all zones from the bridge
+func (b *V1Bridge) GetZones(ctx context.Context) ([]*models.Room, error) {
+	zones, err := b.getGroups(ctx, "Zone")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zones: %w", err)
+	}
+	return zones, nil
+}
+
+// GetScenes retrieves all scenes from the bridge. V1 scenes don't report
+// whether they are active, nor their light states without a request each.
+func (b *V1Bridge) GetScenes(ctx context.Context) ([]*models.Scene, error) {
+	var raw map[string]*v1Scene
+	if err := b.do(ctx, "GET", "/scenes", nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to get scenes: %w", err)
+	}
+	scenes := make([]*models.Scene, 0, len(raw))
+	for _, id := range sortedKeys(raw) {
+		s := raw[id]
+		scenes = append(scenes, &models.Scene{
+			ID:     id,
+			Name:   s.Name,
+			RoomID: s.Group,
+			Status: "inactive",
+		})
+	}
+	return scenes, nil
+}
+
+// FetchAll retrieves all resources from the bridge
+func (b *V1Bridge) FetchAll(ctx context.Context) ([]*models.Room, []*models.Scene, error) {
+	return b.FetchAllProgress(ctx, nil)
+}
+
+// FetchAllProgress retrieves all resources from the bridge, loading each
+// resource type concurrently and reporting it to progress as it completes.
+// Lights are reported as devices too.
+func (b *V1Bridge) FetchAllProgress(ctx context.Context, progress FetchProgress) ([]*models.Room, []*models.Scene, error) {
+	var (
+		wg         sync.WaitGroup
+		progressMu sync.Mutex
+		rooms      []*models.Room
+		lights     []*models.Light
+		scenes     []*models.Scene
+		roomsErr   error
+		lightsErr  error
+		scenesErr  error
+	)
+	report := func(resource string, count int) {
+		if progress == nil {
+			return
+		}
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		progress(resource, count)
+	}
+	fetch := func(load func() (int, error), resources ...string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if count, err := load(); err == nil {
+				for _, resource := range resources {
+					report(resource, count)
+				}
+			}
+		}()
+	}
+
+	fetch(func() (int, error) {
+		rooms, roomsErr = b.GetRooms(ctx)
+		return len(rooms), roomsErr
+	}, FetchRooms)
+	fetch(func() (int, error) {
+		lights, lightsErr = b.GetLights(ctx)
+		return len(lights), lightsErr
+	}, FetchLights, FetchDevices)
+	fetch(func() (int, error) {
+		scenes, scenesErr = b.GetScenes(ctx)
+		return len(scenes), scenesErr
+	}, FetchScenes)
+	wg.Wait()
+
+	if roomsErr != nil {
+		return nil, nil, fmt.Errorf("failed to fetch rooms: %w", roomsErr)
+	}
+	if lightsErr != nil {
+		return nil, nil, fmt.Errorf("failed to fetch lights: %w", lightsErr)
+	}
+
+	roomByID := make(map[string]*models.Room)
+	for _, room := range rooms {
+		roomByID[room.ID] = room
+	}
+	rooms = assignLightsToRooms(lights, rooms)
+
+	if scenesErr != nil {
+		return rooms, nil, fmt.Errorf("%w: %w", ErrScenesUnavailable, scenesErr)
+	}
+	for _, scene := range scenes {
+		if room, ok := roomByID[scene.RoomID]; ok {
+			scene.RoomName = room.Name
+		}
+	}
+	return rooms, scenes, nil
+}
+
+// setLightState sends a state change to a light
+func (b *V1Bridge) setLightState(ctx context.Context, lightID string, state map[string]interface{}) error {
+	if err := b.do(ctx, "PUT", "/lights/"+lightID+"/state", state, nil); err != nil {
+		return fmt.Errorf("failed to set light state: %w", err)
+	}
+	return nil
+}
+
+// SetLightOn turns a light on or off
+func (b *V1Bridge) SetLightOn(ctx context.Context, lightID string, on bool) error {
+	return b.setLightState(ctx, lightID, map[string]interface{}{"on": on})
+}
+
+// SetLightBrightness sets a light's brightness (0-100). The V1 API goes
+// from 1 to 254, 0 being its minimum like in V2.
+func (b *V1Bridge) SetLightBrightness(ctx context.Context, lightID string, brightness int) error {
+	bri := max(1, models.PctToLevel(float64(brightness)))
+	return b.setLightState(ctx, lightID, map[string]interface{}{"bri": bri})
+}
+
+// SetLightColorTemp sets a light's color temperature in mirek (153-500)
+func (b *V1Bridge) SetLightColorTemp(ctx context.Context, lightID string, mirek int) error {
+	mirek = max(153, min(500, mirek))
+	return b.setLightState(ctx, lightID, map[string]interface{}{"ct": mirek})
+}
+
+// SetLightColorXY sets a light's color using XY coordinates
+func (b *V1Bridge) SetLightColorXY(ctx context.Context, lightID string, x, y float64) error {
+	return b.setLightState(ctx, lightID, map[string]interface{}{"xy": []float64{x, y}})
+}
+
+// SetLightColorHS sets a light's hue (0-65535) and saturation (0-254),
+// which the V1 API takes as is
+func (b *V1Bridge) SetLightColorHS(ctx context.Context, lightID string, hue uint16, sat uint8) error {
+	return b.setLightState(ctx, lightID, map[string]interface{}{"hue": hue, "sat": sat})
+}
+
+// SetLightGradient is not supported by the V1 API
+func (b *V1Bridge) SetLightGradient(ctx context.Context, lightID string, points [][2]float64) error {
+	return ErrUnsupportedV1
+}
+
+// IdentifyLight makes a light blink once so the physical bulb can be spotted
+func (b *V1Bridge) IdentifyLight(ctx context.Context, lightID string) error {
+	return b.setLightState(ctx, lightID, map[string]interface{}{"alert": "select"})
+}
+
+// SetLightArchetype is not supported by the V1 API
+func (b *V1Bridge) SetLightArchetype(ctx context.Context, lightID, archetype string) error {
+	return ErrUnsupportedV1
+}
+
+// RenameLight changes the name of a light
+func (b *V1Bridge) RenameLight(ctx context.Context, lightID, name string) error {
+	if err := b.do(ctx, "PUT", "/lights/"+lightID, map[string]interface{}{"name": name}, nil); err != nil {
+		return fmt.Errorf("failed to rename light: %w", err)
+	}
+	return nil
+}
+
+// v1HomeGroupID is the V1 group holding every light
+const v1HomeGroupID = "0"
+
+// HomeGroupedLightID returns group 0, which holds every light
+func (b *V1Bridge) HomeGroupedLightID(ctx context.Context) (string, error) {
+	return v1HomeGroupID, nil
+}
+
+// SetGroupedLightOn turns all lights of a group on or off. V1 groups are
+// their own grouped light.
+func (b *V1Bridge) SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) error {
+	if err := b.do(ctx, "PUT", "/groups/"+groupedLightID+"/action", map[string]interface{}{"on": on}, nil); err != nil {
+		return fmt.Errorf("failed to set group state: %w", err)
+	}
+	return nil
+}
+
+// FadeGroupedLightOn turns a group on or off over duration. V1
+// transitions count in tenths of a second.
+func (b *V1Bridge) FadeGroupedLightOn(ctx context.Context, groupedLightID string, on bool, duration time.Duration) error {
+	body := map[string]interface{}{"on": on, "transitiontime": min(65535, int(duration/(100*time.Millisecond)))}
+	if err := b.do(ctx, "PUT", "/groups/"+groupedLightID+"/action", body, nil); err != nil {
+		return fmt.Errorf("failed to set group state: %w", err)
+	}
+	return nil
+}
+
+// SetGroupedLightBrightness sets every light of a group to brightness
+// percent (0-100)
+func (b *V1Bridge) SetGroupedLightBrightness(ctx context.Context, groupedLightID string, brightness int) error {
+	bri := max(1, models.PctToLevel(float64(brightness)))
+	if err := b.do(ctx, "PUT", "/groups/"+groupedLightID+"/action", map[string]interface{}{"bri": bri}, nil); err != nil {
+		return fmt.Errorf("failed to set group state: %w", err)
+	}
+	return nil
+}
+
+// ActivateScene recalls a scene on its group, or on every light for light
+// scenes
+func (b *V1Bridge) ActivateScene(ctx context.Context, sceneID string) error {
+	return b.recallScene(ctx, sceneID, map[string]interface{}{"scene": sceneID})
+}
+
+// ActivateSceneOver recalls a scene, fading to it over duration
+func (b *V1Bridge) ActivateSceneOver(ctx context.Context, sceneID string, duration time.Duration) error {
+	return b.recallScene(ctx, sceneID, map[string]interface{}{
+		"scene":          sceneID,
+		"transitiontime": min(65535, int(duration/(100*time.Millisecond))),
+	})
+}
+
+// recallScene sends action to the group of a scene
+func (b *V1Bridge) recallScene(ctx context.Context, sceneID string, action map[string]interface{}) error {
+	var scene v1Scene
+	if err := b.do(ctx, "GET", "/scenes/"+sceneID, nil, &scene); err != nil {
+		return fmt.Errorf("failed to get scene: %w", err)
+	}
+	group := scene.Group
+	if group == "" {
+		group = v1HomeGroupID
+	}
+	if err := b.do(ctx, "PUT", "/groups/"+group+"/action", action, nil); err != nil {
+		return fmt.Errorf("failed to activate scene: %w", err)
+	}
+	return nil
+}
+
+// StopSceneDynamics is not supported by the V1 API, which has no dynamic
+// scenes
+func (b *V1Bridge) StopSceneDynamics(ctx context.Context, sceneID string) error {
+	return ErrUnsupportedV1
+}
+
+// SetSmartSceneActive is not supported by the V1 API, which has no smart
+// scenes
+func (b *V1Bridge) SetSmartSceneActive(ctx context.Context, sceneID string, active bool) error {
+	return ErrUnsupportedV1
+}
+
+// CreateScene creates a group scene with the given light states
+func (b *V1Bridge) CreateScene(ctx context.Context, name, groupID, groupType string, actions []SceneAction) (string, error) {
+	states := make(map[string]map[string]interface{}, len(actions))
+	for _, a := range actions {
+		state := make(map[string]interface{})
+		if a.On != nil {
+			state["on"] = *a.On
+		}
+		if a.Brightness != nil {
+			state["bri"] = max(1, models.PctToLevel(*a.Brightness))
+		}
+		if a.Mirek != nil {
+			state["ct"] = *a.Mirek
+		} else if a.XY != nil {
+			state["xy"] = []float64{a.XY[0], a.XY[1]}
+		}
+		states[a.LightID] = state
+	}
+	return b.create(ctx, "/scenes", map[string]interface{}{
+		"name":        name,
+		"type":        "GroupScene",
+		"group":       groupID,
+		"recycle":     false,
+		"lightstates": states,
+	})
+}
+
+// DeleteScene deletes a scene
+func (b *V1Bridge) DeleteScene(ctx context.Context, sceneID string) error {
+	if err := b.do(ctx, "DELETE", "/scenes/"+sceneID, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete scene: %w", err)
+	}
+	return nil
+}
+
+// create posts a new resource and returns its ID
+func (b *V1Bridge) create(ctx context.Context, path string, payload interface{}) (string, error) {
+	var results []struct {
+		Success struct {
+			ID string `json:"id"`
+		} `json:"success"`
+	}
+	if err := b.do(ctx, "POST", path, payload, &results); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", strings.TrimPrefix(path, "/"), err)
+	}
+	if len(results) == 0 || results[0].Success.ID == "" {
+		return "", fmt.Errorf("failed to create %s: no ID in response", strings.TrimPrefix(path, "/"))
+	}
+	return results[0].Success.ID, nil
+}
+
+// createGroup creates a room or zone. V2 archetypes such as "living_room"
+// are sent as the V1 class "Living room".
+func (b *V1Bridge) createGroup(ctx context.Context, groupType, name, archetype string, lightIDs []string) (string, error) {
+	payload := map[string]interface{}{
+		"name":   name,
+		"type":   groupType,
+		"lights": lightIDs,
+	}
+	if archetype != "" {
+		payload["class"] = models.ArchetypeLabel(archetype)
+	}
+	return b.create(ctx, "/groups", payload)
+}
+
+// updateGroup renames a group and sets its lights
+func (b *V1Bridge) updateGroup(ctx context.Context, groupID, name string, lightIDs []string) error {
+	payload := map[string]interface{}{"lights": lightIDs}
+	if name != "" {
+		payload["name"] = name
+	}
+	if err := b.do(ctx, "PUT", "/groups/"+groupID, payload, nil); err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+	return nil
+}
+
+// deleteGroup deletes a room or zone
+func (b *V1Bridge) deleteGroup(ctx context.Context, groupID string) error {
+	if err := b.do(ctx, "DELETE", "/groups/"+groupID, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	return nil
+}
+
+// CreateRoom creates a room with the given lights, which are their own
+// devices in the V1 API
+func (b *V1Bridge) CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error) {
+	return b.createGroup(ctx, "Room", name, archetype, deviceIDs)
+}
+
+// UpdateRoom renames a room and sets its lights
+func (b *V1Bridge) UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error {
+	return b.updateGroup(ctx, roomID, name, deviceIDs)
+}
+
+// DeleteRoom deletes a room
+func (b *V1Bridge) DeleteRoom(ctx context.Context, roomID string) error {
+	return b.deleteGroup(ctx, roomID)
+}
+
+// CreateZone creates a zone with the given lights
+func (b *V1Bridge) CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error) {
+	return b.createGroup(ctx, "Zone", name, archetype, lightIDs)
+}
+
+// UpdateZone renames a zone and sets its lights
+func (b *V1Bridge) UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error {
+	return b.updateGroup(ctx, zoneID, name, lightIDs)
+}
+
+// DeleteZone deletes a zone
+func (b *V1Bridge) DeleteZone(ctx context.Context, zoneID string) error {
+	return b.deleteGroup(ctx, zoneID)
+}
+
+// v1SensorKinds are the V1 sensor types listed as battery devices. Motion
+// sensors also show up as light level and temperature sensors, which are
+// left out.
+var v1SensorKinds = map[string]models.DeviceKind{
+	"ZLLSwitch":         models.DeviceSwitch,
+	"ZLLRelativeRotary": models.DeviceSwitch,
+	"ZLLPresence":       models.DeviceMotionSensor,
+}
+
+// v1Sensor is a sensor of the V1 API
+type v1Sensor struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	ProductName string `json:"productname"`
+	Config      struct {
+		Battery *int `json:"battery"`
+	} `json:"config"`
+}
+
+// GetBatteryDevices returns the switches and motion sensors with a battery
+func (b *V1Bridge) GetBatteryDevices(ctx context.Context) ([]*models.Device, error) {
+	var raw map[string]v1Sensor
+	if err := b.do(ctx, "GET", "/sensors", nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to get sensors: %w", err)
+	}
+
+	var devices []*models.Device
+	for _, id := range sortedKeys(raw) {
+		sensor := raw[id]
+		kind, ok := v1SensorKinds[sensor.Type]
+		if !ok || sensor.Config.Battery == nil {
+			continue
+		}
+		devices = append(devices, &models.Device{
+			ID:           id,
+			Name:         sensor.Name,
+			Product:      sensor.ProductName,
+			Kind:         kind,
+			BatteryLevel: *sensor.Config.Battery,
+		})
+	}
+	sortDevices(devices)
+	return devices, nil
+}
+
+// GetEntertainmentAreas returns no areas, the V1 API has none
+func (b *V1Bridge) GetEntertainmentAreas(ctx context.Context) ([]*models.EntertainmentArea, error) {
+	return nil, nil
+}
+
+// SetEntertainmentActive is not supported by the V1 API
+func (b *V1Bridge) SetEntertainmentActive(ctx context.Context, areaID string, active bool) error {
+	return ErrUnsupportedV1
+}
+
+// GetSchedules returns no schedules: V1 schedules are not behavior
+// instances or smart scenes. Local schedules still work.
+func (b *V1Bridge) GetSchedules(ctx context.Context) ([]*models.Schedule, error) {
+	return nil, nil
+}
+
+// CreateSchedule is not supported by the V1 API
+func (b *V1Bridge) CreateSchedule(ctx context.Context, spec ScheduleSpec) (string, error) {
+	return "", ErrUnsupportedV1
+}
+
+// DeleteSchedule is not supported by the V1 API
+func (b *V1Bridge) DeleteSchedule(ctx context.Context, resource, id string) error {
+	return ErrUnsupportedV1
+}
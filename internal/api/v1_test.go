@@ -0,0 +1,260 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// v1Server serves canned V1 responses by method and path, recording the
+// bodies of commands
+func v1Server(t *testing.T, responses map[string]string) (*V1Bridge, map[string]string) {
+	t.Helper()
+	var mu sync.Mutex
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/api/user")
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[key] = string(data)
+		mu.Unlock()
+		resp, ok := responses[key]
+		if !ok {
+			resp = `[{"success":{}}]`
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(server.Close)
+	return NewV1Bridge(strings.TrimPrefix(server.URL, "http://"), "user", "bridge-1"), bodies
+}
+
+func TestV1FetchAll(t *testing.T) {
+	b, _ := v1Server(t, map[string]string{
+		"GET /lights": `{
+			"1": {"name": "Ceiling", "state": {"on": true, "bri": 127, "ct": 366, "colormode": "ct", "reachable": true}},
+			"2": {"name": "Lamp", "state": {"on": false, "bri": 254, "hue": 10000, "sat": 200, "xy": [0.5, 0.4], "ct": 300, "colormode": "hs", "reachable": true}},
+			"10": {"name": "Plug", "state": {"on": true, "reachable": false}}
+		}`,
+		"GET /groups": `{
+			"1": {"name": "Living", "type": "Room", "class": "Living room", "lights": ["1", "2"]},
+			"2": {"name": "Reading", "type": "Zone", "lights": ["2"]},
+			"3": {"name": "Sync", "type": "Entertainment", "lights": ["1"]}
+		}`,
+		"GET /scenes": `{"abc": {"name": "Relax", "type": "GroupScene", "group": "1"}}`,
+	})
+
+	var mu sync.Mutex
+	reported := make(map[string]int)
+	rooms, scenes, err := b.FetchAllProgress(context.Background(), func(resource string, count int) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported[resource] = count
+	})
+	if err != nil {
+		t.Fatalf("FetchAll returned error: %v", err)
+	}
+	if reported[FetchRooms] != 1 || reported[FetchLights] != 3 || reported[FetchDevices] != 3 || reported[FetchScenes] != 1 {
+		t.Errorf("Unexpected progress: %v", reported)
+	}
+
+	if len(rooms) != 2 || rooms[0].Name != "Living" || rooms[1].ID != OtherRoomID {
+		t.Fatalf("Expected the room and the other lights, got %+v", rooms)
+	}
+	living := rooms[0]
+	if living.Archetype != "living_room" || living.GroupedLightID != "1" || len(living.Lights) != 2 || !living.AnyOn || living.AllOn {
+		t.Errorf("Unexpected room: %+v", living)
+	}
+
+	ceiling, lamp, plug := living.Lights[0], living.Lights[1], rooms[1].Lights[0]
+	if ceiling.Brightness != 127 || ceiling.Color == nil || ceiling.Color.Mode != models.ColorModeColorTemp || ceiling.Color.Mirek != 366 || ceiling.SupportsColor {
+		t.Errorf("Unexpected ceiling light: %+v", ceiling)
+	}
+	if lamp.Color == nil || lamp.Color.Mode != models.ColorModeHS || lamp.Color.Hue != 10000 || !lamp.SupportsColor || !lamp.SupportsColorTemp {
+		t.Errorf("Unexpected lamp: %+v", lamp)
+	}
+	if !plug.OnOffOnly || plug.Reachable || plug.Color != nil {
+		t.Errorf("Unexpected plug: %+v", plug)
+	}
+
+	if len(scenes) != 1 || scenes[0].RoomID != "1" || scenes[0].RoomName != "Living" {
+		t.Errorf("Unexpected scenes: %+v", scenes)
+	}
+
+	zones, err := b.GetZones(context.Background())
+	if err != nil || len(zones) != 1 || zones[0].LightIDs[0] != "2" {
+		t.Errorf("Unexpected zones: %+v (%v)", zones, err)
+	}
+}
+
+func TestV1Commands(t *testing.T) {
+	b, bodies := v1Server(t, map[string]string{
+		"GET /scenes/abc": `{"name": "Relax", "type": "GroupScene", "group": "4"}`,
+		"GET /scenes/def": `{"name": "Movie", "type": "GroupScene", "group": "6"}`,
+		"POST /groups":    `[{"success": {"id": "7"}}]`,
+	})
+	ctx := context.Background()
+
+	if err := b.SetLightBrightness(ctx, "1", 50); err != nil {
+		t.Fatalf("SetLightBrightness returned error: %v", err)
+	}
+	if err := b.SetLightColorHS(ctx, "2", 1000, 100); err != nil {
+		t.Fatalf("SetLightColorHS returned error: %v", err)
+	}
+	if err := b.SetGroupedLightOn(ctx, "3", false); err != nil {
+		t.Fatalf("SetGroupedLightOn returned error: %v", err)
+	}
+	if err := b.ActivateScene(ctx, "abc"); err != nil {
+		t.Fatalf("ActivateScene returned error: %v", err)
+	}
+	if err := b.FadeGroupedLightOn(ctx, "5", true, 30*time.Second); err != nil {
+		t.Fatalf("FadeGroupedLightOn returned error: %v", err)
+	}
+	if err := b.ActivateSceneOver(ctx, "def", 5*time.Second); err != nil {
+		t.Fatalf("ActivateSceneOver returned error: %v", err)
+	}
+	if err := b.SetGroupedLightBrightness(ctx, "8", 40); err != nil {
+		t.Fatalf("SetGroupedLightBrightness returned error: %v", err)
+	}
+	id, err := b.CreateRoom(ctx, "Office", "kids_bedroom", []string{"1"})
+	if err != nil || id != "7" {
+		t.Fatalf("CreateRoom returned %q, %v", id, err)
+	}
+
+	want := map[string]string{
+		"PUT /lights/1/state":  `{"bri":127}`,
+		"PUT /lights/2/state":  `{"hue":1000,"sat":100}`,
+		"PUT /groups/3/action": `{"on":false}`,
+		"PUT /groups/4/action": `{"scene":"abc"}`,
+		"PUT /groups/5/action": `{"on":true,"transitiontime":300}`,
+		"PUT /groups/6/action": `{"scene":"def","transitiontime":50}`,
+		"PUT /groups/8/action": `{"bri":102}`,
+		"POST /groups":         `{"class":"Kids bedroom","lights":["1"],"name":"Office","type":"Room"}`,
+	}
+	for key, body := range want {
+		if bodies[key] != body {
+			t.Errorf("%s: got body %q, want %q", key, bodies[key], body)
+		}
+	}
+
+	if err := b.SetLightGradient(ctx, "1", nil); !errors.Is(err, ErrUnsupportedV1) {
+		t.Errorf("Expected gradients to be unsupported, got %v", err)
+	}
+}
+
+func TestV1Errors(t *testing.T) {
+	b, _ := v1Server(t, map[string]string{
+		"GET /lights":         `[{"error": {"type": 1, "address": "/lights", "description": "unauthorized user"}}]`,
+		"PUT /lights/9/state": `[{"error": {"type": 3, "address": "/lights/9", "description": "resource, /lights/9, not available"}}]`,
+	})
+	if _, err := b.GetLights(context.Background()); err == nil || !strings.Contains(err.Error(), "unauthorized user") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+	if err := b.SetLightOn(context.Background(), "9", true); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+}
+
+func TestV1ErrorsHideUsername(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	host := strings.TrimPrefix(server.URL, "http://")
+	server.Close()
+
+	b := NewV1Bridge(host, "secret-username", "bridge-1")
+	_, err := b.GetLights(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-username") {
+		t.Errorf("Expected the username to be left out, got %v", err)
+	}
+}
+
+func TestV1Pipeline(t *testing.T) {
+	b, bodies := v1Server(t, nil)
+	var buf bytes.Buffer
+	b.SetAuditLog(NewAuditLog(&buf))
+
+	if err := b.SetLightOn(context.Background(), "1", true); err != nil {
+		t.Fatalf("SetLightOn failed: %v", err)
+	}
+	var entry AuditEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected an audit entry, got %q: %v", buf.String(), err)
+	}
+	if entry.Bridge != "bridge-1" || entry.Method != "PUT" || entry.Resource != "lights/1/state" || entry.Status != 200 {
+		t.Errorf("Unexpected audit entry %+v", entry)
+	}
+
+	// A dry run sends nothing
+	var out strings.Builder
+	delete(bodies, "PUT /lights/1/state")
+	if err := NewDryRunBridge(b, &out).SetLightBrightness(context.Background(), "1", 50); err != nil {
+		t.Fatalf("SetLightBrightness failed: %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Errorf("Expected no request, sent %v", bodies)
+	}
+	if want := "[dry-run] SetLightBrightness \"1\" 50\n"; out.String() != want {
+		t.Errorf("Printed %q, want %q", out.String(), want)
+	}
+}
+
+func TestV1CommandsFailFastWhenOffline(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	host := strings.TrimPrefix(server.URL, "http://")
+	server.Close()
+
+	b := NewV1Bridge(host, "user", "bridge-1")
+	if _, err := b.GetLights(context.Background()); err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if b.Connection().Online {
+		t.Error("Expected the bridge to be reported offline")
+	}
+	if err := b.SetLightOn(context.Background(), "1", true); !errors.Is(err, ErrBridgeUnreachable) {
+		t.Errorf("Expected commands to fail fast with ErrBridgeUnreachable, got %v", err)
+	}
+}
+
+func TestDetectAPIVersion(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		want   string
+	}{
+		{http.StatusOK, APIVersionV2},
+		{http.StatusNotFound, APIVersionV1},
+	} {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		b := NewHueBridge(strings.TrimPrefix(server.URL, "https://"), "key", "bridge-1")
+		got, err := b.DetectAPIVersion(context.Background())
+		server.Close()
+		if err != nil || got != tt.want {
+			t.Errorf("Status %d: got %q, %v, want %q", tt.status, got, err, tt.want)
+		}
+	}
+}
+
+func TestDetectAPIVersionUnreachable(t *testing.T) {
+	// A bridge not answering HTTPS, even if it answers plain HTTP, is an
+	// error rather than a V1 bridge
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name": "Bridge"}`))
+	}))
+	defer server.Close()
+	b := NewHueBridge(strings.TrimPrefix(server.URL, "http://"), "key", "bridge-1")
+	if version, err := b.DetectAPIVersion(context.Background()); err == nil {
+		t.Errorf("Expected an error, got %q", version)
+	}
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // BridgeConfig stores connection details for a Hue bridge
@@ -15,6 +17,146 @@ type BridgeConfig struct {
 	Username string `json:"username"`
 	// Unique bridge identifier
 	BridgeID string `json:"bridge_id"`
+	// Certificate validation: "tofu" (default), "ca" or "insecure"
+	TLSMode string `json:"tls_mode,omitempty"`
+	// SHA-256 fingerprint pinned in "tofu" mode
+	CertFingerprint string `json:"cert_fingerprint,omitempty"`
+	// API the bridge was detected with: "v2", or "v1" for old round bridges
+	// without CLIP v2 (empty until detected)
+	APIVersion string `json:"api_version,omitempty"`
+	// How the bridge is reached: "local" (default) on the LAN, or "remote"
+	// through the Hue remote API with the Remote credentials
+	Connection string `json:"connection,omitempty"`
+	// OAuth credentials of the Hue remote API
+	Remote *RemoteConfig `json:"remote,omitempty"`
+	// Brightness and color of the lights when hue-tui last turned them
+	// off, by light ID, restored with remember_colors
+	LightMemory map[string]LightMemory `json:"light_memory,omitempty"`
+	BridgePreferences
+}
+
+// Connections of a bridge
+const (
+	ConnectionLocal  = "local"
+	ConnectionRemote = "remote"
+)
+
+// RemoteConfig stores the OAuth credentials of a Hue remote API app, whose
+// tokens are refreshed and saved back as they expire
+type RemoteConfig struct {
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// LightMemory is the brightness and color a light had when it was turned off
+type LightMemory struct {
+	// Brightness in percent
+	Brightness int `json:"brightness"`
+	// Color temperature, or 0 for a color
+	Mirek int `json:"mirek,omitempty"`
+	// CIE xy color, used when Mirek is 0
+	X float64 `json:"x,omitempty"`
+	Y float64 `json:"y,omitempty"`
+}
+
+// IsRemote reports whether the bridge is reached through the Hue remote API
+func (b *BridgeConfig) IsRemote() bool {
+	return b.Connection == ConnectionRemote
+}
+
+// BridgePreferences are the settings of a bridge besides its credentials,
+// kept in BridgeConfig and exported apart with the preferences
+type BridgePreferences struct {
+	// Local light roles ("tv-bias", "ambient" or "task") by light ID
+	LightRoles map[string]string `json:"light_roles,omitempty"`
+	// Groups of light IDs whose brightness and color are kept in sync
+	LightLinks [][]string `json:"light_links,omitempty"`
+	// Schedules run by hue-tui while it is open, such as sunset triggers
+	LocalSchedules []LocalSchedule `json:"local_schedules,omitempty"`
+	// Mirek added to color temperature commands by light ID, so bulbs of
+	// different generations show the same white
+	ColorTempOffsets map[string]int `json:"color_temp_offsets,omitempty"`
+	// Rooms whose live updates are ignored, to save CPU on busy bridges
+	MutedRooms []string `json:"muted_rooms,omitempty"`
+	// Scene IDs bound to the shortcut keys 1-9, by room ID and key
+	SceneShortcuts map[string]map[string]string `json:"scene_shortcuts,omitempty"`
+	// Recall duration in milliseconds by scene ID, 0 for instant. Scenes
+	// without one use the bridge default.
+	SceneTransitions map[string]int `json:"scene_transitions,omitempty"`
+}
+
+// LocalSchedule is a schedule run by hue-tui rather than the bridge, for
+// what behavior scripts can't do
+type LocalSchedule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// "turn_on", "go_to_sleep" (turn off) or "activate_scene"
+	Kind string `json:"kind"`
+	// "HH:MM", or relative to the sun: "sunset", "sunset-15m", "sunrise+1h"
+	At string `json:"at,omitempty"`
+	// Lowercase weekday names (empty = every day)
+	Days []string `json:"days,omitempty"`
+	// Cron expression such as "0 22 * * *", used instead of at and days
+	Cron string `json:"cron,omitempty"`
+	// Room or zone to act on
+	GroupID string `json:"group_id"`
+	// Scene to activate, for activate_scene
+	SceneID string `json:"scene_id,omitempty"`
+	// Lights within the group (empty = the whole group)
+	LightIDs []string `json:"light_ids,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+}
+
+// Location is where the lights are, used for sunrise and sunset times
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Locale controls how times and numbers are shown
+type Locale struct {
+	// "24h" (default) or "12h"
+	TimeFormat string `json:"time_format,omitempty"`
+	// "." (default) or ","
+	DecimalSeparator string `json:"decimal_separator,omitempty"`
+	// "celsius" (default) or "fahrenheit"
+	TemperatureUnit string `json:"temperature_unit,omitempty"`
+	// "percent" (default) or "raw", Hue's 0-254 scale
+	BrightnessScale string `json:"brightness_scale,omitempty"`
+}
+
+// Hook runs a shell command when an event happens, with the event as JSON
+// on stdin
+type Hook struct {
+	// "light_on", "motion", "bridge_disconnected" or "bridge_reconnected"
+	Event   string `json:"event"`
+	Command string `json:"command"`
+}
+
+// Alerts draws attention to critical events, for hue-tui running in a
+// background pane
+type Alerts struct {
+	// Ring the terminal bell
+	Bell bool `json:"bell,omitempty"`
+	// Flash the header
+	Flash bool `json:"flash,omitempty"`
+	// Motion sensors whose motion is alerted too, by ID
+	MotionSensors []string `json:"motion_sensors,omitempty"`
+}
+
+// Log configures the log file
+type Log struct {
+	// "debug", "info", "warn", "error" or "off" (default)
+	Level string `json:"level,omitempty"`
+	// Path of the log (default hue.log in $XDG_STATE_HOME/hue-cli)
+	File string `json:"file,omitempty"`
+	// Size a log file rotates at, in megabytes (default 5)
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+	// Rotated files kept (default 3)
+	MaxFiles int `json:"max_files,omitempty"`
 }
 
 // Config stores all application configuration
@@ -23,6 +165,64 @@ type Config struct {
 	Bridges []BridgeConfig `json:"bridges"`
 	// ID of the last used bridge
 	LastBridgeID string `json:"last_bridge_id,omitempty"`
+	Preferences
+}
+
+// Preferences are the settings of the app besides the bridges, kept in
+// Config and exported apart from the credentials
+type Preferences struct {
+	// Tint the header with the palette of the active scene
+	SceneAccent bool `json:"scene_accent,omitempty"`
+	// Order of the scenes modal: "room" (default), "name" or "recent"
+	SceneSort string `json:"scene_sort,omitempty"`
+	// Days without activation after which the scene cleanup lists a scene
+	// (default 90)
+	UnusedSceneDays int `json:"unused_scene_days,omitempty"`
+	// Length of the room fades started with F, in seconds (default 30)
+	FadeSeconds int `json:"fade_seconds,omitempty"`
+	// Length of the slow fade of scenes recalled with alt+enter, in
+	// seconds (default 30)
+	SceneFadeSeconds int `json:"scene_fade_seconds,omitempty"`
+	// How room dimming reaches the bridge: every light on each key press
+	// (default), or "commit" to preview the target and send it at once
+	RoomDimming string `json:"room_dimming,omitempty"`
+	// How the dimming step grows while an arrow key is held: not at all
+	// (default), "normal" or "fast"
+	Acceleration string `json:"acceleration,omitempty"`
+	// Seconds without any data after which the event stream is reconnected
+	// (default 300)
+	EventIdleSeconds int `json:"event_idle_seconds,omitempty"`
+	// Seconds between refreshes while the event stream can't connect
+	// (default 10)
+	PollSeconds int `json:"poll_seconds,omitempty"`
+	// Minutes the terminal stays unfocused without input before polling
+	// and animations pause (default 10)
+	IdleMinutes int `json:"idle_minutes,omitempty"`
+	// Name of the room selected and scrolled to on startup
+	StartRoom string `json:"start_room,omitempty"`
+	// How the lights without a room are listed: in one "Other Lights" room
+	// (default), or split by "device" or by "archetype"
+	OtherLights string `json:"other_lights,omitempty"`
+	// PEM file with the CAs trusted in "ca" TLS mode, in place of the
+	// bundled Signify root CA
+	CAFile string `json:"ca_file,omitempty"`
+	// Location for sunrise and sunset schedules
+	Location *Location `json:"location,omitempty"`
+	// Append every command sent to the bridge to audit.log
+	AuditLog bool `json:"audit_log,omitempty"`
+	// Level and location of the log
+	Log *Log `json:"log,omitempty"`
+	// Time, decimal and temperature formats
+	Locale *Locale `json:"locale,omitempty"`
+	// Commands run on bridge activity
+	Hooks []Hook `json:"hooks,omitempty"`
+	// Bell and header flash on disconnects, failed commands and motion
+	Alerts *Alerts `json:"alerts,omitempty"`
+	// Check GitHub for a newer release at most once a week on startup
+	UpdateCheck bool `json:"update_check,omitempty"`
+	// Turn lights switched on with space back on at the brightness and
+	// color they had when hue-tui turned them off
+	RememberColors bool `json:"remember_colors,omitempty"`
 }
 
 var (
@@ -54,6 +254,23 @@ func configPath() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
+// OpenAuditLog opens the audit log in the configuration directory for
+// appending, creating it if needed
+func OpenAuditLog() (*os.File, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "audit.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return f, nil
+}
+
 // Load reads the configuration from disk
 func Load() (*Config, error) {
 	path, err := configPath()
@@ -108,6 +325,33 @@ func (c *Config) AddBridge(bridge BridgeConfig) {
 	// Check if bridge already exists and update it
 	for i, b := range c.Bridges {
 		if b.BridgeID == bridge.BridgeID {
+			// Keep certificate settings, roles, links, schedules,
+			// calibration, scene transitions and remembered colors
+			// across re-pairing
+			if bridge.TLSMode == "" {
+				bridge.TLSMode = b.TLSMode
+			}
+			if bridge.CertFingerprint == "" {
+				bridge.CertFingerprint = b.CertFingerprint
+			}
+			if bridge.LightRoles == nil {
+				bridge.LightRoles = b.LightRoles
+			}
+			if bridge.LightLinks == nil {
+				bridge.LightLinks = b.LightLinks
+			}
+			if bridge.LocalSchedules == nil {
+				bridge.LocalSchedules = b.LocalSchedules
+			}
+			if bridge.ColorTempOffsets == nil {
+				bridge.ColorTempOffsets = b.ColorTempOffsets
+			}
+			if bridge.SceneTransitions == nil {
+				bridge.SceneTransitions = b.SceneTransitions
+			}
+			if bridge.LightMemory == nil {
+				bridge.LightMemory = b.LightMemory
+			}
 			c.Bridges[i] = bridge
 			return
 		}
@@ -78,6 +78,17 @@ func TestConfigAddBridge(t *testing.T) {
 		Host:     "192.168.1.100",
 		Username: "key1",
 		BridgeID: "bridge1",
+		LightMemory: map[string]LightMemory{
+			"light-1": {Brightness: 40, Mirek: 370},
+		},
+		BridgePreferences: BridgePreferences{
+			LightRoles:       map[string]string{"light-1": "tv-bias"},
+			LightLinks:       [][]string{{"light-1", "light-2"}},
+			ColorTempOffsets: map[string]int{"light-2": 15},
+			LocalSchedules: []LocalSchedule{
+				{ID: "local-1", Kind: "turn_on", At: "sunset-15m", GroupID: "room-1"},
+			},
+		},
 	})
 
 	if len(cfg.Bridges) != 1 {
@@ -114,6 +125,21 @@ func TestConfigAddBridge(t *testing.T) {
 	if bridge.Host != "192.168.1.200" {
 		t.Errorf("Expected updated host 192.168.1.200, got %s", bridge.Host)
 	}
+	if bridge.LightRoles["light-1"] != "tv-bias" {
+		t.Errorf("Expected light roles to survive re-pairing, got %v", bridge.LightRoles)
+	}
+	if len(bridge.LightLinks) != 1 {
+		t.Errorf("Expected light links to survive re-pairing, got %v", bridge.LightLinks)
+	}
+	if len(bridge.LocalSchedules) != 1 {
+		t.Errorf("Expected local schedules to survive re-pairing, got %v", bridge.LocalSchedules)
+	}
+	if bridge.ColorTempOffsets["light-2"] != 15 {
+		t.Errorf("Expected calibration to survive re-pairing, got %v", bridge.ColorTempOffsets)
+	}
+	if bridge.LightMemory["light-1"].Mirek != 370 {
+		t.Errorf("Expected remembered colors to survive re-pairing, got %v", bridge.LightMemory)
+	}
 }
 
 func TestConfigGetBridge(t *testing.T) {
@@ -248,3 +274,30 @@ func TestLoadNonExistent(t *testing.T) {
 		t.Errorf("Expected empty bridges, got %d", len(cfg.Bridges))
 	}
 }
+
+func TestOpenAuditLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	// Opening twice appends instead of truncating
+	for _, line := range []string{"first\n", "second\n"} {
+		f, err := OpenAuditLog()
+		if err != nil {
+			t.Fatalf("OpenAuditLog failed: %v", err)
+		}
+		if _, err := f.WriteString(line); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("Failed to close: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "hue-cli", "audit.log"))
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("Expected both lines, got %q", data)
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import "sort"
+
+// PreferencesFile holds the preferences of a config without the bridge
+// credentials, so they can be synced across machines with dotfiles
+type PreferencesFile struct {
+	Preferences
+	// Preferences of each bridge, by bridge ID
+	Bridges map[string]BridgePreferences `json:"bridges,omitempty"`
+}
+
+// ExportPreferences returns the preferences, leaving out the hosts,
+// application keys and certificates of the bridges
+func (c *Config) ExportPreferences() PreferencesFile {
+	prefs := PreferencesFile{Preferences: c.Preferences}
+	for _, b := range c.Bridges {
+		if b.BridgePreferences.isZero() {
+			continue
+		}
+		if prefs.Bridges == nil {
+			prefs.Bridges = make(map[string]BridgePreferences)
+		}
+		prefs.Bridges[b.BridgeID] = b.BridgePreferences
+	}
+	return prefs
+}
+
+// ImportPreferences replaces the preferences with prefs, keeping the
+// credentials. The preferences of bridges not paired here can't be kept
+// without them: their IDs are returned, sorted.
+func (c *Config) ImportPreferences(prefs PreferencesFile) (skipped []string) {
+	c.Preferences = prefs.Preferences
+	for id, bridgePrefs := range prefs.Bridges {
+		bridge, err := c.GetBridge(id)
+		if err != nil {
+			skipped = append(skipped, id)
+			continue
+		}
+		bridge.BridgePreferences = bridgePrefs
+	}
+	sort.Strings(skipped)
+	return skipped
+}
+
+// isZero reports whether no preference is set
+func (p BridgePreferences) isZero() bool {
+	return len(p.LightRoles) == 0 && len(p.LightLinks) == 0 && len(p.LocalSchedules) == 0 &&
+		len(p.ColorTempOffsets) == 0 && len(p.MutedRooms) == 0 && len(p.SceneShortcuts) == 0 &&
+		len(p.SceneTransitions) == 0
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportPreferences(t *testing.T) {
+	cfg := &Config{
+		Bridges: []BridgeConfig{
+			{
+				Host:            "192.168.1.100",
+				Username:        "secret-app-key",
+				BridgeID:        "bridge1",
+				CertFingerprint: "aaaabbbb",
+				BridgePreferences: BridgePreferences{
+					LightRoles: map[string]string{"light-1": "tv-bias"},
+				},
+			},
+			{Host: "192.168.1.101", Username: "other-key", BridgeID: "bridge2"},
+		},
+		LastBridgeID: "bridge1",
+		Preferences:  Preferences{SceneSort: "recent", PollSeconds: 15},
+	}
+
+	data, err := json.Marshal(cfg.ExportPreferences())
+	if err != nil {
+		t.Fatalf("Failed to encode preferences: %v", err)
+	}
+	for _, secret := range []string{"secret-app-key", "other-key", "192.168.1.100", "aaaabbbb", "bridge2"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Exported preferences contain %q: %s", secret, data)
+		}
+	}
+	want := `{"scene_sort":"recent","poll_seconds":15,"bridges":{"bridge1":{"light_roles":{"light-1":"tv-bias"}}}}`
+	if string(data) != want {
+		t.Errorf("Exported %s, want %s", data, want)
+	}
+
+	// The config file keeps its flat layout
+	data, err = json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Failed to encode config: %v", err)
+	}
+	if !strings.Contains(string(data), `"cert_fingerprint":"aaaabbbb","light_roles"`) {
+		t.Errorf("Bridge preferences are not flattened: %s", data)
+	}
+	if !strings.Contains(string(data), `"last_bridge_id":"bridge1","scene_sort":"recent"`) {
+		t.Errorf("Preferences are not flattened: %s", data)
+	}
+}
+
+func TestImportPreferences(t *testing.T) {
+	cfg := &Config{
+		Bridges: []BridgeConfig{{Host: "10.0.0.1", Username: "key", BridgeID: "bridge1"}},
+		Preferences: Preferences{
+			SceneAccent: true,
+		},
+	}
+
+	var prefs PreferencesFile
+	data := `{"fade_seconds": 60, "bridges": {"bridge1": {"muted_rooms": ["room-1"]}, "elsewhere": {"muted_rooms": ["room-2"]}}}`
+	if err := json.Unmarshal([]byte(data), &prefs); err != nil {
+		t.Fatalf("Failed to parse preferences: %v", err)
+	}
+	skipped := cfg.ImportPreferences(prefs)
+
+	if !reflect.DeepEqual(skipped, []string{"elsewhere"}) {
+		t.Errorf("Skipped %v, want the bridge not paired here", skipped)
+	}
+	if cfg.FadeSeconds != 60 || cfg.SceneAccent {
+		t.Errorf("Preferences = %+v, want the imported ones", cfg.Preferences)
+	}
+	bridge := cfg.Bridges[0]
+	if bridge.Username != "key" || !reflect.DeepEqual(bridge.MutedRooms, []string{"room-1"}) {
+		t.Errorf("Bridge = %+v, want its key kept and the muted rooms imported", bridge)
+	}
+}
@@ -0,0 +1,160 @@
+// Package cron parses the five-field cron expressions of local schedules
+// and finds when they next run.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field bounds, in expression order
+var fields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Expr is a parsed cron expression: minute, hour, day of month, month and
+// day of week
+type Expr struct {
+	minutes, hours, days, months, weekdays uint64
+	// Whether the day fields are restricted. When both are, a day matches
+	// either of them, as in crontab(5).
+	anyDay, anyWeekday bool
+	source             string
+}
+
+// Parse parses an expression such as "0 22 * * *" or "*/15 7-9 * * 1-5".
+// Fields accept *, numbers, ranges, lists and steps; Sunday is 0 or 7.
+func Parse(s string) (*Expr, error) {
+	parts := strings.Fields(s)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", s, len(parts))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i].min, fields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s: %w", s, fields[i].name, err)
+		}
+		sets[i] = set
+	}
+	// Sunday can be written 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Expr{
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+		source:     strings.Join(parts, " "),
+	}, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps
+// into a bit set
+func parseField(s string, min, max int) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value %q", to)
+				}
+			} else if hasStep {
+				// "5/15" runs from 5 to the end of the range
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", rangePart, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression as written, with single spaces
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Matches reports whether the expression runs at the minute of t
+func (e *Expr) Matches(t time.Time) bool {
+	return e.minutes&(1<<t.Minute()) != 0 &&
+		e.hours&(1<<t.Hour()) != 0 &&
+		e.months&(1<<int(t.Month())) != 0 &&
+		e.matchesDay(t)
+}
+
+// matchesDay matches the day of month and day of week fields
+func (e *Expr) matchesDay(t time.Time) bool {
+	day := e.days&(1<<t.Day()) != 0
+	weekday := e.weekdays&(1<<int(t.Weekday())) != 0
+	if !e.anyDay && !e.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// maxSearch bounds the search for the next run: every expression that can
+// run at all does so within a leap cycle, as February 29 can be asked for
+const maxSearch = 366 * 8
+
+// Next returns the first minute after t that the expression runs at, or
+// the zero time if it never does (such as on February 30)
+func (e *Expr) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for days := 0; days < maxSearch; {
+		if e.months&(1<<int(t.Month())) == 0 || !e.matchesDay(t) {
+			y, m, d := t.Date()
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+			days++
+			continue
+		}
+		for ; ; t = t.Add(time.Minute) {
+			if e.hours&(1<<t.Hour()) != 0 && e.minutes&(1<<t.Minute()) != 0 {
+				return t
+			}
+			if t.Hour() == 23 && t.Minute() == 59 {
+				break
+			}
+		}
+		t = t.Add(time.Minute)
+		days++
+	}
+	return time.Time{}
+}
@@ -0,0 +1,85 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 5, 15, 21, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"0 22 * * *", "2024-05-15 22:00"},
+		{"30 21 * * *", "2024-05-16 21:30"},
+		{"*/15 * * * *", "2024-05-15 21:45"},
+		{"0 7 * * 1-5", "2024-05-16 07:00"},
+		{"0 9 * * 6,0", "2024-05-18 09:00"},
+		{"0 9 * * 7", "2024-05-19 09:00"},
+		{"0 0 1 * *", "2024-06-01 00:00"},
+		{"5/20 8 * * *", "2024-05-16 08:05"},
+		{"0 12 29 2 *", "2028-02-29 12:00"},
+		// Either day field matches when both are set
+		{"0 8 20 * 5", "2024-05-17 08:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if got := e.Next(from).Format("2006-01-02 15:04"); got != tt.want {
+				t.Errorf("Next() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	e, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if next := e.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected February 30 never to come, got %s", next)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	e, err := Parse("0 22 * * *")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if !e.Matches(time.Date(2024, 5, 15, 22, 0, 45, 0, time.UTC)) {
+		t.Error("Expected 22:00 to match")
+	}
+	if e.Matches(time.Date(2024, 5, 15, 22, 1, 0, 0, time.UTC)) {
+		t.Error("Expected 22:01 not to match")
+	}
+	if e.String() != "0 22 * * *" {
+		t.Errorf("String() = %q", e.String())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 22 * *",
+		"0 22 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package hooks runs the shell commands of the config on bridge activity,
+// to wire desktop notifications or scripts to it.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+)
+
+// Events hooks can run on
+const (
+	// A light was turned on from outside hue-tui
+	LightOn = "light_on"
+	// A motion sensor detected motion
+	Motion = "motion"
+	// The bridge stopped answering
+	BridgeDisconnected = "bridge_disconnected"
+	// The bridge answers again
+	BridgeReconnected = "bridge_reconnected"
+)
+
+// timeout bounds how long a hook command may run
+const timeout = 30 * time.Second
+
+// Payload is the JSON written to the stdin of hook commands
+type Payload struct {
+	Event  string    `json:"event"`
+	Time   time.Time `json:"time"`
+	Bridge string    `json:"bridge,omitempty"`
+	// Light events
+	LightID string `json:"light_id,omitempty"`
+	Light   string `json:"light,omitempty"`
+	Room    string `json:"room,omitempty"`
+	// Motion events
+	SensorID string `json:"sensor_id,omitempty"`
+	// Why the bridge disconnected
+	Error string `json:"error,omitempty"`
+}
+
+// Runner runs the hook commands of each event
+type Runner struct {
+	commands map[string][]string
+	wg       sync.WaitGroup
+	// Called with the error of a failed command
+	onError func(error)
+}
+
+// New creates a runner for the hooks of the config
+func New(hooks []config.Hook) (*Runner, error) {
+	r := &Runner{commands: make(map[string][]string)}
+	for _, h := range hooks {
+		switch h.Event {
+		case LightOn, Motion, BridgeDisconnected, BridgeReconnected:
+		default:
+			return nil, fmt.Errorf("unknown hook event %q (expected %s, %s, %s or %s)",
+				h.Event, LightOn, Motion, BridgeDisconnected, BridgeReconnected)
+		}
+		if h.Command == "" {
+			return nil, fmt.Errorf("hook for %s has no command", h.Event)
+		}
+		r.commands[h.Event] = append(r.commands[h.Event], h.Command)
+	}
+	return r, nil
+}
+
+// SetErrorHandler sets the function failed commands are reported to
+func (r *Runner) SetErrorHandler(handler func(error)) {
+	r.onError = handler
+}
+
+// Has reports whether any hook runs on an event
+func (r *Runner) Has(event string) bool {
+	return r != nil && len(r.commands[event]) > 0
+}
+
+// Fire starts the commands of the payload's event in the background. The
+// time is set to now if missing.
+func (r *Runner) Fire(p Payload) {
+	if !r.Has(p.Event) {
+		return
+	}
+	if p.Time.IsZero() {
+		p.Time = time.Now()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	for _, command := range r.commands[p.Event] {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if err := run(command, data); err != nil && r.onError != nil {
+				r.onError(fmt.Errorf("failed to run %s hook: %w", p.Event, err))
+			}
+		}()
+	}
+}
+
+// Wait waits for the running commands to finish
+func (r *Runner) Wait() {
+	if r != nil {
+		r.wg.Wait()
+	}
+}
+
+// run runs a command through the shell with data on stdin
+func run(command string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/config"
+)
+
+func TestFire(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "payload.json")
+	r, err := New([]config.Hook{
+		{Event: LightOn, Command: "cat > " + out},
+		{Event: Motion, Command: "touch " + filepath.Join(dir, "motion")},
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	r.Fire(Payload{Event: LightOn, LightID: "light-1", Light: "Desk", Room: "Office"})
+	r.Wait()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Expected the hook to write its stdin: %v", err)
+	}
+	var p Payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Expected a JSON payload, got %q", data)
+	}
+	if p.Event != LightOn || p.LightID != "light-1" || p.Light != "Desk" || p.Room != "Office" || p.Time.IsZero() {
+		t.Errorf("Unexpected payload: %+v", p)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "motion")); !os.IsNotExist(err) {
+		t.Error("Expected the motion hook not to run")
+	}
+}
+
+func TestFireError(t *testing.T) {
+	r, err := New([]config.Hook{{Event: BridgeDisconnected, Command: "echo oops >&2; exit 3"}})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	var mu sync.Mutex
+	var errs []error
+	r.SetErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+
+	r.Fire(Payload{Event: BridgeDisconnected, Error: "timeout"})
+	r.Wait()
+
+	var exitErr interface{ ExitCode() int }
+	if len(errs) != 1 || !errors.As(errs[0], &exitErr) || !strings.Contains(errs[0].Error(), "oops") {
+		t.Errorf("Expected the failure to be reported with its stderr, got %v", errs)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, h := range []config.Hook{
+		{Event: "light_off", Command: "true"},
+		{Event: LightOn},
+	} {
+		if _, err := New([]config.Hook{h}); err == nil {
+			t.Errorf("Expected an error for %+v", h)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package locale formats times, decimals, temperatures and brightness the
+// way the config asks for.
+package locale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// Format formats values for display. The zero value uses a 24-hour clock,
+// a decimal point, degrees Celsius and brightness in percent.
+type Format struct {
+	clock12       bool
+	comma         bool
+	fahrenheit    bool
+	rawBrightness bool
+}
+
+// New creates a format from the locale settings of the config, which may
+// be nil
+func New(l *config.Locale) (Format, error) {
+	var f Format
+	if l == nil {
+		return f, nil
+	}
+
+	switch strings.ToLower(l.TimeFormat) {
+	case "", "24h":
+	case "12h":
+		f.clock12 = true
+	default:
+		return Format{}, fmt.Errorf("unknown time_format %q (expected 24h or 12h)", l.TimeFormat)
+	}
+
+	switch l.DecimalSeparator {
+	case "", ".":
+	case ",":
+		f.comma = true
+	default:
+		return Format{}, fmt.Errorf("unknown decimal_separator %q (expected . or ,)", l.DecimalSeparator)
+	}
+
+	switch strings.ToLower(l.TemperatureUnit) {
+	case "", "c", "celsius":
+	case "f", "fahrenheit":
+		f.fahrenheit = true
+	default:
+		return Format{}, fmt.Errorf("unknown temperature_unit %q (expected celsius or fahrenheit)", l.TemperatureUnit)
+	}
+
+	switch strings.ToLower(l.BrightnessScale) {
+	case "", "percent":
+	case "raw":
+		f.rawBrightness = true
+	default:
+		return Format{}, fmt.Errorf("unknown brightness_scale %q (expected percent or raw)", l.BrightnessScale)
+	}
+	return f, nil
+}
+
+// Clock formats a time of day, as "18:30" or "6:30 PM"
+func (f Format) Clock(hour, minute int) string {
+	if !f.clock12 {
+		return fmt.Sprintf("%02d:%02d", hour, minute)
+	}
+	suffix := "AM"
+	if hour >= 12 {
+		suffix = "PM"
+	}
+	hour %= 12
+	if hour == 0 {
+		hour = 12
+	}
+	return fmt.Sprintf("%d:%02d %s", hour, minute, suffix)
+}
+
+// Time formats a time with seconds, as "18:30:05" or "6:30:05 PM"
+func (f Format) Time(t time.Time) string {
+	if f.clock12 {
+		return t.Format("3:04:05 PM")
+	}
+	return t.Format("15:04:05")
+}
+
+// Decimal formats a number with the given number of decimals
+func (f Format) Decimal(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if f.comma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// Temperature formats a temperature given in degrees Celsius, as sensors
+// report it, with one decimal
+func (f Format) Temperature(celsius float64) string {
+	if f.fahrenheit {
+		return f.Decimal(celsius*9/5+32, 1) + "°F"
+	}
+	return f.Decimal(celsius, 1) + "°C"
+}
+
+// Brightness formats a brightness level (0-254), as "80%" or "203"
+func (f Format) Brightness(level uint8) string {
+	if f.rawBrightness {
+		return strconv.Itoa(int(level))
+	}
+	return strconv.Itoa(models.LevelToPct(level)) + "%"
+}
+
+// RawBrightness reports whether brightness is shown on Hue's 0-254 scale
+func (f Format) RawBrightness() bool {
+	return f.rawBrightness
+}
+
+// ToggleBrightness returns the format with the other brightness scale
+func (f Format) ToggleBrightness() Format {
+	f.rawBrightness = !f.rawBrightness
+	return f
+}
@@ -0,0 +1,77 @@
+package locale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+)
+
+func TestDefaultFormat(t *testing.T) {
+	f, err := New(nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got := f.Clock(18, 5); got != "18:05" {
+		t.Errorf("Clock() = %q, want %q", got, "18:05")
+	}
+	if got := f.Time(time.Date(2024, 1, 1, 7, 3, 9, 0, time.UTC)); got != "07:03:09" {
+		t.Errorf("Time() = %q, want %q", got, "07:03:09")
+	}
+	if got := f.Temperature(21.46); got != "21.5°C" {
+		t.Errorf("Temperature() = %q, want %q", got, "21.5°C")
+	}
+	if got := f.Brightness(203); got != "80%" {
+		t.Errorf("Brightness() = %q, want %q", got, "80%")
+	}
+	if got := f.ToggleBrightness().Brightness(203); got != "203" {
+		t.Errorf("Brightness() after toggling = %q, want %q", got, "203")
+	}
+}
+
+func TestLocalizedFormat(t *testing.T) {
+	f, err := New(&config.Locale{TimeFormat: "12h", DecimalSeparator: ",", TemperatureUnit: "fahrenheit", BrightnessScale: "raw"})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	clocks := []struct {
+		hour, minute int
+		want         string
+	}{
+		{0, 15, "12:15 AM"},
+		{9, 0, "9:00 AM"},
+		{12, 30, "12:30 PM"},
+		{23, 59, "11:59 PM"},
+	}
+	for _, tt := range clocks {
+		if got := f.Clock(tt.hour, tt.minute); got != tt.want {
+			t.Errorf("Clock(%d, %d) = %q, want %q", tt.hour, tt.minute, got, tt.want)
+		}
+	}
+	if got := f.Time(time.Date(2024, 1, 1, 19, 3, 9, 0, time.UTC)); got != "7:03:09 PM" {
+		t.Errorf("Time() = %q, want %q", got, "7:03:09 PM")
+	}
+	if got := f.Decimal(0.4573, 3); got != "0,457" {
+		t.Errorf("Decimal() = %q, want %q", got, "0,457")
+	}
+	if got := f.Temperature(21.5); got != "70,7°F" {
+		t.Errorf("Temperature() = %q, want %q", got, "70,7°F")
+	}
+	if got := f.Brightness(254); got != "254" {
+		t.Errorf("Brightness() = %q, want %q", got, "254")
+	}
+}
+
+func TestInvalidLocale(t *testing.T) {
+	for _, l := range []config.Locale{
+		{TimeFormat: "36h"},
+		{DecimalSeparator: ";"},
+		{TemperatureUnit: "kelvin"},
+		{BrightnessScale: "lux"},
+	} {
+		if _, err := New(&l); err == nil {
+			t.Errorf("Expected an error for %+v", l)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package logging is the log shared by the TUI, the API client and the
+// event stream: leveled lines tagged with their component, written to a
+// size-rotated file in the XDG state directory.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Levels, from the most verbose
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+	// LevelOff writes nothing, the default
+	LevelOff = "off"
+)
+
+const (
+	// DefaultMaxSizeMB is the size a log file rotates at
+	DefaultMaxSizeMB = 5
+	// DefaultMaxFiles is the number of rotated files kept
+	DefaultMaxFiles = 3
+	// fileName is the name of the log in the state directory
+	fileName = "hue.log"
+)
+
+// Options configures the log
+type Options struct {
+	// One of the levels, empty for off
+	Level string
+	// Path of the log file, empty for hue.log in the state directory
+	Path string
+	// Size a file rotates at, 0 for DefaultMaxSizeMB
+	MaxSizeMB int
+	// Rotated files kept, 0 for DefaultMaxFiles
+	MaxFiles int
+}
+
+var (
+	mu      sync.RWMutex
+	handler slog.Handler
+	file    *rotatingFile
+	path    string
+)
+
+// ParseLevel checks a level name, case insensitive
+func ParseLevel(s string) (string, error) {
+	switch level := strings.ToLower(s); level {
+	case "", LevelOff:
+		return LevelOff, nil
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return level, nil
+	}
+	return "", fmt.Errorf("unknown log level %q, want debug, info, warn, error or off", s)
+}
+
+// slogLevel maps a level name to its slog level
+func slogLevel(level string) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// DefaultPath returns hue.log in $XDG_STATE_HOME/hue-cli, or
+// ~/.local/state/hue-cli
+func DefaultPath() (string, error) {
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "hue-cli", fileName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "hue-cli", fileName), nil
+}
+
+// Setup opens the log file and starts writing lines at opts.Level and
+// above. A previous setup is closed first.
+func Setup(opts Options) error {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	if err := Close(); err != nil {
+		return err
+	}
+	if level == LevelOff {
+		return nil
+	}
+
+	logPath := opts.Path
+	if logPath == "" {
+		if logPath, err = DefaultPath(); err != nil {
+			return err
+		}
+	}
+	maxSize := opts.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSizeMB
+	}
+	maxFiles := opts.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	f, err := openRotatingFile(logPath, int64(maxSize)<<20, maxFiles)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	file, path = f, logPath
+	handler = newHandler(f, slogLevel(level))
+	return nil
+}
+
+// newHandler writes text lines at level and above to w
+func newHandler(w io.Writer, level slog.Level) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+}
+
+// Close stops logging and closes the log file
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	handler, path = nil, ""
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close log: %w", err)
+	}
+	return nil
+}
+
+// Path returns the file being written, empty when logging is off
+func Path() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return path
+}
+
+// Enabled reports whether anything is logged
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return handler != nil
+}
+
+// Logger writes the lines of one component. Loggers can be made before
+// Setup, they always write to the current log.
+type Logger struct {
+	component string
+}
+
+// For returns the logger of a component, such as "tui" or "events"
+func For(component string) Logger {
+	return Logger{component: component}
+}
+
+// Debugf logs a debug line
+func (l Logger) Debugf(format string, args ...interface{}) {
+	l.logf(slog.LevelDebug, format, args)
+}
+
+// Infof logs an info line
+func (l Logger) Infof(format string, args ...interface{}) {
+	l.logf(slog.LevelInfo, format, args)
+}
+
+// Warnf logs a warning
+func (l Logger) Warnf(format string, args ...interface{}) {
+	l.logf(slog.LevelWarn, format, args)
+}
+
+// Errorf logs an error
+func (l Logger) Errorf(format string, args ...interface{}) {
+	l.logf(slog.LevelError, format, args)
+}
+
+func (l Logger) logf(level slog.Level, format string, args []interface{}) {
+	mu.RLock()
+	h := handler
+	mu.RUnlock()
+
+	ctx := context.Background()
+	if h == nil || !h.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, args...), 0)
+	r.AddAttrs(slog.String("component", l.component))
+	_ = h.Handle(ctx, r)
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "hue.log")
+	if err := Setup(Options{Level: "INFO", Path: logPath}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	t.Cleanup(func() { _ = Close() })
+
+	log := For("events")
+	log.Debugf("debug %d", 1)
+	log.Infof("connected to %s", "bridge")
+	log.Warnf("dropped")
+	if err := Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	log.Errorf("after close")
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), data)
+	}
+	for i, want := range []string{`level=INFO msg="connected to bridge" component=events`, `level=WARN msg=dropped component=events`} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want it to contain %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestSetupOff(t *testing.T) {
+	if err := Setup(Options{Level: "off", Path: filepath.Join(t.TempDir(), "hue.log")}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if Enabled() || Path() != "" {
+		t.Errorf("Enabled() = %v, Path() = %q with logging off", Enabled(), Path())
+	}
+	if err := Setup(Options{Level: "verbose"}); err == nil {
+		t.Error("Setup() accepted an unknown level")
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/state")
+	got, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() error = %v", err)
+	}
+	if want := filepath.Join("/state", "hue-cli", "hue.log"); got != want {
+		t.Errorf("DefaultPath() = %q, want %q", got, want)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "logs", "hue.log")
+	r, err := openRotatingFile(logPath, 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	for _, line := range []string{"aaaaaaa\n", "bbbbbbb\n", "ccccccc\n", "ddddddd\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := map[string]string{
+		logPath:        "ddddddd\n",
+		logPath + ".1": "ccccccc\n",
+		logPath + ".2": "bbbbbbb\n",
+	}
+	for file, content := range want {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", file, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", file, data, content)
+		}
+	}
+	if _, err := os.Stat(logPath + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept more than 2 rotated files")
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import "strings"
+
+// LightArchetypes are the light types the bridge accepts, which set the
+// icon shown in the Hue app
+var LightArchetypes = []string{
+	"unknown_archetype",
+	"classic_bulb",
+	"sultan_bulb",
+	"flood_bulb",
+	"spot_bulb",
+	"candle_bulb",
+	"luster_bulb",
+	"vintage_bulb",
+	"vintage_candle_bulb",
+	"ellipse_bulb",
+	"triangle_bulb",
+	"small_globe_bulb",
+	"large_globe_bulb",
+	"edison_bulb",
+	"pendant_round",
+	"pendant_long",
+	"pendant_spot",
+	"ceiling_round",
+	"ceiling_square",
+	"ceiling_horizontal",
+	"ceiling_tube",
+	"floor_shade",
+	"floor_lantern",
+	"table_shade",
+	"table_wash",
+	"recessed_ceiling",
+	"recessed_floor",
+	"single_spot",
+	"double_spot",
+	"wall_lantern",
+	"wall_shade",
+	"wall_spot",
+	"wall_washer",
+	"flexible_lamp",
+	"ground_spot",
+	"bollard",
+	"up_and_down",
+	"up_and_down_up",
+	"up_and_down_down",
+	"christmas_tree",
+	"string_light",
+	"plug",
+	"hue_go",
+	"hue_lightstrip",
+	"hue_lightstrip_tv",
+	"hue_lightstrip_pc",
+	"hue_iris",
+	"hue_bloom",
+	"hue_play",
+	"hue_centris",
+	"hue_tube",
+	"hue_signe",
+	"hue_floodlight_camera",
+}
+
+// ArchetypeLabel returns an archetype as shown to users, "classic_bulb"
+// becoming "Classic bulb"
+func ArchetypeLabel(archetype string) string {
+	if archetype == "" {
+		return ""
+	}
+	label := strings.ReplaceAll(archetype, "_", " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// ArchetypeKind returns the broad kind of an archetype, to group lights:
+// "Plugs", "Strips", "Bulbs", "Portable lamps", "Fixtures", or "Unknown type"
+func ArchetypeKind(archetype string) string {
+	switch {
+	case archetype == "" || archetype == "unknown_archetype":
+		return "Unknown type"
+	case archetype == "plug":
+		return "Plugs"
+	case strings.HasPrefix(archetype, "hue_lightstrip") || archetype == "string_light":
+		return "Strips"
+	case strings.HasSuffix(archetype, "_bulb"):
+		return "Bulbs"
+	case archetype == "hue_go":
+		return "Portable lamps"
+	}
+	return "Fixtures"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+func TestArchetypeKind(t *testing.T) {
+	tests := map[string]string{
+		"plug":              "Plugs",
+		"hue_lightstrip_tv": "Strips",
+		"string_light":      "Strips",
+		"candle_bulb":       "Bulbs",
+		"hue_go":            "Portable lamps",
+		"ceiling_round":     "Fixtures",
+		"unknown_archetype": "Unknown type",
+		"":                  "Unknown type",
+	}
+	for archetype, want := range tests {
+		if got := ArchetypeKind(archetype); got != want {
+			t.Errorf("ArchetypeKind(%q) = %q, want %q", archetype, got, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// BridgeInfo describes the bridge itself, for the system info screen
+type BridgeInfo struct {
+	Name string
+	// Model ID, like "BSB002" for the square bridge
+	ModelID string
+	// Firmware version
+	SoftwareVersion string
+	// Version of the V1 API, like "1.65.0"
+	APIVersion string
+	// Zigbee channel the lights talk on (11, 15, 20 or 25)
+	ZigbeeChannel int
+	// Number of resources the bridge holds, of every type
+	Resources int
+	// Firmware update state from the bridge: "noupdates", "transferring",
+	// "anyreadytoinstall", "allreadytoinstall" or "installing"
+	UpdateState string
+	TimeZone    string
+	// Round trip time of the request for this info
+	Latency time.Duration
+}
+
+// UpdateStatus describes the firmware update state
+func (i *BridgeInfo) UpdateStatus() string {
+	switch i.UpdateState {
+	case "noupdates":
+		return "Up to date"
+	case "transferring":
+		return "Downloading update"
+	case "anyreadytoinstall", "allreadytoinstall":
+		return "Update ready to install"
+	case "installing":
+		return "Installing update"
+	}
+	return "Unknown"
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
 // ColorMode represents how the color is being controlled
@@ -328,6 +331,37 @@ func NewColorFromXY(x, y float64, brightness uint8) *Color {
 	}
 }
 
+// Color temperature range of Hue lights in mirek (6500K to 2000K)
+const (
+	MirekMin = 153
+	MirekMax = 500
+)
+
+// ColorTempPreset is a named color temperature
+type ColorTempPreset struct {
+	Name   string
+	Kelvin int
+}
+
+// ColorTempPresets are the temperatures offered in temperature mode, from
+// warm to cool
+var ColorTempPresets = []ColorTempPreset{
+	{"Candle", 2200},
+	{"Warm", 2700},
+	{"Neutral", 4000},
+	{"Daylight", 6500},
+}
+
+// KelvinToMirek converts a color temperature in kelvin to mirek
+func KelvinToMirek(kelvin int) int {
+	return int(math.Round(1e6 / float64(kelvin)))
+}
+
+// MirekToKelvin converts a color temperature in mirek to kelvin
+func MirekToKelvin(mirek int) int {
+	return int(math.Round(1e6 / float64(mirek)))
+}
+
 // NewColorFromMirek creates a Color from color temperature
 func NewColorFromMirek(mirek uint16, brightness uint8) *Color {
 	return &Color{
@@ -343,6 +377,16 @@ func (c *Color) HexString() string {
 	return "#" + hexByte(r) + hexByte(g) + hexByte(b)
 }
 
+// ParseHex parses a "#RRGGBB" color, the # being optional
+func ParseHex(s string) (r, g, b uint8, err error) {
+	hex := strings.TrimPrefix(s, "#")
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, expected #RRGGBB", s)
+	}
+	return uint8(n >> 16), uint8(n >> 8), uint8(n), nil
+}
+
 func hexByte(b uint8) string {
 	const hex = "0123456789ABCDEF"
 	return string([]byte{hex[b>>4], hex[b&0x0F]})
@@ -350,5 +394,5 @@ func hexByte(b uint8) string {
 
 // BrightnessPct returns brightness as a percentage (0-100)
 func (c *Color) BrightnessPct() int {
-	return int(float64(c.Brightness) / 254.0 * 100)
+	return LevelToPct(c.Brightness)
 }
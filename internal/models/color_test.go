@@ -287,6 +287,21 @@ func TestHexString(t *testing.T) {
 	}
 }
 
+func TestParseHex(t *testing.T) {
+	r, g, b, err := ParseHex("#FF8800")
+	if err != nil || r != 0xFF || g != 0x88 || b != 0x00 {
+		t.Errorf("ParseHex(#FF8800) = %d, %d, %d, %v", r, g, b, err)
+	}
+	if r, g, b, err := ParseHex("00a0ff"); err != nil || r != 0 || g != 0xA0 || b != 0xFF {
+		t.Errorf("ParseHex(00a0ff) = %d, %d, %d, %v", r, g, b, err)
+	}
+	for _, s := range []string{"", "#FFF", "#GG0000", "#FF000000"} {
+		if _, _, _, err := ParseHex(s); err == nil {
+			t.Errorf("ParseHex(%q) succeeded, want an error", s)
+		}
+	}
+}
+
 func TestBrightnessPct(t *testing.T) {
 	tests := []struct {
 		brightness uint8
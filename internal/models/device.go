@@ -0,0 +1,42 @@
+package models
+
+// DeviceKind is what a battery powered device is used for
+type DeviceKind string
+
+const (
+	DeviceSwitch       DeviceKind = "switch"
+	DeviceMotionSensor DeviceKind = "motion sensor"
+	DeviceButton       DeviceKind = "button"
+	DeviceOther        DeviceKind = "device"
+)
+
+// LowBatteryLevel is the battery percentage at or under which a device is
+// reported as low, when the bridge doesn't say
+const LowBatteryLevel = 20
+
+// Device is a battery powered accessory, like a dimmer switch or a motion
+// sensor
+type Device struct {
+	// Unique identifier from the bridge
+	ID string
+	// User-friendly name
+	Name string
+	// Product name, like "Hue dimmer switch"
+	Product string
+	Kind    DeviceKind
+	// Battery percentage (0-100)
+	BatteryLevel int
+	// Battery state from the bridge: "normal", "low" or "critical"
+	BatteryState string
+}
+
+// LowBattery returns true if the battery should be replaced soon
+func (d *Device) LowBattery() bool {
+	switch d.BatteryState {
+	case "low", "critical":
+		return true
+	case "normal":
+		return false
+	}
+	return d.BatteryLevel <= LowBatteryLevel
+}
@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestDeviceLowBattery(t *testing.T) {
+	tests := []struct {
+		level int
+		state string
+		want  bool
+	}{
+		{level: 80, state: "normal", want: false},
+		{level: 15, state: "normal", want: false},
+		{level: 30, state: "low", want: true},
+		{level: 5, state: "critical", want: true},
+		{level: 20, want: true},
+		{level: 21, want: false},
+	}
+
+	for _, tt := range tests {
+		d := &Device{BatteryLevel: tt.level, BatteryState: tt.state}
+		if got := d.LowBattery(); got != tt.want {
+			t.Errorf("LowBattery() at %d%% %q = %v, want %v", tt.level, tt.state, got, tt.want)
+		}
+	}
+}
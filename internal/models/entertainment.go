@@ -0,0 +1,32 @@
+package models
+
+// EntertainmentChannel is one streaming channel of an entertainment area
+type EntertainmentChannel struct {
+	// Channel number used when streaming
+	ID int
+	// Position in the area (-1 to 1 on each axis: left/right, back/front, down/up)
+	X, Y, Z float64
+	// Lights rendering this channel
+	LightIDs []string
+}
+
+// EntertainmentArea represents a Hue entertainment configuration
+type EntertainmentArea struct {
+	// Unique identifier from the bridge
+	ID string
+	// User-friendly name
+	Name string
+	// Configuration type ("screen", "monitor", "music", "3dspace" or "other")
+	Type string
+	// Streaming status ("active" or "inactive")
+	Status string
+	// Channels with their positions
+	Channels []EntertainmentChannel
+	// All lights that take part in the area
+	LightIDs []string
+}
+
+// IsActive returns true if an entertainment session is running
+func (a *EntertainmentArea) IsActive() bool {
+	return a.Status == "active"
+}
@@ -0,0 +1,86 @@
+package models
+
+import "math"
+
+// GamutPoint is a corner of a gamut, in CIE 1931 xy coordinates
+type GamutPoint struct {
+	X, Y float64
+}
+
+// Gamut is the triangle of the colors a light can show
+type Gamut struct {
+	// "A", "B", "C", or "other" for custom gamuts
+	Type  string
+	Red   GamutPoint
+	Green GamutPoint
+	Blue  GamutPoint
+}
+
+// Gamuts of the Hue lights, by type
+var (
+	// Older LivingColors, Bloom and Iris lamps
+	GamutA = Gamut{Type: "A", Red: GamutPoint{0.704, 0.296}, Green: GamutPoint{0.2151, 0.7106}, Blue: GamutPoint{0.138, 0.08}}
+	// First generation color bulbs
+	GamutB = Gamut{Type: "B", Red: GamutPoint{0.675, 0.322}, Green: GamutPoint{0.409, 0.518}, Blue: GamutPoint{0.167, 0.04}}
+	// Current color bulbs and strips
+	GamutC = Gamut{Type: "C", Red: GamutPoint{0.6915, 0.3083}, Green: GamutPoint{0.17, 0.7}, Blue: GamutPoint{0.1532, 0.0475}}
+)
+
+// GamutOutsideThreshold is how far from its gamut, in xy, a color has to be
+// for the difference to show
+const GamutOutsideThreshold = 0.01
+
+// Label returns the gamut type for display: A, B, C or custom
+func (g *Gamut) Label() string {
+	switch g.Type {
+	case "A", "B", "C":
+		return g.Type
+	}
+	return "custom"
+}
+
+// Closest returns the color of the gamut closest to x, y: the color itself
+// when inside, else the nearest point of the triangle's edges
+func (g *Gamut) Closest(x, y float64) (float64, float64) {
+	p := GamutPoint{x, y}
+	if g.contains(p) {
+		return x, y
+	}
+	best, bestDist := p, math.Inf(1)
+	for _, edge := range [][2]GamutPoint{{g.Red, g.Green}, {g.Green, g.Blue}, {g.Blue, g.Red}} {
+		c := closestOnSegment(p, edge[0], edge[1])
+		if d := math.Hypot(c.X-x, c.Y-y); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best.X, best.Y
+}
+
+// Distance returns how far x, y is outside the gamut, 0 when inside
+func (g *Gamut) Distance(x, y float64) float64 {
+	cx, cy := g.Closest(x, y)
+	return math.Hypot(cx-x, cy-y)
+}
+
+// contains reports whether p is inside the triangle, edges included
+func (g *Gamut) contains(p GamutPoint) bool {
+	d1 := cross(p, g.Red, g.Green)
+	d2 := cross(p, g.Green, g.Blue)
+	d3 := cross(p, g.Blue, g.Red)
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
+
+// cross is the side of the line a-b that p is on
+func cross(p, a, b GamutPoint) float64 {
+	return (p.X-b.X)*(a.Y-b.Y) - (a.X-b.X)*(p.Y-b.Y)
+}
+
+// closestOnSegment returns the point of the segment a-b closest to p
+func closestOnSegment(p, a, b GamutPoint) GamutPoint {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / (dx*dx + dy*dy)
+	t = max(0, min(1, t))
+	return GamutPoint{a.X + t*dx, a.Y + t*dy}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGamutDistance(t *testing.T) {
+	tests := []struct {
+		name  string
+		gamut Gamut
+		x, y  float64
+		want  float64
+	}{
+		{"white inside C", GamutC, 0.3127, 0.329, 0},
+		{"corner", GamutC, 0.6915, 0.3083, 0},
+		{"green outside B", GamutB, 0.17, 0.7, 0.3004},
+		{"deep blue outside B", GamutB, 0.1532, 0.0475, 0.0157},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.gamut.Distance(tt.x, tt.y); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("Distance(%v, %v) = %.4f, want %.4f", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGamutClosest(t *testing.T) {
+	// Beyond the red corner, the corner is the closest color
+	x, y := GamutB.Closest(0.75, 0.3)
+	if math.Abs(x-0.675) > 0.01 || math.Abs(y-0.322) > 0.01 {
+		t.Errorf("Closest() = %.3f, %.3f, want the red corner", x, y)
+	}
+}
+
+func TestGamutLabel(t *testing.T) {
+	custom := Gamut{Type: "other"}
+	if GamutA.Label() != "A" || custom.Label() != "custom" {
+		t.Errorf("Label() = %q, %q", GamutA.Label(), custom.Label())
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+// Gradient is the color points of a gradient light, such as a Play gradient
+// strip, from one end of the light to the other
+type Gradient struct {
+	Points []*Color
+	// Maximum number of points the light accepts
+	PointsCapable int
+}
+
+// MinGradientPoints is the fewest points a gradient can be set with
+const MinGradientPoints = 2
+
+// Clone creates a deep copy of the gradient
+func (g *Gradient) Clone() *Gradient {
+	clone := *g
+	clone.Points = make([]*Color, len(g.Points))
+	for i, point := range g.Points {
+		pointCopy := *point
+		clone.Points[i] = &pointCopy
+	}
+	return &clone
+}
+
+// ColorAt returns the color at position t (0 to 1) along the gradient,
+// blending the two nearest points
+func (g *Gradient) ColorAt(t float64) (r, gr, b uint8) {
+	switch len(g.Points) {
+	case 0:
+		return 0, 0, 0
+	case 1:
+		return g.Points[0].RGB()
+	}
+	t = max(0, min(1, t))
+
+	pos := t * float64(len(g.Points)-1)
+	i := min(int(pos), len(g.Points)-2)
+	frac := pos - float64(i)
+
+	r1, g1, b1 := g.Points[i].RGB()
+	r2, g2, b2 := g.Points[i+1].RGB()
+	blend := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*frac + 0.5)
+	}
+	return blend(r1, r2), blend(g1, g2), blend(b1, b2)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "testing"
+
+func TestGradientColorAt(t *testing.T) {
+	red := &Color{Mode: ColorModeHS, Hue: 0, Saturation: 254, Brightness: 254}
+	blue := &Color{Mode: ColorModeHS, Hue: 43690, Saturation: 254, Brightness: 254}
+	g := &Gradient{Points: []*Color{red, blue}}
+
+	r, _, b := g.ColorAt(0)
+	if r < 200 || b > 50 {
+		t.Errorf("Expected red at the start, got r=%d b=%d", r, b)
+	}
+	r, _, b = g.ColorAt(1)
+	if r > 50 || b < 200 {
+		t.Errorf("Expected blue at the end, got r=%d b=%d", r, b)
+	}
+	r, _, b = g.ColorAt(0.5)
+	if r < 100 || r > 155 || b < 100 || b > 155 {
+		t.Errorf("Expected a blend in the middle, got r=%d b=%d", r, b)
+	}
+
+	// Out of range positions are clamped, single points are solid
+	if rgb(g.ColorAt(2)) != rgb(g.ColorAt(1)) {
+		t.Error("Expected positions past the end to be clamped")
+	}
+	solid := &Gradient{Points: []*Color{red}}
+	if rgb(solid.ColorAt(0.7)) != rgb(red.RGB()) {
+		t.Error("Expected a single point to be solid")
+	}
+}
+
+func rgb(r, g, b uint8) [3]uint8 {
+	return [3]uint8{r, g, b}
+}
@@ -12,32 +12,66 @@ type Light struct {
 	Brightness uint8
 	// Whether the light is reachable on the network
 	Reachable bool
+	// Consecutive commands to this light that failed
+	Failures int
 	// Color state (may be nil for non-color lights)
 	Color *Color
+	// Gradient points (nil for lights without gradient support)
+	Gradient *Gradient
 	// Whether the light supports color
 	SupportsColor bool
+	// Colors the light can show (nil when not reported)
+	Gamut *Gamut
 	// Whether the light supports color temperature
 	SupportsColorTemp bool
+	// Color temperature range in mirek (0 when not reported)
+	MirekMin int
+	MirekMax int
+	// Whether the light can only be switched on and off, like a smart plug
+	OnOffOnly bool
+	// Light type from LightArchetypes, set in the Hue app
+	Archetype string
 	// ID of the room this light belongs to (empty if ungrouped)
 	RoomID string
 	// Device ID that owns this light service
 	DeviceID string
+	// Name of the owning device (empty if unknown)
+	DeviceName string
 }
 
 // BrightnessPct returns the brightness as a percentage (0-100)
 func (l *Light) BrightnessPct() int {
-	return int(float64(l.Brightness) / 254.0 * 100)
+	return LevelToPct(l.Brightness)
 }
 
 // SetBrightnessPct sets brightness from a percentage (0-100)
 func (l *Light) SetBrightnessPct(pct int) {
-	if pct < 0 {
-		pct = 0
-	}
-	if pct > 100 {
-		pct = 100
+	l.Brightness = PctToLevel(float64(pct))
+}
+
+// MirekRange returns the color temperature range of the light in mirek,
+// the full Hue range when the light doesn't report its own
+func (l *Light) MirekRange() (int, int) {
+	lo, hi := MirekMin, MirekMax
+	if l.MirekMin > 0 && l.MirekMax > l.MirekMin {
+		lo, hi = l.MirekMin, l.MirekMax
 	}
-	l.Brightness = uint8(float64(pct) / 100.0 * 254)
+	return lo, hi
+}
+
+// ClampMirek returns the closest color temperature the light can show
+func (l *Light) ClampMirek(mirek int) int {
+	lo, hi := l.MirekRange()
+	return min(hi, max(lo, mirek))
+}
+
+// MaxLightFailures is the number of consecutive failed commands after which
+// a light is considered faulty
+const MaxLightFailures = 2
+
+// Faulty returns true if the light is unreachable or keeps failing commands
+func (l *Light) Faulty() bool {
+	return !l.Reachable || l.Failures >= MaxLightFailures
 }
 
 // IsColorLight returns true if the light supports any color features
@@ -52,5 +86,23 @@ func (l *Light) Clone() *Light {
 		colorCopy := *l.Color
 		clone.Color = &colorCopy
 	}
+	if l.Gamut != nil {
+		gamutCopy := *l.Gamut
+		clone.Gamut = &gamutCopy
+	}
+	if l.Gradient != nil {
+		clone.Gradient = l.Gradient.Clone()
+	}
 	return &clone
 }
+
+// LightMemory is the brightness and color a light had when it was turned
+// off, to turn it back on with
+type LightMemory struct {
+	// Brightness in percent
+	Brightness int
+	// Color temperature in mirek, or 0 for a color
+	Mirek int
+	// CIE xy color, used when Mirek is 0
+	X, Y float64
+}
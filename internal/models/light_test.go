@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestLightBrightnessPctRoundTrip(t *testing.T) {
+	var light Light
+	for pct := 0; pct <= 100; pct++ {
+		light.SetBrightnessPct(pct)
+		if got := light.BrightnessPct(); got != pct {
+			t.Errorf("SetBrightnessPct(%d) reads back as %d%%", pct, got)
+		}
+	}
+}
+
+func TestLightClampMirek(t *testing.T) {
+	ambiance := &Light{MirekMin: 153, MirekMax: 454}
+	unknown := &Light{}
+	tests := []struct {
+		light *Light
+		mirek int
+		want  int
+	}{
+		{ambiance, KelvinToMirek(2000), 454},
+		{ambiance, KelvinToMirek(2700), 370},
+		{ambiance, 100, 153},
+		{unknown, KelvinToMirek(2000), 500},
+		{unknown, KelvinToMirek(10000), 153},
+	}
+	for _, tt := range tests {
+		if got := tt.light.ClampMirek(tt.mirek); got != tt.want {
+			t.Errorf("ClampMirek(%d) with range %d-%d = %d, want %d", tt.mirek, tt.light.MirekMin, tt.light.MirekMax, got, tt.want)
+		}
+	}
+}
+
+func TestLightCloneGradient(t *testing.T) {
+	light := &Light{Gradient: &Gradient{
+		Points:        []*Color{{X: 0.6, Y: 0.3}, {X: 0.2, Y: 0.1}},
+		PointsCapable: 5,
+	}}
+	clone := light.Clone()
+
+	light.Gradient.Points[0].X = 0.1
+	light.Gradient.Points = append(light.Gradient.Points[:1], &Color{X: 0.4, Y: 0.4})
+	light.Gradient.PointsCapable = 3
+
+	g := clone.Gradient
+	if len(g.Points) != 2 || g.Points[0].X != 0.6 || g.Points[1].X != 0.2 || g.PointsCapable != 5 {
+		t.Errorf("Expected the clone's gradient to be left alone, got %+v", g)
+	}
+}
+
+func TestLightCloneGamut(t *testing.T) {
+	gamut := GamutC
+	light := &Light{Gamut: &gamut}
+	clone := light.Clone()
+
+	light.Gamut.Red.X = 0.5
+	if clone.Gamut == light.Gamut || clone.Gamut.Red != GamutC.Red {
+		t.Errorf("Expected the clone's gamut to be left alone, got %+v", clone.Gamut)
+	}
+}
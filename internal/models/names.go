@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DisplayNames returns a name telling each light apart from the others, by
+// light ID. Lights sharing a name (ignoring case) get their room added, then
+// their device when the room is not enough, as in "Ceiling Light (Kitchen)".
+// Lights still alike after that are numbered in ID order.
+func DisplayNames(lights []*Light, rooms []*Room) map[string]string {
+	roomOf := make(map[string]string)
+	for _, light := range lights {
+		for _, room := range rooms {
+			if slices.ContainsFunc(room.Lights, func(l *Light) bool { return l.ID == light.ID }) ||
+				(light.DeviceID != "" && slices.Contains(room.DeviceIDs, light.DeviceID)) {
+				roomOf[light.ID] = room.Name
+				break
+			}
+		}
+	}
+
+	suffixes := make(map[string][]string)
+	name := func(light *Light) string {
+		if len(suffixes[light.ID]) == 0 {
+			return light.Name
+		}
+		return light.Name + " (" + strings.Join(suffixes[light.ID], ", ") + ")"
+	}
+
+	passes := []func(*Light) string{
+		func(l *Light) string { return roomOf[l.ID] },
+		func(l *Light) string { return l.DeviceName },
+	}
+	for _, suffix := range passes {
+		for _, group := range sharedNames(lights, name) {
+			for _, light := range group {
+				if s := suffix(light); s != "" {
+					suffixes[light.ID] = append(suffixes[light.ID], s)
+				}
+			}
+		}
+	}
+
+	names := make(map[string]string, len(lights))
+	for _, light := range lights {
+		names[light.ID] = name(light)
+	}
+	for _, group := range sharedNames(lights, name) {
+		slices.SortFunc(group, func(a, b *Light) int { return strings.Compare(a.ID, b.ID) })
+		for i, light := range group {
+			names[light.ID] = fmt.Sprintf("%s #%d", name(light), i+1)
+		}
+	}
+	return names
+}
+
+// sharedNames returns the groups of lights whose names are alike, in the
+// order of lights
+func sharedNames(lights []*Light, name func(*Light) string) [][]*Light {
+	byName := make(map[string][]*Light)
+	var order []string
+	for _, light := range lights {
+		key := strings.ToLower(name(light))
+		if _, ok := byName[key]; !ok {
+			order = append(order, key)
+		}
+		byName[key] = append(byName[key], light)
+	}
+
+	var groups [][]*Light
+	for _, key := range order {
+		if len(byName[key]) > 1 {
+			groups = append(groups, byName[key])
+		}
+	}
+	return groups
+}
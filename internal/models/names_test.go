@@ -0,0 +1,36 @@
+package models
+
+import "testing"
+
+func TestDisplayNames(t *testing.T) {
+	lights := []*Light{
+		{ID: "l1", Name: "Ceiling Light", DeviceID: "d1"},
+		{ID: "l2", Name: "ceiling light", DeviceID: "d2"},
+		{ID: "l3", Name: "Ceiling Light", DeviceID: "d3", DeviceName: "Hue bulb"},
+		{ID: "l4", Name: "Ceiling Light", DeviceID: "d4", DeviceName: "Hue spot"},
+		{ID: "l6", Name: "Strip", DeviceID: "d5"},
+		{ID: "l5", Name: "Strip", DeviceID: "d5"},
+		{ID: "l7", Name: "Desk"},
+	}
+	rooms := []*Room{
+		{ID: "r1", Name: "Kitchen", DeviceIDs: []string{"d1", "d5"}},
+		{ID: "r2", Name: "Bedroom", Lights: []*Light{lights[1]}},
+		{ID: "r3", Name: "Hall", DeviceIDs: []string{"d3", "d4"}},
+	}
+
+	want := map[string]string{
+		"l1": "Ceiling Light (Kitchen)",
+		"l2": "ceiling light (Bedroom)",
+		"l3": "Ceiling Light (Hall, Hue bulb)",
+		"l4": "Ceiling Light (Hall, Hue spot)",
+		"l5": "Strip (Kitchen) #1",
+		"l6": "Strip (Kitchen) #2",
+		"l7": "Desk",
+	}
+	got := DisplayNames(lights, rooms)
+	for id, name := range want {
+		if got[id] != name {
+			t.Errorf("DisplayNames()[%s] = %q, want %q", id, got[id], name)
+		}
+	}
+}
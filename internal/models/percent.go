@@ -0,0 +1,47 @@
+package models
+
+import "math"
+
+// MaxLevel is the top of the 0-254 scale brightness and saturation use
+const MaxLevel = 254
+
+// PctToLevel converts a percentage (0-100) to the 0-254 scale, rounding.
+// Out of range percentages are clamped.
+func PctToLevel(pct float64) uint8 {
+	pct = math.Max(0, math.Min(100, pct))
+	return uint8(math.Round(pct / 100 * MaxLevel))
+}
+
+// LevelToPct converts a 0-254 value to a whole percentage, rounding. Every
+// whole percentage survives a round trip through PctToLevel.
+func LevelToPct(level uint8) int {
+	return int(math.Round(float64(level) / MaxLevel * 100))
+}
+
+// LevelToPctPrecise converts a 0-254 value to a percentage with one
+// decimal, as the bridge takes them
+func LevelToPctPrecise(level uint8) float64 {
+	return math.Round(float64(level)/MaxLevel*1000) / 10
+}
+
+// MaxHue is the top of the 0-65535 scale hue uses
+const MaxHue = 65535
+
+// DegToHue converts a hue in degrees to the 0-65535 scale, rounding.
+// Degrees wrap around, so 360 is 0.
+func DegToHue(deg int) uint16 {
+	deg = (deg%360 + 360) % 360
+	return uint16(math.Round(float64(deg) / 360 * MaxHue))
+}
+
+// HueToDeg converts a 0-65535 hue to whole degrees (0-359), rounding.
+// Every whole degree survives a round trip through DegToHue.
+func HueToDeg(hue uint16) int {
+	return int(math.Round(float64(hue)/MaxHue*360)) % 360
+}
+
+// RoundPct rounds a percentage reported by the bridge, such as 49.8, to a
+// whole one
+func RoundPct(pct float64) int {
+	return int(math.Round(pct))
+}
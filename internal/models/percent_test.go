@@ -0,0 +1,70 @@
+package models
+
+import "testing"
+
+func TestPctToLevel(t *testing.T) {
+	tests := []struct {
+		pct  float64
+		want uint8
+	}{
+		{-5, 0},
+		{0, 0},
+		{1, 3},
+		{49.8, 126},
+		{50, 127},
+		{100, 254},
+		{120, 254},
+	}
+	for _, tt := range tests {
+		if got := PctToLevel(tt.pct); got != tt.want {
+			t.Errorf("PctToLevel(%v) = %d, want %d", tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestLevelRoundTrip(t *testing.T) {
+	for pct := 0; pct <= 100; pct++ {
+		if got := LevelToPct(PctToLevel(float64(pct))); got != pct {
+			t.Errorf("%d%% reads back as %d%%", pct, got)
+		}
+	}
+	if got := LevelToPctPrecise(127); got != 50 {
+		t.Errorf("LevelToPctPrecise(127) = %v, want 50", got)
+	}
+	if got := RoundPct(49.8); got != 50 {
+		t.Errorf("RoundPct(49.8) = %d, want 50", got)
+	}
+}
+
+func TestColorBrightnessPct(t *testing.T) {
+	c := NewColorFromMirek(300, PctToLevel(50))
+	if got := c.BrightnessPct(); got != 50 {
+		t.Errorf("BrightnessPct() = %d, want 50", got)
+	}
+}
+
+func TestDegToHue(t *testing.T) {
+	tests := []struct {
+		deg  int
+		want uint16
+	}{
+		{0, 0},
+		{180, 32768},
+		{359, 65353},
+		{360, 0},
+		{-1, 65353},
+	}
+	for _, tt := range tests {
+		if got := DegToHue(tt.deg); got != tt.want {
+			t.Errorf("DegToHue(%d) = %d, want %d", tt.deg, got, tt.want)
+		}
+	}
+	if got := HueToDeg(MaxHue); got != 0 {
+		t.Errorf("HueToDeg(%d) = %d, want 0", MaxHue, got)
+	}
+	for deg := 0; deg < 360; deg++ {
+		if got := HueToDeg(DegToHue(deg)); got != deg {
+			t.Errorf("%d° reads back as %d°", deg, got)
+		}
+	}
+}
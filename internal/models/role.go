@@ -0,0 +1,27 @@
+package models
+
+// LightRole is a local tag describing what a light is used for.
+// Roles are stored in the config, the bridge knows nothing about them.
+type LightRole string
+
+const (
+	// RoleTVBias marks lights behind or facing the TV
+	RoleTVBias LightRole = "tv-bias"
+	// RoleAmbient marks decorative and mood lights
+	RoleAmbient LightRole = "ambient"
+	// RoleTask marks reading, desk and work lights
+	RoleTask LightRole = "task"
+)
+
+// LightRoles lists the supported roles in display order
+var LightRoles = []LightRole{RoleTVBias, RoleAmbient, RoleTask}
+
+// Valid returns true if the role is one of LightRoles
+func (r LightRole) Valid() bool {
+	for _, role := range LightRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -1,17 +1,23 @@
 package models
 
+import "math"
+
 // Room represents a Philips Hue room/zone
 type Room struct {
 	// Unique identifier from the bridge
 	ID string
 	// User-friendly name
 	Name string
+	// Room type set in the Hue app ("living_room", "bedroom"...)
+	Archetype string
 	// Lights in this room
 	Lights []*Light
 	// GroupedLight service ID for room-level control
 	GroupedLightID string
 	// Device IDs that belong to this room
 	DeviceIDs []string
+	// Light IDs that belong to this zone (zones group lights, not devices)
+	LightIDs []string
 	// Calculated state: all lights are on
 	AllOn bool
 	// Calculated state: at least one light is on
@@ -48,7 +54,9 @@ func (r *Room) LightByID(id string) *Light {
 	return nil
 }
 
-// AverageBrightness returns the average brightness of all on lights
+// AverageBrightness returns the average brightness of all on lights.
+// Faulty lights are left out, as their state can't be trusted, and so are
+// on/off-only lights, which have no brightness.
 func (r *Room) AverageBrightness() int {
 	if len(r.Lights) == 0 {
 		return 0
@@ -57,7 +65,7 @@ func (r *Room) AverageBrightness() int {
 	var total int
 	var count int
 	for _, light := range r.Lights {
-		if light.On {
+		if light.On && !light.Faulty() && !light.OnOffOnly {
 			total += light.BrightnessPct()
 			count++
 		}
@@ -66,7 +74,18 @@ func (r *Room) AverageBrightness() int {
 	if count == 0 {
 		return 0
 	}
-	return total / count
+	return int(math.Round(float64(total) / float64(count)))
+}
+
+// FaultyLights returns the lights that are unreachable or failing commands
+func (r *Room) FaultyLights() []*Light {
+	var faulty []*Light
+	for _, light := range r.Lights {
+		if light.Faulty() {
+			faulty = append(faulty, light)
+		}
+	}
+	return faulty
 }
 
 // ReachableLights returns only the lights that are reachable
@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+func TestAverageBrightnessSkipsFaultyLights(t *testing.T) {
+	room := &Room{Lights: []*Light{
+		{ID: "a", On: true, Reachable: true, Brightness: 254},
+		{ID: "b", On: true, Reachable: true, Brightness: 127},
+		// Unreachable lights keep their last known state
+		{ID: "c", On: true, Reachable: false, Brightness: 0},
+		// Lights failing commands are left out too
+		{ID: "d", On: true, Reachable: true, Brightness: 0, Failures: MaxLightFailures},
+		// Smart plugs have no brightness
+		{ID: "e", On: true, Reachable: true, OnOffOnly: true},
+	}}
+
+	if got := room.AverageBrightness(); got != 75 {
+		t.Errorf("AverageBrightness() = %d, want 75", got)
+	}
+	if faulty := room.FaultyLights(); len(faulty) != 2 || faulty[0].ID != "c" || faulty[1].ID != "d" {
+		t.Errorf("FaultyLights() = %v, want c and d", faulty)
+	}
+
+	room.Lights[3].Failures = MaxLightFailures - 1
+	if room.Lights[3].Faulty() {
+		t.Error("Expected a single failure not to mark the light faulty")
+	}
+}
+
+func TestAverageBrightnessRounds(t *testing.T) {
+	room := &Room{Lights: []*Light{
+		{ID: "a", On: true, Reachable: true, Brightness: PctToLevel(1)},
+		{ID: "b", On: true, Reachable: true, Brightness: PctToLevel(2)},
+	}}
+	if got := room.AverageBrightness(); got != 2 {
+		t.Errorf("AverageBrightness() of 1%% and 2%% = %d, want 2", got)
+	}
+}
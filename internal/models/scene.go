@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Scene represents a Philips Hue scene
 type Scene struct {
 	// Unique identifier from the bridge
@@ -12,6 +14,62 @@ type Scene struct {
 	RoomName string
 	// Whether this is a dynamic scene
 	IsDynamic bool
+	// Activation status reported by the bridge ("inactive", "static", "dynamic_palette")
+	Status string
+	// Colors of the scene palette (may be empty)
+	Palette []*Color
+	// State the scene gives each of its lights
+	Actions []SceneAction
+	// Last time the scene was activated (zero if never or unknown)
+	LastRecalled time.Time
+	// Whether this is a smart scene, switching scenes through the day.
+	// Its status is "active" or "inactive".
+	IsSmart bool
+}
+
+// SceneAction is the state a scene gives one light
+type SceneAction struct {
+	LightID string
+	On      bool
+	// Brightness level (0-254)
+	Brightness uint8
+	// Color set by the scene (nil if it only sets on and brightness)
+	Color *Color
+}
+
+// defaultSceneMirek is the white shown for scene lights without a color
+const defaultSceneMirek = 366
+
+// Swatches returns one color per light the scene turns on, shown at full
+// brightness so dim lights remain visible. Lights without a color are
+// shown as warm white.
+func (s *Scene) Swatches() []*Color {
+	var swatches []*Color
+	for _, action := range s.Actions {
+		if !action.On {
+			continue
+		}
+		if action.Color == nil {
+			swatches = append(swatches, NewColorFromMirek(defaultSceneMirek, 254))
+			continue
+		}
+		swatch := *action.Color
+		swatch.Brightness = 254
+		swatch.InvalidateCache()
+		swatches = append(swatches, &swatch)
+	}
+	return swatches
+}
+
+// IsActive returns true if the bridge reports the scene as currently active
+func (s *Scene) IsActive() bool {
+	return s.Status != "" && s.Status != "inactive"
+}
+
+// IsCycling returns true if the scene is active and cycling through its
+// palette
+func (s *Scene) IsCycling() bool {
+	return s.Status == "dynamic_palette"
 }
 
 // ScenesByRoom groups scenes by their room ID
@@ -22,3 +80,25 @@ func ScenesByRoom(scenes []*Scene) map[string][]*Scene {
 	}
 	return grouped
 }
+
+// SceneShortcut returns the scene activated by shortcut n (1-9) in a room:
+// the scene bound to it, by ID, or else the nth scene of the room
+func SceneShortcut(scenes []*Scene, roomID, boundID string, n int) *Scene {
+	var roomScenes []*Scene
+	for _, scene := range scenes {
+		if scene.RoomID == roomID {
+			roomScenes = append(roomScenes, scene)
+		}
+	}
+	if boundID != "" {
+		for _, scene := range roomScenes {
+			if scene.ID == boundID {
+				return scene
+			}
+		}
+	}
+	if n >= 1 && n <= len(roomScenes) {
+		return roomScenes[n-1]
+	}
+	return nil
+}
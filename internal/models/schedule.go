@@ -0,0 +1,212 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleKind describes what a schedule does
+type ScheduleKind string
+
+const (
+	// ScheduleWakeUp fades lights in, reaching full brightness at a time of day
+	ScheduleWakeUp ScheduleKind = "wake_up"
+	// ScheduleSleep fades lights out and turns them off at a time of day
+	ScheduleSleep ScheduleKind = "go_to_sleep"
+	// ScheduleSmartScene is a smart scene switching scenes through the day
+	ScheduleSmartScene ScheduleKind = "smart_scene"
+	// ScheduleTurnOn turns lights on, run by hue-tui itself
+	ScheduleTurnOn ScheduleKind = "turn_on"
+	// ScheduleActivateScene activates a scene, run by hue-tui itself
+	ScheduleActivateScene ScheduleKind = "activate_scene"
+	// ScheduleOther is an automation this app does not know how to describe
+	ScheduleOther ScheduleKind = "other"
+)
+
+// Label returns a human-readable name for the kind
+func (k ScheduleKind) Label() string {
+	switch k {
+	case ScheduleWakeUp:
+		return "Wake up"
+	case ScheduleSleep:
+		return "Turn off"
+	case ScheduleSmartScene:
+		return "Smart scene"
+	case ScheduleTurnOn:
+		return "Turn on"
+	case ScheduleActivateScene:
+		return "Scene"
+	default:
+		return "Automation"
+	}
+}
+
+// SunEvent anchors a schedule to sunrise or sunset instead of a clock time
+type SunEvent string
+
+const (
+	Sunrise SunEvent = "sunrise"
+	Sunset  SunEvent = "sunset"
+)
+
+// ScheduleLocal is the resource of schedules run by hue-tui rather than the
+// bridge
+const ScheduleLocal = "local"
+
+// Schedule is a time-based automation on the bridge, backed by a
+// behavior_instance or smart_scene resource, or run locally
+type Schedule struct {
+	// Unique identifier from the bridge
+	ID string
+	// Bridge resource type ("behavior_instance" or "smart_scene"), or
+	// ScheduleLocal
+	Resource string
+	// User-friendly name
+	Name string
+	Kind ScheduleKind
+	// Whether the bridge will run the schedule
+	Enabled bool
+	// Time of day, if HasTime
+	Hour, Minute int
+	HasTime      bool
+	// Sunrise or sunset the time is relative to (empty = clock time). Hour
+	// and Minute are then today's time, when the location is known.
+	Sun       SunEvent
+	SunOffset time.Duration
+	// Days the schedule repeats on (empty = runs once)
+	Days []time.Weekday
+	// Cron expression of local schedules, replacing the time and days
+	Cron string
+	// Duration of the fade in or out
+	Fade time.Duration
+	// Room or zone the schedule acts on
+	GroupID string
+	// Lights within the group (empty = the whole group)
+	LightIDs []string
+	// Scene activated by ScheduleActivateScene
+	SceneID string
+}
+
+// ScheduleRun is an upcoming run of a schedule
+type ScheduleRun struct {
+	Schedule *Schedule
+	At       time.Time
+}
+
+// TimeString returns the time of day as "07:00" or "sunset-15m (18:27)",
+// or "" if there is none
+func (s *Schedule) TimeString() string {
+	return s.FormatTime(func(hour, minute int) string {
+		return fmt.Sprintf("%02d:%02d", hour, minute)
+	})
+}
+
+// FormatTime is TimeString with the clock time formatted by clock
+func (s *Schedule) FormatTime(clock func(hour, minute int) string) string {
+	at := ""
+	if s.HasTime {
+		at = clock(s.Hour, s.Minute)
+	}
+	if s.Sun == "" {
+		return at
+	}
+	if at == "" {
+		return FormatSunTime(s.Sun, s.SunOffset)
+	}
+	return FormatSunTime(s.Sun, s.SunOffset) + " (" + at + ")"
+}
+
+// ParseSunTime parses a time relative to the sun: "sunset", "sunset-15m",
+// "sunrise+1h30m"
+func ParseSunTime(s string) (SunEvent, time.Duration, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, event := range []SunEvent{Sunrise, Sunset} {
+		rest, found := strings.CutPrefix(s, string(event))
+		if !found {
+			continue
+		}
+		if rest == "" {
+			return event, 0, true
+		}
+		sign := time.Duration(1)
+		switch rest[0] {
+		case '-':
+			sign = -1
+		case '+':
+		default:
+			return "", 0, false
+		}
+		offset, err := time.ParseDuration(rest[1:])
+		if err != nil || offset < 0 || offset >= 12*time.Hour {
+			return "", 0, false
+		}
+		return event, sign * offset, true
+	}
+	return "", 0, false
+}
+
+// FormatSunTime formats a time relative to the sun, as read by ParseSunTime
+func FormatSunTime(event SunEvent, offset time.Duration) string {
+	if offset == 0 {
+		return string(event)
+	}
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	offset = offset.Round(time.Minute)
+	h, m := int(offset.Hours()), int(offset.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%s%s%dm", event, sign, m)
+	case m == 0:
+		return fmt.Sprintf("%s%s%dh", event, sign, h)
+	}
+	return fmt.Sprintf("%s%s%dh%dm", event, sign, h, m)
+}
+
+// Weekdays and weekends, for the common day presets
+var (
+	Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	Weekends = []time.Weekday{time.Saturday, time.Sunday}
+	EveryDay = append(append([]time.Weekday{}, Weekdays...), Weekends...)
+)
+
+// DaysString describes recurrence days ("every day", "weekdays", "Mon, Thu"...)
+func DaysString(days []time.Weekday) string {
+	set := make(map[time.Weekday]bool)
+	for _, d := range days {
+		set[d] = true
+	}
+	matches := func(preset []time.Weekday) bool {
+		if len(set) != len(preset) {
+			return false
+		}
+		for _, d := range preset {
+			if !set[d] {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case len(set) == 0:
+		return "once"
+	case matches(EveryDay):
+		return "every day"
+	case matches(Weekdays):
+		return "weekdays"
+	case matches(Weekends):
+		return "weekends"
+	}
+
+	var names []string
+	for _, d := range EveryDay {
+		if set[d] {
+			names = append(names, d.String()[:3])
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaysString(t *testing.T) {
+	tests := []struct {
+		days []time.Weekday
+		want string
+	}{
+		{nil, "once"},
+		{EveryDay, "every day"},
+		{[]time.Weekday{time.Friday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday}, "weekdays"},
+		{Weekends, "weekends"},
+		{[]time.Weekday{time.Sunday, time.Monday}, "Mon, Sun"},
+	}
+
+	for _, tt := range tests {
+		if got := DaysString(tt.days); got != tt.want {
+			t.Errorf("DaysString(%v) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestParseSunTime(t *testing.T) {
+	tests := []struct {
+		in     string
+		event  SunEvent
+		offset time.Duration
+		ok     bool
+	}{
+		{"sunset", Sunset, 0, true},
+		{"Sunset-15m", Sunset, -15 * time.Minute, true},
+		{"sunrise+1h30m", Sunrise, 90 * time.Minute, true},
+		{"sunrise 15m", "", 0, false},
+		{"sunset-", "", 0, false},
+		{"sunset+13h", "", 0, false},
+		{"07:00", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		event, offset, ok := ParseSunTime(tt.in)
+		if event != tt.event || offset != tt.offset || ok != tt.ok {
+			t.Errorf("ParseSunTime(%q) = %q, %v, %v, want %q, %v, %v", tt.in, event, offset, ok, tt.event, tt.offset, tt.ok)
+		}
+		if ok {
+			if got := FormatSunTime(event, offset); !strings.EqualFold(got, tt.in) {
+				t.Errorf("FormatSunTime(%q, %v) = %q, want %q", event, offset, got, tt.in)
+			}
+		}
+	}
+}
+
+func TestScheduleTimeString(t *testing.T) {
+	s := &Schedule{Sun: Sunset, SunOffset: -15 * time.Minute}
+	if got := s.TimeString(); got != "sunset-15m" {
+		t.Errorf("TimeString() = %q, want %q", got, "sunset-15m")
+	}
+	s.Hour, s.Minute, s.HasTime = 18, 27, true
+	if got := s.TimeString(); got != "sunset-15m (18:27)" {
+		t.Errorf("TimeString() = %q, want %q", got, "sunset-15m (18:27)")
+	}
+	clock := func(hour, minute int) string { return fmt.Sprintf("%d:%02d PM", hour-12, minute) }
+	if got := s.FormatTime(clock); got != "sunset-15m (6:27 PM)" {
+		t.Errorf("FormatTime() = %q, want %q", got, "sunset-15m (6:27 PM)")
+	}
+}
@@ -0,0 +1,62 @@
+package plan
+
+import (
+	"slices"
+	"sort"
+)
+
+// Entry is a light, room, zone or scene with its bridge ID, so scripts
+// can refer to it by an ID that survives renames
+type Entry struct {
+	Kind string `json:"kind"` // "light", "room", "zone" or "scene"
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Room of a light, or room or zone of a scene (empty if none)
+	Group string `json:"group,omitempty"`
+}
+
+// Kinds are the kinds of entries, in the order List returns them
+var Kinds = []string{"light", "room", "zone", "scene"}
+
+// List returns the lights, rooms, zones and scenes of the bridge, each
+// kind sorted by name
+func List(state *State) []Entry {
+	groupNames := make(map[string]string)
+	for _, group := range append(slices.Clone(state.Rooms), state.Zones...) {
+		groupNames[group.ID] = group.Name
+	}
+
+	var lights, rooms, zones, scenes []Entry
+	for _, light := range state.Lights {
+		entry := Entry{Kind: "light", ID: light.ID, Name: light.Name}
+		// Rooms contain devices rather than lights
+		for _, room := range state.Rooms {
+			if slices.Contains(room.DeviceIDs, light.DeviceID) {
+				entry.Group = room.Name
+				break
+			}
+		}
+		lights = append(lights, entry)
+	}
+	for _, room := range state.Rooms {
+		rooms = append(rooms, Entry{Kind: "room", ID: room.ID, Name: room.Name})
+	}
+	for _, zone := range state.Zones {
+		zones = append(zones, Entry{Kind: "zone", ID: zone.ID, Name: zone.Name})
+	}
+	for _, scene := range state.Scenes {
+		scenes = append(scenes, Entry{Kind: "scene", ID: scene.ID, Name: scene.Name, Group: groupNames[scene.RoomID]})
+	}
+
+	var entries []Entry
+	for _, kind := range [][]Entry{lights, rooms, zones, scenes} {
+		sort.SliceStable(kind, func(i, j int) bool {
+			if kind[i].Name != kind[j].Name {
+				return kind[i].Name < kind[j].Name
+			}
+			return kind[i].Group < kind[j].Group
+		})
+		entries = append(entries, kind...)
+	}
+	return entries
+}
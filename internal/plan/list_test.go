@@ -0,0 +1,27 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func TestList(t *testing.T) {
+	state := testState()
+	state.Zones = []*models.Room{{ID: "z1", Name: "Upstairs", LightIDs: []string{"l2"}}}
+	state.Scenes = append(state.Scenes, &models.Scene{ID: "s2", Name: "Bright", RoomID: "z1"})
+
+	want := []Entry{
+		{Kind: "light", ID: "l2", Name: "Ceiling"},
+		{Kind: "light", ID: "l1", Name: "Hue color lamp 1", Group: "Study"},
+		{Kind: "light", ID: "l3", Name: "Strip", Group: "Study"},
+		{Kind: "room", ID: "r1", Name: "Study"},
+		{Kind: "zone", ID: "z1", Name: "Upstairs"},
+		{Kind: "scene", ID: "s1", Name: "Bright", Group: "Study"},
+		{Kind: "scene", ID: "s2", Name: "Bright", Group: "Upstairs"},
+	}
+	if got := List(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() =\n%+v\nwant\n%+v", got, want)
+	}
+}
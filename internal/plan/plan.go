@@ -0,0 +1,600 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// Reader is the subset of the bridge API needed to read its layout
+type Reader interface {
+	GetLights(ctx context.Context) ([]*models.Light, error)
+	GetRooms(ctx context.Context) ([]*models.Room, error)
+	GetZones(ctx context.Context) ([]*models.Room, error)
+	GetScenes(ctx context.Context) ([]*models.Scene, error)
+}
+
+// Bridge is the subset of the bridge API needed to provision it
+type Bridge interface {
+	RenameLight(ctx context.Context, lightID, name string) error
+	CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error)
+	UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error
+	CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error)
+	UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error
+	CreateScene(ctx context.Context, name, groupID, groupType string, actions []api.SceneAction) (string, error)
+	SetLightState(ctx context.Context, action api.SceneAction) error
+	SetGroupedLightState(ctx context.Context, groupedLightID string, action api.SceneAction) error
+}
+
+// Compile-time checks that HueBridge can be read and provisioned, and
+// that a dry run can stand in for it
+var (
+	_ Reader = (*api.HueBridge)(nil)
+	_ Bridge = (*api.HueBridge)(nil)
+	_ Bridge = (*api.DryRunBridge)(nil)
+)
+
+// State is the current bridge layout
+type State struct {
+	Lights []*models.Light
+	Rooms  []*models.Room
+	Zones  []*models.Room
+	Scenes []*models.Scene
+}
+
+// FetchState reads the current layout from the bridge
+func FetchState(ctx context.Context, b Reader) (*State, error) {
+	lights, err := b.GetLights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := b.GetRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := b.GetZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := b.GetScenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &State{Lights: lights, Rooms: rooms, Zones: zones, Scenes: scenes}, nil
+}
+
+// Op is the kind of change performed on a resource
+type Op int
+
+const (
+	OpCreate Op = iota
+	OpUpdate
+)
+
+// Change is a single create or update on the bridge
+type Change struct {
+	Op      Op
+	Kind    string // "light", "room", "zone" or "scene"
+	Name    string
+	Details []string
+
+	apply func(ctx context.Context, b Bridge, groupIDs map[string]string) error
+}
+
+// Plan is the ordered list of changes needed to reach a Spec
+type Plan struct {
+	Changes []*Change
+
+	// IDs of rooms and zones by "kind:name", completed as groups are created
+	groupIDs map[string]string
+}
+
+// Empty returns true if the bridge already matches the spec
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Counts returns the number of creates and updates in the plan
+func (p *Plan) Counts() (create, update int) {
+	for _, c := range p.Changes {
+		if c.Op == OpCreate {
+			create++
+		} else {
+			update++
+		}
+	}
+	return create, update
+}
+
+// Write prints the plan in a terraform-like format
+func (p *Plan) Write(w io.Writer) {
+	if p.Empty() {
+		_, _ = fmt.Fprintln(w, "No changes. The bridge already matches the plan.")
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "The following changes will be made:")
+	_, _ = fmt.Fprintln(w)
+	for _, c := range p.Changes {
+		symbol := "~"
+		if c.Op == OpCreate {
+			symbol = "+"
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s %q\n", symbol, c.Kind, c.Name)
+		for _, d := range c.Details {
+			_, _ = fmt.Fprintf(w, "      %s\n", d)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	create, update := p.Counts()
+	_, _ = fmt.Fprintf(w, "Plan: %d to create, %d to change.\n", create, update)
+}
+
+// Apply performs the changes in order, stopping at the first failure.
+// done is called after each change with its result.
+func (p *Plan) Apply(ctx context.Context, b Bridge, done func(*Change, error)) error {
+	for _, c := range p.Changes {
+		err := c.apply(ctx, b, p.groupIDs)
+		if done != nil {
+			done(c, err)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %q: %w", c.Kind, c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Build diffs the spec against the current state
+func Build(spec *Spec, state *State) (*Plan, error) {
+	b := &builder{
+		state:     state,
+		plan:      &Plan{groupIDs: make(map[string]string)},
+		finalName: make(map[string]string),
+	}
+	for _, light := range state.Lights {
+		b.finalName[light.ID] = light.Name
+	}
+
+	if err := b.lights(spec.Lights); err != nil {
+		return nil, err
+	}
+	if err := b.groups("room", spec.Rooms, state.Rooms); err != nil {
+		return nil, err
+	}
+	if err := b.groups("zone", spec.Zones, state.Zones); err != nil {
+		return nil, err
+	}
+	if err := b.scenes(spec.Scenes); err != nil {
+		return nil, err
+	}
+	if err := b.states(spec.States); err != nil {
+		return nil, err
+	}
+
+	return b.plan, nil
+}
+
+type builder struct {
+	state *State
+	plan  *Plan
+	// Light names after the plan's renames, by light ID
+	finalName map[string]string
+}
+
+func (b *builder) add(c *Change) {
+	b.plan.Changes = append(b.plan.Changes, c)
+}
+
+func (b *builder) lightByID(id string) *models.Light {
+	for _, light := range b.state.Lights {
+		if light.ID == id {
+			return light
+		}
+	}
+	return nil
+}
+
+// lightNamed finds the light whose name (current or final) matches. Lights
+// sharing a name are told apart by their display name, such as
+// "Ceiling Light (Kitchen)", and a shared name alone is an error.
+func (b *builder) lightNamed(name string, final bool) (*models.Light, error) {
+	lights := b.state.Lights
+	if final {
+		lights = make([]*models.Light, len(b.state.Lights))
+		for i, light := range b.state.Lights {
+			lights[i] = light.Clone()
+			lights[i].Name = b.finalName[light.ID]
+		}
+	}
+
+	var found []*models.Light
+	for i, light := range lights {
+		if light.Name == name {
+			found = append(found, b.state.Lights[i])
+		}
+	}
+	if len(found) == 1 {
+		return found[0], nil
+	}
+
+	displayNames := models.DisplayNames(lights, b.state.Rooms)
+	if len(found) > 1 {
+		var names []string
+		for _, light := range found {
+			names = append(names, strconv.Quote(displayNames[light.ID]))
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("several lights are named %q, use one of %s or the light ID", name, strings.Join(names, ", "))
+	}
+	for i, light := range lights {
+		if displayNames[light.ID] == name {
+			return b.state.Lights[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveLight finds a light by its name after renames, or by ID
+func (b *builder) resolveLight(ref string) (*models.Light, error) {
+	light, err := b.lightNamed(ref, true)
+	if err != nil {
+		return nil, err
+	}
+	if light == nil {
+		light = b.lightByID(ref)
+	}
+	if light == nil {
+		return nil, fmt.Errorf("unknown light %q", ref)
+	}
+	return light, nil
+}
+
+func (b *builder) lights(specs []LightSpec) error {
+	for _, ls := range specs {
+		var light *models.Light
+		var err error
+		switch {
+		case ls.ID != "":
+			light = b.lightByID(ls.ID)
+		case ls.From != "":
+			light, err = b.lightNamed(ls.From, false)
+			if light == nil && err == nil {
+				// Already renamed on a previous run
+				light, err = b.lightNamed(ls.Name, false)
+			}
+		default:
+			light, err = b.lightNamed(ls.Name, false)
+		}
+		if err != nil {
+			return fmt.Errorf("lights: %w", err)
+		}
+		if light == nil {
+			ref := ls.ID
+			if ref == "" {
+				ref = ls.From
+			}
+			if ref == "" {
+				ref = ls.Name
+			}
+			return fmt.Errorf("lights: no light matches %q", ref)
+		}
+
+		b.finalName[light.ID] = ls.Name
+		if light.Name == ls.Name {
+			continue
+		}
+
+		lightID, name := light.ID, ls.Name
+		b.add(&Change{
+			Op:      OpUpdate,
+			Kind:    "light",
+			Name:    light.Name,
+			Details: []string{fmt.Sprintf("name: %q → %q", light.Name, ls.Name)},
+			apply: func(ctx context.Context, br Bridge, _ map[string]string) error {
+				return br.RenameLight(ctx, lightID, name)
+			},
+		})
+	}
+	return nil
+}
+
+// members returns the member IDs of a group.
+// Rooms contain devices, zones contain lights.
+func (b *builder) members(kind string, group *models.Room) []string {
+	if kind == "zone" {
+		return group.LightIDs
+	}
+	return group.DeviceIDs
+}
+
+// label describes a room member (device) or zone member (light) by its lights
+func (b *builder) label(kind, id string) string {
+	if kind == "zone" {
+		if name, ok := b.finalName[id]; ok {
+			return name
+		}
+		return id
+	}
+	var names []string
+	for _, light := range b.state.Lights {
+		if light.DeviceID == id {
+			names = append(names, b.finalName[light.ID])
+		}
+	}
+	if len(names) == 0 {
+		return id
+	}
+	sort.Strings(names)
+	return strings.Join(names, "/")
+}
+
+// desiredMembers resolves the light references of a group spec into
+// member IDs (device IDs for rooms, light IDs for zones)
+func (b *builder) desiredMembers(kind string, refs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, ref := range refs {
+		light, err := b.resolveLight(ref)
+		if err != nil {
+			return nil, err
+		}
+		id := light.ID
+		if kind == "room" {
+			if light.DeviceID == "" {
+				return nil, fmt.Errorf("light %q has no owning device", ref)
+			}
+			id = light.DeviceID
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func groupNamed(groups []*models.Room, name string) *models.Room {
+	if name == "" {
+		return nil
+	}
+	for _, g := range groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+func (b *builder) groups(kind string, specs []GroupSpec, existing []*models.Room) error {
+	seen := make(map[string]bool)
+	for _, gs := range specs {
+		if seen[gs.Name] {
+			return fmt.Errorf("%ss: %q is defined twice", kind, gs.Name)
+		}
+		seen[gs.Name] = true
+
+		var desired []string
+		if gs.Lights != nil {
+			var err error
+			desired, err = b.desiredMembers(kind, gs.Lights)
+			if err != nil {
+				return fmt.Errorf("%s %q: %w", kind, gs.Name, err)
+			}
+		}
+
+		group := groupNamed(existing, gs.From)
+		if group == nil {
+			group = groupNamed(existing, gs.Name)
+		}
+
+		if group == nil {
+			b.addGroupCreate(kind, gs, desired)
+			continue
+		}
+
+		b.plan.groupIDs[kind+":"+gs.Name] = group.ID
+		b.addGroupUpdate(kind, gs, group, desired)
+	}
+	return nil
+}
+
+func (b *builder) addGroupCreate(kind string, gs GroupSpec, desired []string) {
+	archetype := gs.Archetype
+	if archetype == "" {
+		archetype = "other"
+	}
+	labels := make([]string, len(desired))
+	for i, id := range desired {
+		labels[i] = b.label(kind, id)
+	}
+	sort.Strings(labels)
+
+	details := []string{"archetype: " + archetype}
+	if len(labels) > 0 {
+		details = append(details, "lights: "+strings.Join(labels, ", "))
+	}
+
+	name := gs.Name
+	b.add(&Change{
+		Op:      OpCreate,
+		Kind:    kind,
+		Name:    name,
+		Details: details,
+		apply: func(ctx context.Context, br Bridge, groupIDs map[string]string) error {
+			var id string
+			var err error
+			if kind == "zone" {
+				id, err = br.CreateZone(ctx, name, archetype, desired)
+			} else {
+				id, err = br.CreateRoom(ctx, name, archetype, desired)
+			}
+			if err != nil {
+				return err
+			}
+			groupIDs[kind+":"+name] = id
+			return nil
+		},
+	})
+}
+
+func (b *builder) addGroupUpdate(kind string, gs GroupSpec, group *models.Room, desired []string) {
+	var details []string
+	var newName string
+	if group.Name != gs.Name {
+		newName = gs.Name
+		details = append(details, fmt.Sprintf("name: %q → %q", group.Name, gs.Name))
+	}
+
+	var children []string
+	if desired != nil {
+		current := make(map[string]bool)
+		for _, id := range b.members(kind, group) {
+			current[id] = true
+		}
+		want := make(map[string]bool)
+		var added, removed []string
+		for _, id := range desired {
+			want[id] = true
+			if !current[id] {
+				added = append(added, b.label(kind, id))
+			}
+		}
+		for id := range current {
+			if !want[id] {
+				removed = append(removed, b.label(kind, id))
+			}
+		}
+		sort.Strings(added)
+		sort.Strings(removed)
+		for _, l := range added {
+			details = append(details, "+ "+l)
+		}
+		for _, l := range removed {
+			details = append(details, "- "+l)
+		}
+		if len(added) > 0 || len(removed) > 0 {
+			children = desired
+		}
+	}
+
+	if newName == "" && children == nil {
+		return
+	}
+
+	groupID := group.ID
+	b.add(&Change{
+		Op:      OpUpdate,
+		Kind:    kind,
+		Name:    group.Name,
+		Details: details,
+		apply: func(ctx context.Context, br Bridge, _ map[string]string) error {
+			if kind == "zone" {
+				return br.UpdateZone(ctx, groupID, newName, children)
+			}
+			return br.UpdateRoom(ctx, groupID, newName, children)
+		},
+	})
+}
+
+func (b *builder) scenes(specs []SceneSpec) error {
+	for _, ss := range specs {
+		kind, groupName := "room", ss.Room
+		if ss.Zone != "" {
+			kind, groupName = "zone", ss.Zone
+		}
+		key := kind + ":" + groupName
+
+		groupID, exists := b.plan.groupIDs[key]
+		if !exists && !b.planCreates(kind, groupName) {
+			return fmt.Errorf("scene %q: unknown %s %q", ss.Name, kind, groupName)
+		}
+
+		if exists && b.sceneExists(groupID, ss.Name) {
+			continue
+		}
+
+		actions := make([]api.SceneAction, 0, len(ss.Actions))
+		details := []string{fmt.Sprintf("%s: %s", kind, groupName)}
+		for _, as := range ss.Actions {
+			light, err := b.resolveLight(as.Light)
+			if err != nil {
+				return fmt.Errorf("scene %q: %w", ss.Name, err)
+			}
+			actions = append(actions, api.SceneAction{
+				LightID:    light.ID,
+				On:         as.On,
+				Brightness: as.Brightness,
+				Mirek:      as.Mirek,
+				XY:         as.XY,
+			})
+			details = append(details, describeAction(b.finalName[light.ID], as))
+		}
+
+		name := ss.Name
+		b.add(&Change{
+			Op:      OpCreate,
+			Kind:    "scene",
+			Name:    name,
+			Details: details,
+			apply: func(ctx context.Context, br Bridge, groupIDs map[string]string) error {
+				id, ok := groupIDs[key]
+				if !ok {
+					return fmt.Errorf("%s %q was not created", kind, groupName)
+				}
+				_, err := br.CreateScene(ctx, name, id, kind, actions)
+				return err
+			},
+		})
+	}
+	return nil
+}
+
+// planCreates returns true if the plan creates the given room or zone
+func (b *builder) planCreates(kind, name string) bool {
+	for _, c := range b.plan.Changes {
+		if c.Op == OpCreate && c.Kind == kind && c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *builder) sceneExists(groupID, name string) bool {
+	for _, scene := range b.state.Scenes {
+		if scene.RoomID == groupID && scene.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func describeAction(light string, a ActionSpec) string {
+	var parts []string
+	if a.On != nil {
+		if *a.On {
+			parts = append(parts, "on")
+		} else {
+			parts = append(parts, "off")
+		}
+	}
+	if a.Brightness != nil {
+		parts = append(parts, fmt.Sprintf("%g%%", *a.Brightness))
+	}
+	if a.Mirek != nil {
+		parts = append(parts, fmt.Sprintf("%d mirek", *a.Mirek))
+	} else if a.XY != nil {
+		parts = append(parts, fmt.Sprintf("xy %.4f,%.4f", a.XY[0], a.XY[1]))
+	}
+	if len(parts) == 0 {
+		return light
+	}
+	return light + ": " + strings.Join(parts, ", ")
+}
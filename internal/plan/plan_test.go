@@ -0,0 +1,247 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// fakeBridge records provisioning calls
+type fakeBridge struct {
+	calls []string
+	next  int
+}
+
+func (f *fakeBridge) GetLights(ctx context.Context) ([]*models.Light, error) { return nil, nil }
+func (f *fakeBridge) GetRooms(ctx context.Context) ([]*models.Room, error)   { return nil, nil }
+func (f *fakeBridge) GetZones(ctx context.Context) ([]*models.Room, error)   { return nil, nil }
+func (f *fakeBridge) GetScenes(ctx context.Context) ([]*models.Scene, error) { return nil, nil }
+func (f *fakeBridge) RenameLight(ctx context.Context, lightID, name string) error {
+	f.calls = append(f.calls, fmt.Sprintf("rename light %s %s", lightID, name))
+	return nil
+}
+func (f *fakeBridge) CreateRoom(ctx context.Context, name, archetype string, deviceIDs []string) (string, error) {
+	f.next++
+	f.calls = append(f.calls, fmt.Sprintf("create room %s %s %v", name, archetype, deviceIDs))
+	return fmt.Sprintf("new-%d", f.next), nil
+}
+func (f *fakeBridge) UpdateRoom(ctx context.Context, roomID, name string, deviceIDs []string) error {
+	f.calls = append(f.calls, fmt.Sprintf("update room %s %q %v", roomID, name, deviceIDs))
+	return nil
+}
+func (f *fakeBridge) CreateZone(ctx context.Context, name, archetype string, lightIDs []string) (string, error) {
+	f.next++
+	f.calls = append(f.calls, fmt.Sprintf("create zone %s %s %v", name, archetype, lightIDs))
+	return fmt.Sprintf("new-%d", f.next), nil
+}
+func (f *fakeBridge) UpdateZone(ctx context.Context, zoneID, name string, lightIDs []string) error {
+	f.calls = append(f.calls, fmt.Sprintf("update zone %s %q %v", zoneID, name, lightIDs))
+	return nil
+}
+func (f *fakeBridge) CreateScene(ctx context.Context, name, groupID, groupType string, actions []api.SceneAction) (string, error) {
+	f.calls = append(f.calls, fmt.Sprintf("create scene %s %s %s %d", name, groupID, groupType, len(actions)))
+	return "scene", nil
+}
+
+func (f *fakeBridge) SetLightState(ctx context.Context, action api.SceneAction) error {
+	f.calls = append(f.calls, "set light "+describeAction(action.LightID, actionSpec(action)))
+	return nil
+}
+func (f *fakeBridge) SetGroupedLightState(ctx context.Context, groupedLightID string, action api.SceneAction) error {
+	f.calls = append(f.calls, "set group "+describeAction(groupedLightID, actionSpec(action)))
+	return nil
+}
+
+// actionSpec converts a scene action back to its plan form, to describe it
+func actionSpec(a api.SceneAction) ActionSpec {
+	return ActionSpec{On: a.On, Brightness: a.Brightness, Mirek: a.Mirek, XY: a.XY}
+}
+
+func testState() *State {
+	return &State{
+		Lights: []*models.Light{
+			{ID: "l1", Name: "Hue color lamp 1", DeviceID: "d1"},
+			{ID: "l2", Name: "Ceiling", DeviceID: "d2"},
+			{ID: "l3", Name: "Strip", DeviceID: "d3"},
+		},
+		Rooms: []*models.Room{
+			{ID: "r1", Name: "Study", DeviceIDs: []string{"d1", "d3"}},
+		},
+		Scenes: []*models.Scene{
+			{ID: "s1", Name: "Bright", RoomID: "r1"},
+		},
+	}
+}
+
+func TestBuildAndApply(t *testing.T) {
+	spec, err := ParseSpec([]byte(`
+lights:
+  - from: Hue color lamp 1
+    name: Desk lamp
+rooms:
+  - name: Office
+    from: Study
+    lights: [Desk lamp, Ceiling]
+  - name: Hall
+    archetype: hallway
+    lights: [Strip]
+zones:
+  - name: Downstairs
+    lights: [Desk lamp]
+scenes:
+  - name: Bright
+    room: Office
+  - name: Focus
+    room: Hall
+    actions:
+      - light: Strip
+        on: true
+        brightness: 80
+`))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+
+	p, err := Build(spec, testState())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	create, update := p.Counts()
+	if create != 3 || update != 2 {
+		t.Errorf("Expected 3 creates and 2 updates, got %d and %d", create, update)
+	}
+
+	var out bytes.Buffer
+	p.Write(&out)
+	for _, want := range []string{
+		`~ light "Hue color lamp 1"`,
+		`name: "Study" → "Office"`,
+		"+ Ceiling",
+		"- Strip",
+		`+ room "Hall"`,
+		"Plan: 3 to create, 2 to change.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Plan output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	bridge := &fakeBridge{}
+	if err := p.Apply(context.Background(), bridge, nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	want := []string{
+		"rename light l1 Desk lamp",
+		`update room r1 "Office" [d1 d2]`,
+		"create room Hall hallway [d3]",
+		"create zone Downstairs other [l1]",
+		"create scene Focus new-1 room 1",
+	}
+	if !reflect.DeepEqual(bridge.calls, want) {
+		t.Errorf("Apply calls = %v, want %v", bridge.calls, want)
+	}
+}
+
+func TestBuild_NoChanges(t *testing.T) {
+	spec, err := ParseSpec([]byte(`
+rooms:
+  - name: Study
+    lights: [Hue color lamp 1, Strip]
+`))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+
+	p, err := Build(spec, testState())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if !p.Empty() {
+		t.Errorf("Expected empty plan, got %d changes", len(p.Changes))
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+	}{
+		{"unknown light", "rooms:\n  - name: X\n    lights: [Nope]\n"},
+		{"unknown scene group", "scenes:\n  - name: S\n    room: Nowhere\n"},
+		{"duplicate room", "rooms:\n  - name: A\n  - name: A\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec([]byte(tt.plan))
+			if err != nil {
+				t.Fatalf("ParseSpec failed: %v", err)
+			}
+			if _, err := Build(spec, testState()); err == nil {
+				t.Error("Expected Build to fail")
+			}
+		})
+	}
+}
+
+func TestBuild_DuplicateLightNames(t *testing.T) {
+	state := testState()
+	state.Lights = append(state.Lights, &models.Light{ID: "l4", Name: "Ceiling", DeviceID: "d4"})
+	state.Rooms = append(state.Rooms,
+		&models.Room{ID: "r2", Name: "Hall", DeviceIDs: []string{"d4"}},
+		&models.Room{ID: "r3", Name: "Loft", DeviceIDs: []string{"d2"}},
+	)
+
+	spec, err := ParseSpec([]byte("zones:\n  - name: Upstairs\n    lights: [Ceiling]\n"))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	_, err = Build(spec, state)
+	if err == nil || !strings.Contains(err.Error(), `"Ceiling (Hall)", "Ceiling (Loft)"`) {
+		t.Fatalf("Expected the shared name to be refused with the names to use, got %v", err)
+	}
+
+	spec, err = ParseSpec([]byte("zones:\n  - name: Upstairs\n    lights: [Ceiling (Loft)]\n"))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	p, err := Build(spec, state)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	bridge := &fakeBridge{}
+	if err := p.Apply(context.Background(), bridge, nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if want := []string{"create zone Upstairs other [l2]"}; !reflect.DeepEqual(bridge.calls, want) {
+		t.Errorf("Apply calls = %v, want %v", bridge.calls, want)
+	}
+}
+
+func TestParseSpec_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+	}{
+		{"unknown section", "groups: []\n"},
+		{"missing name", "rooms:\n  - lights: [A]\n"},
+		{"scene without group", "scenes:\n  - name: S\n"},
+		{"bad brightness", "scenes:\n  - name: S\n    room: R\n    actions:\n      - light: A\n        brightness: high\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSpec([]byte(tt.plan)); err == nil {
+				t.Error("Expected ParseSpec to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package plan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// SnapshotVersion is the format version written by Export
+const SnapshotVersion = 1
+
+// Snapshot is a portable dump of a bridge configuration, used to move
+// names, rooms, zones and scenes to another bridge
+type Snapshot struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Lights     []SnapshotLight `json:"lights"`
+	Rooms      []SnapshotGroup `json:"rooms"`
+	Zones      []SnapshotGroup `json:"zones"`
+	Scenes     []SnapshotScene `json:"scenes"`
+}
+
+// SnapshotLight is a light and its state at export time
+type SnapshotLight struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Zigbee MAC address, the only identifier that survives a bridge change
+	MAC   string        `json:"mac,omitempty"`
+	State SnapshotState `json:"state"`
+}
+
+// SnapshotState is the state of a light, either live or in a scene
+type SnapshotState struct {
+	On bool `json:"on"`
+	// Brightness in percent
+	Brightness float64     `json:"brightness"`
+	Mirek      *int        `json:"mirek,omitempty"`
+	XY         *[2]float64 `json:"xy,omitempty"`
+}
+
+// SnapshotGroup is a room or zone. Lights are snapshot light IDs.
+type SnapshotGroup struct {
+	Name      string   `json:"name"`
+	Archetype string   `json:"archetype,omitempty"`
+	Lights    []string `json:"lights"`
+}
+
+// SnapshotScene is a scene of a room or zone
+type SnapshotScene struct {
+	Name    string           `json:"name"`
+	Room    string           `json:"room,omitempty"`
+	Zone    string           `json:"zone,omitempty"`
+	Actions []SnapshotAction `json:"actions"`
+}
+
+// SnapshotAction is the state of one light in a scene
+type SnapshotAction struct {
+	Light string `json:"light"`
+	SnapshotState
+}
+
+// Export builds a snapshot of the bridge state. addresses maps device IDs
+// to Zigbee MAC addresses and may be nil.
+func Export(state *State, addresses map[string]string) *Snapshot {
+	snap := &Snapshot{
+		Version:    SnapshotVersion,
+		ExportedAt: time.Now().UTC(),
+		Lights:     []SnapshotLight{},
+		Rooms:      []SnapshotGroup{},
+		Zones:      []SnapshotGroup{},
+		Scenes:     []SnapshotScene{},
+	}
+
+	for _, light := range state.Lights {
+		snap.Lights = append(snap.Lights, SnapshotLight{
+			ID:    light.ID,
+			Name:  light.Name,
+			MAC:   addresses[light.DeviceID],
+			State: snapshotState(light.On, light.Brightness, light.Color),
+		})
+	}
+
+	// Rooms contain devices, list the lights of those devices instead
+	groupNames := make(map[string][2]string)
+	for _, room := range state.Rooms {
+		group := SnapshotGroup{Name: room.Name, Archetype: room.Archetype, Lights: []string{}}
+		for _, deviceID := range room.DeviceIDs {
+			for _, light := range state.Lights {
+				if light.DeviceID == deviceID {
+					group.Lights = append(group.Lights, light.ID)
+				}
+			}
+		}
+		snap.Rooms = append(snap.Rooms, group)
+		groupNames[room.ID] = [2]string{"room", room.Name}
+	}
+	for _, zone := range state.Zones {
+		group := SnapshotGroup{Name: zone.Name, Archetype: zone.Archetype, Lights: []string{}}
+		group.Lights = append(group.Lights, zone.LightIDs...)
+		snap.Zones = append(snap.Zones, group)
+		groupNames[zone.ID] = [2]string{"zone", zone.Name}
+	}
+
+	for _, scene := range state.Scenes {
+		group, ok := groupNames[scene.RoomID]
+		if !ok || scene.IsSmart {
+			continue
+		}
+		ss := SnapshotScene{Name: scene.Name, Actions: []SnapshotAction{}}
+		if group[0] == "zone" {
+			ss.Zone = group[1]
+		} else {
+			ss.Room = group[1]
+		}
+		for _, a := range scene.Actions {
+			ss.Actions = append(ss.Actions, SnapshotAction{
+				Light:         a.LightID,
+				SnapshotState: snapshotState(a.On, a.Brightness, a.Color),
+			})
+		}
+		snap.Scenes = append(snap.Scenes, ss)
+	}
+
+	return snap
+}
+
+func snapshotState(on bool, brightness uint8, color *models.Color) SnapshotState {
+	s := SnapshotState{
+		On:         on,
+		Brightness: models.LevelToPctPrecise(brightness),
+	}
+	if color == nil {
+		return s
+	}
+	switch color.Mode {
+	case models.ColorModeColorTemp:
+		mirek := int(color.Mirek)
+		s.Mirek = &mirek
+	case models.ColorModeXY, models.ColorModeHS:
+		s.XY = &[2]float64{color.X, color.Y}
+	}
+	return s
+}
+
+// Spec turns the snapshot into a plan spec for the target bridge. Lights are
+// matched by MAC address, then by ID, then by name. Anything that can't be
+// carried over is skipped and reported in the returned warnings.
+func (s *Snapshot) Spec(state *State, addresses map[string]string) (*Spec, []string) {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Snapshot light ID → target light ID
+	targets := make(map[string]string)
+	claimed := make(map[string]bool)
+	spec := &Spec{}
+	for _, sl := range s.Lights {
+		target := matchLight(sl, state, addresses, claimed)
+		if target == nil {
+			warn("light %q: no matching light on this bridge", sl.Name)
+			continue
+		}
+		claimed[target.ID] = true
+		targets[sl.ID] = target.ID
+		spec.Lights = append(spec.Lights, LightSpec{ID: target.ID, Name: sl.Name})
+	}
+
+	groups := func(kind string, snapGroups []SnapshotGroup) []GroupSpec {
+		var specs []GroupSpec
+		for _, sg := range snapGroups {
+			gs := GroupSpec{Name: sg.Name, Archetype: sg.Archetype}
+			for _, id := range sg.Lights {
+				if target, ok := targets[id]; ok {
+					gs.Lights = append(gs.Lights, target)
+				}
+			}
+			if len(sg.Lights) > 0 && gs.Lights == nil {
+				warn("%s %q: none of its lights were found, membership left as is", kind, sg.Name)
+			}
+			specs = append(specs, gs)
+		}
+		return specs
+	}
+	spec.Rooms = groups("room", s.Rooms)
+	spec.Zones = groups("zone", s.Zones)
+
+	for _, ss := range s.Scenes {
+		scene := SceneSpec{Name: ss.Name, Room: ss.Room, Zone: ss.Zone}
+		for _, sa := range ss.Actions {
+			target, ok := targets[sa.Light]
+			if !ok {
+				warn("scene %q: skipping a light that wasn't found", ss.Name)
+				continue
+			}
+			on := sa.On
+			action := ActionSpec{Light: target, On: &on}
+			if on {
+				brightness := sa.Brightness
+				action.Brightness = &brightness
+				action.Mirek = sa.Mirek
+				action.XY = sa.XY
+			}
+			scene.Actions = append(scene.Actions, action)
+		}
+		spec.Scenes = append(spec.Scenes, scene)
+	}
+
+	return spec, warnings
+}
+
+// matchLight finds the target light for a snapshot light, skipping lights
+// already matched to another one
+func matchLight(sl SnapshotLight, state *State, addresses map[string]string, claimed map[string]bool) *models.Light {
+	unique := func(match func(*models.Light) bool) *models.Light {
+		var found *models.Light
+		for _, light := range state.Lights {
+			if claimed[light.ID] || !match(light) {
+				continue
+			}
+			if found != nil {
+				return nil
+			}
+			found = light
+		}
+		return found
+	}
+
+	if sl.MAC != "" {
+		if light := unique(func(l *models.Light) bool { return addresses[l.DeviceID] == sl.MAC }); light != nil {
+			return light
+		}
+	}
+	if light := unique(func(l *models.Light) bool { return l.ID == sl.ID }); light != nil {
+		return light
+	}
+	return unique(func(l *models.Light) bool { return l.Name == sl.Name })
+}
@@ -0,0 +1,115 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func TestExport(t *testing.T) {
+	state := testState()
+	state.Lights[0].On = true
+	state.Lights[0].Brightness = 127
+	state.Lights[0].Color = models.NewColorFromMirek(366, 127)
+	state.Rooms[0].Archetype = "office"
+	state.Zones = []*models.Room{{ID: "z1", Name: "Upstairs", LightIDs: []string{"l2"}}}
+	state.Scenes = append(state.Scenes, &models.Scene{
+		ID: "s2", Name: "Night", RoomID: "z1",
+		Actions: []models.SceneAction{{LightID: "l2", On: true, Brightness: 254, Color: models.NewColorFromXY(0.5, 0.4, 254)}},
+	})
+
+	snap := Export(state, map[string]string{"d1": "00:17:88:01:00:00:00:01"})
+
+	if snap.Version != SnapshotVersion {
+		t.Errorf("Expected version %d, got %d", SnapshotVersion, snap.Version)
+	}
+	lamp := snap.Lights[0]
+	if lamp.MAC != "00:17:88:01:00:00:00:01" || !lamp.State.On || lamp.State.Brightness != 50 {
+		t.Errorf("Unexpected light %+v", lamp)
+	}
+	if lamp.State.Mirek == nil || *lamp.State.Mirek != 366 || lamp.State.XY != nil {
+		t.Errorf("Expected a 366 mirek color temperature, got %+v", lamp.State)
+	}
+	if !reflect.DeepEqual(snap.Rooms, []SnapshotGroup{{Name: "Study", Archetype: "office", Lights: []string{"l1", "l3"}}}) {
+		t.Errorf("Unexpected rooms %+v", snap.Rooms)
+	}
+	if len(snap.Scenes) != 2 || snap.Scenes[0].Room != "Study" || snap.Scenes[1].Zone != "Upstairs" {
+		t.Fatalf("Unexpected scenes %+v", snap.Scenes)
+	}
+	if xy := snap.Scenes[1].Actions[0].XY; xy == nil || xy[0] != 0.5 || xy[1] != 0.4 {
+		t.Errorf("Expected the scene color to be exported as xy, got %v", xy)
+	}
+
+	// The snapshot survives a JSON round trip
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded Snapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Scenes, snap.Scenes) {
+		t.Errorf("Scenes changed after a round trip: %+v", decoded.Scenes)
+	}
+}
+
+func TestSnapshotImport(t *testing.T) {
+	on := true
+	mirek := 300
+	snap := &Snapshot{
+		Version: SnapshotVersion,
+		Lights: []SnapshotLight{
+			{ID: "old-1", Name: "Desk lamp", MAC: "mac-1"},
+			{ID: "old-2", Name: "Ceiling"},
+			{ID: "old-3", Name: "Gone"},
+		},
+		Rooms: []SnapshotGroup{{Name: "Office", Archetype: "office", Lights: []string{"old-1", "old-2"}}},
+		Scenes: []SnapshotScene{{
+			Name: "Work",
+			Room: "Office",
+			Actions: []SnapshotAction{
+				{Light: "old-1", SnapshotState: SnapshotState{On: on, Brightness: 80, Mirek: &mirek}},
+				{Light: "old-3", SnapshotState: SnapshotState{On: on}},
+			},
+		}},
+	}
+
+	// A fresh bridge: new IDs, default names
+	state := &State{
+		Lights: []*models.Light{
+			{ID: "l1", Name: "Hue color lamp 1", DeviceID: "d1"},
+			{ID: "l2", Name: "Ceiling", DeviceID: "d2"},
+		},
+	}
+	spec, warnings := snap.Spec(state, map[string]string{"d1": "mac-1"})
+
+	wantLights := []LightSpec{{ID: "l1", Name: "Desk lamp"}, {ID: "l2", Name: "Ceiling"}}
+	if !reflect.DeepEqual(spec.Lights, wantLights) {
+		t.Errorf("Expected lights %+v, got %+v", wantLights, spec.Lights)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], `"Gone"`) {
+		t.Errorf("Expected warnings for the missing light, got %q", warnings)
+	}
+
+	p, err := Build(spec, state)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	bridge := &fakeBridge{}
+	if err := p.Apply(context.Background(), bridge, nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	want := []string{
+		"rename light l1 Desk lamp",
+		"create room Office office [d1 d2]",
+		"create scene Work new-1 room 1",
+	}
+	if !reflect.DeepEqual(bridge.calls, want) {
+		t.Errorf("Expected calls %q, got %q", want, bridge.calls)
+	}
+}
@@ -0,0 +1,367 @@
+// Package plan implements declarative bridge provisioning: a YAML file
+// describes rooms, zones, light names, scenes and light states, and the
+// package computes and applies the changes needed to make the bridge match
+// it.
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// Spec is the desired bridge layout described by a plan file
+type Spec struct {
+	Lights []LightSpec
+	Rooms  []GroupSpec
+	Zones  []GroupSpec
+	Scenes []SceneSpec
+	States []StateSpec
+}
+
+// LightSpec names a light. The light is matched by ID, or by its current
+// name given in From, or by Name when it is already named correctly.
+type LightSpec struct {
+	ID   string
+	From string
+	Name string
+}
+
+// GroupSpec describes a room or zone
+type GroupSpec struct {
+	Name string
+	// Previous name, to rename an existing group
+	From      string
+	Archetype string
+	// Light names or IDs. nil leaves membership untouched.
+	Lights []string
+}
+
+// SceneSpec describes a scene to create in a room or zone
+type SceneSpec struct {
+	Name    string
+	Room    string
+	Zone    string
+	Actions []ActionSpec
+}
+
+// ActionSpec is the state of one light in a scene
+type ActionSpec struct {
+	Light      string
+	On         *bool
+	Brightness *float64
+	Mirek      *int
+	// CIE xy color. Not read from plan files, only set by snapshot imports.
+	XY *[2]float64
+}
+
+// StateSpec is the desired state of a light, or of every light of a room
+// or zone. Nil fields are left as they are.
+type StateSpec struct {
+	Light string
+	Room  string
+	Zone  string
+
+	On         *bool
+	Brightness *float64
+	Mirek      *int
+	// CIE xy color, parsed from Color
+	XY *[2]float64
+	// Color as written in the plan, such as "#FF8800"
+	Color string
+}
+
+// ParseSpec parses a YAML plan file, or the same plan in JSON
+func ParseSpec(data []byte) (*Spec, error) {
+	doc, err := unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return &Spec{}, nil
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("plan must be a mapping with lights, rooms, zones, scenes or states")
+	}
+
+	d := &decoder{}
+	spec := &Spec{}
+	for key := range root {
+		switch key {
+		case "lights", "rooms", "zones", "scenes", "states":
+		default:
+			d.fail(key, "unknown section")
+		}
+	}
+
+	for i, item := range d.list(root, "lights") {
+		path := fmt.Sprintf("lights[%d]", i)
+		m := d.mapping(item, path)
+		spec.Lights = append(spec.Lights, LightSpec{
+			ID:   d.str(m, path, "id"),
+			From: d.str(m, path, "from"),
+			Name: d.requiredStr(m, path, "name"),
+		})
+	}
+	spec.Rooms = d.groups(root, "rooms")
+	spec.Zones = d.groups(root, "zones")
+
+	for i, item := range d.list(root, "scenes") {
+		path := fmt.Sprintf("scenes[%d]", i)
+		m := d.mapping(item, path)
+		scene := SceneSpec{
+			Name: d.requiredStr(m, path, "name"),
+			Room: d.str(m, path, "room"),
+			Zone: d.str(m, path, "zone"),
+		}
+		if (scene.Room == "") == (scene.Zone == "") {
+			d.fail(path, "exactly one of room or zone is required")
+		}
+		for j, a := range d.list(m, "actions") {
+			apath := fmt.Sprintf("%s.actions[%d]", path, j)
+			am := d.mapping(a, apath)
+			scene.Actions = append(scene.Actions, ActionSpec{
+				Light:      d.requiredStr(am, apath, "light"),
+				On:         d.boolPtr(am, apath, "on"),
+				Brightness: d.floatPtr(am, apath, "brightness"),
+				Mirek:      d.intPtr(am, apath, "mirek"),
+			})
+		}
+		spec.Scenes = append(spec.Scenes, scene)
+	}
+
+	for i, item := range d.list(root, "states") {
+		path := fmt.Sprintf("states[%d]", i)
+		spec.States = append(spec.States, d.state(d.mapping(item, path), path))
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return spec, nil
+}
+
+// unmarshal decodes a plan file into generic values. JSON
+// is told apart by its opening brace, and its whole numbers become ints.
+func unmarshal(data []byte) (interface{}, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		return doc, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return jsonNumbers(doc), nil
+}
+
+// jsonNumbers converts the numbers of a decoded JSON document to the int and
+// float64 values YAML decodes to
+func jsonNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = jsonNumbers(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = jsonNumbers(value)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+		f, _ := v.Float64() // Error ignored: the decoder validated the number
+		return f
+	}
+	return v
+}
+
+// decoder converts generic YAML values, keeping the first error
+type decoder struct {
+	err error
+}
+
+func (d *decoder) fail(path, format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *decoder) mapping(v interface{}, path string) map[string]interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		d.fail(path, "expected a mapping")
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func (d *decoder) list(m map[string]interface{}, key string) []interface{} {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil
+	}
+	l, ok := v.([]interface{})
+	if !ok {
+		d.fail(key, "expected a list")
+		return nil
+	}
+	return l
+}
+
+func (d *decoder) str(m map[string]interface{}, path, key string) string {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return ""
+	}
+	switch s := v.(type) {
+	case string:
+		return s
+	case int, float64:
+		return fmt.Sprint(s)
+	}
+	d.fail(path+"."+key, "expected a string")
+	return ""
+}
+
+func (d *decoder) requiredStr(m map[string]interface{}, path, key string) string {
+	s := d.str(m, path, key)
+	if s == "" {
+		d.fail(path+"."+key, "is required")
+	}
+	return s
+}
+
+func (d *decoder) boolPtr(m map[string]interface{}, path, key string) *bool {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		d.fail(path+"."+key, "expected true or false")
+		return nil
+	}
+	return &b
+}
+
+func (d *decoder) floatPtr(m map[string]interface{}, path, key string) *float64 {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil
+	}
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		d.fail(path+"."+key, "expected a number")
+		return nil
+	}
+	return &f
+}
+
+func (d *decoder) intPtr(m map[string]interface{}, path, key string) *int {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return nil
+	}
+	n, ok := v.(int)
+	if !ok {
+		d.fail(path+"."+key, "expected an integer")
+		return nil
+	}
+	return &n
+}
+
+func (d *decoder) groups(root map[string]interface{}, section string) []GroupSpec {
+	var result []GroupSpec
+	for i, item := range d.list(root, section) {
+		path := fmt.Sprintf("%s[%d]", section, i)
+		m := d.mapping(item, path)
+		group := GroupSpec{
+			Name:      d.requiredStr(m, path, "name"),
+			From:      d.str(m, path, "from"),
+			Archetype: d.str(m, path, "archetype"),
+		}
+		if _, ok := m["lights"]; ok {
+			group.Lights = []string{}
+			for j, l := range d.list(m, "lights") {
+				name, ok := l.(string)
+				if !ok {
+					d.fail(fmt.Sprintf("%s.lights[%d]", path, j), "expected a light name")
+					continue
+				}
+				group.Lights = append(group.Lights, name)
+			}
+		}
+		result = append(result, group)
+	}
+	return result
+}
+
+func (d *decoder) state(m map[string]interface{}, path string) StateSpec {
+	state := StateSpec{
+		Light:      d.str(m, path, "light"),
+		Room:       d.str(m, path, "room"),
+		Zone:       d.str(m, path, "zone"),
+		On:         d.boolPtr(m, path, "on"),
+		Brightness: d.floatPtr(m, path, "brightness"),
+		Mirek:      d.intPtr(m, path, "mirek"),
+		Color:      d.str(m, path, "color"),
+	}
+	targets := 0
+	for _, target := range []string{state.Light, state.Room, state.Zone} {
+		if target != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		d.fail(path, "exactly one of light, room or zone is required")
+	}
+
+	if kelvin := d.intPtr(m, path, "kelvin"); kelvin != nil {
+		switch {
+		case state.Mirek != nil:
+			d.fail(path, "mirek and kelvin can't be combined")
+		case *kelvin <= 0:
+			d.fail(path+".kelvin", "expected a positive temperature")
+		default:
+			mirek := models.KelvinToMirek(*kelvin)
+			state.Mirek = &mirek
+		}
+	}
+	if state.Color != "" {
+		if state.Mirek != nil {
+			d.fail(path, "color and a color temperature can't be combined")
+		}
+		r, g, b, err := models.ParseHex(state.Color)
+		if err != nil {
+			d.fail(path+".color", "%v", err)
+		} else {
+			x, y := models.RGBToXY(r, g, b)
+			state.XY = &[2]float64{x, y}
+		}
+	}
+	if state.Brightness != nil && (*state.Brightness < 0 || *state.Brightness > 100) {
+		d.fail(path+".brightness", "expected a percentage from 0 to 100")
+	}
+	if state.On == nil && state.Brightness == nil && state.Mirek == nil && state.XY == nil {
+		d.fail(path, "nothing to set: expected on, brightness, mirek, kelvin or color")
+	}
+	return state
+}
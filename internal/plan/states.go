@@ -0,0 +1,337 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// Tolerances under which a light already matches its desired state, as the
+// bridge rounds what it reports
+const (
+	brightnessTolerance = 0.5 // percent
+	mirekTolerance      = 1
+	xyTolerance         = 0.01
+)
+
+// desiredState is the state a light should end up in, merged from the
+// states of the plan that cover it
+type desiredState struct {
+	api.SceneAction
+	color string
+}
+
+// merge applies the non-nil fields of a state on top of the desired ones.
+// A color replaces a color temperature and the other way around.
+func (s *desiredState) merge(ss StateSpec) {
+	if ss.On != nil {
+		s.On = ss.On
+	}
+	if ss.Brightness != nil {
+		s.Brightness = ss.Brightness
+	}
+	if ss.Mirek != nil {
+		s.Mirek, s.XY, s.color = ss.Mirek, nil, ""
+	}
+	if ss.XY != nil {
+		s.Mirek, s.XY, s.color = nil, ss.XY, ss.Color
+	}
+}
+
+// stateGroup is a room or zone whose state the plan sets
+type stateGroup struct {
+	kind   string
+	group  *models.Room
+	lights []*models.Light
+}
+
+// states adds the commands bringing lights to their desired states. A room
+// or zone whose lights all need the same change gets one command for the
+// group, the other lights one command each with only what differs.
+func (b *builder) states(specs []StateSpec) error {
+	desired := make(map[string]*desiredState)
+	var order []*models.Light
+	var groups []stateGroup
+	for _, ss := range specs {
+		var lights []*models.Light
+		if ss.Light != "" {
+			light, err := b.resolveLight(ss.Light)
+			if err != nil {
+				return fmt.Errorf("states: %w", err)
+			}
+			lights = []*models.Light{light}
+		} else {
+			g, err := b.stateGroup(ss)
+			if err != nil {
+				return fmt.Errorf("states: %w", err)
+			}
+			groups = append(groups, g)
+			lights = g.lights
+		}
+		for _, light := range lights {
+			state, ok := desired[light.ID]
+			if !ok {
+				state = &desiredState{SceneAction: api.SceneAction{LightID: light.ID}}
+				desired[light.ID] = state
+				order = append(order, light)
+			}
+			state.merge(ss)
+		}
+	}
+
+	diffs := make(map[string]api.SceneAction)
+	for _, light := range order {
+		if diff := diffState(light, desired[light.ID]); !emptyAction(diff) {
+			diffs[light.ID] = diff
+		}
+	}
+
+	done := make(map[string]bool)
+	for _, g := range groups {
+		if !sameChange(g, diffs, done) {
+			continue
+		}
+		for _, light := range g.lights {
+			done[light.ID] = true
+		}
+		b.addGroupState(g, diffs[g.lights[0].ID], desired[g.lights[0].ID].color)
+	}
+	for _, light := range order {
+		diff, ok := diffs[light.ID]
+		if !ok || done[light.ID] {
+			continue
+		}
+		b.addLightState(light, diff, desired[light.ID].color)
+	}
+	return nil
+}
+
+// stateGroup finds the room or zone of a state, by its name in the plan or
+// on the bridge, with its lights
+func (b *builder) stateGroup(ss StateSpec) (stateGroup, error) {
+	kind, name, existing := "room", ss.Room, b.state.Rooms
+	if ss.Zone != "" {
+		kind, name, existing = "zone", ss.Zone, b.state.Zones
+	}
+
+	var group *models.Room
+	if id, ok := b.plan.groupIDs[kind+":"+name]; ok {
+		for _, g := range existing {
+			if g.ID == id {
+				group = g
+			}
+		}
+	} else {
+		group = groupNamed(existing, name)
+	}
+	if group == nil {
+		if b.planCreates(kind, name) {
+			return stateGroup{}, fmt.Errorf("%s %q is created by this plan, apply it before setting its state", kind, name)
+		}
+		return stateGroup{}, fmt.Errorf("unknown %s %q", kind, name)
+	}
+
+	members := make(map[string]bool)
+	for _, id := range b.members(kind, group) {
+		members[id] = true
+	}
+	g := stateGroup{kind: kind, group: group}
+	for _, light := range b.state.Lights {
+		if (kind == "zone" && members[light.ID]) || (kind == "room" && members[light.DeviceID]) {
+			g.lights = append(g.lights, light)
+		}
+	}
+	if len(g.lights) == 0 {
+		return stateGroup{}, fmt.Errorf("%s %q has no lights", kind, name)
+	}
+	return g, nil
+}
+
+// sameChange returns true if every light of a group needs the same change,
+// so that one group command is enough
+func sameChange(g stateGroup, diffs map[string]api.SceneAction, done map[string]bool) bool {
+	if g.group.GroupedLightID == "" || len(g.lights) < 2 {
+		return false
+	}
+	first, ok := diffs[g.lights[0].ID]
+	if !ok {
+		return false
+	}
+	for _, light := range g.lights {
+		diff, ok := diffs[light.ID]
+		if !ok || done[light.ID] || !sameAction(first, diff) {
+			return false
+		}
+	}
+	return true
+}
+
+// diffState returns the fields of the desired state the light doesn't
+// already have, leaving out the ones it can't show. Lights turned off keep
+// their brightness and color.
+func diffState(light *models.Light, want *desiredState) api.SceneAction {
+	diff := api.SceneAction{LightID: light.ID}
+	if want.On != nil && *want.On != light.On {
+		diff.On = want.On
+	}
+	if want.On != nil && !*want.On {
+		return diff
+	}
+
+	if want.Brightness != nil && !light.OnOffOnly &&
+		math.Abs(*want.Brightness-models.LevelToPctPrecise(light.Brightness)) >= brightnessTolerance {
+		diff.Brightness = want.Brightness
+	}
+
+	c := light.Color
+	switch {
+	case want.Mirek != nil && light.SupportsColorTemp:
+		mirek := light.ClampMirek(*want.Mirek)
+		if c == nil || c.Mode != models.ColorModeColorTemp || abs(int(c.Mirek)-mirek) > mirekTolerance {
+			diff.Mirek = &mirek
+		}
+	case want.XY != nil && light.SupportsColor:
+		x, y, ok := currentXY(c)
+		if !ok || math.Abs(x-want.XY[0]) > xyTolerance || math.Abs(y-want.XY[1]) > xyTolerance {
+			diff.XY = want.XY
+		}
+	}
+	return diff
+}
+
+// currentXY returns the xy color a light shows, if it shows one
+func currentXY(c *models.Color) (x, y float64, ok bool) {
+	if c == nil {
+		return 0, 0, false
+	}
+	switch c.Mode {
+	case models.ColorModeXY:
+		return c.X, c.Y, true
+	case models.ColorModeHS:
+		x, y := api.HSToXY(c.Hue, c.Saturation)
+		return x, y, true
+	}
+	return 0, 0, false
+}
+
+func emptyAction(a api.SceneAction) bool {
+	return a.On == nil && a.Brightness == nil && a.Mirek == nil && a.XY == nil
+}
+
+// sameAction compares the fields of two actions, ignoring their light
+func sameAction(a, b api.SceneAction) bool {
+	return equalPtr(a.On, b.On) && equalPtr(a.Brightness, b.Brightness) &&
+		equalPtr(a.Mirek, b.Mirek) && equalPtr(a.XY, b.XY)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// addLightState adds the command changing the state of a light
+func (b *builder) addLightState(light *models.Light, diff api.SceneAction, color string) {
+	b.add(&Change{
+		Op:      OpUpdate,
+		Kind:    "light",
+		Name:    b.finalName[light.ID],
+		Details: describeState(diff, color, light),
+		apply: func(ctx context.Context, br Bridge, _ map[string]string) error {
+			return br.SetLightState(ctx, diff)
+		},
+	})
+}
+
+// addGroupState adds the command changing every light of a group at once
+func (b *builder) addGroupState(g stateGroup, diff api.SceneAction, color string) {
+	names := make([]string, len(g.lights))
+	for i, light := range g.lights {
+		names[i] = b.finalName[light.ID]
+	}
+	sort.Strings(names)
+
+	groupedLightID := g.group.GroupedLightID
+	b.add(&Change{
+		Op:      OpUpdate,
+		Kind:    g.kind,
+		Name:    g.group.Name,
+		Details: append(describeState(diff, color, nil), "lights: "+strings.Join(names, ", ")),
+		apply: func(ctx context.Context, br Bridge, _ map[string]string) error {
+			return br.SetGroupedLightState(ctx, groupedLightID, diff)
+		},
+	})
+}
+
+// describeState lists the fields of a state change, from the current state
+// of light when there is one
+func describeState(diff api.SceneAction, color string, light *models.Light) []string {
+	var details []string
+	if diff.On != nil {
+		to := "off"
+		if *diff.On {
+			to = "on"
+		}
+		details = append(details, "on: "+from(light, onLabel)+to)
+	}
+	if diff.Brightness != nil {
+		details = append(details, fmt.Sprintf("brightness: %s%g%%", from(light, brightnessLabel), *diff.Brightness))
+	}
+	if diff.Mirek != nil {
+		details = append(details, fmt.Sprintf("temperature: %s%dK", from(light, temperatureLabel), models.MirekToKelvin(*diff.Mirek)))
+	}
+	if diff.XY != nil {
+		details = append(details, "color: "+from(light, colorLabel)+color)
+	}
+	return details
+}
+
+// from returns the current value of a light field followed by an arrow, or
+// "" for groups, whose lights may differ
+func from(light *models.Light, label func(*models.Light) string) string {
+	if light == nil {
+		return ""
+	}
+	if current := label(light); current != "" {
+		return current + " → "
+	}
+	return ""
+}
+
+func onLabel(l *models.Light) string {
+	if l.On {
+		return "on"
+	}
+	return "off"
+}
+
+func brightnessLabel(l *models.Light) string {
+	return fmt.Sprintf("%d%%", l.BrightnessPct())
+}
+
+func temperatureLabel(l *models.Light) string {
+	if l.Color == nil || l.Color.Mode != models.ColorModeColorTemp || l.Color.Mirek == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dK", models.MirekToKelvin(int(l.Color.Mirek)))
+}
+
+func colorLabel(l *models.Light) string {
+	if _, _, ok := currentXY(l.Color); !ok {
+		return ""
+	}
+	return l.Color.HexString()
+}
@@ -0,0 +1,119 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func stateTestState() *State {
+	state := testState()
+	state.Rooms[0].GroupedLightID = "g1"
+	for _, light := range state.Lights {
+		light.SupportsColor, light.SupportsColorTemp = true, true
+		light.Color = models.NewColorFromMirek(250, 254)
+		light.SetBrightnessPct(20)
+	}
+	state.Lights[1].On = true
+	return state
+}
+
+func applyStates(t *testing.T, profile string) (*Plan, []string) {
+	t.Helper()
+	spec, err := ParseSpec([]byte(profile))
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	p, err := Build(spec, stateTestState())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	bridge := &fakeBridge{}
+	if err := p.Apply(context.Background(), bridge, nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	return p, bridge.calls
+}
+
+func TestStates(t *testing.T) {
+	// Both lights of the study change the same way, in one group command
+	p, calls := applyStates(t, `
+states:
+  - room: Study
+    on: true
+    brightness: 40
+  - light: Ceiling
+    on: true
+    kelvin: 2700
+`)
+	want := []string{
+		"set group g1: on, 40%",
+		"set light l2: 370 mirek",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls = %q, want %q", calls, want)
+	}
+	var out bytes.Buffer
+	p.Write(&out)
+	for _, line := range []string{
+		`~ room "Study"`,
+		"lights: Hue color lamp 1, Strip",
+		`~ light "Ceiling"`,
+		"temperature: 4000K → 2703K",
+		"Plan: 0 to create, 2 to change.",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Plan output missing %q:\n%s", line, out.String())
+		}
+	}
+
+	// A light set apart from its room gets its own command, with only what
+	// differs from its current state
+	_, calls = applyStates(t, `
+states:
+  - room: Study
+    on: true
+    brightness: 40
+  - light: Strip
+    brightness: 20
+    color: "#FF0000"
+`)
+	x, y := models.RGBToXY(255, 0, 0)
+	strip := describeAction("l3", ActionSpec{On: ptr(true), XY: &[2]float64{x, y}})
+	want = []string{"set light l1: on, 40%", "set light " + strip}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls = %q, want %q", calls, want)
+	}
+
+	// Lights already in their state are left alone
+	p, _ = applyStates(t, `{"states": [{"light": "Ceiling", "on": true, "mirek": 250, "brightness": 20}]}`)
+	if !p.Empty() {
+		t.Errorf("Expected no changes, got %d", len(p.Changes))
+	}
+}
+
+func TestStatesErrors(t *testing.T) {
+	for _, tc := range []struct{ profile, want string }{
+		{"states:\n  - light: Ceiling\n", "nothing to set"},
+		{"states:\n  - light: Ceiling\n    room: Study\n    on: true\n", "exactly one of light, room or zone"},
+		{"states:\n  - light: Ceiling\n    color: orange\n", "invalid color"},
+		{"states:\n  - light: Ceiling\n    color: \"#FF0000\"\n    kelvin: 2700\n", "can't be combined"},
+		{"states:\n  - room: Nowhere\n    on: true\n", `unknown room "Nowhere"`},
+	} {
+		spec, err := ParseSpec([]byte(tc.profile))
+		if err == nil {
+			_, err = Build(spec, stateTestState())
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Profile %q: error %v, want %q", tc.profile, err, tc.want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
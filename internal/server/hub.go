@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/angristan/hue-tui/internal/api"
+)
+
+// eventJSON is the wire format of a bridge event on the SSE stream
+type eventJSON struct {
+	Type     api.EventType   `json:"type"`
+	Resource string          `json:"resource"`
+	ID       string          `json:"id"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// hub fans bridge events out to connected SSE clients
+type hub struct {
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+func newHub() *hub {
+	return &hub{clients: make(map[chan []byte]struct{})}
+}
+
+// subscribe registers a client. The returned function unregisters it.
+func (h *hub) subscribe() (<-chan []byte, func()) {
+	ch := make(chan []byte, 64)
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}
+}
+
+// publish sends events to every client. Slow clients drop events rather
+// than blocking the bridge subscription.
+func (h *hub) publish(events []api.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, event := range events {
+		data, err := json.Marshal(eventJSON{
+			Type:     event.Type,
+			Resource: event.Resource,
+			ID:       event.ResourceID,
+			Data:     event.Data,
+		})
+		if err != nil {
+			continue
+		}
+		for ch := range h.clients {
+			select {
+			case ch <- data:
+			default:
+			}
+		}
+	}
+}
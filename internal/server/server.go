@@ -0,0 +1,450 @@
+// Package server exposes the bridge client over a small local HTTP API,
+// used by `hue serve` to let other tools automate lights, and serves a
+// read-only dashboard of the lights at /.
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// requestTimeout bounds each bridge call made on behalf of an HTTP request
+const requestTimeout = 10 * time.Second
+
+var errNotFound = errors.New("not found")
+
+// dashboardHTML is the read-only lights overview served at /
+//
+//go:embed dashboard.html
+var dashboardHTML []byte
+
+// Server serves the HTTP API for a bridge
+type Server struct {
+	bridge api.BridgeClient
+	hub    *hub
+	mux    *http.ServeMux
+
+	// Reject requests that change lights
+	readOnly bool
+	// Host and port the server listens on, set with SetAddr
+	host, port string
+}
+
+// New creates a server for the given bridge
+func New(bridge api.BridgeClient) *Server {
+	s := &Server{
+		bridge: bridge,
+		hub:    newHub(),
+		mux:    http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /{$}", s.handleDashboard)
+	s.mux.HandleFunc("GET /api/rooms", s.handleRooms)
+	s.mux.HandleFunc("GET /api/lights/{id}", s.handleGetLight)
+	s.mux.HandleFunc("PUT /api/lights/{id}", s.writable(s.handleSetLight))
+	s.mux.HandleFunc("POST /api/lights/{id}/toggle", s.writable(s.handleToggleLight))
+	s.mux.HandleFunc("PUT /api/rooms/{id}", s.writable(s.handleSetRoom))
+	s.mux.HandleFunc("GET /api/scenes", s.handleScenes)
+	s.mux.HandleFunc("POST /api/scenes/{id}/activate", s.writable(s.handleActivateScene))
+	s.mux.HandleFunc("GET /api/events", s.handleEvents)
+
+	return s
+}
+
+// SetReadOnly makes the server reject requests that change lights, so the
+// dashboard can be shared on the local network
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
+
+// SetAddr sets the host:port the server listens on. Requests are only
+// accepted for this host, localhost or an IP address, on this port.
+func (s *Server) SetAddr(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	s.host, s.port = host, port
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkOrigin(r); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// checkOrigin rejects requests from web pages: cross-origin requests a
+// page sends to the API, and requests reaching it through DNS rebinding,
+// which name the page's host
+func (s *Server) checkOrigin(r *http.Request) error {
+	host, port, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host, port = r.Host, ""
+	}
+	host = strings.Trim(host, "[]")
+	if !s.allowedHost(host) || (s.port != "" && port != "" && port != s.port) {
+		return fmt.Errorf("unexpected host %q", r.Host)
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return fmt.Errorf("cross-origin request from %q", origin)
+		}
+	}
+	return nil
+}
+
+// allowedHost returns true for a host name the server can be reached by
+func (s *Server) allowedHost(host string) bool {
+	return strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil ||
+		(s.host != "" && strings.EqualFold(host, s.host))
+}
+
+// Publish forwards bridge events to connected /api/events clients.
+// It matches api.EventHandler so it can be passed to NewEventSubscription.
+func (s *Server) Publish(events []api.Event) {
+	s.hub.publish(events)
+}
+
+// lightJSON is the API representation of a light
+type lightJSON struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	RoomID     string      `json:"room_id,omitempty"`
+	On         bool        `json:"on"`
+	Brightness int         `json:"brightness"`
+	Reachable  bool        `json:"reachable"`
+	Mirek      *int        `json:"mirek,omitempty"`
+	XY         *[2]float64 `json:"xy,omitempty"`
+	Hex        string      `json:"hex,omitempty"`
+	// XY color points of gradient lights
+	Gradient [][2]float64 `json:"gradient,omitempty"`
+}
+
+// roomJSON is the API representation of a room
+type roomJSON struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	AnyOn  bool        `json:"any_on"`
+	AllOn  bool        `json:"all_on"`
+	Lights []lightJSON `json:"lights"`
+}
+
+// sceneJSON is the API representation of a scene
+type sceneJSON struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RoomID   string `json:"room_id"`
+	RoomName string `json:"room_name,omitempty"`
+	Active   bool   `json:"active"`
+}
+
+// lightStateJSON is the request body for PUT /api/lights/{id}
+type lightStateJSON struct {
+	On         *bool        `json:"on"`
+	Brightness *int         `json:"brightness"`
+	Mirek      *int         `json:"mirek"`
+	XY         *[2]float64  `json:"xy"`
+	Gradient   [][2]float64 `json:"gradient"`
+}
+
+func newLightJSON(light *models.Light, roomID string) lightJSON {
+	l := lightJSON{
+		ID:         light.ID,
+		Name:       light.Name,
+		RoomID:     roomID,
+		On:         light.On,
+		Brightness: light.BrightnessPct(),
+		Reachable:  light.Reachable,
+	}
+	if c := light.Color; c != nil {
+		switch c.Mode {
+		case models.ColorModeColorTemp:
+			mirek := int(c.Mirek)
+			l.Mirek = &mirek
+		default:
+			l.XY = &[2]float64{c.X, c.Y}
+		}
+		l.Hex = c.HexString()
+	}
+	if g := light.Gradient; g != nil {
+		for _, p := range g.Points {
+			l.Gradient = append(l.Gradient, [2]float64{p.X, p.Y})
+		}
+	}
+	return l
+}
+
+// writable wraps a handler that changes lights, rejecting it in read-only mode
+func (s *Server) writable(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.readOnly {
+			writeError(w, http.StatusForbidden, errors.New("server is read-only"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(dashboardHTML) // Error ignored: client went away
+}
+
+func (s *Server) handleRooms(w http.ResponseWriter, r *http.Request) {
+	rooms, _, err := s.fetch(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	result := make([]roomJSON, 0, len(rooms))
+	for _, room := range rooms {
+		rj := roomJSON{
+			ID:     room.ID,
+			Name:   room.Name,
+			AnyOn:  room.AnyOn,
+			AllOn:  room.AllOn,
+			Lights: make([]lightJSON, 0, len(room.Lights)),
+		}
+		for _, light := range room.Lights {
+			rj.Lights = append(rj.Lights, newLightJSON(light, room.ID))
+		}
+		result = append(result, rj)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleGetLight(w http.ResponseWriter, r *http.Request) {
+	light, roomID, err := s.findLight(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newLightJSON(light, roomID))
+}
+
+func (s *Server) handleSetLight(w http.ResponseWriter, r *http.Request) {
+	var req lightStateJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON body: %w", err))
+		return
+	}
+	if req.Brightness != nil && (*req.Brightness < 0 || *req.Brightness > 100) {
+		writeError(w, http.StatusBadRequest, errors.New("brightness must be between 0 and 100"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	id := r.PathValue("id")
+	var err error
+	if req.On != nil {
+		err = s.bridge.SetLightOn(ctx, id, *req.On)
+	}
+	if err == nil && req.Brightness != nil {
+		err = s.bridge.SetLightBrightness(ctx, id, *req.Brightness)
+	}
+	if err == nil && req.Mirek != nil {
+		err = s.bridge.SetLightColorTemp(ctx, id, *req.Mirek)
+	}
+	if err == nil && req.XY != nil {
+		err = s.bridge.SetLightColorXY(ctx, id, req.XY[0], req.XY[1])
+	}
+	if err == nil && req.Gradient != nil {
+		err = s.bridge.SetLightGradient(ctx, id, req.Gradient)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleToggleLight(w http.ResponseWriter, r *http.Request) {
+	light, _, err := s.findLight(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	on := !light.On
+	if err := s.bridge.SetLightOn(ctx, light.ID, on); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"on": on})
+}
+
+func (s *Server) handleSetRoom(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		On *bool `json:"on"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON body: %w", err))
+		return
+	}
+	if req.On == nil {
+		writeError(w, http.StatusBadRequest, errors.New(`"on" is required`))
+		return
+	}
+
+	rooms, _, err := s.fetch(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	var room *models.Room
+	for _, rm := range rooms {
+		if rm.ID == r.PathValue("id") {
+			room = rm
+			break
+		}
+	}
+	if room == nil || room.GroupedLightID == "" {
+		writeLookupError(w, errNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	if err := s.bridge.SetGroupedLightOn(ctx, room.GroupedLightID, *req.On); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleScenes(w http.ResponseWriter, r *http.Request) {
+	_, scenes, err := s.fetch(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	result := make([]sceneJSON, 0, len(scenes))
+	for _, scene := range scenes {
+		// Smart scenes are started, not recalled like the others
+		if scene.IsSmart {
+			continue
+		}
+		result = append(result, sceneJSON{
+			ID:       scene.ID,
+			Name:     scene.Name,
+			RoomID:   scene.RoomID,
+			RoomName: scene.RoomName,
+			Active:   scene.IsActive(),
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleActivateScene(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	if err := s.bridge.ActivateScene(ctx, r.PathValue("id")); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEvents streams bridge events as server-sent events
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	events, unsubscribe := s.hub.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-events:
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// fetch reads the current rooms and scenes from the bridge
+func (s *Server) fetch(ctx context.Context) ([]*models.Room, []*models.Scene, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	return s.bridge.FetchAll(ctx)
+}
+
+// findLight looks up a light and the room it belongs to
+func (s *Server) findLight(ctx context.Context, id string) (*models.Light, string, error) {
+	rooms, _, err := s.fetch(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, room := range rooms {
+		if light := room.LightByID(id); light != nil {
+			return light, room.ID, nil
+		}
+	}
+	return nil, "", errNotFound
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // Error ignored: client went away
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeLookupError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeError(w, http.StatusBadGateway, err)
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+)
+
+func TestServerAPI(t *testing.T) {
+	srv := httptest.NewServer(New(api.NewDemoBridge()))
+	defer srv.Close()
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("NewRequest failed: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	resp := do("GET", "/api/rooms", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/rooms status = %d", resp.StatusCode)
+	}
+	var rooms []roomJSON
+	if err := json.NewDecoder(resp.Body).Decode(&rooms); err != nil {
+		t.Fatalf("Failed to decode rooms: %v", err)
+	}
+	if len(rooms) == 0 || len(rooms[0].Lights) == 0 {
+		t.Fatalf("Expected rooms with lights, got %+v", rooms)
+	}
+
+	resp = do("POST", "/api/lights/light-lr-accent/toggle", "")
+	var toggled map[string]bool
+	if err := json.NewDecoder(resp.Body).Decode(&toggled); err != nil {
+		t.Fatalf("Failed to decode toggle response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !toggled["on"] {
+		t.Errorf("Toggle = %d %v, want 200 and on", resp.StatusCode, toggled)
+	}
+
+	tests := []struct {
+		method, path, body string
+		want               int
+	}{
+		{"PUT", "/api/lights/light-lr-floor", `{"brightness": 50}`, http.StatusNoContent},
+		{"PUT", "/api/lights/light-lr-floor", `{"brightness": 150}`, http.StatusBadRequest},
+		{"PUT", "/api/lights/light-lr-floor", `not json`, http.StatusBadRequest},
+		{"GET", "/api/lights/nope", "", http.StatusNotFound},
+		{"PUT", "/api/rooms/room-nope", `{"on": true}`, http.StatusNotFound},
+		{"POST", "/api/scenes/scene-relax/activate", "", http.StatusNoContent},
+		{"DELETE", "/api/scenes", "", http.StatusMethodNotAllowed},
+		{"GET", "/", "", http.StatusOK},
+		{"GET", "/nope", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if resp := do(tt.method, tt.path, tt.body); resp.StatusCode != tt.want {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestServerReadOnly(t *testing.T) {
+	s := New(api.NewDemoBridge())
+	s.SetReadOnly(true)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "http://127.0.0.1/api/scenes/scene-relax/activate", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Activate in read-only mode status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "http://127.0.0.1/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<title>Hue lights</title>") {
+		t.Errorf("Dashboard status = %d, want %d with the page", rec.Code, http.StatusOK)
+	}
+}
+
+func TestServerOrigin(t *testing.T) {
+	s := New(api.NewDemoBridge())
+	if err := s.SetAddr("hue.local:8080"); err != nil {
+		t.Fatalf("SetAddr failed: %v", err)
+	}
+
+	tests := []struct {
+		name, host, origin string
+		want               int
+	}{
+		{"loopback", "127.0.0.1:8080", "", http.StatusOK},
+		{"localhost", "localhost:8080", "", http.StatusOK},
+		{"listen host", "hue.local:8080", "", http.StatusOK},
+		{"LAN address", "192.168.1.20:8080", "", http.StatusOK},
+		{"same origin", "127.0.0.1:8080", "http://127.0.0.1:8080", http.StatusOK},
+		{"rebound host", "evil.example:8080", "", http.StatusForbidden},
+		{"other port", "127.0.0.1:9090", "", http.StatusForbidden},
+		{"cross origin", "127.0.0.1:8080", "http://evil.example", http.StatusForbidden},
+		{"null origin", "127.0.0.1:8080", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/scenes", nil)
+		req.Host = tt.host
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/api/scenes/scene-relax/activate", nil)
+	req.Host = "127.0.0.1:8080"
+	req.Header.Set("Origin", "http://evil.example")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Cross-origin activate status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	h := newHub()
+	events, unsubscribe := h.subscribe()
+	defer unsubscribe()
+
+	h.publish([]api.Event{{
+		Type:       api.EventTypeUpdate,
+		Resource:   "light",
+		ResourceID: "light-1",
+		Data:       []byte(`{"on":{"on":true}}`),
+	}})
+
+	select {
+	case data := <-events:
+		want := `{"type":"update","resource":"light","id":"light-1","data":{"on":{"on":true}}}`
+		if string(data) != want {
+			t.Errorf("Event = %s, want %s", data, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an event")
+	}
+}
@@ -0,0 +1,53 @@
+// Package sun computes sunrise and sunset times for a location
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+// j2000 is the Julian date of 2000-01-01 12:00 UTC
+const j2000 = 2451545.0
+
+// Times returns sunrise and sunset on the day of date, in the location of
+// date. Latitude and longitude are in degrees, east and north positive.
+// ok is false when the sun doesn't rise or set that day (polar day or night).
+func Times(date time.Time, lat, lon float64) (sunrise, sunset time.Time, ok bool) {
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, date.Location())
+
+	// Solar noon is close to local noon, pick the day number accordingly
+	n := math.Round(julian(noon) - j2000 + lon/360)
+	meanNoon := n - lon/360
+
+	anomaly := math.Mod(357.5291+0.98560028*meanNoon, 360)
+	center := 1.9148*sinDeg(anomaly) + 0.0200*sinDeg(2*anomaly) + 0.0003*sinDeg(3*anomaly)
+	ecliptic := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := j2000 + meanNoon + 0.0053*sinDeg(anomaly) - 0.0069*sinDeg(2*ecliptic)
+
+	sinDecl := sinDeg(ecliptic) * sinDeg(23.4397)
+	cosDecl := math.Cos(math.Asin(sinDecl))
+	// -0.833° accounts for refraction and the size of the sun's disc
+	cosHour := (sinDeg(-0.833) - sinDeg(lat)*sinDecl) / (cosDeg(lat) * cosDecl)
+	if cosHour < -1 || cosHour > 1 {
+		return time.Time{}, time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHour) * 180 / math.Pi
+
+	loc := date.Location()
+	sunrise = fromJulian(transit - hourAngle/360).In(loc)
+	sunset = fromJulian(transit + hourAngle/360).In(loc)
+	return sunrise, sunset, true
+}
+
+func julian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + 2440587.5
+}
+
+func fromJulian(jd float64) time.Time {
+	secs := (jd - 2440587.5) * 86400
+	return time.Unix(0, int64(secs*float64(time.Second))).Round(time.Second)
+}
+
+func sinDeg(deg float64) float64 { return math.Sin(deg * math.Pi / 180) }
+func cosDeg(deg float64) float64 { return math.Cos(deg * math.Pi / 180) }
@@ -0,0 +1,60 @@
+package sun
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimes(t *testing.T) {
+	cest := time.FixedZone("CEST", 2*3600)
+	est := time.FixedZone("EST", -5*3600)
+	aest := time.FixedZone("AEST", 10*3600)
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		lat, lon  float64
+		rise, set string
+	}{
+		{"Paris summer solstice", time.Date(2024, 6, 21, 0, 0, 0, 0, cest), 48.8566, 2.3522, "05:47", "21:58"},
+		{"New York winter solstice", time.Date(2024, 12, 21, 23, 0, 0, 0, est), 40.7128, -74.0060, "07:17", "16:32"},
+		{"Sydney", time.Date(2024, 7, 1, 12, 0, 0, 0, aest), -33.8688, 151.2093, "07:00", "16:57"},
+	}
+
+	within := func(got time.Time, want string, loc *time.Location) bool {
+		w, err := time.ParseInLocation("2006-01-02 15:04", got.Format("2006-01-02 ")+want, loc)
+		if err != nil {
+			return false
+		}
+		diff := got.Sub(w)
+		return diff > -2*time.Minute && diff < 2*time.Minute
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rise, set, ok := Times(tt.date, tt.lat, tt.lon)
+			if !ok {
+				t.Fatal("Expected the sun to rise and set")
+			}
+			loc := tt.date.Location()
+			if rise.Location() != loc || rise.Day() != tt.date.Day() || set.Day() != tt.date.Day() {
+				t.Errorf("Expected times on the same local day, got %v and %v", rise, set)
+			}
+			if !within(rise, tt.rise, loc) {
+				t.Errorf("sunrise = %s, want about %s", rise.Format("15:04"), tt.rise)
+			}
+			if !within(set, tt.set, loc) {
+				t.Errorf("sunset = %s, want about %s", set.Format("15:04"), tt.set)
+			}
+		})
+	}
+}
+
+func TestTimesPolar(t *testing.T) {
+	// Tromsø has midnight sun in June and polar night in December
+	for _, month := range []time.Month{time.June, time.December} {
+		if _, _, ok := Times(time.Date(2024, month, 21, 12, 0, 0, 0, time.UTC), 69.6496, 18.9560); ok {
+			t.Errorf("Expected no sunrise or sunset in Tromsø in %s", month)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// flashDuration is how long the header flashes on an alert
+const flashDuration = 3 * time.Second
+
+// bellOut is where the terminal bell is rung
+var bellOut io.Writer = os.Stdout
+
+// alert rings the bell and flashes the header with reason, as configured.
+// It returns nil when alerts are off.
+func (m *Model) alert(reason string) tea.Cmd {
+	alerts := m.config.Alerts
+	if alerts == nil {
+		return nil
+	}
+	tuiLog.Warnf("Alert: %s", reason)
+
+	var cmds []tea.Cmd
+	if alerts.Bell {
+		cmds = append(cmds, ringBell)
+	}
+	if alerts.Flash {
+		m.flashID++
+		id := m.flashID
+		m.mainScreen.SetFlash(reason)
+		cmds = append(cmds, tea.Tick(flashDuration, func(time.Time) tea.Msg {
+			return messages.FlashEndMsg{ID: id}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// endFlash stops the header flash, unless a newer alert restarted it
+func (m *Model) endFlash(id int) {
+	if id == m.flashID {
+		m.mainScreen.SetFlash("")
+	}
+}
+
+// watchedSensor reports whether motion on a sensor is alerted
+func (m *Model) watchedSensor(sensorID string) bool {
+	return m.config.Alerts != nil && slices.Contains(m.config.Alerts.MotionSensors, sensorID)
+}
+
+func ringBell() tea.Msg {
+	_, _ = io.WriteString(bellOut, "\a") // Error ignored: the bell is best effort
+	return nil
+}
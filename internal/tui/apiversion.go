@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+)
+
+// newBridge creates the client of a configured bridge: a V1 client for
+// bridges detected without CLIP v2, a HueBridge otherwise
+func (m *Model) newBridge(bridgeCfg *config.BridgeConfig) (api.BridgeClient, error) {
+	audit, err := m.auditLog()
+	if err != nil {
+		return nil, err
+	}
+	if bridgeCfg.APIVersion == api.APIVersionV1 {
+		bridge := api.NewV1Bridge(bridgeCfg.Host, bridgeCfg.Username, bridgeCfg.BridgeID)
+		if audit != nil {
+			bridge.SetAuditLog(audit)
+		}
+		return bridge, nil
+	}
+	bridge, err := newHueBridge(m.config, bridgeCfg, audit)
+	if err != nil {
+		// A nil *HueBridge would make a non-nil interface
+		return nil, err
+	}
+	return bridge, nil
+}
+
+// connectCmd fetches the bridge data, first probing the API version of
+// bridges that weren't probed yet
+func (m Model) connectCmd() tea.Cmd {
+	// The bridge couldn't be set up, and m.err says why
+	if m.bridge == nil && m.err != nil {
+		return nil
+	}
+	hueBridge, ok := m.bridge.(*api.HueBridge)
+	if !ok || m.demoMode {
+		return m.fetchDataCmd()
+	}
+	bridgeCfg, err := m.config.GetBridge(hueBridge.BridgeID())
+	if err != nil || bridgeCfg.APIVersion != "" {
+		return m.fetchDataCmd()
+	}
+	ctx := m.ctx
+	return func() tea.Msg {
+		version, err := hueBridge.DetectAPIVersion(ctx)
+		return messages.APIVersionDetectedMsg{BridgeID: hueBridge.BridgeID(), Version: version, Err: err}
+	}
+}
+
+// apiVersionDetected saves the probed API version and switches V1 bridges
+// to the V1 client before fetching. A failed probe is retried on the next
+// refresh, the fetch reporting the error meanwhile.
+func (m *Model) apiVersionDetected(msg messages.APIVersionDetectedMsg) tea.Cmd {
+	if msg.Err != nil || m.bridge == nil || m.bridge.BridgeID() != msg.BridgeID {
+		return m.fetchDataCmd()
+	}
+	bridgeCfg, err := m.config.GetBridge(msg.BridgeID)
+	if err != nil {
+		return m.fetchDataCmd()
+	}
+	// The V1 API is plain HTTP, which would bypass a certificate policy
+	// the user chose or a fingerprint already pinned: it has to be opted
+	// into by hand
+	if msg.Version == api.APIVersionV1 && !v1Allowed(bridgeCfg) {
+		m.reportError(fmt.Errorf("bridge %s has no CLIP v2 API; set \"api_version\": \"v1\" in the config to use its unencrypted V1 API", msg.BridgeID))
+		return m.fetchDataCmd()
+	}
+	bridgeCfg.APIVersion = msg.Version
+	if err := m.config.Save(); err != nil {
+		m.reportError(err)
+	}
+	if msg.Version == api.APIVersionV1 {
+		tuiLog.Infof("Bridge %s only has the V1 API", msg.BridgeID)
+		bridge, err := m.newBridge(bridgeCfg)
+		if err != nil {
+			m.reportError(err)
+			return nil
+		}
+		m.bridge = bridge
+	}
+	return m.fetchDataCmd()
+}
+
+// v1Allowed returns true if the bridge may be switched to the V1 API on
+// its own: neither a certificate is pinned nor a TLS mode chosen
+func v1Allowed(bridgeCfg *config.BridgeConfig) bool {
+	mode := api.TLSMode(bridgeCfg.TLSMode)
+	return bridgeCfg.CertFingerprint == "" && mode != api.TLSModeCA && mode != api.TLSModeTOFU
+}
@@ -2,36 +2,31 @@ package tui
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/angristan/hue-tui/internal/api"
 	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/hooks"
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/logging"
 	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/components"
 	"github.com/angristan/hue-tui/internal/tui/messages"
 	"github.com/angristan/hue-tui/internal/tui/screens"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-var debugMode = os.Getenv("HUE_DEBUG") != ""
-var debugLog *log.Logger
-
-func init() {
-	if debugMode {
-		f, err := os.OpenFile("hue-debug.log", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-		if err != nil {
-			debugLog = log.New(os.Stderr, "[HUE] ", log.LstdFlags|log.Lmicroseconds)
-		} else {
-			debugLog = log.New(f, "[HUE] ", log.LstdFlags|log.Lmicroseconds)
-		}
-		debugLog.Println("Debug mode enabled")
-	}
-}
+// tuiLog is the log of the TUI
+var tuiLog = logging.For("tui")
 
 func debugf(format string, args ...interface{}) {
-	if debugMode && debugLog != nil {
-		debugLog.Printf(format, args...)
-	}
+	tuiLog.Debugf(format, args...)
 }
 
 // Screen represents the current screen state
@@ -41,6 +36,13 @@ const (
 	ScreenSetup Screen = iota
 	ScreenMain
 	ScreenScenes
+	ScreenEntertainment
+	ScreenSchedules
+	ScreenRooms
+	ScreenDevices
+	ScreenBridges
+	ScreenNotifications
+	ScreenSceneCleanup
 )
 
 // Model is the main application model
@@ -52,30 +54,120 @@ type Model struct {
 	bridge   api.BridgeClient
 	events   *api.EventSubscription
 	demoMode bool
+	// Audit log shared by the clients of every bridge connected to,
+	// opened on first use and closed by Close
+	audit     *api.AuditLog
+	auditFile *os.File
 
 	// Event handling
 	eventChan chan tea.Msg
-	pending   *PendingTracker
+	// Optional event log to record to, or to replay from
+	recorder *api.EventRecorder
+	replay   []api.EventLogEntry
+	pending  *PendingTracker
+	// Room to focus once the lights first load, cleared after
+	startRoom string
+	// Bridge whose home group was fetched for the "All lights" entry
+	homeGroupBridge string
+	// Drops the updates of muted rooms
+	eventFilter *eventFilter
+	// Merges rapid brightness and color writes to the same light
+	coalescer *writeCoalescer
+	// Lights whose last update was never confirmed, being fetched again
+	unconfirmed map[string]bool
+	// Commands run on bridge activity
+	hooks *hooks.Runner
+	// Lights our scene recalls and local schedules turn on, until when
+	// their coming on doesn't run the light_on hooks
+	ownLightsOn map[string]time.Time
+
+	// Bumped by every alert, so that only the last one ends the flash
+	flashID int
+	// Notifications shown over the bottom of the screen for a while
+	toasts *toastQueue
 
 	// Data
 	rooms  []*models.Room
 	scenes []*models.Scene
 
+	// Set while scenes can't be fetched, with the delay before the next
+	// retry and a counter that invalidates superseded retries
+	scenesErr     error
+	scenesBackoff time.Duration
+	scenesAttempt int
+
+	// Set while the bridge is unreachable, with the next refetch attempt
+	offline bool
+	retryAt time.Time
+
+	// Set while the event stream can't connect and data is refreshed every
+	// pollInterval instead
+	polling      bool
+	pollTicking  bool
+	pollInterval time.Duration
+
+	// Set once the terminal has been unfocused without input for
+	// idleAfter, which pauses polling and animations until the next input
+	idle         bool
+	idleChecking bool
+	idleAfter    time.Duration
+	focused      bool
+	lastInput    time.Time
+
+	// Linked lights and calibration in demo mode, which has no config to
+	// save them to
+	demoLinks     [][]string
+	demoOffsets   map[string]int
+	demoMuted     []string
+	demoShortcuts map[string]map[string]string
+	// Scene recall durations in demo mode, in milliseconds by scene ID
+	demoTransitions map[string]int
+	// Remembered light colors in demo mode, by light ID
+	demoMemory map[string]models.LightMemory
+	// How long scenes recalled with alt+enter take to fade in
+	sceneFade time.Duration
+
+	// Local schedules in demo mode, and when they were last checked
+	demoSchedules      []config.LocalSchedule
+	schedulesCheckedAt time.Time
+
+	// Whether rooms were changed from the manage rooms screen
+	roomsChanged bool
+
+	// Most recently activated scene, used for the header accent
+	accentSceneID string
+
+	// Set when the bridge presents a different certificate than the pinned one
+	certAlert *certAlert
+
 	// Current screen
 	screen Screen
 
 	// Screen models
-	setupScreen  screens.SetupModel
-	mainScreen   screens.MainModel
-	scenesScreen screens.ScenesModel
+	setupScreen         screens.SetupModel
+	mainScreen          screens.MainModel
+	scenesScreen        screens.ScenesModel
+	entertainmentScreen screens.EntertainmentModel
+	devicesScreen       screens.DevicesModel
+	bridgesScreen       screens.BridgesModel
+	schedulesScreen     screens.SchedulesModel
+	roomsScreen         screens.RoomsModel
+	notificationsScreen screens.NotificationsModel
+	sceneCleanupScreen  screens.SceneCleanupModel
 
 	// Window size
 	width  int
 	height int
 
+	// How times and numbers are shown
+	format locale.Format
+
 	// Error state
 	err error
 
+	// Version of this build, compared with the latest release
+	version string
+
 	// Context for cancellation
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -86,12 +178,19 @@ func NewModel(cfg *config.Config, demoMode bool) Model {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	m := Model{
-		config:    cfg,
-		ctx:       ctx,
-		cancel:    cancel,
-		eventChan: make(chan tea.Msg, 100),
-		pending:   NewPendingTracker(),
-		demoMode:  demoMode,
+		config:      cfg,
+		ctx:         ctx,
+		cancel:      cancel,
+		eventChan:   make(chan tea.Msg, 100),
+		pending:     NewPendingTracker(),
+		demoMode:    demoMode,
+		eventFilter: newEventFilter(),
+		coalescer:   newWriteCoalescer(coalesceWindow),
+		unconfirmed: make(map[string]bool),
+		toasts:      newToastQueue(),
+		ownLightsOn: make(map[string]time.Time),
+
+		schedulesCheckedAt: time.Now(),
 	}
 
 	// Determine initial screen
@@ -103,7 +202,12 @@ func NewModel(cfg *config.Config, demoMode bool) Model {
 		m.screen = ScreenMain
 		bridgeCfg, _ := cfg.GetLastBridge()
 		if bridgeCfg != nil {
-			m.bridge = api.NewHueBridge(bridgeCfg.Host, bridgeCfg.Username, bridgeCfg.BridgeID)
+			bridge, err := m.newBridge(bridgeCfg)
+			if err != nil {
+				m.err = err
+			} else {
+				m.bridge = bridge
+			}
 		}
 	} else {
 		m.screen = ScreenSetup
@@ -112,7 +216,50 @@ func NewModel(cfg *config.Config, demoMode bool) Model {
 	// Initialize screen models
 	m.setupScreen = screens.NewSetupModel()
 	m.mainScreen = screens.NewMainModel(nil)
+	m.mainScreen.SetFadeDuration(time.Duration(cfg.FadeSeconds) * time.Second)
+	m.sceneFade = time.Duration(cfg.SceneFadeSeconds) * time.Second
+	if m.sceneFade <= 0 {
+		m.sceneFade = defaultSceneFade
+	}
+	m.mainScreen.SetOtherGrouping(cfg.OtherLights)
+	m.mainScreen.SetRoomDimming(cfg.RoomDimming)
+	m.mainScreen.SetAcceleration(cfg.Acceleration)
+	m.mainScreen.SetRememberColors(cfg.RememberColors)
+	m.pollInterval = time.Duration(cfg.PollSeconds) * time.Second
+	if m.pollInterval <= 0 {
+		m.pollInterval = defaultPollInterval
+	}
+	m.idleAfter = time.Duration(cfg.IdleMinutes) * time.Minute
+	if m.idleAfter <= 0 {
+		m.idleAfter = defaultIdleAfter
+	}
+	m.focused, m.lastInput = true, time.Now()
+	m.startRoom = cfg.StartRoom
 	m.scenesScreen = screens.NewScenesModel()
+	m.scenesScreen.SetSort(cfg.SceneSort)
+	m.entertainmentScreen = screens.NewEntertainmentModel()
+	m.devicesScreen = screens.NewDevicesModel()
+	m.bridgesScreen = screens.NewBridgesModel()
+	m.schedulesScreen = screens.NewSchedulesModel()
+	m.roomsScreen = screens.NewRoomsModel()
+	m.notificationsScreen = screens.NewNotificationsModel()
+	m.sceneCleanupScreen = screens.NewSceneCleanupModel()
+	m.sceneCleanupScreen.SetDays(cfg.UnusedSceneDays)
+
+	if format, err := locale.New(cfg.Locale); err != nil {
+		m.err = err
+	} else {
+		m.format = format
+	}
+	if runner, err := hooks.New(cfg.Hooks); err != nil {
+		m.err = err
+	} else {
+		runner.SetErrorHandler(m.handleHookError)
+		m.hooks = runner
+	}
+	m.schedulesScreen.SetLocale(m.format)
+	m.notificationsScreen.SetLocale(m.format)
+	m.mainScreen.SetLocale(m.format)
 
 	return m
 }
@@ -122,6 +269,14 @@ func (m Model) Init() tea.Cmd {
 	debugf("Init called, screen=%d, demoMode=%v, bridge=%v", m.screen, m.demoMode, m.bridge != nil)
 	cmds := []tea.Cmd{
 		tea.SetWindowTitle("Hue CLI"),
+		localScheduleTick(),
+	}
+	// Demo mode has no events to confirm updates with
+	if !m.demoMode {
+		cmds = append(cmds, reconcileTick())
+	}
+	if cmd := m.updateCheck(); cmd != nil {
+		cmds = append(cmds, cmd)
 	}
 
 	// Start with appropriate screen initialization
@@ -131,34 +286,70 @@ func (m Model) Init() tea.Cmd {
 		cmds = append(cmds, m.setupScreen.Init())
 	case ScreenMain:
 		debugf("Init: starting main screen, will fetch data")
-		cmds = append(cmds, m.mainScreen.Init(), m.fetchDataCmd())
+		cmds = append(cmds, m.mainScreen.Init(), m.connectCmd())
 	}
 
 	return tea.Batch(cmds...)
 }
 
-// Update handles messages
+// Update handles messages, then keeps the shown toasts expiring
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if tick := m.toasts.tick(); tick != nil {
+		cmd = tea.Batch(cmd, tick)
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	// Log all message types for debugging
 	debugf("Update received message: %T", msg)
 
+	// Input after an idle pause resumes background work, then is handled
+	// as usual
+	if cmd := m.noteInput(msg); cmd != nil {
+		next, nextCmd := m.update(msg)
+		return next, tea.Batch(cmd, nextCmd)
+	}
+
 	switch msg := msg.(type) {
+	case updateAvailableMsg:
+		m.notify(components.ToastInfo, msg.release.Hint())
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.mainScreen.SetSize(msg.Width, msg.Height)
 		m.setupScreen.SetSize(msg.Width, msg.Height)
 		m.scenesScreen.SetSize(msg.Width, msg.Height)
+		m.entertainmentScreen.SetSize(msg.Width, msg.Height)
+		m.devicesScreen.SetSize(msg.Width, msg.Height)
+		m.bridgesScreen.SetSize(msg.Width, msg.Height)
+		m.schedulesScreen.SetSize(msg.Width, msg.Height)
+		m.roomsScreen.SetSize(msg.Width, msg.Height)
+		m.notificationsScreen.SetSize(msg.Width, msg.Height)
+		m.sceneCleanupScreen.SetSize(msg.Width, msg.Height)
 
 	case tea.KeyMsg:
 		// Global key handlers
-		switch msg.String() {
-		case "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			m.cancel()
 			return m, tea.Quit
 		}
+		// Nothing on screen can be acted on while the terminal is too small
+		if m.tooSmall() {
+			return m, m.handleTooSmallKey(msg.String())
+		}
+		if msg.String() == "T" && m.certAlert != nil {
+			return m, m.trustCertificate()
+		}
+		// The error panel takes the keys until it is dismissed
+		if m.err != nil && m.certAlert == nil && m.screen != ScreenSetup {
+			return m, m.handleErrorKey(msg.String())
+		}
 
 	case messages.BridgeConnectedMsg:
 		// Bridge connection successful
@@ -172,77 +363,112 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 			m.config.LastBridgeID = msg.Bridge.BridgeID()
 			if err := m.config.Save(); err != nil {
-				m.err = err
+				m.reportError(err)
 			}
+			m.applyTLSPolicy()
 		}
 
 		m.screen = ScreenMain
 		m.mainScreen.SetLoading(true)
-		cmds = append(cmds, m.mainScreen.Init(), m.fetchDataCmd())
+		cmds = append(cmds, m.mainScreen.Init(), m.connectCmd())
+
+	case messages.APIVersionDetectedMsg:
+		cmds = append(cmds, m.apiVersionDetected(msg))
+
+	case messages.FetchProgressMsg:
+		m.mainScreen.SetFetchProgress(msg.Resource, msg.Count)
+		cmds = append(cmds, msg.Next)
 
 	case messages.DataFetchedMsg:
 		debugf("DataFetchedMsg received: %d rooms, %d scenes", len(msg.Rooms), len(msg.Scenes))
+		m.uncalibrate(msg.Rooms)
 		m.rooms = msg.Rooms
-		m.scenes = msg.Scenes
+		// Scenes already loaded stay usable while the bridge fails to list them
+		if msg.ScenesErr != nil {
+			cmds = append(cmds, m.scenesFailed(msg.ScenesErr))
+		} else {
+			m.scenes = msg.Scenes
+			if m.scenesErr != nil {
+				m.scenesLoaded()
+			}
+		}
 		m.mainScreen.SetData(m.rooms, m.scenes)
+		m.mainScreen.SetLightRoles(m.lightRoles())
+		m.mainScreen.SetLightLinks(m.lightLinks())
+		m.mainScreen.SetColorTempOffsets(m.colorTempOffsets())
+		m.mainScreen.SetLightMemory(m.lightMemory())
+		m.applyMutedRooms()
+		m.applySceneShortcuts()
+		m.applySceneTransitions()
 		m.scenesScreen.SetScenes(m.scenes, m.rooms)
+		m.updateAccent()
+		m.pinCertificate()
+		m.saveRemoteToken()
 		debugf("SetData called, mainScreen.loading should be false now")
+		if cmd := m.fetchHomeGroupCmd(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if m.startRoom != "" {
+			if !m.mainScreen.FocusRoom(m.startRoom) {
+				m.mainScreen.SetNotice(fmt.Sprintf("No room named %q", m.startRoom))
+			}
+			m.startRoom = ""
+		}
 
 		// Start event subscription (skip in demo mode - state changes are immediate)
 		if m.events == nil && m.bridge != nil && !m.demoMode {
 			debugf("Starting event subscription")
 			// Cast to *HueBridge for event subscription (only real bridges support SSE)
 			if hueBridge, ok := m.bridge.(*api.HueBridge); ok {
-				m.events = api.NewEventSubscription(hueBridge, func(events []api.Event) {
-					debugf("Received %d events from WebSocket", len(events))
-					for _, event := range events {
-						debugf("  Event: type=%s resource=%s id=%s", event.Type, event.Resource, event.ResourceID)
-						if event.Resource == "light" && event.Type == api.EventTypeUpdate {
-							if update, err := api.ParseLightUpdate(event); err == nil {
-								msg := messages.LightUpdateMsg{
-									LightID: update.ID,
-									On:      update.On,
-								}
-								if update.Brightness != nil {
-									b := int(*update.Brightness)
-									msg.Brightness = &b
-								}
-								if update.ColorTemp != nil {
-									msg.ColorTemp = update.ColorTemp
-								}
-								if update.ColorXY != nil {
-									msg.ColorXY = &struct{ X, Y float64 }{update.ColorXY.X, update.ColorXY.Y}
-								}
-								debugf("  Parsed light update: id=%s on=%v brightness=%v", update.ID, update.On, update.Brightness)
-								// Non-blocking send to avoid deadlock
-								select {
-								case m.eventChan <- msg:
-									debugf("  Sent to event channel")
-								default:
-									debugf("  Channel full, dropped event")
-								}
-							} else {
-								debugf("  Failed to parse light update: %v", err)
-							}
-						}
-					}
-				})
-				if err := m.events.Start(m.ctx); err != nil {
-					debugf("Failed to start event subscription: %v", err)
-					m.err = err
-				} else {
-					debugf("Event subscription started successfully")
+				m.events = api.NewEventSubscription(hueBridge, m.handleEvents)
+				m.events.SetRecorder(m.recorder)
+				m.events.SetIdleTimeout(time.Duration(m.config.EventIdleSeconds) * time.Second)
+				hueBridge.SetConnectionHandler(m.handleConnection)
+				hueBridge.SetThrottleHandler(m.handleThrottle)
+				if auth := hueBridge.Remote(); auth != nil {
+					auth.SetTokenHandler(m.handleRemoteToken)
 				}
-				cmds = append(cmds, m.listenForEvents())
+				m.events.SetStreamHandler(m.handleStream)
+				cmds = append(cmds, m.startEvents())
+			} else if v1Bridge, ok := m.bridge.(*api.V1Bridge); ok {
+				// V1 bridges have no event stream, but report going
+				// offline and throttled commands all the same
+				v1Bridge.SetConnectionHandler(m.handleConnection)
+				v1Bridge.SetThrottleHandler(m.handleThrottle)
 			}
 		}
 
+		// A recorded event log is replayed on top of the demo data
+		if m.events == nil && m.replay != nil {
+			debugf("Starting event replay (%d messages)", len(m.replay))
+			m.events = api.NewEventReplay(m.replay, m.handleEvents)
+			cmds = append(cmds, m.startEvents())
+		}
+
 	case messages.ErrorMsg:
-		m.err = msg.Err
+		// Failures while offline are covered by the offline banner
+		// Errors the app can go on with only show as a toast
+		if !m.offline && !errors.Is(msg.Err, api.ErrBridgeUnreachable) {
+			if m.blockingError(msg.Err) {
+				m.err = msg.Err
+			} else {
+				m.reportError(msg.Err)
+			}
+		}
+		if errors.Is(msg.Err, api.ErrCertificateMismatch) {
+			m.certAlert = m.newCertAlert()
+		}
 		// Stop the loading spinner on error
 		m.mainScreen.SetLoading(false)
+		m.entertainmentScreen.SetLoading(false)
+		m.devicesScreen.SetLoading(false)
+		m.schedulesScreen.SetLoading(false)
 
 	case messages.ShowScenesMsg:
+		if m.scenesErr != nil && len(m.scenes) == 0 {
+			m.mainScreen.SetNotice("Scenes unavailable — retrying…")
+			return m, m.fetchScenesCmd()
+		}
 		m.screen = ScreenScenes
 		m.scenesScreen.SetRoomFilter(msg.RoomID)
 		return m, nil
@@ -253,19 +479,289 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case messages.SceneActivatedMsg:
 		m.screen = ScreenMain
+		// Smart scenes are started rather than recalled
+		if scene := m.findScene(msg.SceneID); scene != nil && scene.IsSmart {
+			return m, m.smartSceneCmd(scene, true)
+		}
+		m.accentSceneID = msg.SceneID
+		m.mainScreen.RememberBeforeScene(msg.SceneID)
+		m.markSceneRecalled(msg.SceneID)
+		m.expectLightsOn(m.sceneLightIDs(msg.SceneID))
+		if msg.Fade {
+			if scene := m.findScene(msg.SceneID); scene != nil {
+				m.mainScreen.SetNotice(fmt.Sprintf("Fading in %s over %s", scene.Name, formatSceneFade(m.sceneFade)))
+			}
+		}
 		if m.bridge != nil {
-			cmds = append(cmds, m.activateSceneCmd(msg.SceneID))
+			if msg.Fade {
+				cmds = append(cmds, m.fadeSceneCmd(msg.SceneID))
+			} else {
+				cmds = append(cmds, m.activateSceneCmd(msg.SceneID))
+			}
+		}
+
+	case messages.HomeGroupFetchedMsg:
+		m.handleHomeGroupFetched(msg)
+		return m, nil
+
+	case messages.SmartSceneToggleMsg:
+		m.screen = ScreenMain
+		if scene := m.findScene(msg.SceneID); scene != nil {
+			cmds = append(cmds, m.smartSceneCmd(scene, msg.Active))
+		}
+
+	case messages.StopSceneDynamicsMsg:
+		m.screen = ScreenMain
+		if m.bridge != nil {
+			cmds = append(cmds, m.stopSceneDynamicsCmd(msg.SceneID))
+		}
+
+	case messages.RetryScenesMsg:
+		if msg.Attempt != m.scenesAttempt || m.scenesErr == nil || m.bridge == nil {
+			return m, nil
+		}
+		return m, m.fetchScenesCmd()
+
+	case messages.ScenesFetchedMsg:
+		if msg.Err != nil {
+			return m, m.scenesFailed(msg.Err)
+		}
+		m.scenes = msg.Scenes
+		m.scenesLoaded()
+		m.refreshScreens()
+		return m, nil
+
+	case messages.SaveSceneMsg:
+		m.screen = ScreenMain
+		if m.bridge != nil {
+			cmds = append(cmds, m.saveSceneCmd(msg.Name, msg.RoomID))
+		}
+
+	case messages.SceneSavedMsg:
+		m.mainScreen.SetNotice("Saved scene " + msg.Name)
+		return m, func() tea.Msg { return messages.RefreshMsg{} }
+
+	case messages.GenerateScenesMsg:
+		m.screen = ScreenMain
+		if m.bridge != nil {
+			cmds = append(cmds, m.generateScenesCmd(msg.RoomID))
+		}
+
+	case messages.ScenesGeneratedMsg:
+		if len(msg.Names) == 0 {
+			m.mainScreen.SetNotice("The room already has natural light scenes")
+			return m, nil
+		}
+		m.mainScreen.SetNotice("Created scenes " + strings.Join(msg.Names, ", "))
+		return m, func() tea.Msg { return messages.RefreshMsg{} }
+
+	case messages.ShowEntertainmentMsg:
+		m.screen = ScreenEntertainment
+		m.entertainmentScreen.SetLights(m.rooms)
+		m.entertainmentScreen.SetLoading(true)
+		return m, m.fetchEntertainmentCmd()
+
+	case messages.HideEntertainmentMsg:
+		m.screen = ScreenMain
+		return m, nil
+
+	case messages.EntertainmentFetchedMsg:
+		m.entertainmentScreen.SetAreas(msg.Areas)
+		return m, nil
+
+	case messages.EntertainmentToggleMsg:
+		return m, m.toggleEntertainmentCmd(msg.AreaID, msg.Active)
+
+	case messages.EntertainmentChangedMsg:
+		cmds = append(cmds, m.listenForEvents())
+		if m.screen == ScreenEntertainment {
+			cmds = append(cmds, m.fetchEntertainmentCmd())
+		}
+		return m, tea.Batch(cmds...)
+
+	case messages.ShowSceneCleanupMsg:
+		m.screen = ScreenSceneCleanup
+		m.sceneCleanupScreen.SetLoading(true)
+		return m, m.fetchSceneCleanupCmd()
+
+	case messages.HideSceneCleanupMsg:
+		m.screen = ScreenMain
+		return m, nil
+
+	case messages.SceneCleanupFetchedMsg:
+		m.sceneCleanupScreen.SetScenes(msg.Scenes, msg.Groups)
+		return m, nil
+
+	case messages.DeleteSceneMsg:
+		return m, m.deleteSceneCmd(msg.SceneID)
+
+	case messages.SceneDeletedMsg:
+		return m, m.sceneCleanupScreen.SceneDeleted(msg.SceneID, msg.Err)
+
+	case messages.ScenesCleanedMsg:
+		if m.bridge == nil {
+			return m, nil
+		}
+		return m, m.fetchScenesCmd()
+
+	case messages.ShowDevicesMsg:
+		m.screen = ScreenDevices
+		m.devicesScreen.SetLoading(true)
+		return m, m.fetchDevicesCmd()
+
+	case messages.HideDevicesMsg:
+		m.screen = ScreenMain
+		return m, nil
+
+	case messages.ShowNotificationsMsg:
+		m.toasts.dismiss()
+		m.notificationsScreen.SetToasts(m.toasts.history)
+		m.screen = ScreenNotifications
+		return m, nil
+
+	case messages.HideNotificationsMsg:
+		m.screen = ScreenMain
+		return m, nil
+
+	case messages.ClearNotificationsMsg:
+		m.toasts.clear()
+		return m, nil
+
+	case toastTickMsg:
+		return m, m.handleToastTick(time.Now())
+
+	case messages.ShowBridgesMsg:
+		return m, m.showBridges()
+
+	case messages.HideBridgesMsg, messages.HideSetupMsg:
+		m.screen = ScreenMain
+		return m, nil
+
+	case messages.SwitchBridgeMsg:
+		return m, m.switchToBridge(msg.BridgeID)
+
+	case messages.FetchBridgeInfoMsg:
+		m.updateBridgeStream()
+		return m, m.fetchBridgeInfoCmd()
+
+	case messages.BridgeInfoFetchedMsg:
+		m.bridgesScreen.SetInfo(msg.Info, msg.Err)
+		return m, nil
+
+	case messages.AddBridgeMsg:
+		return m, m.startAddingBridge()
+
+	case messages.BridgeAddedMsg:
+		m.bridgeAdded(msg)
+		return m, nil
+
+	case messages.DevicesFetchedMsg:
+		m.devicesScreen.SetDevices(msg.Devices)
+		return m, nil
+
+	case messages.ShowSchedulesMsg:
+		m.screen = ScreenSchedules
+		m.schedulesScreen.SetRooms(m.rooms)
+		m.schedulesScreen.SetScenes(m.scenes)
+		m.schedulesScreen.SetHasLocation(m.location() != nil)
+		m.schedulesScreen.SetLoading(true)
+		return m, m.fetchSchedulesCmd()
+
+	case messages.HideSchedulesMsg:
+		m.screen = ScreenMain
+		return m, nil
+
+	case messages.SchedulesFetchedMsg:
+		now := time.Now()
+		schedules := append(msg.Schedules, m.localSchedules(now)...)
+		m.schedulesScreen.SetSchedules(schedules)
+		m.schedulesScreen.SetUpcoming(upcomingRuns(schedules, m.location(), now))
+		return m, nil
+
+	case messages.ScheduleCreateMsg:
+		if msg.Spec.Local() {
+			if err := m.addLocalSchedule(msg.Spec); err != nil {
+				return m, func() tea.Msg { return messages.ErrorMsg{Err: err} }
+			}
+			return m, m.fetchSchedulesCmd()
+		}
+		return m, m.createScheduleCmd(msg.Spec)
+
+	case messages.ScheduleDeleteMsg:
+		if msg.Resource == models.ScheduleLocal {
+			if err := m.deleteLocalSchedule(msg.ID); err != nil {
+				return m, func() tea.Msg { return messages.ErrorMsg{Err: err} }
+			}
+			return m, m.fetchSchedulesCmd()
+		}
+		return m, m.deleteScheduleCmd(msg.Resource, msg.ID)
+
+	case messages.LocalScheduleTickMsg:
+		return m, m.handleLocalScheduleTick(msg.Time)
+
+	case messages.LocalScheduleRanMsg:
+		if msg.Err != nil {
+			return m, func() tea.Msg { return messages.ErrorMsg{Err: msg.Err} }
 		}
+		m.mainScreen.SetNotice("Ran schedule " + msg.Name)
+		return m, func() tea.Msg { return messages.RefreshMsg{} }
+
+	case messages.SchedulesChangedMsg:
+		cmds = append(cmds, m.listenForEvents())
+		if m.screen == ScreenSchedules {
+			cmds = append(cmds, m.fetchSchedulesCmd())
+		}
+		if msg.Resource == "smart_scene" && m.bridge != nil {
+			cmds = append(cmds, m.fetchScenesCmd())
+		}
+		return m, tea.Batch(cmds...)
+
+	case messages.ShowRoomsMsg:
+		m.screen = ScreenRooms
+		m.roomsScreen.SetLights(m.rooms)
+		m.roomsScreen.SetLoading(true)
+		return m, m.fetchGroupsCmd()
+
+	case messages.HideRoomsMsg:
+		m.screen = ScreenMain
+		// Rooms changed on the screen show up in the main view
+		if m.roomsChanged {
+			m.roomsChanged = false
+			return m, func() tea.Msg { return messages.RefreshMsg{} }
+		}
+		return m, nil
+
+	case messages.RoomsFetchedMsg:
+		m.roomsScreen.SetGroups(msg.Rooms, msg.Zones)
+		m.mainScreen.SetZones(msg.Zones)
+		return m, nil
+
+	case messages.ZonesRequestMsg:
+		return m, m.fetchGroupsCmd()
+
+	case messages.RoomSaveMsg:
+		m.roomsChanged = true
+		return m, m.saveGroupCmd(msg)
+
+	case messages.RoomDeleteMsg:
+		m.roomsChanged = true
+		return m, m.deleteGroupCmd(msg)
 
 	case messages.RefreshMsg:
 		m.mainScreen.SetLoading(true)
-		cmds = append(cmds, m.mainScreen.Init(), m.fetchDataCmd())
+		cmds = append(cmds, m.mainScreen.Init(), m.connectCmd())
 
 	case messages.LightUpdateMsg:
 		// Handle real-time light updates from WebSocket
 		debugf("Handling LightUpdateMsg: id=%s on=%v brightness=%v colorTemp=%v",
 			msg.LightID, msg.On, msg.Brightness, msg.ColorTemp)
 
+		// Calibrated lights report the temperature they were sent
+		if offset := m.colorTempOffsets()[msg.LightID]; offset != 0 && msg.ColorTemp != nil && *msg.ColorTemp >= 153 {
+			mirek := calibrate(*msg.ColorTemp, -offset)
+			msg.ColorTemp = &mirek
+		}
+
 		light := m.findLightByID(msg.LightID)
 		if light == nil {
 			debugf("  Light not found: %s", msg.LightID)
@@ -279,6 +775,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.On != nil {
 			if !m.pending.MatchesAndClear(msg.LightID, "on", *msg.On) {
 				debugf("  Applying on=%v (no pending match)", *msg.On)
+				if *msg.On && !light.On {
+					m.fireLightOn(light)
+				}
 				light.On = *msg.On
 				updated = true
 			} else {
@@ -349,6 +848,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if len(msg.Gradient) > 0 && light.Gradient != nil {
+			light.Gradient.Points = nil
+			for _, p := range msg.Gradient {
+				light.Gradient.Points = append(light.Gradient.Points, models.NewColorFromXY(p[0], p[1], 254))
+			}
+			updated = true
+		}
+
 		debugf("  Updated=%v", updated)
 
 		if updated {
@@ -361,8 +868,161 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+			// Mirror the change to linked lights
+			cmds = append(cmds, m.mainScreen.SyncLinks(m.commandBridge(), m.addPending, m.pending.AddCompound))
+		}
+
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.GroupedLightUpdateMsg:
+		m.handleGroupedLightUpdate(msg)
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.MotionMsg:
+		m.fireMotion(msg.SensorID)
+		if m.watchedSensor(msg.SensorID) {
+			cmds = append(cmds, m.alert("Motion on sensor "+msg.SensorID))
+		}
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.FlashEndMsg:
+		m.endFlash(msg.ID)
+		return m, nil
+
+	case messages.HookFailedMsg:
+		m.notify(components.ToastWarning, msg.Err.Error())
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.ConnectivityUpdateMsg:
+		m.handleConnectivityUpdate(msg)
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.LightLinksChangedMsg:
+		if err := m.saveLightLinks(msg.Links); err != nil {
+			m.reportError(err)
+		}
+
+	case messages.RoomMutedMsg:
+		if err := m.setRoomMuted(msg.RoomID, msg.Muted); err != nil {
+			m.reportError(err)
+		}
+		// Catch up on the updates missed while muted
+		if !msg.Muted {
+			cmds = append(cmds, m.fetchDataCmd())
+		}
+
+	case messages.SceneSortMsg:
+		if err := m.setSceneSort(msg.Sort); err != nil {
+			m.reportError(err)
+		}
+
+	case messages.SceneShortcutMsg:
+		if err := m.setSceneShortcut(msg.RoomID, msg.Key, msg.SceneID); err != nil {
+			m.reportError(err)
+		}
+
+	case messages.SceneTransitionMsg:
+		if err := m.setSceneTransition(msg); err != nil {
+			m.reportError(err)
+		}
+
+	case messages.CalibrationStepMsg:
+		if m.bridge != nil {
+			cmds = append(cmds, m.calibrationCmd(msg))
+		}
+
+	case messages.ColorTempOffsetMsg:
+		if err := m.saveColorTempOffset(msg.LightID, msg.Offset); err != nil {
+			m.reportError(err)
+		}
+		m.mainScreen.SetColorTempOffsets(m.colorTempOffsets())
+
+	case messages.LightMemoryMsg:
+		if err := m.saveLightMemory(msg.Lights); err != nil {
+			m.reportError(err)
+		}
+		m.mainScreen.SetLightMemory(m.lightMemory())
+
+	case messages.ConnectionStatusMsg:
+		cmds = append(cmds, m.handleConnectionStatus(msg.Status), m.listenForEvents())
+
+	case messages.ThrottleMsg:
+		m.mainScreen.SetThrottled(msg.Queued)
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.ReconcileTickMsg:
+		if m.events != nil && !m.offline {
+			cmds = append(cmds, m.reconcile())
+		}
+		cmds = append(cmds, reconcileTick())
+
+	case messages.LightReconciledMsg:
+		m.applyReconciled(msg)
+
+	case messages.ConnectionTickMsg:
+		cmds = append(cmds, m.handleConnectionTick())
+
+	case messages.StreamStatusMsg:
+		cmds = append(cmds, m.handleStreamStatus(msg.Connected), m.listenForEvents())
+
+	case messages.RemoteTokenMsg:
+		m.saveRemoteToken()
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.PollTickMsg:
+		cmds = append(cmds, m.handlePollTick())
+
+	case tea.BlurMsg:
+		cmds = append(cmds, m.handleBlur())
+
+	case messages.IdleCheckMsg:
+		cmds = append(cmds, m.handleIdleCheck())
+
+	case messages.LightCommandsResultMsg:
+		// Commands dropped while offline aren't the lights' fault, and the
+		// refetch on reconnect restores the real state
+		if !errors.Is(msg.Err, api.ErrBridgeUnreachable) {
+			if cmd := m.handleLightCommandsResult(msg); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			if len(msg.Failed) > 0 {
+				cmds = append(cmds, m.alert("Command failed"))
+			}
+		}
+		if msg.Err != nil {
+			err := msg.Err
+			cmds = append(cmds, func() tea.Msg { return messages.ErrorMsg{Err: err} })
 		}
 
+	case messages.RoomUpdateMsg:
+		if cmd := m.handleRoomUpdate(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.SceneUpdateMsg:
+		m.handleSceneUpdate(msg)
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.LightAddedMsg:
+		if cmd := m.handleLightAdded(msg.Light); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.DeviceNameFetchedMsg:
+		m.applyDeviceName(msg)
+
+	case messages.RoomAddedMsg:
+		m.handleRoomAdded(msg.Room)
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.SceneAddedMsg:
+		m.handleSceneAdded(msg.Scene)
+		cmds = append(cmds, m.listenForEvents())
+
+	case messages.ResourceDeletedMsg:
+		m.handleResourceDeleted(msg)
 		cmds = append(cmds, m.listenForEvents())
 	}
 
@@ -375,15 +1035,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ScreenMain:
 		var cmd tea.Cmd
-		m.mainScreen, cmd = m.mainScreen.Update(msg, m.bridge, func(lightID, field string, value interface{}, dir screens.Direction) {
-			m.pending.AddWithDirection(lightID, field, value, Direction(dir))
-		})
+		m.mainScreen, cmd = m.mainScreen.Update(msg, m.commandBridge(), m.addPending, m.pending.AddCompound)
 		cmds = append(cmds, cmd)
 
 	case ScreenScenes:
 		var cmd tea.Cmd
 		m.scenesScreen, cmd = m.scenesScreen.Update(msg)
 		cmds = append(cmds, cmd)
+
+	case ScreenEntertainment:
+		var cmd tea.Cmd
+		m.entertainmentScreen, cmd = m.entertainmentScreen.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ScreenSchedules:
+		var cmd tea.Cmd
+		m.schedulesScreen, cmd = m.schedulesScreen.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ScreenDevices:
+		var cmd tea.Cmd
+		m.devicesScreen, cmd = m.devicesScreen.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ScreenNotifications:
+		var cmd tea.Cmd
+		m.notificationsScreen, cmd = m.notificationsScreen.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ScreenSceneCleanup:
+		var cmd tea.Cmd
+		m.sceneCleanupScreen, cmd = m.sceneCleanupScreen.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ScreenBridges:
+		var cmd tea.Cmd
+		m.bridgesScreen, cmd = m.bridgesScreen.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ScreenRooms:
+		var cmd tea.Cmd
+		m.roomsScreen, cmd = m.roomsScreen.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -391,6 +1084,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the current screen
 func (m Model) View() string {
+	if m.tooSmall() {
+		return m.renderTooSmall()
+	}
+
 	var view string
 	switch m.screen {
 	case ScreenSetup:
@@ -399,57 +1096,315 @@ func (m Model) View() string {
 		view = m.mainScreen.View()
 	case ScreenScenes:
 		view = m.scenesScreen.View()
+	case ScreenEntertainment:
+		view = m.entertainmentScreen.View()
+	case ScreenSchedules:
+		view = m.schedulesScreen.View()
+	case ScreenRooms:
+		view = m.roomsScreen.View()
+	case ScreenDevices:
+		view = m.devicesScreen.View()
+	case ScreenNotifications:
+		view = m.notificationsScreen.View()
+	case ScreenSceneCleanup:
+		view = m.sceneCleanupScreen.View()
+	case ScreenBridges:
+		view = m.bridgesScreen.View()
 	default:
 		view = "Unknown screen"
 	}
 
-	// Append error message if there's an error
-	if m.err != nil {
-		view += "\n\n  ⚠ Error: " + m.err.Error()
+	// A changed certificate gets a dedicated alert instead of the raw error
+	if m.certAlert != nil {
+		return view + "\n\n" + m.certAlert.View()
 	}
 
-	return view
+	if m.err != nil && m.screen != ScreenSetup {
+		view += "\n\n" + components.RenderErrorPanel(m.width, m.errorPanel())
+	}
+
+	return m.renderToasts(view)
 }
 
 // fetchDataCmd creates a command to fetch all data from the bridge
 func (m Model) fetchDataCmd() tea.Cmd {
 	debugf("fetchDataCmd called, bridge=%v, demoMode=%v", m.bridge != nil, m.demoMode)
 	// Capture bridge reference directly to avoid closure issues
+	bridge := m.bridge
+	ctx := m.ctx
+	if bridge == nil {
+		debugf("fetchDataCmd: bridge is nil!")
+		return func() tea.Msg { return messages.ErrorMsg{Err: config.ErrNoBridges} }
+	}
+
+	// Progress messages are delivered one at a time, each carrying the
+	// command that waits for the next one. The fetch starts with the first.
+	results := make(chan tea.Msg, len(api.FetchResources)+1)
+	var start sync.Once
+	fetch := func() {
+		debugf("fetchDataCmd executing")
+		rooms, scenes, err := bridge.FetchAllProgress(ctx, func(resource string, count int) {
+			results <- messages.FetchProgressMsg{Resource: resource, Count: count}
+		})
+		debugf("fetchDataCmd: FetchAll returned %d rooms, %d scenes, err=%v", len(rooms), len(scenes), err)
+		if errors.Is(err, api.ErrScenesUnavailable) && rooms != nil {
+			results <- messages.DataFetchedMsg{Rooms: rooms, ScenesErr: err}
+			return
+		}
+		if err != nil {
+			results <- messages.ErrorMsg{Err: err}
+			return
+		}
+		results <- messages.DataFetchedMsg{Rooms: rooms, Scenes: scenes}
+	}
+
+	var next tea.Cmd
+	next = func() tea.Msg {
+		start.Do(func() { go fetch() })
+		msg := <-results
+		if progress, ok := msg.(messages.FetchProgressMsg); ok {
+			progress.Next = next
+			return progress
+		}
+		return msg
+	}
+	return next
+}
+
+// activateSceneCmd creates a command to activate a scene, over its recall
+// duration when it has one
+func (m Model) activateSceneCmd(sceneID string) tea.Cmd {
+	transitions := m.sceneTransitions()
+	return func() tea.Msg {
+		if m.bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		err := activateScene(m.ctx, m.bridge, sceneID, transitions)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+
+		return messages.RefreshMsg{}
+	}
+}
+
+// fadeSceneCmd creates a command to recall a scene over the slow fade
+// duration, whatever its own recall duration
+func (m Model) fadeSceneCmd(sceneID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	fade := m.sceneFade
+	return func() tea.Msg {
+		if err := bridge.ActivateSceneOver(ctx, sceneID, fade); err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.RefreshMsg{}
+	}
+}
+
+// stopSceneDynamicsCmd creates a command to stop a cycling scene
+func (m Model) stopSceneDynamicsCmd(sceneID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if err := bridge.StopSceneDynamics(ctx, sceneID); err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.RefreshMsg{}
+	}
+}
+
+// saveSceneCmd creates a scene from the current state of a room's lights
+func (m Model) saveSceneCmd(name, roomID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	var actions []api.SceneAction
+	for _, room := range m.rooms {
+		if room.ID == roomID {
+			actions = api.CaptureSceneActions(room.Lights)
+		}
+	}
+	return func() tea.Msg {
+		if actions == nil {
+			return messages.ErrorMsg{Err: fmt.Errorf("failed to save scene %q: room not found", name)}
+		}
+		if _, err := bridge.CreateScene(ctx, name, roomID, "room", actions); err != nil {
+			return messages.ErrorMsg{Err: fmt.Errorf("failed to save scene %q: %w", name, err)}
+		}
+		return messages.SceneSavedMsg{Name: name}
+	}
+}
+
+// fetchEntertainmentCmd creates a command to fetch the entertainment areas
+func (m Model) fetchEntertainmentCmd() tea.Cmd {
 	bridge := m.bridge
 	ctx := m.ctx
 	return func() tea.Msg {
-		debugf("fetchDataCmd executing, bridge=%v", bridge != nil)
 		if bridge == nil {
-			debugf("fetchDataCmd: bridge is nil!")
 			return messages.ErrorMsg{Err: config.ErrNoBridges}
 		}
 
-		rooms, scenes, err := bridge.FetchAll(ctx)
-		debugf("fetchDataCmd: FetchAll returned %d rooms, %d scenes, err=%v", len(rooms), len(scenes), err)
+		areas, err := bridge.GetEntertainmentAreas(ctx)
 		if err != nil {
 			return messages.ErrorMsg{Err: err}
 		}
+		return messages.EntertainmentFetchedMsg{Areas: areas}
+	}
+}
+
+// toggleEntertainmentCmd starts or stops an entertainment session and
+// refreshes the areas to show the new status
+func (m Model) toggleEntertainmentCmd(areaID string, active bool) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		if err := bridge.SetEntertainmentActive(ctx, areaID, active); err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
 
-		return messages.DataFetchedMsg{Rooms: rooms, Scenes: scenes}
+		areas, err := bridge.GetEntertainmentAreas(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.EntertainmentFetchedMsg{Areas: areas}
 	}
 }
 
-// activateSceneCmd creates a command to activate a scene
-func (m Model) activateSceneCmd(sceneID string) tea.Cmd {
+// fetchDevicesCmd creates a command to fetch the battery powered devices
+func (m Model) fetchDevicesCmd() tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
 	return func() tea.Msg {
-		if m.bridge == nil {
+		if bridge == nil {
 			return messages.ErrorMsg{Err: config.ErrNoBridges}
 		}
 
-		err := m.bridge.ActivateScene(m.ctx, sceneID)
+		devices, err := bridge.GetBatteryDevices(ctx)
 		if err != nil {
 			return messages.ErrorMsg{Err: err}
 		}
+		return messages.DevicesFetchedMsg{Devices: devices}
+	}
+}
 
-		return messages.RefreshMsg{}
+// fetchSchedulesCmd creates a command to fetch the schedules
+func (m Model) fetchSchedulesCmd() tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		schedules, err := bridge.GetSchedules(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.SchedulesFetchedMsg{Schedules: schedules}
 	}
 }
 
+// createScheduleCmd creates a schedule and refreshes the list
+func (m Model) createScheduleCmd(spec api.ScheduleSpec) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		if _, err := bridge.CreateSchedule(ctx, spec); err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+
+		schedules, err := bridge.GetSchedules(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.SchedulesFetchedMsg{Schedules: schedules}
+	}
+}
+
+// deleteScheduleCmd deletes a schedule and refreshes the list
+func (m Model) deleteScheduleCmd(resource, id string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		if err := bridge.DeleteSchedule(ctx, resource, id); err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+
+		schedules, err := bridge.GetSchedules(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.SchedulesFetchedMsg{Schedules: schedules}
+	}
+}
+
+// handleEvents forwards bridge events to the event channel
+func (m Model) handleEvents(events []api.Event) {
+	debugf("Received %d events from WebSocket", len(events))
+	for _, event := range events {
+		debugf("  Event: type=%s resource=%s id=%s", event.Type, event.Resource, event.ResourceID)
+		if m.eventFilter.ignores(event) {
+			continue
+		}
+		msg := eventToMsg(event)
+		if msg == nil {
+			continue
+		}
+		// Non-blocking send to avoid deadlock
+		select {
+		case m.eventChan <- msg:
+			debugf("  Sent %T to event channel", msg)
+		default:
+			debugf("  Channel full, dropped event")
+		}
+	}
+}
+
+// startEvents starts the event subscription and listens for its events
+func (m *Model) startEvents() tea.Cmd {
+	if err := m.events.Start(m.ctx); err != nil {
+		tuiLog.Warnf("Failed to start event subscription: %v", err)
+		m.notify(components.ToastWarning, "Live updates unavailable: "+err.Error())
+	} else {
+		debugf("Event subscription started successfully")
+	}
+	return m.listenForEvents()
+}
+
+// RecordEvents writes the raw bridge event stream to an event log
+func (m *Model) RecordEvents(recorder *api.EventRecorder) {
+	m.recorder = recorder
+}
+
+// FocusRoom selects and scrolls to the named room once the lights load,
+// instead of the start_room of the config
+func (m *Model) FocusRoom(name string) {
+	m.startRoom = name
+}
+
+// ReplayEvents replays a recorded event log instead of live bridge events
+func (m *Model) ReplayEvents(entries []api.EventLogEntry) {
+	m.replay = append([]api.EventLogEntry{}, entries...)
+}
+
+// addPending registers a pending operation started by the main screen
+func (m Model) addPending(lightID, field string, value interface{}, dir screens.Direction) {
+	m.pending.AddWithDirection(lightID, field, value, Direction(dir))
+}
+
 // listenForEvents creates a command that waits for the next event from the channel
 func (m Model) listenForEvents() tea.Cmd {
 	return func() tea.Msg {
@@ -468,3 +1423,77 @@ func (m Model) findLightByID(lightID string) *models.Light {
 	}
 	return nil
 }
+
+// updateAccent tints the header with the palette of the active scene when
+// enabled in config. The most recently activated scene wins, falling back to
+// any other active scene with a palette.
+func (m *Model) updateAccent() {
+	if !m.config.SceneAccent {
+		return
+	}
+
+	var accent lipgloss.Color
+	for _, scene := range m.scenes {
+		if !scene.IsActive() || len(scene.Palette) == 0 {
+			continue
+		}
+		if scene.ID == m.accentSceneID {
+			accent = lipgloss.Color(scene.Palette[0].HexString())
+			break
+		}
+		if accent == "" {
+			accent = lipgloss.Color(scene.Palette[0].HexString())
+		}
+	}
+	m.mainScreen.SetAccent(accent)
+}
+
+// newHueBridge creates a bridge client enforcing the configured TLS policy,
+// recording its commands to audit when not nil
+func newHueBridge(cfg *config.Config, bridgeCfg *config.BridgeConfig, audit *api.AuditLog) (*api.HueBridge, error) {
+	bridge := api.NewHueBridge(bridgeCfg.Host, bridgeCfg.Username, bridgeCfg.BridgeID)
+	policy, err := api.PolicyFor(bridgeCfg.TLSMode, bridgeCfg.CertFingerprint, cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	bridge.SetTLSPolicy(policy)
+	if bridgeCfg.IsRemote() {
+		if bridgeCfg.Remote == nil {
+			return nil, fmt.Errorf("bridge %s has a remote connection but no remote credentials", bridgeCfg.BridgeID)
+		}
+		bridge.SetRemote(api.NewRemoteAuth(bridgeCfg.Remote.ClientID, bridgeCfg.Remote.ClientSecret, api.RemoteToken{
+			AccessToken:  bridgeCfg.Remote.AccessToken,
+			RefreshToken: bridgeCfg.Remote.RefreshToken,
+			ExpiresAt:    bridgeCfg.Remote.ExpiresAt,
+		}))
+	}
+	if audit != nil {
+		bridge.SetAuditLog(audit)
+	}
+	return bridge, nil
+}
+
+// auditLog returns the audit log commands are recorded to, or nil when it
+// is disabled. It is opened once, for the clients of every bridge.
+func (m *Model) auditLog() (*api.AuditLog, error) {
+	if !m.config.AuditLog || m.audit != nil {
+		return m.audit, nil
+	}
+	f, err := config.OpenAuditLog()
+	if err != nil {
+		return nil, err
+	}
+	m.audit, m.auditFile = api.NewAuditLog(f), f
+	return m.audit, nil
+}
+
+// Close releases the files the model keeps open, once the program exits
+func (m Model) Close() error {
+	if m.auditFile == nil {
+		return nil
+	}
+	if err := m.auditFile.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	return nil
+}
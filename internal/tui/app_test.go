@@ -1,10 +1,29 @@
 package tui
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/angristan/hue-tui/internal/api"
 	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/components"
 	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/screens"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestDemoModeInit(t *testing.T) {
@@ -26,7 +45,7 @@ func TestDemoModeInit(t *testing.T) {
 
 	// Simulate the fetchDataCmd directly
 	fetchCmd := model.fetchDataCmd()
-	fetchMsg := fetchCmd()
+	fetchMsg := drainFetch(fetchCmd)
 	t.Logf("fetchDataCmd returned: %T", fetchMsg)
 
 	if dataMsg, ok := fetchMsg.(messages.DataFetchedMsg); ok {
@@ -60,22 +79,3833 @@ func TestDemoModeInit(t *testing.T) {
 	}
 }
 
+func TestResourceAddAndDelete(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	// A new light without a known device lands in "Other Lights"
+	newModel, _ := model.Update(messages.LightAddedMsg{Light: &models.Light{ID: "light-new", Name: "New Bulb"}})
+	model = newModel.(Model)
+	if model.findLightByID("light-new") == nil {
+		t.Fatal("Expected added light to be present")
+	}
+
+	// Deleting it removes the now empty "Other Lights" room
+	roomCount := len(model.rooms)
+	newModel, _ = model.Update(messages.ResourceDeletedMsg{Resource: "light", ResourceID: "light-new"})
+	model = newModel.(Model)
+	if model.findLightByID("light-new") != nil {
+		t.Error("Expected deleted light to be gone")
+	}
+	if len(model.rooms) != roomCount-1 {
+		t.Errorf("Expected empty room to be dropped, got %d rooms", len(model.rooms))
+	}
+
+	// Deleting a scene
+	sceneCount := len(model.scenes)
+	newModel, _ = model.Update(messages.ResourceDeletedMsg{Resource: "scene", ResourceID: "scene-relax"})
+	model = newModel.(Model)
+	if len(model.scenes) != sceneCount-1 {
+		t.Errorf("Expected %d scenes, got %d", sceneCount-1, len(model.scenes))
+	}
+}
+
+func TestLightRoleKeys(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	press := func(key string) {
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = newModel.(Model)
+	}
+	for i := 0; i < 20; i++ {
+		if room := model.mainScreen.SelectedRoom(); room != nil && room.Name == "Living Room" {
+			break
+		}
+		press("j")
+	}
+
+	bias := model.findLightByID("light-lr-tv-bias")
+	ceiling := model.findLightByID("light-lr-ceiling")
+	if bias == nil || ceiling == nil || !bias.On {
+		t.Fatal("Expected the demo TV bias light to be on")
+	}
+	ceilingBrightness := ceiling.BrightnessPct()
+
+	// Dim, then off, then back on
+	press("b")
+	if !bias.On || bias.BrightnessPct() > 20 {
+		t.Errorf("Expected bias light dimmed to about 20%%, got on=%v %d%%", bias.On, bias.BrightnessPct())
+	}
+	press("b")
+	if bias.On {
+		t.Error("Expected bias light off")
+	}
+	press("b")
+	if !bias.On {
+		t.Error("Expected bias light back on")
+	}
+
+	// Other roles are untouched
+	if !ceiling.On || ceiling.BrightnessPct() != ceilingBrightness {
+		t.Errorf("Expected task light untouched, got on=%v %d%%", ceiling.On, ceiling.BrightnessPct())
+	}
+}
+
+func TestSchedulesScreen(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+	fetched := func(cmd tea.Cmd) messages.SchedulesFetchedMsg {
+		t.Helper()
+		if cmd == nil {
+			t.Fatal("Expected a command")
+		}
+		msg, ok := cmd().(messages.SchedulesFetchedMsg)
+		if !ok {
+			t.Fatalf("Expected SchedulesFetchedMsg, got %T", msg)
+		}
+		return msg
+	}
+
+	msg := fetched(update(messages.ShowSchedulesMsg{}))
+	if model.screen != ScreenSchedules {
+		t.Fatalf("Expected ScreenSchedules, got %d", model.screen)
+	}
+	update(msg)
+	before := len(msg.Schedules)
+
+	// Create a schedule with the form defaults
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	cmd := update(tea.KeyMsg{Type: tea.KeyEnter})
+	createMsg, ok := cmd().(messages.ScheduleCreateMsg)
+	if !ok {
+		t.Fatal("Expected ScheduleCreateMsg")
+	}
+	if createMsg.Spec.Kind != models.ScheduleWakeUp || createMsg.Spec.Hour != 7 || createMsg.Spec.GroupID == "" {
+		t.Errorf("Unexpected schedule spec: %+v", createMsg.Spec)
+	}
+	msg = fetched(update(createMsg))
+	if len(msg.Schedules) != before+1 {
+		t.Fatalf("Expected %d schedules, got %d", before+1, len(msg.Schedules))
+	}
+	update(msg)
+	if !contains(model.View(), createMsg.Spec.Name) {
+		t.Error("Expected the new schedule in the list")
+	}
+
+	// Delete asks for confirmation first
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	cmd = update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	msg = fetched(update(cmd()))
+	if len(msg.Schedules) != before {
+		t.Errorf("Expected %d schedules after delete, got %d", before, len(msg.Schedules))
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	key := func(k string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)} }
+
+	// The first item is a room header: turn the whole room off in one step
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || !model.mainScreen.IsRoomSelected() {
+		t.Fatal("Expected a room to be selected")
+	}
+	onBefore := make(map[string]bool)
+	for _, light := range room.Lights {
+		onBefore[light.ID] = light.On
+	}
+	press(key("x"))
+	for _, light := range room.Lights {
+		if light.On {
+			t.Fatalf("Expected %s off", light.Name)
+		}
+	}
+
+	press(key("u"))
+	for _, light := range room.Lights {
+		if light.On != onBefore[light.ID] {
+			t.Errorf("Expected %s on=%v after undo, got %v", light.Name, onBefore[light.ID], light.On)
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlR})
+	for _, light := range room.Lights {
+		if light.On {
+			t.Errorf("Expected %s off after redo", light.Name)
+		}
+	}
+
+	// A new change clears the redo stack
+	press(key("u"))
+	press(key("j"))
+	light := model.mainScreen.SelectedLight()
+	if light == nil || !light.On {
+		t.Fatal("Expected the first light to be on")
+	}
+	brightness := light.Brightness
+	press(key("5"))
+	press(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if light.BrightnessPct() != 50 {
+		t.Errorf("Expected redo to be a no-op, got %d%%", light.BrightnessPct())
+	}
+	press(key("u"))
+	if light.Brightness != brightness {
+		t.Errorf("Expected brightness %d after undo, got %d", brightness, light.Brightness)
+	}
+}
+
+func TestLightLinks(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	var cmd tea.Cmd
+	press := func(k string) {
+		var newModel tea.Model
+		newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		model = newModel.(Model)
+	}
+
+	// Mark the first two lights and link them
+	press("j")
+	first := model.mainScreen.SelectedLight()
+	first.On = true
+	press("v")
+	press("j")
+	second := model.mainScreen.SelectedLight()
+	second.On = true
+	press("v")
+	press("L")
+	if cmd == nil {
+		t.Fatal("Expected L to save the links")
+	}
+	changed, ok := cmd().(messages.LightLinksChangedMsg)
+	if !ok || len(changed.Links) != 1 || len(changed.Links[0]) != 2 {
+		t.Fatalf("Expected one link group of two lights, got %+v", changed)
+	}
+	newModel, _ := model.Update(changed)
+	model = newModel.(Model)
+	if len(model.lightLinks()) != 1 {
+		t.Error("Expected the links to be kept")
+	}
+
+	// A key change on one light is mirrored to the other
+	press("3")
+	if first.Brightness != second.Brightness || first.Brightness == 0 {
+		t.Errorf("Expected the linked light at %d, got %d", second.Brightness, first.Brightness)
+	}
+
+	// So is a change reported by the bridge
+	brightness := 70
+	newModel, _ = model.Update(messages.LightUpdateMsg{LightID: first.ID, Brightness: &brightness})
+	model = newModel.(Model)
+	if second.Brightness != first.Brightness {
+		t.Errorf("Expected the linked light at %d, got %d", first.Brightness, second.Brightness)
+	}
+
+	// L on a linked light with nothing marked unlinks it
+	press("L")
+	if changed, ok := cmd().(messages.LightLinksChangedMsg); !ok || len(changed.Links) != 0 {
+		t.Errorf("Expected the link to be removed, got %+v", changed)
+	}
+}
+
+func TestOfflineBanner(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	offline := messages.ConnectionStatusMsg{Status: api.ConnectionStatus{Err: fmt.Errorf("timeout")}}
+	newModel, cmd := model.Update(offline)
+	model = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected a retry countdown to start")
+	}
+	if view := model.View(); !contains(view, "Bridge unreachable") || !contains(view, "retrying in 5s") {
+		t.Errorf("Expected the offline banner, got:\n%s", view)
+	}
+
+	// Dropped commands neither flag lights nor show an error
+	light := model.rooms[0].Lights[0]
+	newModel, _ = model.Update(messages.LightCommandsResultMsg{
+		Failed: []string{light.ID},
+		Err:    fmt.Errorf("failed to set light: %w", api.ErrBridgeUnreachable),
+	})
+	model = newModel.(Model)
+	newModel, _ = model.Update(messages.ErrorMsg{Err: api.ErrBridgeUnreachable})
+	model = newModel.(Model)
+	if light.Failures != 0 || model.err != nil {
+		t.Errorf("Expected dropped commands to be ignored, failures=%d err=%v", light.Failures, model.err)
+	}
+
+	// Coming back online clears the banner and refetches
+	newModel, cmd = model.Update(messages.ConnectionStatusMsg{Status: api.ConnectionStatus{Online: true}})
+	model = newModel.(Model)
+	if cmd == nil {
+		t.Error("Expected a refetch on reconnect")
+	}
+	if contains(model.View(), "Bridge unreachable") {
+		t.Error("Expected the offline banner to be cleared")
+	}
+}
+
+func TestPollingFallback(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{Preferences: config.Preferences{PollSeconds: 15}})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	model = newModel.(Model)
+
+	// The stream can't connect: data is polled instead
+	newModel, _ = model.Update(messages.StreamStatusMsg{Connected: false})
+	model = newModel.(Model)
+	if !model.polling || !model.pollTicking {
+		t.Fatal("Expected polling to start")
+	}
+	if view := model.View(); !contains(view, "polling every 15s") {
+		t.Errorf("Expected the header to show polling, got:\n%s", view)
+	}
+	newModel, cmd := model.Update(messages.PollTickMsg{})
+	model = newModel.(Model)
+	if cmd == nil {
+		t.Error("Expected a poll tick to refetch and tick again")
+	}
+
+	// Once the stream is back, the next tick stops polling
+	newModel, _ = model.Update(messages.StreamStatusMsg{Connected: true})
+	model = newModel.(Model)
+	if view := model.View(); !contains(view, "live") || contains(view, "polling") {
+		t.Errorf("Expected the header to show live updates, got:\n%s", view)
+	}
+	newModel, cmd = model.Update(messages.PollTickMsg{})
+	model = newModel.(Model)
+	if cmd != nil || model.pollTicking {
+		t.Error("Expected polling to stop with the stream back")
+	}
+}
+
+func TestStartRoom(t *testing.T) {
+	model := NewModel(&config.Config{Preferences: config.Preferences{StartRoom: "office"}}, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+	if room := model.mainScreen.SelectedRoom(); room == nil || room.Name != "Office" {
+		t.Fatalf("Expected the Office room to be selected, got %+v", room)
+	}
+
+	// Later refreshes keep the selection where the user moved it
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	model = newModel.(Model)
+	selected := model.mainScreen.SelectedLight()
+	newModel, _ = model.Update(dataMsg)
+	model = newModel.(Model)
+	if selected == nil || model.mainScreen.SelectedLight() == nil || model.mainScreen.SelectedLight().ID != selected.ID {
+		t.Error("Expected a refresh not to focus the start room again")
+	}
+
+	model = NewModel(&config.Config{}, true)
+	model.FocusRoom("Garage")
+	newModel, _ = model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+	if !contains(model.View(), `No room named "Garage"`) {
+		t.Error("Expected a notice for an unknown start room")
+	}
+}
+
+func TestIdlePausesPolling(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	newModel, _ := model.Update(drainFetch(model.fetchDataCmd()))
+	model = newModel.(Model)
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+	update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	update(messages.StreamStatusMsg{Connected: false})
+
+	// Focused, or unfocused for a short while, the app keeps polling
+	update(messages.IdleCheckMsg{})
+	if update(tea.BlurMsg{}) == nil || model.idle {
+		t.Fatal("Expected an idle check to be scheduled")
+	}
+	update(messages.IdleCheckMsg{})
+	if model.idle || !model.idleChecking {
+		t.Fatal("Expected the app to wait for the idle delay")
+	}
+
+	// Long unfocused without input, polling pauses
+	model.lastInput = time.Now().Add(-time.Hour)
+	update(messages.IdleCheckMsg{})
+	if !model.idle {
+		t.Fatal("Expected the app to be idle")
+	}
+	if view := model.View(); !contains(view, "polling paused while idle") {
+		t.Errorf("Expected the header to show the pause, got:\n%s", view)
+	}
+	if update(messages.PollTickMsg{}) != nil || model.pollTicking {
+		t.Error("Expected polling to stop while idle")
+	}
+
+	// The next key resumes polling right away, and is handled as usual
+	selected := model.mainScreen.SelectedLight()
+	if update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}) == nil || model.idle || !model.pollTicking {
+		t.Fatal("Expected input to resume polling")
+	}
+	if model.mainScreen.SelectedLight() == selected {
+		t.Error("Expected the key to move the selection")
+	}
+	if view := model.View(); !contains(view, "polling every 10s") {
+		t.Errorf("Expected the header to show polling again, got:\n%s", view)
+	}
+}
+
+func TestSnapshotExport(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	model := newLoadedModel(t, &config.Config{})
+
+	// The first item is a room header, so only that room is captured
+	room := model.mainScreen.SelectedRoom()
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if cmd == nil {
+		t.Fatal("Expected P to save a snapshot")
+	}
+	saved, ok := cmd().(messages.SnapshotSavedMsg)
+	if !ok || len(saved.Paths) != 2 {
+		t.Fatalf("Expected a text and an HTML file, got %+v", saved)
+	}
+
+	text, err := os.ReadFile(filepath.Join(dir, saved.Paths[0]))
+	if err != nil {
+		t.Fatalf("Failed to read the text snapshot: %v", err)
+	}
+	if !contains(string(text), room.Name) || !contains(string(text), room.Lights[0].Name) {
+		t.Errorf("Expected the room and its lights in the snapshot:\n%s", text)
+	}
+	if contains(string(text), "\x1b") || contains(string(text), model.rooms[1].Name) {
+		t.Errorf("Expected only the selected room, without escape sequences:\n%s", text)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, saved.Paths[1]))
+	if err != nil || !contains(string(html), "<pre") {
+		t.Errorf("Expected an HTML snapshot, got %v", err)
+	}
+
+	newModel, _ := model.Update(saved)
+	model = newModel.(Model)
+	if !contains(model.View(), "Saved "+saved.Paths[0]) {
+		t.Error("Expected the saved files in the status bar")
+	}
+}
+
+func TestLightCommandFailures(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	light := model.findLightByID("light-lr-floor")
+	if light == nil || light.Faulty() {
+		t.Fatal("Expected a healthy demo light")
+	}
+
+	failed := messages.LightCommandsResultMsg{Failed: []string{light.ID}, Err: fmt.Errorf("timeout")}
+	newModel, cmd := model.Update(failed)
+	model = newModel.(Model)
+	if light.Faulty() {
+		t.Error("Expected a single failure not to flag the light")
+	}
+	if cmd == nil {
+		t.Fatal("Expected the error to be reported")
+	}
+
+	newModel, _ = model.Update(failed)
+	model = newModel.(Model)
+	if !light.Faulty() {
+		t.Error("Expected repeated failures to flag the light")
+	}
+
+	newModel, _ = model.Update(messages.LightCommandsResultMsg{Succeeded: []string{light.ID}})
+	model = newModel.(Model)
+	if light.Faulty() {
+		t.Error("Expected a successful command to clear the flag")
+	}
+
+	// Unreachable devices are flagged through connectivity events
+	light.DeviceID = "device-floor"
+	newModel, _ = model.Update(messages.ConnectivityUpdateMsg{DeviceID: light.DeviceID, Reachable: false})
+	model = newModel.(Model)
+	if !light.Faulty() {
+		t.Error("Expected the unreachable light to be flagged")
+	}
+
+	// The side panel says why the light is greyed out
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+	selectLight(t, &model, light.ID)
+	if !contains(model.View(), "⚠ Unreachable") {
+		t.Error("Expected the unreachable light to be flagged in the side panel")
+	}
+
+	newModel, _ = model.Update(messages.ConnectivityUpdateMsg{DeviceID: light.DeviceID, Reachable: true})
+	model = newModel.(Model)
+	if light.Faulty() || contains(model.View(), "⚠ Unreachable") {
+		t.Error("Expected the light to recover when its device reconnects")
+	}
+}
+
+func TestGradientLight(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	light := model.findLightByID("light-lr-tv-bias")
+	if light == nil || light.Gradient == nil {
+		t.Fatal("Expected the demo gradient light")
+	}
+	selectLight(t, &model, light.ID)
+	if !contains(model.View(), "3/5 points") {
+		t.Error("Expected the gradient in the side panel")
+	}
+
+	// Bridge events replace the gradient points
+	newModel, _ = model.Update(messages.LightUpdateMsg{
+		LightID:  light.ID,
+		Gradient: [][2]float64{{0.64, 0.33}, {0.15, 0.06}},
+	})
+	model = newModel.(Model)
+	if len(light.Gradient.Points) != 2 || light.Gradient.Points[0].X != 0.64 {
+		t.Errorf("Expected the gradient to be updated, got %+v", light.Gradient.Points)
+	}
+	if !contains(model.View(), "2/5 points") {
+		t.Error("Expected the side panel to show the new gradient")
+	}
+}
+
+func TestSaveSceneFromModal(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil {
+		t.Fatal("Expected a selected room")
+	}
+	newModel, _ = model.Update(messages.ShowScenesMsg{RoomID: room.ID})
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Dinner")})
+	model = newModel.(Model)
+	if !contains(model.View(), "Save as: Dinner") {
+		t.Error("Expected the scene name input")
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to save the scene")
+	}
+	save, ok := cmd().(messages.SaveSceneMsg)
+	if !ok || save.Name != "Dinner" || save.RoomID != room.ID {
+		t.Fatalf("Unexpected save message %+v", save)
+	}
+	newModel, cmd = model.Update(save)
+	model = newModel.(Model)
+	if model.screen != ScreenMain {
+		t.Error("Expected saving to close the modal")
+	}
+	var saved *messages.SceneSavedMsg
+	var run func(msg tea.Msg)
+	run = func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				if c != nil {
+					run(c())
+				}
+			}
+		case messages.SceneSavedMsg:
+			saved = &msg
+		}
+	}
+	run(cmd())
+	if saved == nil {
+		t.Fatal("Expected the scene to be saved")
+	}
+
+	// The refetch brings in the new scene
+	newModel, _ = model.Update(*saved)
+	model = newModel.(Model)
+	if !contains(model.View(), "Saved scene Dinner") {
+		t.Error("Expected a notice for the saved scene")
+	}
+	dataMsg = drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	found := false
+	for _, scene := range dataMsg.Scenes {
+		if scene.Name == "Dinner" && scene.RoomID == room.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the saved scene on the bridge")
+	}
+}
+
+// scenelessBridge is a demo bridge whose scenes fail to load
+type scenelessBridge struct {
+	*api.DemoBridge
+	failing bool
+}
+
+func (b *scenelessBridge) FetchAllProgress(ctx context.Context, progress api.FetchProgress) ([]*models.Room, []*models.Scene, error) {
+	rooms, scenes, err := b.DemoBridge.FetchAllProgress(ctx, progress)
+	if b.failing {
+		return rooms, nil, fmt.Errorf("%w: timeout", api.ErrScenesUnavailable)
+	}
+	return rooms, scenes, err
+}
+
+func (b *scenelessBridge) GetScenes(ctx context.Context) ([]*models.Scene, error) {
+	if b.failing {
+		return nil, errors.New("timeout")
+	}
+	return b.DemoBridge.GetScenes(ctx)
+}
+
+func TestScenesUnavailable(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	bridge := &scenelessBridge{DemoBridge: api.NewDemoBridge(), failing: true}
+	model.bridge = bridge
+
+	msg := drainFetch(model.fetchDataCmd())
+	dataMsg, ok := msg.(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatalf("Expected rooms to load without scenes, got %T", msg)
+	}
+	if dataMsg.ScenesErr == nil || len(dataMsg.Rooms) == 0 {
+		t.Fatal("Expected rooms with a scenes error")
+	}
+	newModel, cmd := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	model = newModel.(Model)
+	if model.err != nil || cmd == nil {
+		t.Fatal("Expected scenes to be retried without an error")
+	}
+	if !contains(model.View(), "scenes unavailable — retry") {
+		t.Error("Expected the scenes key to show scenes are unavailable")
+	}
+	if model.scenesBackoff != scenesRetryMin {
+		t.Errorf("Expected a %v backoff, got %v", scenesRetryMin, model.scenesBackoff)
+	}
+
+	// The scenes key retries right away instead of opening an empty modal
+	newModel, cmd = model.Update(messages.ShowScenesMsg{})
+	model = newModel.(Model)
+	if model.screen != ScreenMain || cmd == nil {
+		t.Fatal("Expected the scenes key to retry")
+	}
+	fetched := cmd().(messages.ScenesFetchedMsg)
+	newModel, _ = model.Update(fetched)
+	model = newModel.(Model)
+	if model.scenesBackoff != 2*scenesRetryMin {
+		t.Errorf("Expected the backoff to double, got %v", model.scenesBackoff)
+	}
+
+	// Retries superseded by a newer one are dropped
+	if _, cmd := model.Update(messages.RetryScenesMsg{Attempt: model.scenesAttempt - 1}); cmd != nil {
+		t.Error("Expected a stale retry to be ignored")
+	}
+
+	bridge.failing = false
+	_, cmd = model.Update(messages.RetryScenesMsg{Attempt: model.scenesAttempt})
+	if cmd == nil {
+		t.Fatal("Expected the retry to fetch scenes")
+	}
+	newModel, _ = model.Update(cmd())
+	model = newModel.(Model)
+	if model.scenesErr != nil || len(model.scenes) == 0 || model.scenesBackoff != 0 {
+		t.Fatal("Expected scenes to load once the bridge recovers")
+	}
+	if model.scenes[0].RoomName == "" {
+		t.Error("Expected scenes to be named after their room")
+	}
+	if contains(model.View(), "scenes unavailable") {
+		t.Error("Expected the scenes key to be back to normal")
+	}
+}
+
+func TestLocalSunSchedules(t *testing.T) {
+	cfg := &config.Config{Preferences: config.Preferences{Location: &config.Location{Latitude: 48.8566, Longitude: 2.3522}}}
+	model := newLoadedModel(t, cfg)
+
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+	keys := func(s string) {
+		for _, r := range s {
+			update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	update(update(messages.ShowSchedulesMsg{})())
+
+	// Turn on a room 15 minutes before sunset
+	keys("n")
+	update(tea.KeyMsg{Type: tea.KeyLeft})
+	update(tea.KeyMsg{Type: tea.KeyDown})
+	update(tea.KeyMsg{Type: tea.KeyDown})
+	for i := 0; i < 5; i++ {
+		update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	keys("sunset-15m")
+	createMsg, ok := update(tea.KeyMsg{Type: tea.KeyEnter})().(messages.ScheduleCreateMsg)
+	if !ok {
+		t.Fatal("Expected ScheduleCreateMsg")
+	}
+	spec := createMsg.Spec
+	if spec.Kind != models.ScheduleTurnOn || spec.Sun != models.Sunset || spec.SunOffset != -15*time.Minute || !spec.Local() {
+		t.Fatalf("Unexpected schedule spec: %+v", spec)
+	}
+
+	update(update(createMsg)())
+	local := model.localSchedules(time.Now())
+	if len(local) != 1 {
+		t.Fatalf("Expected 1 local schedule, got %d", len(local))
+	}
+	if !contains(model.View(), "sunset-15m") {
+		t.Error("Expected the sunset time in the list")
+	}
+
+	// Due once, at sunset minus 15 minutes
+	at, ok := scheduleTime(local[0], cfg.Location, time.Now())
+	if !ok {
+		t.Fatal("Expected a sunset time in Paris")
+	}
+	if due := dueSchedules(local, cfg.Location, at.Add(-time.Minute), at); len(due) != 1 {
+		t.Errorf("Expected the schedule to be due at %s", at.Format("15:04"))
+	}
+	if due := dueSchedules(local, cfg.Location, at, at.Add(time.Minute)); len(due) != 0 {
+		t.Error("Expected the schedule to run only once")
+	}
+
+	ran, ok := model.runLocalScheduleCmd(local[0])().(messages.LocalScheduleRanMsg)
+	if !ok || ran.Err != nil {
+		t.Fatalf("Expected the schedule to run, got %+v", ran)
+	}
+	update(messages.HideSchedulesMsg{})
+	update(ran)
+	if !contains(model.View(), "Ran schedule") {
+		t.Error("Expected a notice after running the schedule")
+	}
+
+	// Local schedules are listed last and deleted like bridge ones
+	update(update(messages.ShowSchedulesMsg{})())
+	for i := 0; i < 10; i++ {
+		keys("j")
+	}
+	keys("d")
+	update(update(update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})())())
+	if len(model.localSchedules(time.Now())) != 0 {
+		t.Error("Expected the local schedule to be deleted")
+	}
+}
+
+func TestCronSceneSchedule(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+
+	model.demoSchedules = []config.LocalSchedule{{
+		ID:      "local-1",
+		Name:    "Wind down",
+		Kind:    string(models.ScheduleActivateScene),
+		Cron:    "0 22 * * *",
+		GroupID: "room-living",
+		SceneID: "scene-relax",
+	}}
+	now := time.Now()
+	local := model.localSchedules(now)
+	if len(local) != 1 || local[0].Cron != "0 22 * * *" {
+		t.Fatalf("Expected the cron schedule, got %+v", local)
+	}
+
+	// Due once, at 22:00
+	y, mo, d := now.Date()
+	at := time.Date(y, mo, d, 22, 0, 0, 0, now.Location())
+	if due := dueSchedules(local, nil, at.Add(-time.Minute), at); len(due) != 1 {
+		t.Error("Expected the schedule to be due at 22:00")
+	}
+	if due := dueSchedules(local, nil, at, at.Add(time.Minute)); len(due) != 0 {
+		t.Error("Expected the schedule to run only once")
+	}
+	ran, ok := model.runLocalScheduleCmd(local[0])().(messages.LocalScheduleRanMsg)
+	if !ok || ran.Err != nil {
+		t.Fatalf("Expected the scene to be activated, got %+v", ran)
+	}
+
+	update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	update(update(messages.ShowSchedulesMsg{})())
+	view := model.View()
+	if !contains(view, "Relax") || !contains(view, "cron 0 22 * * *") {
+		t.Error("Expected the scene and cron expression in the list")
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	view = model.View()
+	if !contains(view, "Upcoming Runs") || !contains(view, "Wind down") {
+		t.Error("Expected the next run in the upcoming list")
+	}
+}
+
+func TestIdentifyKey(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model = newModel.(Model)
+	light := model.mainScreen.SelectedLight()
+	if light == nil {
+		t.Fatal("Expected a light to be selected")
+	}
+	on, brightness := light.On, light.Brightness
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if cmd == nil {
+		t.Fatal("Expected an identify command")
+	}
+	// The result may come batched with other commands
+	var result *messages.LightCommandsResultMsg
+	var run func(msg tea.Msg)
+	run = func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				if c != nil {
+					run(c())
+				}
+			}
+		case messages.LightCommandsResultMsg:
+			result = &msg
+		}
+	}
+	run(cmd())
+	if result == nil || len(result.Succeeded) != 1 || result.Succeeded[0] != light.ID {
+		t.Fatalf("Expected identify to succeed for %s, got %+v", light.ID, result)
+	}
+	if light.On != on || light.Brightness != brightness {
+		t.Error("Expected identify to leave the light state unchanged")
+	}
+}
+
+// failingBridge is a demo bridge whose brightness writes fail
+type failingBridge struct {
+	*api.DemoBridge
+}
+
+func (b failingBridge) SetLightBrightness(ctx context.Context, lightID string, brightness int) error {
+	return fmt.Errorf("bridge returned status 429")
+}
+
+func (b failingBridge) SetGroupedLightOn(ctx context.Context, groupedLightID string, on bool) error {
+	return fmt.Errorf("bridge returned status 429")
+}
+
+func TestFailedCommandRollback(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	model.bridge = failingBridge{DemoBridge: api.NewDemoBridge()}
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model = newModel.(Model)
+	light := model.mainScreen.SelectedLight()
+	if light == nil {
+		t.Fatal("Expected a light to be selected")
+	}
+	light.On = true
+	light.SetBrightnessPct(50)
+
+	var result *messages.LightCommandsResultMsg
+	var run func(msg tea.Msg)
+	run = func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				if c != nil {
+					run(c())
+				}
+			}
+		case messages.LightCommandsResultMsg:
+			result = &msg
+		}
+	}
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	model = newModel.(Model)
+	if light.BrightnessPct() >= 50 {
+		t.Fatalf("Expected the light to be dimmed optimistically, got %d%%", light.BrightnessPct())
+	}
+	run(cmd())
+	if result == nil || len(result.Rollbacks) != 1 {
+		t.Fatalf("Expected a rollback of the failed light, got %+v", result)
+	}
+	if !contains(result.Err.Error(), "failed to set the brightness of "+light.Name) {
+		t.Errorf("Expected the error to name the operation, got %q", result.Err)
+	}
+
+	newModel, _ = model.Update(*result)
+	model = newModel.(Model)
+	if light.BrightnessPct() != 50 {
+		t.Errorf("Expected the light to be rolled back to 50%%, got %d%%", light.BrightnessPct())
+	}
+	if model.pending.HasPending(light.ID, "brightness") {
+		t.Error("Expected the failed update to stop being pending")
+	}
+	if !contains(model.View(), "Reverted 1 light") {
+		t.Error("Expected a notice of the rollback")
+	}
+
+	// A newer change in between is fetched rather than overwritten
+	newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	model = newModel.(Model)
+	result = nil
+	run(cmd())
+	light.SetBrightnessPct(20)
+	newModel, cmd = model.Update(*result)
+	model = newModel.(Model)
+	if light.BrightnessPct() != 20 {
+		t.Errorf("Expected the newer change to be kept, got %d%%", light.BrightnessPct())
+	}
+	if !model.unconfirmed[light.ID] || cmd == nil {
+		t.Error("Expected the light to be fetched")
+	}
+
+	// Room commands roll back every light of the room
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	model = newModel.(Model)
+	room := model.mainScreen.SelectedRoom()
+	if !model.mainScreen.IsRoomSelected() || room == nil {
+		t.Fatal("Expected the room to be selected")
+	}
+	for _, l := range room.Lights {
+		l.On = true
+		l.SetBrightnessPct(50)
+	}
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyLeft}, {Type: tea.KeyRunes, Runes: []rune("x")}} {
+		newModel, cmd = model.Update(key)
+		model = newModel.(Model)
+		result = nil
+		run(cmd())
+		if result == nil || len(result.Rollbacks) != len(room.Lights) {
+			t.Fatalf("%s: expected a rollback of every light of the room, got %+v", key, result)
+		}
+		newModel, _ = model.Update(*result)
+		model = newModel.(Model)
+		for _, l := range room.Lights {
+			if !l.On || l.BrightnessPct() != 50 {
+				t.Errorf("%s: expected %s rolled back to on at 50%%, got %v at %d%%", key, l.Name, l.On, l.BrightnessPct())
+			}
+		}
+	}
+}
+
+func TestDimmingAcceleration(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{Preferences: config.Preferences{Acceleration: "fast"}})
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	model.mainScreen.SetClock(func() time.Time { return now })
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model = newModel.(Model)
+	light := model.mainScreen.SelectedLight()
+	if light == nil {
+		t.Fatal("Expected a light to be selected")
+	}
+	light.On = true
+	light.SetBrightnessPct(100)
+
+	// Auto-repeats grow the step: 10, 10, 20, 20, 30
+	for i := 0; i < 5; i++ {
+		now = now.Add(50 * time.Millisecond)
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+		model = newModel.(Model)
+	}
+	if light.BrightnessPct() != 10 {
+		t.Errorf("Expected held dimming to accelerate to 10%%, got %d%%", light.BrightnessPct())
+	}
+
+	// A separate press starts over
+	light.SetBrightnessPct(100)
+	now = now.Add(200 * time.Millisecond)
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	model = newModel.(Model)
+	if light.BrightnessPct() != 90 {
+		t.Errorf("Expected a single press to dim by 10%%, got %d%%", light.BrightnessPct())
+	}
+}
+
+func TestExactColorInput(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 140, Height: 60})
+	model = newModel.(Model)
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	// clear empties the focused field and types a new value
+	clear := func(value string) {
+		for i := 0; i < 5; i++ {
+			press(tea.KeyMsg{Type: tea.KeyBackspace})
+		}
+		press(runes(value))
+	}
+
+	light := model.findLightByID("light-lr-floor")
+	selectLight(t, &model, light.ID)
+
+	// Prefilled with the current warm white
+	press(runes("C"))
+	if !contains(model.View(), "2500") {
+		t.Error("Expected the temperature in kelvin to be prefilled")
+	}
+
+	// Hue and saturation, skipping brightness
+	clear("120")
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	clear("50")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if light.Color.Mode != models.ColorModeHS {
+		t.Fatalf("Expected the light in hue/saturation mode, got %v", light.Color.Mode)
+	}
+	if light.Color.Hue != 21845 || light.Color.Saturation != 127 {
+		t.Errorf("Expected 120° at 50%%, got hue %d sat %d", light.Color.Hue, light.Color.Saturation)
+	}
+	if light.BrightnessPct() != 60 {
+		t.Errorf("Expected the brightness untouched, got %d%%", light.BrightnessPct())
+	}
+
+	// Kelvin and brightness together
+	press(runes("C"))
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	clear("4000K")
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	clear("25")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if light.Color.Mode != models.ColorModeColorTemp || light.Color.Mirek != 250 || light.BrightnessPct() != 25 {
+		t.Errorf("Expected 4000K at 25%%, got mode %v mirek %d at %d%%", light.Color.Mode, light.Color.Mirek, light.BrightnessPct())
+	}
+
+	// Out of range values keep the input open
+	press(runes("C"))
+	clear("400")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !contains(model.View(), "hue goes from 0 to 360°") {
+		t.Error("Expected an error for an out of range hue")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if light.Color.Mode != models.ColorModeColorTemp {
+		t.Error("Expected esc to leave the light alone")
+	}
+
+	// The change is a single undo step
+	press(runes("u"))
+	if light.Color.Mode != models.ColorModeHS || light.BrightnessPct() != 60 {
+		t.Errorf("Expected undo to restore the color, got mode %v at %d%%", light.Color.Mode, light.BrightnessPct())
+	}
+}
+
+// recordingBridge is a demo bridge remembering the color temperatures sent
+type recordingBridge struct {
+	*api.DemoBridge
+	mu    sync.Mutex
+	mirek map[string]int
+}
+
+func (b *recordingBridge) SetLightColorTemp(ctx context.Context, lightID string, mirek int) error {
+	b.mu.Lock()
+	b.mirek[lightID] = mirek
+	b.mu.Unlock()
+	return b.DemoBridge.SetLightColorTemp(ctx, lightID, mirek)
+}
+
+func TestColorTempCalibration(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	bridge := &recordingBridge{DemoBridge: api.NewDemoBridge(), mirek: make(map[string]int)}
+	model.bridge = bridge
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+
+	// send updates the model and runs the commands it returns, feeding
+	// calibration messages back. Commands still blocking after a while,
+	// like ticks and event listeners, are left behind.
+	var send func(msg tea.Msg)
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+		select {
+		case msg := <-done:
+			switch msg := msg.(type) {
+			case tea.BatchMsg:
+				for _, c := range msg {
+					run(c)
+				}
+			case messages.CalibrationStepMsg, messages.ColorTempOffsetMsg:
+				send(msg)
+			}
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	send = func(msg tea.Msg) {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		run(cmd)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	// Calibration needs two marked lights
+	send(runes("K"))
+	if !contains(model.View(), "Mark two white ambiance lights") {
+		t.Error("Expected a hint without marked lights")
+	}
+
+	selectLight(t, &model, "light-lr-ceiling")
+	send(runes("v"))
+	selectLight(t, &model, "light-lr-floor")
+	send(runes("v"))
+	send(runes("K"))
+	if bridge.mirek["light-lr-ceiling"] != 326 || bridge.mirek["light-lr-floor"] != 326 {
+		t.Fatalf("Expected both lights at the reference 326 mirek, got %v", bridge.mirek)
+	}
+	send(runes("w"))
+	send(runes("w"))
+	if bridge.mirek["light-lr-floor"] != 336 || bridge.mirek["light-lr-ceiling"] != 326 {
+		t.Errorf("Expected only the second light to step warmer, got %v", bridge.mirek)
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.colorTempOffsets()["light-lr-floor"] != 10 {
+		t.Fatalf("Expected a saved +10 offset, got %v", model.colorTempOffsets())
+	}
+
+	// Temperature commands carry the offset, bridge reports have it removed
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	light := selectLight(t, &model, "light-lr-floor")
+	want := int(light.Color.Mirek) + 25
+	send(runes("w"))
+	if bridge.mirek["light-lr-floor"] != want+10 {
+		t.Errorf("Expected %d mirek to be sent as %d, got %d", want, want+10, bridge.mirek["light-lr-floor"])
+	}
+	reported := 410
+	send(messages.LightUpdateMsg{LightID: light.ID, ColorTemp: &reported})
+	if light.Color.Mirek != 400 {
+		t.Errorf("Expected the reported temperature without the offset, got %d", light.Color.Mirek)
+	}
+}
+
+func TestManageRooms(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	// send updates the model and returns the message of the command, which
+	// the screen commands produce right away
+	send := func(msg tea.Msg) tea.Msg {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		if cmd == nil {
+			return nil
+		}
+		return cmd()
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	show, ok := send(runes("R")).(messages.ShowRoomsMsg)
+	if !ok {
+		t.Fatal("Expected R to open the rooms screen")
+	}
+	fetched, ok := send(show).(messages.RoomsFetchedMsg)
+	if !ok {
+		t.Fatal("Expected the rooms and zones to be fetched")
+	}
+	send(fetched)
+	view := model.View()
+	if !contains(view, "Living Room") || !contains(view, "Downstairs") || !contains(view, "zone · 3 lights") {
+		t.Error("Expected rooms and zones to be listed")
+	}
+
+	// New room with the floor lamp, taken from the living room
+	send(runes("n"))
+	send(runes("Nook"))
+	// Lights are listed in the order of the main view
+	floor := -1
+	i := 0
+	for _, room := range model.rooms {
+		for _, light := range room.Lights {
+			if light.ID == "light-lr-floor" {
+				floor = i
+			}
+			i++
+		}
+	}
+	for i := 0; i <= floor; i++ {
+		send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	save, ok := send(tea.KeyMsg{Type: tea.KeyEnter}).(messages.RoomSaveMsg)
+	if !ok || save.Name != "Nook" || save.ID != "" || len(save.ChildIDs) != 1 {
+		t.Fatalf("Unexpected save message %+v", save)
+	}
+	fetched, ok = send(save).(messages.RoomsFetchedMsg)
+	if !ok {
+		t.Fatal("Expected the room to be created")
+	}
+	send(fetched)
+	if !contains(model.View(), "Nook") {
+		t.Error("Expected the new room in the list")
+	}
+
+	// Closing the screen refreshes the main view
+	hide := send(tea.KeyMsg{Type: tea.KeyEsc})
+	if _, ok := send(hide).(messages.RefreshMsg); !ok {
+		t.Fatal("Expected a refresh after changing rooms")
+	}
+	dataMsg = drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	newModel, _ = model.Update(dataMsg)
+	model = newModel.(Model)
+	found := false
+	for _, room := range model.rooms {
+		if room.Name == "Nook" && room.LightByID("light-lr-floor") != nil {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the floor lamp in the new room")
+	}
+}
+
+func TestStopSceneDynamics(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	if err := model.bridge.ActivateScene(context.Background(), "scene-relax"); err != nil {
+		t.Fatalf("ActivateScene returned error: %v", err)
+	}
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	newModel, _ = model.Update(messages.ShowScenesMsg{RoomID: "room-living"})
+	model = newModel.(Model)
+	if !contains(model.View(), "stop dynamics") {
+		t.Fatal("Expected a stop action for the cycling scene")
+	}
+
+	// The stop action comes after the room's scenes
+	var cmd tea.Cmd
+	for i := 0; i < 10; i++ {
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model = newModel.(Model)
+	}
+	newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newModel.(Model)
+	stop, ok := cmd().(messages.StopSceneDynamicsMsg)
+	if !ok || stop.SceneID != "scene-relax" {
+		t.Fatalf("Expected to stop the relax scene, got %+v", stop)
+	}
+	newModel, cmd = model.Update(stop)
+	model = newModel.(Model)
+	if model.screen != ScreenMain {
+		t.Error("Expected stopping to close the modal")
+	}
+	if _, ok := cmd().(messages.RefreshMsg); !ok {
+		t.Error("Expected a refresh after stopping the scene")
+	}
+
+	scenes, _ := model.bridge.GetScenes(context.Background())
+	for _, scene := range scenes {
+		if scene.ID == "scene-relax" && scene.Status != "static" {
+			t.Errorf("Expected the scene to be static, got %q", scene.Status)
+		}
+	}
+}
+
+func TestSmartScene(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	newModel, _ = model.Update(messages.ShowScenesMsg{RoomID: "room-living"})
+	model = newModel.(Model)
+	if !contains(model.View(), "☀ Natural light") {
+		t.Fatal("Expected the smart scene in the room's scenes")
+	}
+
+	var cmd tea.Cmd
+	for _, r := range "/natural" {
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = newModel.(Model)
+	}
+	newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newModel.(Model)
+	toggle, ok := cmd().(messages.SmartSceneToggleMsg)
+	if !ok || toggle.SceneID != "schedule-natural" || !toggle.Active {
+		t.Fatalf("Expected to start the smart scene, got %+v", toggle)
+	}
+	newModel, cmd = model.Update(toggle)
+	model = newModel.(Model)
+	newModel, _ = model.Update(cmd())
+	model = newModel.(Model)
+
+	newModel, _ = model.Update(messages.ShowScenesMsg{RoomID: "room-living"})
+	model = newModel.(Model)
+	if !contains(model.View(), "running, enter stops") {
+		t.Error("Expected the smart scene to show as running")
+	}
+	schedules, _ := model.bridge.GetSchedules(context.Background())
+	for _, s := range schedules {
+		if s.ID == "schedule-natural" && !s.Enabled {
+			t.Error("Expected the smart scene schedule to be enabled")
+		}
+	}
+}
+
+func TestCommandPaletteScenes(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	if _, err := model.bridge.CreateScene(context.Background(), "Relax", "room-bedroom", "room", nil); err != nil {
+		t.Fatalf("CreateScene returned error: %v", err)
+	}
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = newModel.(Model)
+	for _, r := range "relax" {
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = newModel.(Model)
+	}
+	view := model.View()
+	if !contains(view, "Relax — Bedroom") || !contains(view, "Relax — Living Room") {
+		t.Fatalf("Expected both Relax scenes with their room:\n%s", view)
+	}
+	if contains(view, "Energize") {
+		t.Error("Expected the other scenes to be filtered out")
+	}
+
+	// Bedroom sorts first, the second entry is the living room's
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = newModel.(Model)
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newModel.(Model)
+	activated, ok := cmd().(messages.SceneActivatedMsg)
+	if !ok || activated.SceneID != "scene-relax" {
+		t.Fatalf("Expected the living room's Relax to be activated, got %+v", activated)
+	}
+	if contains(model.View(), "Commands") {
+		t.Error("Expected the palette to close")
+	}
+}
+
+func TestOnOffOnlyLight(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	plug := selectLight(t, &model, "light-of-plug")
+	if !contains(model.View(), "⏻") {
+		t.Error("Expected the plug icon in the view")
+	}
+
+	brightness := plug.Brightness
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(runes("5"))
+	if plug.Brightness != brightness {
+		t.Errorf("Expected brightness to stay %d, got %d", brightness, plug.Brightness)
+	}
+	if !plug.On {
+		t.Error("Expected the plug to stay on")
+	}
+}
+
+func TestFineBrightness(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	press(runes("j"))
+	light := model.mainScreen.SelectedLight()
+	if light == nil {
+		t.Fatal("Expected a light to be selected")
+	}
+
+	// Type an exact value, replacing the prefilled one
+	press(runes("%"))
+	for i := 0; i < 3; i++ {
+		press(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(runes("x")) // ignored, not a digit
+	press(runes("3"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !light.On || light.BrightnessPct() != 3 {
+		t.Fatalf("Expected the light on at 3%%, got on=%v %d%%", light.On, light.BrightnessPct())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyShiftRight})
+	if light.BrightnessPct() != 4 {
+		t.Errorf("Expected shift+right to step to 4%%, got %d%%", light.BrightnessPct())
+	}
+	press(tea.KeyMsg{Type: tea.KeyShiftLeft})
+	press(tea.KeyMsg{Type: tea.KeyShiftLeft})
+	if light.BrightnessPct() != 2 {
+		t.Errorf("Expected shift+left to step to 2%%, got %d%%", light.BrightnessPct())
+	}
+
+	// Out of range values are rejected, esc leaves the light alone
+	press(runes("%"))
+	press(runes("55"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if light.BrightnessPct() != 2 {
+		t.Errorf("Expected 255%% to be rejected, got %d%%", light.BrightnessPct())
+	}
+
+	// 0 turns the light off
+	press(runes("%"))
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(runes("0"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if light.On {
+		t.Error("Expected 0% to turn the light off")
+	}
+
+	// Undo brings the exact value back
+	press(runes("u"))
+	if !light.On || light.BrightnessPct() != 2 {
+		t.Errorf("Expected undo to restore 2%%, got on=%v %d%%", light.On, light.BrightnessPct())
+	}
+}
+
+func TestRoomPanelScroll(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+
+	// Make the first room bigger than the panel list
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || !model.mainScreen.IsRoomSelected() {
+		t.Fatal("Expected the first room to be selected")
+	}
+	for i := 0; i < 12; i++ {
+		room.Lights = append(room.Lights, &models.Light{
+			ID:        fmt.Sprintf("extra-%d", i),
+			Name:      fmt.Sprintf("Zz extra %02d", i),
+			Reachable: true,
+		})
+	}
+	newModel, _ = model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	model = newModel.(Model)
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+
+	if !contains(model.View(), "S-tab to browse") {
+		t.Error("Expected the panel list to be cut short before browsing")
+	}
+
+	// Browse to the last light of the room
+	press(tea.KeyMsg{Type: tea.KeyShiftTab})
+	for i := 0; i < len(room.Lights)+2; i++ {
+		press(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	light := model.mainScreen.SelectedLight()
+	if light == nil || light.ID != "extra-11" {
+		t.Fatalf("Expected down to stop at the last light of the room, got %v", light)
+	}
+	if !contains(model.View(), fmt.Sprintf("↑ %d more", len(room.Lights)-8)) {
+		t.Error("Expected the panel list to scroll to the selected light")
+	}
+
+	// Light keys still apply to the selected light
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if !light.On || light.BrightnessPct() != 50 {
+		t.Errorf("Expected the browsed light at 50%%, got on=%v %d%%", light.On, light.BrightnessPct())
+	}
+
+	// esc gives the focus back to the main list
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	if selected := model.mainScreen.SelectedLight(); selected == nil || selected.ID != "extra-10" {
+		t.Errorf("Expected up to move in the main list, got %v", selected)
+	}
+	if contains(model.View(), "↑↓ lights") {
+		t.Error("Expected the panel to leave browse mode")
+	}
+}
+
+func TestRoomBrightnessSpread(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	model = newModel.(Model)
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || len(room.Lights) < 2 {
+		t.Fatal("Expected a room with several lights")
+	}
+	for _, light := range room.Lights {
+		light.On = true
+		light.SetBrightnessPct(40)
+	}
+	view := model.View()
+	if !contains(view, "Spread:") || !contains(view, "··  ··  ██  ··  ··") {
+		t.Error("Expected every light in the 26-50% bucket")
+	}
+	if !contains(view, "off 25  50  75  100") {
+		t.Error("Expected the bucket labels")
+	}
+
+	// A mixed room shows several buckets
+	room.Lights[0].On = false
+	room.Lights[1].SetBrightnessPct(100)
+	if contains(model.View(), "··  ··  ██  ··  ··") {
+		t.Error("Expected the spread to show the mixed brightness")
+	}
+}
+
+func TestTerminalTooSmall(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+
+	update(tea.WindowSizeMsg{Width: 30, Height: 8})
+	view := model.View()
+	if !contains(view, "Terminal too small") || !contains(view, "30×8, needs 48×12") {
+		t.Errorf("Expected the minimum size, got:\n%s", view)
+	}
+	if lines := strings.Split(view, "\n"); len(lines) > 8 || lipgloss.Width(view) > 30 {
+		t.Errorf("Expected the notice to fit 30×8, got %d lines of %d columns", len(lines), lipgloss.Width(view))
+	}
+
+	// Keys don't act on the hidden screen
+	light := model.rooms[0].Lights[0]
+	on := light.On
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	if light.On != on {
+		t.Error("Expected keys to be ignored while too small")
+	}
+
+	// No size garbles the layout or panics
+	for width := 1; width <= 90; width += 7 {
+		for _, height := range []int{1, 5, 12, 30} {
+			update(tea.WindowSizeMsg{Width: width, Height: height})
+			_ = model.View()
+		}
+	}
+
+	update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if view := model.View(); contains(view, "Terminal too small") || !contains(view, model.rooms[0].Name) {
+		t.Error("Expected the UI back after resizing")
+	}
+}
+
+func TestLeaderKeyChords(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	key := func(k string) tea.Cmd {
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		model = newModel.(Model)
+		return cmd
+	}
+
+	if cmd := key("g"); cmd != nil {
+		t.Error("Expected the leader key to wait for the next key")
+	}
+	if view := model.View(); !contains(view, "g-") || !contains(view, "schedules") {
+		t.Error("Expected the pending chord in the status bar")
+	}
+	cmd := key("a")
+	if cmd == nil {
+		t.Fatal("Expected g a to open the schedules")
+	}
+	if _, ok := cmd().(messages.ShowSchedulesMsg); !ok {
+		t.Error("Expected ShowSchedulesMsg")
+	}
+
+	// g g jumps back to the top
+	key("j")
+	key("j")
+	key("g")
+	key("g")
+	if !model.mainScreen.IsRoomSelected() || model.mainScreen.SelectedRoom() != model.rooms[0] {
+		t.Error("Expected g g to select the first room")
+	}
+
+	// Unbound keys cancel the chord without acting
+	key("g")
+	if cmd := key("q"); cmd != nil {
+		t.Error("Expected g q to only cancel the chord")
+	}
+	if contains(model.View(), "g-") {
+		t.Error("Expected the chord to be cleared")
+	}
+}
+
+func TestGroupByType(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+	key := func(k string) {
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		model = newModel.(Model)
+	}
+
+	key("j")
+	selected := model.mainScreen.SelectedLight()
+	if selected == nil {
+		t.Fatal("Expected a light to be selected")
+	}
+	key("g")
+	key("t")
+	view := model.View()
+	if !contains(view, "Lights grouped by type") || !contains(view, "Plugs") || !contains(view, "Fixtures") {
+		t.Errorf("Expected the lights grouped by type, got:\n%s", view)
+	}
+	if contains(view, "Kitchen (") {
+		t.Error("Expected no room headers")
+	}
+	if light := model.mainScreen.SelectedLight(); light == nil || light.ID != selected.ID {
+		t.Error("Expected the selected light to stay selected")
+	}
+
+	// Each light is listed once, under its kind
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || room.Name != models.ArchetypeKind(selected.Archetype) {
+		t.Errorf("Expected %s under %s, got %v", selected.Name, models.ArchetypeKind(selected.Archetype), room)
+	}
+
+	key("g")
+	key("t")
+	if view := model.View(); !contains(view, "Lights grouped by room") || !contains(view, "Kitchen (") {
+		t.Error("Expected the rooms back")
+	}
+}
+
+// newLoadedModel returns a demo mode model with the demo data loaded
+func newLoadedModel(t *testing.T, cfg *config.Config) Model {
+	t.Helper()
+	model := NewModel(cfg, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	return newModel.(Model)
+}
+
+// selectLight moves the selection down to a light and returns it
+func selectLight(t *testing.T, model *Model, id string) *models.Light {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if light := model.mainScreen.SelectedLight(); light != nil && light.ID == id && !model.mainScreen.IsRoomSelected() {
+			return light
+		}
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		*model = newModel.(Model)
+	}
+	t.Fatalf("Light %s not found", id)
+	return nil
+}
+
+// drainFetch runs a fetch command through its progress messages
+func drainFetch(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	for {
+		progress, ok := msg.(messages.FetchProgressMsg)
+		if !ok {
+			return msg
+		}
+		msg = progress.Next()
+	}
+}
+
+func TestFetchProgress(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	msg := model.fetchDataCmd()()
+	var loaded []string
+	for {
+		progress, ok := msg.(messages.FetchProgressMsg)
+		if !ok {
+			break
+		}
+		loaded = append(loaded, progress.Resource)
+		newModel, _ := model.Update(progress)
+		model = newModel.(Model)
+		if progress.Resource == api.FetchRooms && !contains(model.View(), "rooms ✓") {
+			t.Error("Expected loaded rooms to be checked off")
+		}
+		msg = progress.Next()
+	}
+	if len(loaded) != len(api.FetchResources) {
+		t.Errorf("Expected progress for %v, got %v", api.FetchResources, loaded)
+	}
+	if _, ok := msg.(messages.DataFetchedMsg); !ok {
+		t.Fatalf("Expected DataFetchedMsg after the progress, got %T", msg)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
 
-func containsHelper(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+func containsHelper(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestCertificateMismatchAlert(t *testing.T) {
+	cfg := &config.Config{
+		Bridges: []config.BridgeConfig{
+			{Host: "192.168.1.100", Username: "key", BridgeID: "bridge1", CertFingerprint: "aaaabbbb"},
+		},
+		LastBridgeID: "bridge1",
+	}
+	model := NewModel(cfg, false)
+
+	err := fmt.Errorf("failed to fetch rooms: %w", api.ErrCertificateMismatch)
+	newModel, _ := model.Update(messages.ErrorMsg{Err: err})
+	m := newModel.(Model)
+
+	if m.certAlert == nil {
+		t.Fatal("Expected certificate alert to be set")
+	}
+	if m.certAlert.Expected != "aaaabbbb" {
+		t.Errorf("Expected pinned fingerprint aaaabbbb, got %q", m.certAlert.Expected)
+	}
+	if view := m.View(); !contains(view, "certificate has changed") || !contains(view, "aaaa:bbbb") {
+		t.Errorf("Expected certificate alert in view, got:\n%s", view)
+	}
+
+	// Without a presented certificate there is nothing to trust yet
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if newModel.(Model).certAlert == nil {
+		t.Error("Expected alert to remain until a certificate is presented")
+	}
+}
+
+func TestErrorPanel(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	model = newModel.(Model)
+
+	authErr := fmt.Errorf("failed to set light state: %w", errors.New("API error (status 403): unauthorized user"))
+	newModel, _ = model.Update(messages.ErrorMsg{Err: authErr})
+	model = newModel.(Model)
+	view := model.View()
+	for _, want := range []string{"Authorization error", "set light state", "re-pair", "esc"} {
+		if !contains(view, want) {
+			t.Errorf("Expected the error panel to contain %q", want)
+		}
+	}
+
+	// Other keys wait until the error is dealt with
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model = newModel.(Model)
+	if model.err == nil {
+		t.Fatal("Expected the error to stay until dismissed")
+	}
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	model = newModel.(Model)
+	if model.err != nil || model.screen != ScreenSetup {
+		t.Errorf("Expected re-pairing to open the setup screen, got screen %d err %v", model.screen, model.err)
+	}
+
+	model.screen = ScreenMain
+	netErr := fmt.Errorf("failed to fetch lights: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	newModel, _ = model.Update(messages.ErrorMsg{Err: netErr})
+	model = newModel.(Model)
+	if !contains(model.View(), "Connection error") {
+		t.Error("Expected a connection error")
+	}
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	model = newModel.(Model)
+	if _, ok := cmd().(messages.RefreshMsg); !ok || model.err != nil {
+		t.Error("Expected retry to refresh and clear the error")
+	}
+
+	newModel, _ = model.Update(messages.ErrorMsg{Err: errors.New("boom")})
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newModel.(Model)
+	if model.err != nil || contains(model.View(), "boom") {
+		t.Error("Expected esc to dismiss the error")
+	}
+}
+
+func TestErrorOperation(t *testing.T) {
+	tests := []struct {
+		err       error
+		operation string
+		message   string
+	}{
+		{fmt.Errorf("failed to save scene %q: %w", "Cozy", errors.New("room not found")), `save scene "Cozy"`, "room not found"},
+		{errors.New("no bridges configured"), "", "no bridges configured"},
+		{errors.New("failed to pair"), "pair", "failed to pair"},
+	}
+	for _, tt := range tests {
+		operation, message := errorOperation(tt.err)
+		if operation != tt.operation || message != tt.message {
+			t.Errorf("errorOperation(%q) = %q, %q, want %q, %q", tt.err, operation, message, tt.operation, tt.message)
+		}
+	}
+}
+
+func TestMuteRoomEvents(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || len(room.Lights) == 0 {
+		t.Fatal("Expected a room with lights to be selected")
+	}
+	lightEvent := api.Event{
+		Type:       api.EventTypeUpdate,
+		Resource:   "light",
+		ResourceID: room.Lights[0].ID,
+		Data:       []byte(`{"id":"` + room.Lights[0].ID + `","on":{"on":false}}`),
+	}
+
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+	model = newModel.(Model)
+	muteMsg, ok := cmd().(messages.RoomMutedMsg)
+	if !ok || muteMsg.RoomID != room.ID || !muteMsg.Muted {
+		t.Fatalf("Expected to mute %s, got %+v", room.ID, muteMsg)
+	}
+	newModel, _ = model.Update(muteMsg)
+	model = newModel.(Model)
+	if !contains(model.View(), "live updates paused") {
+		t.Error("Expected the room to show its updates are paused")
+	}
+
+	model.handleEvents([]api.Event{lightEvent})
+	select {
+	case msg := <-model.eventChan:
+		t.Errorf("Expected the muted room's update to be dropped, got %T", msg)
+	default:
+	}
+
+	// Unmuting refetches to catch up
+	newModel, cmd = model.Update(messages.RoomMutedMsg{RoomID: room.ID, Muted: false})
+	model = newModel.(Model)
+	if _, ok := drainFetch(cmd).(messages.DataFetchedMsg); !ok {
+		t.Error("Expected a refetch after unmuting")
+	}
+	model.handleEvents([]api.Event{lightEvent})
+	select {
+	case <-model.eventChan:
+	default:
+		t.Error("Expected the room's updates to be followed again")
+	}
+}
+
+func TestGroupedLightUpdate(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || len(room.Lights) < 2 || room.GroupedLightID == "" {
+		t.Fatal("Expected a room with a grouped light and several lights")
+	}
+	for _, light := range room.Lights {
+		light.On = false
+	}
+	room.UpdateState()
+
+	// One light turning on turns the room on, not the other lights
+	on := true
+	room.Lights[0].On = true
+	newModel, _ := model.Update(messages.GroupedLightUpdateMsg{GroupedLightID: room.GroupedLightID, On: &on})
+	model = newModel.(Model)
+	if room.Lights[1].On {
+		t.Error("Expected the room turning on to leave the other lights off")
+	}
+
+	off := false
+	_, _ = model.Update(messages.GroupedLightUpdateMsg{GroupedLightID: room.GroupedLightID, On: &off})
+	for _, light := range room.Lights {
+		if light.On {
+			t.Errorf("Expected %s off with its room", light.Name)
+		}
+	}
+	if room.AnyOn {
+		t.Error("Expected the room to be off")
+	}
+}
+
+func TestSceneShortcuts(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || room.ID != "room-bedroom" {
+		t.Fatalf("Expected the bedroom to be selected, got %+v", room)
+	}
+	altKey := func(key string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: true}
+	}
+
+	// Unbound keys activate the room's scenes in order
+	_, cmd := model.Update(altKey("1"))
+	if cmd == nil {
+		t.Fatal("Expected alt+1 to activate a scene")
+	}
+	if msg, ok := cmd().(messages.SceneActivatedMsg); !ok || msg.SceneID != "scene-sleep" {
+		t.Errorf("Expected alt+1 to activate Sleep, got %+v", msg)
+	}
+
+	// Bind Reading to 1 from the scenes modal
+	newModel, _ := model.Update(messages.ShowScenesMsg{RoomID: room.ID})
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = newModel.(Model)
+	_, cmd = model.Update(altKey("1"))
+	shortcut, ok := cmd().(messages.SceneShortcutMsg)
+	if !ok || shortcut.RoomID != room.ID || shortcut.Key != "1" || shortcut.SceneID != "scene-reading" {
+		t.Fatalf("Expected Reading to be bound to 1, got %+v", shortcut)
+	}
+	newModel, _ = model.Update(shortcut)
+	model = newModel.(Model)
+	if !contains(model.View(), "Reading  [1]") {
+		t.Error("Expected the modal to show the key of Reading")
+	}
+
+	// Digits activate the shortcuts in the modal, alt+digits in the main view
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if msg, ok := cmd().(messages.SceneActivatedMsg); !ok || msg.SceneID != "scene-reading" {
+		t.Errorf("Expected 1 to activate Reading in the modal, got %+v", msg)
+	}
+	newModel, _ = model.Update(messages.HideScenesMsg{})
+	model = newModel.(Model)
+	_, cmd = model.Update(altKey("1"))
+	if msg, ok := cmd().(messages.SceneActivatedMsg); !ok || msg.SceneID != "scene-reading" {
+		t.Errorf("Expected alt+1 to activate Reading, got %+v", msg)
+	}
+	_, cmd = model.Update(altKey("9"))
+	if cmd != nil {
+		t.Errorf("Expected no scene for alt+9, got %T", cmd())
+	}
+}
+
+func TestReconcileUnconfirmedUpdate(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || len(room.Lights) == 0 {
+		t.Fatal("Expected a room with lights to be selected")
+	}
+	light := room.Lights[0]
+	marks := strings.Count(model.View(), " ?")
+
+	// The bridge never echoes the change
+	model.pending.Add(light.ID, "on", !light.On)
+	model.pending.mu.Lock()
+	model.pending.ops[light.ID+":on"].ExpiresAt = time.Now().Add(-time.Second)
+	model.pending.mu.Unlock()
+
+	cmd := model.reconcile()
+	if cmd == nil {
+		t.Fatal("Expected the light to be fetched again")
+	}
+	if got := strings.Count(model.View(), " ?"); got != marks+1 {
+		t.Errorf("Expected the light row to be marked, got %d marks instead of %d", got, marks+1)
+	}
+	fetched, ok := cmd().(messages.LightReconciledMsg)
+	if !ok || fetched.LightID != light.ID || fetched.Err != nil {
+		t.Fatalf("Expected the light's state, got %+v", fetched)
+	}
+
+	// The bridge's state replaces the local guess
+	fetched.Light.On = !light.On
+	fetched.Light.SetBrightnessPct(10)
+	newModel, _ := model.Update(fetched)
+	model = newModel.(Model)
+	if light.On != fetched.Light.On || light.BrightnessPct() != 10 {
+		t.Errorf("Expected the bridge's state to be applied, got on=%v brightness=%d", light.On, light.BrightnessPct())
+	}
+	if got := strings.Count(model.View(), " ?"); got != marks {
+		t.Errorf("Expected the mark to be cleared, got %d marks instead of %d", got, marks)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{Preferences: config.Preferences{Hooks: []config.Hook{
+		{Event: "light_on", Command: "cat >> " + filepath.Join(dir, "light_on")},
+		{Event: "motion", Command: "cat > " + filepath.Join(dir, "motion")},
+	}}}
+	model := newLoadedModel(t, cfg)
+
+	var off *models.Light
+	for _, room := range model.rooms {
+		for _, light := range room.Lights {
+			if !light.On && off == nil {
+				off = light
+			}
+		}
+	}
+	if off == nil {
+		t.Fatal("Expected a light that is off")
+	}
+
+	// Our own change is echoed without running the hook
+	on := true
+	model.pending.Add(off.ID, "on", true)
+	newModel, _ := model.Update(messages.LightUpdateMsg{LightID: off.ID, On: &on})
+	model = newModel.(Model)
+	model.hooks.Wait()
+	if _, err := os.Stat(filepath.Join(dir, "light_on")); !os.IsNotExist(err) {
+		t.Error("Expected no hook for a change made from hue-tui")
+	}
+
+	// Nor for the lights of a scene recalled from hue-tui
+	var scene *models.Scene
+	for _, sc := range model.scenes {
+		if len(sc.Actions) > 0 && sc.Actions[0].On && !sc.IsSmart {
+			scene = sc
+			break
+		}
+	}
+	if scene == nil {
+		t.Fatal("Expected a scene turning a light on")
+	}
+	recalled := model.findLightByID(scene.Actions[0].LightID)
+	recalled.On = false
+	newModel, _ = model.Update(messages.SceneActivatedMsg{SceneID: scene.ID})
+	model = newModel.(Model)
+	newModel, _ = model.Update(messages.LightUpdateMsg{LightID: recalled.ID, On: &on})
+	model = newModel.(Model)
+	model.hooks.Wait()
+	if _, err := os.Stat(filepath.Join(dir, "light_on")); !os.IsNotExist(err) {
+		t.Error("Expected no hook for a scene recalled from hue-tui")
+	}
+
+	// Turned on from the app or a switch
+	off.On = false
+	newModel, _ = model.Update(messages.LightUpdateMsg{LightID: off.ID, On: &on})
+	model = newModel.(Model)
+	model.hooks.Wait()
+	data, err := os.ReadFile(filepath.Join(dir, "light_on"))
+	if err != nil {
+		t.Fatalf("Expected the light_on hook to run: %v", err)
+	}
+	if !contains(string(data), `"light_id":"`+off.ID+`"`) || !contains(string(data), `"light":"`+off.Name+`"`) {
+		t.Errorf("Unexpected payload: %s", data)
+	}
+
+	msg := eventToMsg(api.Event{
+		Type:       api.EventTypeUpdate,
+		Resource:   "motion",
+		ResourceID: "motion-1",
+		Data:       []byte(`{"id":"motion-1","motion":{"motion":true}}`),
+	})
+	newModel, _ = model.Update(msg)
+	model = newModel.(Model)
+	model.hooks.Wait()
+	if data, err := os.ReadFile(filepath.Join(dir, "motion")); err != nil || !contains(string(data), `"sensor_id":"motion-1"`) {
+		t.Errorf("Expected the motion hook to run, got %q (%v)", data, err)
+	}
+}
+
+// countingBridge is a demo bridge counting the brightness writes it gets
+type countingBridge struct {
+	*api.DemoBridge
+	mu         sync.Mutex
+	writes     map[string]int
+	brightness map[string]int
+}
+
+func (b *countingBridge) SetLightBrightness(ctx context.Context, lightID string, brightness int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes[lightID]++
+	b.brightness[lightID] = brightness
+	return nil
+}
+
+func TestCoalescedWrites(t *testing.T) {
+	bridge := &countingBridge{DemoBridge: api.NewDemoBridge(), writes: make(map[string]int), brightness: make(map[string]int)}
+	coalescer := newWriteCoalescer(coalesceWindow)
+	// The window ends when the test says so
+	windowStarted := make(chan struct{}, 2)
+	windowEnd := make(chan time.Time)
+	coalescer.after = func(time.Duration) <-chan time.Time {
+		windowStarted <- struct{}{}
+		return windowEnd
+	}
+	coalesced := coalescingBridge{BridgeClient: bridge, coalescer: coalescer}
+
+	// A held arrow key: the first press waits for the window, the
+	// following ones only replace its value
+	var wg sync.WaitGroup
+	send := func(lightID string, brightness int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := coalesced.SetLightBrightness(context.Background(), lightID, brightness); err != nil {
+				t.Errorf("SetLightBrightness returned error: %v", err)
+			}
+		}()
+		<-windowStarted
+	}
+	send("light-a", 10)
+	for i := 2; i <= 10; i++ {
+		if err := coalesced.SetLightBrightness(context.Background(), "light-a", i*10); err != nil {
+			t.Fatalf("SetLightBrightness returned error: %v", err)
+		}
+	}
+	send("light-b", 30)
+	close(windowEnd)
+	wg.Wait()
+
+	if bridge.writes["light-a"] != 1 || bridge.brightness["light-a"] != 100 {
+		t.Errorf("Expected one write of the latest value, got %d writes of %d", bridge.writes["light-a"], bridge.brightness["light-a"])
+	}
+	if bridge.writes["light-b"] != 1 {
+		t.Errorf("Expected other lights to be written separately, got %d writes", bridge.writes["light-b"])
+	}
+
+	// Once sent, the next change goes out again
+	if err := coalesced.SetLightBrightness(context.Background(), "light-a", 40); err != nil {
+		t.Fatalf("SetLightBrightness returned error: %v", err)
+	}
+	if bridge.writes["light-a"] != 2 || bridge.brightness["light-a"] != 40 {
+		t.Errorf("Expected a second write of 40, got %d writes of %d", bridge.writes["light-a"], bridge.brightness["light-a"])
+	}
+}
+
+func TestThrottleIndicator(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	newModel, _ = model.Update(messages.ThrottleMsg{Queued: 3})
+	model = newModel.(Model)
+	if !contains(model.View(), "3 queued") {
+		t.Error("Expected the throttled commands to be shown")
+	}
+	newModel, _ = model.Update(messages.ThrottleMsg{Queued: 0})
+	model = newModel.(Model)
+	if contains(model.View(), "queued") {
+		t.Error("Expected the indicator to go away once the queue is empty")
+	}
+}
+
+func TestGenerateNaturalLightScenes(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	newModel, _ := model.Update(messages.ShowScenesMsg{RoomID: "room-office"})
+	model = newModel.(Model)
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	model = newModel.(Model)
+	generate, ok := cmd().(messages.GenerateScenesMsg)
+	if !ok || generate.RoomID != "room-office" {
+		t.Fatalf("Expected to generate scenes for the office, got %+v", generate)
+	}
+
+	newModel, cmd = model.Update(generate)
+	model = newModel.(Model)
+	if model.screen != ScreenMain {
+		t.Error("Expected the scenes modal to close")
+	}
+	generated, ok := cmd().(messages.ScenesGeneratedMsg)
+	if !ok || len(generated.Names) != len(api.NaturalLightPresets) {
+		t.Fatalf("Expected every natural light scene to be created, got %+v", generated)
+	}
+
+	scenes, err := model.bridge.GetScenes(context.Background())
+	if err != nil {
+		t.Fatalf("GetScenes returned error: %v", err)
+	}
+	found := 0
+	for _, scene := range scenes {
+		if scene.RoomID == "room-office" && (scene.Name == "Morning" || scene.Name == "Night") {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expected the Morning and Night scenes in the office, found %d", found)
+	}
+
+	// Running it again doesn't duplicate them, nor existing scenes of the
+	// same name
+	newModel, _ = model.Update(drainFetch(model.fetchDataCmd()))
+	model = newModel.(Model)
+	newModel, cmd = model.Update(messages.GenerateScenesMsg{RoomID: "room-office"})
+	model = newModel.(Model)
+	if again, ok := cmd().(messages.ScenesGeneratedMsg); !ok || len(again.Names) != 0 {
+		t.Errorf("Expected no new scenes, got %+v", again)
+	}
+	_, cmd = model.Update(messages.GenerateScenesMsg{RoomID: "room-kitchen"})
+	if kitchen, ok := cmd().(messages.ScenesGeneratedMsg); !ok || slices.Contains(kitchen.Names, "Morning") {
+		t.Errorf("Expected the kitchen's Morning scene to be kept, got %+v", kitchen)
+	}
+}
+
+func TestArchetypePicker(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	var cmd tea.Cmd
+	press := func(msg tea.KeyMsg) {
+		var newModel tea.Model
+		newModel, cmd = model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	desk := selectLight(t, &model, "light-of-desk")
+	if !contains(model.View(), "Table shade") {
+		t.Error("Expected the light type in the side panel")
+	}
+
+	press(runes("A"))
+	press(runes("vintage c"))
+	if !contains(model.View(), "> Vintage candle bulb") {
+		t.Fatal("Expected the filtered archetype to be selected")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if desk.Archetype != "vintage_candle_bulb" {
+		t.Errorf("Expected the archetype to be set optimistically, got %q", desk.Archetype)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command setting the archetype")
+	}
+	cmd()
+	light, err := model.bridge.GetLight(context.Background(), "light-of-desk")
+	if err != nil {
+		t.Fatalf("GetLight returned error: %v", err)
+	}
+	if light.Archetype != "vintage_candle_bulb" {
+		t.Errorf("Expected the bridge to store the archetype, got %q", light.Archetype)
+	}
+
+	// esc closes the picker without changes
+	press(runes("A"))
+	press(runes("plug"))
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if desk.Archetype != "vintage_candle_bulb" || contains(model.View(), "esc cancel") {
+		t.Error("Expected esc to cancel the picker")
+	}
+}
+
+func TestBridgeConfigError(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg := &config.Config{Bridges: []config.BridgeConfig{{
+		Host:     "192.168.1.2",
+		Username: "user",
+		BridgeID: "bridge",
+		TLSMode:  "bogus",
+	}}}
+	model := NewModel(cfg, false)
+	if model.bridge != nil {
+		t.Fatalf("Expected no bridge on a TLS config error, got %T", model.bridge)
+	}
+	if model.err == nil {
+		t.Fatal("Expected the TLS config error to be shown")
+	}
+	if cmd := model.connectCmd(); cmd != nil {
+		t.Error("Expected no connection attempt without a bridge")
+	}
+}
+
+// namedDeviceBridge is a demo bridge knowing the name of one more device
+type namedDeviceBridge struct {
+	*api.DemoBridge
+}
+
+func (b namedDeviceBridge) GetDeviceName(ctx context.Context, deviceID string) (string, error) {
+	if deviceID == "device-new" {
+		return "Hue go", nil
+	}
+	return b.DemoBridge.GetDeviceName(ctx, deviceID)
+}
+
+func TestLightAddedDeviceName(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	model.bridge = namedDeviceBridge{DemoBridge: api.NewDemoBridge()}
+
+	// The light shows up right away, named once its device is fetched
+	cmd := model.handleLightAdded(&models.Light{ID: "light-new", Name: "New Bulb", DeviceID: "device-new"})
+	if model.findLightByID("light-new") == nil {
+		t.Fatal("Expected added light to be present")
+	}
+	if cmd == nil {
+		t.Fatal("Expected the device name to be fetched")
+	}
+	newModel, _ := model.Update(cmd())
+	model = newModel.(Model)
+	if name := model.findLightByID("light-new").DeviceName; name != "Hue go" {
+		t.Errorf("Expected device name Hue go, got %q", name)
+	}
+
+	// Lights added with their device name need no fetch
+	if cmd := model.handleLightAdded(&models.Light{ID: "light-named", DeviceID: "device-named", DeviceName: "Hue play"}); cmd != nil {
+		t.Error("Expected no fetch for a light with a device name")
+	}
+}
+
+func TestAuditLogOpenedOnce(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg := &config.Config{Preferences: config.Preferences{AuditLog: true}, Bridges: []config.BridgeConfig{{
+		Host:     "192.168.1.2",
+		Username: "user",
+		BridgeID: "bridge",
+	}}}
+	model := NewModel(cfg, false)
+	if model.auditFile == nil {
+		t.Fatal("Expected the audit log to be opened")
+	}
+	file := model.auditFile
+
+	// Switching bridges keeps the same audit log
+	model.connectBridge(&cfg.Bridges[0])
+	if model.auditFile != file {
+		t.Error("Expected the audit log to be opened once")
+	}
+
+	if err := model.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := file.Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected the audit log to be closed, got %v", err)
+	}
+}
+
+func TestRemoteTokenRefreshNotDropped(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	for i := 0; i < cap(model.eventChan); i++ {
+		model.eventChan <- messages.ThrottleMsg{}
+	}
+
+	model.handleRemoteToken(api.RemoteToken{AccessToken: "new"})
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case msg := <-model.eventChan:
+			if _, ok := msg.(messages.RemoteTokenMsg); ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Expected the token refresh to reach the update loop")
+		}
+	}
+}
+
+func TestV1BridgeFallback(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/api/user") {
+		case "/lights":
+			_, _ = w.Write([]byte(`{"1": {"name": "Hallway", "state": {"on": true, "bri": 254, "reachable": true}}}`))
+		case "/groups":
+			_, _ = w.Write([]byte(`{"1": {"name": "Entrance", "type": "Room", "lights": ["1"]}}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Bridges: []config.BridgeConfig{{
+		Host:     strings.TrimPrefix(server.URL, "http://"),
+		Username: "user",
+		BridgeID: "old-bridge",
+	}}}
+	model := NewModel(cfg, false)
+	if _, ok := model.bridge.(*api.HueBridge); !ok {
+		t.Fatalf("Expected a CLIP v2 client before detection, got %T", model.bridge)
+	}
+
+	cmd := model.apiVersionDetected(messages.APIVersionDetectedMsg{BridgeID: "old-bridge", Version: api.APIVersionV1})
+	if _, ok := model.bridge.(*api.V1Bridge); !ok {
+		t.Fatalf("Expected the V1 client after detection, got %T", model.bridge)
+	}
+	if cfg.Bridges[0].APIVersion != api.APIVersionV1 {
+		t.Errorf("Expected the API version to be saved, got %q", cfg.Bridges[0].APIVersion)
+	}
+	dataMsg, ok := drainFetch(cmd).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	if len(dataMsg.Rooms) != 1 || dataMsg.Rooms[0].Lights[0].Name != "Hallway" {
+		t.Errorf("Unexpected rooms: %+v", dataMsg.Rooms)
+	}
+
+	// Known V1 bridges get the V1 client right away
+	model = NewModel(cfg, false)
+	if _, ok := model.bridge.(*api.V1Bridge); !ok {
+		t.Errorf("Expected the V1 client for a known V1 bridge, got %T", model.bridge)
+	}
+
+	// Bridges with a pinned certificate are never downgraded to plain HTTP
+	cfg.Bridges[0].APIVersion = ""
+	cfg.Bridges[0].CertFingerprint = "ab:cd"
+	model = NewModel(cfg, false)
+	model.apiVersionDetected(messages.APIVersionDetectedMsg{BridgeID: "old-bridge", Version: api.APIVersionV1})
+	if _, ok := model.bridge.(*api.HueBridge); !ok {
+		t.Errorf("Expected the CLIP v2 client to be kept, got %T", model.bridge)
+	}
+	if cfg.Bridges[0].APIVersion != "" {
+		t.Errorf("Expected no API version to be saved, got %q", cfg.Bridges[0].APIVersion)
+	}
+}
+
+func TestZonePicker(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	// send updates the model and feeds the messages of its commands back
+	// until the zones are fetched again
+	var send func(msg tea.Msg)
+	send = func(msg tea.Msg) {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		if cmd == nil {
+			return
+		}
+		switch next := cmd().(type) {
+		case messages.ZonesRequestMsg, messages.RoomSaveMsg, messages.RoomDeleteMsg, messages.RoomsFetchedMsg:
+			send(next)
+		}
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	zone := func(name string) *models.Room {
+		zones, err := model.bridge.GetZones(context.Background())
+		if err != nil {
+			t.Fatalf("GetZones returned error: %v", err)
+		}
+		for _, z := range zones {
+			if z.Name == name {
+				return z
+			}
+		}
+		return nil
+	}
+
+	selectLight(t, &model, "light-of-desk")
+
+	// Add the desk lamp to Downstairs, then remove it
+	send(runes("Z"))
+	if !contains(model.View(), "Downstairs") {
+		t.Fatal("Expected the zones to be listed")
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if z := zone("Downstairs"); z == nil || len(z.LightIDs) != 4 || !slices.Contains(z.LightIDs, "light-of-desk") {
+		t.Fatalf("Expected the desk lamp to be added, got %+v", z)
+	}
+	send(runes("Z"))
+	if !contains(model.View(), "✓ Downstairs") {
+		t.Error("Expected the zone to be checked")
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if z := zone("Downstairs"); z == nil || len(z.LightIDs) != 3 || slices.Contains(z.LightIDs, "light-of-desk") {
+		t.Fatalf("Expected the desk lamp to be removed, got %+v", z)
+	}
+
+	// New zone with the lamp, then delete it
+	send(runes("Z"))
+	send(runes("n"))
+	send(runes("Reading corner"))
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if z := zone("Reading corner"); z == nil || len(z.LightIDs) != 1 || z.LightIDs[0] != "light-of-desk" {
+		t.Fatalf("Expected a new zone with the desk lamp, got %+v", z)
+	}
+	send(runes("Z"))
+	send(tea.KeyMsg{Type: tea.KeyDown})
+	send(runes("d"))
+	if !contains(model.View(), "Delete Reading corner?") {
+		t.Fatal("Expected a confirmation")
+	}
+	send(runes("y"))
+	if zone("Reading corner") != nil {
+		t.Error("Expected the zone to be deleted")
+	}
+}
+
+func TestLightMenu(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	var cmd tea.Cmd
+	press := func(msg tea.KeyMsg) {
+		var newModel tea.Model
+		newModel, cmd = model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	pick := func(action string) {
+		press(enter)
+		for i := 0; i < 5 && !contains(model.View(), "> "+action); i++ {
+			press(runes("j"))
+		}
+		if !contains(model.View(), "> "+action) {
+			t.Fatalf("Expected the %q action in the menu", action)
+		}
+		press(enter)
+	}
+
+	desk := selectLight(t, &model, "light-of-desk")
+
+	// Rename
+	pick("Rename")
+	for range desk.Name {
+		press(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(runes("Reading Lamp"))
+	press(enter)
+	if desk.Name != "Reading Lamp" {
+		t.Errorf("Expected the light to be renamed optimistically, got %q", desk.Name)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command renaming the light")
+	}
+	cmd()
+	light, err := model.bridge.GetLight(context.Background(), "light-of-desk")
+	if err != nil {
+		t.Fatalf("GetLight returned error: %v", err)
+	}
+	if light.Name != "Reading Lamp" {
+		t.Errorf("Expected the bridge to store the name, got %q", light.Name)
+	}
+
+	// Device info
+	pick("Device info")
+	if !contains(model.View(), desk.DeviceID) {
+		t.Error("Expected the device ID in the device info")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if contains(model.View(), "esc close") {
+		t.Error("Expected esc to close the menu")
+	}
+
+	// Move to another room
+	oldRoom := model.mainScreen.SelectedRoom()
+	pick("Move to room")
+	if !contains(model.View(), "Move to:") {
+		t.Fatal("Expected the room list")
+	}
+	press(enter)
+	newRoom := model.mainScreen.SelectedRoom()
+	if newRoom == nil || newRoom == oldRoom || model.mainScreen.SelectedLight() != desk {
+		t.Fatal("Expected the light to stay selected in its new room")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command saving the room")
+	}
+	newModel, cmd = model.Update(cmd())
+	model = newModel.(Model)
+	cmd()
+	rooms, err := model.bridge.GetRooms(context.Background())
+	if err != nil {
+		t.Fatalf("GetRooms returned error: %v", err)
+	}
+	for _, room := range rooms {
+		if slices.Contains(room.DeviceIDs, desk.DeviceID) != (room.ID == newRoom.ID) {
+			t.Errorf("Expected the device only in %s, found in %s: %v", newRoom.Name, room.Name, room.DeviceIDs)
+		}
+	}
+}
+
+func TestDevicesScreen(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+
+	cmd := update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	if cmd == nil {
+		t.Fatal("Expected a command opening the devices screen")
+	}
+	cmd = update(cmd())
+	if model.screen != ScreenDevices {
+		t.Fatalf("Expected ScreenDevices, got %d", model.screen)
+	}
+	msg, ok := cmd().(messages.DevicesFetchedMsg)
+	if !ok {
+		t.Fatalf("Expected DevicesFetchedMsg, got %T", msg)
+	}
+	update(msg)
+
+	view := model.View()
+	for _, want := range []string{"Bedroom Dimmer", "Hallway Sensor", "12% low", "1 device needs new batteries"} {
+		if !contains(view, want) {
+			t.Errorf("Expected %q in the devices screen", want)
+		}
+	}
+	if contains(view, "64% low") {
+		t.Error("Expected only low batteries to be flagged")
+	}
+
+	update(update(tea.KeyMsg{Type: tea.KeyEsc})())
+	if model.screen != ScreenMain {
+		t.Errorf("Expected esc to go back to the main screen, got %d", model.screen)
+	}
+}
+
+func TestRevertScene(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+
+	var cmd tea.Cmd
+	update := func(msg tea.Msg) {
+		var newModel tea.Model
+		newModel, cmd = model.Update(msg)
+		model = newModel.(Model)
+	}
+	ctrlZ := tea.KeyMsg{Type: tea.KeyCtrlZ}
+	var run func(msg tea.Msg)
+	run = func(msg tea.Msg) {
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				if c != nil {
+					run(c())
+				}
+			}
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if room := model.mainScreen.SelectedRoom(); room != nil && room.ID == "room-living" {
+			break
+		}
+		update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	}
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || room.ID != "room-living" {
+		t.Fatal("Expected the living room to be selected")
+	}
+	before := make(map[string]uint8)
+	for _, light := range room.Lights {
+		before[light.ID] = light.Brightness
+	}
+
+	// Nothing to revert yet
+	update(ctrlZ)
+	if !contains(model.View(), "No scene to revert") {
+		t.Error("Expected a notice without a scene to revert")
+	}
+
+	update(messages.SceneActivatedMsg{SceneID: "scene-movie-night"})
+	if cmd == nil {
+		t.Fatal("Expected a command activating the scene")
+	}
+	run(cmd())
+	changed := false
+	for _, light := range room.Lights {
+		changed = changed || light.Brightness != before[light.ID]
+	}
+	if !changed {
+		t.Fatal("Expected the scene to change the room")
+	}
+
+	update(ctrlZ)
+	for _, light := range room.Lights {
+		if light.Brightness != before[light.ID] {
+			t.Errorf("Expected %s back at %d, got %d", light.Name, before[light.ID], light.Brightness)
+		}
+	}
+	if cmd == nil {
+		t.Fatal("Expected commands restoring the lights")
+	}
+	run(cmd())
+	for _, light := range room.Lights {
+		got, err := model.bridge.GetLight(context.Background(), light.ID)
+		if err != nil {
+			t.Fatalf("GetLight returned error: %v", err)
+		}
+		// The bridge takes whole percentages
+		if want := models.LevelToPct(before[light.ID]); got.On && got.BrightnessPct() != want {
+			t.Errorf("Expected the bridge to restore %s to %d%%, got %d%%", light.Name, want, got.BrightnessPct())
+		}
+	}
+
+	// The capture is used once
+	update(ctrlZ)
+	if !contains(model.View(), "No scene to revert") {
+		t.Error("Expected the revert to be used up")
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	var bell bytes.Buffer
+	bellOut = &bell
+	defer func() { bellOut = os.Stdout }()
+
+	cfg := &config.Config{Preferences: config.Preferences{Alerts: &config.Alerts{Bell: true, Flash: true, MotionSensors: []string{"motion-1"}}}}
+	model := newLoadedModel(t, cfg)
+
+	// Motion on other sensors is not alerted
+	newModel, _ := model.Update(messages.MotionMsg{SensorID: "motion-2"})
+	model = newModel.(Model)
+	if contains(model.View(), "Motion on sensor") {
+		t.Error("Expected no alert for an unwatched sensor")
+	}
+
+	newModel, _ = model.Update(messages.MotionMsg{SensorID: "motion-1"})
+	model = newModel.(Model)
+	if !contains(model.View(), "Motion on sensor motion-1") {
+		t.Error("Expected the header to flash on motion")
+	}
+	firstFlash := model.flashID
+
+	// A newer alert keeps the header flashing past the first one's end
+	light := model.rooms[0].Lights[0]
+	newModel, cmd := model.Update(messages.LightCommandsResultMsg{Failed: []string{light.ID}, Err: fmt.Errorf("boom")})
+	model = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected alert commands")
+	}
+	newModel, _ = model.Update(messages.FlashEndMsg{ID: firstFlash})
+	model = newModel.(Model)
+	if !contains(model.View(), "Command failed") {
+		t.Error("Expected the header to flash on a failed command")
+	}
+	newModel, _ = model.Update(messages.FlashEndMsg{ID: model.flashID})
+	model = newModel.(Model)
+	if contains(model.View(), "Command failed") {
+		t.Error("Expected the flash to end")
+	}
+
+	ringBell()
+	if bell.String() != "\a" {
+		t.Errorf("Expected the bell to ring, got %q", bell.String())
+	}
+
+	// Alerts are off by default
+	model = NewModel(&config.Config{}, true)
+	if model.alert("Bridge disconnected") != nil {
+		t.Error("Expected no alert without the alerts config")
+	}
+}
+
+func TestLongNameMarquee(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 80, Height: 60})
+	model = newModel.(Model)
+
+	var cmd tea.Cmd
+	update := func(msg tea.Msg) {
+		var newModel tea.Model
+		newModel, cmd = model.Update(msg)
+		model = newModel.(Model)
+	}
+	// tick waits for the next marquee tick among the commands
+	var tick func(c tea.Cmd) tea.Msg
+	tick = func(c tea.Cmd) tea.Msg {
+		if c == nil {
+			return nil
+		}
+		msg := c()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				if msg := tick(c); msg != nil {
+					return msg
+				}
+			}
+			return nil
+		}
+		return msg
+	}
+
+	desk := selectLight(t, &model, "light-of-desk")
+	desk.Name = "Desk Lamp by North Window"
+
+	// Moving away and back starts the marquee on the long name
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if view := model.View(); !contains(view, "North Win…") || contains(view, "North Window") {
+		t.Fatal("Expected the long name to start truncated")
+	}
+	for i := 0; i < 20 && !contains(model.View(), "North Window"); i++ {
+		msg := tick(cmd)
+		if msg == nil {
+			t.Fatal("Expected the marquee to keep scrolling")
+		}
+		update(msg)
+	}
+	if !contains(model.View(), "North Window") {
+		t.Error("Expected the marquee to scroll to the end of the name")
+	}
+
+	// Short names don't scroll
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if tick(cmd) != nil {
+		t.Error("Expected no marquee for a name that fits")
+	}
+}
+
+func TestSceneSortAndSearch(t *testing.T) {
+	cfg := &config.Config{}
+	model := newLoadedModel(t, cfg)
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	key := func(k string) tea.Cmd {
+		var msg tea.KeyMsg
+		switch k {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+	before := func(first, second string) bool {
+		view := model.View()
+		i, j := strings.Index(view, first), strings.Index(view, second)
+		return i >= 0 && j >= 0 && i < j
+	}
+
+	newModel, _ = model.Update(messages.ShowScenesMsg{})
+	model = newModel.(Model)
+	if !contains(model.View(), "Sorted by room") || !before("Reading", "Cooking") {
+		t.Fatal("Expected the scenes grouped by room")
+	}
+
+	// Tab sorts by name, and the order is remembered
+	sortMsg, ok := key("tab")().(messages.SceneSortMsg)
+	if !ok || sortMsg.Sort != "name" {
+		t.Fatalf("Expected to sort by name, got %+v", sortMsg)
+	}
+	newModel, _ = model.Update(sortMsg)
+	model = newModel.(Model)
+	if cfg.SceneSort != "name" {
+		t.Errorf("Expected the sort to be remembered, got %q", cfg.SceneSort)
+	}
+	if !contains(model.View(), "Sorted by name") || !before("Cooking", "Reading") || !before("Movie Night", "Relax") {
+		t.Error("Expected the scenes sorted by name")
+	}
+
+	// The most recently activated scene comes first
+	newModel, _ = model.Update(messages.SceneActivatedMsg{SceneID: "scene-sleep"})
+	model = newModel.(Model)
+	newModel, _ = model.Update(messages.ShowScenesMsg{})
+	model = newModel.(Model)
+	newModel, _ = model.Update(key("tab")())
+	model = newModel.(Model)
+	if cfg.SceneSort != "recent" || !before("Sleep", "Cooking") {
+		t.Errorf("Expected Sleep first when sorted by last activated (sort %q)", cfg.SceneSort)
+	}
+	newModel, _ = model.Update(key("tab")())
+	model = newModel.(Model)
+	if cfg.SceneSort != "room" {
+		t.Errorf("Expected tab to cycle back to rooms, got %q", cfg.SceneSort)
+	}
+
+	// After /, digits search instead of activating shortcuts
+	newModel, _ = model.Update(messages.ShowScenesMsg{RoomID: "room-bedroom"})
+	model = newModel.(Model)
+	key("/")
+	if cmd := key("1"); cmd != nil {
+		t.Errorf("Expected 1 to be searched, got %T", cmd())
+	}
+	if !contains(model.View(), `No scenes match "1"`) {
+		t.Error("Expected the search for 1")
+	}
+	key("esc")
+	if cmd := key("esc"); cmd != nil {
+		t.Errorf("Expected esc to leave the search first, got %T", cmd())
+	}
+	if _, ok := key("esc")().(messages.HideScenesMsg); !ok {
+		t.Error("Expected the third esc to close the modal")
+	}
+
+	// Without a search, j/k move and s/q close; after /, they are searched
+	newModel, _ = model.Update(messages.ShowScenesMsg{})
+	model = newModel.(Model)
+	key("j")
+	if !contains(model.View(), "/ to filter") {
+		t.Error("Expected j to move instead of searching")
+	}
+	key("/")
+	for _, r := range "sunset" {
+		if cmd := key(string(r)); cmd != nil {
+			t.Fatalf("Expected %q to be searched, got %T", r, cmd())
+		}
+	}
+	if !contains(model.View(), "/ sunset█") || !contains(model.View(), `No scenes match "sunset"`) {
+		t.Error("Expected the search for sunset")
+	}
+	key("esc")
+	key("esc")
+	if _, ok := key("q")().(messages.HideScenesMsg); !ok {
+		t.Error("Expected q to close the modal")
+	}
+
+	// The remembered sort is restored
+	restored := NewModel(&config.Config{Preferences: config.Preferences{SceneSort: "recent"}}, true)
+	if !contains(restored.scenesScreen.View(), "Sorted by last activated") {
+		t.Error("Expected the sort to be restored from the config")
+	}
+}
+
+func TestTemperaturePresets(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	// The kitchen's main light goes from 153 to 454 mirek
+	light := model.findLightByID("light-kt-main")
+	selectLight(t, &model, light.ID)
+
+	press(runes("T"))
+	if !contains(model.View(), "Candle") || !contains(model.View(), "6500K") {
+		t.Fatal("Expected the temperature presets")
+	}
+	press(runes("1"))
+	if light.Color.Mirek != 454 {
+		t.Errorf("Expected candle held to the light's warmest, got %d mirek", light.Color.Mirek)
+	}
+	if !contains(model.View(), "closest some lights can show") {
+		t.Error("Expected a notice about the light's range")
+	}
+
+	// Typed kelvin, on the last row
+	press(runes("T"))
+	for range models.ColorTempPresets {
+		press(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	press(runes("500"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !contains(model.View(), "from 1000K to 10000K") {
+		t.Error("Expected 500K to be refused")
+	}
+	press(runes("0"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if light.Color.Mirek != 200 {
+		t.Errorf("Expected 5000K as 200 mirek, got %d", light.Color.Mirek)
+	}
+
+	// Each temperature is one undo step
+	press(runes("u"))
+	if light.Color.Mirek != 454 {
+		t.Errorf("Expected undo to go back to the candle preset, got %d mirek", light.Color.Mirek)
+	}
+}
+
+func TestDuplicateLightNames(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 140, Height: 60})
+	model = newModel.(Model)
+
+	// Both the living room and the bedroom have a Ceiling Light
+	view := model.View()
+	if !contains(view, "Ceiling Light (Living Room)") || !contains(view, "Ceiling Light (Bedroom)") {
+		t.Fatal("Expected the ceiling lights to show their room")
+	}
+	if contains(view, "Desk Lamp (") {
+		t.Error("Expected unique names to be left alone")
+	}
+
+	// Search matches the room part of the name
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("/")},
+		{Type: tea.KeyRunes, Runes: []rune("light (bed")},
+		{Type: tea.KeyEnter},
+	} {
+		newModel, _ = model.Update(msg)
+		model = newModel.(Model)
+	}
+	view = model.View()
+	if !contains(view, "Ceiling Light (Bedroom)") || contains(view, "Ceiling Light (Living Room)") {
+		t.Error("Expected the search to find the bedroom's ceiling light only")
+	}
+}
+
+func TestRoomFadeProgress(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{Preferences: config.Preferences{FadeSeconds: 30}})
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	model.mainScreen.SetClock(func() time.Time { return now })
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 140, Height: 60})
+	model = newModel.(Model)
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || !model.mainScreen.IsRoomSelected() || !room.AnyOn {
+		t.Fatalf("Expected a lit room to be selected, got %+v", room)
+	}
+
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	model = newModel.(Model)
+	if room.AnyOn {
+		t.Error("Expected the room to fade off")
+	}
+	if !contains(model.View(), "fading off ▱▱▱▱▱▱▱▱ 30s") {
+		t.Fatal("Expected the fade progress in the room header")
+	}
+
+	// The progress ticks while the fade is in flight
+	var tick tea.Msg
+	var run func(c tea.Cmd)
+	run = func(c tea.Cmd) {
+		if c == nil {
+			return
+		}
+		switch msg := c().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				run(c)
+			}
+		default:
+			if fmt.Sprintf("%T", msg) == "screens.fadeTickMsg" {
+				tick = msg
+			}
 		}
 	}
-	return false
+	run(cmd)
+	if tick == nil {
+		t.Fatal("Expected the fade to tick")
+	}
+
+	now = now.Add(15 * time.Second)
+	newModel, _ = model.Update(tick)
+	model = newModel.(Model)
+	if !contains(model.View(), "fading off ▰▰▰▰▱▱▱▱ 15s") {
+		t.Error("Expected the fade halfway through")
+	}
+
+	// Fading back replaces the fade, and finished fades leave the header
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	model = newModel.(Model)
+	if !room.AnyOn || !contains(model.View(), "fading on ▱▱▱▱▱▱▱▱ 30s") {
+		t.Error("Expected the room to fade back on")
+	}
+	now = now.Add(30 * time.Second)
+	newModel, _ = model.Update(tick)
+	model = newModel.(Model)
+	if contains(model.View(), "fading") {
+		t.Error("Expected the finished fade to leave the header")
+	}
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+func TestRawBrightnessScale(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 140, Height: 60})
+	model = newModel.(Model)
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	light := model.findLightByID("light-lr-floor")
+	selectLight(t, &model, light.ID)
+	if !contains(model.View(), "Brightness: 60%") {
+		t.Fatal("Expected the brightness in percent by default")
+	}
+
+	press(runes("#"))
+	if view := model.View(); !contains(view, "Brightness: 152") || !contains(view, " 152") {
+		t.Error("Expected the brightness on the 0-254 scale")
+	}
+
+	// The input takes 0-254 values too
+	press(runes("%"))
+	for i := 0; i < 3; i++ {
+		press(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(runes("254"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if light.BrightnessPct() != 100 {
+		t.Errorf("Expected 254 to set full brightness, got %d%%", light.BrightnessPct())
+	}
+	press(runes("%"))
+	for i := 0; i < 3; i++ {
+		press(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(runes("1"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !light.On || light.BrightnessPct() != 1 {
+		t.Errorf("Expected 1 to dim the light without turning it off, got %d%% (on %v)", light.BrightnessPct(), light.On)
+	}
+
+	// The scale can be set in the config
+	raw := NewModel(&config.Config{Preferences: config.Preferences{Locale: &config.Locale{BrightnessScale: "raw"}}}, true)
+	newModel, _ = raw.Update(dataMsg)
+	raw = newModel.(Model)
+	newModel, _ = raw.Update(tea.WindowSizeMsg{Width: 140, Height: 60})
+	raw = newModel.(Model)
+	if contains(raw.View(), "60%") {
+		t.Error("Expected no percentages with the raw scale")
+	}
+}
+
+func TestOtherLightsGrouping(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{Preferences: config.Preferences{OtherLights: "archetype"}})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	model = newModel.(Model)
+
+	// The office lights lose their room
+	for _, room := range model.rooms {
+		if room.ID == "room-office" {
+			room.ID, room.Name, room.GroupedLightID = api.OtherRoomID, "Other Lights", ""
+		}
+	}
+	model.mainScreen.SetData(model.rooms, model.scenes)
+
+	view := model.View()
+	for _, want := range []string{"Other Lights · Plugs", "Other Lights · Fixtures", "Other Lights · Unknown type"} {
+		if !contains(view, want) {
+			t.Errorf("Expected a %q group, got:\n%s", want, view)
+		}
+	}
+
+	model.mainScreen.SetOtherGrouping("device")
+	if view := model.View(); !contains(view, "Other Lights · Salt Lamp") || contains(view, "Other Lights · Plugs") {
+		t.Errorf("Expected a group per device, got:\n%s", view)
+	}
+
+	model.mainScreen.SetOtherGrouping("")
+	if view := model.View(); !contains(view, "Other Lights") || contains(view, "Other Lights ·") {
+		t.Errorf("Expected a single Other Lights room, got:\n%s", view)
+	}
+}
+
+func TestBridgePickerAddBridge(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg := &config.Config{
+		Bridges: []config.BridgeConfig{
+			{Host: "10.0.0.1", Username: "key1", BridgeID: "bridge1"},
+			{Host: "10.0.0.2", Username: "key2", BridgeID: "bridge2"},
+		},
+		LastBridgeID: "bridge1",
+	}
+	model := NewModel(cfg, false)
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+	press := func(key string) tea.Cmd {
+		if key == "esc" {
+			return update(tea.KeyMsg{Type: tea.KeyEsc})
+		}
+		if key == "enter" {
+			return update(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+		return update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	update(messages.ShowBridgesMsg{})
+	if view := model.View(); !contains(view, "10.0.0.1") || !contains(view, "10.0.0.2") || !contains(view, "in use") {
+		t.Errorf("Expected both bridges in the picker, got:\n%s", view)
+	}
+
+	// Switching to the second bridge
+	press("j")
+	update(press("enter")())
+	if model.bridge.BridgeID() != "bridge2" || cfg.LastBridgeID != "bridge2" || model.screen != ScreenMain {
+		t.Errorf("Expected to switch to bridge2, got %s (last %s)", model.bridge.BridgeID(), cfg.LastBridgeID)
+	}
+
+	// Adding a third bridge keeps the current one
+	update(messages.ShowBridgesMsg{})
+	update(press("a")())
+	if model.screen != ScreenSetup {
+		t.Fatal("Expected the setup screen to add a bridge")
+	}
+	added := update(screens.PairingSuccessMsg{Bridge: api.NewHueBridge("10.0.0.3", "key3", "bridge3"), AppKey: "key3"})
+	update(added())
+	if len(cfg.Bridges) != 3 || cfg.Bridges[2].Username != "key3" || cfg.LastBridgeID != "bridge2" {
+		t.Errorf("Expected the new bridge to be saved without switching, got %+v (last %s)", cfg.Bridges, cfg.LastBridgeID)
+	}
+	if view := model.View(); !contains(view, "Added bridge 10.0.0.3") || !contains(view, "enter switch to it") {
+		t.Errorf("Expected to be asked whether to switch, got:\n%s", view)
+	}
+	update(press("esc")())
+	if model.screen != ScreenMain || model.bridge.BridgeID() != "bridge2" {
+		t.Errorf("Expected to keep bridge2, got %s on screen %d", model.bridge.BridgeID(), model.screen)
+	}
+}
+
+// recallBridge is a demo bridge recording the scenes recalled over a
+// duration
+type recallBridge struct {
+	*api.DemoBridge
+	mu     sync.Mutex
+	recall map[string]time.Duration
+}
+
+func (b *recallBridge) ActivateSceneOver(ctx context.Context, sceneID string, duration time.Duration) error {
+	b.mu.Lock()
+	b.recall[sceneID] = duration
+	b.mu.Unlock()
+	return b.DemoBridge.ActivateSceneOver(ctx, sceneID, duration)
+}
+
+func TestSceneTransitionOverride(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	demo, ok := model.bridge.(*api.DemoBridge)
+	if !ok {
+		t.Fatalf("Expected the demo bridge, got %T", model.bridge)
+	}
+	bridge := &recallBridge{DemoBridge: demo, recall: make(map[string]time.Duration)}
+	model.bridge = bridge
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+
+	update(messages.ShowScenesMsg{})
+	update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if !contains(model.View(), "Recall over:") {
+		t.Fatal("Expected the recall duration input")
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	transition, ok := update(tea.KeyMsg{Type: tea.KeyEnter})().(messages.SceneTransitionMsg)
+	if !ok || transition.Duration != 5*time.Second {
+		t.Fatalf("Expected a 5s recall duration, got %+v", transition)
+	}
+	update(transition)
+	if !contains(model.View(), "⏱ 5s") {
+		t.Errorf("Expected the duration next to the scene, got:\n%s", model.View())
+	}
+
+	// Recalling the scene uses the duration, other scenes the bridge default
+	model.activateSceneCmd(transition.SceneID)()
+	for _, scene := range model.scenes {
+		if scene.ID != transition.SceneID {
+			model.activateSceneCmd(scene.ID)()
+			break
+		}
+	}
+	if len(bridge.recall) != 1 || bridge.recall[transition.SceneID] != 5*time.Second {
+		t.Errorf("Expected only the scene to be recalled over 5s, got %v", bridge.recall)
+	}
+
+	// An empty duration goes back to the bridge default
+	update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	for range 2 {
+		update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	update(update(tea.KeyMsg{Type: tea.KeyEnter})())
+	if len(model.sceneTransitions()) != 0 || contains(model.View(), "⏱") {
+		t.Errorf("Expected the duration to be cleared, got %v", model.sceneTransitions())
+	}
+
+	// alt+enter fades the scene in slowly, over the default 30s
+	activated, ok := update(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})().(messages.SceneActivatedMsg)
+	if !ok || !activated.Fade {
+		t.Fatalf("Expected a faded activation, got %+v", activated)
+	}
+	update(activated)()
+	if bridge.recall[activated.SceneID] != 30*time.Second {
+		t.Errorf("Expected the scene to fade in over 30s, got %v", bridge.recall[activated.SceneID])
+	}
+	if !contains(model.View(), "over 30s") {
+		t.Error("Expected a notice of the fade")
+	}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	newModel, _ := model.Update(drainFetch(model.fetchDataCmd()))
+	model = newModel.(Model)
+	update := func(msg tea.Msg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+
+	// The narrowest help line still points to the overlay
+	update(tea.WindowSizeMsg{Width: 50, Height: 30})
+	if !contains(model.View(), "? help") {
+		t.Errorf("Expected ? in the narrow help line, got:\n%s", model.View())
+	}
+
+	update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	view := model.View()
+	for _, want := range []string{"Keyboard shortcuts", "Navigation", "Light control", "Color", "Rooms", "Scenes", "App", "fade room", "home/end", "go to devices"} {
+		if !contains(view, want) {
+			t.Errorf("Expected %q in the help overlay, got:\n%s", want, view)
+		}
+	}
+
+	// Keys don't reach the lights while it is open
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(tea.KeyMsg{Type: tea.KeyEsc})
+	if view := model.View(); contains(view, "Keyboard shortcuts") || !contains(view, "Living Room") {
+		t.Errorf("Expected esc to close the overlay, got:\n%s", view)
+	}
+}
+
+func TestRowCacheRedrawsChangedRows(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	newModel, _ := model.Update(drainFetch(model.fetchDataCmd()))
+	model = newModel.(Model)
+	update := func(msg tea.Msg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	update(tea.WindowSizeMsg{Width: 120, Height: 60})
+
+	view := model.View()
+	if again := model.View(); again != view {
+		t.Fatal("Expected the same view from the cached rows")
+	}
+
+	// A change reported by the bridge redraws the light and its room
+	room := model.rooms[0]
+	light := room.Lights[0]
+	on, brightness := true, 37
+	update(messages.LightUpdateMsg{LightID: light.ID, On: &on, Brightness: &brightness})
+	view = model.View()
+	if !contains(view, "37%") {
+		t.Errorf("Expected the new brightness of %s, got:\n%s", light.Name, view)
+	}
+
+	// So do the selection, the marks and the brightness format
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !contains(model.View(), "✓") {
+		t.Errorf("Expected the marked light, got:\n%s", model.View())
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#")})
+	if view := model.View(); contains(view, "37%") || !contains(view, fmt.Sprintf("%d", light.Brightness)) {
+		t.Errorf("Expected brightness on the 0-254 scale, got:\n%s", view)
+	}
+
+	// And the width
+	update(tea.WindowSizeMsg{Width: 70, Height: 60})
+	for _, line := range strings.Split(model.View(), "\n") {
+		if w := lipgloss.Width(line); w > 70 {
+			t.Errorf("Expected rows narrowed to 70 columns, got %d: %q", w, line)
+		}
+	}
+}
+
+func TestGamutWarning(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	newModel, _ := model.Update(drainFetch(model.fetchDataCmd()))
+	model = newModel.(Model)
+	update := func(msg tea.Msg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	sel := func(id string) {
+		update(tea.KeyMsg{Type: tea.KeyHome})
+		for range 50 {
+			if selected := model.mainScreen.SelectedLight(); selected != nil && selected.ID == id && !model.mainScreen.IsRoomSelected() {
+				return
+			}
+			update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		}
+		t.Fatalf("Light %s not found", id)
+	}
+
+	sel("light-of-desk")
+	if !contains(model.View(), "Gamut: C") {
+		t.Errorf("Expected the gamut in the panel, got:\n%s", model.View())
+	}
+
+	// Turning the hue of a gamut B bulb towards green goes beyond it
+	sel("light-of-bookshelf")
+	if view := model.View(); !contains(view, "Gamut: B") || contains(view, "beyond the gamut") {
+		t.Fatalf("Expected a color within gamut B, got:\n%s", view)
+	}
+	warned := false
+	for range 18 {
+		update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+		if contains(model.View(), "can't show this color (gamut B)") {
+			warned = true
+			break
+		}
+	}
+	if !warned {
+		t.Fatal("Expected a warning once the color left gamut B")
+	}
+	if !contains(model.View(), "beyond the gamut") {
+		t.Errorf("Expected the panel to flag the color, got:\n%s", model.View())
+	}
+}
+
+func TestErrorToasts(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	height := lipgloss.Height(model.View())
+
+	// Errors once the lights are loaded don't take over the screen
+	sceneErr := errors.New("failed to activate scene: API error (status 500)")
+	newModel, cmd := model.Update(messages.ErrorMsg{Err: sceneErr})
+	model = newModel.(Model)
+	if model.err != nil {
+		t.Fatal("Expected a toast instead of the error panel")
+	}
+	if cmd == nil || !contains(model.View(), "activate scene") {
+		t.Fatal("Expected the toast to be shown and expire")
+	}
+	if got := lipgloss.Height(model.View()); got != height {
+		t.Errorf("Expected the toast over the screen, got %d lines instead of %d", got, height)
+	}
+	model.handleToastTick(time.Now().Add(10 * time.Second))
+	if contains(model.View(), "activate scene") {
+		t.Error("Expected the toast to expire")
+	}
+
+	// Only a few are shown at once, the others wait for their turn
+	for i := range 5 {
+		model.notify(components.ToastWarning, fmt.Sprintf("warning %d", i))
+	}
+	if len(model.toasts.shown) != maxVisibleToasts || len(model.toasts.waiting) != 2 {
+		t.Errorf("Expected %d toasts shown and 2 waiting, got %d and %d", maxVisibleToasts, len(model.toasts.shown), len(model.toasts.waiting))
+	}
+	model.handleToastTick(time.Now().Add(6 * time.Second))
+	if view := model.View(); !contains(view, "warning 4") || contains(view, "warning 0") {
+		t.Error("Expected the waiting toasts to replace the expired ones")
+	}
+
+	// Past toasts can be reviewed
+	newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")})
+	model = newModel.(Model)
+	newModel, _ = model.Update(cmd())
+	model = newModel.(Model)
+	if model.screen != ScreenNotifications || len(model.toasts.shown) != 0 {
+		t.Fatal("Expected the notifications screen, without toasts over it")
+	}
+	view := model.View()
+	if !contains(view, "activate scene") || !contains(view, "warning 0") {
+		t.Error("Expected the past toasts on the notifications screen")
+	}
+	newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	model = newModel.(Model)
+	newModel, _ = model.Update(cmd())
+	model = newModel.(Model)
+	if len(model.toasts.history) != 0 || contains(model.View(), "warning 0") {
+		t.Error("Expected c to clear the notifications")
+	}
+}
+
+func TestLitRoomsFilter(t *testing.T) {
+	model := NewModel(&config.Config{}, true)
+	dataMsg, ok := drainFetch(model.fetchDataCmd()).(messages.DataFetchedMsg)
+	if !ok {
+		t.Fatal("Expected DataFetchedMsg")
+	}
+	var dark, lit *models.Room
+	for _, room := range dataMsg.Rooms {
+		room.UpdateState()
+		if !room.AnyOn {
+			continue
+		}
+		if dark == nil {
+			dark = room
+			for _, light := range room.Lights {
+				light.On = false
+			}
+			room.UpdateState()
+		} else if lit == nil {
+			lit = room
+		}
+	}
+	if dark == nil || lit == nil {
+		t.Fatal("Expected two demo rooms with lights on")
+	}
+	newModel, _ := model.Update(dataMsg)
+	model = newModel.(Model)
+	newModel, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+	if !contains(model.View(), dark.Name) {
+		t.Fatalf("Expected %s to be listed", dark.Name)
+	}
+
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	model = newModel.(Model)
+	view := model.View()
+	if contains(view, dark.Name) || !contains(view, lit.Name) || !contains(view, "lit rooms only") {
+		t.Errorf("Expected only the rooms with lights on, got:\n%s", view)
+	}
+
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	model = newModel.(Model)
+	if !contains(model.View(), dark.Name) {
+		t.Errorf("Expected o again to list %s", dark.Name)
+	}
+}
+
+func TestRoomDimmingCommit(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{Preferences: config.Preferences{RoomDimming: "commit"}})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = newModel.(Model)
+
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+
+	room := model.mainScreen.SelectedRoom()
+	if room == nil || !model.mainScreen.IsRoomSelected() {
+		t.Fatal("Expected a room to be selected")
+	}
+	for _, light := range room.Lights {
+		light.On = true
+		light.SetBrightnessPct(50)
+	}
+	room.UpdateState()
+
+	// Keys only move the target shown in the header
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	if got := room.Lights[0].BrightnessPct(); got != 50 {
+		t.Errorf("Expected the lights to wait for the commit, got %d%%", got)
+	}
+	if !contains(model.View(), "→ 70%") {
+		t.Error("Expected the target in the room header")
+	}
+
+	// esc drops the preview
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if contains(model.View(), "→ 70%") {
+		t.Error("Expected esc to drop the preview")
+	}
+
+	// enter sends it at once
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("Expected the grouped command to be sent")
+	}
+	for _, light := range room.Lights {
+		if !light.OnOffOnly && light.BrightnessPct() != 40 {
+			t.Errorf("Expected %s at 40%%, got %d%%", light.Name, light.BrightnessPct())
+		}
+	}
+	if contains(model.View(), "→ 40%") {
+		t.Error("Expected the preview to end once sent")
+	}
+}
+
+func TestBridgeInfo(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	newModel, cmd := model.Update(messages.ShowBridgesMsg{})
+	model = newModel.(Model)
+	if model.screen != ScreenBridges {
+		t.Fatal("Expected the bridges screen in demo mode")
+	}
+	if !contains(model.View(), "Loading") {
+		t.Error("Expected the bridge details to be loading")
+	}
+	if contains(model.View(), "Add bridge") {
+		t.Error("Expected no pairing in demo mode")
+	}
+
+	infoMsg, ok := cmd().(messages.BridgeInfoFetchedMsg)
+	if !ok || infoMsg.Err != nil {
+		t.Fatalf("Expected BridgeInfoFetchedMsg, got %+v", infoMsg)
+	}
+	newModel, _ = model.Update(infoMsg)
+	model = newModel.(Model)
+	view := model.View()
+	for _, want := range []string{"BSB002", "channel 25", "Up to date", "not available"} {
+		if !contains(view, want) {
+			t.Errorf("Expected %q in the bridge details", want)
+		}
+	}
+}
+
+func TestSceneCleanup(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	newModel, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = newModel.(Model)
+
+	// update runs a message and the messages of the commands it returns,
+	// until none is left
+	var update func(msg tea.Msg)
+	update = func(msg tea.Msg) {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		if cmd == nil {
+			return
+		}
+		if next := cmd(); next != nil {
+			update(next)
+		}
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	update(messages.ShowSceneCleanupMsg{})
+	if model.screen != ScreenSceneCleanup {
+		t.Fatal("Expected the scene cleanup")
+	}
+	view := model.View()
+	for _, name := range []string{"Focus", "Energize", "Reading", "Morning"} {
+		if !contains(view, name) {
+			t.Errorf("Expected %s, unused for over 90 days or never, to be listed", name)
+		}
+	}
+	for _, name := range []string{"Cooking", "Relax"} {
+		if contains(view, name) {
+			t.Errorf("Expected %s, used recently, not to be listed", name)
+		}
+	}
+
+	// Mark all, then confirm
+	update(runes("a"))
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !contains(model.View(), "Delete 4 scenes?") {
+		t.Fatal("Expected a confirmation")
+	}
+	update(runes("y"))
+	if !contains(model.View(), "Deleted 4 of 4 scenes") {
+		t.Error("Expected the batch to be reported")
+	}
+	for _, scene := range model.scenes {
+		if scene.Name == "Focus" || scene.Name == "Energize" || scene.Name == "Reading" || scene.Name == "Morning" {
+			t.Errorf("Expected %s to be deleted", scene.Name)
+		}
+	}
+	if len(model.scenes) != 5 {
+		t.Errorf("Expected 4 scenes and the smart scene left, got %d", len(model.scenes))
+	}
+}
+
+func TestRememberColors(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RememberColors = true
+	model := newLoadedModel(t, cfg)
+
+	// send updates the model and runs the commands it returns, feeding
+	// the remembered colors back
+	var send func(msg tea.Msg)
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				run(c)
+			}
+		case messages.LightMemoryMsg:
+			send(msg)
+		}
+	}
+	send = func(msg tea.Msg) {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		run(cmd)
+	}
+	light := selectLight(t, &model, "light-lr-tv-bias")
+	brightness, x, y := light.BrightnessPct(), light.Color.X, light.Color.Y
+
+	send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if light.On {
+		t.Fatal("Expected space to turn the light off")
+	}
+	mem, ok := model.lightMemory()["light-lr-tv-bias"]
+	if !ok || mem.Brightness != brightness || mem.X != x || mem.Y != y {
+		t.Fatalf("Expected %d%% at %.2f,%.2f to be remembered, got %+v", brightness, x, y, mem)
+	}
+
+	// The bridge changed the light while it was off
+	light.SetBrightnessPct(100)
+	light.Color.Mode = models.ColorModeColorTemp
+	light.Color.Mirek = 250
+	send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if !light.On || light.BrightnessPct() != brightness {
+		t.Errorf("Expected the light back on at %d%%, got on=%v at %d%%", brightness, light.On, light.BrightnessPct())
+	}
+	if light.Color.Mode != models.ColorModeXY || light.Color.X != x || light.Color.Y != y {
+		t.Errorf("Expected the light back at %.2f,%.2f, got %+v", x, y, light.Color)
+	}
+	// Echoes of the light coming on don't bring the bridge's color back
+	if op := model.pending.compounds[light.ID]; op == nil || op.Targets["on"] != true || op.Targets["color_xy"] == nil {
+		t.Errorf("Expected on and the color to be pending together, got %+v", op)
+	}
+
+	// Without the option, nothing is remembered
+	cfg = &config.Config{}
+	model = newLoadedModel(t, cfg)
+	send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if len(model.lightMemory()) != 0 {
+		t.Errorf("Expected no remembered colors, got %v", model.lightMemory())
+	}
+}
+
+func TestColorWheel(t *testing.T) {
+	model := newLoadedModel(t, &config.Config{})
+	update := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := model.Update(msg)
+		model = newModel.(Model)
+		return cmd
+	}
+	alt := func(k string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k), Alt: true}
+	}
+	update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	light := selectLight(t, &model, "light-lr-tv-bias")
+	if !contains(model.View(), "alt+hjkl color wheel") {
+		t.Fatal("Expected the color wheel in the side panel")
+	}
+
+	// Moving the cursor only previews the color
+	color := *light.Color
+	update(alt("k"))
+	update(alt("l"))
+	if !contains(model.View(), "Preview:") {
+		t.Fatal("Expected a preview of the cursor color")
+	}
+	if *light.Color != color {
+		t.Error("Expected the light unchanged before enter")
+	}
+	update(tea.KeyMsg{Type: tea.KeyEsc})
+	if contains(model.View(), "Preview:") || *light.Color != color {
+		t.Error("Expected esc to close the preview and keep the light")
+	}
+
+	// The cursor stops at the edge of the wheel, fully saturated
+	for range 20 {
+		update(alt("l"))
+	}
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+	if light.Color.Mode != models.ColorModeHS {
+		t.Fatalf("Expected the wheel color to be set, got %+v", light.Color)
+	}
+	if sat := models.LevelToPct(light.Color.Saturation); sat < 90 {
+		t.Errorf("Expected a saturated color at the edge, got %d%%", sat)
 	}
-	return b
 }
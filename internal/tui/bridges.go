@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/screens"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showBridges opens the bridge picker with the configured bridges, and
+// fetches the system details of the bridge in use
+func (m *Model) showBridges() tea.Cmd {
+	var entries []screens.BridgeEntry
+	current := ""
+	if m.bridge != nil {
+		current = m.bridge.BridgeID()
+	}
+	if m.demoMode {
+		entries = []screens.BridgeEntry{{Host: m.bridge.Host(), BridgeID: current}}
+	} else {
+		for _, b := range m.config.Bridges {
+			entries = append(entries, screens.BridgeEntry{Host: b.Host, BridgeID: b.BridgeID})
+		}
+	}
+	m.bridgesScreen.SetBridges(entries, current, !m.demoMode)
+	m.bridgesScreen.SetInfoLoading()
+	m.updateBridgeStream()
+	m.screen = ScreenBridges
+	return m.fetchBridgeInfoCmd()
+}
+
+// updateBridgeStream shows the state of the event stream in the bridge
+// details
+func (m *Model) updateBridgeStream() {
+	if m.events == nil {
+		m.bridgesScreen.SetStream(false, time.Time{})
+		return
+	}
+	m.bridgesScreen.SetStream(true, m.events.Stats().ConnectedAt)
+}
+
+// fetchBridgeInfoCmd fetches the system details of the bridge in use
+func (m Model) fetchBridgeInfoCmd() tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.BridgeInfoFetchedMsg{Err: config.ErrNoBridges}
+		}
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		info, err := bridge.GetBridgeInfo(ctx)
+		return messages.BridgeInfoFetchedMsg{Info: info, Err: err}
+	}
+}
+
+// switchToBridge connects to the configured bridge with the given ID
+func (m *Model) switchToBridge(bridgeID string) tea.Cmd {
+	bridgeCfg, err := m.config.GetBridge(bridgeID)
+	if err != nil {
+		m.err = err
+		m.screen = ScreenMain
+		return nil
+	}
+	return m.connectBridge(bridgeCfg)
+}
+
+// startAddingBridge opens the setup screen to pair one more bridge, while
+// the current one stays connected
+func (m *Model) startAddingBridge() tea.Cmd {
+	paired := make([]string, 0, len(m.config.Bridges))
+	for _, b := range m.config.Bridges {
+		paired = append(paired, b.BridgeID)
+	}
+	m.setupScreen = screens.NewSetupModel()
+	m.setupScreen.SetAdding(paired)
+	m.setupScreen.SetSize(m.width, m.height)
+	m.screen = ScreenSetup
+	return m.setupScreen.Init()
+}
+
+// bridgeAdded saves a bridge paired from the bridge picker, keeping the
+// current bridge in use
+func (m *Model) bridgeAdded(msg messages.BridgeAddedMsg) {
+	m.config.AddBridge(config.BridgeConfig{
+		Host:     msg.Bridge.Host(),
+		Username: msg.AppKey,
+		BridgeID: msg.Bridge.BridgeID(),
+	})
+	if err := m.config.Save(); err != nil {
+		m.reportError(err)
+	}
+}
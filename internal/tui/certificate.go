@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// certAlert describes a bridge certificate that no longer matches its pin
+type certAlert struct {
+	Expected string
+	Got      string
+}
+
+// newCertAlert captures the pinned and presented fingerprints
+func (m *Model) newCertAlert() *certAlert {
+	alert := &certAlert{}
+	if hueBridge, ok := m.bridge.(*api.HueBridge); ok {
+		alert.Got = hueBridge.CertFingerprint()
+		if bridgeCfg, err := m.config.GetBridge(hueBridge.BridgeID()); err == nil {
+			alert.Expected = bridgeCfg.CertFingerprint
+		}
+	}
+	return alert
+}
+
+// View renders the certificate alert
+func (a *certAlert) View() string {
+	var b strings.Builder
+	b.WriteString(styles.StyleError.Render("  ⚠ The bridge certificate has changed!"))
+	b.WriteString("\n\n")
+	b.WriteString("  This can happen after a bridge reset or replacement, but may also\n")
+	b.WriteString("  mean another device is impersonating your bridge.\n\n")
+	b.WriteString(fmt.Sprintf("  Pinned:    %s\n", formatFingerprint(a.Expected)))
+	b.WriteString(fmt.Sprintf("  Presented: %s\n\n", formatFingerprint(a.Got)))
+	b.WriteString(styles.StyleHelp.Render("  Press T to trust the new certificate, ctrl+c to quit"))
+	return b.String()
+}
+
+// formatFingerprint groups a hex fingerprint for readability
+func formatFingerprint(fp string) string {
+	if fp == "" {
+		return "unknown"
+	}
+	var parts []string
+	for i := 0; i < len(fp); i += 4 {
+		end := i + 4
+		if end > len(fp) {
+			end = len(fp)
+		}
+		parts = append(parts, fp[i:end])
+	}
+	return strings.Join(parts, ":")
+}
+
+// trustCertificate pins the certificate currently presented by the bridge
+// and reconnects
+func (m *Model) trustCertificate() tea.Cmd {
+	alert := m.certAlert
+	hueBridge, ok := m.bridge.(*api.HueBridge)
+	if !ok || alert.Got == "" {
+		// Nothing to trust until the bridge has presented a certificate
+		return nil
+	}
+	m.certAlert = nil
+	m.err = nil
+
+	bridgeCfg, err := m.config.GetBridge(hueBridge.BridgeID())
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	bridgeCfg.CertFingerprint = alert.Got
+	if err := m.config.Save(); err != nil {
+		m.reportError(err)
+		return nil
+	}
+	tuiLog.Infof("Trusted new certificate %s for bridge %s", alert.Got, bridgeCfg.BridgeID)
+	m.applyTLSPolicy()
+
+	m.mainScreen.SetLoading(true)
+	return tea.Batch(m.mainScreen.Init(), func() tea.Msg { return messages.RefreshMsg{} })
+}
+
+// applyTLSPolicy applies the configured certificate policy to the bridge
+func (m *Model) applyTLSPolicy() {
+	hueBridge, ok := m.bridge.(*api.HueBridge)
+	if !ok {
+		return
+	}
+	bridgeCfg, err := m.config.GetBridge(hueBridge.BridgeID())
+	if err != nil {
+		return
+	}
+	policy, err := api.PolicyFor(bridgeCfg.TLSMode, bridgeCfg.CertFingerprint, m.config.CAFile)
+	if err != nil {
+		m.err = err
+		return
+	}
+	hueBridge.SetTLSPolicy(policy)
+}
+
+// pinCertificate stores the bridge certificate fingerprint the first time
+// a bridge in trust-on-first-use mode is reached
+func (m *Model) pinCertificate() {
+	hueBridge, ok := m.bridge.(*api.HueBridge)
+	if !ok || m.config == nil {
+		return
+	}
+	bridgeCfg, err := m.config.GetBridge(hueBridge.BridgeID())
+	if err != nil || bridgeCfg.CertFingerprint != "" {
+		return
+	}
+	if mode := api.TLSMode(bridgeCfg.TLSMode); mode != "" && mode != api.TLSModeTOFU {
+		return
+	}
+	fp := hueBridge.CertFingerprint()
+	if fp == "" {
+		return
+	}
+	bridgeCfg.CertFingerprint = fp
+	tuiLog.Infof("Pinned certificate %s for bridge %s", fp, bridgeCfg.BridgeID)
+	if err := m.config.Save(); err != nil {
+		m.reportError(err)
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+)
+
+// coalesceWindow is how long rapid changes to a light are gathered before
+// the latest one is sent
+const coalesceWindow = 100 * time.Millisecond
+
+// writeCoalescer sends at most one write per light and field every window,
+// with the latest value. Holding an arrow key would otherwise send a
+// request per key repeat and overflow the bridge's queue.
+type writeCoalescer struct {
+	mu     sync.Mutex
+	window time.Duration
+	// Timer the window is waited with, time.After outside of tests
+	after func(time.Duration) <-chan time.Time
+	// Latest write waiting to be sent, by light ID and field
+	waiting map[string]func(ctx context.Context) error
+}
+
+func newWriteCoalescer(window time.Duration) *writeCoalescer {
+	return &writeCoalescer{
+		window:  window,
+		after:   time.After,
+		waiting: make(map[string]func(ctx context.Context) error),
+	}
+}
+
+// write queues a write. The first write of a burst waits for the window
+// and sends the latest queued value; later ones only replace that value
+// and return right away.
+func (c *writeCoalescer) write(ctx context.Context, key string, send func(ctx context.Context) error) error {
+	c.mu.Lock()
+	_, queued := c.waiting[key]
+	c.waiting[key] = send
+	c.mu.Unlock()
+	if queued {
+		return nil
+	}
+
+	select {
+	case <-c.after(c.window):
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.waiting, key)
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+
+	c.mu.Lock()
+	latest := c.waiting[key]
+	delete(c.waiting, key)
+	c.mu.Unlock()
+	return latest(ctx)
+}
+
+// coalescingBridge coalesces brightness and color writes. Color
+// temperature, xy and hue/saturation share a key, as the latest color
+// wins whatever its mode.
+type coalescingBridge struct {
+	api.BridgeClient
+	coalescer *writeCoalescer
+}
+
+func (b coalescingBridge) SetLightBrightness(ctx context.Context, lightID string, brightness int) error {
+	return b.coalescer.write(ctx, lightID+":brightness", func(ctx context.Context) error {
+		return b.BridgeClient.SetLightBrightness(ctx, lightID, brightness)
+	})
+}
+
+func (b coalescingBridge) SetLightColorTemp(ctx context.Context, lightID string, mirek int) error {
+	return b.coalescer.write(ctx, lightID+":color", func(ctx context.Context) error {
+		return b.BridgeClient.SetLightColorTemp(ctx, lightID, mirek)
+	})
+}
+
+func (b coalescingBridge) SetLightColorXY(ctx context.Context, lightID string, x, y float64) error {
+	return b.coalescer.write(ctx, lightID+":color", func(ctx context.Context) error {
+		return b.BridgeClient.SetLightColorXY(ctx, lightID, x, y)
+	})
+}
+
+func (b coalescingBridge) SetLightColorHS(ctx context.Context, lightID string, hue uint16, sat uint8) error {
+	return b.coalescer.write(ctx, lightID+":color", func(ctx context.Context) error {
+		return b.BridgeClient.SetLightColorHS(ctx, lightID, hue, sat)
+	})
+}
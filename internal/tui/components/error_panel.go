@@ -0,0 +1,66 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrorAction is a key offered to recover from an error
+type ErrorAction struct {
+	Key   string
+	Label string
+}
+
+// ErrorPanel describes an error for RenderErrorPanel
+type ErrorPanel struct {
+	// What kind of failure it is, like "Connection" or "Authorization"
+	Category string
+	// What was being done when it failed, may be empty
+	Operation string
+	Message   string
+	Hint      string
+	Actions   []ErrorAction
+}
+
+// RenderErrorPanel renders an error with its recovery actions
+func RenderErrorPanel(width int, p ErrorPanel) string {
+	var b strings.Builder
+
+	title := "⚠ " + p.Category + " error"
+	if p.Operation != "" {
+		title += " while trying to " + p.Operation
+	}
+	b.WriteString(styles.StyleError.Render(title))
+	b.WriteString("\n\n")
+	b.WriteString(p.Message)
+	b.WriteString("\n")
+	if p.Hint != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.StyleTextMuted.Render(p.Hint))
+		b.WriteString("\n")
+	}
+
+	var keys []string
+	for _, a := range p.Actions {
+		keys = append(keys, styles.StyleHelpKey.Render(a.Key)+" "+a.Label)
+	}
+	keys = append(keys, styles.StyleHelpKey.Render("esc")+" dismiss")
+	b.WriteString("\n")
+	b.WriteString(styles.StyleHelp.Render(strings.Join(keys, " • ")))
+
+	panelWidth := width - 4
+	if panelWidth > 80 {
+		panelWidth = 80
+	}
+	if panelWidth < 30 {
+		panelWidth = 30
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorError).
+		Padding(0, 1).
+		Width(panelWidth).
+		Render(b.String())
+}
@@ -130,6 +130,9 @@ func RenderLightList(lights []*models.Light, selectedID string, width int) strin
 }
 
 func truncate(s string, maxLen int) string {
+	if maxLen <= 0 {
+		return ""
+	}
 	if len(s) <= maxLen {
 		return s
 	}
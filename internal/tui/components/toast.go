@@ -0,0 +1,64 @@
+package components
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ToastSeverity tells how serious a toast is, which sets its color and how
+// long it stays
+type ToastSeverity int
+
+const (
+	ToastInfo ToastSeverity = iota
+	ToastWarning
+	ToastError
+)
+
+// Toast is a short-lived notification
+type Toast struct {
+	Severity ToastSeverity
+	Message  string
+	At       time.Time
+}
+
+// Duration is how long a toast stays on screen
+func (t Toast) Duration() time.Duration {
+	switch t.Severity {
+	case ToastError:
+		return 8 * time.Second
+	case ToastWarning:
+		return 5 * time.Second
+	}
+	return 3 * time.Second
+}
+
+// ToastIcon returns the icon of a severity, in its color
+func ToastIcon(severity ToastSeverity) string {
+	switch severity {
+	case ToastError:
+		return lipgloss.NewStyle().Foreground(styles.ColorError).Render("✗")
+	case ToastWarning:
+		return lipgloss.NewStyle().Foreground(styles.ColorWarning).Render("⚠")
+	}
+	return lipgloss.NewStyle().Foreground(styles.ColorInfo).Render("ℹ")
+}
+
+// RenderToast renders a toast on one line, cut to width
+func RenderToast(width int, t Toast) string {
+	color := styles.ColorInfo
+	switch t.Severity {
+	case ToastError:
+		color = styles.ColorError
+	case ToastWarning:
+		color = styles.ColorWarning
+	}
+	line := ToastIcon(t.Severity) + " " + lipgloss.NewStyle().Foreground(color).Render(t.Message)
+	return lipgloss.NewStyle().
+		Background(styles.ColorSurface).
+		Padding(0, 1).
+		MaxWidth(max(10, width)).
+		Render(line)
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/hooks"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reconnectInterval is how often data is refetched while the bridge is
+// unreachable, to notice when it comes back
+const reconnectInterval = 5 * time.Second
+
+// handleConnection forwards bridge connectivity changes to the event channel
+func (m Model) handleConnection(status api.ConnectionStatus) {
+	select {
+	case m.eventChan <- messages.ConnectionStatusMsg{Status: status}:
+	default:
+		debugf("Channel full, dropped connection status")
+	}
+}
+
+// handleThrottle forwards the number of throttled commands to the event
+// channel
+func (m Model) handleThrottle(queued int) {
+	select {
+	case m.eventChan <- messages.ThrottleMsg{Queued: queued}:
+	default:
+		debugf("Channel full, dropped throttle status")
+	}
+}
+
+// handleConnectionStatus shows or clears the offline banner. Events may have
+// been missed while offline, so everything is refetched on reconnect.
+func (m *Model) handleConnectionStatus(status api.ConnectionStatus) tea.Cmd {
+	tuiLog.Infof("Connection status: online=%v err=%v", status.Online, status.Err)
+	if m.events != nil {
+		stats := m.events.Stats()
+		debugf("Event stream: %d connects, %d drops, %d idle drops, last data at %s",
+			stats.Connects, stats.Drops, stats.IdleDrops, stats.LastData.Format(time.TimeOnly))
+	}
+	if !status.Online {
+		if m.offline {
+			return nil
+		}
+		m.offline = true
+		m.fireConnection(hooks.BridgeDisconnected, status.Err)
+		m.retryAt = time.Now().Add(reconnectInterval)
+		m.mainScreen.SetOffline(true, reconnectInterval)
+		return tea.Batch(connectionTick(), m.alert("Bridge disconnected"))
+	}
+
+	if !m.offline {
+		return nil
+	}
+	m.offline = false
+	m.fireConnection(hooks.BridgeReconnected, nil)
+	m.err = nil
+	m.mainScreen.SetOffline(false, 0)
+	return m.fetchDataCmd()
+}
+
+// handleConnectionTick counts down to the next retry and retries
+func (m *Model) handleConnectionTick() tea.Cmd {
+	if !m.offline {
+		return nil
+	}
+	cmds := []tea.Cmd{connectionTick()}
+	if !time.Now().Before(m.retryAt) {
+		m.retryAt = time.Now().Add(reconnectInterval)
+		cmds = append(cmds, m.fetchDataCmd())
+	}
+	m.mainScreen.SetOffline(true, time.Until(m.retryAt))
+	return tea.Batch(cmds...)
+}
+
+func connectionTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return messages.ConnectionTickMsg{} })
+}
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/logging"
+	"github.com/angristan/hue-tui/internal/tui/components"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/screens"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Error categories
+const (
+	errorConnection = "Connection"
+	errorAuth       = "Authorization"
+	errorSetup      = "Setup"
+	errorBridge     = "Bridge"
+	errorOther      = "Unexpected"
+)
+
+// Recovery actions, by key
+var (
+	actionRetry        = components.ErrorAction{Key: "r", Label: "retry"}
+	actionRepair       = components.ErrorAction{Key: "p", Label: "re-pair"}
+	actionSwitchBridge = components.ErrorAction{Key: "b", Label: "switch bridge"}
+	actionDebugLog     = components.ErrorAction{Key: "l", Label: "open log"}
+)
+
+// errorCategory tells what kind of failure an error is
+func errorCategory(err error) string {
+	var netErr net.Error
+	msg := err.Error()
+	switch {
+	case errors.Is(err, config.ErrNoBridges), errors.Is(err, config.ErrBridgeNotFound):
+		return errorSetup
+	case strings.Contains(msg, "status 401"), strings.Contains(msg, "status 403"),
+		strings.Contains(msg, "unauthorized user"):
+		return errorAuth
+	case errors.Is(err, api.ErrBridgeUnreachable), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr), strings.Contains(msg, "connection refused"),
+		strings.Contains(msg, "no route to host"):
+		return errorConnection
+	case strings.Contains(msg, "API error"):
+		return errorBridge
+	}
+	return errorOther
+}
+
+// errorOperation extracts the operation from the "failed to ...:" wrapping
+// of an error, and the rest of the message
+func errorOperation(err error) (operation, message string) {
+	msg := err.Error()
+	rest, ok := strings.CutPrefix(msg, "failed to ")
+	if !ok {
+		return "", msg
+	}
+	operation, cause, ok := strings.Cut(rest, ": ")
+	if !ok {
+		return rest, msg
+	}
+	return operation, cause
+}
+
+// errorPanel describes the current error and the actions that can fix it
+func (m Model) errorPanel() components.ErrorPanel {
+	category := errorCategory(m.err)
+	operation, message := errorOperation(m.err)
+	panel := components.ErrorPanel{Category: category, Operation: operation, Message: message}
+
+	switch category {
+	case errorConnection:
+		panel.Hint = "Check that the bridge is powered and on the same network."
+		panel.Actions = []components.ErrorAction{actionRetry}
+	case errorAuth:
+		panel.Hint = "The bridge no longer accepts this app key. Pair again to get a new one."
+		panel.Actions = []components.ErrorAction{actionRepair}
+	case errorSetup:
+		panel.Hint = "Pair a bridge to get started."
+		panel.Actions = []components.ErrorAction{actionRepair}
+	default:
+		panel.Actions = []components.ErrorAction{actionRetry}
+	}
+	if m.canSwitchBridge() && category != errorSetup {
+		panel.Actions = append(panel.Actions, actionSwitchBridge)
+	}
+	if logging.Enabled() {
+		panel.Actions = append(panel.Actions, actionDebugLog)
+	}
+	return panel
+}
+
+// handleErrorKey runs the recovery action of a key while an error is
+// shown. Other keys are ignored until the error is dismissed with esc.
+func (m *Model) handleErrorKey(key string) tea.Cmd {
+	if key == "esc" {
+		m.err = nil
+		return nil
+	}
+	for _, action := range m.errorPanel().Actions {
+		if action.Key != key {
+			continue
+		}
+		m.err = nil
+		switch action {
+		case actionRetry:
+			return func() tea.Msg { return messages.RefreshMsg{} }
+		case actionRepair:
+			return m.startPairing()
+		case actionSwitchBridge:
+			return m.switchBridge()
+		case actionDebugLog:
+			return openDebugLog()
+		}
+	}
+	return nil
+}
+
+// startPairing goes back to the setup screen to pair a bridge again
+func (m *Model) startPairing() tea.Cmd {
+	m.stopEvents()
+	m.setupScreen = screens.NewSetupModel()
+	m.setupScreen.SetSize(m.width, m.height)
+	m.screen = ScreenSetup
+	return m.setupScreen.Init()
+}
+
+// canSwitchBridge reports whether another bridge is configured
+func (m Model) canSwitchBridge() bool {
+	return !m.demoMode && m.config != nil && len(m.config.Bridges) > 1
+}
+
+// switchBridge connects to the configured bridge after the current one
+func (m *Model) switchBridge() tea.Cmd {
+	if !m.canSwitchBridge() {
+		return nil
+	}
+	next := 0
+	if m.bridge != nil {
+		for i, b := range m.config.Bridges {
+			if b.BridgeID == m.bridge.BridgeID() {
+				next = (i + 1) % len(m.config.Bridges)
+				break
+			}
+		}
+	}
+	return m.connectBridge(&m.config.Bridges[next])
+}
+
+// connectBridge connects to a configured bridge in place of the current one
+func (m *Model) connectBridge(bridgeCfg *config.BridgeConfig) tea.Cmd {
+	bridge, err := m.newBridge(bridgeCfg)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	m.stopEvents()
+	m.bridge = bridge
+	m.config.LastBridgeID = bridgeCfg.BridgeID
+	if err := m.config.Save(); err != nil {
+		m.reportError(err)
+	}
+	tuiLog.Infof("Switched to bridge %s (%s)", bridgeCfg.BridgeID, bridgeCfg.Host)
+
+	m.screen = ScreenMain
+	m.mainScreen.SetHomeGroup("")
+	m.mainScreen.SetNotice("Switched to bridge " + bridgeCfg.Host)
+	return func() tea.Msg { return messages.RefreshMsg{} }
+}
+
+// stopEvents stops the event subscription of the current bridge
+func (m *Model) stopEvents() {
+	if m.events == nil {
+		return
+	}
+	if err := m.events.Stop(); err != nil {
+		tuiLog.Warnf("Failed to stop events: %v", err)
+	}
+	m.events = nil
+}
+
+// openDebugLog shows the log in $PAGER
+func openDebugLog() tea.Cmd {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	return tea.ExecProcess(exec.Command(pager, logging.Path()), func(err error) tea.Msg {
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,501 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// eventToMsg converts a bridge event into the matching bubbletea message.
+// Returns nil for events the TUI doesn't care about.
+func eventToMsg(event api.Event) tea.Msg {
+	// Schedules are refetched whole on any change
+	switch event.Resource {
+	case "behavior_instance", "smart_scene":
+		return messages.SchedulesChangedMsg{Resource: event.Resource}
+	}
+
+	switch event.Type {
+	case api.EventTypeAdd:
+		return addEventToMsg(event)
+	case api.EventTypeDelete:
+		switch event.Resource {
+		case "light", "room", "scene":
+			return messages.ResourceDeletedMsg{Resource: event.Resource, ResourceID: event.ResourceID}
+		}
+		return nil
+	case api.EventTypeUpdate:
+	default:
+		return nil
+	}
+
+	switch event.Resource {
+	case "light":
+		update, err := api.ParseLightUpdate(event)
+		if err != nil {
+			debugf("  Failed to parse light update: %v", err)
+			return nil
+		}
+		msg := messages.LightUpdateMsg{
+			LightID:  update.ID,
+			On:       update.On,
+			Gradient: update.Gradient,
+		}
+		if update.Brightness != nil {
+			b := models.RoundPct(*update.Brightness)
+			msg.Brightness = &b
+		}
+		if update.ColorTemp != nil {
+			msg.ColorTemp = update.ColorTemp
+		}
+		if update.ColorXY != nil {
+			msg.ColorXY = &struct{ X, Y float64 }{update.ColorXY.X, update.ColorXY.Y}
+		}
+		debugf("  Parsed light update: id=%s on=%v brightness=%v", update.ID, update.On, update.Brightness)
+		return msg
+
+	case "grouped_light":
+		update, err := api.ParseGroupedLightUpdate(event)
+		if err != nil {
+			debugf("  Failed to parse grouped_light update: %v", err)
+			return nil
+		}
+		if update.On == nil {
+			// Brightness-only group updates are followed by per-light events
+			return nil
+		}
+		return messages.GroupedLightUpdateMsg{
+			GroupedLightID: update.ID,
+			On:             update.On,
+		}
+
+	case "zigbee_connectivity":
+		update, err := api.ParseConnectivityUpdate(event)
+		if err != nil {
+			debugf("  Failed to parse connectivity update: %v", err)
+			return nil
+		}
+		return messages.ConnectivityUpdateMsg{DeviceID: update.DeviceID, Reachable: update.Reachable}
+
+	case "room":
+		update, err := api.ParseRoomUpdate(event)
+		if err != nil {
+			debugf("  Failed to parse room update: %v", err)
+			return nil
+		}
+		return messages.RoomUpdateMsg{
+			RoomID:          update.ID,
+			Name:            update.Name,
+			ChildrenChanged: update.ChildrenChanged,
+		}
+
+	case "entertainment_configuration":
+		return messages.EntertainmentChangedMsg{}
+
+	case "motion":
+		update, err := api.ParseMotionUpdate(event)
+		if err != nil || !update.Motion {
+			// Motion ending is not worth a message
+			return nil
+		}
+		return messages.MotionMsg{SensorID: update.ID}
+
+	case "scene":
+		update, err := api.ParseSceneUpdate(event)
+		if err != nil {
+			debugf("  Failed to parse scene update: %v", err)
+			return nil
+		}
+		return messages.SceneUpdateMsg{
+			SceneID: update.ID,
+			Name:    update.Name,
+			Status:  update.Status,
+		}
+	}
+
+	return nil
+}
+
+// addEventToMsg converts a resource add event into the matching message
+func addEventToMsg(event api.Event) tea.Msg {
+	switch event.Resource {
+	case "light":
+		light, err := api.ParseLightResource(event)
+		if err != nil {
+			debugf("  Failed to parse added light: %v", err)
+			return nil
+		}
+		return messages.LightAddedMsg{Light: light}
+
+	case "room":
+		room, err := api.ParseRoomResource(event)
+		if err != nil {
+			debugf("  Failed to parse added room: %v", err)
+			return nil
+		}
+		return messages.RoomAddedMsg{Room: room}
+
+	case "scene":
+		scene, err := api.ParseSceneResource(event)
+		if err != nil {
+			debugf("  Failed to parse added scene: %v", err)
+			return nil
+		}
+		return messages.SceneAddedMsg{Scene: scene}
+	}
+	return nil
+}
+
+// handleGroupedLightUpdate applies a room turning off to its lights. A
+// room is on when any of its lights is, so turning on is left to the
+// events of the lights themselves.
+func (m *Model) handleGroupedLightUpdate(msg messages.GroupedLightUpdateMsg) {
+	if msg.On == nil || *msg.On {
+		return
+	}
+
+	for _, room := range m.rooms {
+		if room.GroupedLightID != msg.GroupedLightID {
+			continue
+		}
+		debugf("Handling GroupedLightUpdateMsg: room=%s off", room.Name)
+		for _, light := range room.Lights {
+			// Our own toggles are confirmed by the per-light echo
+			if m.pending.HasPending(light.ID, "on") {
+				continue
+			}
+			light.On = false
+		}
+		room.UpdateState()
+		return
+	}
+}
+
+// handleConnectivityUpdate marks the lights of a device reachable or not
+func (m *Model) handleConnectivityUpdate(msg messages.ConnectivityUpdateMsg) {
+	for _, room := range m.rooms {
+		for _, light := range room.Lights {
+			if light.DeviceID == msg.DeviceID {
+				debugf("Handling ConnectivityUpdateMsg: light=%s reachable=%v", light.Name, msg.Reachable)
+				light.Reachable = msg.Reachable
+			}
+		}
+	}
+}
+
+// handleLightCommandsResult counts consecutive failed commands per light
+// and rolls back the optimistic changes of the lights that failed
+func (m *Model) handleLightCommandsResult(msg messages.LightCommandsResultMsg) tea.Cmd {
+	for _, id := range msg.Succeeded {
+		if light := m.findLightByID(id); light != nil {
+			light.Failures = 0
+		}
+	}
+	for _, id := range msg.Failed {
+		if light := m.findLightByID(id); light != nil {
+			light.Failures++
+		}
+	}
+	return m.rollBack(msg.Rollbacks)
+}
+
+// rollBack restores the lights whose commands failed to their state before
+// the change. A light changed again since then is fetched instead, as the
+// newer change may or may not have reached it.
+func (m *Model) rollBack(rollbacks []messages.LightRollback) tea.Cmd {
+	var cmds []tea.Cmd
+	reverted := 0
+	for _, rb := range rollbacks {
+		light := m.findLightByID(rb.Before.ID)
+		if light == nil || sameLightState(rb.Before, rb.After) {
+			continue
+		}
+		m.pending.Forget(light.ID)
+		if !sameLightState(light, rb.After) {
+			debugf("Commands for %s failed after a newer change, fetching it", light.ID)
+			m.unconfirmed[light.ID] = true
+			cmds = append(cmds, m.fetchLightCmd(light.ID))
+			continue
+		}
+		debugf("Commands for %s failed, rolling it back", light.ID)
+		light.On = rb.Before.On
+		light.Brightness = rb.Before.Brightness
+		light.Name = rb.Before.Name
+		light.Archetype = rb.Before.Archetype
+		if rb.Before.Color != nil {
+			color := *rb.Before.Color
+			light.Color = &color
+			light.Color.InvalidateCache()
+		}
+		reverted++
+	}
+	if reverted == 0 && len(cmds) == 0 {
+		return nil
+	}
+	for _, room := range m.rooms {
+		room.UpdateState()
+	}
+	m.mainScreen.SetUnconfirmed(m.unconfirmed)
+	m.refreshScreens()
+	switch reverted {
+	case 0:
+	case 1:
+		m.mainScreen.SetNotice("Reverted 1 light after a failed command")
+	default:
+		m.mainScreen.SetNotice(fmt.Sprintf("Reverted %d lights after a failed command", reverted))
+	}
+	return tea.Batch(cmds...)
+}
+
+// sameLightState reports whether two snapshots of a light show the same
+// state, as far as the app changes it
+func sameLightState(a, b *models.Light) bool {
+	if a.On != b.On || a.Brightness != b.Brightness || a.Name != b.Name || a.Archetype != b.Archetype {
+		return false
+	}
+	if a.Color == nil || b.Color == nil {
+		return a.Color == b.Color
+	}
+	ca, cb := a.Color, b.Color
+	return ca.Mode == cb.Mode && ca.Hue == cb.Hue && ca.Saturation == cb.Saturation &&
+		ca.Mirek == cb.Mirek && ca.X == cb.X && ca.Y == cb.Y
+}
+
+// handleRoomUpdate applies a room rename, or refetches when membership changed
+func (m *Model) handleRoomUpdate(msg messages.RoomUpdateMsg) tea.Cmd {
+	if msg.ChildrenChanged {
+		// Membership is resolved through devices, so a full fetch is simplest
+		debugf("Room %s children changed, refreshing", msg.RoomID)
+		return m.fetchDataCmd()
+	}
+
+	if msg.Name == nil {
+		return nil
+	}
+
+	for _, room := range m.rooms {
+		if room.ID == msg.RoomID {
+			room.Name = *msg.Name
+			break
+		}
+	}
+	for _, scene := range m.scenes {
+		if scene.RoomID == msg.RoomID {
+			scene.RoomName = *msg.Name
+		}
+	}
+
+	m.refreshScreens()
+	return nil
+}
+
+// handleSceneUpdate applies a scene rename or activation status change
+func (m *Model) handleSceneUpdate(msg messages.SceneUpdateMsg) {
+	for _, scene := range m.scenes {
+		if scene.ID != msg.SceneID {
+			continue
+		}
+		if msg.Name != nil {
+			scene.Name = *msg.Name
+		}
+		if msg.Status != nil {
+			scene.Status = *msg.Status
+			if scene.IsActive() {
+				m.accentSceneID = scene.ID
+			}
+		}
+		m.refreshScreens()
+		return
+	}
+}
+
+// handleLightAdded inserts a new light into the room owning its device, and
+// fetches the name of the device when the event lacks it
+func (m *Model) handleLightAdded(light *models.Light) tea.Cmd {
+	if m.findLightByID(light.ID) != nil {
+		return nil
+	}
+
+	room := m.roomForDevice(light.DeviceID)
+	if room == nil {
+		room = m.otherRoom()
+	}
+	room.Lights = append(room.Lights, light)
+	room.UpdateState()
+	debugf("Added light %s to room %s", light.Name, room.Name)
+
+	m.refreshScreens()
+	if light.DeviceName != "" || light.DeviceID == "" || m.bridge == nil {
+		return nil
+	}
+	return m.fetchDeviceNameCmd(light.DeviceID)
+}
+
+// fetchDeviceNameCmd gets the name of a device
+func (m Model) fetchDeviceNameCmd(deviceID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		name, err := bridge.GetDeviceName(ctx, deviceID)
+		return messages.DeviceNameFetchedMsg{DeviceID: deviceID, Name: name, Err: err}
+	}
+}
+
+// applyDeviceName names the lights of a device
+func (m *Model) applyDeviceName(msg messages.DeviceNameFetchedMsg) {
+	if msg.Err != nil {
+		tuiLog.Warnf("Failed to get the name of device %s: %v", msg.DeviceID, msg.Err)
+		return
+	}
+	for _, room := range m.rooms {
+		for _, light := range room.Lights {
+			if light.DeviceID == msg.DeviceID {
+				light.DeviceName = msg.Name
+			}
+		}
+	}
+	m.refreshScreens()
+}
+
+// handleRoomAdded moves lights whose devices belong to the new room into it.
+// Rooms without lights are not displayed, so an empty room is ignored.
+func (m *Model) handleRoomAdded(newRoom *models.Room) {
+	for _, room := range m.rooms {
+		if room.ID == newRoom.ID {
+			return
+		}
+	}
+
+	devices := make(map[string]bool, len(newRoom.DeviceIDs))
+	for _, id := range newRoom.DeviceIDs {
+		devices[id] = true
+	}
+
+	for _, room := range m.rooms {
+		kept := room.Lights[:0]
+		for _, light := range room.Lights {
+			if devices[light.DeviceID] {
+				newRoom.Lights = append(newRoom.Lights, light)
+			} else {
+				kept = append(kept, light)
+			}
+		}
+		room.Lights = kept
+		room.UpdateState()
+	}
+
+	if len(newRoom.Lights) > 0 {
+		newRoom.UpdateState()
+		m.rooms = append(m.rooms, newRoom)
+	}
+	m.dropEmptyRooms()
+	m.refreshScreens()
+}
+
+// handleSceneAdded appends a new scene
+func (m *Model) handleSceneAdded(scene *models.Scene) {
+	for _, s := range m.scenes {
+		if s.ID == scene.ID {
+			return
+		}
+	}
+
+	for _, room := range m.rooms {
+		if room.ID == scene.RoomID {
+			scene.RoomName = room.Name
+			break
+		}
+	}
+	m.scenes = append(m.scenes, scene)
+	m.refreshScreens()
+}
+
+// handleResourceDeleted removes a deleted light, room or scene
+func (m *Model) handleResourceDeleted(msg messages.ResourceDeletedMsg) {
+	switch msg.Resource {
+	case "light":
+		for _, room := range m.rooms {
+			for i, light := range room.Lights {
+				if light.ID == msg.ResourceID {
+					room.Lights = append(room.Lights[:i], room.Lights[i+1:]...)
+					room.UpdateState()
+					break
+				}
+			}
+		}
+
+	case "room":
+		for i, room := range m.rooms {
+			if room.ID != msg.ResourceID {
+				continue
+			}
+			m.rooms = append(m.rooms[:i], m.rooms[i+1:]...)
+			// Lights of a deleted room become unassigned
+			if len(room.Lights) > 0 {
+				other := m.otherRoom()
+				other.Lights = append(other.Lights, room.Lights...)
+				other.UpdateState()
+			}
+			break
+		}
+
+	case "scene":
+		for i, scene := range m.scenes {
+			if scene.ID == msg.ResourceID {
+				m.scenes = append(m.scenes[:i], m.scenes[i+1:]...)
+				break
+			}
+		}
+	}
+
+	m.dropEmptyRooms()
+	m.refreshScreens()
+}
+
+// roomForDevice returns the displayed room containing the given device
+func (m *Model) roomForDevice(deviceID string) *models.Room {
+	if deviceID == "" {
+		return nil
+	}
+	for _, room := range m.rooms {
+		for _, id := range room.DeviceIDs {
+			if id == deviceID {
+				return room
+			}
+		}
+	}
+	return nil
+}
+
+// otherRoom returns the "Other Lights" room, creating it if needed
+func (m *Model) otherRoom() *models.Room {
+	for _, room := range m.rooms {
+		if room.ID == api.OtherRoomID {
+			return room
+		}
+	}
+	room := api.NewOtherRoom()
+	m.rooms = append(m.rooms, room)
+	return room
+}
+
+// dropEmptyRooms removes rooms left without lights, matching the initial fetch
+func (m *Model) dropEmptyRooms() {
+	kept := m.rooms[:0]
+	for _, room := range m.rooms {
+		if len(room.Lights) > 0 {
+			kept = append(kept, room)
+		}
+	}
+	m.rooms = kept
+}
+
+// refreshScreens pushes the current rooms and scenes to the screens
+func (m *Model) refreshScreens() {
+	m.mainScreen.SetData(m.rooms, m.scenes)
+	m.scenesScreen.SetScenes(m.scenes, m.rooms)
+	m.updateAccent()
+}
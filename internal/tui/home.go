@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fetchHomeGroupCmd fetches the grouped light of every light, once per
+// bridge, for the "All lights" entry
+func (m *Model) fetchHomeGroupCmd() tea.Cmd {
+	if m.bridge == nil || m.homeGroupBridge == m.bridge.BridgeID() {
+		return nil
+	}
+	bridge := m.bridge
+	ctx := m.ctx
+	bridgeID := bridge.BridgeID()
+	m.homeGroupBridge = bridgeID
+	return func() tea.Msg {
+		id, err := bridge.HomeGroupedLightID(ctx)
+		return messages.HomeGroupFetchedMsg{BridgeID: bridgeID, GroupedLightID: id, Err: err}
+	}
+}
+
+// handleHomeGroupFetched shows the "All lights" entry. Without the home
+// group, the rooms still work and the entry stays hidden.
+func (m *Model) handleHomeGroupFetched(msg messages.HomeGroupFetchedMsg) {
+	if msg.Err != nil {
+		tuiLog.Warnf("Home group unavailable: %v", msg.Err)
+		return
+	}
+	if m.bridge == nil || msg.BridgeID != m.bridge.BridgeID() {
+		return
+	}
+	m.mainScreen.SetHomeGroup(msg.GroupedLightID)
+}
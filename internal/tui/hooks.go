@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/hooks"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+)
+
+// handleHookError forwards a failed hook command to the event channel. It
+// is called from the goroutine running the command.
+func (m Model) handleHookError(err error) {
+	tuiLog.Warnf("Hook failed: %v", err)
+	select {
+	case m.eventChan <- messages.HookFailedMsg{Err: err}:
+	default:
+		debugf("Channel full, dropped hook failure")
+	}
+}
+
+// payload starts a hook payload for the current bridge
+func (m *Model) payload(event string) hooks.Payload {
+	p := hooks.Payload{Event: event}
+	if m.bridge != nil {
+		p.Bridge = m.bridge.BridgeID()
+	}
+	return p
+}
+
+// ownCommandWindow is how long the lights of a scene recalled or a local
+// schedule run from hue-tui can come on without running the light_on hooks
+const ownCommandWindow = 10 * time.Second
+
+// expectLightsOn holds back the light_on hooks of lights about to be
+// turned on by hue-tui itself, which the bridge events don't tell apart
+func (m *Model) expectLightsOn(lightIDs []string) {
+	until := time.Now().Add(ownCommandWindow)
+	for _, id := range lightIDs {
+		m.ownLightsOn[id] = until
+	}
+}
+
+// fireLightOn runs the hooks of a light turned on from outside hue-tui
+func (m *Model) fireLightOn(light *models.Light) {
+	if until, ok := m.ownLightsOn[light.ID]; ok {
+		delete(m.ownLightsOn, light.ID)
+		if time.Now().Before(until) {
+			return
+		}
+	}
+	if !m.hooks.Has(hooks.LightOn) {
+		return
+	}
+	p := m.payload(hooks.LightOn)
+	p.LightID, p.Light = light.ID, light.Name
+	for _, room := range m.rooms {
+		if room.LightByID(light.ID) != nil {
+			p.Room = room.Name
+			break
+		}
+	}
+	m.hooks.Fire(p)
+}
+
+// fireMotion runs the hooks of a motion sensor detecting motion
+func (m *Model) fireMotion(sensorID string) {
+	p := m.payload(hooks.Motion)
+	p.SensorID = sensorID
+	m.hooks.Fire(p)
+}
+
+// fireConnection runs the hooks of the bridge disconnecting or coming back
+func (m *Model) fireConnection(event string, err error) {
+	p := m.payload(event)
+	if err != nil {
+		p.Error = err.Error()
+	}
+	m.hooks.Fire(p)
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultIdleAfter is how long the terminal stays unfocused without input
+// before background work pauses
+const defaultIdleAfter = 10 * time.Minute
+
+// noteInput records keys, mouse events and the terminal getting focus
+// back. Input ends an idle pause, with the commands resuming the work.
+func (m *Model) noteInput(msg tea.Msg) tea.Cmd {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+	case tea.FocusMsg:
+		m.focused = true
+	default:
+		return nil
+	}
+	m.lastInput = time.Now()
+	if !m.idle {
+		return nil
+	}
+	return m.resume()
+}
+
+// handleBlur starts counting down to the idle pause once the terminal
+// loses focus
+func (m *Model) handleBlur() tea.Cmd {
+	m.focused = false
+	return m.idleCheck()
+}
+
+// handleIdleCheck pauses background work when the terminal has been
+// unfocused without input for idleAfter, or checks again later
+func (m *Model) handleIdleCheck() tea.Cmd {
+	m.idleChecking = false
+	if m.focused || m.idle {
+		return nil
+	}
+	if time.Since(m.lastInput) < m.idleAfter {
+		return m.idleCheck()
+	}
+	tuiLog.Infof("Idle for %s, pausing background work", m.idleAfter)
+	m.idle = true
+	return m.mainScreen.SetIdle(true)
+}
+
+// idleCheck schedules the next idle check, unless one is pending
+func (m *Model) idleCheck() tea.Cmd {
+	if m.idleChecking {
+		return nil
+	}
+	m.idleChecking = true
+	wait := max(time.Second, m.idleAfter-time.Since(m.lastInput))
+	return tea.Tick(wait, func(time.Time) tea.Msg { return messages.IdleCheckMsg{} })
+}
+
+// resume restarts the work paused while idle, refetching the data right
+// away when it was polled
+func (m *Model) resume() tea.Cmd {
+	tuiLog.Infof("Input after idle, resuming background work")
+	m.idle = false
+	cmds := []tea.Cmd{m.mainScreen.SetIdle(false)}
+	if m.polling {
+		if !m.pollTicking {
+			m.pollTicking = true
+			cmds = append(cmds, pollTick(m.pollInterval))
+		}
+		if !m.offline {
+			cmds = append(cmds, m.fetchDataCmd())
+		}
+	}
+	return tea.Batch(cmds...)
+}
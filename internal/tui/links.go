@@ -0,0 +1,28 @@
+package tui
+
+// lightLinks returns the configured groups of linked lights of the current
+// bridge. Demo mode keeps links in memory only.
+func (m *Model) lightLinks() [][]string {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		return m.demoLinks
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return nil
+	}
+	return bridgeCfg.LightLinks
+}
+
+// saveLightLinks persists the groups of linked lights
+func (m *Model) saveLightLinks(links [][]string) error {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		m.demoLinks = links
+		return nil
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return err
+	}
+	bridgeCfg.LightLinks = links
+	return m.config.Save()
+}
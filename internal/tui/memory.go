@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"maps"
+
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// lightMemory returns the remembered brightness and color of the current
+// bridge's lights. Demo mode keeps them in memory only.
+func (m *Model) lightMemory() map[string]models.LightMemory {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		return m.demoMemory
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return nil
+	}
+	memory := make(map[string]models.LightMemory, len(bridgeCfg.LightMemory))
+	for id, mem := range bridgeCfg.LightMemory {
+		memory[id] = models.LightMemory{Brightness: mem.Brightness, Mirek: mem.Mirek, X: mem.X, Y: mem.Y}
+	}
+	return memory
+}
+
+// saveLightMemory persists the brightness and color of lights just turned off
+func (m *Model) saveLightMemory(lights map[string]models.LightMemory) error {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		memory := maps.Clone(m.demoMemory)
+		if memory == nil {
+			memory = make(map[string]models.LightMemory)
+		}
+		maps.Copy(memory, lights)
+		m.demoMemory = memory
+		return nil
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return err
+	}
+	memory := maps.Clone(bridgeCfg.LightMemory)
+	if memory == nil {
+		memory = make(map[string]config.LightMemory)
+	}
+	for id, mem := range lights {
+		memory[id] = config.LightMemory{Brightness: mem.Brightness, Mirek: mem.Mirek, X: mem.X, Y: mem.Y}
+	}
+	bridgeCfg.LightMemory = memory
+	return m.config.Save()
+}
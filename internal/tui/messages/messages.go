@@ -1,8 +1,11 @@
 package messages
 
 import (
+	"time"
+
 	"github.com/angristan/hue-tui/internal/api"
 	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // BridgeConnectedMsg indicates successful bridge connection
@@ -11,10 +14,156 @@ type BridgeConnectedMsg struct {
 	AppKey string
 }
 
+// BridgeAddedMsg reports an additional bridge paired from the bridge
+// picker, to save without switching to it
+type BridgeAddedMsg struct {
+	Bridge api.BridgeClient
+	AppKey string
+}
+
+// ShowBridgesMsg opens the bridge picker
+type ShowBridgesMsg struct{}
+
+// HideBridgesMsg closes the bridge picker
+type HideBridgesMsg struct{}
+
+// SwitchBridgeMsg connects to another configured bridge
+type SwitchBridgeMsg struct {
+	BridgeID string
+}
+
+// FetchBridgeInfoMsg requests the system details of the bridge in use
+type FetchBridgeInfoMsg struct{}
+
+// BridgeInfoFetchedMsg carries the system details of the bridge in use
+type BridgeInfoFetchedMsg struct {
+	Info *models.BridgeInfo
+	Err  error
+}
+
+// AddBridgeMsg starts pairing an additional bridge
+type AddBridgeMsg struct{}
+
+// HideSetupMsg leaves the setup screen opened to add a bridge
+type HideSetupMsg struct{}
+
+// APIVersionDetectedMsg reports the API version a bridge was probed with
+type APIVersionDetectedMsg struct {
+	BridgeID string
+	Version  string
+	Err      error
+}
+
 // DataFetchedMsg contains fetched data from the bridge
 type DataFetchedMsg struct {
 	Rooms  []*models.Room
 	Scenes []*models.Scene
+	// Set when everything but the scenes loaded
+	ScenesErr error
+}
+
+// RetryScenesMsg requests another attempt at fetching unavailable scenes
+type RetryScenesMsg struct {
+	Attempt int
+}
+
+// ScenesFetchedMsg contains the scenes fetched on their own
+type ScenesFetchedMsg struct {
+	Scenes []*models.Scene
+	Err    error
+}
+
+// FetchProgressMsg reports a resource type loaded during a fetch
+type FetchProgressMsg struct {
+	Resource string
+	Count    int
+	// Next waits for the rest of the fetch
+	Next tea.Cmd
+}
+
+// CalibrationStepMsg shows a calibration step: both lights at Mirek, the
+// second one with Offset added instead of its saved offset
+type CalibrationStepMsg struct {
+	ReferenceID string
+	LightID     string
+	Mirek       int
+	Offset      int
+}
+
+// ColorTempOffsetMsg carries a new calibration offset for a light
+type ColorTempOffsetMsg struct {
+	LightID string
+	Offset  int
+}
+
+// LightMemoryMsg carries the brightness and color of lights just turned
+// off, by light ID, to restore when they are turned back on
+type LightMemoryMsg struct {
+	Lights map[string]models.LightMemory
+}
+
+// RoomMutedMsg asks to ignore or follow again the live updates of a room
+type RoomMutedMsg struct {
+	RoomID string
+	Muted  bool
+}
+
+// SceneShortcutMsg binds a scene to a shortcut key of its room
+type SceneShortcutMsg struct {
+	RoomID  string
+	Key     string
+	SceneID string
+}
+
+// LightLinksChangedMsg carries the new groups of linked light IDs
+type LightLinksChangedMsg struct {
+	Links [][]string
+}
+
+// ConnectionStatusMsg reports the bridge going offline or coming back
+type ConnectionStatusMsg struct {
+	Status api.ConnectionStatus
+}
+
+// ThrottleMsg reports how many commands wait for the bridge's rate limits
+type ThrottleMsg struct {
+	Queued int
+}
+
+// ReconcileTickMsg triggers a check for optimistic updates the bridge never
+// confirmed
+type ReconcileTickMsg struct{}
+
+// LightReconciledMsg carries the bridge's state of a light whose update
+// was not confirmed
+type LightReconciledMsg struct {
+	LightID string
+	Light   *models.Light
+	Err     error
+}
+
+// ConnectionTickMsg counts down to the next reconnection attempt
+type ConnectionTickMsg struct{}
+
+// StreamStatusMsg reports the event stream connecting, or failing to
+type StreamStatusMsg struct {
+	Connected bool
+}
+
+// RemoteTokenMsg reports that the remote API token of the bridge was
+// refreshed and needs saving
+type RemoteTokenMsg struct{}
+
+// PollTickMsg refreshes the data while the event stream is down
+type PollTickMsg struct{}
+
+// IdleCheckMsg checks whether the app has been idle long enough to pause
+// background work
+type IdleCheckMsg struct{}
+
+// SnapshotSavedMsg reports the files a view snapshot was saved to
+type SnapshotSavedMsg struct {
+	Paths []string
 }
 
 // ErrorMsg indicates an error occurred
@@ -33,6 +182,61 @@ type HideScenesMsg struct{}
 // SceneActivatedMsg indicates a scene was activated
 type SceneActivatedMsg struct {
 	SceneID string
+	// Fade the scene in slowly rather than over its own recall duration
+	Fade bool
+}
+
+// SceneTransitionMsg sets how long recalling a scene takes, or clears it
+// to use the bridge default
+type SceneTransitionMsg struct {
+	SceneID  string
+	Duration time.Duration
+	Clear    bool
+}
+
+// SceneSortMsg reports the order picked in the scenes modal, to remember it
+type SceneSortMsg struct {
+	Sort string
+}
+
+// StopSceneDynamicsMsg requests stopping a cycling scene on its current
+// colors
+type StopSceneDynamicsMsg struct {
+	SceneID string
+}
+
+// HomeGroupFetchedMsg carries the grouped light of every light on a bridge
+type HomeGroupFetchedMsg struct {
+	BridgeID       string
+	GroupedLightID string
+	Err            error
+}
+
+// SmartSceneToggleMsg requests starting or stopping a smart scene
+type SmartSceneToggleMsg struct {
+	SceneID string
+	Active  bool
+}
+
+// SaveSceneMsg requests saving the current state of a room as a scene
+type SaveSceneMsg struct {
+	Name   string
+	RoomID string
+}
+
+// SceneSavedMsg indicates a scene was created from the current state
+type SceneSavedMsg struct {
+	Name string
+}
+
+// GenerateScenesMsg requests the natural light scenes for a room
+type GenerateScenesMsg struct {
+	RoomID string
+}
+
+// ScenesGeneratedMsg lists the natural light scenes created for a room
+type ScenesGeneratedMsg struct {
+	Names []string
 }
 
 // RefreshMsg requests a data refresh
@@ -45,4 +249,230 @@ type LightUpdateMsg struct {
 	Brightness *int
 	ColorTemp  *int
 	ColorXY    *struct{ X, Y float64 }
+	Gradient   [][2]float64
+}
+
+// GroupedLightUpdateMsg indicates a room/zone grouped light state change
+type GroupedLightUpdateMsg struct {
+	GroupedLightID string
+	On             *bool
+}
+
+// MotionMsg indicates a motion sensor detected motion
+type MotionMsg struct {
+	SensorID string
+}
+
+// FlashEndMsg ends the header flash of an alert, unless a newer one
+// restarted it
+type FlashEndMsg struct {
+	ID int
+}
+
+// HookFailedMsg reports a hook command that failed
+type HookFailedMsg struct {
+	Err error
+}
+
+// ConnectivityUpdateMsg indicates a device became reachable or unreachable
+type ConnectivityUpdateMsg struct {
+	DeviceID  string
+	Reachable bool
+}
+
+// LightCommandsResultMsg reports which lights accepted a batch of commands
+type LightCommandsResultMsg struct {
+	Succeeded []string
+	Failed    []string
+	// Joined errors of the failed lights (nil if none failed)
+	Err error
+	// Optimistic changes of the failed lights, to roll back
+	Rollbacks []LightRollback
+}
+
+// LightRollback is a light's local state before and after a change whose
+// commands failed
+type LightRollback struct {
+	Before *models.Light
+	After  *models.Light
+}
+
+// RoomUpdateMsg indicates a room metadata change
+type RoomUpdateMsg struct {
+	RoomID          string
+	Name            *string
+	ChildrenChanged bool
+}
+
+// SceneUpdateMsg indicates a scene metadata or status change
+type SceneUpdateMsg struct {
+	SceneID string
+	Name    *string
+	Status  *string
+}
+
+// LightAddedMsg indicates a light was added to the bridge
+type LightAddedMsg struct {
+	Light *models.Light
+}
+
+// DeviceNameFetchedMsg carries the name of the device of an added light
+type DeviceNameFetchedMsg struct {
+	DeviceID string
+	Name     string
+	Err      error
+}
+
+// RoomAddedMsg indicates a room was added to the bridge
+type RoomAddedMsg struct {
+	Room *models.Room
+}
+
+// SceneAddedMsg indicates a scene was added to the bridge
+type SceneAddedMsg struct {
+	Scene *models.Scene
+}
+
+// ResourceDeletedMsg indicates a resource was deleted from the bridge
+type ResourceDeletedMsg struct {
+	Resource   string // "light", "room" or "scene"
+	ResourceID string
+}
+
+// ShowEntertainmentMsg requests showing the entertainment areas screen
+type ShowEntertainmentMsg struct{}
+
+// HideEntertainmentMsg requests hiding the entertainment areas screen
+type HideEntertainmentMsg struct{}
+
+// EntertainmentFetchedMsg contains the fetched entertainment areas
+type EntertainmentFetchedMsg struct {
+	Areas []*models.EntertainmentArea
+}
+
+// EntertainmentToggleMsg requests starting or stopping an entertainment session
+type EntertainmentToggleMsg struct {
+	AreaID string
+	Active bool
+}
+
+// EntertainmentChangedMsg indicates an entertainment area changed on the bridge
+type EntertainmentChangedMsg struct{}
+
+// ShowSceneCleanupMsg requests showing the scene cleanup
+type ShowSceneCleanupMsg struct{}
+
+// HideSceneCleanupMsg requests hiding the scene cleanup
+type HideSceneCleanupMsg struct{}
+
+// SceneCleanupFetchedMsg carries the scenes and the rooms and zones they
+// may belong to, for the scene cleanup
+type SceneCleanupFetchedMsg struct {
+	Scenes []*models.Scene
+	Groups []*models.Room
+}
+
+// DeleteSceneMsg requests deleting a scene of a cleanup batch
+type DeleteSceneMsg struct {
+	SceneID string
+}
+
+// SceneDeletedMsg reports a scene of a cleanup batch deleted, or why not
+type SceneDeletedMsg struct {
+	SceneID string
+	Err     error
+}
+
+// ScenesCleanedMsg reports the end of a cleanup batch
+type ScenesCleanedMsg struct{}
+
+// ShowDevicesMsg requests showing the battery devices screen
+type ShowDevicesMsg struct{}
+
+// HideDevicesMsg requests hiding the battery devices screen
+type HideDevicesMsg struct{}
+
+// ShowNotificationsMsg requests showing the past notifications
+type ShowNotificationsMsg struct{}
+
+// HideNotificationsMsg requests hiding the past notifications
+type HideNotificationsMsg struct{}
+
+// ClearNotificationsMsg requests forgetting the past notifications
+type ClearNotificationsMsg struct{}
+
+// DevicesFetchedMsg contains the fetched battery powered devices
+type DevicesFetchedMsg struct {
+	Devices []*models.Device
+}
+
+// ShowSchedulesMsg requests showing the schedules screen
+type ShowSchedulesMsg struct{}
+
+// HideSchedulesMsg requests hiding the schedules screen
+type HideSchedulesMsg struct{}
+
+// SchedulesFetchedMsg contains the fetched schedules
+type SchedulesFetchedMsg struct {
+	Schedules []*models.Schedule
+}
+
+// ScheduleCreateMsg requests creating a schedule
+type ScheduleCreateMsg struct {
+	Spec api.ScheduleSpec
+}
+
+// ScheduleDeleteMsg requests deleting a schedule
+type ScheduleDeleteMsg struct {
+	Resource string
+	ID       string
+}
+
+// SchedulesChangedMsg indicates a schedule changed on the bridge
+type SchedulesChangedMsg struct {
+	// Bridge resource type of the schedule, smart scenes are also scenes
+	Resource string
+}
+
+// ShowRoomsMsg requests showing the manage rooms screen
+type ShowRoomsMsg struct{}
+
+// HideRoomsMsg requests hiding the manage rooms screen
+type HideRoomsMsg struct{}
+
+// RoomsFetchedMsg contains the rooms and zones of the bridge, rooms with
+// their device IDs and zones with their light IDs
+type RoomsFetchedMsg struct {
+	Rooms []*models.Room
+	Zones []*models.Room
+}
+
+// ZonesRequestMsg requests the zones for the zone picker of the main
+// screen, delivered as a RoomsFetchedMsg
+type ZonesRequestMsg struct{}
+
+// RoomSaveMsg requests creating a room or zone, or updating it when ID is
+// set. ChildIDs are device IDs for rooms and light IDs for zones.
+type RoomSaveMsg struct {
+	Zone     bool
+	ID       string
+	Name     string
+	ChildIDs []string
+}
+
+// RoomDeleteMsg requests deleting a room or zone
+type RoomDeleteMsg struct {
+	Zone bool
+	ID   string
+}
+
+// LocalScheduleTickMsg triggers a check for local schedules that are due
+type LocalScheduleTickMsg struct {
+	Time time.Time
+}
+
+// LocalScheduleRanMsg reports a local schedule that was run
+type LocalScheduleRanMsg struct {
+	Name string
+	Err  error
 }
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/angristan/hue-tui/internal/api"
+)
+
+// eventFilter drops the updates of muted rooms before they are parsed. It
+// is shared with the event subscription goroutine.
+type eventFilter struct {
+	mu sync.Mutex
+	// Light and grouped light IDs of the muted rooms
+	ignored map[string]bool
+}
+
+func newEventFilter() *eventFilter {
+	return &eventFilter{ignored: make(map[string]bool)}
+}
+
+// set replaces the ignored resource IDs
+func (f *eventFilter) set(ids map[string]bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ignored = ids
+}
+
+// ignores reports whether an event belongs to a muted room. Only light
+// and grouped light updates are dropped: additions and deletions still
+// change what is shown.
+func (f *eventFilter) ignores(event api.Event) bool {
+	if f == nil || event.Type != api.EventTypeUpdate {
+		return false
+	}
+	if event.Resource != "light" && event.Resource != "grouped_light" {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ignored[event.ResourceID]
+}
+
+// mutedRooms returns the IDs of the rooms whose live updates are ignored
+// on the current bridge. Demo mode keeps them in memory only.
+func (m *Model) mutedRooms() []string {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		return m.demoMuted
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return nil
+	}
+	return bridgeCfg.MutedRooms
+}
+
+// setRoomMuted mutes or unmutes the live updates of a room
+func (m *Model) setRoomMuted(roomID string, muted bool) error {
+	rooms := slices.DeleteFunc(slices.Clone(m.mutedRooms()), func(id string) bool { return id == roomID })
+	if muted {
+		rooms = append(rooms, roomID)
+	}
+
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		m.demoMuted = rooms
+	} else {
+		bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+		if err != nil {
+			return err
+		}
+		bridgeCfg.MutedRooms = rooms
+		if err := m.config.Save(); err != nil {
+			return err
+		}
+	}
+	m.applyMutedRooms()
+	return nil
+}
+
+// applyMutedRooms updates the event filter and the main screen after the
+// rooms or the muted rooms changed
+func (m *Model) applyMutedRooms() {
+	muted := m.mutedRooms()
+	ids := make(map[string]bool)
+	for _, room := range m.rooms {
+		if !slices.Contains(muted, room.ID) {
+			continue
+		}
+		if room.GroupedLightID != "" {
+			ids[room.GroupedLightID] = true
+		}
+		for _, light := range room.Lights {
+			ids[light.ID] = true
+		}
+	}
+	m.eventFilter.set(ids)
+	m.mainScreen.SetMutedRooms(muted)
+}
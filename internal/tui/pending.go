@@ -1,12 +1,19 @@
 package tui
 
 import (
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
 const pendingOpExpiry = 5 * time.Second
 
+// pendingTolerance is how far an echoed value may be from its target and
+// still confirm it, by field. Brightness is echoed in percent rounded from
+// the bridge's own scale, which can land a percent off.
+var pendingTolerance = map[string]float64{"brightness": 1}
+
 // Direction represents the direction of a change
 type Direction int
 
@@ -24,19 +31,66 @@ type PendingOp struct {
 	ExpiresAt time.Time
 }
 
+// CompoundOp groups several fields changed together (e.g. on + brightness)
+// so their echoes settle as one unit with a single expiry. Until every field
+// has reported its target, intermediate values for any member are ignored.
+type CompoundOp struct {
+	Targets   map[string]interface{} // field -> target value
+	Confirmed map[string]bool        // fields whose target has been echoed
+	ExpiresAt time.Time
+}
+
 // PendingTracker tracks pending operations to avoid flickering from event echoes
 type PendingTracker struct {
-	ops map[string]*PendingOp // keyed by lightID:field
-	mu  sync.Mutex
+	ops       map[string]*PendingOp  // keyed by lightID:field
+	compounds map[string]*CompoundOp // keyed by lightID
+	mu        sync.Mutex
 }
 
 // NewPendingTracker creates a new pending operations tracker
 func NewPendingTracker() *PendingTracker {
 	return &PendingTracker{
-		ops: make(map[string]*PendingOp),
+		ops:       make(map[string]*PendingOp),
+		compounds: make(map[string]*CompoundOp),
 	}
 }
 
+// AddCompound registers fields that were changed together for a light.
+// It replaces any single-field pending ops for those fields.
+func (t *PendingTracker) AddCompound(lightID string, targets map[string]interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	debugf("PendingTracker: Adding compound op for %s = %v", lightID, targets)
+	op := &CompoundOp{
+		Targets:   make(map[string]interface{}, len(targets)),
+		Confirmed: make(map[string]bool, len(targets)),
+		ExpiresAt: time.Now().Add(pendingOpExpiry),
+	}
+	for field, target := range targets {
+		op.Targets[field] = target
+		delete(t.ops, lightID+":"+field)
+	}
+	t.compounds[lightID] = op
+}
+
+// compoundFor returns the live compound op covering a light field, if any.
+// Must be called with the lock held.
+func (t *PendingTracker) compoundFor(lightID, field string) *CompoundOp {
+	op, exists := t.compounds[lightID]
+	if !exists {
+		return nil
+	}
+	if time.Now().After(op.ExpiresAt) {
+		delete(t.compounds, lightID)
+		return nil
+	}
+	if _, ok := op.Targets[field]; !ok {
+		return nil
+	}
+	return op
+}
+
 // Add registers a pending operation for a light (exact match, for booleans)
 func (t *PendingTracker) Add(lightID, field string, value interface{}) {
 	t.AddWithDirection(lightID, field, value, DirExact)
@@ -49,6 +103,16 @@ func (t *PendingTracker) AddWithDirection(lightID, field string, target interfac
 
 	key := lightID + ":" + field
 	debugf("PendingTracker: Adding pending op %s = %T(%v) dir=%v", key, target, target, dir)
+
+	// A newer single-field change supersedes that field in a compound op
+	if op, exists := t.compounds[lightID]; exists {
+		delete(op.Targets, field)
+		delete(op.Confirmed, field)
+		if len(op.Targets) == 0 {
+			delete(t.compounds, lightID)
+		}
+	}
+
 	t.ops[key] = &PendingOp{
 		Field:     field,
 		Target:    target,
@@ -64,6 +128,18 @@ func (t *PendingTracker) ShouldIgnore(lightID, field string, value interface{})
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if compound := t.compoundFor(lightID, field); compound != nil {
+		if targetReached(field, compound.Targets[field], value) {
+			compound.Confirmed[field] = true
+			if len(compound.Confirmed) == len(compound.Targets) {
+				debugf("PendingTracker: compound op for %s fully confirmed", lightID)
+				delete(t.compounds, lightID)
+			}
+		}
+		// Ignore every member value until the whole unit has settled
+		return true
+	}
+
 	key := lightID + ":" + field
 	op, exists := t.ops[key]
 	if !exists {
@@ -81,7 +157,7 @@ func (t *PendingTracker) ShouldIgnore(lightID, field string, value interface{})
 	switch op.Direction {
 	case DirExact:
 		// For exact matches (booleans), only ignore if value matches target
-		if valuesEqual(op.Target, value) {
+		if valuesEqual(op.Target, value) || withinTolerance(field, op.Target, value) {
 			debugf("PendingTracker: DirExact match for %s, ignoring", key)
 			delete(t.ops, key)
 			return true
@@ -92,7 +168,7 @@ func (t *PendingTracker) ShouldIgnore(lightID, field string, value interface{})
 	case DirUp:
 		// We're increasing toward target
 		// Ignore if value <= target (still on the way or reached)
-		cmp := compareValues(value, op.Target)
+		cmp := compareField(field, value, op.Target)
 		if cmp <= 0 {
 			// If we reached or passed target, clear the op
 			if cmp == 0 {
@@ -107,7 +183,7 @@ func (t *PendingTracker) ShouldIgnore(lightID, field string, value interface{})
 	case DirDown:
 		// We're decreasing toward target
 		// Ignore if value >= target (still on the way or reached)
-		cmp := compareValues(value, op.Target)
+		cmp := compareField(field, value, op.Target)
 		if cmp >= 0 {
 			// If we reached or passed target, clear the op
 			if cmp == 0 {
@@ -133,6 +209,10 @@ func (t *PendingTracker) HasPending(lightID, field string) bool {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.compoundFor(lightID, field) != nil {
+		return true
+	}
+
 	key := lightID + ":" + field
 	op, exists := t.ops[key]
 	if !exists {
@@ -159,6 +239,82 @@ func (t *PendingTracker) Cleanup() {
 			delete(t.ops, key)
 		}
 	}
+	for lightID, op := range t.compounds {
+		if now.After(op.ExpiresAt) {
+			delete(t.compounds, lightID)
+		}
+	}
+}
+
+// Forget removes the pending operations of a light, whose commands failed
+// so no echo will confirm them
+func (t *PendingTracker) Forget(lightID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, op := range t.ops {
+		if key == lightID+":"+op.Field {
+			delete(t.ops, key)
+		}
+	}
+	delete(t.compounds, lightID)
+}
+
+// Unconfirmed removes the operations that expired without their target
+// being echoed and returns the IDs of their lights, whose local state may
+// not match the bridge
+func (t *PendingTracker) Unconfirmed() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	var lightIDs []string
+	add := func(lightID string) {
+		if !seen[lightID] {
+			seen[lightID] = true
+			lightIDs = append(lightIDs, lightID)
+		}
+	}
+	for key, op := range t.ops {
+		if now.After(op.ExpiresAt) {
+			delete(t.ops, key)
+			add(strings.TrimSuffix(key, ":"+op.Field))
+		}
+	}
+	for lightID, op := range t.compounds {
+		if now.After(op.ExpiresAt) {
+			delete(t.compounds, lightID)
+			add(lightID)
+		}
+	}
+	sort.Strings(lightIDs)
+	return lightIDs
+}
+
+// targetReached reports whether an incoming value confirms a target
+func targetReached(field string, target, value interface{}) bool {
+	switch target.(type) {
+	case bool, struct{ X, Y float64 }:
+		return valuesEqual(target, value)
+	}
+	return compareField(field, value, target) == 0
+}
+
+// withinTolerance reports whether two numeric values of a field are equal
+// within its rounding tolerance
+func withinTolerance(field string, a, b interface{}) bool {
+	tolerance, ok := pendingTolerance[field]
+	return ok && absFloat(toFloat64(a)-toFloat64(b)) <= tolerance
+}
+
+// compareField compares two values of a field like compareValues, values
+// within its rounding tolerance being equal
+func compareField(field string, a, b interface{}) int {
+	if withinTolerance(field, a, b) {
+		return 0
+	}
+	return compareValues(a, b)
 }
 
 // compareValues compares two numeric values
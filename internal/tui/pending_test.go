@@ -454,3 +454,133 @@ func TestPendingTracker_ColorXY_MutualExclusion(t *testing.T) {
 		t.Error("Expected pending color_temp")
 	}
 }
+
+func TestPendingTracker_Compound_IgnoresUntilSettled(t *testing.T) {
+	tracker := NewPendingTracker()
+
+	// Turning on an off light: on + brightness 10
+	tracker.AddCompound("light1", map[string]interface{}{"on": true, "brightness": 10})
+
+	// First echo carries on=true with the pre-on brightness
+	if !tracker.ShouldIgnore("light1", "on", true) {
+		t.Error("Expected to ignore on=true echo")
+	}
+	if !tracker.ShouldIgnore("light1", "brightness", 80) {
+		t.Error("Expected to ignore stale brightness 80 while compound settles")
+	}
+	if !tracker.HasPending("light1", "brightness") {
+		t.Error("Expected brightness to still be pending")
+	}
+
+	// Final brightness confirms the whole unit
+	if !tracker.ShouldIgnore("light1", "brightness", 10) {
+		t.Error("Expected to ignore target brightness 10")
+	}
+	if tracker.HasPending("light1", "on") || tracker.HasPending("light1", "brightness") {
+		t.Error("Expected compound op to be cleared once all fields confirmed")
+	}
+	if tracker.ShouldIgnore("light1", "brightness", 50) {
+		t.Error("Expected external change to apply after compound settled")
+	}
+}
+
+func TestPendingTracker_Compound_SupersededBySingleOp(t *testing.T) {
+	tracker := NewPendingTracker()
+
+	tracker.AddCompound("light1", map[string]interface{}{"on": true, "brightness": 10})
+	// User keeps pressing right before the echo arrives
+	tracker.AddWithDirection("light1", "brightness", 20, DirUp)
+
+	// Brightness is now governed by the directional op
+	if !tracker.ShouldIgnore("light1", "brightness", 15) {
+		t.Error("Expected to ignore intermediate brightness 15")
+	}
+	if tracker.ShouldIgnore("light1", "brightness", 30) {
+		t.Error("Expected not to ignore brightness above target")
+	}
+
+	// On is still part of the compound op
+	if !tracker.ShouldIgnore("light1", "on", true) {
+		t.Error("Expected to ignore on=true echo")
+	}
+	if tracker.HasPending("light1", "on") {
+		t.Error("Expected compound op to be cleared after remaining field confirmed")
+	}
+}
+
+func TestPendingTracker_Compound_Expiry(t *testing.T) {
+	tracker := NewPendingTracker()
+
+	tracker.AddCompound("light1", map[string]interface{}{"on": true, "brightness": 10})
+	tracker.compounds["light1"].ExpiresAt = time.Now().Add(-time.Second)
+
+	if tracker.ShouldIgnore("light1", "brightness", 80) {
+		t.Error("Expected expired compound op not to ignore values")
+	}
+}
+
+func TestPendingTracker_Unconfirmed(t *testing.T) {
+	tracker := NewPendingTracker()
+	tracker.Add("light1", "on", true)
+	tracker.Add("light2", "on", true)
+	tracker.AddCompound("light3", map[string]interface{}{"on": true, "brightness": 50})
+
+	// Expire light2 and light3 without any echo
+	tracker.mu.Lock()
+	tracker.ops["light2:on"].ExpiresAt = time.Now().Add(-1 * time.Second)
+	tracker.compounds["light3"].ExpiresAt = time.Now().Add(-1 * time.Second)
+	tracker.mu.Unlock()
+
+	got := tracker.Unconfirmed()
+	if len(got) != 2 || got[0] != "light2" || got[1] != "light3" {
+		t.Errorf("Unconfirmed() = %v, want [light2 light3]", got)
+	}
+	if got := tracker.Unconfirmed(); len(got) != 0 {
+		t.Errorf("Expected unconfirmed ops to be reported once, got %v", got)
+	}
+	if !tracker.HasPending("light1", "on") {
+		t.Error("Expected the live op to be kept")
+	}
+}
+
+func TestPendingTracker_Forget(t *testing.T) {
+	tracker := NewPendingTracker()
+	tracker.Add("light1", "brightness", 40)
+	tracker.Add("light10", "brightness", 40)
+	tracker.AddCompound("light1", map[string]interface{}{"on": true, "brightness": 50})
+
+	tracker.Forget("light1")
+
+	if tracker.HasPending("light1", "on") || tracker.HasPending("light1", "brightness") {
+		t.Error("Expected the light's ops to be forgotten")
+	}
+	if !tracker.HasPending("light10", "brightness") {
+		t.Error("Expected other lights' ops to be kept")
+	}
+}
+
+func TestPendingTracker_BrightnessRounding(t *testing.T) {
+	tracker := NewPendingTracker()
+
+	// 50% echoed as 49% after the bridge's own rounding
+	tracker.AddWithDirection("light1", "brightness", 50, DirUp)
+	if !tracker.ShouldIgnore("light1", "brightness", 49) {
+		t.Error("Expected a value a percent off to confirm the target")
+	}
+	if tracker.HasPending("light1", "brightness") {
+		t.Error("Expected the op to be cleared")
+	}
+
+	tracker.AddCompound("light2", map[string]interface{}{"on": true, "brightness": 30})
+	tracker.ShouldIgnore("light2", "on", true)
+	tracker.ShouldIgnore("light2", "brightness", 31)
+	if tracker.HasPending("light2", "brightness") {
+		t.Error("Expected the compound op to settle a percent off")
+	}
+
+	// Color temperature has no tolerance
+	tracker.Add("light3", "color_temp", 300)
+	if tracker.ShouldIgnore("light3", "color_temp", 301) {
+		t.Error("Expected color temperature to match exactly")
+	}
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultPollInterval is how often data is refreshed while the event
+// stream can't connect
+const defaultPollInterval = 10 * time.Second
+
+// handleStream forwards the event stream connecting, or failing to, to the
+// event channel
+func (m Model) handleStream(connected bool) {
+	select {
+	case m.eventChan <- messages.StreamStatusMsg{Connected: connected}:
+	default:
+		debugf("Channel full, dropped stream status")
+	}
+}
+
+// handleStreamStatus falls back to polling while the event stream is down,
+// and shows which one keeps the data fresh in the header
+func (m *Model) handleStreamStatus(connected bool) tea.Cmd {
+	tuiLog.Infof("Event stream connected=%v", connected)
+	m.polling = !connected
+	if connected {
+		m.mainScreen.SetUpdateSource(true, 0)
+		return nil
+	}
+	m.mainScreen.SetUpdateSource(false, m.pollInterval)
+	if m.pollTicking {
+		return nil
+	}
+	m.pollTicking = true
+	return pollTick(m.pollInterval)
+}
+
+// handlePollTick refreshes the data, until the event stream is back or
+// the app is idle. While offline, the reconnection attempts already
+// refetch.
+func (m *Model) handlePollTick() tea.Cmd {
+	if !m.polling || m.idle {
+		m.pollTicking = false
+		return nil
+	}
+	cmds := []tea.Cmd{pollTick(m.pollInterval)}
+	if !m.offline {
+		cmds = append(cmds, m.fetchDataCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+func pollTick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg { return messages.PollTickMsg{} })
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reconcileInterval is how often optimistic updates that expired without
+// an event confirming them are looked for
+const reconcileInterval = time.Second
+
+// reconcileTick schedules the next check for unconfirmed updates
+func reconcileTick() tea.Cmd {
+	return tea.Tick(reconcileInterval, func(time.Time) tea.Msg {
+		return messages.ReconcileTickMsg{}
+	})
+}
+
+// reconcile marks the lights whose optimistic updates were never confirmed
+// and fetches their state from the bridge, rather than trusting the local
+// guess
+func (m *Model) reconcile() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, lightID := range m.pending.Unconfirmed() {
+		if m.findLightByID(lightID) == nil {
+			continue
+		}
+		debugf("Update of %s not confirmed, fetching it", lightID)
+		m.unconfirmed[lightID] = true
+		cmds = append(cmds, m.fetchLightCmd(lightID))
+	}
+	m.mainScreen.SetUnconfirmed(m.unconfirmed)
+	return tea.Batch(cmds...)
+}
+
+// fetchLightCmd gets the state of a single light
+func (m Model) fetchLightCmd(lightID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		light, err := bridge.GetLight(ctx, lightID)
+		return messages.LightReconciledMsg{LightID: lightID, Light: light, Err: err}
+	}
+}
+
+// applyReconciled replaces the local state of a light with the bridge's.
+// The light stays marked if it could not be fetched.
+func (m *Model) applyReconciled(msg messages.LightReconciledMsg) {
+	if msg.Err != nil {
+		tuiLog.Warnf("Failed to reconcile %s: %v", msg.LightID, msg.Err)
+		return
+	}
+	delete(m.unconfirmed, msg.LightID)
+	m.mainScreen.SetUnconfirmed(m.unconfirmed)
+
+	light := m.findLightByID(msg.LightID)
+	if light == nil {
+		return
+	}
+	// Leave a newer change alone, its own echo will settle it
+	if m.pending.HasPending(light.ID, "on") || m.pending.HasPending(light.ID, "brightness") ||
+		m.pending.HasPending(light.ID, "color_temp") || m.pending.HasPending(light.ID, "color_xy") {
+		return
+	}
+
+	fetched := msg.Light
+	m.uncalibrate([]*models.Room{{Lights: []*models.Light{fetched}}})
+	light.On = fetched.On
+	light.Brightness = fetched.Brightness
+	if fetched.Color != nil {
+		color := *fetched.Color
+		light.Color = &color
+		light.Color.InvalidateCache()
+	}
+	for _, room := range m.rooms {
+		for _, l := range room.Lights {
+			if l.ID == light.ID {
+				room.UpdateState()
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+)
+
+// handleRemoteToken forwards a refresh of the remote API token to the event
+// channel, the config being saved from the update loop. The refresh is
+// never dropped when the channel is full: the refresh token in the config
+// no longer works, so the send waits its turn in its own goroutine.
+func (m Model) handleRemoteToken(api.RemoteToken) {
+	go func() {
+		m.eventChan <- messages.RemoteTokenMsg{}
+	}()
+}
+
+// saveRemoteToken stores the remote API token of the bridge when it was
+// refreshed. Each refresh replaces the refresh token too, so the old one
+// kept in the config would no longer work.
+func (m *Model) saveRemoteToken() {
+	hueBridge, ok := m.bridge.(*api.HueBridge)
+	if !ok || hueBridge.Remote() == nil || m.config == nil {
+		return
+	}
+	bridgeCfg, err := m.config.GetBridge(hueBridge.BridgeID())
+	if err != nil || bridgeCfg.Remote == nil {
+		return
+	}
+	token := hueBridge.Remote().Token()
+	if token.AccessToken == bridgeCfg.Remote.AccessToken && token.RefreshToken == bridgeCfg.Remote.RefreshToken {
+		return
+	}
+	bridgeCfg.Remote.AccessToken = token.AccessToken
+	bridgeCfg.Remote.RefreshToken = token.RefreshToken
+	bridgeCfg.Remote.ExpiresAt = token.ExpiresAt
+	tuiLog.Infof("Saved the refreshed remote API token of bridge %s", bridgeCfg.BridgeID)
+	if err := m.config.Save(); err != nil {
+		m.reportError(err)
+	}
+}
@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// demoLightRoles tags a few demo lights so role keys work in demo mode
+var demoLightRoles = map[string]models.LightRole{
+	"light-lr-tv-bias": models.RoleTVBias,
+	"light-lr-accent":  models.RoleAmbient,
+	"light-lr-floor":   models.RoleAmbient,
+	"light-lr-ceiling": models.RoleTask,
+}
+
+// lightRoles returns the configured roles of the current bridge's lights.
+// Unknown roles are ignored.
+func (m *Model) lightRoles() map[string]models.LightRole {
+	if m.demoMode {
+		return demoLightRoles
+	}
+
+	roles := make(map[string]models.LightRole)
+	if m.bridge == nil || m.config == nil {
+		return roles
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return roles
+	}
+	for lightID, name := range bridgeCfg.LightRoles {
+		if role := models.LightRole(name); role.Valid() {
+			roles[lightID] = role
+		} else {
+			tuiLog.Warnf("Ignoring unknown role %q for light %s", name, lightID)
+		}
+	}
+	return roles
+}
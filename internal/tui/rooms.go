@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fetchGroupsCmd creates a command to fetch the rooms and zones
+func (m Model) fetchGroupsCmd() tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+		return fetchGroups(ctx, bridge)
+	}
+}
+
+// saveGroupCmd creates or updates a room or zone and refreshes the list
+func (m Model) saveGroupCmd(msg messages.RoomSaveMsg) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		var err error
+		switch {
+		case msg.Zone && msg.ID == "":
+			_, err = bridge.CreateZone(ctx, msg.Name, "", msg.ChildIDs)
+		case msg.Zone:
+			err = bridge.UpdateZone(ctx, msg.ID, msg.Name, msg.ChildIDs)
+		case msg.ID == "":
+			_, err = bridge.CreateRoom(ctx, msg.Name, "", msg.ChildIDs)
+		default:
+			err = bridge.UpdateRoom(ctx, msg.ID, msg.Name, msg.ChildIDs)
+		}
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return fetchGroups(ctx, bridge)
+	}
+}
+
+// deleteGroupCmd deletes a room or zone and refreshes the list
+func (m Model) deleteGroupCmd(msg messages.RoomDeleteMsg) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+
+		var err error
+		if msg.Zone {
+			err = bridge.DeleteZone(ctx, msg.ID)
+		} else {
+			err = bridge.DeleteRoom(ctx, msg.ID)
+		}
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return fetchGroups(ctx, bridge)
+	}
+}
+
+// fetchGroups loads the rooms and zones for the manage rooms screen
+func fetchGroups(ctx context.Context, bridge api.BridgeClient) tea.Msg {
+	rooms, err := bridge.GetRooms(ctx)
+	if err != nil {
+		return messages.ErrorMsg{Err: err}
+	}
+	zones, err := bridge.GetZones(ctx)
+	if err != nil {
+		return messages.ErrorMsg{Err: err}
+	}
+	return messages.RoomsFetchedMsg{Rooms: rooms, Zones: zones}
+}
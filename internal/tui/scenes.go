@@ -0,0 +1,349 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Backoff between scene fetch retries while scenes are unavailable
+const (
+	scenesRetryMin = 5 * time.Second
+	scenesRetryMax = 5 * time.Minute
+)
+
+// scenesFailed marks scenes as unavailable and schedules the next retry,
+// doubling the delay each time
+func (m *Model) scenesFailed(err error) tea.Cmd {
+	tuiLog.Warnf("Scenes unavailable: %v", err)
+	m.scenesErr = err
+	m.mainScreen.SetScenesUnavailable(true)
+
+	m.scenesBackoff *= 2
+	if m.scenesBackoff < scenesRetryMin {
+		m.scenesBackoff = scenesRetryMin
+	}
+	if m.scenesBackoff > scenesRetryMax {
+		m.scenesBackoff = scenesRetryMax
+	}
+	// Only the latest retry runs, earlier ones are superseded
+	m.scenesAttempt++
+	attempt := m.scenesAttempt
+	return tea.Tick(m.scenesBackoff, func(time.Time) tea.Msg {
+		return messages.RetryScenesMsg{Attempt: attempt}
+	})
+}
+
+// scenesLoaded clears the unavailable state once scenes load again
+func (m *Model) scenesLoaded() {
+	m.scenesErr = nil
+	m.scenesBackoff = 0
+	m.scenesAttempt++
+	m.mainScreen.SetScenesUnavailable(false)
+}
+
+// fetchScenesCmd fetches only the scenes, naming their rooms
+func (m Model) fetchScenesCmd() tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	roomNames := make(map[string]string, len(m.rooms))
+	for _, room := range m.rooms {
+		roomNames[room.ID] = room.Name
+	}
+	return func() tea.Msg {
+		scenes, err := bridge.GetScenes(ctx)
+		if err != nil {
+			return messages.ScenesFetchedMsg{Err: err}
+		}
+		for _, scene := range scenes {
+			scene.RoomName = roomNames[scene.RoomID]
+		}
+		return messages.ScenesFetchedMsg{Scenes: scenes}
+	}
+}
+
+// generateScenesCmd creates the natural light scenes of a room. Scenes the
+// room already has by those names are left alone.
+func (m Model) generateScenesCmd(roomID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	var room *models.Room
+	for _, r := range m.rooms {
+		if r.ID == roomID {
+			room = r
+		}
+	}
+	existing := make(map[string]bool)
+	for _, scene := range m.scenes {
+		if scene.RoomID == roomID {
+			existing[strings.ToLower(scene.Name)] = true
+		}
+	}
+	return func() tea.Msg {
+		if room == nil {
+			return messages.ErrorMsg{Err: fmt.Errorf("failed to create natural light scenes: room not found")}
+		}
+		var created []string
+		for _, preset := range api.NaturalLightPresets {
+			if existing[strings.ToLower(preset.Name)] {
+				continue
+			}
+			actions := api.PresetSceneActions(room.Lights, preset)
+			if _, err := bridge.CreateScene(ctx, preset.Name, room.ID, "room", actions); err != nil {
+				return messages.ErrorMsg{Err: fmt.Errorf("failed to create scene %q: %w", preset.Name, err)}
+			}
+			created = append(created, preset.Name)
+		}
+		return messages.ScenesGeneratedMsg{Names: created}
+	}
+}
+
+// sceneShortcuts returns the scene IDs bound to the keys 1-9 on the current
+// bridge, by room ID and key. Demo mode keeps them in memory only.
+func (m *Model) sceneShortcuts() map[string]map[string]string {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		return m.demoShortcuts
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return nil
+	}
+	return bridgeCfg.SceneShortcuts
+}
+
+// setSceneShortcut binds a scene to a key of its room
+func (m *Model) setSceneShortcut(roomID, key, sceneID string) error {
+	shortcuts := make(map[string]map[string]string)
+	for room, keys := range m.sceneShortcuts() {
+		shortcuts[room] = maps.Clone(keys)
+	}
+	if shortcuts[roomID] == nil {
+		shortcuts[roomID] = make(map[string]string)
+	}
+	// A scene has a single key
+	for k, id := range shortcuts[roomID] {
+		if id == sceneID {
+			delete(shortcuts[roomID], k)
+		}
+	}
+	shortcuts[roomID][key] = sceneID
+
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		m.demoShortcuts = shortcuts
+	} else {
+		bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+		if err != nil {
+			return err
+		}
+		bridgeCfg.SceneShortcuts = shortcuts
+		if err := m.config.Save(); err != nil {
+			return err
+		}
+	}
+	m.applySceneShortcuts()
+	return nil
+}
+
+// applySceneShortcuts passes the scene shortcuts to the screens
+func (m *Model) applySceneShortcuts() {
+	shortcuts := m.sceneShortcuts()
+	m.mainScreen.SetSceneShortcuts(shortcuts)
+	m.scenesScreen.SetSceneShortcuts(shortcuts)
+}
+
+// setSceneSort remembers the order of the scenes modal
+func (m *Model) setSceneSort(sort string) error {
+	if m.config == nil || m.config.SceneSort == sort {
+		return nil
+	}
+	m.config.SceneSort = sort
+	if m.demoMode {
+		return nil
+	}
+	return m.config.Save()
+}
+
+// markSceneRecalled records the activation of a scene, for the scenes
+// sorted by last activated
+func (m *Model) markSceneRecalled(sceneID string) {
+	for _, scene := range m.scenes {
+		if scene.ID == sceneID {
+			scene.LastRecalled = time.Now()
+		}
+	}
+	m.scenesScreen.SetScenes(m.scenes, m.rooms)
+}
+
+// findScene returns the scene with the given ID, or nil
+func (m *Model) findScene(sceneID string) *models.Scene {
+	for _, scene := range m.scenes {
+		if scene.ID == sceneID {
+			return scene
+		}
+	}
+	return nil
+}
+
+// sceneLightIDs returns the lights a scene turns on, or all the lights of
+// its room when its actions aren't known
+func (m *Model) sceneLightIDs(sceneID string) []string {
+	scene := m.findScene(sceneID)
+	if scene == nil {
+		return nil
+	}
+	var ids []string
+	for _, action := range scene.Actions {
+		if action.On {
+			ids = append(ids, action.LightID)
+		}
+	}
+	if len(scene.Actions) > 0 {
+		return ids
+	}
+	for _, room := range m.rooms {
+		if room.ID == scene.RoomID {
+			for _, light := range room.Lights {
+				ids = append(ids, light.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// smartSceneCmd starts or stops a smart scene, then refetches the scenes
+// for its new status
+func (m Model) smartSceneCmd(scene *models.Scene, active bool) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	sceneID, name := scene.ID, scene.Name
+	fetch := m.fetchScenesCmd()
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+		if err := bridge.SetSmartSceneActive(ctx, sceneID, active); err != nil {
+			return messages.ErrorMsg{Err: fmt.Errorf("failed to switch %s: %w", name, err)}
+		}
+		return fetch()
+	}
+}
+
+// sceneTransitions returns the recall durations in milliseconds set on the
+// current bridge, by scene ID. Demo mode keeps them in memory only.
+func (m *Model) sceneTransitions() map[string]int {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		return m.demoTransitions
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return nil
+	}
+	return bridgeCfg.SceneTransitions
+}
+
+// setSceneTransition sets how long recalling a scene takes, or clears it
+func (m *Model) setSceneTransition(msg messages.SceneTransitionMsg) error {
+	transitions := maps.Clone(m.sceneTransitions())
+	if transitions == nil {
+		transitions = make(map[string]int)
+	}
+	if msg.Clear {
+		delete(transitions, msg.SceneID)
+	} else {
+		transitions[msg.SceneID] = int(msg.Duration.Milliseconds())
+	}
+
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		m.demoTransitions = transitions
+	} else {
+		bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+		if err != nil {
+			return err
+		}
+		bridgeCfg.SceneTransitions = transitions
+		if err := m.config.Save(); err != nil {
+			return err
+		}
+	}
+	m.applySceneTransitions()
+	return nil
+}
+
+// applySceneTransitions passes the scene recall durations to the scenes
+// modal
+func (m *Model) applySceneTransitions() {
+	transitions := make(map[string]time.Duration)
+	for id, ms := range m.sceneTransitions() {
+		transitions[id] = time.Duration(ms) * time.Millisecond
+	}
+	m.scenesScreen.SetSceneTransitions(transitions)
+}
+
+// defaultSceneFade is how long scenes recalled with alt+enter take to fade
+// in when scene_fade_seconds isn't set
+const defaultSceneFade = 30 * time.Second
+
+// formatSceneFade returns a fade duration like "30s" or "2m"
+func formatSceneFade(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%gs", d.Seconds())
+}
+
+// activateScene recalls a scene over its recall duration when it has one
+func activateScene(ctx context.Context, bridge api.BridgeClient, sceneID string, transitions map[string]int) error {
+	if ms, ok := transitions[sceneID]; ok {
+		return bridge.ActivateSceneOver(ctx, sceneID, time.Duration(ms)*time.Millisecond)
+	}
+	return bridge.ActivateScene(ctx, sceneID)
+}
+
+// fetchSceneCleanupCmd fetches the scenes with the rooms and zones they may
+// belong to, for the scene cleanup
+func (m Model) fetchSceneCleanupCmd() tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.ErrorMsg{Err: config.ErrNoBridges}
+		}
+		scenes, err := bridge.GetScenes(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		rooms, err := bridge.GetRooms(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		zones, err := bridge.GetZones(ctx)
+		if err != nil {
+			return messages.ErrorMsg{Err: err}
+		}
+		return messages.SceneCleanupFetchedMsg{Scenes: scenes, Groups: append(rooms, zones...)}
+	}
+}
+
+// deleteSceneCmd deletes a scene of a cleanup batch
+func (m Model) deleteSceneCmd(sceneID string) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	return func() tea.Msg {
+		if bridge == nil {
+			return messages.SceneDeletedMsg{SceneID: sceneID, Err: config.ErrNoBridges}
+		}
+		err := bridge.DeleteScene(ctx, sceneID)
+		if err == nil {
+			tuiLog.Infof("Deleted scene %s", sceneID)
+		}
+		return messages.SceneDeletedMsg{SceneID: sceneID, Err: err}
+	}
+}
@@ -0,0 +1,307 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/cron"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/sun"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// localScheduleInterval is how often local schedules are checked
+const localScheduleInterval = time.Minute
+
+// localScheduleConfigs returns the local schedules of the current bridge.
+// Demo mode keeps them in memory only.
+func (m *Model) localScheduleConfigs() []config.LocalSchedule {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		return m.demoSchedules
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return nil
+	}
+	return bridgeCfg.LocalSchedules
+}
+
+// saveLocalSchedules persists the local schedules
+func (m *Model) saveLocalSchedules(schedules []config.LocalSchedule) error {
+	if m.demoMode || m.bridge == nil || m.config == nil {
+		m.demoSchedules = schedules
+		return nil
+	}
+	bridgeCfg, err := m.config.GetBridge(m.bridge.BridgeID())
+	if err != nil {
+		return err
+	}
+	bridgeCfg.LocalSchedules = schedules
+	return m.config.Save()
+}
+
+// location returns the configured location, or nil
+func (m *Model) location() *config.Location {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.Location
+}
+
+// localSchedules returns the local schedules as shown on the schedules
+// screen, with sunrise and sunset resolved for the day of now
+func (m *Model) localSchedules(now time.Time) []*models.Schedule {
+	var schedules []*models.Schedule
+	for _, ls := range m.localScheduleConfigs() {
+		s := localSchedule(ls)
+		if at, ok := scheduleTime(s, m.location(), now); ok && s.Sun != "" {
+			s.Hour, s.Minute, s.HasTime = at.Hour(), at.Minute(), true
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules
+}
+
+// localSchedule converts a local schedule from the config
+func localSchedule(ls config.LocalSchedule) *models.Schedule {
+	s := &models.Schedule{
+		ID:       ls.ID,
+		Resource: models.ScheduleLocal,
+		Name:     ls.Name,
+		Kind:     models.ScheduleKind(ls.Kind),
+		Enabled:  !ls.Disabled,
+		GroupID:  ls.GroupID,
+		LightIDs: ls.LightIDs,
+		SceneID:  ls.SceneID,
+	}
+	if ls.Cron != "" {
+		s.Cron = ls.Cron
+		return s
+	}
+	if event, offset, ok := models.ParseSunTime(ls.At); ok {
+		s.Sun, s.SunOffset = event, offset
+	} else if t, err := time.Parse("15:04", ls.At); err == nil {
+		s.Hour, s.Minute, s.HasTime = t.Hour(), t.Minute(), true
+	}
+	for _, day := range models.EveryDay {
+		if len(ls.Days) == 0 || slices.Contains(ls.Days, strings.ToLower(day.String())) {
+			s.Days = append(s.Days, day)
+		}
+	}
+	return s
+}
+
+// scheduleTime returns when a schedule runs on the day of date. Sun-relative
+// schedules have no time without a location, or when the sun doesn't rise
+// or set that day.
+func scheduleTime(s *models.Schedule, loc *config.Location, date time.Time) (time.Time, bool) {
+	if s.Sun == "" {
+		if !s.HasTime {
+			return time.Time{}, false
+		}
+		y, mo, d := date.Date()
+		return time.Date(y, mo, d, s.Hour, s.Minute, 0, 0, date.Location()), true
+	}
+	if loc == nil {
+		return time.Time{}, false
+	}
+	sunrise, sunset, ok := sun.Times(date, loc.Latitude, loc.Longitude)
+	if !ok {
+		return time.Time{}, false
+	}
+	at := sunset
+	if s.Sun == models.Sunrise {
+		at = sunrise
+	}
+	return at.Add(s.SunOffset).Truncate(time.Minute), true
+}
+
+// dueSchedules returns the enabled schedules with a run in (from, to]
+func dueSchedules(schedules []*models.Schedule, loc *config.Location, from, to time.Time) []*models.Schedule {
+	var due []*models.Schedule
+	for _, s := range schedules {
+		if next, ok := nextRun(s, loc, from); ok && !next.After(to) {
+			due = append(due, s)
+		}
+	}
+	return due
+}
+
+// upcomingDays is how far ahead the runs of schedules with a time of day
+// are looked for
+const upcomingDays = 8
+
+// nextRun returns the first run of an enabled schedule after t
+func nextRun(s *models.Schedule, loc *config.Location, t time.Time) (time.Time, bool) {
+	if !s.Enabled {
+		return time.Time{}, false
+	}
+	if s.Cron != "" {
+		expr, err := cron.Parse(s.Cron)
+		if err != nil {
+			tuiLog.Warnf("Skipping schedule %s: %v", s.Name, err)
+			return time.Time{}, false
+		}
+		next := expr.Next(t)
+		return next, !next.IsZero()
+	}
+	// Offsets can move a run to the previous or next day
+	for day := t.AddDate(0, 0, -1); !day.After(t.AddDate(0, 0, upcomingDays)); day = day.AddDate(0, 0, 1) {
+		at, ok := scheduleTime(s, loc, day)
+		if ok && at.After(t) && slices.Contains(s.Days, day.Weekday()) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// upcomingRuns returns the next run of each schedule after now, soonest
+// first
+func upcomingRuns(schedules []*models.Schedule, loc *config.Location, now time.Time) []models.ScheduleRun {
+	var runs []models.ScheduleRun
+	for _, s := range schedules {
+		if at, ok := nextRun(s, loc, now); ok {
+			runs = append(runs, models.ScheduleRun{Schedule: s, At: at})
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].At.Before(runs[j].At) })
+	return runs
+}
+
+// addLocalSchedule saves a schedule the bridge can't run
+func (m *Model) addLocalSchedule(spec api.ScheduleSpec) error {
+	if spec.Sun != "" && m.location() == nil {
+		return errors.New("failed to add schedule: no location configured")
+	}
+	schedules := m.localScheduleConfigs()
+
+	ids := make(map[string]bool)
+	for _, s := range schedules {
+		ids[s.ID] = true
+	}
+	id := ""
+	for n := len(schedules) + 1; id == "" || ids[id]; n++ {
+		id = fmt.Sprintf("local-%d", n)
+	}
+
+	at := fmt.Sprintf("%02d:%02d", spec.Hour, spec.Minute)
+	if spec.Sun != "" {
+		at = models.FormatSunTime(spec.Sun, spec.SunOffset)
+	}
+	var days []string
+	for _, d := range spec.Days {
+		days = append(days, strings.ToLower(d.String()))
+	}
+	return m.saveLocalSchedules(append(slices.Clone(schedules), config.LocalSchedule{
+		ID:       id,
+		Name:     spec.Name,
+		Kind:     string(spec.Kind),
+		At:       at,
+		Days:     days,
+		GroupID:  spec.GroupID,
+		LightIDs: spec.LightIDs,
+	}))
+}
+
+// deleteLocalSchedule removes a local schedule
+func (m *Model) deleteLocalSchedule(id string) error {
+	schedules := slices.DeleteFunc(slices.Clone(m.localScheduleConfigs()), func(s config.LocalSchedule) bool {
+		return s.ID == id
+	})
+	return m.saveLocalSchedules(schedules)
+}
+
+// localScheduleTick checks local schedules at the start of every minute
+func localScheduleTick() tea.Cmd {
+	return tea.Every(localScheduleInterval, func(t time.Time) tea.Msg {
+		return messages.LocalScheduleTickMsg{Time: t}
+	})
+}
+
+// handleLocalScheduleTick runs the local schedules due since the last check
+func (m *Model) handleLocalScheduleTick(now time.Time) tea.Cmd {
+	cmds := []tea.Cmd{localScheduleTick()}
+	from := m.schedulesCheckedAt
+	m.schedulesCheckedAt = now
+	if m.bridge == nil || from.IsZero() {
+		return tea.Batch(cmds...)
+	}
+	for _, s := range dueSchedules(m.localSchedules(now), m.location(), from, now) {
+		tuiLog.Infof("Running local schedule %s", s.Name)
+		m.expectLightsOn(m.scheduleLightIDs(s))
+		cmds = append(cmds, m.runLocalScheduleCmd(s))
+	}
+	return tea.Batch(cmds...)
+}
+
+// scheduleLightIDs returns the lights a local schedule turns on
+func (m *Model) scheduleLightIDs(s *models.Schedule) []string {
+	switch s.Kind {
+	case models.ScheduleActivateScene:
+		return m.sceneLightIDs(s.SceneID)
+	case models.ScheduleTurnOn:
+		if len(s.LightIDs) > 0 {
+			return s.LightIDs
+		}
+		var ids []string
+		for _, room := range m.rooms {
+			if room.ID == s.GroupID {
+				for _, light := range room.Lights {
+					ids = append(ids, light.ID)
+				}
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+// runLocalScheduleCmd turns the lights of a local schedule on or off, or
+// activates its scene
+func (m Model) runLocalScheduleCmd(s *models.Schedule) tea.Cmd {
+	bridge := m.bridge
+	ctx := m.ctx
+	on := s.Kind == models.ScheduleTurnOn
+	scene := s.Kind == models.ScheduleActivateScene
+	sceneID := s.SceneID
+	transitions := m.sceneTransitions()
+	lightIDs := s.LightIDs
+	groupedLightID := ""
+	for _, room := range m.rooms {
+		if room.ID == s.GroupID {
+			groupedLightID = room.GroupedLightID
+		}
+	}
+	name := s.Name
+
+	return func() tea.Msg {
+		var err error
+		switch {
+		case scene && sceneID == "":
+			err = errors.New("no scene_id set")
+		case scene:
+			err = activateScene(ctx, bridge, sceneID, transitions)
+		case len(lightIDs) > 0:
+			for _, id := range lightIDs {
+				if lightErr := bridge.SetLightOn(ctx, id, on); lightErr != nil && err == nil {
+					err = lightErr
+				}
+			}
+		case groupedLightID != "":
+			err = bridge.SetGroupedLightOn(ctx, groupedLightID, on)
+		default:
+			err = errors.New("room not found")
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to run schedule %q: %w", name, err)
+		}
+		return messages.LocalScheduleRanMsg{Name: name, Err: err}
+	}
+}
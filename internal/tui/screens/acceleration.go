@@ -0,0 +1,65 @@
+package screens
+
+import "time"
+
+// Dimming acceleration, growing the arrow key step while a key is held
+const (
+	// AccelerationOff keeps the step fixed
+	AccelerationOff = ""
+	// AccelerationNormal triples the step at most
+	AccelerationNormal = "normal"
+	// AccelerationFast grows the step quicker, up to five times
+	AccelerationFast = "fast"
+)
+
+// repeatWindow is the longest gap between two presses of a key that are
+// still taken as auto-repeat. Terminals repeat every 30 to 100ms, well
+// below it, while separate presses are further apart.
+const repeatWindow = 150 * time.Millisecond
+
+// accelerationCurve is how many repeats it takes for the step to grow by
+// one more multiple, and the largest multiple
+type accelerationCurve struct {
+	every, most int
+}
+
+var accelerationCurves = map[string]accelerationCurve{
+	AccelerationNormal: {every: 4, most: 3},
+	AccelerationFast:   {every: 2, most: 5},
+}
+
+// keyRepeat is the burst of auto-repeats of the last key
+type keyRepeat struct {
+	key     string
+	last    time.Time
+	repeats int
+}
+
+// SetAcceleration sets how the dimming step grows while an arrow key is
+// held, one of the Acceleration values
+func (m *MainModel) SetAcceleration(mode string) {
+	if _, ok := accelerationCurves[mode]; !ok {
+		mode = AccelerationOff
+	}
+	m.acceleration = mode
+}
+
+// acceleratedStep scales the step of a key press by how long the key has
+// been repeating. The bridge gets no more requests for it, as writes to a
+// light are coalesced, the lights just get there faster.
+func (m *MainModel) acceleratedStep(key string, step int) int {
+	now := m.now()
+	if key == m.repeat.key && now.Sub(m.repeat.last) < repeatWindow {
+		m.repeat.repeats++
+	} else {
+		m.repeat.repeats = 0
+	}
+	m.repeat.key = key
+	m.repeat.last = now
+
+	curve, ok := accelerationCurves[m.acceleration]
+	if !ok {
+		return step
+	}
+	return step * min(curve.most, 1+m.repeat.repeats/curve.every)
+}
@@ -0,0 +1,133 @@
+package screens
+
+import (
+	"context"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// archetypeRows is the number of archetypes the picker shows at once
+const archetypeRows = 8
+
+// archetypePicker picks the type of the selected light in the side panel
+type archetypePicker struct {
+	lightID string
+	// Typed filter, and the selection within the matching archetypes
+	query    string
+	selected int
+}
+
+// matches returns the archetypes matching the typed filter
+func (p *archetypePicker) matches() []string {
+	var matches []string
+	for _, a := range models.LightArchetypes {
+		if fuzzyMatch(models.ArchetypeLabel(a), p.query) {
+			matches = append(matches, a)
+		}
+	}
+	return matches
+}
+
+// startArchetypePicker opens the archetype picker for the selected light,
+// on its current archetype
+func (m *MainModel) startArchetypePicker() {
+	light := m.SelectedLight()
+	if light == nil || m.IsRoomSelected() {
+		return
+	}
+	m.archetype = &archetypePicker{lightID: light.ID}
+	for i, a := range models.LightArchetypes {
+		if a == light.Archetype {
+			m.archetype.selected = i
+		}
+	}
+	m.showPanel = true
+}
+
+// updateArchetypePicker handles keys while the archetype picker is open.
+// enter sets the selected archetype, esc cancels, other keys filter.
+func (m *MainModel) updateArchetypePicker(msg tea.KeyMsg, bridge api.BridgeClient) tea.Cmd {
+	p := m.archetype
+	matches := p.matches()
+
+	switch msg.String() {
+	case "esc":
+		m.archetype = nil
+
+	case "up", "ctrl+p":
+		if p.selected > 0 {
+			p.selected--
+		}
+
+	case "down", "ctrl+n":
+		if p.selected < len(matches)-1 {
+			p.selected++
+		}
+
+	case "backspace":
+		if p.query != "" {
+			runes := []rune(p.query)
+			p.query = string(runes[:len(runes)-1])
+			p.selected = 0
+		}
+
+	case "enter":
+		if p.selected >= len(matches) {
+			return nil
+		}
+		m.archetype = nil
+		light := m.findLight(p.lightID)
+		if light == nil {
+			return nil
+		}
+		archetype := matches[p.selected]
+		before := light.Clone()
+		light.Archetype = archetype
+		m.notice = light.Name + " is now a " + strings.ToLower(models.ArchetypeLabel(archetype))
+		return runLightCalls(bridge, []lightBatch{{
+			lightID: light.ID,
+			before:  before,
+			after:   light.Clone(),
+			calls: lightCalls{{"change the type of %s", func(ctx context.Context, bridge api.BridgeClient) error {
+				return bridge.SetLightArchetype(ctx, light.ID, archetype)
+			}}},
+		}})
+
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			p.query += string(msg.Runes)
+			p.selected = 0
+		}
+	}
+	return nil
+}
+
+// renderArchetypePicker renders the open archetype picker for the side
+// panel, scrolled to keep the selection visible
+func (m MainModel) renderArchetypePicker() string {
+	p := m.archetype
+	matches := p.matches()
+
+	var b strings.Builder
+	b.WriteString(styleMuted.Render("Type: "))
+	b.WriteString(p.query + "█\n")
+
+	start := max(0, min(p.selected-archetypeRows/2, len(matches)-archetypeRows))
+	for i := start; i < len(matches) && i < start+archetypeRows; i++ {
+		label := models.ArchetypeLabel(matches[i])
+		if i == p.selected {
+			b.WriteString(styleSelected.Render("> " + label))
+		} else {
+			b.WriteString("  " + label)
+		}
+		b.WriteString("\n")
+	}
+	if len(matches) == 0 {
+		b.WriteString(styleMuted.Render("  no match") + "\n")
+	}
+	b.WriteString(styleMuted.Render("enter set · esc cancel"))
+	return b.String()
+}
@@ -0,0 +1,275 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BridgeEntry is a configured bridge in the bridge picker
+type BridgeEntry struct {
+	Host     string
+	BridgeID string
+}
+
+// BridgesModel is the bridge picker: the configured bridges to switch
+// to, and a last row to pair another one. Below them are the system
+// details of the bridge in use.
+type BridgesModel struct {
+	bridges []BridgeEntry
+	// Bridge ID of the bridge in use
+	current  string
+	selected int
+	// Whether another bridge can be paired, not in demo mode
+	canAdd bool
+
+	info        *models.BridgeInfo
+	infoErr     error
+	infoLoading bool
+	// Whether the bridge has an event stream, and when it connected (zero
+	// while disconnected)
+	hasStream   bool
+	streamSince time.Time
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewBridgesModel creates a new bridge picker model
+func NewBridgesModel() BridgesModel {
+	return BridgesModel{}
+}
+
+// SetSize sets the terminal size
+func (m *BridgesModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetBridges sets the configured bridges, selecting the one in use.
+// canAdd shows the row to pair another bridge.
+func (m *BridgesModel) SetBridges(bridges []BridgeEntry, current string, canAdd bool) {
+	m.bridges = bridges
+	m.current = current
+	m.canAdd = canAdd
+	m.selected = 0
+	for i, bridge := range bridges {
+		if bridge.BridgeID == current {
+			m.selected = i
+		}
+	}
+}
+
+// SetInfoLoading marks the system details as being fetched
+func (m *BridgesModel) SetInfoLoading() {
+	m.infoLoading = true
+}
+
+// SetInfo sets the system details of the bridge in use, or why they
+// couldn't be fetched
+func (m *BridgesModel) SetInfo(info *models.BridgeInfo, err error) {
+	m.info = info
+	m.infoErr = err
+	m.infoLoading = false
+}
+
+// SetStream sets whether the bridge has an event stream, and since when it
+// is connected (zero while disconnected)
+func (m *BridgesModel) SetStream(hasStream bool, connectedAt time.Time) {
+	m.hasStream = hasStream
+	m.streamSince = connectedAt
+}
+
+// onAdd reports whether the "Add bridge" row is selected
+func (m BridgesModel) onAdd() bool {
+	return m.canAdd && m.selected == len(m.bridges)
+}
+
+// lastRow is the index of the last selectable row
+func (m BridgesModel) lastRow() int {
+	if m.canAdd {
+		return len(m.bridges)
+	}
+	return max(0, len(m.bridges)-1)
+}
+
+// Update handles messages
+func (m BridgesModel) Update(msg tea.Msg) (BridgesModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "B", "q":
+		return m, func() tea.Msg { return messages.HideBridgesMsg{} }
+
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+
+	case "down", "j":
+		if m.selected < m.lastRow() {
+			m.selected++
+		}
+
+	case "a":
+		if m.canAdd {
+			return m, func() tea.Msg { return messages.AddBridgeMsg{} }
+		}
+
+	case "r":
+		m.infoLoading = true
+		return m, func() tea.Msg { return messages.FetchBridgeInfoMsg{} }
+
+	case "enter":
+		if m.onAdd() {
+			return m, func() tea.Msg { return messages.AddBridgeMsg{} }
+		}
+		if m.selected >= len(m.bridges) {
+			return m, nil
+		}
+		bridge := m.bridges[m.selected]
+		if bridge.BridgeID == m.current {
+			return m, func() tea.Msg { return messages.HideBridgesMsg{} }
+		}
+		return m, func() tea.Msg { return messages.SwitchBridgeMsg{BridgeID: bridge.BridgeID} }
+	}
+
+	return m, nil
+}
+
+// View renders the bridge picker
+func (m BridgesModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleModalTitle.Render("Bridges"))
+	b.WriteString("\n\n")
+
+	for i, bridge := range m.bridges {
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if i == m.selected {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		name := bridge.Host
+		if len(bridge.BridgeID) >= 8 {
+			name = fmt.Sprintf("%s (%s)", bridge.Host, bridge.BridgeID[:8])
+		}
+		line := cursor + style.Render(name)
+		if bridge.BridgeID == m.current {
+			line += styles.StyleSuccess.UnsetBold().Render("  ● in use")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if m.canAdd {
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if m.onAdd() {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		b.WriteString("\n" + cursor + style.Render("+ Add bridge…") + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.renderInfo())
+	b.WriteString("\n")
+	help := "↑/↓ navigate • enter switch • a add bridge • r refresh • esc close"
+	if !m.canAdd {
+		help = "r refresh • esc close"
+	}
+	b.WriteString(styles.StyleHelp.Render(help))
+
+	content := b.String()
+	modalWidth := m.width * 70 / 100
+	if modalWidth < 44 {
+		modalWidth = 44
+	}
+	if modalWidth > 64 {
+		modalWidth = 64
+	}
+	modal := styles.StyleModal.Width(modalWidth).Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+// renderInfo renders the system details of the bridge in use
+func (m BridgesModel) renderInfo() string {
+	var b strings.Builder
+	b.WriteString(styles.StyleTextMuted.Bold(true).Render("Bridge in use"))
+	b.WriteString("\n")
+
+	switch {
+	case m.infoLoading && m.info == nil:
+		b.WriteString(styles.StyleTextMuted.Render("  Loading...") + "\n")
+		return b.String()
+	case m.infoErr != nil:
+		b.WriteString(styles.StyleError.Render("  "+m.infoErr.Error()) + "\n")
+		return b.String()
+	case m.info == nil:
+		return b.String()
+	}
+
+	info := m.info
+	name := info.Name
+	if info.ModelID != "" {
+		name += " (" + info.ModelID + ")"
+	}
+	firmware := info.SoftwareVersion
+	if info.APIVersion != "" {
+		firmware += ", API " + info.APIVersion
+	}
+	update := info.UpdateStatus()
+	if info.UpdateState != "noupdates" {
+		update = styles.StylePrimary.Render(update)
+	}
+	events := "not available"
+	if m.hasStream {
+		events = "disconnected"
+		if !m.streamSince.IsZero() {
+			events = "connected for " + formatUptime(time.Since(m.streamSince))
+		}
+	}
+
+	rows := [][2]string{
+		{"Name", name},
+		{"Firmware", firmware},
+		{"Updates", update},
+		{"Zigbee", fmt.Sprintf("channel %d", info.ZigbeeChannel)},
+		{"Resources", fmt.Sprintf("%d", info.Resources)},
+		{"Latency", info.Latency.Round(time.Millisecond).String()},
+		{"Events", events},
+	}
+	if info.TimeZone != "" {
+		rows = append(rows, [2]string{"Time zone", info.TimeZone})
+	}
+	for _, row := range rows {
+		b.WriteString("  " + styles.StyleTextMuted.Render(fmt.Sprintf("%-10s", row[0])) + row[1] + "\n")
+	}
+	return b.String()
+}
+
+// formatUptime renders how long a connection has been up ("45s", "12m",
+// "3h 5m", "2d 4h")
+func formatUptime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+}
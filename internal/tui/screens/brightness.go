@@ -0,0 +1,193 @@
+package screens
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Brightness steps for the arrow keys, and shift+arrows for fine control
+const (
+	brightnessStep     = 10
+	brightnessFineStep = 1
+)
+
+// stepBrightness dims or brightens by step percent: every lit light of the
+// selected room, or the target lights
+func (m *MainModel) stepBrightness(bridge api.BridgeClient, step int, pending pendingFuncs) tea.Cmd {
+	if len(m.marked) > 0 || !m.IsRoomSelected() {
+		return m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+			return stepLightBrightness(light, step, pending)
+		})
+	}
+
+	room := m.SelectedRoom()
+	if room == nil {
+		return nil
+	}
+	// Room dimming stops at the step size instead of turning lights off
+	floor := min(brightnessStep, step)
+	dir := DirUp
+	if step < 0 {
+		floor = min(brightnessStep, -step)
+		dir = DirDown
+	}
+	if m.roomDimming == RoomDimmingCommit && room.GroupedLightID != "" {
+		return m.previewRoomDim(room, step, floor)
+	}
+	return m.applyToLights(bridge, room.Lights, func(light *models.Light) lightCalls {
+		if !light.On || light.OnOffOnly {
+			return nil
+		}
+		newBrightness := min(100, max(floor, light.BrightnessPct()+step))
+		light.SetBrightnessPct(newBrightness)
+		pending.addOp(light.ID, "brightness", newBrightness, dir)
+		return lightCalls{callSetBrightness(light.ID, newBrightness)}
+	})
+}
+
+// brightnessTargets returns the lights an exact brightness applies to: the
+// whole selected room, or the target lights
+func (m *MainModel) brightnessTargets() []*models.Light {
+	if len(m.marked) == 0 && m.IsRoomSelected() {
+		if room := m.SelectedRoom(); room != nil {
+			return room.Lights
+		}
+	}
+	return m.targetLights()
+}
+
+// startBrightnessInput opens the brightness input in the side panel,
+// prefilled with the current brightness
+func (m *MainModel) startBrightnessInput() tea.Cmd {
+	var current uint8
+	if light := m.SelectedLight(); light != nil && !m.IsRoomSelected() {
+		current = light.Brightness
+	} else if room := m.SelectedRoom(); room != nil {
+		current = models.PctToLevel(float64(room.AverageBrightness()))
+	}
+	m.editingBrightness = true
+	m.showPanel = true
+	m.brightnessInput.SetValue(m.brightnessValue(current))
+	m.brightnessInput.CursorEnd()
+	return m.brightnessInput.Focus()
+}
+
+// updateBrightnessInput handles keys while the brightness input is open.
+// enter applies the value as one undo step, esc cancels.
+func (m *MainModel) updateBrightnessInput(msg tea.KeyMsg, bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.stopBrightnessInput()
+		return nil
+
+	case "enter":
+		brightness, ok := m.parseBrightnessInput(m.brightnessInput.Value())
+		if !ok {
+			m.brightnessError = "enter a value from 0 to " + m.brightnessMax()
+			return nil
+		}
+		m.stopBrightnessInput()
+
+		before := m.captureLights()
+		cmd := m.applyToLights(bridge, m.brightnessTargets(), func(light *models.Light) lightCalls {
+			if brightness == 0 {
+				if !light.On {
+					return nil
+				}
+				return setLightOn(light, false, pending)
+			}
+			return setLightBrightness(light, brightness, pending)
+		})
+		m.history.record(before, m.captureLights())
+		return tea.Batch(cmd, m.syncLinks(bridge, pending))
+	}
+
+	// Only digits go in
+	if msg.Type == tea.KeyRunes {
+		for _, r := range msg.Runes {
+			if r < '0' || r > '9' {
+				return nil
+			}
+		}
+	}
+	m.brightnessError = ""
+	var cmd tea.Cmd
+	m.brightnessInput, cmd = m.brightnessInput.Update(msg)
+	return cmd
+}
+
+func (m *MainModel) stopBrightnessInput() {
+	m.editingBrightness = false
+	m.brightnessError = ""
+	m.brightnessInput.Blur()
+}
+
+// parseBrightness reads a percentage from 0 to 100. 0 turns lights off.
+func parseBrightness(s string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(s, "%")))
+	if err != nil || n < 0 || n > 100 {
+		return 0, false
+	}
+	return n, true
+}
+
+// SetLocale sets how brightness is shown
+func (m *MainModel) SetLocale(format locale.Format) {
+	m.format = format
+}
+
+// brightnessValue returns a brightness level as typed in the inputs, in
+// percent or on the 0-254 scale
+func (m *MainModel) brightnessValue(level uint8) string {
+	if m.format.RawBrightness() {
+		return strconv.Itoa(int(level))
+	}
+	return strconv.Itoa(models.LevelToPct(level))
+}
+
+// brightnessMax returns the top of the brightness scale in use
+func (m *MainModel) brightnessMax() string {
+	if m.format.RawBrightness() {
+		return strconv.Itoa(models.MaxLevel)
+	}
+	return "100"
+}
+
+// parseBrightnessInput reads a typed brightness as a percentage. On the
+// 0-254 scale, levels round to the nearest percentage, but never to 0 as
+// that turns lights off.
+func (m *MainModel) parseBrightnessInput(s string) (int, bool) {
+	if !m.format.RawBrightness() {
+		return parseBrightness(s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 || n > models.MaxLevel {
+		return 0, false
+	}
+	if n == 0 {
+		return 0, true
+	}
+	return max(1, models.LevelToPct(uint8(n))), true
+}
+
+// renderBrightnessInput renders the open brightness input for the side panel
+func (m MainModel) renderBrightnessInput() string {
+	var b strings.Builder
+	b.WriteString(styleMuted.Render("Brightness: "))
+	b.WriteString(m.brightnessInput.View())
+	if !m.format.RawBrightness() {
+		b.WriteString("%")
+	}
+	b.WriteString("\n")
+	if m.brightnessError != "" {
+		b.WriteString(styleLightFaulty.Render(m.brightnessError))
+		b.WriteString("\n")
+	}
+	b.WriteString(styleMuted.Render("enter set · esc cancel"))
+	return b.String()
+}
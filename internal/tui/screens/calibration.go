@@ -0,0 +1,108 @@
+package screens
+
+import (
+	"fmt"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Calibration steps in mirek, and the largest offset
+const (
+	calibrationStep      = 5
+	maxCalibrationOffset = 100
+)
+
+// calibration matches the white of a light to a reference light
+type calibration struct {
+	reference *models.Light
+	light     *models.Light
+	mirek     int
+	// Offset on trial, and the one saved before calibrating
+	offset int
+	saved  int
+}
+
+// SetColorTempOffsets sets the saved calibration offsets, by light ID
+func (m *MainModel) SetColorTempOffsets(offsets map[string]int) {
+	m.colorTempOffsets = offsets
+}
+
+// startCalibration shows the two marked lights at the same temperature so
+// the second one can be stepped until both look the same
+func (m *MainModel) startCalibration(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	lights := m.targetLights()
+	if len(m.marked) != 2 || len(lights) != 2 || !lights[0].SupportsColorTemp || !lights[1].SupportsColorTemp ||
+		lights[0].Color == nil || lights[1].Color == nil {
+		m.notice = "Mark two white ambiance lights with v to calibrate them"
+		return nil
+	}
+
+	mirek := 366
+	if c := lights[0].Color; c.Mode == models.ColorModeColorTemp && c.Mirek > 0 {
+		mirek = int(c.Mirek)
+	}
+	saved := m.colorTempOffsets[lights[1].ID]
+	m.calibration = &calibration{reference: lights[0], light: lights[1], mirek: mirek, offset: saved, saved: saved}
+
+	cmd := m.applyToLights(bridge, lights, func(light *models.Light) lightCalls {
+		light.Color.Mirek = uint16(mirek)
+		light.Color.Mode = models.ColorModeColorTemp
+		light.Color.InvalidateCache()
+		if !light.On {
+			return setLightOn(light, true, pending)
+		}
+		return nil
+	})
+	return tea.Batch(cmd, m.calibrationStepCmd())
+}
+
+// updateCalibration handles keys while calibrating: w and c step the
+// light warmer or cooler, enter saves the offset, esc restores the old one
+func (m *MainModel) updateCalibration(msg tea.KeyMsg) tea.Cmd {
+	c := m.calibration
+	switch msg.String() {
+	case "w":
+		c.offset = min(maxCalibrationOffset, c.offset+calibrationStep)
+		return m.calibrationStepCmd()
+
+	case "c":
+		c.offset = max(-maxCalibrationOffset, c.offset-calibrationStep)
+		return m.calibrationStepCmd()
+
+	case "enter":
+		m.calibration = nil
+		m.notice = fmt.Sprintf("Calibrated %s: %+d mirek", c.light.Name, c.offset)
+		lightID, offset := c.light.ID, c.offset
+		return func() tea.Msg {
+			return messages.ColorTempOffsetMsg{LightID: lightID, Offset: offset}
+		}
+
+	case "esc":
+		c.offset = c.saved
+		cmd := m.calibrationStepCmd()
+		m.calibration = nil
+		return cmd
+	}
+	return nil
+}
+
+// calibrationStepCmd shows the current calibration step on the lights
+func (m MainModel) calibrationStepCmd() tea.Cmd {
+	step := messages.CalibrationStepMsg{
+		ReferenceID: m.calibration.reference.ID,
+		LightID:     m.calibration.light.ID,
+		Mirek:       m.calibration.mirek,
+		Offset:      m.calibration.offset,
+	}
+	return func() tea.Msg { return step }
+}
+
+// renderCalibration renders the calibration status for the status bar
+func (m MainModel) renderCalibration() string {
+	c := m.calibration
+	return styleSearch.Render(fmt.Sprintf("Calibrating %s against %s: %+d mirek", c.light.Name, c.reference.Name, c.offset)) +
+		styleMuted.Render("  w warmer · c cooler · enter save · esc cancel")
+}
@@ -0,0 +1,73 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// leaderKey starts a key chord: the next key picks the action
+const leaderKey = "g"
+
+// chord is an action bound to the leader key followed by another key
+type chord struct {
+	key  string
+	desc string
+	run  func(m *MainModel) tea.Cmd
+}
+
+// chords are the leader key bindings, in the order shown in the status bar
+var chords = []chord{
+	{"g", "top", func(m *MainModel) tea.Cmd {
+		m.selectedIndex = 0
+		m.ensureVisible()
+		return nil
+	}},
+	{"s", "scenes", func(m *MainModel) tea.Cmd {
+		roomID := ""
+		if room := m.SelectedRoom(); room != nil && !isHome(room) && !isTypeRoom(room) {
+			roomID = room.ID
+		}
+		return func() tea.Msg { return messages.ShowScenesMsg{RoomID: roomID} }
+	}},
+	{"e", "entertainment", func(m *MainModel) tea.Cmd {
+		return func() tea.Msg { return messages.ShowEntertainmentMsg{} }
+	}},
+	{"a", "schedules", func(m *MainModel) tea.Cmd {
+		return func() tea.Msg { return messages.ShowSchedulesMsg{} }
+	}},
+	{"r", "rooms", func(m *MainModel) tea.Cmd {
+		return func() tea.Msg { return messages.ShowRoomsMsg{} }
+	}},
+	{"d", "devices", func(m *MainModel) tea.Cmd {
+		return func() tea.Msg { return messages.ShowDevicesMsg{} }
+	}},
+	{"c", "scene cleanup", func(m *MainModel) tea.Cmd {
+		return func() tea.Msg { return messages.ShowSceneCleanupMsg{} }
+	}},
+	{"t", "by type", func(m *MainModel) tea.Cmd {
+		m.toggleByType()
+		return nil
+	}},
+}
+
+// finishChord runs the chord completed by key. Unbound keys just cancel it.
+func (m *MainModel) finishChord(key string) tea.Cmd {
+	m.chord = ""
+	for _, c := range chords {
+		if c.key == key {
+			return c.run(m)
+		}
+	}
+	return nil
+}
+
+// chordHint lists the keys that can follow the pending chord
+func chordHint() string {
+	hints := make([]string, len(chords))
+	for i, c := range chords {
+		hints[i] = styleHelpKey.Render(c.key) + " " + c.desc
+	}
+	return strings.Join(hints, "  ")
+}
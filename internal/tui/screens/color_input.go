@@ -0,0 +1,257 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fields of the advanced color input
+const (
+	colorFieldHue = iota
+	colorFieldSat
+	colorFieldBri
+	colorFieldTemp
+	colorFieldCount
+)
+
+var colorFieldLabels = [colorFieldCount]string{
+	"Hue:        ",
+	"Saturation: ",
+	"Brightness: ",
+	"Temp:       ",
+}
+
+var colorFieldUnits = [colorFieldCount]string{"°", "%", "%", "K"}
+
+// newColorInputs creates the text inputs of the advanced color input
+func newColorInputs() [colorFieldCount]textinput.Model {
+	var inputs [colorFieldCount]textinput.Model
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].CharLimit = 5
+		inputs[i].Width = 6
+		inputs[i].Prompt = ""
+	}
+	return inputs
+}
+
+// startColorInput opens the advanced color input in the side panel,
+// prefilled with the values of the selected light, or of the first lit
+// light of the selected room
+func (m *MainModel) startColorInput() tea.Cmd {
+	var light *models.Light
+	if l := m.SelectedLight(); l != nil && !m.IsRoomSelected() {
+		light = l
+	} else if room := m.SelectedRoom(); room != nil {
+		for _, l := range room.Lights {
+			if light == nil || (l.On && !light.On) {
+				light = l
+			}
+		}
+	}
+
+	values := [colorFieldCount]string{}
+	if light != nil {
+		values[colorFieldBri] = m.brightnessValue(light.Brightness)
+		if room := m.SelectedRoom(); room != nil && m.IsRoomSelected() {
+			values[colorFieldBri] = m.brightnessValue(models.PctToLevel(float64(room.AverageBrightness())))
+		}
+		if c := light.Color; c != nil {
+			hue, sat := rgbToHueSat(c.RGB())
+			if c.Mode == models.ColorModeHS {
+				hue = models.HueToDeg(c.Hue)
+				sat = models.LevelToPct(c.Saturation)
+			}
+			if light.SupportsColor {
+				values[colorFieldHue] = strconv.Itoa(hue % 360)
+				values[colorFieldSat] = strconv.Itoa(sat)
+			}
+			if light.SupportsColorTemp && c.Mirek > 0 {
+				values[colorFieldTemp] = strconv.Itoa(models.MirekToKelvin(int(c.Mirek)))
+			}
+		}
+	}
+
+	for i := range m.colorInputs {
+		m.colorInputs[i].SetValue(values[i])
+		m.colorInputs[i].CursorEnd()
+		m.colorInputs[i].Blur()
+	}
+	m.colorInitial = values
+	m.colorFocus = colorFieldHue
+	m.colorError = ""
+	m.editingColor = true
+	m.showPanel = true
+	return m.colorInputs[m.colorFocus].Focus()
+}
+
+// updateColorInput handles keys while the advanced color input is open.
+// tab and the arrows move between fields, enter applies every changed
+// field as one undo step, esc cancels.
+func (m *MainModel) updateColorInput(msg tea.KeyMsg, bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.stopColorInput()
+		return nil
+
+	case "tab", "down":
+		return m.focusColorField((m.colorFocus + 1) % colorFieldCount)
+
+	case "shift+tab", "up":
+		return m.focusColorField((m.colorFocus + colorFieldCount - 1) % colorFieldCount)
+
+	case "enter":
+		return m.applyColorInput(bridge, pending)
+	}
+
+	// Only digits go in, and a K or % suffix
+	if msg.Type == tea.KeyRunes {
+		for _, r := range msg.Runes {
+			if (r < '0' || r > '9') && !strings.ContainsRune("kK%", r) {
+				return nil
+			}
+		}
+	}
+	m.colorError = ""
+	var cmd tea.Cmd
+	m.colorInputs[m.colorFocus], cmd = m.colorInputs[m.colorFocus].Update(msg)
+	return cmd
+}
+
+func (m *MainModel) focusColorField(field int) tea.Cmd {
+	m.colorInputs[m.colorFocus].Blur()
+	m.colorFocus = field
+	return m.colorInputs[field].Focus()
+}
+
+func (m *MainModel) stopColorInput() {
+	m.editingColor = false
+	m.colorError = ""
+	m.colorInputs[m.colorFocus].Blur()
+}
+
+// applyColorInput validates the changed fields and applies them to the
+// same lights as the brightness input
+func (m *MainModel) applyColorInput(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	// -1 leaves a field unchanged
+	values := [colorFieldCount]int{-1, -1, -1, -1}
+	for i := range m.colorInputs {
+		value := strings.TrimSpace(m.colorInputs[i].Value())
+		if value == m.colorInitial[i] {
+			continue
+		}
+		var n int
+		var ok bool
+		if i == colorFieldBri {
+			n, ok = m.parseBrightnessInput(strings.TrimSuffix(value, "%"))
+		} else {
+			n, ok = parseColorField(i, value)
+		}
+		if !ok {
+			m.colorError = m.colorFieldError(i)
+			return m.focusColorField(i)
+		}
+		values[i] = n
+	}
+	hue, sat, bri, mirek := values[colorFieldHue], values[colorFieldSat], values[colorFieldBri], values[colorFieldTemp]
+	if (hue >= 0 || sat >= 0) && mirek >= 0 {
+		m.colorError = "set a color or a temperature, not both"
+		return nil
+	}
+	m.stopColorInput()
+	if hue < 0 && sat < 0 && bri < 0 && mirek < 0 {
+		return nil
+	}
+
+	before := m.captureLights()
+	targets := m.brightnessTargets()
+	cmd := m.applyToLights(bridge, targets, func(light *models.Light) lightCalls {
+		var calls lightCalls
+		if bri == 0 {
+			if !light.On {
+				return nil
+			}
+			return setLightOn(light, false, pending)
+		}
+		if bri > 0 {
+			calls = append(calls, setLightBrightness(light, bri, pending)...)
+		}
+		if hue >= 0 || sat >= 0 {
+			calls = append(calls, setLightHueSat(light, hue, sat, pending)...)
+		}
+		if mirek >= 0 {
+			calls = append(calls, setLightColorTemp(light, mirek, pending)...)
+		}
+		return calls
+	})
+	m.history.record(before, m.captureLights())
+	if hue >= 0 || sat >= 0 {
+		m.warnOutsideGamut(targets)
+	}
+	return tea.Batch(cmd, m.syncLinks(bridge, pending))
+}
+
+// parseColorField reads the value of a color field: hue in degrees,
+// saturation in percent, and the temperature in kelvin or mirek, returned
+// as mirek
+func parseColorField(field int, s string) (int, bool) {
+	s = strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(s), "%"), "K")
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	switch field {
+	case colorFieldHue:
+		return n, n <= 360
+	case colorFieldSat:
+		return n, n <= 100
+	default:
+		// Small values are mirek, large ones kelvin
+		if n >= 1000 {
+			n = models.KelvinToMirek(n)
+		}
+		return n, n >= models.MirekMin && n <= models.MirekMax
+	}
+}
+
+func (m *MainModel) colorFieldError(field int) string {
+	switch field {
+	case colorFieldHue:
+		return "hue goes from 0 to 360°"
+	case colorFieldSat:
+		return "saturation goes from 0 to 100%"
+	case colorFieldBri:
+		return "brightness goes from 0 to " + m.brightnessMax()
+	default:
+		return "temperature goes from 2000K to 6500K (153-500 mirek)"
+	}
+}
+
+// renderColorInput renders the open advanced color input for the side panel
+func (m MainModel) renderColorInput() string {
+	var b strings.Builder
+	for i, input := range m.colorInputs {
+		cursor := "  "
+		if i == m.colorFocus {
+			cursor = styleSearch.Render("> ")
+		}
+		b.WriteString(cursor + styleMuted.Render(colorFieldLabels[i]))
+		unit := colorFieldUnits[i]
+		if i == colorFieldBri && m.format.RawBrightness() {
+			unit = ""
+		}
+		b.WriteString(fmt.Sprintf("%s%s\n", input.View(), unit))
+	}
+	if m.colorError != "" {
+		b.WriteString(styleLightFaulty.Render(m.colorError))
+		b.WriteString("\n")
+	}
+	b.WriteString(styleMuted.Render("tab next · enter set · esc cancel"))
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package screens
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// wheelSize is the diameter of the color wheel in pixels. A cell stacks
+// two pixels with a half block, so the wheel takes wheelSize columns and
+// half as many lines, and looks about round.
+const wheelSize = 13
+
+// colorWheel is the cursor moved over the color wheel of the side panel.
+// Its color is previewed in the panel until enter sets it.
+type colorWheel struct {
+	lightID string
+	// Pixel under the cursor, y counting half lines
+	x, y int
+}
+
+// moveColorWheel moves the wheel cursor with alt+h/j/k/l, bringing it out
+// on the color of the selected light first. Other keys are ignored.
+func (m *MainModel) moveColorWheel(key string) {
+	dx, dy := 0, 0
+	switch key {
+	case "alt+h":
+		dx = -1
+	case "alt+l":
+		dx = 1
+	case "alt+k":
+		dy = -1
+	case "alt+j":
+		dy = 1
+	default:
+		return
+	}
+
+	if m.colorWheel == nil {
+		light := m.SelectedLight()
+		if light == nil || m.IsRoomSelected() || !light.SupportsColor || light.Color == nil {
+			m.notice = "Select a color light to use the color wheel"
+			return
+		}
+		hue, sat := wheelHueSat(light.Color)
+		x, y := wheelPixel(hue, sat)
+		m.colorWheel = &colorWheel{lightID: light.ID, x: x, y: y}
+		m.showPanel = true
+	}
+	// The cursor stays on the wheel
+	w := m.colorWheel
+	if _, _, ok := wheelColor(w.x+dx, w.y+dy); ok {
+		w.x += dx
+		w.y += dy
+	}
+}
+
+// updateColorWheel handles keys while the wheel cursor is out: alt+h/j/k/l
+// move it, enter sets its color on the targeted lights, esc cancels
+func (m *MainModel) updateColorWheel(msg tea.KeyMsg, bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.colorWheel = nil
+	case "enter":
+		return m.applyColorWheel(bridge, pending)
+	default:
+		m.moveColorWheel(msg.String())
+	}
+	return nil
+}
+
+// applyColorWheel closes the wheel and sets its color on the targeted
+// lights, as one undo step
+func (m *MainModel) applyColorWheel(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	hue, sat, _ := wheelColor(m.colorWheel.x, m.colorWheel.y)
+	m.colorWheel = nil
+
+	before := m.captureLights()
+	targets := m.targetLights()
+	cmd := m.applyToLights(bridge, targets, func(light *models.Light) lightCalls {
+		return setLightHueSat(light, hue, sat, pending)
+	})
+	m.history.record(before, m.captureLights())
+	m.notice = fmt.Sprintf("Set to %d° at %d%%", hue, sat)
+	m.warnOutsideGamut(targets)
+	return tea.Batch(cmd, m.syncLinks(bridge, pending))
+}
+
+// wheelHueSat returns the hue in degrees and saturation in percent of a
+// color, as the side panel shows them
+func wheelHueSat(c *models.Color) (hue, sat int) {
+	if c.Mode == models.ColorModeHS {
+		return models.HueToDeg(c.Hue), models.LevelToPct(c.Saturation)
+	}
+	hue, sat = rgbToHueSat(getColorPreview(c))
+	return hue % 360, sat
+}
+
+// wheelColor returns the hue in degrees and saturation in percent of a
+// pixel of the wheel: hue around the center, from red on the right,
+// saturation from white in the center. ok is false off the wheel.
+func wheelColor(x, y int) (hue, sat int, ok bool) {
+	r := float64(wheelSize-1) / 2
+	dx, dy := float64(x)-r, r-float64(y)
+	d := math.Hypot(dx, dy)
+	// Half a pixel of slack rounds the edge
+	if x < 0 || y < 0 || x >= wheelSize || y >= wheelSize || d > r+0.5 {
+		return 0, 0, false
+	}
+	hue = (int(math.Round(math.Atan2(dy, dx)*180/math.Pi)) + 360) % 360
+	return hue, min(100, int(math.Round(d/r*100))), true
+}
+
+// wheelPixel returns the pixel of the wheel closest to a hue and saturation
+func wheelPixel(hue, sat int) (x, y int) {
+	r := float64(wheelSize-1) / 2
+	a := float64(hue) * math.Pi / 180
+	d := float64(sat) / 100 * r
+	return int(math.Round(r + d*math.Cos(a))), int(math.Round(r - d*math.Sin(a)))
+}
+
+// wheelRGB returns the color of a hue and saturation at full brightness
+func wheelRGB(hue, sat int) (r, g, b uint8) {
+	fullR, fullG, fullB := hueToRGB(float64(hue))
+	ratio := float64(sat) / 100
+	blend := func(full uint8) uint8 {
+		return uint8(255 - ratio*(255-float64(full)))
+	}
+	return blend(fullR), blend(fullG), blend(fullB)
+}
+
+func wheelHex(hue, sat int) lipgloss.Color {
+	r, g, b := wheelRGB(hue, sat)
+	return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", r, g, b))
+}
+
+// renderColorWheel renders the wheel of a color light with the cursor on
+// its color, or on the previewed one while the cursor is out
+func (m MainModel) renderColorWheel(light *models.Light) string {
+	cx, cy := -1, -1
+	w := m.colorWheel
+	if w != nil && w.lightID == light.ID {
+		cx, cy = w.x, w.y
+	} else if light.Color.Mode != models.ColorModeColorTemp {
+		cx, cy = wheelPixel(wheelHueSat(light.Color))
+	}
+
+	var b strings.Builder
+	for y := 0; y < wheelSize; y += 2 {
+		for x := 0; x < wheelSize; x++ {
+			topHue, topSat, top := wheelColor(x, y)
+			bottomHue, bottomSat, bottom := wheelColor(x, y+1)
+			switch {
+			case x == cx && (y == cy || y+1 == cy):
+				hue, sat, _ := wheelColor(cx, cy)
+				r, g, bl := wheelRGB(hue, sat)
+				fg := lipgloss.Color("#FFFFFF")
+				if 0.299*float64(r)+0.587*float64(g)+0.114*float64(bl) > 140 {
+					fg = lipgloss.Color("#000000")
+				}
+				b.WriteString(lipgloss.NewStyle().Foreground(fg).Background(wheelHex(hue, sat)).Render("●"))
+			case top && bottom:
+				b.WriteString(lipgloss.NewStyle().Foreground(wheelHex(topHue, topSat)).Background(wheelHex(bottomHue, bottomSat)).Render("▀"))
+			case top:
+				b.WriteString(lipgloss.NewStyle().Foreground(wheelHex(topHue, topSat)).Render("▀"))
+			case bottom:
+				b.WriteString(lipgloss.NewStyle().Foreground(wheelHex(bottomHue, bottomSat)).Render("▄"))
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if w != nil && w.lightID == light.ID {
+		hue, sat, _ := wheelColor(w.x, w.y)
+		b.WriteString(styleMuted.Render("Preview: "))
+		b.WriteString(lipgloss.NewStyle().Background(wheelHex(hue, sat)).Render("    "))
+		b.WriteString(fmt.Sprintf(" %d° %d%%\n", hue, sat))
+		b.WriteString(styleMuted.Render("enter set · esc cancel"))
+	} else {
+		b.WriteString(styleMuted.Render("alt+hjkl color wheel"))
+	}
+	return b.String()
+}
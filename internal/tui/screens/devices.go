@@ -0,0 +1,174 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// batteryBarWidth is the number of cells of a battery gauge
+const batteryBarWidth = 10
+
+// deviceIcons mark the kind of each device in the list
+var deviceIcons = map[models.DeviceKind]string{
+	models.DeviceSwitch:       "◫",
+	models.DeviceMotionSensor: "◉",
+	models.DeviceButton:       "●",
+	models.DeviceOther:        "□",
+}
+
+// DevicesModel is the screen listing battery powered devices
+type DevicesModel struct {
+	devices  []*models.Device
+	selected int
+	loading  bool
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewDevicesModel creates a new devices screen model
+func NewDevicesModel() DevicesModel {
+	return DevicesModel{}
+}
+
+// SetSize sets the terminal size
+func (m *DevicesModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetLoading sets the loading state
+func (m *DevicesModel) SetLoading(loading bool) {
+	m.loading = loading
+}
+
+// SetDevices sets the devices, keeping the selection in range
+func (m *DevicesModel) SetDevices(devices []*models.Device) {
+	m.devices = devices
+	m.loading = false
+	m.selected = max(0, min(m.selected, len(devices)-1))
+}
+
+// Update handles messages
+func (m DevicesModel) Update(msg tea.Msg) (DevicesModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "D", "q":
+			return m, func() tea.Msg { return messages.HideDevicesMsg{} }
+
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+
+		case "down", "j":
+			if m.selected < len(m.devices)-1 {
+				m.selected++
+			}
+
+		case "r":
+			m.loading = true
+			return m, func() tea.Msg { return messages.ShowDevicesMsg{} }
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the devices screen
+func (m DevicesModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleModalTitle.Render("Devices"))
+	b.WriteString("\n\n")
+
+	low := 0
+	for _, device := range m.devices {
+		if device.LowBattery() {
+			low++
+		}
+	}
+
+	switch {
+	case m.loading && len(m.devices) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("Loading..."))
+		b.WriteString("\n")
+	case len(m.devices) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("No battery powered devices"))
+		b.WriteString("\n")
+	case low == 1:
+		b.WriteString(styles.StyleError.Render("⚠ 1 device needs new batteries"))
+		b.WriteString("\n\n")
+	case low > 1:
+		b.WriteString(styles.StyleError.Render(fmt.Sprintf("⚠ %d devices need new batteries", low)))
+		b.WriteString("\n\n")
+	}
+
+	nameWidth := 0
+	for _, device := range m.devices {
+		nameWidth = max(nameWidth, lipgloss.Width(device.Name))
+	}
+	for i, device := range m.devices {
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if i == m.selected {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		name := device.Name + strings.Repeat(" ", nameWidth-lipgloss.Width(device.Name))
+		b.WriteString(fmt.Sprintf("%s%s %s  %s\n", cursor, deviceIcons[device.Kind], style.Render(name), renderBattery(device)))
+	}
+
+	if device := m.selectedDevice(); device != nil {
+		b.WriteString("\n")
+		kind := string(device.Kind)
+		if device.Product != "" {
+			kind = device.Product
+		}
+		b.WriteString(styles.StyleTextMuted.Render(kind))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • r refresh • esc close"))
+
+	content := b.String()
+	modalWidth := m.width * 70 / 100
+	if modalWidth < 44 {
+		modalWidth = 44
+	}
+	if modalWidth > 64 {
+		modalWidth = 64
+	}
+	modal := styles.StyleModal.Width(modalWidth).Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+func (m DevicesModel) selectedDevice() *models.Device {
+	if m.selected >= 0 && m.selected < len(m.devices) {
+		return m.devices[m.selected]
+	}
+	return nil
+}
+
+// renderBattery draws the battery gauge and level of a device, in the
+// error color when it runs low
+func renderBattery(device *models.Device) string {
+	level := max(0, min(100, device.BatteryLevel))
+	filled := (level*batteryBarWidth + 50) / 100
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", batteryBarWidth-filled)
+	text := fmt.Sprintf("%s %3d%%", bar, level)
+	if device.LowBattery() {
+		return styles.StyleError.Render(text + " low")
+	}
+	return styles.StyleSuccess.UnsetBold().Render(text)
+}
@@ -0,0 +1,238 @@
+package screens
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Size of the top-down channel layout map
+const (
+	layoutCols = 29
+	layoutRows = 9
+)
+
+// EntertainmentModel is the entertainment areas screen model
+type EntertainmentModel struct {
+	areas      []*models.EntertainmentArea
+	lightNames map[string]string
+	selected   int
+	loading    bool
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewEntertainmentModel creates a new entertainment screen model
+func NewEntertainmentModel() EntertainmentModel {
+	return EntertainmentModel{
+		lightNames: make(map[string]string),
+	}
+}
+
+// SetSize sets the terminal size
+func (m *EntertainmentModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetLoading sets the loading state
+func (m *EntertainmentModel) SetLoading(loading bool) {
+	m.loading = loading
+}
+
+// SetLights records light names used to describe channels
+func (m *EntertainmentModel) SetLights(rooms []*models.Room) {
+	m.lightNames = make(map[string]string)
+	for _, room := range rooms {
+		for _, light := range room.Lights {
+			m.lightNames[light.ID] = light.Name
+		}
+	}
+}
+
+// SetAreas sets the entertainment areas, keeping the selection when possible
+func (m *EntertainmentModel) SetAreas(areas []*models.EntertainmentArea) {
+	var selectedID string
+	if area := m.selectedArea(); area != nil {
+		selectedID = area.ID
+	}
+
+	m.areas = areas
+	m.loading = false
+	m.selected = 0
+	for i, area := range areas {
+		if area.ID == selectedID {
+			m.selected = i
+			break
+		}
+	}
+}
+
+func (m EntertainmentModel) selectedArea() *models.EntertainmentArea {
+	if m.selected >= 0 && m.selected < len(m.areas) {
+		return m.areas[m.selected]
+	}
+	return nil
+}
+
+// Update handles messages
+func (m EntertainmentModel) Update(msg tea.Msg) (EntertainmentModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "e", "q":
+			return m, func() tea.Msg { return messages.HideEntertainmentMsg{} }
+
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+
+		case "down", "j":
+			if m.selected < len(m.areas)-1 {
+				m.selected++
+			}
+
+		case "enter", " ":
+			if area := m.selectedArea(); area != nil {
+				toggle := messages.EntertainmentToggleMsg{AreaID: area.ID, Active: !area.IsActive()}
+				return m, func() tea.Msg { return toggle }
+			}
+
+		case "r":
+			m.loading = true
+			return m, func() tea.Msg { return messages.ShowEntertainmentMsg{} }
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the entertainment areas screen
+func (m EntertainmentModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleModalTitle.Render("Entertainment Areas"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.loading && len(m.areas) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("Loading..."))
+		b.WriteString("\n")
+	case len(m.areas) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("No entertainment areas configured"))
+		b.WriteString("\n")
+	}
+
+	for i, area := range m.areas {
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if i == m.selected {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		status := styles.StyleStatusOff.Render("○ stopped")
+		if area.IsActive() {
+			status = styles.StyleStatusOn.Render("● streaming")
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s  %s\n", cursor, style.Render(area.Name),
+			styles.StyleTextMuted.Render("("+area.Type+")"), status))
+	}
+
+	if area := m.selectedArea(); area != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderLayout(area))
+		b.WriteString("\n")
+		b.WriteString(m.renderChannels(area))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • enter start/stop • r refresh • esc close"))
+
+	content := b.String()
+	modalWidth := m.width * 70 / 100
+	if modalWidth < 44 {
+		modalWidth = 44
+	}
+	if modalWidth > 64 {
+		modalWidth = 64
+	}
+	modal := styles.StyleModal.Width(modalWidth).Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+// renderLayout draws a top-down map of the channel positions.
+// The front of the area (towards the screen) is at the top.
+func (m EntertainmentModel) renderLayout(area *models.EntertainmentArea) string {
+	grid := make([][]rune, layoutRows)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", layoutCols))
+	}
+
+	for _, ch := range area.Channels {
+		col := int(math.Round((clampUnit(ch.X) + 1) / 2 * float64(layoutCols-1)))
+		row := int(math.Round((1 - clampUnit(ch.Y)) / 2 * float64(layoutRows-1)))
+		grid[row][col] = channelRune(ch.ID)
+	}
+
+	var b strings.Builder
+	if area.Type == "screen" || area.Type == "monitor" {
+		label := "▀▀▀ screen ▀▀▀"
+		pad := (layoutCols + 2 - lipgloss.Width(label)) / 2
+		b.WriteString(strings.Repeat(" ", pad) + styles.StyleTextMuted.Render(label) + "\n")
+	}
+	b.WriteString("┌" + strings.Repeat("─", layoutCols) + "┐\n")
+	for _, row := range grid {
+		b.WriteString("│" + styles.StylePrimary.Render(string(row)) + "│\n")
+	}
+	b.WriteString("└" + strings.Repeat("─", layoutCols) + "┘\n")
+	return b.String()
+}
+
+// renderChannels lists channels with their position and lights
+func (m EntertainmentModel) renderChannels(area *models.EntertainmentArea) string {
+	var b strings.Builder
+	for _, ch := range area.Channels {
+		names := make([]string, 0, len(ch.LightIDs))
+		for _, id := range ch.LightIDs {
+			if name, ok := m.lightNames[id]; ok {
+				names = append(names, name)
+			} else {
+				names = append(names, id)
+			}
+		}
+		lights := strings.Join(names, ", ")
+		if lights == "" {
+			lights = "—"
+		}
+		b.WriteString(fmt.Sprintf("%c %s %s\n",
+			channelRune(ch.ID),
+			styles.StyleTextMuted.Render(fmt.Sprintf("(%+.2f, %+.2f, %+.2f)", ch.X, ch.Y, ch.Z)),
+			lights))
+	}
+	return b.String()
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// channelRune labels a channel on the map: 0-9, then A-Z
+func channelRune(id int) rune {
+	switch {
+	case id >= 0 && id < 10:
+		return rune('0' + id)
+	case id >= 10 && id < 36:
+		return rune('A' + id - 10)
+	}
+	return '*'
+}
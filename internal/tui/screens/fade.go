@@ -0,0 +1,141 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultFadeDuration is how long F takes to fade a room
+	DefaultFadeDuration = 30 * time.Second
+	// fadeTickInterval is how often the room header progress is redrawn
+	fadeTickInterval = time.Second
+	// fadeBarWidth is the number of cells of the room header progress
+	fadeBarWidth = 8
+)
+
+// roomFade is a group transition in flight
+type roomFade struct {
+	start    time.Time
+	duration time.Duration
+	on       bool
+}
+
+// progress returns how far the fade is, from 0 to 1
+func (f roomFade) progress(now time.Time) float64 {
+	if f.duration <= 0 {
+		return 1
+	}
+	return math.Min(1, float64(now.Sub(f.start))/float64(f.duration))
+}
+
+// fadeTickMsg redraws the progress of the fades in flight
+type fadeTickMsg struct{}
+
+// fadeFailedMsg drops the fade of a room whose command failed
+type fadeFailedMsg struct {
+	roomID string
+	err    error
+}
+
+func fadeTick() tea.Cmd {
+	return tea.Tick(fadeTickInterval, func(time.Time) tea.Msg { return fadeTickMsg{} })
+}
+
+// SetFadeDuration sets how long F takes to fade a room
+func (m *MainModel) SetFadeDuration(d time.Duration) {
+	m.fadeDuration = d
+}
+
+// SetClock sets the clock the fades and key repeats are timed with,
+// time.Now by default
+func (m *MainModel) SetClock(now func() time.Time) {
+	m.now = now
+}
+
+// fadeRoom turns the selected room on or off over the fade duration, and
+// shows the progress in its header
+func (m *MainModel) fadeRoom(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	room := m.SelectedRoom()
+	if room == nil || room.GroupedLightID == "" {
+		return nil
+	}
+	duration := m.fadeDuration
+	if duration <= 0 {
+		duration = DefaultFadeDuration
+	}
+
+	on := !room.AnyOn
+	for _, light := range room.Lights {
+		light.On = on
+		pending.addOp(light.ID, "on", on, DirExact)
+	}
+	m.updateRoomState(room)
+	m.fades[room.ID] = roomFade{start: m.now(), duration: duration, on: on}
+
+	roomID, groupID := room.ID, room.GroupedLightID
+	cmds := []tea.Cmd{func() tea.Msg {
+		if bridge == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := bridge.FadeGroupedLightOn(ctx, groupID, on, duration); err != nil {
+			return fadeFailedMsg{roomID: roomID, err: err}
+		}
+		return nil
+	}}
+	if !m.fadeTicking {
+		m.fadeTicking = true
+		cmds = append(cmds, fadeTick())
+	}
+	return tea.Batch(cmds...)
+}
+
+// advanceFades drops the finished fades, and keeps ticking while some are
+// in flight and the app isn't idle
+func (m *MainModel) advanceFades() tea.Cmd {
+	if m.idle {
+		m.fadeTicking = false
+		return nil
+	}
+	now := m.now()
+	for id, fade := range m.fades {
+		if fade.progress(now) >= 1 {
+			delete(m.fades, id)
+		}
+	}
+	if len(m.fades) == 0 {
+		m.fadeTicking = false
+		return nil
+	}
+	return fadeTick()
+}
+
+// renderFadeProgress renders the progress of a room's fade for its header
+func renderFadeProgress(fade roomFade, now time.Time) string {
+	p := fade.progress(now)
+	filled := int(p * fadeBarWidth)
+	state := "off"
+	if fade.on {
+		state = "on"
+	}
+	left := (fade.duration - now.Sub(fade.start)).Round(time.Second)
+	if left < 0 {
+		left = 0
+	}
+	return fmt.Sprintf(" fading %s %s%s %s", state, strings.Repeat("▰", filled), strings.Repeat("▱", fadeBarWidth-filled), left)
+}
+
+// fadeFailed drops the fade of a room and reports the error
+func (m *MainModel) fadeFailed(msg fadeFailedMsg) tea.Cmd {
+	delete(m.fades, msg.roomID)
+	return func() tea.Msg { return messages.ErrorMsg{Err: msg.err} }
+}
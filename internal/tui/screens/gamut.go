@@ -0,0 +1,49 @@
+package screens
+
+import (
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// requestedXY returns the xy color a light was asked for, false in
+// temperature mode
+func requestedXY(light *models.Light) (x, y float64, ok bool) {
+	switch c := light.Color; {
+	case c == nil:
+		return 0, 0, false
+	case c.Mode == models.ColorModeHS:
+		x, y = api.HSToXY(c.Hue, c.Saturation)
+		return x, y, true
+	case c.Mode == models.ColorModeXY:
+		return c.X, c.Y, true
+	}
+	return 0, 0, false
+}
+
+// outsideGamut reports whether a light was asked for a color noticeably
+// beyond its gamut, which the bulb approximates
+func outsideGamut(light *models.Light) bool {
+	if light.Gamut == nil {
+		return false
+	}
+	x, y, ok := requestedXY(light)
+	return ok && light.Gamut.Distance(x, y) > models.GamutOutsideThreshold
+}
+
+// warnOutsideGamut tells in the status bar when a color just set is beyond
+// the gamut of some of the lights
+func (m *MainModel) warnOutsideGamut(lights []*models.Light) {
+	var outside []*models.Light
+	for _, light := range lights {
+		if outsideGamut(light) {
+			outside = append(outside, light)
+		}
+	}
+	switch len(outside) {
+	case 0:
+	case 1:
+		m.notice = m.displayName(outside[0]) + " can't show this color (gamut " + outside[0].Gamut.Label() + "), it shows the closest one"
+	default:
+		m.notice = "Some lights can't show this color, they show the closest one"
+	}
+}
@@ -0,0 +1,66 @@
+package screens
+
+import (
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// homeRoomName is the name of the entry holding every light
+const homeRoomName = "All lights"
+
+// SetHomeGroup shows the "All lights" entry above the rooms, controlling
+// every light through the given grouped light. Empty hides it.
+func (m *MainModel) SetHomeGroup(groupedLightID string) {
+	var selected *listItem
+	if item := m.SelectedItem(); item != nil {
+		copied := *item
+		selected = &copied
+	}
+	m.homeGroupID = groupedLightID
+	m.rebuildLightList()
+	if selected != nil {
+		m.reselect(*selected)
+	}
+}
+
+// homeRoom builds the "All lights" entry from the listed lights
+func (m *MainModel) homeRoom(lights []*models.Light) *models.Room {
+	home := &models.Room{
+		ID:             api.HomeRoomID,
+		Name:           homeRoomName,
+		GroupedLightID: m.homeGroupID,
+		Lights:         lights,
+	}
+	home.UpdateState()
+	return home
+}
+
+// isHome reports whether room is the "All lights" entry
+func isHome(room *models.Room) bool {
+	return room != nil && room.ID == api.HomeRoomID
+}
+
+// updateRoomState refreshes the on state of room after its lights changed.
+// Changing every light changes every room.
+func (m *MainModel) updateRoomState(room *models.Room) {
+	room.UpdateState()
+	if isHome(room) {
+		for _, r := range m.rooms {
+			r.UpdateState()
+		}
+	}
+}
+
+// reselect selects the light or room of item again after the list changed
+func (m *MainModel) reselect(item listItem) {
+	for i, it := range m.items {
+		if it.isRoom != item.isRoom {
+			continue
+		}
+		if (it.isRoom && it.room.ID == item.room.ID) || (!it.isRoom && it.light.ID == item.light.ID) {
+			m.selectedIndex = i
+			m.ensureVisible()
+			return
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package screens
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Help line tiers: the bottom line shows the bindings up to the tier its
+// width allows, the help overlay shows them all
+const (
+	tierNarrow  = iota // always shown
+	tierMedium         // from 60 columns
+	tierWide           // from 90 columns
+	tierOverlay        // only in the help overlay
+)
+
+// keyBinding is a key of the main screen as shown in the help
+type keyBinding struct {
+	key      string
+	help     string
+	category string
+	tier     int
+}
+
+// Key binding categories, in the order of the help overlay
+const (
+	categoryNavigation = "Navigation"
+	categoryLight      = "Light control"
+	categoryColor      = "Color"
+	categoryRooms      = "Rooms"
+	categoryScenes     = "Scenes"
+	categoryApp        = "App"
+)
+
+var keyCategories = []string{categoryNavigation, categoryLight, categoryColor, categoryRooms, categoryScenes, categoryApp}
+
+// keymap lists the bindings of the main screen, in the order of the help
+// line. Both the help line and the help overlay are rendered from it.
+var keymap = []keyBinding{
+	{"↑↓", "nav", categoryNavigation, tierNarrow},
+	{"pgup/dn", "scroll", categoryNavigation, tierWide},
+	{"home/end", "first/last", categoryNavigation, tierOverlay},
+	{"/", "search", categoryNavigation, tierOverlay},
+	{"n", "next light on device", categoryNavigation, tierOverlay},
+	{"tab", "side panel", categoryNavigation, tierOverlay},
+	{"S-tab", "browse room", categoryNavigation, tierWide},
+	{"g…", "go to", categoryNavigation, tierWide},
+	{"←→", "dim", categoryLight, tierMedium},
+	{"S-←→", "fine", categoryLight, tierWide},
+	{"%", "set %", categoryLight, tierWide},
+	{"1-9/0", "10-90%/100%", categoryLight, tierOverlay},
+	{"space", "toggle", categoryLight, tierNarrow},
+	{"#", "%/0-254", categoryLight, tierWide},
+	{"v", "select", categoryLight, tierWide},
+	{"esc", "clear selection", categoryLight, tierOverlay},
+	{"enter", "actions", categoryLight, tierWide},
+	{"i", "identify", categoryLight, tierWide},
+	{"A", "light type", categoryLight, tierWide},
+	{"L", "link", categoryLight, tierWide},
+	{"K", "calibrate", categoryLight, tierWide},
+	{"Z", "zones", categoryLight, tierWide},
+	{"w/c", "temp", categoryColor, tierWide},
+	{"T", "temp presets", categoryColor, tierWide},
+	{"C", "exact color", categoryColor, tierWide},
+	{"[]", "hue", categoryColor, tierWide},
+	{"-/=", "sat", categoryColor, tierWide},
+	{"alt+hjkl", "color wheel", categoryColor, tierOverlay},
+	{"a/x", "room", categoryRooms, tierWide},
+	{"F", "fade room", categoryRooms, tierWide},
+	{"b/m/t", "roles", categoryRooms, tierWide},
+	{"M", "mute updates", categoryRooms, tierWide},
+	{"R", "rooms", categoryRooms, tierWide},
+	{"o", "lit rooms only", categoryRooms, tierWide},
+	{"s", "scenes", categoryScenes, tierMedium},
+	{"alt+1-9", "room scene", categoryScenes, tierWide},
+	{"^z", "revert scene", categoryScenes, tierWide},
+	{"e", "entertainment", categoryScenes, tierWide},
+	{"S", "schedules", categoryScenes, tierWide},
+	{"u/^r", "undo/redo", categoryApp, tierWide},
+	{"D", "devices", categoryApp, tierWide},
+	{"B", "bridges & info", categoryApp, tierWide},
+	{"E", "notifications", categoryApp, tierOverlay},
+	{"P", "snapshot", categoryApp, tierWide},
+	{"r", "refresh", categoryApp, tierOverlay},
+	{"^p", "command palette", categoryApp, tierOverlay},
+	{"?", "help", categoryApp, tierNarrow},
+	{"q", "quit", categoryApp, tierNarrow},
+}
+
+// bindingHelp returns the help of a binding, which can depend on the state
+func (m MainModel) bindingHelp(kb keyBinding) string {
+	if kb.key == "s" && m.scenesUnavailable {
+		return "scenes unavailable — retry"
+	}
+	return kb.help
+}
+
+// helpOverlay is the full-screen list of the key bindings opened with ?
+type helpOverlay struct {
+	scroll int
+}
+
+// updateHelpOverlay scrolls the help overlay, and closes it on esc, ? or q
+func (m *MainModel) updateHelpOverlay(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "?", "q":
+		m.help = nil
+	case "up", "k":
+		m.help.scroll = max(0, m.help.scroll-1)
+	case "down", "j":
+		m.help.scroll = min(m.help.scroll+1, max(0, len(m.helpOverlayBody())-m.helpOverlayHeight()))
+	case "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// helpOverlayHeight is the number of binding lines shown at once
+func (m MainModel) helpOverlayHeight() int {
+	return max(1, m.height-4)
+}
+
+// helpOverlayBody lays the categories out in as many columns as fit
+func (m MainModel) helpOverlayBody() []string {
+	keyWidth := 0
+	for _, kb := range keymap {
+		keyWidth = max(keyWidth, lipgloss.Width(kb.key))
+	}
+	for _, c := range chords {
+		keyWidth = max(keyWidth, lipgloss.Width(leaderKey+" "+c.key))
+	}
+
+	var sections []string
+	for _, category := range keyCategories {
+		lines := []string{styleRoomName.Render(category)}
+		for _, kb := range keymap {
+			if kb.category == category {
+				lines = append(lines, styleHelpKey.Render(padRight(kb.key, keyWidth))+"  "+m.bindingHelp(kb))
+			}
+		}
+		if category == categoryNavigation {
+			for _, c := range chords {
+				lines = append(lines, styleHelpKey.Render(padRight(leaderKey+" "+c.key, keyWidth))+"  go to "+c.desc)
+			}
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	// Fill the columns in order, each about as tall as the others
+	columnWidth := 0
+	for _, s := range sections {
+		columnWidth = max(columnWidth, lipgloss.Width(s))
+	}
+	columnCount := max(1, min(len(sections), (m.width+4)/(columnWidth+4)))
+	total := 0
+	for _, s := range sections {
+		total += lipgloss.Height(s) + 1
+	}
+	columns := make([][]string, columnCount)
+	target := (total + columnCount - 1) / columnCount
+	col, height := 0, 0
+	for _, s := range sections {
+		if height > 0 && height+lipgloss.Height(s) > target && col < columnCount-1 {
+			col, height = col+1, 0
+		}
+		columns[col] = append(columns[col], s)
+		height += lipgloss.Height(s) + 1
+	}
+	rendered := make([]string, 0, columnCount)
+	for i, c := range columns {
+		style := lipgloss.NewStyle().Width(columnWidth)
+		if i < columnCount-1 {
+			style = style.MarginRight(4)
+		}
+		rendered = append(rendered, style.Render(strings.Join(c, "\n\n")))
+	}
+	return strings.Split(lipgloss.JoinHorizontal(lipgloss.Top, rendered...), "\n")
+}
+
+// renderHelpOverlay renders the bindings, scrolling what doesn't fit
+// between the title and the footer
+func (m MainModel) renderHelpOverlay() string {
+	body := m.helpOverlayBody()
+	visible := m.helpOverlayHeight()
+	scroll := 0
+	if m.help != nil {
+		scroll = min(m.help.scroll, max(0, len(body)-visible))
+	}
+	end := min(len(body), scroll+visible)
+
+	var b strings.Builder
+	b.WriteString(styleHeader.Render(" Keyboard shortcuts "))
+	b.WriteString("\n\n")
+	b.WriteString(strings.Join(body[scroll:end], "\n"))
+	b.WriteString("\n\n")
+	footer := "esc close"
+	if len(body) > visible {
+		footer = "↑↓ scroll · " + footer
+	}
+	b.WriteString(styleHelp.Render(footer))
+	return b.String()
+}
+
+// padRight pads s with spaces to width cells
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-lipgloss.Width(s)))
+}
@@ -0,0 +1,413 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+)
+
+// lightCall is a single bridge request for one light
+type lightCall struct {
+	// What the request does, with %s for the light's name, like
+	// "set the brightness of %s"
+	op   string
+	send func(ctx context.Context, bridge api.BridgeClient) error
+}
+
+// lightCalls are the requests needed to apply a change to one light, in order
+type lightCalls []lightCall
+
+// lightBatch is the requests for one light
+type lightBatch struct {
+	lightID string
+	calls   lightCalls
+	// Light before and after the change was applied locally, to roll it
+	// back if a request fails (nil when the change can't be undone)
+	before, after *models.Light
+}
+
+// runLightCalls sends the requests for several lights as one batched command.
+// Requests for the same light run in order, different lights run concurrently.
+// The result reports which lights failed, so repeatedly failing lights can be
+// flagged and their optimistic state rolled back.
+func runLightCalls(bridge api.BridgeClient, batches []lightBatch) tea.Cmd {
+	if len(batches) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		if bridge == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(batches))
+		for i, batch := range batches {
+			wg.Add(1)
+			go func(i int, batch lightBatch) {
+				defer wg.Done()
+				for _, call := range batch.calls {
+					if err := call.send(ctx, bridge); err != nil {
+						errs[i] = fmt.Errorf("failed to %s: %w", fmt.Sprintf(call.op, batch.name()), err)
+						return
+					}
+				}
+			}(i, batch)
+		}
+		wg.Wait()
+
+		var result messages.LightCommandsResultMsg
+		for i, batch := range batches {
+			if errs[i] != nil {
+				result.Failed = append(result.Failed, batch.lightID)
+				if batch.before != nil && batch.after != nil {
+					result.Rollbacks = append(result.Rollbacks, messages.LightRollback{Before: batch.before, After: batch.after})
+				}
+			} else {
+				result.Succeeded = append(result.Succeeded, batch.lightID)
+			}
+		}
+		result.Err = errors.Join(errs...)
+		return result
+	}
+}
+
+// runGroupCall sends one grouped light request for the lights of batches,
+// which carry no requests of their own. call's op names the room. The
+// lights are rolled back together if the request fails.
+func runGroupCall(bridge api.BridgeClient, room string, call lightCall, batches []lightBatch) tea.Cmd {
+	return func() tea.Msg {
+		if bridge == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var result messages.LightCommandsResultMsg
+		if err := call.send(ctx, bridge); err != nil {
+			result.Err = fmt.Errorf("failed to %s: %w", fmt.Sprintf(call.op, room), err)
+			for _, batch := range batches {
+				result.Failed = append(result.Failed, batch.lightID)
+				result.Rollbacks = append(result.Rollbacks, messages.LightRollback{Before: batch.before, After: batch.after})
+			}
+			return result
+		}
+		for _, batch := range batches {
+			result.Succeeded = append(result.Succeeded, batch.lightID)
+		}
+		return result
+	}
+}
+
+// name is how the batch's light is called in errors
+func (b lightBatch) name() string {
+	if b.before != nil && b.before.Name != "" {
+		return b.before.Name
+	}
+	return b.lightID
+}
+
+func callSetOn(lightID string, on bool) lightCall {
+	op := "turn off %s"
+	if on {
+		op = "turn on %s"
+	}
+	return lightCall{op, func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetLightOn(ctx, lightID, on)
+	}}
+}
+
+func callSetGroupOn(groupID string, on bool) lightCall {
+	op := "turn off %s"
+	if on {
+		op = "turn on %s"
+	}
+	return lightCall{op, func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetGroupedLightOn(ctx, groupID, on)
+	}}
+}
+
+func callSetGroupBrightness(groupID string, brightness int) lightCall {
+	return lightCall{"set the brightness of %s", func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetGroupedLightBrightness(ctx, groupID, brightness)
+	}}
+}
+
+func callSetBrightness(lightID string, brightness int) lightCall {
+	return lightCall{"set the brightness of %s", func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetLightBrightness(ctx, lightID, brightness)
+	}}
+}
+
+func callSetColorTemp(lightID string, mirek int) lightCall {
+	return lightCall{"set the color temperature of %s", func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetLightColorTemp(ctx, lightID, mirek)
+	}}
+}
+
+func callSetColorXY(lightID string, x, y float64) lightCall {
+	return lightCall{"set the color of %s", func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetLightColorXY(ctx, lightID, x, y)
+	}}
+}
+
+func callSetColorHS(lightID string, hue uint16, sat uint8) lightCall {
+	return lightCall{"set the color of %s", func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.SetLightColorHS(ctx, lightID, hue, sat)
+	}}
+}
+
+func callIdentify(lightID string) lightCall {
+	return lightCall{"identify %s", func(ctx context.Context, bridge api.BridgeClient) error {
+		return bridge.IdentifyLight(ctx, lightID)
+	}}
+}
+
+// pendingFuncs bundles the pending trackers passed down from the app
+type pendingFuncs struct {
+	add      PendingAdder
+	compound CompoundPendingAdder
+}
+
+func (p pendingFuncs) addOp(lightID, field string, value interface{}, dir Direction) {
+	if p.add != nil {
+		p.add(lightID, field, value, dir)
+	}
+}
+
+func (p pendingFuncs) addCompound(lightID string, targets map[string]interface{}) {
+	if p.compound != nil {
+		p.compound(lightID, targets)
+	}
+}
+
+// setLightOn switches a light on or off
+func setLightOn(light *models.Light, on bool, pending pendingFuncs) lightCalls {
+	light.On = on
+	pending.addOp(light.ID, "on", on, DirExact)
+	return lightCalls{callSetOn(light.ID, on)}
+}
+
+// turnOnWith switches an off light on along with the changes of set, like
+// a brightness or color. They are pending as one compound op, so the echo
+// of the light coming on doesn't bring back its old brightness or color.
+func turnOnWith(light *models.Light, pending pendingFuncs, set func(pendingFuncs) lightCalls) lightCalls {
+	targets := make(map[string]interface{})
+	collect := pendingFuncs{add: func(_, field string, value interface{}, _ Direction) {
+		targets[field] = value
+	}}
+	calls := setLightOn(light, true, collect)
+	calls = append(calls, set(collect)...)
+	if len(targets) == 1 {
+		pending.addOp(light.ID, "on", true, DirExact)
+	} else {
+		pending.addCompound(light.ID, targets)
+	}
+	return calls
+}
+
+// roleStep is the action applied to the lights of a role
+type roleStep int
+
+const (
+	roleStepDim roleStep = iota
+	roleStepOff
+	roleStepOn
+)
+
+// roleDimPct is the brightness role lights are dimmed to
+const roleDimPct = 20
+
+// nextRoleStep picks the action for a role's lights: dim them if any is
+// brighter than roleDimPct, turn them off if any is still on, else turn them on
+func nextRoleStep(lights []*models.Light) roleStep {
+	step := roleStepOn
+	for _, light := range lights {
+		if !light.On {
+			continue
+		}
+		if light.BrightnessPct() > roleDimPct {
+			return roleStepDim
+		}
+		step = roleStepOff
+	}
+	return step
+}
+
+// applyRoleStep applies a role action to one light
+func applyRoleStep(light *models.Light, step roleStep, pending pendingFuncs) lightCalls {
+	switch step {
+	case roleStepDim:
+		if !light.On || light.BrightnessPct() <= roleDimPct {
+			return nil
+		}
+		return setLightBrightness(light, roleDimPct, pending)
+	case roleStepOff:
+		if !light.On {
+			return nil
+		}
+		return setLightOn(light, false, pending)
+	default:
+		return setLightOn(light, true, pending)
+	}
+}
+
+// stepLightBrightness dims or brightens a light by step percent.
+// Dimming to zero turns the light off, brightening an off light turns it on at 10%.
+func stepLightBrightness(light *models.Light, step int, pending pendingFuncs) lightCalls {
+	if light.OnOffOnly {
+		return nil
+	}
+	if step < 0 {
+		if !light.On {
+			return nil
+		}
+		newBrightness := max(0, light.BrightnessPct()+step)
+		if newBrightness == 0 {
+			return setLightOn(light, false, pending)
+		}
+		light.SetBrightnessPct(newBrightness)
+		pending.addOp(light.ID, "brightness", newBrightness, DirDown)
+		return lightCalls{callSetBrightness(light.ID, newBrightness)}
+	}
+
+	if !light.On {
+		// Off lights come on at the step size, so fine steps start at 1%
+		brightness := min(brightnessStep, step)
+		light.On = true
+		light.SetBrightnessPct(brightness)
+		pending.addCompound(light.ID, map[string]interface{}{"on": true, "brightness": brightness})
+		return lightCalls{callSetOn(light.ID, true), callSetBrightness(light.ID, brightness)}
+	}
+	newBrightness := min(100, light.BrightnessPct()+step)
+	light.SetBrightnessPct(newBrightness)
+	pending.addOp(light.ID, "brightness", newBrightness, DirUp)
+	return lightCalls{callSetBrightness(light.ID, newBrightness)}
+}
+
+// setLightBrightness sets an absolute brightness, turning the light on if needed
+func setLightBrightness(light *models.Light, brightness int, pending pendingFuncs) lightCalls {
+	if light.OnOffOnly {
+		return nil
+	}
+	oldBrightness := light.BrightnessPct()
+	light.SetBrightnessPct(brightness)
+
+	var calls lightCalls
+	if !light.On {
+		light.On = true
+		pending.addCompound(light.ID, map[string]interface{}{"on": true, "brightness": brightness})
+		calls = append(calls, callSetOn(light.ID, true))
+	} else {
+		dir := DirExact
+		if brightness > oldBrightness {
+			dir = DirUp
+		} else if brightness < oldBrightness {
+			dir = DirDown
+		}
+		pending.addOp(light.ID, "brightness", brightness, dir)
+	}
+	return append(calls, callSetBrightness(light.ID, brightness))
+}
+
+// stepLightColorTemp shifts color temperature by delta mirek (positive = warmer)
+func stepLightColorTemp(light *models.Light, delta int, pending pendingFuncs) lightCalls {
+	if !light.SupportsColorTemp || light.Color == nil {
+		return nil
+	}
+	if light.Color.Mirek == 0 {
+		light.Color.Mirek = 326 // Default to middle (3000K)
+	}
+	newMirek := light.ClampMirek(int(light.Color.Mirek) + delta)
+	light.Color.Mirek = uint16(newMirek)
+	light.Color.Mode = models.ColorModeColorTemp
+	light.Color.InvalidateCache()
+
+	dir := DirUp
+	if delta < 0 {
+		dir = DirDown
+	}
+	pending.addOp(light.ID, "color_temp", newMirek, dir)
+	return lightCalls{callSetColorTemp(light.ID, newMirek)}
+}
+
+// setLightColorTemp sets an exact color temperature in mirek
+func setLightColorTemp(light *models.Light, mirek int, pending pendingFuncs) lightCalls {
+	if !light.SupportsColorTemp || light.Color == nil {
+		return nil
+	}
+	light.Color.Mirek = uint16(mirek)
+	light.Color.Mode = models.ColorModeColorTemp
+	light.Color.InvalidateCache()
+	pending.addOp(light.ID, "color_temp", mirek, DirExact)
+	return lightCalls{callSetColorTemp(light.ID, mirek)}
+}
+
+// stepLightHue rotates the hue by delta (in 0-65535 hue units)
+func stepLightHue(light *models.Light, delta int, pending pendingFuncs) lightCalls {
+	if !light.SupportsColor || light.Color == nil {
+		return nil
+	}
+	ensureHSMode(light)
+	newHue := ((int(light.Color.Hue)+delta)%65536 + 65536) % 65536
+	light.Color.Hue = uint16(newHue)
+	return applyHS(light, pending)
+}
+
+// stepLightSaturation changes saturation by delta (in 0-254 units)
+func stepLightSaturation(light *models.Light, delta int, pending pendingFuncs) lightCalls {
+	if !light.SupportsColor || light.Color == nil {
+		return nil
+	}
+	ensureHSMode(light)
+	newSat := min(254, max(0, int(light.Color.Saturation)+delta))
+	light.Color.Saturation = uint8(newSat)
+	return applyHS(light, pending)
+}
+
+// setLightHueSat sets an exact hue (degrees) and saturation (percent).
+// A negative value keeps the current one.
+func setLightHueSat(light *models.Light, hueDeg, satPct int, pending pendingFuncs) lightCalls {
+	if !light.SupportsColor || light.Color == nil {
+		return nil
+	}
+	ensureHSMode(light)
+	if hueDeg >= 0 {
+		light.Color.Hue = models.DegToHue(hueDeg)
+	}
+	if satPct >= 0 {
+		light.Color.Saturation = models.PctToLevel(float64(satPct))
+	}
+	return applyHS(light, pending)
+}
+
+// ensureHSMode initializes hue/saturation from the current color when
+// switching from another color mode
+func ensureHSMode(light *models.Light) {
+	if light.Color.Mode == models.ColorModeHS {
+		return
+	}
+	r, g, b := light.Color.RGB()
+	h, s := rgbToHueSat(r, g, b)
+	light.Color.Hue = models.DegToHue(h)
+	light.Color.Saturation = models.PctToLevel(float64(s))
+	light.Color.Brightness = light.Brightness // Preserve brightness
+}
+
+// applyHS commits the light's hue/saturation locally and returns the request
+func applyHS(light *models.Light, pending pendingFuncs) lightCalls {
+	light.Color.Mode = models.ColorModeHS
+	light.Color.InvalidateCache()
+	x, y := api.HSToXY(light.Color.Hue, light.Color.Saturation)
+	pending.addOp(light.ID, "color_xy", struct{ X, Y float64 }{x, y}, DirExact)
+	return lightCalls{callSetColorHS(light.ID, light.Color.Hue, light.Color.Saturation)}
+}
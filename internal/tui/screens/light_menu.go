@@ -0,0 +1,357 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxLightName is the longest light name the bridge accepts
+const maxLightName = 32
+
+// lightAction is an entry of the light menu
+type lightAction int
+
+const (
+	actionRename lightAction = iota
+	actionIdentify
+	actionColor
+	actionMove
+	actionInfo
+)
+
+var lightActionLabels = map[lightAction]string{
+	actionRename:   "Rename",
+	actionIdentify: "Identify",
+	actionColor:    "Set exact color",
+	actionMove:     "Move to room",
+	actionInfo:     "Device info",
+}
+
+// lightMenuView is what the light menu shows: the actions, or the one
+// being carried out
+type lightMenuView int
+
+const (
+	menuActions lightMenuView = iota
+	menuRename
+	menuRooms
+	menuInfo
+)
+
+// lightMenu lists the actions on the selected light in the side panel
+type lightMenu struct {
+	lightID  string
+	actions  []lightAction
+	view     lightMenuView
+	selected int
+	// New name being typed
+	name string
+	// Rooms the light can move to, and the selected one
+	rooms []*models.Room
+	room  int
+	err   string
+}
+
+// startLightMenu opens the actions menu of the selected light
+func (m *MainModel) startLightMenu() {
+	light := m.SelectedLight()
+	if light == nil || m.IsRoomSelected() {
+		return
+	}
+	menu := &lightMenu{lightID: light.ID}
+	for _, action := range []lightAction{actionRename, actionIdentify, actionColor, actionMove, actionInfo} {
+		if action == actionColor && !light.IsColorLight() {
+			continue
+		}
+		menu.actions = append(menu.actions, action)
+	}
+	m.lightMenu = menu
+	m.showPanel = true
+}
+
+// updateLightMenu handles keys while the light menu is open. enter runs
+// the selected action, esc goes back to the actions, then closes.
+func (m *MainModel) updateLightMenu(msg tea.KeyMsg, bridge api.BridgeClient) tea.Cmd {
+	menu := m.lightMenu
+	menu.err = ""
+	light := m.findLight(menu.lightID)
+	if light == nil {
+		m.lightMenu = nil
+		return nil
+	}
+
+	switch menu.view {
+	case menuRename:
+		return m.updateLightName(msg, light, bridge)
+	case menuRooms:
+		return m.updateRoomChoice(msg, light)
+	case menuInfo:
+		if msg.String() == "esc" || msg.String() == "enter" {
+			menu.view = menuActions
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.lightMenu = nil
+
+	case "up", "k":
+		if menu.selected > 0 {
+			menu.selected--
+		}
+
+	case "down", "j":
+		if menu.selected < len(menu.actions)-1 {
+			menu.selected++
+		}
+
+	case "enter":
+		switch menu.actions[menu.selected] {
+		case actionRename:
+			menu.view = menuRename
+			menu.name = light.Name
+
+		case actionIdentify:
+			m.notice = "Identifying " + light.Name
+			return runLightCalls(bridge, []lightBatch{{lightID: light.ID, calls: lightCalls{callIdentify(light.ID)}}})
+
+		case actionColor:
+			m.lightMenu = nil
+			return m.startColorInput()
+
+		case actionMove:
+			if light.DeviceID == "" {
+				menu.err = "the bridge does not tell which device this light is on"
+				return nil
+			}
+			menu.rooms = nil
+			for _, room := range m.rooms {
+				if room.ID != api.OtherRoomID && room.ID != light.RoomID && !slices.Contains(room.DeviceIDs, light.DeviceID) {
+					menu.rooms = append(menu.rooms, room)
+				}
+			}
+			if len(menu.rooms) == 0 {
+				menu.err = "no other room"
+				return nil
+			}
+			menu.room = 0
+			menu.view = menuRooms
+
+		case actionInfo:
+			menu.view = menuInfo
+		}
+	}
+	return nil
+}
+
+// updateLightName handles keys while the new name of the light is typed
+func (m *MainModel) updateLightName(msg tea.KeyMsg, light *models.Light, bridge api.BridgeClient) tea.Cmd {
+	menu := m.lightMenu
+	switch msg.Type {
+	case tea.KeyEsc:
+		menu.view = menuActions
+
+	case tea.KeyBackspace:
+		if menu.name != "" {
+			runes := []rune(menu.name)
+			menu.name = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeyEnter:
+		name := strings.TrimSpace(menu.name)
+		if name == "" {
+			menu.err = "enter a name"
+			return nil
+		}
+		m.lightMenu = nil
+		if name == light.Name {
+			return nil
+		}
+		m.notice = light.Name + " renamed to " + name
+		before := light.Clone()
+		light.Name = name
+		m.rebuildLightList()
+		m.selectLight(light.ID)
+		return runLightCalls(bridge, []lightBatch{{
+			lightID: light.ID,
+			before:  before,
+			after:   light.Clone(),
+			calls: lightCalls{{"rename %s", func(ctx context.Context, bridge api.BridgeClient) error {
+				return bridge.RenameLight(ctx, light.ID, name)
+			}}},
+		}})
+
+	case tea.KeyRunes, tea.KeySpace:
+		if len([]rune(menu.name))+len(msg.Runes) <= maxLightName {
+			menu.name += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// updateRoomChoice handles keys while the room to move the light to is
+// picked. Rooms hold devices, so the whole device of the light moves.
+func (m *MainModel) updateRoomChoice(msg tea.KeyMsg, light *models.Light) tea.Cmd {
+	menu := m.lightMenu
+	switch msg.String() {
+	case "esc":
+		menu.view = menuActions
+
+	case "up", "k":
+		if menu.room > 0 {
+			menu.room--
+		}
+
+	case "down", "j":
+		if menu.room < len(menu.rooms)-1 {
+			menu.room++
+		}
+
+	case "enter":
+		room := menu.rooms[menu.room]
+		m.lightMenu = nil
+		m.moveDevice(light.DeviceID, room)
+		m.selectLight(light.ID)
+		m.notice = light.Name + " moved to " + room.Name
+		deviceIDs := slices.Clone(room.DeviceIDs)
+		return func() tea.Msg {
+			return messages.RoomSaveMsg{ID: room.ID, ChildIDs: deviceIDs}
+		}
+	}
+	return nil
+}
+
+// moveDevice moves the lights of a device to room in the list, ahead of
+// the bridge
+func (m *MainModel) moveDevice(deviceID string, room *models.Room) {
+	for _, r := range m.rooms {
+		if r == room {
+			continue
+		}
+		r.DeviceIDs = slices.DeleteFunc(r.DeviceIDs, func(id string) bool { return id == deviceID })
+		r.Lights = slices.DeleteFunc(r.Lights, func(light *models.Light) bool {
+			if light.DeviceID != deviceID {
+				return false
+			}
+			light.RoomID = room.ID
+			room.Lights = append(room.Lights, light)
+			return true
+		})
+		r.UpdateState()
+	}
+	room.DeviceIDs = append(room.DeviceIDs, deviceID)
+	room.UpdateState()
+	m.rebuildLightList()
+}
+
+// renderLightMenu renders the open light menu for the side panel
+func (m MainModel) renderLightMenu() string {
+	menu := m.lightMenu
+	light := m.findLight(menu.lightID)
+	if light == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(styleSelected.Render(light.Name))
+	b.WriteString("\n\n")
+
+	hint := "enter select · esc close"
+	switch menu.view {
+	case menuActions:
+		for i, action := range menu.actions {
+			label := lightActionLabels[action]
+			if i == menu.selected {
+				b.WriteString(styleSelected.Render("> " + label))
+			} else {
+				b.WriteString("  " + label)
+			}
+			b.WriteString("\n")
+		}
+
+	case menuRename:
+		b.WriteString(styleMuted.Render("Name: "))
+		b.WriteString(menu.name + "█\n")
+		hint = "enter rename · esc back"
+
+	case menuRooms:
+		b.WriteString(styleMuted.Render("Move to:\n"))
+		for i, room := range menu.rooms {
+			if i == menu.room {
+				b.WriteString(styleSelected.Render("> " + room.Name))
+			} else {
+				b.WriteString("  " + room.Name)
+			}
+			b.WriteString("\n")
+		}
+		hint = "enter move · esc back"
+
+	case menuInfo:
+		b.WriteString(m.renderDeviceInfo(light))
+		hint = "esc back"
+	}
+
+	b.WriteString("\n")
+	if menu.err != "" {
+		b.WriteString(styleLightFaulty.Render(menu.err))
+	} else {
+		b.WriteString(styleMuted.Render(hint))
+	}
+	return b.String()
+}
+
+// renderDeviceInfo renders the identifiers and capabilities of a light
+func (m MainModel) renderDeviceInfo(light *models.Light) string {
+	var rows [][2]string
+	rows = append(rows, [2]string{"Light ID", light.ID})
+	if light.DeviceName != "" {
+		rows = append(rows, [2]string{"Device", light.DeviceName})
+	}
+	if light.DeviceID != "" {
+		rows = append(rows, [2]string{"Device ID", light.DeviceID})
+	}
+	if light.Archetype != "" {
+		rows = append(rows, [2]string{"Type", models.ArchetypeLabel(light.Archetype)})
+	}
+	if room := m.lightToRoom[light.ID]; room != nil {
+		rows = append(rows, [2]string{"Room", room.Name})
+	}
+
+	var features []string
+	switch {
+	case light.OnOffOnly:
+		features = append(features, "on/off")
+	default:
+		features = append(features, "dimming")
+		if light.SupportsColorTemp {
+			features = append(features, "white")
+		}
+		if light.SupportsColor {
+			features = append(features, "color")
+		}
+		if g := light.Gradient; g != nil {
+			features = append(features, fmt.Sprintf("gradient (%d points)", g.PointsCapable))
+		}
+	}
+	rows = append(rows, [2]string{"Supports", strings.Join(features, ", ")})
+
+	reachable := "yes"
+	if !light.Reachable {
+		reachable = "no"
+	}
+	rows = append(rows, [2]string{"Reachable", reachable})
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(styleMuted.Render(row[0]+": ") + row[1] + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package screens
+
+import (
+	"sort"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SetLightLinks sets the groups of linked light IDs
+func (m *MainModel) SetLightLinks(links [][]string) {
+	m.links = links
+	m.recordLinkStates()
+}
+
+// linkGroup returns the index of the link group containing a light, or -1
+func (m *MainModel) linkGroup(lightID string) int {
+	for i, group := range m.links {
+		for _, id := range group {
+			if id == lightID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// linkedLights returns the other lights linked to a light
+func (m *MainModel) linkedLights(light *models.Light) []*models.Light {
+	i := m.linkGroup(light.ID)
+	if i < 0 {
+		return nil
+	}
+	var linked []*models.Light
+	for _, id := range m.links[i] {
+		if other := m.findLight(id); other != nil && id != light.ID {
+			linked = append(linked, other)
+		}
+	}
+	return linked
+}
+
+// toggleLink links the marked lights together, or unlinks the selected light
+// when nothing is marked. Returns the command persisting the new links.
+func (m *MainModel) toggleLink() tea.Cmd {
+	var links [][]string
+	switch {
+	case len(m.marked) >= 2:
+		group := make([]string, 0, len(m.marked))
+		for id := range m.marked {
+			group = append(group, id)
+		}
+		sort.Strings(group)
+		links = append(withoutLights(m.links, m.marked), group)
+		m.marked = make(map[string]bool)
+
+	case len(m.marked) == 0:
+		light := m.SelectedLight()
+		if light == nil || m.linkGroup(light.ID) < 0 {
+			return nil
+		}
+		links = withoutLights(m.links, map[string]bool{light.ID: true})
+
+	default:
+		return nil
+	}
+
+	m.SetLightLinks(links)
+	return func() tea.Msg { return messages.LightLinksChangedMsg{Links: links} }
+}
+
+// withoutLights removes lights from link groups, dropping groups left with
+// fewer than two lights
+func withoutLights(links [][]string, remove map[string]bool) [][]string {
+	var result [][]string
+	for _, group := range links {
+		var kept []string
+		for _, id := range group {
+			if !remove[id] {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) >= 2 {
+			result = append(result, kept)
+		}
+	}
+	return result
+}
+
+// recordLinkStates remembers the state of linked lights, to spot which one
+// changed on the next sync
+func (m *MainModel) recordLinkStates() {
+	m.linkStates = make(map[string]lightState)
+	for _, group := range m.links {
+		for _, id := range group {
+			if light := m.findLight(id); light != nil {
+				m.linkStates[id] = captureLightState(light)
+			}
+		}
+	}
+}
+
+// SyncLinks mirrors brightness and color changes of linked lights to the
+// rest of their group, whether the change came from a key or the bridge
+func (m *MainModel) SyncLinks(bridge api.BridgeClient, addPending PendingAdder, addCompound CompoundPendingAdder) tea.Cmd {
+	return m.syncLinks(bridge, pendingFuncs{add: addPending, compound: addCompound})
+}
+
+func (m *MainModel) syncLinks(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	sources := make(map[string]*models.Light)
+	var targets []*models.Light
+	for _, group := range m.links {
+		var members []*models.Light
+		changed := make(map[string]bool)
+		var source *models.Light
+		for _, id := range group {
+			light := m.findLight(id)
+			if light == nil {
+				continue
+			}
+			members = append(members, light)
+			prev, ok := m.linkStates[id]
+			cur := captureLightState(light)
+			if ok && (prev.brightness != cur.brightness || !colorEqual(prev.color, cur.color)) {
+				changed[id] = true
+				if source == nil {
+					source = light
+				}
+			}
+		}
+		if source == nil {
+			continue
+		}
+		// Lights changed directly in the same step keep their own change
+		for _, light := range members {
+			if !changed[light.ID] {
+				sources[light.ID] = source
+				targets = append(targets, light)
+			}
+		}
+	}
+
+	cmd := m.applyToLights(bridge, targets, func(light *models.Light) lightCalls {
+		return mirrorLightState(light, sources[light.ID], pending)
+	})
+	m.recordLinkStates()
+	return cmd
+}
+
+// mirrorLightState copies the brightness and color of source to a light,
+// skipping colors the light can't show. Lights that are off are left alone.
+func mirrorLightState(light, source *models.Light, pending pendingFuncs) lightCalls {
+	s := lightState{on: light.On, brightness: source.Brightness}
+	if c := source.Color; c != nil {
+		switch {
+		case c.Mode == models.ColorModeColorTemp && light.SupportsColorTemp,
+			c.Mode != models.ColorModeColorTemp && light.SupportsColor:
+			copied := *c
+			s.color = &copied
+		}
+	}
+	return restoreLightState(light, s, pending)
+}
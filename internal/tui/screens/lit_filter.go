@@ -0,0 +1,28 @@
+package screens
+
+import "github.com/angristan/hue-tui/internal/models"
+
+// toggleLitOnly hides or shows again the rooms with every light off,
+// keeping the selection when it is still listed
+func (m *MainModel) toggleLitOnly() {
+	var selected *listItem
+	if item := m.SelectedItem(); item != nil {
+		copied := *item
+		selected = &copied
+	}
+	m.litOnly = !m.litOnly
+	if m.litOnly {
+		m.notice = "Only rooms with lights on"
+	} else {
+		m.notice = "All rooms"
+	}
+	m.rebuildLightList()
+	if selected != nil {
+		m.reselect(*selected)
+	}
+}
+
+// listedRoom reports whether room passes the lit rooms filter
+func (m *MainModel) listedRoom(room *models.Room) bool {
+	return !m.litOnly || room.AnyOn
+}
@@ -1,9 +1,9 @@
 package screens
 
 import (
-	"context"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -14,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/locale"
 	"github.com/angristan/hue-tui/internal/models"
 	"github.com/angristan/hue-tui/internal/tui/messages"
 )
@@ -30,12 +31,16 @@ const (
 // PendingAdder is a function that registers a pending operation with direction
 type PendingAdder func(lightID, field string, value interface{}, dir Direction)
 
+// CompoundPendingAdder registers several fields changed together as one unit
+type CompoundPendingAdder func(lightID string, targets map[string]interface{})
+
 // Colors
 var (
 	colorPrimary = lipgloss.Color("#B794F4")
 	colorMuted   = lipgloss.Color("#6B6B80")
 	colorSuccess = lipgloss.Color("#68D391")
 	colorWarning = lipgloss.Color("#FBBF24")
+	colorError   = lipgloss.Color("#FC8181")
 	colorDim     = lipgloss.Color("#4A4A5A")
 )
 
@@ -57,6 +62,9 @@ var (
 	styleLightOff = lipgloss.NewStyle().
 			Foreground(colorDim)
 
+	styleLightFaulty = lipgloss.NewStyle().
+				Foreground(colorError)
+
 	styleLightName = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FAFAFA"))
 
@@ -85,6 +93,23 @@ var (
 			Padding(1, 2)
 )
 
+// roleKeys maps the keys that step the lights of a role to that role
+var roleKeys = map[string]models.LightRole{
+	"b": models.RoleTVBias,
+	"m": models.RoleAmbient,
+	"t": models.RoleTask,
+}
+
+// roleKey returns the key bound to a role
+func roleKey(role models.LightRole) string {
+	for key, r := range roleKeys {
+		if r == role {
+			return key
+		}
+	}
+	return ""
+}
+
 // listItem represents either a room header or a light in the unified list
 type listItem struct {
 	isRoom bool
@@ -100,15 +125,161 @@ type MainModel struct {
 	scrollOffset  int        // Vertical scroll offset
 	items         []listItem // Unified list of rooms and lights
 	lightToRoom   map[string]*models.Room
+	// Names telling apart lights that share a name, by light ID
+	displayNames map[string]string
+
+	// Lights marked in multi-select mode, by ID
+	marked map[string]bool
+
+	// Local light roles from the config, by light ID
+	roles map[string]models.LightRole
+
+	// Rooms whose live updates are ignored
+	mutedRooms []string
+
+	// Scene IDs bound to the shortcut keys, by room ID and key
+	sceneShortcuts map[string]map[string]string
+
+	// Lights whose last update the bridge never confirmed, by ID
+	unconfirmed map[string]bool
+
+	// Undo/redo stacks of light changes
+	history *undoHistory
+
+	// State of each room before its last scene, by room ID
+	sceneRecalls map[string]sceneRecall
+
+	// Groups of linked light IDs, and their last seen state
+	links      [][]string
+	linkStates map[string]lightState
+
+	// Pending key chord (the leader key), empty when none
+	chord string
+
+	// Help overlay opened with ?, nil when closed
+	help *helpOverlay
+	// Command palette opened with ctrl+p, nil when closed
+	palette *commandPalette
+
+	// Grouped light of every light, listed as "All lights" above the rooms
+	// when set
+	homeGroupID string
+
+	// Rendered rows of the light list
+	rows *rowCache
+
+	// One-off message shown in the status bar until the next key
+	notice string
+
+	// Set while scenes can't be fetched from the bridge
+	scenesUnavailable bool
+
+	showPanel bool
+	// Room whose light list in the side panel has the focus (nil = the
+	// main list), and how far that list is scrolled
+	panelRoom   *models.Room
+	panelScroll int
 
-	showPanel   bool
 	searchMode  bool
 	searchInput textinput.Model
 	searchQuery string
-
-	// Loading state
-	loading bool
-	spinner spinner.Model
+	// Hides the rooms with every light off
+	litOnly bool
+
+	// How room dimming reaches the bridge, and the room brightnesses
+	// previewed in commit mode by room ID
+	roomDimming string
+	roomDims    map[string]*roomDim
+	roomDimSeq  int
+	// Growth of the dimming step while an arrow key is held, and the
+	// current burst of repeats
+	acceleration string
+	repeat       keyRepeat
+
+	// Exact brightness input in the side panel
+	editingBrightness bool
+	brightnessInput   textinput.Model
+	brightnessError   string
+
+	// White calibration of two marked lights (nil when not calibrating),
+	// and the saved offsets by light ID
+	calibration      *calibration
+	colorTempOffsets map[string]int
+
+	// Whether space turns lights back on at the brightness and color they
+	// had when turned off, remembered by light ID
+	rememberColors bool
+	lightMemory    map[string]models.LightMemory
+
+	// Advanced color input in the side panel: exact hue, saturation,
+	// brightness and temperature, with the values it opened with
+	editingColor bool
+	colorInputs  [colorFieldCount]textinput.Model
+	colorInitial [colorFieldCount]string
+	colorFocus   int
+	colorError   string
+
+	// Archetype picker of the selected light (nil when closed)
+	archetype *archetypePicker
+
+	// Temperature mode: presets and kelvin input (nil when closed)
+	tempPicker *tempPicker
+
+	// Cursor on the color wheel of the side panel (nil when not moved)
+	colorWheel *colorWheel
+
+	// Actions menu of the selected light (nil when closed)
+	lightMenu *lightMenu
+
+	// Zone picker of the target lights (nil when closed), and the zones
+	// it lists
+	zonePicker *zonePicker
+	zones      []*models.Room
+
+	// Loading state, with the item count of each resource loaded so far
+	loading       bool
+	fetchProgress map[string]int
+	spinner       spinner.Model
+
+	// Scrolling of the selected light's name when it is too long
+	marquee    marquee
+	marqueeGen int
+
+	// How brightness is shown, in percent or on the 0-254 scale
+	format locale.Format
+
+	// Room fades in flight, by room ID, and how long F fades
+	fades        map[string]roomFade
+	fadeTicking  bool
+	fadeDuration time.Duration
+	// Clock the fades and key repeats are timed with
+	now func() time.Time
+
+	// How the lights without a room are listed, one of the OtherGrouping
+	// values
+	otherGrouping string
+	// Lists the lights by type instead of by room
+	byType bool
+
+	// Header accent color (empty = default theme color)
+	accent lipgloss.Color
+
+	// Reason of the alert flashing the header (empty when not flashing)
+	flash string
+
+	// Set while the bridge is unreachable, with the time to the next retry
+	offline bool
+	retryIn time.Duration
+
+	// Commands waiting for the bridge's rate limits
+	throttled int
+
+	// How the state is kept fresh, unknown while both are unset
+	liveUpdates  bool
+	pollInterval time.Duration
+
+	// Set while the app is idle, which pauses the animations
+	idle bool
 
 	width  int
 	height int
@@ -120,16 +291,31 @@ func NewMainModel(keys interface{}) MainModel {
 	ti.Placeholder = "Search..."
 	ti.CharLimit = 50
 
+	bi := textinput.New()
+	bi.CharLimit = 3
+	bi.Width = 4
+	bi.Prompt = ""
+
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(colorPrimary)
 
 	return MainModel{
-		searchInput: ti,
-		lightToRoom: make(map[string]*models.Room),
-		showPanel:   true, // Side panel on by default
-		loading:     true, // Start in loading state
-		spinner:     sp,
+		searchInput:     ti,
+		brightnessInput: bi,
+		colorInputs:     newColorInputs(),
+		lightToRoom:     make(map[string]*models.Room),
+		marked:          make(map[string]bool),
+		roles:           make(map[string]models.LightRole),
+		history:         &undoHistory{},
+		sceneRecalls:    make(map[string]sceneRecall),
+		fades:           make(map[string]roomFade),
+		now:             time.Now,
+		roomDims:        make(map[string]*roomDim),
+		rows:            newRowCache(),
+		showPanel:       true, // Side panel on by default
+		loading:         true, // Start in loading state
+		spinner:         sp,
 	}
 }
 
@@ -201,24 +387,173 @@ func (m *MainModel) SetData(rooms []*models.Room, scenes []*models.Scene) {
 	m.rooms = rooms
 	m.scenes = scenes
 	m.loading = false
+
+	// Forget marks for lights that no longer exist
+	if len(m.marked) > 0 {
+		present := make(map[string]bool)
+		for _, room := range rooms {
+			for _, light := range room.Lights {
+				present[light.ID] = true
+			}
+		}
+		for id := range m.marked {
+			if !present[id] {
+				delete(m.marked, id)
+			}
+		}
+	}
 	m.scrollOffset = 0
 	m.rebuildLightList()
+	m.recordLinkStates()
+}
+
+// SetOffline shows or clears the bridge unreachable banner
+func (m *MainModel) SetOffline(offline bool, retryIn time.Duration) {
+	m.offline = offline
+	m.retryIn = retryIn
+}
+
+// SetUpdateSource shows in the header how the state is kept fresh: live
+// events, or polling every pollInterval when the event stream can't connect
+func (m *MainModel) SetUpdateSource(live bool, pollInterval time.Duration) {
+	m.liveUpdates = live
+	m.pollInterval = pollInterval
+}
+
+// SetIdle pauses the animations while the app is idle, and restarts them
+// when it is back
+func (m *MainModel) SetIdle(idle bool) tea.Cmd {
+	m.idle = idle
+	if idle {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if len(m.fades) > 0 && !m.fadeTicking {
+		m.fadeTicking = true
+		cmds = append(cmds, fadeTick())
+	}
+	return tea.Batch(append(cmds, m.startMarquee())...)
+}
+
+// SetFlash flashes the header with the reason of an alert, or stops
+// flashing when empty
+func (m *MainModel) SetFlash(reason string) {
+	m.flash = reason
+}
+
+// SetThrottled shows how many commands wait for the bridge's rate limits
+func (m *MainModel) SetThrottled(queued int) {
+	m.throttled = queued
+}
+
+// SetNotice shows a one-off message in the status bar
+func (m *MainModel) SetNotice(notice string) {
+	m.notice = notice
+}
+
+// SetScenesUnavailable flags scenes as failing to load
+func (m *MainModel) SetScenesUnavailable(unavailable bool) {
+	m.scenesUnavailable = unavailable
+}
+
+// SetAccent overrides the header accent color (empty restores the default)
+func (m *MainModel) SetAccent(color lipgloss.Color) {
+	m.accent = color
+}
+
+// SetLightRoles sets the local light roles, by light ID
+func (m *MainModel) SetLightRoles(roles map[string]models.LightRole) {
+	m.roles = roles
+}
+
+// SetMutedRooms sets the rooms whose live updates are ignored
+func (m *MainModel) SetMutedRooms(roomIDs []string) {
+	m.mutedRooms = roomIDs
+}
+
+// SetSceneShortcuts sets the scene IDs bound to the keys 1-9, by room ID
+func (m *MainModel) SetSceneShortcuts(shortcuts map[string]map[string]string) {
+	m.sceneShortcuts = shortcuts
+}
+
+// SetUnconfirmed sets the lights whose last update the bridge never
+// confirmed, by ID
+func (m *MainModel) SetUnconfirmed(lightIDs map[string]bool) {
+	m.unconfirmed = lightIDs
+}
+
+// roleLights returns the lights of a room that have the given role
+func (m *MainModel) roleLights(room *models.Room, role models.LightRole) []*models.Light {
+	var lights []*models.Light
+	for _, light := range room.Lights {
+		if m.roles[light.ID] == role {
+			lights = append(lights, light)
+		}
+	}
+	return lights
 }
 
 func (m *MainModel) SetLoading(loading bool) {
 	m.loading = loading
+	if loading {
+		m.fetchProgress = nil
+	}
+}
+
+// SetFetchProgress records a resource type loaded by the running fetch
+func (m *MainModel) SetFetchProgress(resource string, count int) {
+	if m.fetchProgress == nil {
+		m.fetchProgress = make(map[string]int)
+	}
+	m.fetchProgress[resource] = count
+}
+
+// renderFetchProgress shows each resource type as loaded or pending,
+// e.g. "rooms ✓ 5  lights ✓ 14  devices…  scenes…"
+func (m MainModel) renderFetchProgress() string {
+	parts := make([]string, len(api.FetchResources))
+	for i, resource := range api.FetchResources {
+		if count, ok := m.fetchProgress[resource]; ok {
+			parts[i] = resource + lipgloss.NewStyle().Foreground(colorSuccess).Render(" ✓") + styleMuted.Render(fmt.Sprintf(" %d", count))
+		} else {
+			parts[i] = styleMuted.Render(resource + "…")
+		}
+	}
+	return strings.Join(parts, "  ")
 }
 
 func (m *MainModel) rebuildLightList() {
 	m.items = nil
 	m.lightToRoom = make(map[string]*models.Room)
 
+	var all []*models.Light
 	for _, room := range m.rooms {
+		all = append(all, room.Lights...)
+	}
+	// Only the names that differ are kept, so renames show right away
+	m.displayNames = models.DisplayNames(all, m.rooms)
+	for _, light := range all {
+		if m.displayNames[light.ID] == light.Name {
+			delete(m.displayNames, light.ID)
+		}
+	}
+
+	// The whole home comes first, but not in search results
+	if m.homeGroupID != "" && m.searchQuery == "" && len(all) > 0 {
+		if home := m.homeRoom(all); m.listedRoom(home) {
+			m.items = append(m.items, listItem{isRoom: true, room: home})
+		}
+	}
+
+	for _, room := range m.listedRooms() {
+		if !m.listedRoom(room) {
+			continue
+		}
 		hasMatchingLights := false
 		var roomLights []*models.Light
 
 		for _, light := range room.Lights {
-			if m.searchQuery == "" || strings.Contains(strings.ToLower(light.Name), strings.ToLower(m.searchQuery)) {
+			if m.searchQuery == "" || strings.Contains(strings.ToLower(m.displayName(light)), strings.ToLower(m.searchQuery)) {
 				roomLights = append(roomLights, light)
 				m.lightToRoom[light.ID] = room
 				hasMatchingLights = true
@@ -228,7 +563,7 @@ func (m *MainModel) rebuildLightList() {
 		if hasMatchingLights {
 			// Sort lights alphabetically by name
 			sort.Slice(roomLights, func(i, j int) bool {
-				return roomLights[i].Name < roomLights[j].Name
+				return m.displayName(roomLights[i]) < m.displayName(roomLights[j])
 			})
 			// Add room header
 			m.items = append(m.items, listItem{isRoom: true, room: room})
@@ -239,6 +574,10 @@ func (m *MainModel) rebuildLightList() {
 		}
 	}
 
+	if m.rows != nil {
+		m.rows.forget(m.items)
+	}
+
 	if m.selectedIndex >= len(m.items) {
 		m.selectedIndex = max(0, len(m.items)-1)
 	}
@@ -246,6 +585,15 @@ func (m *MainModel) rebuildLightList() {
 	m.ensureVisible()
 }
 
+// displayName returns the name of a light in lists, with its room or
+// device added when another light has the same name
+func (m *MainModel) displayName(light *models.Light) string {
+	if name, ok := m.displayNames[light.ID]; ok {
+		return name
+	}
+	return light.Name
+}
+
 func (m *MainModel) SelectedItem() *listItem {
 	if m.selectedIndex >= 0 && m.selectedIndex < len(m.items) {
 		return &m.items[m.selectedIndex]
@@ -274,8 +622,191 @@ func (m *MainModel) IsRoomSelected() bool {
 	return false
 }
 
-func (m MainModel) Update(msg tea.Msg, bridge api.BridgeClient, addPending PendingAdder) (MainModel, tea.Cmd) {
+// siblingLights returns the other lights owned by the same device, ordered
+// so that the first entry is the one following light in name order
+func (m *MainModel) siblingLights(light *models.Light) []*models.Light {
+	if light.DeviceID == "" {
+		return nil
+	}
+
+	var all []*models.Light
+	for _, room := range m.rooms {
+		for _, l := range room.Lights {
+			if l.DeviceID == light.DeviceID {
+				all = append(all, l)
+			}
+		}
+	}
+	if len(all) < 2 {
+		return nil
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Name != all[j].Name {
+			return all[i].Name < all[j].Name
+		}
+		return all[i].ID < all[j].ID
+	})
+
+	// Rotate so the list starts right after the current light
+	for i, l := range all {
+		if l.ID == light.ID {
+			siblings := make([]*models.Light, 0, len(all)-1)
+			siblings = append(siblings, all[i+1:]...)
+			return append(siblings, all[:i]...)
+		}
+	}
+	return nil
+}
+
+// targetLights returns the lights a light action applies to: every marked
+// light in multi-select mode, otherwise the selected light
+func (m *MainModel) targetLights() []*models.Light {
+	if len(m.marked) == 0 {
+		if light := m.SelectedLight(); light != nil {
+			return []*models.Light{light}
+		}
+		return nil
+	}
+
+	var targets []*models.Light
+	for _, room := range m.rooms {
+		for _, light := range room.Lights {
+			if m.marked[light.ID] {
+				targets = append(targets, light)
+			}
+		}
+	}
+	return targets
+}
+
+// applyToTargets applies a light action to every target light and sends the
+// resulting requests as one batched command
+func (m *MainModel) applyToTargets(bridge api.BridgeClient, action func(*models.Light) lightCalls) tea.Cmd {
+	return m.applyToLights(bridge, m.targetLights(), action)
+}
+
+// applyToLights applies a light action to each light as one batched command
+func (m *MainModel) applyToLights(bridge api.BridgeClient, lights []*models.Light, action func(*models.Light) lightCalls) tea.Cmd {
+	var batches []lightBatch
+	var turnedOff []*models.Light
+	touchedRooms := make(map[*models.Room]bool)
+	for _, light := range lights {
+		before := light.Clone()
+		if calls := action(light); len(calls) > 0 {
+			batches = append(batches, lightBatch{lightID: light.ID, calls: calls, before: before, after: light.Clone()})
+			if room := m.lightToRoom[light.ID]; room != nil {
+				touchedRooms[room] = true
+			}
+			if before.On && !light.On {
+				turnedOff = append(turnedOff, before)
+			}
+		}
+	}
+	for room := range touchedRooms {
+		room.UpdateState()
+	}
+	// Listed by type, the rooms the lights belong to are elsewhere
+	if m.byType && len(batches) > 0 {
+		for _, room := range m.rooms {
+			room.UpdateState()
+		}
+	}
+	if remember := m.rememberLights(turnedOff); remember != nil {
+		return tea.Batch(runLightCalls(bridge, batches), remember)
+	}
+	return runLightCalls(bridge, batches)
+}
+
+// setGroupOn switches every light of a room on or off with one grouped
+// light request, rolling them back if it fails
+func (m *MainModel) setGroupOn(bridge api.BridgeClient, room *models.Room, on bool, pending pendingFuncs) tea.Cmd {
+	batches := make([]lightBatch, 0, len(room.Lights))
+	for _, light := range room.Lights {
+		before := light.Clone()
+		light.On = on
+		pending.addOp(light.ID, "on", on, DirExact)
+		batches = append(batches, lightBatch{lightID: light.ID, before: before, after: light.Clone()})
+	}
+	m.updateRoomState(room)
+	return runGroupCall(bridge, room.Name, callSetGroupOn(room.GroupedLightID, on), batches)
+}
+
+// applyRole steps the lights with the given role in the selected room:
+// lit lights dim, dimmed lights turn off and off lights turn back on
+func (m *MainModel) applyRole(bridge api.BridgeClient, role models.LightRole, pending pendingFuncs) tea.Cmd {
+	room := m.SelectedRoom()
+	if room == nil {
+		return nil
+	}
+	lights := m.roleLights(room, role)
+	step := nextRoleStep(lights)
+	return m.applyToLights(bridge, lights, func(light *models.Light) lightCalls {
+		return applyRoleStep(light, step, pending)
+	})
+}
+
+// toggleMark marks or unmarks the selected light, or all lights of the
+// selected room (unmarking only if they were all marked)
+func (m *MainModel) toggleMark() {
+	item := m.SelectedItem()
+	if item == nil {
+		return
+	}
+	if !item.isRoom {
+		if m.marked[item.light.ID] {
+			delete(m.marked, item.light.ID)
+		} else {
+			m.marked[item.light.ID] = true
+		}
+		return
+	}
+
+	allMarked := true
+	for _, light := range item.room.Lights {
+		if !m.marked[light.ID] {
+			allMarked = false
+			break
+		}
+	}
+	for _, light := range item.room.Lights {
+		if allMarked {
+			delete(m.marked, light.ID)
+		} else {
+			m.marked[light.ID] = true
+		}
+	}
+}
+
+// FocusRoom selects the room named name, ignoring case, and scrolls it to
+// the top of the list
+func (m *MainModel) FocusRoom(name string) bool {
+	for i, item := range m.items {
+		if item.isRoom && strings.EqualFold(item.room.Name, name) {
+			m.selectedIndex = i
+			m.scrollOffset = i
+			m.ensureVisible()
+			return true
+		}
+	}
+	return false
+}
+
+// selectLight moves the selection to the given light if it's in the list
+func (m *MainModel) selectLight(lightID string) bool {
+	for i, item := range m.items {
+		if !item.isRoom && item.light.ID == lightID {
+			m.selectedIndex = i
+			m.ensureVisible()
+			return true
+		}
+	}
+	return false
+}
+
+func (m MainModel) Update(msg tea.Msg, bridge api.BridgeClient, addPending PendingAdder, addCompound CompoundPendingAdder) (MainModel, tea.Cmd) {
 	var cmds []tea.Cmd
+	pending := pendingFuncs{add: addPending, compound: addCompound}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -303,10 +834,90 @@ func (m MainModel) Update(msg tea.Msg, bridge api.BridgeClient, addPending Pendi
 			}
 		}
 
+		if m.help != nil {
+			return m, m.updateHelpOverlay(msg)
+		}
+		if m.palette != nil {
+			return m, m.updatePalette(msg)
+		}
+		if m.editingBrightness {
+			return m, m.updateBrightnessInput(msg, bridge, pending)
+		}
+		if m.editingColor {
+			return m, m.updateColorInput(msg, bridge, pending)
+		}
+		if m.archetype != nil {
+			return m, m.updateArchetypePicker(msg, bridge)
+		}
+		if m.tempPicker != nil {
+			return m, m.updateTempPicker(msg, bridge, pending)
+		}
+		if m.colorWheel != nil {
+			return m, m.updateColorWheel(msg, bridge, pending)
+		}
+		if m.zonePicker != nil {
+			return m, m.updateZonePicker(msg)
+		}
+		if m.lightMenu != nil {
+			return m, m.updateLightMenu(msg, bridge)
+		}
+		if m.calibration != nil {
+			return m, m.updateCalibration(msg)
+		}
+
+		m.notice = ""
+		if m.chord != "" {
+			return m, m.finishChord(msg.String())
+		}
+
+		// With the room panel focused, up and down walk its lights, other
+		// keys act on the selected light as usual
+		if m.panelRoom != nil {
+			switch msg.String() {
+			case "up", "k":
+				m.movePanelSelection(-1)
+				return m, nil
+			case "down", "j":
+				m.movePanelSelection(1)
+				return m, nil
+			case "esc", "shift+tab":
+				m.panelRoom = nil
+				return m, nil
+			}
+		}
+
+		// Every keystroke that changes lights becomes one undo step
+		before := m.captureLights()
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 
+		case leaderKey:
+			m.chord = leaderKey
+			return m, nil
+
+		case "?":
+			m.help = &helpOverlay{}
+			return m, nil
+
+		case "ctrl+p":
+			m.palette = &commandPalette{}
+			return m, nil
+
+		case "u":
+			cmds = append(cmds, m.undo(bridge, pending), m.syncLinks(bridge, pending))
+			return m, tea.Batch(cmds...)
+
+		case "ctrl+r":
+			cmds = append(cmds, m.redo(bridge, pending), m.syncLinks(bridge, pending))
+			return m, tea.Batch(cmds...)
+
+		case "ctrl+z":
+			// Put the room back as it was before its last scene
+			cmds = append(cmds, m.revertScene(bridge, pending), m.syncLinks(bridge, pending))
+			return m, tea.Batch(cmds...)
+
 		case "up", "k":
 			if m.selectedIndex > 0 {
 				m.selectedIndex--
@@ -348,267 +959,209 @@ func (m MainModel) Update(msg tea.Msg, bridge api.BridgeClient, addPending Pendi
 			m.ensureVisible()
 
 		case "left", "h":
-			if m.IsRoomSelected() {
-				// Dim all lights in room
-				if room := m.SelectedRoom(); room != nil {
-					for _, light := range room.Lights {
-						if light.On {
-							newBrightness := max(10, light.BrightnessPct()-10)
-							light.SetBrightnessPct(newBrightness)
-							if addPending != nil {
-								addPending(light.ID, "brightness", newBrightness, DirDown)
-							}
-							cmds = append(cmds, m.setBrightnessCmd(bridge, light.ID, newBrightness))
-						}
-					}
-				}
-			} else if light := m.SelectedLight(); light != nil && light.On {
-				newBrightness := max(0, light.BrightnessPct()-10)
-				if newBrightness == 0 {
-					light.On = false
-					if addPending != nil {
-						addPending(light.ID, "on", false, DirExact)
-					}
-					cmds = append(cmds, m.toggleLightCmd(bridge, light.ID, false))
-				} else {
-					light.SetBrightnessPct(newBrightness)
-					if addPending != nil {
-						addPending(light.ID, "brightness", newBrightness, DirDown)
-					}
-					cmds = append(cmds, m.setBrightnessCmd(bridge, light.ID, newBrightness))
-				}
-			}
+			cmds = append(cmds, m.stepBrightness(bridge, -m.acceleratedStep("left", brightnessStep), pending))
 
 		case "right", "l":
-			if m.IsRoomSelected() {
-				// Brighten all lights in room
-				if room := m.SelectedRoom(); room != nil {
-					for _, light := range room.Lights {
-						if light.On {
-							newBrightness := min(100, light.BrightnessPct()+10)
-							light.SetBrightnessPct(newBrightness)
-							if addPending != nil {
-								addPending(light.ID, "brightness", newBrightness, DirUp)
-							}
-							cmds = append(cmds, m.setBrightnessCmd(bridge, light.ID, newBrightness))
-						}
-					}
-				}
-			} else if light := m.SelectedLight(); light != nil {
-				if !light.On {
-					light.On = true
-					light.SetBrightnessPct(10)
-					if addPending != nil {
-						addPending(light.ID, "on", true, DirExact)
-						addPending(light.ID, "brightness", 10, DirUp)
-					}
-					cmds = append(cmds, m.toggleLightCmd(bridge, light.ID, true))
-					cmds = append(cmds, m.setBrightnessCmd(bridge, light.ID, 10))
-				} else {
-					newBrightness := min(100, light.BrightnessPct()+10)
-					light.SetBrightnessPct(newBrightness)
-					if addPending != nil {
-						addPending(light.ID, "brightness", newBrightness, DirUp)
-					}
-					cmds = append(cmds, m.setBrightnessCmd(bridge, light.ID, newBrightness))
-				}
+			cmds = append(cmds, m.stepBrightness(bridge, m.acceleratedStep("right", brightnessStep), pending))
+
+		case "shift+left":
+			cmds = append(cmds, m.stepBrightness(bridge, -brightnessFineStep, pending))
+
+		case "shift+right":
+			cmds = append(cmds, m.stepBrightness(bridge, brightnessFineStep, pending))
+
+		case "%":
+			cmds = append(cmds, m.startBrightnessInput())
+
+		case "C":
+			cmds = append(cmds, m.startColorInput())
+
+		case "K":
+			cmds = append(cmds, m.startCalibration(bridge, pending))
+
+		case "T":
+			m.startTempPicker()
+
+		case "F":
+			cmds = append(cmds, m.fadeRoom(bridge, pending))
+
+		case "alt+h", "alt+j", "alt+k", "alt+l":
+			m.moveColorWheel(msg.String())
+
+		case "#":
+			m.format = m.format.ToggleBrightness()
+			m.notice = "Brightness in percent"
+			if m.format.RawBrightness() {
+				m.notice = "Brightness on the 0-254 scale"
 			}
 
 		case " ":
-			if m.IsRoomSelected() {
+			if len(m.marked) == 0 && m.IsRoomSelected() {
 				// Toggle all lights in room
 				if room := m.SelectedRoom(); room != nil && room.GroupedLightID != "" {
+					room.UpdateState()
 					newState := !room.AnyOn
-					for _, l := range room.Lights {
-						l.On = newState
-						if addPending != nil {
-							addPending(l.ID, "on", newState, DirExact)
-						}
+					if newState && m.remembers(room.Lights) {
+						// Lights come back one by one, each as remembered
+						cmds = append(cmds, m.applyToLights(bridge, room.Lights, func(light *models.Light) lightCalls {
+							return m.turnOn(light, pending)
+						}))
+					} else {
+						cmds = append(cmds, m.rememberLights(room.Lights))
+						cmds = append(cmds, m.setGroupOn(bridge, room, newState, pending))
 					}
-					room.UpdateState()
-					cmds = append(cmds, m.setGroupOnCmd(bridge, room.GroupedLightID, newState))
 				}
-			} else if light := m.SelectedLight(); light != nil {
-				light.On = !light.On
-				if addPending != nil {
-					addPending(light.ID, "on", light.On, DirExact)
+			} else {
+				// Multiple targets toggle together: all on unless any is on
+				newState := true
+				for _, light := range m.targetLights() {
+					if light.On {
+						newState = false
+						break
+					}
 				}
-				cmds = append(cmds, m.toggleLightCmd(bridge, light.ID, light.On))
+				cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+					if newState {
+						return m.turnOn(light, pending)
+					}
+					return setLightOn(light, false, pending)
+				}))
 			}
 
 		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
-			if light := m.SelectedLight(); light != nil {
-				brightness := brightnessFromKey(msg.String())
-				if brightness >= 0 {
-					oldBrightness := light.BrightnessPct()
-					light.SetBrightnessPct(brightness)
-					if !light.On {
-						light.On = true
-						if addPending != nil {
-							addPending(light.ID, "on", true, DirExact)
-						}
-						cmds = append(cmds, m.toggleLightCmd(bridge, light.ID, true))
-					}
-					if addPending != nil {
-						dir := DirExact
-						if brightness > oldBrightness {
-							dir = DirUp
-						} else if brightness < oldBrightness {
-							dir = DirDown
-						}
-						addPending(light.ID, "brightness", brightness, dir)
-					}
-					cmds = append(cmds, m.setBrightnessCmd(bridge, light.ID, brightness))
-				}
+			if brightness := brightnessFromKey(msg.String()); brightness >= 0 {
+				cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+					return setLightBrightness(light, brightness, pending)
+				}))
 			}
 
 		case "w":
-			if light := m.SelectedLight(); light != nil && light.SupportsColorTemp && light.Color != nil {
-				// Switch to temperature mode and make warmer (higher mirek = warmer)
-				if light.Color.Mirek == 0 {
-					light.Color.Mirek = 326 // Default to middle (3000K)
-				}
-				newMirek := min(500, int(light.Color.Mirek)+25)
-				light.Color.Mirek = uint16(newMirek)
-				light.Color.Mode = models.ColorModeColorTemp
-				light.Color.InvalidateCache()
-				if addPending != nil {
-					addPending(light.ID, "color_temp", newMirek, DirUp)
-				}
-				cmds = append(cmds, m.setColorTempCmd(bridge, light.ID, newMirek))
-			}
+			// Switch to temperature mode and make warmer (higher mirek = warmer)
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return stepLightColorTemp(light, 25, pending)
+			}))
 
 		case "c":
-			if light := m.SelectedLight(); light != nil && light.SupportsColorTemp && light.Color != nil {
-				// Switch to temperature mode and make cooler (lower mirek = cooler)
-				if light.Color.Mirek == 0 {
-					light.Color.Mirek = 326 // Default to middle (3000K)
-				}
-				newMirek := max(153, int(light.Color.Mirek)-25)
-				light.Color.Mirek = uint16(newMirek)
-				light.Color.Mode = models.ColorModeColorTemp
-				light.Color.InvalidateCache()
-				if addPending != nil {
-					addPending(light.ID, "color_temp", newMirek, DirDown)
-				}
-				cmds = append(cmds, m.setColorTempCmd(bridge, light.ID, newMirek))
-			}
+			// Switch to temperature mode and make cooler (lower mirek = cooler)
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return stepLightColorTemp(light, -25, pending)
+			}))
 
 		case "[":
-			// Decrease hue (rotate color wheel left)
-			if light := m.SelectedLight(); light != nil && light.SupportsColor && light.Color != nil {
-				// Initialize HS from current color if switching from other mode
-				if light.Color.Mode != models.ColorModeHS {
-					r, g, b := light.Color.RGB()
-					h, s := rgbToHueSat(r, g, b)
-					light.Color.Hue = uint16(float64(h) / 360.0 * 65535.0)
-					light.Color.Saturation = uint8(float64(s) / 100.0 * 254.0)
-					light.Color.Brightness = light.Brightness // Preserve brightness
-				}
-				newHue := (int(light.Color.Hue) - 3640 + 65536) % 65536 // -20° in hue units
-				light.Color.Hue = uint16(newHue)
-				light.Color.Mode = models.ColorModeHS
-				light.Color.InvalidateCache()
-				if addPending != nil {
-					x, y := api.HSToXY(light.Color.Hue, light.Color.Saturation)
-					addPending(light.ID, "color_xy", struct{ X, Y float64 }{x, y}, DirExact)
-				}
-				cmds = append(cmds, m.setColorHSCmd(bridge, light.ID, light.Color.Hue, light.Color.Saturation))
-			}
+			// Decrease hue (rotate color wheel left, -20° in hue units)
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return stepLightHue(light, -3640, pending)
+			}))
+			m.warnOutsideGamut(m.targetLights())
 
 		case "]":
-			// Increase hue (rotate color wheel right)
-			if light := m.SelectedLight(); light != nil && light.SupportsColor && light.Color != nil {
-				// Initialize HS from current color if switching from other mode
-				if light.Color.Mode != models.ColorModeHS {
-					r, g, b := light.Color.RGB()
-					h, s := rgbToHueSat(r, g, b)
-					light.Color.Hue = uint16(float64(h) / 360.0 * 65535.0)
-					light.Color.Saturation = uint8(float64(s) / 100.0 * 254.0)
-					light.Color.Brightness = light.Brightness // Preserve brightness
-				}
-				newHue := (int(light.Color.Hue) + 3640) % 65536 // +20° in hue units
-				light.Color.Hue = uint16(newHue)
-				light.Color.Mode = models.ColorModeHS
-				light.Color.InvalidateCache()
-				if addPending != nil {
-					x, y := api.HSToXY(light.Color.Hue, light.Color.Saturation)
-					addPending(light.ID, "color_xy", struct{ X, Y float64 }{x, y}, DirExact)
-				}
-				cmds = append(cmds, m.setColorHSCmd(bridge, light.ID, light.Color.Hue, light.Color.Saturation))
-			}
+			// Increase hue (rotate color wheel right, +20° in hue units)
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return stepLightHue(light, 3640, pending)
+			}))
+			m.warnOutsideGamut(m.targetLights())
 
 		case "-":
 			// Decrease saturation
-			if light := m.SelectedLight(); light != nil && light.SupportsColor && light.Color != nil {
-				// Initialize HS from current color if switching from other mode
-				if light.Color.Mode != models.ColorModeHS {
-					r, g, b := light.Color.RGB()
-					h, s := rgbToHueSat(r, g, b)
-					light.Color.Hue = uint16(float64(h) / 360.0 * 65535.0)
-					light.Color.Saturation = uint8(float64(s) / 100.0 * 254.0)
-					light.Color.Brightness = light.Brightness // Preserve brightness
-				}
-				newSat := max(0, int(light.Color.Saturation)-25)
-				light.Color.Saturation = uint8(newSat)
-				light.Color.Mode = models.ColorModeHS
-				light.Color.InvalidateCache()
-				if addPending != nil {
-					x, y := api.HSToXY(light.Color.Hue, light.Color.Saturation)
-					addPending(light.ID, "color_xy", struct{ X, Y float64 }{x, y}, DirExact)
-				}
-				cmds = append(cmds, m.setColorHSCmd(bridge, light.ID, light.Color.Hue, light.Color.Saturation))
-			}
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return stepLightSaturation(light, -25, pending)
+			}))
+			m.warnOutsideGamut(m.targetLights())
 
 		case "=", "+":
 			// Increase saturation
-			if light := m.SelectedLight(); light != nil && light.SupportsColor && light.Color != nil {
-				// Initialize HS from current color if switching from other mode
-				if light.Color.Mode != models.ColorModeHS {
-					r, g, b := light.Color.RGB()
-					h, s := rgbToHueSat(r, g, b)
-					light.Color.Hue = uint16(float64(h) / 360.0 * 65535.0)
-					light.Color.Saturation = uint8(float64(s) / 100.0 * 254.0)
-					light.Color.Brightness = light.Brightness // Preserve brightness
-				}
-				newSat := min(254, int(light.Color.Saturation)+25)
-				light.Color.Saturation = uint8(newSat)
-				light.Color.Mode = models.ColorModeHS
-				light.Color.InvalidateCache()
-				if addPending != nil {
-					x, y := api.HSToXY(light.Color.Hue, light.Color.Saturation)
-					addPending(light.ID, "color_xy", struct{ X, Y float64 }{x, y}, DirExact)
-				}
-				cmds = append(cmds, m.setColorHSCmd(bridge, light.ID, light.Color.Hue, light.Color.Saturation))
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return stepLightSaturation(light, 25, pending)
+			}))
+			m.warnOutsideGamut(m.targetLights())
+
+		case "v":
+			// Mark/unmark the selected light (or every light of the selected room)
+			m.toggleMark()
+
+		case "esc":
+			// Drop the previewed brightness of the selected room first
+			if roomID, ok := m.selectedRoomDim(); ok {
+				delete(m.roomDims, roomID)
+			} else {
+				m.marked = make(map[string]bool)
 			}
 
 		case "a":
 			if room := m.SelectedRoom(); room != nil && room.GroupedLightID != "" {
-				for _, l := range room.Lights {
-					l.On = true
-					if addPending != nil {
-						addPending(l.ID, "on", true, DirExact)
-					}
-				}
-				room.UpdateState()
-				cmds = append(cmds, m.setGroupOnCmd(bridge, room.GroupedLightID, true))
+				cmds = append(cmds, m.setGroupOn(bridge, room, true, pending))
 			}
 
 		case "x":
 			if room := m.SelectedRoom(); room != nil && room.GroupedLightID != "" {
-				for _, l := range room.Lights {
-					l.On = false
-					if addPending != nil {
-						addPending(l.ID, "on", false, DirExact)
-					}
+				cmds = append(cmds, m.setGroupOn(bridge, room, false, pending))
+			}
+
+		case "b", "m", "t":
+			// Dim, then turn off, then turn on the lights of a role in the room
+			cmds = append(cmds, m.applyRole(bridge, roleKeys[msg.String()], pending))
+
+		case "i":
+			// Blink the target lights to find the physical bulbs
+			cmds = append(cmds, m.applyToTargets(bridge, func(light *models.Light) lightCalls {
+				return lightCalls{callIdentify(light.ID)}
+			}))
+
+		case "enter":
+			// Send the previewed brightness of the selected room, or open
+			// the actions menu of the selected light
+			if roomID, ok := m.selectedRoomDim(); ok {
+				cmds = append(cmds, m.commitRoomDim(bridge, roomID, pending))
+			} else {
+				m.startLightMenu()
+			}
+
+		case "A":
+			// Pick the type of the selected light, as shown by the Hue app
+			m.startArchetypePicker()
+
+		case "Z":
+			// Add the target lights to zones, or remove them
+			cmds = append(cmds, m.startZonePicker())
+
+		case "L":
+			// Link the marked lights, or unlink the selected one
+			cmds = append(cmds, m.toggleLink())
+
+		case "M":
+			// Ignore or follow again the live updates of the selected room
+			if room := m.SelectedRoom(); room != nil && !isHome(room) && !isTypeRoom(room) {
+				muted := !slices.Contains(m.mutedRooms, room.ID)
+				if muted {
+					m.notice = "Live updates of " + room.Name + " paused"
+				} else {
+					m.notice = "Live updates of " + room.Name + " resumed"
+				}
+				cmds = append(cmds, func() tea.Msg { return messages.RoomMutedMsg{RoomID: room.ID, Muted: muted} })
+			}
+
+		case "n":
+			// Jump to the next light on the same device (multi-channel fixtures)
+			if light := m.SelectedLight(); light != nil {
+				if siblings := m.siblingLights(light); len(siblings) > 0 {
+					m.selectLight(siblings[0].ID)
+				}
+			}
+
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			// Activate a scene of the selected room without opening the modal
+			if room := m.SelectedRoom(); room != nil {
+				key := strings.TrimPrefix(msg.String(), "alt+")
+				n := int(key[0] - '0')
+				if scene := models.SceneShortcut(m.scenes, room.ID, m.sceneShortcuts[room.ID][key], n); scene != nil {
+					m.notice = "Scene " + scene.Name + " activated"
+					return m, func() tea.Msg { return messages.SceneActivatedMsg{SceneID: scene.ID} }
 				}
-				room.UpdateState()
-				cmds = append(cmds, m.setGroupOnCmd(bridge, room.GroupedLightID, false))
 			}
 
 		case "s":
 			roomID := ""
-			if room := m.SelectedRoom(); room != nil {
+			if room := m.SelectedRoom(); room != nil && !isHome(room) && !isTypeRoom(room) {
 				roomID = room.ID
 			}
 			return m, func() tea.Msg { return messages.ShowScenesMsg{RoomID: roomID} }
@@ -620,12 +1173,67 @@ func (m MainModel) Update(msg tea.Msg, bridge api.BridgeClient, addPending Pendi
 
 		case "tab":
 			m.showPanel = !m.showPanel
+			m.panelRoom = nil
+
+		case "shift+tab":
+			m.focusRoomPanel()
+
+		case "e":
+			return m, func() tea.Msg { return messages.ShowEntertainmentMsg{} }
+
+		case "S":
+			return m, func() tea.Msg { return messages.ShowSchedulesMsg{} }
+
+		case "R":
+			return m, func() tea.Msg { return messages.ShowRoomsMsg{} }
+
+		case "D":
+			return m, func() tea.Msg { return messages.ShowDevicesMsg{} }
+
+		case "E":
+			return m, func() tea.Msg { return messages.ShowNotificationsMsg{} }
+
+		case "o":
+			m.toggleLitOnly()
+
+		case "B":
+			return m, func() tea.Msg { return messages.ShowBridgesMsg{} }
+
+		case "P":
+			return m, m.exportSnapshot()
 
 		case "r":
-			m.loading = true
+			m.SetLoading(true)
 			cmds = append(cmds, m.spinner.Tick)
 			return m, tea.Batch(func() tea.Msg { return messages.RefreshMsg{} }, tea.Batch(cmds...))
 		}
+		m.history.record(before, m.captureLights())
+		cmds = append(cmds, m.syncLinks(bridge, pending), m.startMarquee())
+		// The panel focus ends when the selection leaves the room
+		if m.panelRoom != nil && m.SelectedRoom() != m.panelRoom {
+			m.panelRoom = nil
+		}
+
+	case messages.SnapshotSavedMsg:
+		m.notice = "Saved " + strings.Join(msg.Paths, " and ")
+
+	case marqueeTickMsg:
+		cmds = append(cmds, m.advanceMarquee(msg))
+
+	case fadeTickMsg:
+		cmds = append(cmds, m.advanceFades())
+
+	case fadeFailedMsg:
+		cmds = append(cmds, m.fadeFailed(msg))
+
+	case roomDimCommitMsg:
+		// Only the last key of a preview sends it
+		if dim, ok := m.roomDims[msg.roomID]; ok && dim.seq == msg.seq {
+			before := m.captureLights()
+			cmds = append(cmds, m.commitRoomDim(bridge, msg.roomID, pending))
+			m.history.record(before, m.captureLights())
+			cmds = append(cmds, m.syncLinks(bridge, pending))
+		}
 
 	case spinner.TickMsg:
 		if m.loading {
@@ -639,15 +1247,54 @@ func (m MainModel) Update(msg tea.Msg, bridge api.BridgeClient, addPending Pendi
 }
 
 func (m MainModel) View() string {
+	if m.help != nil {
+		return m.renderHelpOverlay()
+	}
+	if m.palette != nil {
+		return m.renderPalette()
+	}
+
 	var b strings.Builder
 
 	// Header
-	header := styleHeader.Render(" HUE CLI ")
+	headerStyle := styleHeader
+	if m.accent != "" {
+		headerStyle = headerStyle.Background(m.accent).Foreground(contrastText(m.accent))
+	}
+	if m.flash != "" {
+		headerStyle = headerStyle.Background(colorError).Foreground(contrastText(colorError))
+	}
+	header := headerStyle.Render(" HUE CLI ")
 	var status string
-	if m.loading {
+	if m.flash != "" {
+		status = lipgloss.NewStyle().Foreground(colorError).Bold(true).Render(" ⚠ " + m.flash)
+	} else if m.offline {
+		retry := "retrying…"
+		if secs := int(math.Ceil(m.retryIn.Seconds())); secs > 0 {
+			retry = fmt.Sprintf("retrying in %ds", secs)
+		}
+		status = lipgloss.NewStyle().Foreground(colorWarning).Render(" ⚠ Bridge unreachable – " + retry)
+	} else if m.loading {
 		status = lipgloss.NewStyle().Foreground(colorWarning).Render(" ⟳ Loading...")
 	} else {
 		status = lipgloss.NewStyle().Foreground(colorSuccess).Render(" ● Connected")
+		if m.liveUpdates {
+			status += styleMuted.Render(" · live")
+		} else if m.pollInterval > 0 && m.idle {
+			status += styleMuted.Render(" · polling paused while idle")
+		} else if m.pollInterval > 0 {
+			status += styleMuted.Render(fmt.Sprintf(" · polling every %ds", int(m.pollInterval.Seconds())))
+		}
+	}
+	if m.throttled > 0 && !m.offline {
+		status += lipgloss.NewStyle().Foreground(colorWarning).Render(fmt.Sprintf("  ⏳ %d queued", m.throttled)) +
+			styleMuted.Render(" (bridge rate limit)")
+	}
+	if len(m.marked) > 0 {
+		status += styleSearch.Render(fmt.Sprintf("  ✓ %d selected", len(m.marked))) + styleMuted.Render(" (esc to clear)")
+	}
+	if m.litOnly {
+		status += styleSearch.Render("  ● lit rooms only") + styleMuted.Render(" (o to show all)")
 	}
 	headerLine := header + status
 	b.WriteString(headerLine)
@@ -665,21 +1312,8 @@ func (m MainModel) View() string {
 	b.WriteString("\n")
 
 	// Calculate content area with responsive layout
-	contentWidth := m.width
-	panelWidth := 0
-	// Auto-hide panel on narrow terminals, show panel only if enabled and width >= 80
-	showPanelNow := m.showPanel && m.width >= 80
-	if showPanelNow {
-		// Panel takes ~30% of width, with min 30 and max 45
-		panelWidth = m.width * 30 / 100
-		if panelWidth < 30 {
-			panelWidth = 30
-		}
-		if panelWidth > 45 {
-			panelWidth = 45
-		}
-		contentWidth = m.width - panelWidth - 3
-	}
+	contentWidth, panelWidth := m.layoutWidths()
+	showPanelNow := panelWidth > 0
 
 	// Main content with vertical scrolling
 	var content strings.Builder
@@ -704,11 +1338,11 @@ func (m MainModel) View() string {
 			if idx > m.scrollOffset {
 				content.WriteString("\n")
 			}
-			content.WriteString(m.renderRoomHeader(item.room, isSelected))
+			content.WriteString(m.cachedRoomHeader(item.room, isSelected))
 			content.WriteString("\n")
 		} else {
 			// Light row - no extra spacing needed
-			content.WriteString(m.renderLightRow(item.light, isSelected, contentWidth))
+			content.WriteString(m.cachedLightRow(item.light, isSelected, contentWidth))
 			content.WriteString("\n")
 		}
 	}
@@ -721,7 +1355,9 @@ func (m MainModel) View() string {
 
 	if len(m.items) == 0 {
 		if m.loading {
-			content.WriteString(fmt.Sprintf("  %s Loading lights...", m.spinner.View()))
+			content.WriteString(fmt.Sprintf("  %s %s", m.spinner.View(), m.renderFetchProgress()))
+		} else if m.litOnly {
+			content.WriteString(styleMuted.Render("  No lights on"))
 		} else {
 			content.WriteString(styleMuted.Render("  No lights found"))
 		}
@@ -772,6 +1408,12 @@ func (m MainModel) renderRoomHeader(room *models.Room, selected bool) string {
 	// Room name (always use styleRoomName to preserve MarginTop and avoid flicker)
 	nameStyle := styleRoomName
 
+	return fmt.Sprintf("%s%s %s", cursor, nameStyle.Render(room.Name), styleMuted.Render(m.roomSummary(room)))
+}
+
+// roomSummary returns the lights on, brightness and state shown after a
+// room's name
+func (m MainModel) roomSummary(room *models.Room) string {
 	// Count lights on
 	lightsOn := 0
 	totalBrightness := 0
@@ -786,33 +1428,49 @@ func (m MainModel) renderRoomHeader(room *models.Room, selected bool) string {
 	summary := fmt.Sprintf("(%d/%d on", lightsOn, len(room.Lights))
 	if lightsOn > 0 {
 		avgBrightness := totalBrightness / lightsOn
-		summary += fmt.Sprintf(" • %d%%", avgBrightness)
+		summary += " • " + m.format.Brightness(models.PctToLevel(float64(avgBrightness)))
 	}
 	summary += ")"
-
-	return fmt.Sprintf("%s%s %s", cursor, nameStyle.Render(room.Name), styleMuted.Render(summary))
-}
-
-func (m MainModel) renderLightRow(light *models.Light, selected bool, width int) string {
-	// Cursor - always same width character
-	cursor := styleMuted.Render("  ")
-	if selected {
-		cursor = styleSelected.Render("> ")
+	if slices.Contains(m.mutedRooms, room.ID) {
+		summary += " ⏸ live updates paused"
+	}
+	if fade, ok := m.fades[room.ID]; ok {
+		summary += renderFadeProgress(fade, m.now())
+	}
+	if dim, ok := m.roomDims[room.ID]; ok {
+		summary += " → " + m.format.Brightness(models.PctToLevel(float64(dim.target))) + " (enter to apply)"
 	}
+	return summary
+}
 
-	// Status icon
-	icon := styleLightOff.Render("○")
-	if light.On {
-		icon = styleLightOn.Render("●")
+// layoutWidths returns the width of the light list and of the side panel,
+// 0 when hidden
+func (m MainModel) layoutWidths() (contentWidth, panelWidth int) {
+	contentWidth = m.width
+	// Auto-hide panel on narrow terminals, show panel only if enabled and width >= 80
+	if m.showPanel && m.width >= 80 {
+		// Panel takes ~30% of width, with min 30 and max 45
+		panelWidth = m.width * 30 / 100
+		if panelWidth < 30 {
+			panelWidth = 30
+		}
+		if panelWidth > 45 {
+			panelWidth = 45
+		}
+		contentWidth = m.width - panelWidth - 3
 	}
+	return contentWidth, panelWidth
+}
 
-	// Calculate layout dynamically based on available width
+// lightRowWidths splits a light row of the given width between the name
+// and the brightness bar
+func lightRowWidths(width int) (nameWidth, barWidth int) {
 	// Fixed parts: cursor(2) + icon(1) + space(1) + spaces(2) + space(1) + pct(4) + color(2) = 13
 	fixedParts := 13
 	availableForNameAndBar := width - fixedParts
 
 	// Split available space: ~60% for name, ~40% for bar
-	barWidth := availableForNameAndBar * 35 / 100
+	barWidth = availableForNameAndBar * 35 / 100
 	if barWidth < 8 {
 		barWidth = 8
 	}
@@ -820,39 +1478,95 @@ func (m MainModel) renderLightRow(light *models.Light, selected bool, width int)
 		barWidth = 20
 	}
 
-	nameWidth := availableForNameAndBar - barWidth
+	nameWidth = availableForNameAndBar - barWidth
 	if nameWidth < 10 {
 		nameWidth = 10
 	}
 	if nameWidth > 45 {
 		nameWidth = 45
 	}
+	return nameWidth, barWidth
+}
+
+func (m MainModel) renderLightRow(light *models.Light, selected bool, width int) string {
+	// Cursor - always same width character
+	cursor := styleMuted.Render("  ")
+	if selected {
+		cursor = styleSelected.Render("> ")
+	}
+	if m.marked[light.ID] {
+		if selected {
+			cursor = styleSelected.Render(">✓")
+		} else {
+			cursor = styleSelected.Render(" ✓")
+		}
+	}
+
+	// Status icon. Lights that don't respond are greyed out whatever their
+	// last known state.
+	faulty := light.Faulty()
+	onIcon, offIcon := "●", "○"
+	if light.OnOffOnly {
+		onIcon, offIcon = "⏻", "⏻"
+	}
+	icon := styleLightOff.Render(offIcon)
+	if faulty {
+		icon = styleLightFaulty.Render("⚠")
+	} else if light.On {
+		icon = styleLightOn.Render(onIcon)
+	}
+
+	// Calculate layout dynamically based on available width
+	nameWidth, barWidth := lightRowWidths(width)
 
 	// Name
 	nameStyle := styleLightNameDim
-	if light.On {
+	if light.On && !faulty {
 		nameStyle = styleLightName
 	}
 	if selected {
 		nameStyle = styleSelected
 	}
-	name := nameStyle.Render(truncate(light.Name, nameWidth))
+	// The selected name scrolls when it doesn't fit
+	name := nameStyle.Render(truncate(m.displayName(light), nameWidth))
+	if selected && m.marquee.lightID == light.ID {
+		name = nameStyle.Render(m.marquee.window(m.displayName(light), nameWidth))
+	}
 
 	// Brightness bar
-	bar := m.renderBrightnessBar(light.BrightnessPct(), light.On, barWidth)
+	bar := m.renderBrightnessBar(light.BrightnessPct(), light.On && !faulty, barWidth)
 
 	// Percentage
-	pct := styleBrightness.Render(fmt.Sprintf("%3d%%", light.BrightnessPct()))
+	pctStyle := styleBrightness
+	if faulty {
+		pctStyle = styleMuted
+	}
+	pct := pctStyle.Render(fmt.Sprintf("%4s", m.format.Brightness(light.Brightness)))
+
+	// On/off-only lights have no brightness to show
+	if light.OnOffOnly {
+		bar = strings.Repeat(" ", barWidth)
+		state := "off"
+		if light.On {
+			state = "on"
+		}
+		pct = pctStyle.Render(fmt.Sprintf("%4s", state))
+	}
 
 	// Color indicator
 	colorInd := ""
-	if light.Color != nil && light.On {
+	if light.Color != nil && light.On && !faulty {
 		r, g, bl := light.Color.RGB()
 		colorInd = lipgloss.NewStyle().
 			Foreground(lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", r, g, bl))).
 			Render(" ◆")
 	}
 
+	// The bridge never confirmed the last change, the row may be stale
+	if m.unconfirmed[light.ID] {
+		colorInd += styleMuted.Render(" ?")
+	}
+
 	return fmt.Sprintf("%s%s %s  %s %s%s", cursor, icon, name, bar, pct, colorInd)
 }
 
@@ -887,8 +1601,18 @@ func (m MainModel) renderPanel(panelWidth int) string {
 		return stylePanel.Width(panelWidth - 4).Render(m.spinner.View() + " Loading...")
 	}
 
-	// Check if room is selected
-	if m.IsRoomSelected() {
+	if m.zonePicker != nil {
+		return stylePanel.Width(panelWidth - 4).Render(m.renderZonePicker())
+	}
+	if m.lightMenu != nil {
+		return stylePanel.Width(panelWidth - 4).Render(m.renderLightMenu())
+	}
+	if m.tempPicker != nil {
+		return stylePanel.Width(panelWidth - 4).Render(m.renderTempPicker())
+	}
+
+	// Check if room is selected, or its light list focused
+	if m.IsRoomSelected() || m.panelRoom != nil {
 		return m.renderRoomPanel(panelWidth)
 	}
 
@@ -918,13 +1642,34 @@ func (m MainModel) renderPanel(panelWidth int) string {
 		status = styleLightOn.Render("● On")
 	}
 	content.WriteString(status)
+	switch {
+	case !light.Reachable:
+		content.WriteString("  " + styleLightFaulty.Render("⚠ Unreachable"))
+	case light.Faulty():
+		content.WriteString("  " + styleLightFaulty.Render("⚠ Not responding"))
+	}
 	content.WriteString("\n\n")
 
 	// Brightness
-	content.WriteString(styleMuted.Render("Brightness: "))
-	content.WriteString(fmt.Sprintf("%d%%\n", light.BrightnessPct()))
-	content.WriteString(m.renderBrightnessBar(light.BrightnessPct(), light.On, barWidth))
-	content.WriteString("\n\n")
+	if m.editingColor {
+		content.WriteString(m.renderColorInput())
+		content.WriteString("\n\n")
+	}
+	switch {
+	case light.OnOffOnly:
+		content.WriteString(styleMuted.Render("⏻ On/off only"))
+		content.WriteString("\n\n")
+	case m.editingBrightness:
+		content.WriteString(m.renderBrightnessInput())
+		content.WriteString("\n")
+		content.WriteString(m.renderBrightnessBar(light.BrightnessPct(), light.On, barWidth))
+		content.WriteString("\n\n")
+	default:
+		content.WriteString(styleMuted.Render("Brightness: "))
+		content.WriteString(m.format.Brightness(light.Brightness) + "\n")
+		content.WriteString(m.renderBrightnessBar(light.BrightnessPct(), light.On, barWidth))
+		content.WriteString("\n\n")
+	}
 
 	// Color mode display
 	if light.Color != nil {
@@ -961,14 +1706,14 @@ func (m MainModel) renderPanel(panelWidth int) string {
 			content.WriteString("Color (HS)\n\n")
 
 			// Hue (convert from 0-65535 to 0-360°)
-			hueDeg := int(float64(light.Color.Hue) / 65535.0 * 360.0)
+			hueDeg := models.HueToDeg(light.Color.Hue)
 			content.WriteString(styleMuted.Render("Hue: "))
 			content.WriteString(fmt.Sprintf("%d°\n", hueDeg))
 			content.WriteString(m.renderHueBar(hueDeg, barWidth))
 			content.WriteString("\n\n")
 
 			// Saturation (convert from 0-254 to 0-100%)
-			satPct := int(float64(light.Color.Saturation) / 254.0 * 100.0)
+			satPct := models.LevelToPct(light.Color.Saturation)
 			content.WriteString(styleMuted.Render("Saturation: "))
 			content.WriteString(fmt.Sprintf("%d%%\n", satPct))
 			content.WriteString(m.renderSatBar(satPct, hueDeg, barWidth))
@@ -1002,6 +1747,30 @@ func (m MainModel) renderPanel(panelWidth int) string {
 			content.WriteString(styleMuted.Render("Color: "))
 			content.WriteString(colorBox)
 		}
+
+		if light.SupportsColor {
+			content.WriteString("\n\n")
+			content.WriteString(m.renderColorWheel(light))
+		}
+	}
+
+	// Gamut, and whether the color asked for is beyond it
+	if light.Gamut != nil {
+		content.WriteString("\n\n")
+		content.WriteString(styleMuted.Render("Gamut: "))
+		content.WriteString(light.Gamut.Label())
+		if outsideGamut(light) {
+			content.WriteString("\n")
+			content.WriteString(lipgloss.NewStyle().Foreground(colorWarning).Render("⚠ Color beyond the gamut, approximated"))
+		}
+	}
+
+	// Gradient strips show every point, blended along the light
+	if g := light.Gradient; g != nil && len(g.Points) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(styleMuted.Render("Gradient: "))
+		content.WriteString(fmt.Sprintf("%d/%d points\n", len(g.Points), g.PointsCapable))
+		content.WriteString(m.renderGradientBar(g, barWidth))
 	}
 
 	// Room
@@ -1011,6 +1780,52 @@ func (m MainModel) renderPanel(panelWidth int) string {
 		content.WriteString(room.Name)
 	}
 
+	// Archetype, or its picker
+	if m.archetype != nil && m.archetype.lightID == light.ID {
+		content.WriteString("\n\n")
+		content.WriteString(m.renderArchetypePicker())
+		content.WriteString("\n")
+	} else if light.Archetype != "" {
+		content.WriteString("\n")
+		content.WriteString(styleMuted.Render("Type: "))
+		content.WriteString(models.ArchetypeLabel(light.Archetype))
+	}
+
+	// Local role from the config
+	if role, ok := m.roles[light.ID]; ok {
+		content.WriteString("\n")
+		content.WriteString(styleMuted.Render("Role: "))
+		content.WriteString(string(role))
+	}
+
+	// Owning device and other lights on the same device
+	if light.DeviceName != "" {
+		content.WriteString("\n")
+		content.WriteString(styleMuted.Render("Device: "))
+		content.WriteString(light.DeviceName)
+	}
+	if linked := m.linkedLights(light); len(linked) > 0 {
+		names := make([]string, len(linked))
+		for i, other := range linked {
+			names[i] = other.Name
+		}
+		content.WriteString("\n")
+		content.WriteString(styleMuted.Render("Linked: "))
+		content.WriteString(strings.Join(names, ", "))
+	}
+	if siblings := m.siblingLights(light); len(siblings) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(styleMuted.Render("Same device:\n"))
+		for _, sibling := range siblings {
+			icon := styleLightOff.Render("○")
+			if sibling.On {
+				icon = styleLightOn.Render("●")
+			}
+			content.WriteString(fmt.Sprintf("  %s %s\n", icon, sibling.Name))
+		}
+		content.WriteString(styleMuted.Render("n next on device"))
+	}
+
 	// Use panel width minus border padding
 	return stylePanel.Width(panelWidth - 4).Render(content.String())
 }
@@ -1038,11 +1853,9 @@ func (m MainModel) renderRoomPanel(panelWidth int) string {
 
 	// Status
 	lightsOn := 0
-	totalBrightness := 0
 	for _, light := range room.Lights {
 		if light.On {
 			lightsOn++
-			totalBrightness += light.BrightnessPct()
 		}
 	}
 
@@ -1053,13 +1866,26 @@ func (m MainModel) renderRoomPanel(panelWidth int) string {
 	} else {
 		content.WriteString(styleLightOn.Render(fmt.Sprintf("● %d/%d On", lightsOn, len(room.Lights))))
 	}
+	if faulty := len(room.FaultyLights()); faulty > 0 {
+		content.WriteString("  ")
+		content.WriteString(styleLightFaulty.Render(fmt.Sprintf("⚠ %d not responding", faulty)))
+	}
 	content.WriteString("\n\n")
 
-	// Average brightness
-	if lightsOn > 0 {
-		avgBrightness := totalBrightness / lightsOn
+	if m.editingColor {
+		content.WriteString(m.renderColorInput())
+		content.WriteString("\n\n")
+	}
+
+	// Average brightness, leaving out faulty lights
+	if m.editingBrightness {
+		content.WriteString(m.renderBrightnessInput())
+		content.WriteString("\n")
+		content.WriteString(m.renderBrightnessBar(room.AverageBrightness(), room.AnyOn, barWidth))
+		content.WriteString("\n\n")
+	} else if avgBrightness := room.AverageBrightness(); avgBrightness > 0 {
 		content.WriteString(styleMuted.Render("Avg Brightness: "))
-		content.WriteString(fmt.Sprintf("%d%%\n", avgBrightness))
+		content.WriteString(m.format.Brightness(models.PctToLevel(float64(avgBrightness))) + "\n")
 		content.WriteString(m.renderBrightnessBar(avgBrightness, true, barWidth))
 		content.WriteString("\n\n")
 	} else {
@@ -1069,32 +1895,76 @@ func (m MainModel) renderRoomPanel(panelWidth int) string {
 		content.WriteString("\n\n")
 	}
 
-	// Lights list - scale max items with height
+	if spread := renderBrightnessSpread(room); spread != "" {
+		content.WriteString(spread)
+		content.WriteString("\n\n")
+	}
+
+	// Lights list, scrolled through once focused
 	content.WriteString(styleMuted.Render("Lights:\n"))
-	maxLights := 8
 	maxNameLen := panelWidth - 8
 	if maxNameLen < 12 {
 		maxNameLen = 12
 	}
-	for i, light := range room.Lights {
-		if i >= maxLights {
-			content.WriteString(fmt.Sprintf("  ... +%d more\n", len(room.Lights)-maxLights))
-			break
-		}
+	lights := panelLights(room)
+	selected := m.panelIndex(lights)
+	start := 0
+	if m.panelRoom == room {
+		start = m.panelScroll
+	}
+	end := min(len(lights), start+roomPanelLights)
+	if start > 0 {
+		content.WriteString(styleMuted.Render(fmt.Sprintf("  ↑ %d more\n", start)))
+	}
+	for i := start; i < end; i++ {
+		light := lights[i]
 		icon := styleLightOff.Render("○")
-		if light.On {
+		if light.Faulty() {
+			icon = styleLightFaulty.Render("⚠")
+		} else if light.On {
 			icon = styleLightOn.Render("●")
 		}
 		name := light.Name
 		if len(name) > maxNameLen {
 			name = name[:maxNameLen-1] + "…"
 		}
-		content.WriteString(fmt.Sprintf("  %s %s\n", icon, name))
+		cursor := "  "
+		if i == selected {
+			cursor = styleSelected.Render("> ")
+			name = styleSelected.Render(name)
+		}
+		content.WriteString(fmt.Sprintf("%s%s %s\n", cursor, icon, name))
+	}
+	if more := len(lights) - end; more > 0 {
+		hint := ""
+		if m.panelRoom != room {
+			hint = " (S-tab to browse)"
+		}
+		content.WriteString(styleMuted.Render(fmt.Sprintf("  ↓ %d more%s\n", more, hint)))
+	}
+
+	// Role lights, stepped with one key per role
+	var roleHints []string
+	for _, role := range models.LightRoles {
+		if n := len(m.roleLights(room, role)); n > 0 {
+			roleHints = append(roleHints, fmt.Sprintf("%s %s (%d)", roleKey(role), role, n))
+		}
+	}
+	if len(roleHints) > 0 {
+		content.WriteString("\n")
+		content.WriteString(styleMuted.Render("Roles:\n"))
+		for _, hint := range roleHints {
+			content.WriteString("  " + hint + "\n")
+		}
 	}
 
 	// Controls hint
 	content.WriteString("\n")
-	content.WriteString(styleMuted.Render("←→ dim • space toggle"))
+	if m.panelRoom == room {
+		content.WriteString(styleMuted.Render("↑↓ lights • esc back"))
+	} else {
+		content.WriteString(styleMuted.Render("←→ dim • space toggle"))
+	}
 
 	return stylePanel.Width(panelWidth - 4).Render(content.String())
 }
@@ -1127,6 +1997,20 @@ func (m MainModel) renderTempBar(mirek int, width int) string {
 	return bar.String()
 }
 
+// renderGradientBar renders the colors of a gradient light along a bar
+func (m MainModel) renderGradientBar(g *models.Gradient, width int) string {
+	var bar strings.Builder
+	for i := 0; i < width; i++ {
+		t := 0.0
+		if width > 1 {
+			t = float64(i) / float64(width-1)
+		}
+		r, gr, b := g.ColorAt(t)
+		bar.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", r, gr, b))).Render("█"))
+	}
+	return bar.String()
+}
+
 func (m MainModel) renderHueBar(hueDeg int, width int) string {
 	pos := hueDeg * width / 360
 	if pos >= width {
@@ -1208,6 +2092,19 @@ func mod(a, b float64) float64 {
 	return a - b*float64(int(a/b))
 }
 
+// contrastText returns black or white, whichever reads better on a background
+func contrastText(bg lipgloss.Color) lipgloss.Color {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(string(bg), "#%02X%02X%02X", &r, &g, &b); err != nil {
+		return lipgloss.Color("#FFFFFF")
+	}
+	// Perceived luminance (ITU-R BT.601)
+	if 0.299*float64(r)+0.587*float64(g)+0.114*float64(b) > 150 {
+		return lipgloss.Color("#000000")
+	}
+	return lipgloss.Color("#FFFFFF")
+}
+
 // getColorPreview returns RGB values for displaying a color preview at full brightness
 func getColorPreview(c *models.Color) (r, g, b uint8) {
 	switch c.Mode {
@@ -1419,119 +2316,45 @@ func (m MainModel) renderStatusBar() string {
 		status += fmt.Sprintf(" • %d/%d rooms active", roomsActive, totalRooms)
 	}
 
+	if m.calibration != nil {
+		return m.renderCalibration()
+	}
+	if m.notice != "" {
+		return styleSearch.Render(m.notice)
+	}
+	if m.chord != "" {
+		return styleSearch.Render(m.chord+"-") + "  " + chordHint() + styleMuted.Render("  (esc to cancel)")
+	}
 	return styleMuted.Render(status)
 }
 
 func (m MainModel) renderHelp() string {
-	keys := []string{
-		styleHelpKey.Render("↑↓") + " nav",
-		styleHelpKey.Render("pgup/dn") + " scroll",
-		styleHelpKey.Render("←→") + " dim",
-		styleHelpKey.Render("space") + " toggle",
-		styleHelpKey.Render("w/c") + " temp",
-		styleHelpKey.Render("[]") + " hue",
-		styleHelpKey.Render("-/=") + " sat",
-		styleHelpKey.Render("a/x") + " room",
-		styleHelpKey.Render("s") + " scenes",
-		styleHelpKey.Render("q") + " quit",
-	}
-
 	// For narrow terminals, show fewer keys
+	tier := tierWide
 	if m.width < 60 {
-		keys = []string{
-			styleHelpKey.Render("↑↓") + " nav",
-			styleHelpKey.Render("space") + " toggle",
-			styleHelpKey.Render("q") + " quit",
-		}
+		tier = tierNarrow
 	} else if m.width < 90 {
-		keys = []string{
-			styleHelpKey.Render("↑↓") + " nav",
-			styleHelpKey.Render("←→") + " dim",
-			styleHelpKey.Render("space") + " toggle",
-			styleHelpKey.Render("s") + " scenes",
-			styleHelpKey.Render("q") + " quit",
-		}
-	}
-
-	return styleHelp.Render(strings.Join(keys, "  "))
-}
-
-// Commands
-func (m MainModel) toggleLightCmd(bridge api.BridgeClient, lightID string, on bool) tea.Cmd {
-	return func() tea.Msg {
-		if bridge == nil {
-			return nil
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := bridge.SetLightOn(ctx, lightID, on); err != nil {
-			return messages.ErrorMsg{Err: err}
-		}
-		return nil
+		tier = tierMedium
 	}
-}
 
-func (m MainModel) setBrightnessCmd(bridge api.BridgeClient, lightID string, brightness int) tea.Cmd {
-	return func() tea.Msg {
-		if bridge == nil {
-			return nil
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := bridge.SetLightBrightness(ctx, lightID, brightness); err != nil {
-			return messages.ErrorMsg{Err: err}
+	var keys []string
+	for _, kb := range keymap {
+		if kb.tier <= tier {
+			keys = append(keys, styleHelpKey.Render(kb.key)+" "+m.bindingHelp(kb))
 		}
-		return nil
-	}
-}
-
-func (m MainModel) setColorTempCmd(bridge api.BridgeClient, lightID string, mirek int) tea.Cmd {
-	return func() tea.Msg {
-		if bridge == nil {
-			return nil
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := bridge.SetLightColorTemp(ctx, lightID, mirek); err != nil {
-			return messages.ErrorMsg{Err: err}
-		}
-		return nil
-	}
-}
-
-func (m MainModel) setColorHSCmd(bridge api.BridgeClient, lightID string, hue uint16, sat uint8) tea.Cmd {
-	return func() tea.Msg {
-		if bridge == nil {
-			return nil
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := bridge.SetLightColorHS(ctx, lightID, hue, sat); err != nil {
-			return messages.ErrorMsg{Err: err}
-		}
-		return nil
-	}
-}
-
-func (m MainModel) setGroupOnCmd(bridge api.BridgeClient, groupID string, on bool) tea.Cmd {
-	return func() tea.Msg {
-		if bridge == nil {
-			return nil
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := bridge.SetGroupedLightOn(ctx, groupID, on); err != nil {
-			return messages.ErrorMsg{Err: err}
-		}
-		return nil
 	}
+	return styleHelp.Render(strings.Join(keys, "  "))
 }
 
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s + strings.Repeat(" ", maxLen-len(s))
+	if maxLen <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s + strings.Repeat(" ", maxLen-len(runes))
 	}
-	return s[:maxLen-1] + "…"
+	return string(runes[:maxLen-1]) + "…"
 }
 
 func max(a, b int) int {
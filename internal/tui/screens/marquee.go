@@ -0,0 +1,99 @@
+package screens
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// marqueeInterval is how often a long name scrolls by one character
+	marqueeInterval = 300 * time.Millisecond
+	// marqueePause is the number of steps the name rests at each end
+	marqueePause = 5
+)
+
+// marquee scrolls the name of the selected light back and forth when it
+// is too long for the name column
+type marquee struct {
+	lightID string
+	step    int
+	// Generation of the running tick chain (0 when stopped), so that the
+	// ticks of a previous selection are dropped
+	gen      int
+	lastTick time.Time
+}
+
+// marqueeTickMsg advances the marquee of generation gen
+type marqueeTickMsg struct {
+	gen int
+}
+
+func marqueeTick(gen int) tea.Cmd {
+	return tea.Tick(marqueeInterval, func(time.Time) tea.Msg { return marqueeTickMsg{gen: gen} })
+}
+
+// window returns the part of name shown at the current step: the start
+// with an ellipsis, then sliding to the end, resting at both ends
+func (q marquee) window(name string, width int) string {
+	runes := []rune(name)
+	overflow := len(runes) - width
+	if overflow <= 0 {
+		return truncate(name, width)
+	}
+	pos := q.step%(overflow+2*marqueePause) - marqueePause
+	pos = max(0, min(pos, overflow))
+	if pos == 0 {
+		return truncate(name, width)
+	}
+	return string(runes[pos : pos+width])
+}
+
+// nameOverflows reports whether the selected light's name is too long for
+// the name column
+func (m *MainModel) nameOverflows() bool {
+	light := m.SelectedLight()
+	if light == nil || m.IsRoomSelected() {
+		return false
+	}
+	contentWidth, _ := m.layoutWidths()
+	nameWidth, _ := lightRowWidths(contentWidth)
+	return len([]rune(m.displayName(light))) > nameWidth
+}
+
+// startMarquee starts scrolling the selected light's name when it
+// overflows. A chain whose ticks were lost, while another screen was
+// shown, is restarted.
+func (m *MainModel) startMarquee() tea.Cmd {
+	if !m.nameOverflows() {
+		m.marquee = marquee{}
+		return nil
+	}
+	light := m.SelectedLight()
+	stale := time.Since(m.marquee.lastTick) > 3*marqueeInterval
+	if m.marquee.lightID == light.ID && m.marquee.gen != 0 && !stale {
+		return nil
+	}
+	m.marqueeGen++
+	m.marquee = marquee{lightID: light.ID, gen: m.marqueeGen, lastTick: time.Now()}
+	return marqueeTick(m.marqueeGen)
+}
+
+// advanceMarquee scrolls the name one step, and stops once the selection
+// no longer overflows or the app is idle
+func (m *MainModel) advanceMarquee(msg marqueeTickMsg) tea.Cmd {
+	if msg.gen != m.marquee.gen {
+		return nil
+	}
+	if m.idle {
+		m.marquee.gen = 0
+		return nil
+	}
+	if light := m.SelectedLight(); light == nil || light.ID != m.marquee.lightID || !m.nameOverflows() {
+		m.marquee = marquee{}
+		return nil
+	}
+	m.marquee.step++
+	m.marquee.lastTick = time.Now()
+	return marqueeTick(msg.gen)
+}
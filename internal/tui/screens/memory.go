@@ -0,0 +1,116 @@
+package screens
+
+import (
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SetRememberColors sets whether lights switched on with space come back
+// at the brightness and color they had when turned off
+func (m *MainModel) SetRememberColors(remember bool) {
+	m.rememberColors = remember
+}
+
+// SetLightMemory sets the remembered brightness and color of the lights,
+// by light ID
+func (m *MainModel) SetLightMemory(memory map[string]models.LightMemory) {
+	m.lightMemory = memory
+}
+
+// remembers returns true if any of the lights has a remembered state
+func (m *MainModel) remembers(lights []*models.Light) bool {
+	if !m.rememberColors {
+		return false
+	}
+	for _, light := range lights {
+		if _, ok := m.lightMemory[light.ID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rememberLights returns the command saving the state of the lit lights
+// about to be turned off, or nil when there is nothing to remember
+func (m *MainModel) rememberLights(lights []*models.Light) tea.Cmd {
+	if !m.rememberColors {
+		return nil
+	}
+	memory := make(map[string]models.LightMemory)
+	for _, light := range lights {
+		if light.On {
+			memory[light.ID] = lightMemory(light)
+		}
+	}
+	if len(memory) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return messages.LightMemoryMsg{Lights: memory}
+	}
+}
+
+// lightMemory returns the brightness and color a light shows
+func lightMemory(light *models.Light) models.LightMemory {
+	mem := models.LightMemory{Brightness: light.BrightnessPct()}
+	if c := light.Color; c != nil {
+		switch c.Mode {
+		case models.ColorModeColorTemp:
+			mem.Mirek = int(c.Mirek)
+		case models.ColorModeXY:
+			mem.X, mem.Y = c.X, c.Y
+		case models.ColorModeHS:
+			mem.X, mem.Y = api.HSToXY(c.Hue, c.Saturation)
+		}
+	}
+	return mem
+}
+
+// turnOn switches a light on, at its remembered brightness and color when
+// there is one
+func (m *MainModel) turnOn(light *models.Light, pending pendingFuncs) lightCalls {
+	mem, ok := m.lightMemory[light.ID]
+	if !m.rememberColors || !ok {
+		return setLightOn(light, true, pending)
+	}
+	return recallLight(light, mem, pending)
+}
+
+// recallLight turns a light on and gives it back a remembered state,
+// leaving out what it can't show
+func recallLight(light *models.Light, mem models.LightMemory, pending pendingFuncs) lightCalls {
+	if !light.On {
+		return turnOnWith(light, pending, func(pending pendingFuncs) lightCalls {
+			return recallState(light, mem, pending)
+		})
+	}
+	return append(setLightOn(light, true, pending), recallState(light, mem, pending)...)
+}
+
+// recallState gives a lit light back the brightness and color of mem
+func recallState(light *models.Light, mem models.LightMemory, pending pendingFuncs) lightCalls {
+	var calls lightCalls
+	if mem.Brightness > 0 && !light.OnOffOnly && mem.Brightness != light.BrightnessPct() {
+		light.SetBrightnessPct(mem.Brightness)
+		pending.addOp(light.ID, "brightness", mem.Brightness, DirExact)
+		calls = append(calls, callSetBrightness(light.ID, mem.Brightness))
+	}
+
+	c := light.Color
+	switch {
+	case c == nil:
+		return calls
+	case mem.Mirek > 0 && light.SupportsColorTemp:
+		c.Mirek = uint16(light.ClampMirek(mem.Mirek))
+		c.Mode = models.ColorModeColorTemp
+	case mem.Mirek == 0 && (mem.X > 0 || mem.Y > 0) && light.SupportsColor:
+		c.X, c.Y = mem.X, mem.Y
+		c.Mode = models.ColorModeXY
+	default:
+		return calls
+	}
+	c.InvalidateCache()
+	return append(calls, restoreColor(light, pending)...)
+}
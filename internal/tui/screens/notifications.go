@@ -0,0 +1,117 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/tui/components"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NotificationsModel is the screen reviewing past toasts, newest first
+type NotificationsModel struct {
+	toasts []components.Toast
+	scroll int
+	format locale.Format
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewNotificationsModel creates a new notifications screen model
+func NewNotificationsModel() NotificationsModel {
+	return NotificationsModel{}
+}
+
+// SetSize sets the terminal size
+func (m *NotificationsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetLocale sets how the times are shown
+func (m *NotificationsModel) SetLocale(format locale.Format) {
+	m.format = format
+}
+
+// SetToasts sets the past toasts, oldest first, and scrolls to the newest
+func (m *NotificationsModel) SetToasts(toasts []components.Toast) {
+	m.toasts = toasts
+	m.scroll = 0
+}
+
+// visibleRows is the number of toasts shown at once
+func (m NotificationsModel) visibleRows() int {
+	return max(3, m.height-10)
+}
+
+// Update handles messages
+func (m NotificationsModel) Update(msg tea.Msg) (NotificationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "E", "q":
+			return m, func() tea.Msg { return messages.HideNotificationsMsg{} }
+
+		case "up", "k":
+			if m.scroll > 0 {
+				m.scroll--
+			}
+
+		case "down", "j":
+			if m.scroll < len(m.toasts)-m.visibleRows() {
+				m.scroll++
+			}
+
+		case "c":
+			m.toasts = nil
+			m.scroll = 0
+			return m, func() tea.Msg { return messages.ClearNotificationsMsg{} }
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the notifications screen
+func (m NotificationsModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleModalTitle.Render("Notifications"))
+	b.WriteString("\n\n")
+
+	modalWidth := m.width * 80 / 100
+	if modalWidth < 44 {
+		modalWidth = 44
+	}
+	if modalWidth > 100 {
+		modalWidth = 100
+	}
+
+	if len(m.toasts) == 0 {
+		b.WriteString(styles.StyleTextMuted.Render("Nothing to report"))
+		b.WriteString("\n")
+	}
+	end := min(len(m.toasts), m.scroll+m.visibleRows())
+	for i := m.scroll; i < end; i++ {
+		toast := m.toasts[len(m.toasts)-1-i]
+		at := m.format.Time(toast.At)
+		line := styles.StyleTextMuted.Render(at) + " " + components.ToastIcon(toast.Severity) + " " + toast.Message
+		b.WriteString(lipgloss.NewStyle().MaxWidth(modalWidth - 4).Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	help := "c clear • esc close"
+	if len(m.toasts) > m.visibleRows() {
+		help = "↑/↓ scroll • " + help
+	}
+	b.WriteString(styles.StyleHelp.Render(help))
+
+	modal := styles.StyleModal.Width(modalWidth).Render(b.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
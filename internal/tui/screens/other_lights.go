@@ -0,0 +1,134 @@
+package screens
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// Ways to list the lights without a room
+const (
+	// OtherGroupingNone lists them in a single "Other Lights" room
+	OtherGroupingNone = ""
+	// OtherGroupingDevice splits them by owning device
+	OtherGroupingDevice = "device"
+	// OtherGroupingArchetype splits them by kind: plugs, strips, bulbs...
+	OtherGroupingArchetype = "archetype"
+)
+
+// SetOtherGrouping sets how the lights without a room are listed, one of
+// the OtherGrouping values
+func (m *MainModel) SetOtherGrouping(grouping string) {
+	if grouping != OtherGroupingDevice && grouping != OtherGroupingArchetype {
+		grouping = OtherGroupingNone
+	}
+	m.otherGrouping = grouping
+	m.rebuildLightList()
+}
+
+// listedRooms returns the rooms as listed, with the lights without a room
+// split into one room per device or kind when grouping is set. The split
+// rooms have no grouped light, like the room they come from.
+func (m *MainModel) listedRooms() []*models.Room {
+	if m.byType {
+		return m.typeRooms()
+	}
+	if m.otherGrouping == OtherGroupingNone {
+		return m.rooms
+	}
+
+	var rooms []*models.Room
+	for _, room := range m.rooms {
+		if room.ID != api.OtherRoomID {
+			rooms = append(rooms, room)
+			continue
+		}
+
+		groups := make(map[string]*models.Room)
+		for _, light := range room.Lights {
+			key, name := otherGroup(light, m.otherGrouping)
+			group, ok := groups[key]
+			if !ok {
+				group = &models.Room{ID: api.OtherRoomID + ":" + key, Name: room.Name + " · " + name}
+				groups[key] = group
+			}
+			group.Lights = append(group.Lights, light)
+		}
+		split := make([]*models.Room, 0, len(groups))
+		for _, group := range groups {
+			group.UpdateState()
+			split = append(split, group)
+		}
+		sort.Slice(split, func(i, j int) bool { return split[i].Name < split[j].Name })
+		rooms = append(rooms, split...)
+	}
+	return rooms
+}
+
+// otherGroup returns the key and name of the group of a light without a
+// room
+func otherGroup(light *models.Light, grouping string) (string, string) {
+	if grouping == OtherGroupingArchetype {
+		kind := models.ArchetypeKind(light.Archetype)
+		return kind, kind
+	}
+	switch {
+	case light.DeviceName != "":
+		return light.DeviceID, light.DeviceName
+	case light.DeviceID != "":
+		return light.DeviceID, light.Name
+	}
+	return "", "Unknown device"
+}
+
+// typeRoomPrefix starts the IDs of the rooms listed by light type
+const typeRoomPrefix = "type:"
+
+// isTypeRoom reports whether room is a group of the lights of one type,
+// which has no scenes nor live updates of its own
+func isTypeRoom(room *models.Room) bool {
+	return strings.HasPrefix(room.ID, typeRoomPrefix)
+}
+
+// toggleByType switches the list between rooms and light types, keeping
+// the selected light selected
+func (m *MainModel) toggleByType() {
+	selected := m.SelectedLight()
+	m.byType = !m.byType
+	m.rebuildLightList()
+	if selected != nil {
+		m.selectLight(selected.ID)
+	}
+	if m.byType {
+		m.notice = "Lights grouped by type"
+	} else {
+		m.notice = "Lights grouped by room"
+	}
+}
+
+// typeRooms groups every light by its kind, as set by its archetype:
+// bulbs, strips, plugs, portable lamps... Like the split other lights,
+// the groups have no grouped light, so they're controlled light by light.
+func (m *MainModel) typeRooms() []*models.Room {
+	groups := make(map[string]*models.Room)
+	for _, room := range m.rooms {
+		for _, light := range room.Lights {
+			kind := models.ArchetypeKind(light.Archetype)
+			group, ok := groups[kind]
+			if !ok {
+				group = &models.Room{ID: typeRoomPrefix + kind, Name: kind}
+				groups[kind] = group
+			}
+			group.Lights = append(group.Lights, light)
+		}
+	}
+	rooms := make([]*models.Room, 0, len(groups))
+	for _, group := range groups {
+		group.UpdateState()
+		rooms = append(rooms, group)
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].Name < rooms[j].Name })
+	return rooms
+}
@@ -0,0 +1,147 @@
+package screens
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteMaxRows is the number of commands the palette lists at once
+const paletteMaxRows = 10
+
+// commandPalette runs a command picked by typing part of its name, opened
+// with ctrl+p
+type commandPalette struct {
+	query    string
+	selected int
+}
+
+// paletteCommand is an entry of the command palette
+type paletteCommand struct {
+	label string
+	run   func(m *MainModel) tea.Cmd
+}
+
+// paletteCommands lists every scene as its own entry, with its room, so
+// scenes sharing a name in different rooms can be told apart, then the
+// go-to chords
+func (m MainModel) paletteCommands() []paletteCommand {
+	roomNames := make(map[string]string, len(m.rooms))
+	for _, room := range m.rooms {
+		roomNames[room.ID] = room.Name
+	}
+
+	scenes := slices.Clone(m.scenes)
+	slices.SortStableFunc(scenes, func(a, b *models.Scene) int {
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		return strings.Compare(roomNames[a.RoomID], roomNames[b.RoomID])
+	})
+
+	var commands []paletteCommand
+	for _, scene := range scenes {
+		label := scene.Name
+		room := roomNames[scene.RoomID]
+		if room == "" {
+			room = scene.RoomName
+		}
+		if room != "" {
+			label += " — " + room
+		}
+		commands = append(commands, paletteCommand{label: label, run: func(m *MainModel) tea.Cmd {
+			m.notice = "Scene " + label + " activated"
+			return func() tea.Msg { return messages.SceneActivatedMsg{SceneID: scene.ID} }
+		}})
+	}
+	for _, c := range chords {
+		commands = append(commands, paletteCommand{label: "Go to " + c.desc, run: c.run})
+	}
+	return commands
+}
+
+// paletteMatches returns the commands matching the typed query
+func (m MainModel) paletteMatches() []paletteCommand {
+	var matches []paletteCommand
+	for _, c := range m.paletteCommands() {
+		if fuzzyMatch(c.label, m.palette.query) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// updatePalette filters the commands as keys are typed, and runs the
+// selected one on enter
+func (m *MainModel) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "ctrl+p":
+		m.palette = nil
+	case "up", "ctrl+k":
+		m.palette.selected = max(0, m.palette.selected-1)
+	case "down", "ctrl+j":
+		m.palette.selected = min(m.palette.selected+1, max(0, len(m.paletteMatches())-1))
+	case "enter":
+		matches := m.paletteMatches()
+		if m.palette.selected >= len(matches) {
+			return nil
+		}
+		command := matches[m.palette.selected]
+		m.palette = nil
+		return command.run(m)
+	case "backspace":
+		if m.palette.query != "" {
+			runes := []rune(m.palette.query)
+			m.palette.query = string(runes[:len(runes)-1])
+			m.palette.selected = 0
+		}
+	case "ctrl+u":
+		m.palette.query, m.palette.selected = "", 0
+	case "ctrl+c":
+		return tea.Quit
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.palette.query += string(msg.Runes)
+			m.palette.selected = 0
+		}
+	}
+	return nil
+}
+
+// renderPalette renders the palette centered over the screen, scrolling
+// the matches to keep the selected one shown
+func (m MainModel) renderPalette() string {
+	modalWidth := max(40, min(60, m.width*70/100))
+
+	var b strings.Builder
+	b.WriteString(styles.StyleModalTitle.Render("Commands"))
+	b.WriteString("\n")
+	b.WriteString(styles.StyleSearchBarFocused.Width(modalWidth - 6).Render("> " + m.palette.query + "█"))
+	b.WriteString("\n\n")
+
+	matches := m.paletteMatches()
+	start := max(0, m.palette.selected-paletteMaxRows+1)
+	end := min(len(matches), start+paletteMaxRows)
+	for i := start; i < end; i++ {
+		if i == m.palette.selected {
+			b.WriteString("> " + styles.StyleSceneItemSelected.Render(matches[i].label) + "\n")
+		} else {
+			b.WriteString("  " + styles.StyleSceneItem.Render(matches[i].label) + "\n")
+		}
+	}
+	if len(matches) == 0 {
+		b.WriteString(styles.StyleTextMuted.Render("No commands match \"" + m.palette.query + "\""))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.StyleHelp.Render("type to filter • ↑/↓ navigate • enter run • esc close"))
+
+	modal := styles.StyleModal.Width(modalWidth).Render(b.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
@@ -0,0 +1,118 @@
+package screens
+
+import (
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Ways room dimming reaches the bridge
+const (
+	// RoomDimmingLive steps every lit light of the room on each key press
+	RoomDimmingLive = ""
+	// RoomDimmingCommit shows the target in the room header, and sets the
+	// room to it with one grouped command once the keys stop, or on enter
+	RoomDimmingCommit = "commit"
+)
+
+// roomDimQuiet is how long after the last key a previewed room brightness
+// is sent
+const roomDimQuiet = 700 * time.Millisecond
+
+// roomDim is a room brightness previewed in commit mode, not sent yet
+type roomDim struct {
+	groupID string
+	// Name of the room, for errors
+	name string
+	// Lit lights of the room when the preview started
+	lights []*models.Light
+	start  int
+	target int
+	// Bumped by every key, so that only the last one sends it
+	seq int
+}
+
+// roomDimCommitMsg sends a previewed room brightness after the quiet period
+type roomDimCommitMsg struct {
+	roomID string
+	seq    int
+}
+
+// SetRoomDimming sets how room dimming reaches the bridge, one of the
+// RoomDimming values
+func (m *MainModel) SetRoomDimming(mode string) {
+	if mode != RoomDimmingCommit {
+		mode = RoomDimmingLive
+	}
+	m.roomDimming = mode
+}
+
+// previewRoomDim moves the previewed brightness of room by step percent,
+// and sends it once the keys stop
+func (m *MainModel) previewRoomDim(room *models.Room, step, floor int) tea.Cmd {
+	dim, ok := m.roomDims[room.ID]
+	if !ok {
+		var lights []*models.Light
+		for _, light := range room.Lights {
+			if light.On && !light.OnOffOnly {
+				lights = append(lights, light)
+			}
+		}
+		if len(lights) == 0 {
+			return nil
+		}
+		current := room.AverageBrightness()
+		dim = &roomDim{groupID: room.GroupedLightID, name: room.Name, lights: lights, start: current, target: current}
+		m.roomDims[room.ID] = dim
+	}
+	dim.target = min(100, max(floor, dim.target+step))
+	m.roomDimSeq++
+	dim.seq = m.roomDimSeq
+
+	roomID, seq := room.ID, dim.seq
+	return tea.Tick(roomDimQuiet, func(time.Time) tea.Msg {
+		return roomDimCommitMsg{roomID: roomID, seq: seq}
+	})
+}
+
+// commitRoomDim sets the lights of a room to its previewed brightness with
+// one grouped command
+func (m *MainModel) commitRoomDim(bridge api.BridgeClient, roomID string, pending pendingFuncs) tea.Cmd {
+	dim, ok := m.roomDims[roomID]
+	if !ok {
+		return nil
+	}
+	delete(m.roomDims, roomID)
+	if dim.target == dim.start {
+		return nil
+	}
+
+	dir := DirUp
+	if dim.target < dim.start {
+		dir = DirDown
+	}
+	batches := make([]lightBatch, 0, len(dim.lights))
+	for _, light := range dim.lights {
+		before := light.Clone()
+		light.SetBrightnessPct(dim.target)
+		pending.addOp(light.ID, "brightness", dim.target, dir)
+		batches = append(batches, lightBatch{lightID: light.ID, before: before, after: light.Clone()})
+	}
+	return runGroupCall(bridge, dim.name, callSetGroupBrightness(dim.groupID, dim.target), batches)
+}
+
+// selectedRoomDim returns the ID of the selected room when its brightness
+// is being previewed
+func (m *MainModel) selectedRoomDim() (string, bool) {
+	if !m.IsRoomSelected() {
+		return "", false
+	}
+	room := m.SelectedRoom()
+	if room == nil {
+		return "", false
+	}
+	_, ok := m.roomDims[room.ID]
+	return room.ID, ok
+}
@@ -0,0 +1,137 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// roomPanelLights is how many lights the room panel lists at once
+const roomPanelLights = 8
+
+// panelLights returns the lights of a room in main list order
+func panelLights(room *models.Room) []*models.Light {
+	lights := append([]*models.Light{}, room.Lights...)
+	sort.Slice(lights, func(i, j int) bool {
+		return lights[i].Name < lights[j].Name
+	})
+	return lights
+}
+
+// focusRoomPanel moves the keyboard focus to the light list of the selected
+// room in the side panel
+func (m *MainModel) focusRoomPanel() {
+	room := m.SelectedRoom()
+	if room == nil || !m.showPanel || len(room.Lights) == 0 {
+		return
+	}
+	m.panelRoom = room
+	m.panelScroll = 0
+	if m.IsRoomSelected() {
+		m.movePanelSelection(1)
+		return
+	}
+	m.ensurePanelVisible()
+}
+
+// movePanelSelection selects the next or previous light of the focused room,
+// skipping lights hidden by the search
+func (m *MainModel) movePanelSelection(delta int) {
+	lights := panelLights(m.panelRoom)
+	i := m.panelIndex(lights)
+	for i += delta; i >= 0 && i < len(lights); i += delta {
+		if m.selectLight(lights[i].ID) {
+			break
+		}
+	}
+	m.ensurePanelVisible()
+}
+
+// panelIndex returns the position of the selected light in the panel list,
+// or -1 when the room itself is selected
+func (m *MainModel) panelIndex(lights []*models.Light) int {
+	if light := m.SelectedLight(); light != nil {
+		for i, l := range lights {
+			if l.ID == light.ID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// ensurePanelVisible scrolls the panel list to the selected light
+func (m *MainModel) ensurePanelVisible() {
+	i := m.panelIndex(panelLights(m.panelRoom))
+	if i < 0 {
+		return
+	}
+	if i < m.panelScroll {
+		m.panelScroll = i
+	}
+	if i >= m.panelScroll+roomPanelLights {
+		m.panelScroll = i - roomPanelLights + 1
+	}
+}
+
+// spreadLabels are the brightness buckets of the room panel histogram: off
+// or 0%, then quarters up to 100%
+var spreadLabels = [...]string{"off", "25", "50", "75", "100"}
+
+// spreadLevels draw a bucket's count relative to the biggest one
+var spreadLevels = []rune("▁▂▃▄▅▆▇█")
+
+// brightnessSpread counts the lights of a room per brightness bucket,
+// leaving out faulty lights as the average does
+func brightnessSpread(room *models.Room) (buckets [len(spreadLabels)]int, counted int) {
+	for _, light := range room.Lights {
+		if light.Faulty() {
+			continue
+		}
+		pct := 0
+		if light.On {
+			pct = light.BrightnessPct()
+			if light.OnOffOnly {
+				pct = 100
+			}
+		}
+		bucket := 0
+		if pct > 0 {
+			bucket = min(4, (pct+24)/25)
+		}
+		buckets[bucket]++
+		counted++
+	}
+	return buckets, counted
+}
+
+// renderBrightnessSpread draws a histogram of the room's light brightness,
+// telling a uniformly dimmed room from a mix of bright and off lights.
+// Rooms with fewer than two lights get none.
+func renderBrightnessSpread(room *models.Room) string {
+	buckets, counted := brightnessSpread(room)
+	if counted < 2 {
+		return ""
+	}
+	biggest := 0
+	for _, n := range buckets {
+		biggest = max(biggest, n)
+	}
+
+	var bars, labels strings.Builder
+	for i, n := range buckets {
+		if n == 0 {
+			bars.WriteString(styleLightOff.Render("··"))
+		} else {
+			level := spreadLevels[(n*len(spreadLevels)-1)/biggest]
+			bars.WriteString(styleLightOn.Render(strings.Repeat(string(level), 2)))
+		}
+		bars.WriteString("  ")
+		labels.WriteString(fmt.Sprintf("%-4s", spreadLabels[i]))
+	}
+	return styleMuted.Render("Spread:") + "\n" +
+		strings.TrimRight(bars.String(), " ") + "\n" +
+		styleMuted.Render(strings.TrimRight(labels.String(), " "))
+}
@@ -0,0 +1,440 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxGroupName is the longest room or zone name the bridge accepts
+const maxGroupName = 32
+
+// managedGroup is a room or a zone on the manage rooms screen
+type managedGroup struct {
+	room *models.Room
+	zone bool
+}
+
+// groupEditor holds the name and lights of the room or zone being edited
+type groupEditor struct {
+	zone bool
+	// Empty for a new room or zone
+	id   string
+	name string
+	// Selected lights, by light ID
+	members map[string]bool
+	// 0 is the name field, then one row per light
+	cursor int
+	err    string
+}
+
+// RoomsModel is the manage rooms screen model
+type RoomsModel struct {
+	groups   []managedGroup
+	lights   []*models.Light
+	selected int
+	loading  bool
+
+	// Pressing d once asks for confirmation
+	confirmDelete bool
+
+	// Editor shown while creating or editing (nil = list view)
+	editor *groupEditor
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewRoomsModel creates a new manage rooms screen model
+func NewRoomsModel() RoomsModel {
+	return RoomsModel{}
+}
+
+// SetSize sets the terminal size
+func (m *RoomsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetLoading sets the loading state
+func (m *RoomsModel) SetLoading(loading bool) {
+	m.loading = loading
+}
+
+// SetLights sets the lights that can be assigned to rooms and zones
+func (m *RoomsModel) SetLights(rooms []*models.Room) {
+	m.lights = nil
+	for _, room := range rooms {
+		m.lights = append(m.lights, room.Lights...)
+	}
+}
+
+// SetGroups sets the rooms and zones, keeping the selection when possible
+func (m *RoomsModel) SetGroups(rooms, zones []*models.Room) {
+	var selectedID string
+	if g := m.selectedGroup(); g != nil {
+		selectedID = g.room.ID
+	}
+
+	m.groups = nil
+	for _, room := range rooms {
+		m.groups = append(m.groups, managedGroup{room: room})
+	}
+	for _, zone := range zones {
+		m.groups = append(m.groups, managedGroup{room: zone, zone: true})
+	}
+	m.loading = false
+	m.confirmDelete = false
+	m.selected = 0
+	for i, g := range m.groups {
+		if g.room.ID == selectedID {
+			m.selected = i
+			break
+		}
+	}
+}
+
+func (m RoomsModel) selectedGroup() *managedGroup {
+	if m.selected >= 0 && m.selected < len(m.groups) {
+		return &m.groups[m.selected]
+	}
+	return nil
+}
+
+// contains reports whether a light belongs to a room or zone. Rooms hold
+// devices, so a light is in the room of its device.
+func (g managedGroup) contains(light *models.Light) bool {
+	if g.zone {
+		return slices.Contains(g.room.LightIDs, light.ID)
+	}
+	return light.DeviceID != "" && slices.Contains(g.room.DeviceIDs, light.DeviceID)
+}
+
+// lightCount returns the number of known lights in a room or zone
+func (m RoomsModel) lightCount(g managedGroup) int {
+	count := 0
+	for _, light := range m.lights {
+		if g.contains(light) {
+			count++
+		}
+	}
+	return count
+}
+
+// Update handles messages
+func (m RoomsModel) Update(msg tea.Msg) (RoomsModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editor != nil {
+		return m.updateEditor(keyMsg)
+	}
+
+	key := keyMsg.String()
+	if m.confirmDelete {
+		m.confirmDelete = false
+		if key == "y" {
+			if g := m.selectedGroup(); g != nil {
+				del := messages.RoomDeleteMsg{Zone: g.zone, ID: g.room.ID}
+				m.loading = true
+				return m, func() tea.Msg { return del }
+			}
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "esc", "R", "q":
+		return m, func() tea.Msg { return messages.HideRoomsMsg{} }
+
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+
+	case "down", "j":
+		if m.selected < len(m.groups)-1 {
+			m.selected++
+		}
+
+	case "n":
+		m.editor = &groupEditor{members: make(map[string]bool)}
+
+	case "z":
+		m.editor = &groupEditor{zone: true, members: make(map[string]bool)}
+
+	case "enter", "e":
+		if g := m.selectedGroup(); g != nil {
+			m.editor = &groupEditor{zone: g.zone, id: g.room.ID, name: g.room.Name, members: make(map[string]bool)}
+			for _, light := range m.lights {
+				if g.contains(light) {
+					m.editor.members[light.ID] = true
+				}
+			}
+		}
+
+	case "d", "delete":
+		if m.selectedGroup() != nil {
+			m.confirmDelete = true
+		}
+
+	case "r":
+		m.loading = true
+		return m, func() tea.Msg { return messages.ShowRoomsMsg{} }
+	}
+
+	return m, nil
+}
+
+// updateEditor handles keys while a room or zone is edited: the name is
+// typed on the first row, space toggles the lights on the next ones
+func (m RoomsModel) updateEditor(msg tea.KeyMsg) (RoomsModel, tea.Cmd) {
+	e := m.editor
+	e.err = ""
+	rows := len(m.lights) + 1
+
+	switch msg.String() {
+	case "esc":
+		m.editor = nil
+
+	case "up", "shift+tab":
+		e.cursor = (e.cursor + rows - 1) % rows
+
+	case "down", "tab":
+		e.cursor = (e.cursor + 1) % rows
+
+	case "backspace":
+		if e.cursor == 0 && e.name != "" {
+			runes := []rune(e.name)
+			e.name = string(runes[:len(runes)-1])
+		}
+
+	case "enter":
+		save, err := m.editorSave()
+		if err != nil {
+			e.err = err.Error()
+			return m, nil
+		}
+		m.editor = nil
+		m.loading = true
+		return m, func() tea.Msg { return save }
+
+	case " ":
+		if e.cursor == 0 {
+			m.typeName([]rune{' '})
+		} else {
+			m.toggleMember(m.lights[e.cursor-1])
+		}
+
+	default:
+		if e.cursor == 0 && msg.Type == tea.KeyRunes {
+			m.typeName(msg.Runes)
+		}
+	}
+
+	return m, nil
+}
+
+func (m RoomsModel) typeName(runes []rune) {
+	e := m.editor
+	if len([]rune(e.name))+len(runes) <= maxGroupName {
+		e.name += string(runes)
+	}
+}
+
+// toggleMember adds or removes a light. In a room, the other lights of
+// the same device follow, as rooms hold whole devices.
+func (m RoomsModel) toggleMember(light *models.Light) {
+	e := m.editor
+	member := !e.members[light.ID]
+	if !e.zone && light.DeviceID == "" {
+		e.err = light.Name + " has no device and can't be put in a room"
+		return
+	}
+	for _, l := range m.lights {
+		if l.ID == light.ID || (!e.zone && l.DeviceID == light.DeviceID) {
+			e.members[l.ID] = member
+		}
+	}
+}
+
+// editorSave validates the editor and builds the save request
+func (m RoomsModel) editorSave() (messages.RoomSaveMsg, error) {
+	e := m.editor
+	name := strings.TrimSpace(e.name)
+	if name == "" {
+		return messages.RoomSaveMsg{}, fmt.Errorf("enter a name")
+	}
+	for _, g := range m.groups {
+		if g.zone == e.zone && g.room.ID != e.id && strings.EqualFold(g.room.Name, name) {
+			return messages.RoomSaveMsg{}, fmt.Errorf("%q already exists", g.room.Name)
+		}
+	}
+
+	// Non-nil so that an emptied group is saved empty
+	children := []string{}
+	for _, light := range m.lights {
+		if !e.members[light.ID] {
+			continue
+		}
+		id := light.ID
+		if !e.zone {
+			id = light.DeviceID
+		}
+		if !slices.Contains(children, id) {
+			children = append(children, id)
+		}
+	}
+	return messages.RoomSaveMsg{Zone: e.zone, ID: e.id, Name: name, ChildIDs: children}, nil
+}
+
+// View renders the manage rooms screen
+func (m RoomsModel) View() string {
+	var b strings.Builder
+
+	if e := m.editor; e != nil {
+		kind := "Room"
+		if e.zone {
+			kind = "Zone"
+		}
+		action := "Edit "
+		if e.id == "" {
+			action = "New "
+		}
+		b.WriteString(styles.StyleModalTitle.Render(action + kind))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderEditor())
+	} else {
+		b.WriteString(styles.StyleModalTitle.Render("Rooms & Zones"))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderList())
+	}
+
+	content := b.String()
+	modalWidth := m.width * 70 / 100
+	if modalWidth < 44 {
+		modalWidth = 44
+	}
+	if modalWidth > 64 {
+		modalWidth = 64
+	}
+	modal := styles.StyleModal.Width(modalWidth).Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+func (m RoomsModel) renderList() string {
+	var b strings.Builder
+
+	switch {
+	case m.loading && len(m.groups) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("Loading..."))
+		b.WriteString("\n")
+	case len(m.groups) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("No rooms or zones"))
+		b.WriteString("\n")
+	}
+
+	for i, g := range m.groups {
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if i == m.selected {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		kind := "room"
+		if g.zone {
+			kind = "zone"
+		}
+		count := m.lightCount(g)
+		lights := fmt.Sprintf("%d lights", count)
+		if count == 1 {
+			lights = "1 light"
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, style.Render(g.room.Name), styles.StyleTextMuted.Render(kind+" · "+lights)))
+	}
+
+	b.WriteString("\n")
+	if m.confirmDelete {
+		if g := m.selectedGroup(); g != nil {
+			b.WriteString(styles.StyleError.Render(fmt.Sprintf("Delete %q? y to confirm", g.room.Name)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • enter edit • n new room • z new zone • d delete • esc close"))
+	return b.String()
+}
+
+func (m RoomsModel) renderEditor() string {
+	e := m.editor
+	var b strings.Builder
+
+	label := styles.StyleTextMuted.Render("Name  ")
+	if e.cursor == 0 {
+		b.WriteString("> " + label + styles.StyleSceneItemSelected.Render(e.name) + "█\n")
+	} else {
+		b.WriteString("  " + label + e.name + "\n")
+	}
+	b.WriteString("\n")
+
+	// Current room of each device, to show which lights a room takes over
+	deviceRooms := make(map[string]string)
+	for _, g := range m.groups {
+		if !g.zone && g.room.ID != e.id {
+			for _, id := range g.room.DeviceIDs {
+				deviceRooms[id] = g.room.Name
+			}
+		}
+	}
+
+	// Only the rows around the cursor fit on small terminals
+	visible := m.height - 16
+	if visible < 5 {
+		visible = 5
+	}
+	start := 0
+	if e.cursor > visible {
+		start = e.cursor - visible
+	}
+	end := min(len(m.lights), start+visible)
+
+	for i := start; i < end; i++ {
+		light := m.lights[i]
+		check := "[ ]"
+		if e.members[light.ID] {
+			check = "[x]"
+		}
+		line := check + " " + light.Name
+		if room, ok := deviceRooms[light.DeviceID]; ok && !e.zone && !e.members[light.ID] {
+			line += styles.StyleTextMuted.Render(" (" + room + ")")
+		}
+		if e.cursor == i+1 {
+			b.WriteString("> " + styles.StyleSceneItemSelected.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	if len(m.lights) == 0 {
+		b.WriteString(styles.StyleTextMuted.Render("No lights"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if e.err != "" {
+		b.WriteString(styles.StyleError.Render(e.err))
+		b.WriteString("\n")
+	} else if !e.zone {
+		b.WriteString(styles.StyleTextMuted.Render("A light leaves its current room when added"))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.StyleHelp.Render("↑/↓ move • space toggle • enter save • esc cancel"))
+	return b.String()
+}
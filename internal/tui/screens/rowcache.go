@@ -0,0 +1,152 @@
+package screens
+
+import "github.com/angristan/hue-tui/internal/models"
+
+// lightRowState is everything a light row is drawn from, but the width and
+// the selection
+type lightRowState struct {
+	name        string
+	on          bool
+	faulty      bool
+	onOffOnly   bool
+	brightness  uint8
+	rgb         [3]uint8
+	hasColor    bool
+	marked      bool
+	unconfirmed bool
+	raw         bool
+	// Step of the scrolling name, -1 when it doesn't scroll
+	marquee int
+}
+
+// roomHeaderState is everything a room header is drawn from, but the
+// selection
+type roomHeaderState struct {
+	name    string
+	summary string
+}
+
+// rowVersion is the version of the state of a row, bumped whenever the
+// state changes
+type rowVersion struct {
+	state   interface{}
+	version uint64
+}
+
+// rowCacheKey identifies a rendered row
+type rowCacheKey struct {
+	id       string
+	version  uint64
+	width    int
+	selected bool
+}
+
+// rowCache keeps the rendered rows of the light list, so that View only
+// redraws the rows whose state, width or selection changed. It is shared
+// by the copies of the main screen.
+type rowCache struct {
+	versions map[string]rowVersion
+	rows     map[string]cachedRow
+	// Last version handed out, versions are never reused
+	last uint64
+}
+
+type cachedRow struct {
+	key rowCacheKey
+	out string
+}
+
+func newRowCache() *rowCache {
+	return &rowCache{
+		versions: make(map[string]rowVersion),
+		rows:     make(map[string]cachedRow),
+	}
+}
+
+// version returns the version of the state of row id, bumping it when
+// state differs from the last one seen
+func (c *rowCache) version(id string, state interface{}) uint64 {
+	v, ok := c.versions[id]
+	if !ok || v.state != state {
+		c.last++
+		v = rowVersion{state: state, version: c.last}
+		c.versions[id] = v
+	}
+	return v.version
+}
+
+// render returns the cached row for key, or renders and keeps it
+func (c *rowCache) render(key rowCacheKey, render func() string) string {
+	if row, ok := c.rows[key.id]; ok && row.key == key {
+		return row.out
+	}
+	out := render()
+	c.rows[key.id] = cachedRow{key: key, out: out}
+	return out
+}
+
+// forget drops the rows of lights and rooms no longer listed
+func (c *rowCache) forget(items []listItem) {
+	listed := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.isRoom {
+			listed[roomRowID(item.room)] = true
+		} else {
+			listed[item.light.ID] = true
+		}
+	}
+	for id := range c.versions {
+		if !listed[id] {
+			delete(c.versions, id)
+			delete(c.rows, id)
+		}
+	}
+}
+
+// roomRowID is the cache ID of a room header, apart from the light IDs
+func roomRowID(room *models.Room) string {
+	return "room:" + room.ID
+}
+
+// cachedLightRow renders a light row, reusing the last rendering when
+// nothing it shows changed
+func (m MainModel) cachedLightRow(light *models.Light, selected bool, width int) string {
+	if m.rows == nil {
+		return m.renderLightRow(light, selected, width)
+	}
+	state := lightRowState{
+		name:        m.displayName(light),
+		on:          light.On,
+		faulty:      light.Faulty(),
+		onOffOnly:   light.OnOffOnly,
+		brightness:  light.Brightness,
+		marked:      m.marked[light.ID],
+		unconfirmed: m.unconfirmed[light.ID],
+		raw:         m.format.RawBrightness(),
+		marquee:     -1,
+	}
+	if light.Color != nil {
+		r, g, b := light.Color.RGB()
+		state.rgb, state.hasColor = [3]uint8{r, g, b}, true
+	}
+	if selected && m.marquee.lightID == light.ID {
+		state.marquee = m.marquee.step
+	}
+	key := rowCacheKey{id: light.ID, version: m.rows.version(light.ID, state), width: width, selected: selected}
+	return m.rows.render(key, func() string { return m.renderLightRow(light, selected, width) })
+}
+
+// cachedRoomHeader renders a room header, reusing the last rendering when
+// nothing it shows changed. Fading rooms are always redrawn.
+func (m MainModel) cachedRoomHeader(room *models.Room, selected bool) string {
+	if m.rows == nil {
+		return m.renderRoomHeader(room, selected)
+	}
+	if _, fading := m.fades[room.ID]; fading {
+		return m.renderRoomHeader(room, selected)
+	}
+	id := roomRowID(room)
+	state := roomHeaderState{name: room.Name, summary: m.roomSummary(room)}
+	key := rowCacheKey{id: id, version: m.rows.version(id, state), selected: selected}
+	return m.rows.render(key, func() string { return m.renderRoomHeader(room, selected) })
+}
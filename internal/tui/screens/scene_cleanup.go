@@ -0,0 +1,405 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// DefaultUnusedSceneDays is how long a scene goes without being
+	// activated before the cleanup lists it
+	DefaultUnusedSceneDays = 90
+	// unusedSceneDaysStep is the change of ←/→
+	unusedSceneDaysStep = 30
+)
+
+// cleanupScene is a scene the cleanup suggests deleting
+type cleanupScene struct {
+	scene *models.Scene
+	// Name of its room or zone, empty when orphaned
+	groupName string
+	// The room or zone of the scene no longer exists
+	orphaned bool
+}
+
+// cleanupFailure is a scene that couldn't be deleted
+type cleanupFailure struct {
+	name string
+	err  error
+}
+
+// SceneCleanupModel lists the scenes not activated for a while, or whose
+// room or zone was deleted, to delete the marked ones at once
+type SceneCleanupModel struct {
+	scenes []*models.Scene
+	// Names of the rooms and zones by ID
+	groups map[string]string
+	// Scenes unused for this many days are listed
+	days       int
+	candidates []cleanupScene
+	// Marked scene IDs
+	marked   map[string]bool
+	selected int
+	offset   int
+	loading  bool
+	// Pressing enter once asks for confirmation
+	confirm bool
+
+	// Scene IDs left to delete, the first one being deleted
+	queue    []string
+	total    int
+	deleted  int
+	failures []cleanupFailure
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewSceneCleanupModel creates a new scene cleanup model
+func NewSceneCleanupModel() SceneCleanupModel {
+	return SceneCleanupModel{days: DefaultUnusedSceneDays, marked: make(map[string]bool)}
+}
+
+// SetSize sets the terminal size
+func (m *SceneCleanupModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetDays sets after how many days without activation a scene is listed
+func (m *SceneCleanupModel) SetDays(days int) {
+	if days <= 0 {
+		days = DefaultUnusedSceneDays
+	}
+	m.days = days
+	m.rebuild()
+}
+
+// SetLoading sets the loading state, and forgets the last batch
+func (m *SceneCleanupModel) SetLoading(loading bool) {
+	m.loading = loading
+	if loading {
+		m.confirm = false
+		m.total, m.deleted, m.failures = 0, 0, nil
+	}
+}
+
+// SetScenes sets the scenes and the rooms and zones they may belong to
+func (m *SceneCleanupModel) SetScenes(scenes []*models.Scene, groups []*models.Room) {
+	m.scenes = scenes
+	m.groups = make(map[string]string, len(groups))
+	for _, g := range groups {
+		m.groups[g.ID] = g.Name
+	}
+	m.loading = false
+	m.rebuild()
+}
+
+// hasHistory reports whether the bridge tells when scenes were last
+// activated. Old bridges don't, so no scene can be called unused.
+func (m SceneCleanupModel) hasHistory() bool {
+	for _, scene := range m.scenes {
+		if !scene.LastRecalled.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuild lists the orphaned scenes, then the unused ones from the least
+// recently activated, keeping the marks of the scenes still listed
+func (m *SceneCleanupModel) rebuild() {
+	cutoff := time.Now().AddDate(0, 0, -m.days)
+	history := m.hasHistory()
+
+	m.candidates = m.candidates[:0]
+	for _, scene := range m.scenes {
+		// Smart scenes are a different resource, managed with schedules
+		if scene.IsSmart {
+			continue
+		}
+		name, ok := m.groups[scene.RoomID]
+		switch {
+		case !ok:
+			m.candidates = append(m.candidates, cleanupScene{scene: scene, orphaned: true})
+		case history && scene.LastRecalled.Before(cutoff):
+			m.candidates = append(m.candidates, cleanupScene{scene: scene, groupName: name})
+		}
+	}
+	slices.SortStableFunc(m.candidates, func(a, b cleanupScene) int {
+		if a.orphaned != b.orphaned {
+			if a.orphaned {
+				return -1
+			}
+			return 1
+		}
+		if c := a.scene.LastRecalled.Compare(b.scene.LastRecalled); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.scene.Name), strings.ToLower(b.scene.Name))
+	})
+
+	listed := make(map[string]bool, len(m.candidates))
+	for _, c := range m.candidates {
+		listed[c.scene.ID] = true
+	}
+	for id := range m.marked {
+		if !listed[id] {
+			delete(m.marked, id)
+		}
+	}
+	m.selected = max(0, min(m.selected, len(m.candidates)-1))
+	m.ensureVisible()
+}
+
+// deleting reports whether a batch is being deleted
+func (m SceneCleanupModel) deleting() bool {
+	return len(m.queue) > 0
+}
+
+// SceneDeleted records the outcome of deleting the first queued scene, and
+// returns the command deleting the next one
+func (m *SceneCleanupModel) SceneDeleted(sceneID string, err error) tea.Cmd {
+	if !m.deleting() || m.queue[0] != sceneID {
+		return nil
+	}
+	m.queue = m.queue[1:]
+	if err != nil {
+		m.failures = append(m.failures, cleanupFailure{name: m.sceneName(sceneID), err: err})
+	} else {
+		m.deleted++
+		delete(m.marked, sceneID)
+		m.scenes = slices.DeleteFunc(slices.Clone(m.scenes), func(s *models.Scene) bool { return s.ID == sceneID })
+		m.rebuild()
+	}
+	return m.deleteNext()
+}
+
+// deleteNext asks to delete the first queued scene, or reports the end of
+// the batch
+func (m SceneCleanupModel) deleteNext() tea.Cmd {
+	if !m.deleting() {
+		if m.total == 0 {
+			return nil
+		}
+		return func() tea.Msg { return messages.ScenesCleanedMsg{} }
+	}
+	id := m.queue[0]
+	return func() tea.Msg { return messages.DeleteSceneMsg{SceneID: id} }
+}
+
+// sceneName returns the name of a scene by ID
+func (m SceneCleanupModel) sceneName(id string) string {
+	for _, scene := range m.scenes {
+		if scene.ID == id {
+			return scene.Name
+		}
+	}
+	return id
+}
+
+// Update handles messages
+func (m SceneCleanupModel) Update(msg tea.Msg) (SceneCleanupModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	key := keyMsg.String()
+
+	// A batch runs to the end, only closing is possible
+	if m.deleting() {
+		if key == "esc" || key == "q" {
+			return m, func() tea.Msg { return messages.HideSceneCleanupMsg{} }
+		}
+		return m, nil
+	}
+
+	if m.confirm {
+		m.confirm = false
+		if key != "y" {
+			return m, nil
+		}
+		for _, c := range m.candidates {
+			if m.marked[c.scene.ID] {
+				m.queue = append(m.queue, c.scene.ID)
+			}
+		}
+		m.total, m.deleted, m.failures = len(m.queue), 0, nil
+		return m, m.deleteNext()
+	}
+
+	switch key {
+	case "esc", "q":
+		return m, func() tea.Msg { return messages.HideSceneCleanupMsg{} }
+
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+
+	case "down", "j":
+		if m.selected < len(m.candidates)-1 {
+			m.selected++
+		}
+
+	case " ", "x":
+		if m.selected < len(m.candidates) {
+			id := m.candidates[m.selected].scene.ID
+			if m.marked[id] {
+				delete(m.marked, id)
+			} else {
+				m.marked[id] = true
+			}
+		}
+
+	case "a":
+		// Marks every scene, or clears the marks when all are marked
+		all := len(m.marked) == len(m.candidates)
+		m.marked = make(map[string]bool)
+		if !all {
+			for _, c := range m.candidates {
+				m.marked[c.scene.ID] = true
+			}
+		}
+
+	case "left", "h":
+		if m.days > unusedSceneDaysStep {
+			m.SetDays(m.days - unusedSceneDaysStep)
+		}
+
+	case "right", "l":
+		m.SetDays(m.days + unusedSceneDaysStep)
+
+	case "enter", "d", "delete":
+		if len(m.marked) > 0 {
+			m.confirm = true
+		}
+
+	case "r":
+		m.SetLoading(true)
+		return m, func() tea.Msg { return messages.ShowSceneCleanupMsg{} }
+	}
+
+	m.ensureVisible()
+	return m, nil
+}
+
+// visibleRows is the number of scenes listed at once
+func (m SceneCleanupModel) visibleRows() int {
+	return max(3, m.height-18)
+}
+
+// View renders the scene cleanup
+func (m SceneCleanupModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.StyleModalTitle.Render("Scene cleanup"))
+	b.WriteString("\n")
+	criteria := fmt.Sprintf("Scenes unused for %d days, or whose room or zone is gone", m.days)
+	if !m.loading && len(m.scenes) > 0 && !m.hasHistory() {
+		criteria = "Scenes whose room or zone is gone (the bridge doesn't tell when scenes were used)"
+	}
+	b.WriteString(styles.StyleTextMuted.Render(criteria))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.loading:
+		b.WriteString(styles.StyleTextMuted.Render("Loading...") + "\n")
+	case len(m.candidates) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("Nothing to clean up") + "\n")
+	default:
+		b.WriteString(m.renderList())
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.deleting():
+		done := m.total - len(m.queue)
+		b.WriteString(styles.StylePrimary.Render(fmt.Sprintf("Deleting %d/%d…", done+1, m.total)) + "\n")
+	case m.total > 0:
+		b.WriteString(styles.StyleSuccess.Render(fmt.Sprintf("Deleted %d of %d scenes", m.deleted, m.total)) + "\n")
+	}
+	for _, f := range m.failures {
+		b.WriteString(styles.StyleError.Render(fmt.Sprintf("✗ %s: %v", f.name, f.err)) + "\n")
+	}
+	if m.confirm {
+		b.WriteString(styles.StyleError.Render(fmt.Sprintf("Delete %d scenes? y to confirm", len(m.marked))) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • space mark • a all • ←/→ days • enter delete • r refresh • esc close"))
+
+	content := b.String()
+	modalWidth := min(max(m.width*80/100, 50), 90)
+	modal := styles.StyleModal.Width(modalWidth).Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+// ensureVisible scrolls the list to the selected scene
+func (m *SceneCleanupModel) ensureVisible() {
+	rows := m.visibleRows()
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+rows {
+		m.offset = m.selected - rows + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.candidates)-rows))
+}
+
+// renderList renders the window of listed scenes
+func (m SceneCleanupModel) renderList() string {
+	rows := m.visibleRows()
+
+	nameWidth := 0
+	for _, c := range m.candidates {
+		nameWidth = max(nameWidth, lipgloss.Width(c.scene.Name))
+	}
+	nameWidth = min(nameWidth, 28)
+
+	var b strings.Builder
+	end := min(len(m.candidates), m.offset+rows)
+	for i := m.offset; i < end; i++ {
+		c := m.candidates[i]
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if i == m.selected {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		box := "[ ] "
+		if m.marked[c.scene.ID] {
+			box = "[x] "
+		}
+		name := padRight(truncate(c.scene.Name, nameWidth), nameWidth)
+		b.WriteString(cursor + box + style.Render(name) + "  " + styles.StyleTextMuted.Render(cleanupReason(c)) + "\n")
+	}
+	if len(m.candidates) > rows {
+		b.WriteString(styles.StyleTextMuted.Render(fmt.Sprintf("  %d-%d of %d, %d marked", m.offset+1, end, len(m.candidates), len(m.marked))) + "\n")
+	} else if len(m.marked) > 0 {
+		b.WriteString(styles.StyleTextMuted.Render(fmt.Sprintf("  %d marked", len(m.marked))) + "\n")
+	}
+	return b.String()
+}
+
+// cleanupReason tells why a scene is listed
+func cleanupReason(c cleanupScene) string {
+	if c.orphaned {
+		return "room or zone deleted"
+	}
+	if c.scene.LastRecalled.IsZero() {
+		return c.groupName + " • never used"
+	}
+	days := int(time.Since(c.scene.LastRecalled).Hours() / 24)
+	return fmt.Sprintf("%s • used %d days ago", c.groupName, days)
+}
@@ -0,0 +1,79 @@
+package screens
+
+import (
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sceneRecall is the state a room's lights had before a scene was
+// activated on them
+type sceneRecall struct {
+	sceneName string
+	states    undoStep
+}
+
+// RememberBeforeScene captures the lights a scene is about to change, by
+// room, so ctrl+z can put the room back. Zone scenes are filed under the
+// rooms of their lights. Activating another scene replaces the capture.
+func (m *MainModel) RememberBeforeScene(sceneID string) {
+	var scene *models.Scene
+	for _, s := range m.scenes {
+		if s.ID == sceneID {
+			scene = s
+		}
+	}
+	if scene == nil {
+		return
+	}
+
+	var lights []*models.Light
+	for _, room := range m.rooms {
+		if room.ID == scene.RoomID {
+			lights = room.Lights
+		}
+	}
+	if lights == nil {
+		for _, action := range scene.Actions {
+			if light := m.findLight(action.LightID); light != nil {
+				lights = append(lights, light)
+			}
+		}
+	}
+
+	recalls := make(map[string]sceneRecall)
+	for _, light := range lights {
+		room := m.lightToRoom[light.ID]
+		if room == nil {
+			continue
+		}
+		recall, ok := recalls[room.ID]
+		if !ok {
+			recall = sceneRecall{sceneName: scene.Name, states: make(undoStep)}
+			recalls[room.ID] = recall
+		}
+		recall.states[light.ID] = captureLightState(light)
+	}
+	for roomID, recall := range recalls {
+		m.sceneRecalls[roomID] = recall
+	}
+}
+
+// revertScene restores the selected room to its state before the last
+// scene activated on it. It is kept apart from the undo history.
+func (m *MainModel) revertScene(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	room := m.SelectedRoom()
+	if room == nil {
+		return nil
+	}
+	recall, ok := m.sceneRecalls[room.ID]
+	if !ok {
+		m.notice = "No scene to revert in " + room.Name
+		return nil
+	}
+	delete(m.sceneRecalls, room.ID)
+	_, cmd := m.restoreStep(bridge, recall.states, pending)
+	room.UpdateState()
+	m.notice = room.Name + " restored from before " + recall.sceneName
+	return cmd
+}
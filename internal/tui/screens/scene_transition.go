@@ -0,0 +1,92 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxSceneTransition is the longest recall duration accepted, in seconds
+const maxSceneTransition = 3600
+
+// SetSceneTransitions sets the recall duration of the scenes that have
+// one, by scene ID
+func (m *ScenesModel) SetSceneTransitions(transitions map[string]time.Duration) {
+	m.transitions = transitions
+}
+
+// startTransition opens the recall duration input of the selected scene
+func (m *ScenesModel) startTransition() {
+	if m.selected < 0 || m.selected >= len(m.flatList) {
+		return
+	}
+	item := m.flatList[m.selected]
+	// Smart scenes switch scenes on their own schedule
+	if item.isHeader || item.stop || item.scene == nil || item.scene.IsSmart {
+		return
+	}
+	m.transitionScene = item.scene.ID
+	m.transitionInput = ""
+	m.transitionErr = ""
+	if d, ok := m.transitions[item.scene.ID]; ok {
+		m.transitionInput = strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+	}
+}
+
+// updateTransition handles the recall duration input. An empty value
+// goes back to the bridge default.
+func (m ScenesModel) updateTransition(msg tea.KeyMsg) (ScenesModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.transitionScene = ""
+
+	case "enter":
+		sceneID := m.transitionScene
+		input := strings.TrimSpace(m.transitionInput)
+		if input == "" {
+			m.transitionScene = ""
+			return m, func() tea.Msg { return messages.SceneTransitionMsg{SceneID: sceneID, Clear: true} }
+		}
+		secs, err := strconv.ParseFloat(input, 64)
+		if err != nil || secs < 0 || secs > maxSceneTransition {
+			m.transitionErr = fmt.Sprintf("from 0 to %d seconds", maxSceneTransition)
+			return m, nil
+		}
+		m.transitionScene = ""
+		d := time.Duration(secs * float64(time.Second)).Round(100 * time.Millisecond)
+		return m, func() tea.Msg { return messages.SceneTransitionMsg{SceneID: sceneID, Duration: d} }
+
+	case "backspace":
+		if m.transitionInput != "" {
+			m.transitionInput = m.transitionInput[:len(m.transitionInput)-1]
+			m.transitionErr = ""
+		}
+
+	default:
+		if msg.Type != tea.KeyRunes {
+			return m, nil
+		}
+		for _, r := range msg.Runes {
+			if (r < '0' || r > '9') && r != '.' {
+				return m, nil
+			}
+		}
+		if len(m.transitionInput)+len(msg.Runes) <= 6 {
+			m.transitionInput += string(msg.Runes)
+			m.transitionErr = ""
+		}
+	}
+	return m, nil
+}
+
+// formatTransition returns a recall duration as shown next to scenes
+func formatTransition(d time.Duration) string {
+	if d == 0 {
+		return "instant"
+	}
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -1,7 +1,9 @@
 package screens
 
 import (
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/angristan/hue-tui/internal/models"
 	"github.com/angristan/hue-tui/internal/tui/messages"
@@ -10,6 +12,15 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Orders of the scenes modal, cycled with tab
+const (
+	SceneSortRoom   = "room"
+	SceneSortName   = "name"
+	SceneSortRecent = "recent"
+)
+
+var sceneSorts = []string{SceneSortRoom, SceneSortName, SceneSortRecent}
+
 // ScenesModel is the scenes modal model
 type ScenesModel struct {
 	scenes   []*models.Scene
@@ -27,6 +38,28 @@ type ScenesModel struct {
 	filterRoomID   string
 	filterRoomName string
 
+	// Typed search, matched against scene names. Letters and digits are
+	// only typed while searching, after /; otherwise they act as keys.
+	query     string
+	searching bool
+
+	// Order of the list, one of sceneSorts
+	sort string
+
+	// Scene IDs bound to the shortcut keys, by room ID and key
+	shortcuts map[string]map[string]string
+
+	// Name of the scene being saved from the room's current state
+	naming    bool
+	sceneName string
+
+	// Recall durations by scene ID, and the input setting the duration of
+	// transitionScene (empty when closed)
+	transitions     map[string]time.Duration
+	transitionScene string
+	transitionInput string
+	transitionErr   string
+
 	// Window size
 	width  int
 	height int
@@ -36,11 +69,13 @@ type sceneItem struct {
 	scene    *models.Scene
 	isHeader bool
 	roomName string
+	// Stops the dynamics of scene instead of activating it
+	stop bool
 }
 
 // NewScenesModel creates a new scenes screen model
 func NewScenesModel() ScenesModel {
-	return ScenesModel{}
+	return ScenesModel{sort: SceneSortRoom}
 }
 
 // SetSize sets the terminal size
@@ -57,10 +92,47 @@ func (m *ScenesModel) SetScenes(scenes []*models.Scene, rooms []*models.Room) {
 	m.rebuildFlatList()
 }
 
+// SetSort sets the order of the list, defaulting to grouping by room
+func (m *ScenesModel) SetSort(sort string) {
+	if !slices.Contains(sceneSorts, sort) {
+		sort = SceneSortRoom
+	}
+	m.sort = sort
+	m.rebuildFlatList()
+}
+
+// SetSceneShortcuts sets the scene IDs bound to the keys 1-9, by room ID
+func (m *ScenesModel) SetSceneShortcuts(shortcuts map[string]map[string]string) {
+	m.shortcuts = shortcuts
+}
+
+// shortcutScene returns the scene activated by key n in the filtered room
+func (m *ScenesModel) shortcutScene(n int) *models.Scene {
+	if m.filterRoomID == "" {
+		return nil
+	}
+	key := string(rune('0' + n))
+	return models.SceneShortcut(m.scenes, m.filterRoomID, m.shortcuts[m.filterRoomID][key], n)
+}
+
+// shortcutKey returns the key activating a scene in the filtered room, or
+// an empty string
+func (m *ScenesModel) shortcutKey(scene *models.Scene) string {
+	for n := 1; n <= 9; n++ {
+		if s := m.shortcutScene(n); s != nil && s.ID == scene.ID {
+			return string(rune('0' + n))
+		}
+	}
+	return ""
+}
+
 // SetRoomFilter sets the room filter and rebuilds the list
 func (m *ScenesModel) SetRoomFilter(roomID string) {
 	m.filterRoomID = roomID
 	m.filterRoomName = ""
+	m.query = ""
+	m.searching = false
+	m.naming = false
 
 	// Find room name for the filter
 	if roomID != "" {
@@ -75,19 +147,38 @@ func (m *ScenesModel) SetRoomFilter(roomID string) {
 	m.rebuildFlatList()
 }
 
+// setQuery updates the search and rebuilds the list
+func (m *ScenesModel) setQuery(query string) {
+	m.query = query
+	m.rebuildFlatList()
+}
+
 // rebuildFlatList rebuilds the flat list based on current filter
 func (m *ScenesModel) rebuildFlatList() {
 	// Build room order and flat list
 	m.roomOrder = nil
 	m.flatList = nil
 
+	if m.sort == SceneSortName || m.sort == SceneSortRecent {
+		m.rebuildSortedList()
+		m.selectFirst()
+		return
+	}
+
 	for _, room := range m.rooms {
 		// Skip if filtering to a specific room and this isn't it
 		if m.filterRoomID != "" && room.ID != m.filterRoomID {
 			continue
 		}
 
-		if scenes, ok := m.groupedScenes[room.ID]; ok && len(scenes) > 0 {
+		var scenes []*models.Scene
+		for _, scene := range m.groupedScenes[room.ID] {
+			if fuzzyMatch(scene.Name, m.query) {
+				scenes = append(scenes, scene)
+			}
+		}
+
+		if len(scenes) > 0 {
 			m.roomOrder = append(m.roomOrder, room.ID)
 
 			// Only add room header if showing all rooms (no filter)
@@ -105,12 +196,59 @@ func (m *ScenesModel) rebuildFlatList() {
 					roomName: room.Name,
 				})
 			}
+
+			// A cycling scene can be stopped from its room
+			for _, scene := range m.groupedScenes[room.ID] {
+				if scene.IsCycling() && m.query == "" {
+					m.flatList = append(m.flatList, sceneItem{
+						scene:    scene,
+						roomName: room.Name,
+						stop:     true,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	m.selectFirst()
+}
+
+// rebuildSortedList lists the matching scenes without room headers, by
+// name or most recently activated first. Scenes never activated come last.
+func (m *ScenesModel) rebuildSortedList() {
+	var stops []sceneItem
+	for _, room := range m.rooms {
+		if m.filterRoomID != "" && room.ID != m.filterRoomID {
+			continue
+		}
+		for _, scene := range m.groupedScenes[room.ID] {
+			if fuzzyMatch(scene.Name, m.query) {
+				m.flatList = append(m.flatList, sceneItem{scene: scene, roomName: room.Name})
+			}
+		}
+		for _, scene := range m.groupedScenes[room.ID] {
+			if scene.IsCycling() && m.query == "" {
+				stops = append(stops, sceneItem{scene: scene, roomName: room.Name, stop: true})
+				break
+			}
 		}
 	}
 
-	// Reset selection
+	slices.SortStableFunc(m.flatList, func(a, b sceneItem) int {
+		if m.sort == SceneSortRecent {
+			if c := b.scene.LastRecalled.Compare(a.scene.LastRecalled); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(strings.ToLower(a.scene.Name), strings.ToLower(b.scene.Name))
+	})
+	m.flatList = append(m.flatList, stops...)
+}
+
+// selectFirst selects the first scene, skipping headers
+func (m *ScenesModel) selectFirst() {
 	m.selected = 0
-	// Skip to first scene (not header)
 	for i, item := range m.flatList {
 		if !item.isHeader {
 			m.selected = i
@@ -123,31 +261,222 @@ func (m *ScenesModel) rebuildFlatList() {
 func (m ScenesModel) Update(msg tea.Msg) (ScenesModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.naming {
+			return m.updateNaming(msg)
+		}
+		if m.transitionScene != "" {
+			return m.updateTransition(msg)
+		}
 		switch msg.String() {
-		case "esc", "s", "q":
+		case "ctrl+s":
+			// Scenes are saved for the room the modal was opened for
+			if m.filterRoomID != "" {
+				m.naming = true
+				m.sceneName = m.query
+			}
+
+		case "ctrl+g":
+			// Morning, Day, Evening and Night scenes for a new room
+			if m.filterRoomID != "" {
+				roomID := m.filterRoomID
+				return m, func() tea.Msg { return messages.GenerateScenesMsg{RoomID: roomID} }
+			}
+
+		case "ctrl+t":
+			m.startTransition()
+
+		case "/":
+			if !m.searching {
+				m.searching = true
+				return m, nil
+			}
+			m.setQuery(m.query + "/")
+
+		case "tab":
+			i := slices.Index(sceneSorts, m.sort)
+			m.SetSort(sceneSorts[(i+1)%len(sceneSorts)])
+			sort := m.sort
+			return m, func() tea.Msg { return messages.SceneSortMsg{Sort: sort} }
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Without a search, digits activate the room's scene shortcuts
+			if !m.searching {
+				if scene := m.shortcutScene(int(msg.Runes[0] - '0')); scene != nil {
+					return m, func() tea.Msg { return messages.SceneActivatedMsg{SceneID: scene.ID} }
+				}
+				return m, nil
+			}
+			m.setQuery(m.query + string(msg.Runes))
+
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			// Bind the selected scene to a digit
+			if m.selected >= 0 && m.selected < len(m.flatList) {
+				item := m.flatList[m.selected]
+				if !item.isHeader && !item.stop && item.scene != nil {
+					shortcut := messages.SceneShortcutMsg{
+						RoomID:  item.scene.RoomID,
+						Key:     strings.TrimPrefix(msg.String(), "alt+"),
+						SceneID: item.scene.ID,
+					}
+					return m, func() tea.Msg { return shortcut }
+				}
+			}
+
+		case "esc":
+			// First esc clears the search, then leaves it, then closes
+			if m.query != "" {
+				m.setQuery("")
+				return m, nil
+			}
+			if m.searching {
+				m.searching = false
+				return m, nil
+			}
 			return m, func() tea.Msg { return messages.HideScenesMsg{} }
 
-		case "up", "k":
+		case "s", "q":
+			if !m.searching {
+				return m, func() tea.Msg { return messages.HideScenesMsg{} }
+			}
+			m.setQuery(m.query + string(msg.Runes))
+
+		case "up", "ctrl+p":
 			m.movePrev()
 
-		case "down", "j":
+		case "down", "ctrl+n":
 			m.moveNext()
 
-		case "enter":
+		case "k", "j":
+			// Without a search, j and k move like the arrows
+			if !m.searching {
+				if msg.String() == "k" {
+					m.movePrev()
+				} else {
+					m.moveNext()
+				}
+				return m, nil
+			}
+			m.setQuery(m.query + string(msg.Runes))
+
+		case "backspace":
+			if m.query != "" {
+				runes := []rune(m.query)
+				m.setQuery(string(runes[:len(runes)-1]))
+			}
+
+		case "ctrl+u":
+			m.setQuery("")
+
+		case "enter", "alt+enter":
 			if m.selected >= 0 && m.selected < len(m.flatList) {
 				item := m.flatList[m.selected]
+				if item.stop {
+					return m, func() tea.Msg {
+						return messages.StopSceneDynamicsMsg{SceneID: item.scene.ID}
+					}
+				}
+				if !item.isHeader && item.scene != nil && item.scene.IsSmart {
+					toggle := messages.SmartSceneToggleMsg{SceneID: item.scene.ID, Active: !item.scene.IsActive()}
+					return m, func() tea.Msg { return toggle }
+				}
 				if !item.isHeader && item.scene != nil {
+					fade := msg.String() == "alt+enter"
 					return m, func() tea.Msg {
-						return messages.SceneActivatedMsg{SceneID: item.scene.ID}
+						return messages.SceneActivatedMsg{SceneID: item.scene.ID, Fade: fade}
 					}
 				}
 			}
+
+		default:
+			// Any other printable key extends the search
+			if m.searching && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+				m.setQuery(m.query + string(msg.Runes))
+			}
 		}
 	}
 
 	return m, nil
 }
 
+// updateNaming handles the scene name input
+func (m ScenesModel) updateNaming(msg tea.KeyMsg) (ScenesModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.naming = false
+
+	case "enter":
+		name := strings.TrimSpace(m.sceneName)
+		if name == "" {
+			return m, nil
+		}
+		m.naming = false
+		roomID := m.filterRoomID
+		return m, func() tea.Msg {
+			return messages.SaveSceneMsg{Name: name, RoomID: roomID}
+		}
+
+	case "backspace":
+		if m.sceneName != "" {
+			runes := []rune(m.sceneName)
+			m.sceneName = string(runes[:len(runes)-1])
+		}
+
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.sceneName += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// maxSwatches caps the number of color swatches shown per scene
+const maxSwatches = 8
+
+// renderSwatches renders a ◆ in the color of each light the scene turns on
+func renderSwatches(scene *models.Scene) string {
+	swatches := scene.Swatches()
+	if len(swatches) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(" ")
+	for i, c := range swatches {
+		if i == maxSwatches {
+			b.WriteString(styles.StyleTextMuted.Render("+"))
+			break
+		}
+		b.WriteString(" " + lipgloss.NewStyle().Foreground(lipgloss.Color(c.HexString())).Render("◆"))
+	}
+	return b.String()
+}
+
+// fuzzyMatch reports whether the characters of query appear in name in
+// order, ignoring case (so "svs" matches "Savanna Sunset")
+func fuzzyMatch(name, query string) bool {
+	name = strings.ToLower(name)
+	for _, r := range strings.ToLower(query) {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(string(r)):]
+	}
+	return true
+}
+
+// sortLabel describes the current order of the list
+func (m ScenesModel) sortLabel() string {
+	switch m.sort {
+	case SceneSortName:
+		return "name"
+	case SceneSortRecent:
+		return "last activated"
+	default:
+		return "room"
+	}
+}
+
 func (m *ScenesModel) moveNext() {
 	for i := m.selected + 1; i < len(m.flatList); i++ {
 		if !m.flatList[i].isHeader {
@@ -170,13 +499,41 @@ func (m *ScenesModel) movePrev() {
 func (m ScenesModel) View() string {
 	var b strings.Builder
 
+	// Responsive width (60-80% of screen, 40-60 chars)
+	modalWidth := m.width * 70 / 100
+	if modalWidth < 40 {
+		modalWidth = 40
+	}
+	if modalWidth > 60 {
+		modalWidth = 60
+	}
+
 	// Modal title - show room name if filtering
 	title := "Scenes"
 	if m.filterRoomName != "" {
 		title = m.filterRoomName + " Scenes"
 	}
 	b.WriteString(styles.StyleModalTitle.Render(title))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+
+	// Search bar, spanning the modal content
+	searchWidth := modalWidth - 6
+	if m.naming {
+		b.WriteString(styles.StyleSearchBarFocused.Width(searchWidth).Render("Save as: " + m.sceneName + "█"))
+	} else if m.transitionScene != "" {
+		b.WriteString(styles.StyleSearchBarFocused.Width(searchWidth).Render("Recall over: " + m.transitionInput + "█ s"))
+	} else if m.query != "" || m.searching {
+		b.WriteString(styles.StyleSearchBarFocused.Width(searchWidth).Render("/ " + m.query + "█"))
+	} else {
+		b.WriteString(styles.StyleSearchBar.Width(searchWidth).Render(styles.StyleTextMuted.Render("/ to filter")))
+	}
+	b.WriteString("\n")
+	if m.transitionErr != "" {
+		b.WriteString(styles.StyleError.Render(m.transitionErr))
+	} else {
+		b.WriteString(styles.StyleTextMuted.Render("Sorted by " + m.sortLabel() + " · tab to change"))
+	}
+	b.WriteString("\n")
 
 	// Scene list
 	for i, item := range m.flatList {
@@ -193,26 +550,57 @@ func (m ScenesModel) View() string {
 			cursor = "> "
 		}
 
-		b.WriteString(cursor + style.Render(item.scene.Name) + "\n")
+		if item.stop {
+			b.WriteString(cursor + style.Render("⏸ stop dynamics") + "\n")
+			continue
+		}
+		label := item.scene.Name
+		if item.scene.IsSmart {
+			// Smart scenes switch scenes through the day
+			label = "☀ " + label
+		}
+		name := style.Render(label)
+		if item.scene.IsSmart && item.scene.IsActive() {
+			name += styles.StyleTextMuted.Render(" · running, enter stops")
+		}
+		if item.scene.IsCycling() {
+			name += styles.StyleTextMuted.Render(" ↻")
+		}
+		if key := m.shortcutKey(item.scene); key != "" {
+			name += styles.StyleTextMuted.Render(" [" + key + "]")
+		}
+		if d, ok := m.transitions[item.scene.ID]; ok {
+			name += styles.StyleTextMuted.Render(" ⏱ " + formatTransition(d))
+		}
+		if m.sort != SceneSortRoom && m.filterRoomID == "" {
+			name += styles.StyleTextMuted.Render(" · " + item.roomName)
+		}
+		b.WriteString(cursor + name + renderSwatches(item.scene) + "\n")
 	}
 
 	if len(m.flatList) == 0 {
-		b.WriteString(styles.StyleTextMuted.Render("No scenes available"))
+		empty := "No scenes available"
+		if m.query != "" {
+			empty = "No scenes match \"" + m.query + "\""
+		}
+		b.WriteString(styles.StyleTextMuted.Render(empty))
 		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • enter activate • esc close"))
+	switch {
+	case m.naming:
+		b.WriteString(styles.StyleHelp.Render("enter save current state • esc cancel"))
+	case m.transitionScene != "":
+		b.WriteString(styles.StyleHelp.Render("seconds, 0 for instant • enter set • empty enter bridge default • esc cancel"))
+	case m.filterRoomID != "":
+		b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • enter/1-9 activate • alt+1-9 bind • ^t recall time • ^s save • ^g natural light scenes • / search • tab sort • esc clear/close"))
+	default:
+		b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • enter activate • alt+enter fade in • ^t recall time • / search • tab sort • esc clear/close"))
+	}
 
-	// Wrap in modal style - responsive width (60-80% of screen, 40-60 chars)
+	// Wrap in modal style
 	content := b.String()
-	modalWidth := m.width * 70 / 100
-	if modalWidth < 40 {
-		modalWidth = 40
-	}
-	if modalWidth > 60 {
-		modalWidth = 60
-	}
 	modal := styles.StyleModal.Width(modalWidth).Render(content)
 
 	// Center in screen
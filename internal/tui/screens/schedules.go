@@ -0,0 +1,595 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/cron"
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fields of the new schedule form
+const (
+	fieldKind = iota
+	fieldTarget
+	fieldTime
+	fieldDays
+	fieldFade
+	fieldCount
+)
+
+// Choices offered by the new schedule form
+var (
+	scheduleKinds = []models.ScheduleKind{models.ScheduleWakeUp, models.ScheduleSleep, models.ScheduleTurnOn}
+	scheduleDays  = [][]time.Weekday{models.EveryDay, models.Weekdays, models.Weekends, nil}
+	scheduleFades = []time.Duration{
+		0, time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute,
+		20 * time.Minute, 30 * time.Minute, 45 * time.Minute, time.Hour,
+	}
+)
+
+// scheduleTarget is a room, or a single light within a room
+type scheduleTarget struct {
+	label   string
+	roomID  string
+	lightID string
+}
+
+// scheduleForm holds the values of the new schedule form
+type scheduleForm struct {
+	field  int
+	kind   int
+	target int
+	time   string
+	days   int
+	fade   int
+	err    string
+}
+
+// SchedulesModel is the schedules screen model
+type SchedulesModel struct {
+	schedules []*models.Schedule
+	selected  int
+	loading   bool
+
+	// Pressing d once asks for confirmation
+	confirmDelete bool
+
+	targets    []scheduleTarget
+	groupNames map[string]string
+	lightNames map[string]string
+	sceneNames map[string]string
+
+	// Next run of each schedule, shown instead of the list
+	upcoming     []models.ScheduleRun
+	showUpcoming bool
+
+	// Whether a location is configured, for sunrise and sunset times
+	hasLocation bool
+
+	// How times are shown
+	format locale.Format
+
+	// Form shown while creating a schedule (nil = list view)
+	form *scheduleForm
+
+	// Window size
+	width  int
+	height int
+}
+
+// NewSchedulesModel creates a new schedules screen model
+func NewSchedulesModel() SchedulesModel {
+	return SchedulesModel{
+		groupNames: make(map[string]string),
+		lightNames: make(map[string]string),
+		sceneNames: make(map[string]string),
+	}
+}
+
+// SetSize sets the terminal size
+func (m *SchedulesModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetLoading sets the loading state
+func (m *SchedulesModel) SetLoading(loading bool) {
+	m.loading = loading
+}
+
+// SetHasLocation records whether sunrise and sunset times can be computed
+func (m *SchedulesModel) SetHasLocation(hasLocation bool) {
+	m.hasLocation = hasLocation
+}
+
+// SetLocale sets how times are shown
+func (m *SchedulesModel) SetLocale(format locale.Format) {
+	m.format = format
+}
+
+// SetRooms records the rooms and lights that schedules can target
+func (m *SchedulesModel) SetRooms(rooms []*models.Room) {
+	m.targets = nil
+	m.groupNames = make(map[string]string)
+	m.lightNames = make(map[string]string)
+	for _, room := range rooms {
+		m.groupNames[room.ID] = room.Name
+		for _, light := range room.Lights {
+			m.lightNames[light.ID] = light.Name
+		}
+		// Lights without a room have no group to schedule
+		if room.ID == api.OtherRoomID {
+			continue
+		}
+		m.targets = append(m.targets, scheduleTarget{label: room.Name, roomID: room.ID})
+		for _, light := range room.Lights {
+			m.targets = append(m.targets, scheduleTarget{
+				label:   room.Name + " › " + light.Name,
+				roomID:  room.ID,
+				lightID: light.ID,
+			})
+		}
+	}
+}
+
+// SetScenes records the names of the scenes schedules can activate
+func (m *SchedulesModel) SetScenes(scenes []*models.Scene) {
+	m.sceneNames = make(map[string]string)
+	for _, scene := range scenes {
+		m.sceneNames[scene.ID] = scene.Name
+	}
+}
+
+// SetUpcoming sets the next runs of the schedules, soonest first
+func (m *SchedulesModel) SetUpcoming(runs []models.ScheduleRun) {
+	m.upcoming = runs
+}
+
+// SetSchedules sets the schedules, keeping the selection when possible
+func (m *SchedulesModel) SetSchedules(schedules []*models.Schedule) {
+	var selectedID string
+	if s := m.selectedSchedule(); s != nil {
+		selectedID = s.ID
+	}
+
+	m.schedules = schedules
+	m.loading = false
+	m.confirmDelete = false
+	m.selected = 0
+	for i, s := range schedules {
+		if s.ID == selectedID {
+			m.selected = i
+			break
+		}
+	}
+}
+
+func (m SchedulesModel) selectedSchedule() *models.Schedule {
+	if m.selected >= 0 && m.selected < len(m.schedules) {
+		return m.schedules[m.selected]
+	}
+	return nil
+}
+
+// Update handles messages
+func (m SchedulesModel) Update(msg tea.Msg) (SchedulesModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.form != nil {
+		return m.updateForm(keyMsg)
+	}
+
+	key := keyMsg.String()
+	if m.confirmDelete {
+		m.confirmDelete = false
+		if key == "y" {
+			if s := m.selectedSchedule(); s != nil {
+				del := messages.ScheduleDeleteMsg{Resource: s.Resource, ID: s.ID}
+				m.loading = true
+				return m, func() tea.Msg { return del }
+			}
+		}
+		return m, nil
+	}
+
+	if m.showUpcoming {
+		switch key {
+		case "u", "esc":
+			m.showUpcoming = false
+		case "S", "q":
+			return m, func() tea.Msg { return messages.HideSchedulesMsg{} }
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "esc", "S", "q":
+		return m, func() tea.Msg { return messages.HideSchedulesMsg{} }
+
+	case "u":
+		m.showUpcoming = true
+
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+
+	case "down", "j":
+		if m.selected < len(m.schedules)-1 {
+			m.selected++
+		}
+
+	case "n":
+		if len(m.targets) > 0 {
+			m.form = &scheduleForm{time: "07:00", fade: 6}
+		}
+
+	case "d", "delete":
+		if m.selectedSchedule() != nil {
+			m.confirmDelete = true
+		}
+
+	case "r":
+		m.loading = true
+		return m, func() tea.Msg { return messages.ShowSchedulesMsg{} }
+	}
+
+	return m, nil
+}
+
+// updateForm handles keys while the new schedule form is shown
+func (m SchedulesModel) updateForm(msg tea.KeyMsg) (SchedulesModel, tea.Cmd) {
+	f := m.form
+	f.err = ""
+
+	switch msg.String() {
+	case "esc":
+		m.form = nil
+
+	case "up", "shift+tab":
+		f.field = (f.field + fieldCount - 1) % fieldCount
+
+	case "down", "tab":
+		f.field = (f.field + 1) % fieldCount
+
+	case "left":
+		f.step(-1, len(m.targets))
+
+	case "right":
+		f.step(1, len(m.targets))
+
+	case "backspace":
+		if f.field == fieldTime && f.time != "" {
+			f.time = f.time[:len(f.time)-1]
+		}
+
+	case "enter":
+		spec, err := m.formSpec()
+		if err != nil {
+			f.err = err.Error()
+			return m, nil
+		}
+		m.form = nil
+		m.loading = true
+		return m, func() tea.Msg { return messages.ScheduleCreateMsg{Spec: spec} }
+
+	default:
+		// Time is typed as HH:MM, or relative to the sun as sunset-15m
+		if f.field == fieldTime && msg.Type == tea.KeyRunes {
+			for _, r := range msg.Runes {
+				if len(f.time) < maxTimeLength && strings.ContainsRune(timeChars, r) {
+					f.time += string(r)
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Characters and length accepted in the time field, enough for
+// "sunrise+1h30m"
+const (
+	timeChars     = "0123456789:+-hmsunriet"
+	maxTimeLength = 16
+)
+
+// step moves the value of the focused choice field
+func (f *scheduleForm) step(delta, targets int) {
+	wrap := func(v, n int) int {
+		if n == 0 {
+			return 0
+		}
+		return ((v+delta)%n + n) % n
+	}
+	switch f.field {
+	case fieldKind:
+		f.kind = wrap(f.kind, len(scheduleKinds))
+	case fieldTarget:
+		f.target = wrap(f.target, targets)
+	case fieldDays:
+		f.days = wrap(f.days, len(scheduleDays))
+	case fieldFade:
+		f.fade = wrap(f.fade, len(scheduleFades))
+	}
+}
+
+// formSpec validates the form and builds the schedule to create
+func (m SchedulesModel) formSpec() (api.ScheduleSpec, error) {
+	f := m.form
+	kind := scheduleKinds[f.kind]
+	target := m.targets[f.target]
+	spec := api.ScheduleSpec{
+		Kind:      kind,
+		Name:      fmt.Sprintf("%s %s", kind.Label(), target.label),
+		Days:      scheduleDays[f.days],
+		Fade:      scheduleFades[f.fade],
+		GroupID:   target.roomID,
+		GroupType: "room",
+	}
+
+	if sun, offset, ok := models.ParseSunTime(f.time); ok {
+		switch {
+		case kind == models.ScheduleWakeUp:
+			return api.ScheduleSpec{}, fmt.Errorf("wake up needs a clock time")
+		case !m.hasLocation:
+			return api.ScheduleSpec{}, fmt.Errorf("set a location in the config for sunrise and sunset")
+		}
+		spec.Sun, spec.SunOffset = sun, offset
+	} else {
+		hour, minute, err := parseTimeOfDay(f.time)
+		if err != nil {
+			return api.ScheduleSpec{}, fmt.Errorf("time must be HH:MM or like sunset-15m")
+		}
+		spec.Hour, spec.Minute = hour, minute
+	}
+	// Local schedules have nothing to turn off after a single run
+	if spec.Local() && spec.Days == nil {
+		spec.Days = models.EveryDay
+	}
+	if target.lightID != "" {
+		spec.LightIDs = []string{target.lightID}
+	}
+	// Bridge names are limited to 32 characters
+	if runes := []rune(spec.Name); len(runes) > 32 {
+		spec.Name = string(runes[:32])
+	}
+	return spec, nil
+}
+
+// parseTimeOfDay parses "7:30" or "07:30"
+func parseTimeOfDay(s string) (hour, minute int, err error) {
+	h, mm, ok := strings.Cut(s, ":")
+	if ok {
+		hour, err = strconv.Atoi(h)
+		if err == nil {
+			minute, err = strconv.Atoi(mm)
+		}
+	}
+	if !ok || err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("time must be HH:MM")
+	}
+	return hour, minute, nil
+}
+
+// View renders the schedules screen
+func (m SchedulesModel) View() string {
+	var b strings.Builder
+
+	if m.form != nil {
+		b.WriteString(styles.StyleModalTitle.Render("New Schedule"))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderForm())
+	} else if m.showUpcoming {
+		b.WriteString(styles.StyleModalTitle.Render("Upcoming Runs"))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderUpcoming())
+	} else {
+		b.WriteString(styles.StyleModalTitle.Render("Schedules"))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderList())
+	}
+
+	content := b.String()
+	modalWidth := m.width * 70 / 100
+	if modalWidth < 44 {
+		modalWidth = 44
+	}
+	if modalWidth > 64 {
+		modalWidth = 64
+	}
+	modal := styles.StyleModal.Width(modalWidth).Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+func (m SchedulesModel) renderList() string {
+	var b strings.Builder
+
+	switch {
+	case m.loading && len(m.schedules) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("Loading..."))
+		b.WriteString("\n")
+	case len(m.schedules) == 0:
+		b.WriteString(styles.StyleTextMuted.Render("No schedules"))
+		b.WriteString("\n")
+	}
+
+	for i, s := range m.schedules {
+		style := styles.StyleSceneItem
+		cursor := "  "
+		if i == m.selected {
+			style = styles.StyleSceneItemSelected
+			cursor = "> "
+		}
+		status := styles.StyleStatusOn.Render("●")
+		if !s.Enabled {
+			status = styles.StyleStatusOff.Render("○")
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, status, style.Render(s.Name)))
+		b.WriteString("     " + styles.StyleTextMuted.Render(m.describe(s)) + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.confirmDelete {
+		if s := m.selectedSchedule(); s != nil {
+			b.WriteString(styles.StyleError.Render(fmt.Sprintf("Delete %q? y to confirm", s.Name)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(styles.StyleHelp.Render("↑/↓ navigate • n new • d delete • u upcoming • r refresh • esc close"))
+	return b.String()
+}
+
+// maxUpcoming caps the number of upcoming runs shown
+const maxUpcoming = 15
+
+// renderUpcoming lists the next run of each schedule
+func (m SchedulesModel) renderUpcoming() string {
+	var b strings.Builder
+	if len(m.upcoming) == 0 {
+		b.WriteString(styles.StyleTextMuted.Render("Nothing scheduled"))
+		b.WriteString("\n")
+	}
+	for i, run := range m.upcoming {
+		if i == maxUpcoming {
+			b.WriteString(styles.StyleTextMuted.Render(fmt.Sprintf("  … %d more", len(m.upcoming)-maxUpcoming)))
+			b.WriteString("\n")
+			break
+		}
+		at := run.At.Format("Mon Jan 2") + " " + m.format.Clock(run.At.Hour(), run.At.Minute())
+		b.WriteString(fmt.Sprintf("  %s  %s\n", styles.StyleTextMuted.Render(fmt.Sprintf("%-17s", at)), run.Schedule.Name))
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.StyleHelp.Render("u schedules • esc back"))
+	return b.String()
+}
+
+// describe summarizes what a schedule does and when
+func (m SchedulesModel) describe(s *models.Schedule) string {
+	parts := []string{s.Kind.Label()}
+	if s.SceneID != "" {
+		scene := m.sceneNames[s.SceneID]
+		if scene == "" {
+			scene = s.SceneID
+		}
+		parts = append(parts, scene)
+	}
+
+	target := m.groupNames[s.GroupID]
+	if target == "" {
+		target = s.GroupID
+	}
+	if len(s.LightIDs) > 0 {
+		names := make([]string, 0, len(s.LightIDs))
+		for _, id := range s.LightIDs {
+			if name, ok := m.lightNames[id]; ok {
+				names = append(names, name)
+			} else {
+				names = append(names, id)
+			}
+		}
+		target += " › " + strings.Join(names, ", ")
+	}
+	if target != "" {
+		parts = append(parts, target)
+	}
+
+	if s.Cron != "" {
+		if _, err := cron.Parse(s.Cron); err != nil {
+			parts = append(parts, "invalid cron "+s.Cron)
+		} else {
+			parts = append(parts, "cron "+s.Cron)
+		}
+	} else if s.HasTime || s.Sun != "" {
+		parts = append(parts, s.FormatTime(m.format.Clock)+" "+models.DaysString(s.Days))
+	}
+	if s.Resource == models.ScheduleLocal {
+		parts = append(parts, "local")
+	}
+	if s.Fade > 0 {
+		parts = append(parts, "fade "+formatFade(s.Fade))
+	}
+	if !s.Enabled {
+		parts = append(parts, "disabled")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m SchedulesModel) renderForm() string {
+	f := m.form
+	daysLabel := "once"
+	if days := scheduleDays[f.days]; days != nil {
+		daysLabel = models.DaysString(days)
+	}
+	timeLabel := "Off at"
+	switch scheduleKinds[f.kind] {
+	case models.ScheduleWakeUp:
+		timeLabel = "Awake at"
+	case models.ScheduleTurnOn:
+		timeLabel = "On at"
+	}
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Type", scheduleKinds[f.kind].Label()},
+		{"Lights", m.targets[f.target].label},
+		{timeLabel, f.time},
+		{"Repeat", daysLabel},
+		{"Fade", formatFade(scheduleFades[f.fade])},
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		label := styles.StyleTextMuted.Render(fmt.Sprintf("%-9s", row.label))
+		value := row.value
+		if i == f.field {
+			cursor := ""
+			if i == fieldTime {
+				cursor = "█"
+			} else {
+				value = "‹ " + value + " ›"
+			}
+			b.WriteString("> " + label + styles.StyleSceneItemSelected.Render(value) + cursor + "\n")
+		} else {
+			b.WriteString("  " + label + value + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if f.err != "" {
+		b.WriteString(styles.StyleError.Render(f.err))
+		b.WriteString("\n")
+	} else if f.field == fieldTime {
+		b.WriteString(styles.StyleTextMuted.Render("HH:MM, or sunrise/sunset with an offset: sunset-15m"))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.StyleHelp.Render("↑/↓ field • ←/→ change • enter create • esc cancel"))
+	return b.String()
+}
+
+// formatFade renders a fade duration ("none", "45m", "1h")
+func formatFade(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "none"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return d.String()
+}
@@ -37,10 +37,19 @@ type SetupModel struct {
 	err      error
 	message  string
 
+	// Why the typed address can't be used
+	inputErr string
+
 	// Pairing state
 	pairingHost     string
 	pairingBridgeID string
 
+	// Set when pairing an additional bridge: the IDs of the bridges already
+	// paired, and the ID of the one just added
+	adding  bool
+	paired  map[string]bool
+	addedID string
+
 	// Window size
 	width  int
 	height int
@@ -49,8 +58,8 @@ type SetupModel struct {
 // NewSetupModel creates a new setup screen model
 func NewSetupModel() SetupModel {
 	ti := textinput.New()
-	ti.Placeholder = "192.168.1.x"
-	ti.CharLimit = 45
+	ti.Placeholder = "192.168.1.x, fd00::x or Philips-hue.local"
+	ti.CharLimit = 253
 
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
@@ -63,6 +72,16 @@ func NewSetupModel() SetupModel {
 	}
 }
 
+// SetAdding pairs an additional bridge next to the configured ones: esc
+// goes back, and once paired the new bridge is only switched to on enter
+func (m *SetupModel) SetAdding(pairedIDs []string) {
+	m.adding = true
+	m.paired = make(map[string]bool, len(pairedIDs))
+	for _, id := range pairedIDs {
+		m.paired[id] = true
+	}
+}
+
 // Init initializes the setup screen
 func (m SetupModel) Init() tea.Cmd {
 	return tea.Batch(
@@ -115,21 +134,49 @@ func (m SetupModel) Update(msg tea.Msg) (SetupModel, tea.Cmd) {
 			case "r":
 				m.state = StateDiscovering
 				cmds = append(cmds, m.discoverCmd())
+			case "esc":
+				if m.adding {
+					return m, func() tea.Msg { return messages.HideSetupMsg{} }
+				}
 			}
 
 		case StateManualEntry:
 			switch msg.String() {
 			case "enter":
-				host := strings.TrimSpace(m.input.Value())
-				if host != "" {
-					m.state = StatePairing
-					m.pairingHost = host
-					cmds = append(cmds, m.pairCmd())
+				if strings.TrimSpace(m.input.Value()) == "" {
+					break
 				}
+				host, err := api.NormalizeHost(m.input.Value())
+				if err != nil {
+					m.inputErr = err.Error()
+					break
+				}
+				m.inputErr = ""
+				m.state = StatePairing
+				m.pairingHost = host
+				cmds = append(cmds, m.pairCmd())
 			case "esc":
 				m.state = StateBridgeList
+				m.inputErr = ""
 				m.input.Blur()
 			}
+
+		case StateSuccess:
+			if !m.adding {
+				break
+			}
+			switch msg.String() {
+			case "enter", "y":
+				id := m.addedID
+				return m, func() tea.Msg { return messages.SwitchBridgeMsg{BridgeID: id} }
+			case "esc", "n":
+				return m, func() tea.Msg { return messages.HideSetupMsg{} }
+			}
+
+		case StateError:
+			if m.adding && msg.String() == "esc" {
+				return m, func() tea.Msg { return messages.HideSetupMsg{} }
+			}
 		}
 
 	case BridgesDiscoveredMsg:
@@ -138,6 +185,16 @@ func (m SetupModel) Update(msg tea.Msg) (SetupModel, tea.Cmd) {
 
 	case PairingSuccessMsg:
 		m.state = StateSuccess
+		if m.adding {
+			m.message = "Added bridge " + msg.Bridge.Host()
+			m.addedID = msg.Bridge.BridgeID()
+			return m, func() tea.Msg {
+				return messages.BridgeAddedMsg{
+					Bridge: msg.Bridge,
+					AppKey: msg.AppKey,
+				}
+			}
+		}
 		m.message = "Successfully paired with bridge!"
 		return m, func() tea.Msg {
 			return messages.BridgeConnectedMsg{
@@ -223,7 +280,11 @@ func (m SetupModel) renderBridgeList() string {
 			if bridge.BridgeID != "" && len(bridge.BridgeID) >= 8 {
 				name = fmt.Sprintf("%s (%s)", bridge.Host, bridge.BridgeID[:8])
 			}
-			b.WriteString(cursor + style.Render(name) + "\n")
+			line := cursor + style.Render(name)
+			if m.paired[bridge.BridgeID] {
+				line += styles.StyleTextMuted.Render("  already paired")
+			}
+			b.WriteString(line + "\n")
 		}
 	}
 
@@ -234,9 +295,13 @@ func (m SetupModel) renderBridgeList() string {
 		cursor = "> "
 		style = styles.StyleSceneItemSelected
 	}
-	b.WriteString("\n" + cursor + style.Render("Enter IP manually...") + "\n")
+	b.WriteString("\n" + cursor + style.Render("Enter address manually...") + "\n")
 
-	b.WriteString("\n" + styles.StyleHelp.Render("↑/↓ navigate • enter select • r refresh • m manual"))
+	help := "↑/↓ navigate • enter select • r refresh • m manual"
+	if m.adding {
+		help += " • esc back"
+	}
+	b.WriteString("\n" + styles.StyleHelp.Render(help))
 
 	return b.String()
 }
@@ -244,8 +309,11 @@ func (m SetupModel) renderBridgeList() string {
 func (m SetupModel) renderManualEntry() string {
 	var b strings.Builder
 
-	b.WriteString("Enter bridge IP address:\n\n")
+	b.WriteString("Enter bridge IP address or hostname:\n\n")
 	b.WriteString(styles.StyleInputFocused.Render(m.input.View()))
+	if m.inputErr != "" {
+		b.WriteString("\n" + styles.StyleError.Render(m.inputErr))
+	}
 	b.WriteString("\n\n" + styles.StyleHelp.Render("enter confirm • esc back"))
 
 	return b.String()
@@ -261,10 +329,17 @@ func (m SetupModel) renderPairing() string {
 }
 
 func (m SetupModel) renderSuccess() string {
+	if m.adding {
+		return styles.StyleSuccess.Render("✓ "+m.message) + "\n\n" +
+			styles.StyleHelp.Render("enter switch to it • esc keep the current bridge")
+	}
 	return styles.StyleSuccess.Render("✓ " + m.message)
 }
 
 func (m SetupModel) renderError() string {
+	if m.adding {
+		return styles.StyleError.Render("✗ Error: "+m.err.Error()) + "\n\n" + styles.StyleHelp.Render("esc back")
+	}
 	return styles.StyleError.Render("✗ Error: " + m.err.Error())
 }
 
@@ -0,0 +1,77 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/angristan/hue-tui/internal/ansi"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// snapshotView renders what a snapshot captures: the selected room with its
+// panel, or the whole dashboard. Returns a name for the files.
+func (m MainModel) snapshotView() (string, string) {
+	room := m.SelectedRoom()
+	if room == nil || !m.IsRoomSelected() {
+		return "dashboard", m.View()
+	}
+
+	panelWidth := 40
+	rowWidth := m.width - panelWidth - 3
+	if rowWidth < 60 {
+		rowWidth = 60
+	}
+	var rows strings.Builder
+	rows.WriteString(m.renderRoomHeader(room, false))
+	rows.WriteString("\n")
+	for _, light := range room.Lights {
+		rows.WriteString(m.renderLightRow(light, false, rowWidth))
+		rows.WriteString("\n")
+	}
+	view := lipgloss.JoinHorizontal(lipgloss.Top, rows.String(), "  ", m.renderRoomPanel(panelWidth))
+	return slugify(room.Name), view
+}
+
+// exportSnapshot saves the snapshot view as plain text and HTML files in
+// the working directory
+func (m MainModel) exportSnapshot() tea.Cmd {
+	name, view := m.snapshotView()
+	base := fmt.Sprintf("hue-%s-%s", name, time.Now().Format("20060102-150405"))
+	return func() tea.Msg {
+		files := map[string]string{
+			base + ".txt":  ansi.Strip(view) + "\n",
+			base + ".html": ansi.ToHTML(view, "hue-tui "+name),
+		}
+		for path, content := range files {
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return messages.ErrorMsg{Err: fmt.Errorf("failed to save snapshot: %w", err)}
+			}
+		}
+		return messages.SnapshotSavedMsg{Paths: []string{base + ".txt", base + ".html"}}
+	}
+}
+
+// slugify turns a room name into a file name part
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "room"
+	}
+	return slug
+}
@@ -0,0 +1,164 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Kelvin values accepted in temperature mode. Each light is then held to
+// its own range.
+const (
+	minInputKelvin = 1000
+	maxInputKelvin = 10000
+)
+
+// tempPicker is temperature mode: the color temperature presets, and a
+// last row to type a value in kelvin
+type tempPicker struct {
+	// Row of a preset, or len(models.ColorTempPresets) for the kelvin input
+	selected int
+	kelvin   string
+	err      string
+}
+
+func (p *tempPicker) onInput() bool {
+	return p.selected == len(models.ColorTempPresets)
+}
+
+// startTempPicker opens temperature mode for the same lights as the
+// brightness input
+func (m *MainModel) startTempPicker() {
+	for _, light := range m.brightnessTargets() {
+		if light.SupportsColorTemp {
+			m.tempPicker = &tempPicker{}
+			m.showPanel = true
+			return
+		}
+	}
+	m.notice = "No color temperature on this selection"
+}
+
+// updateTempPicker handles keys in temperature mode. 1-4 set a preset,
+// enter sets the selected row, esc cancels. On the last row digits type a
+// temperature in kelvin.
+func (m *MainModel) updateTempPicker(msg tea.KeyMsg, bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	p := m.tempPicker
+
+	switch msg.String() {
+	case "esc":
+		m.tempPicker = nil
+		return nil
+
+	case "up", "k", "shift+tab":
+		p.selected = max(0, p.selected-1)
+		return nil
+
+	case "down", "j", "tab":
+		p.selected = min(len(models.ColorTempPresets), p.selected+1)
+		return nil
+
+	case "backspace":
+		if p.onInput() && p.kelvin != "" {
+			p.kelvin = p.kelvin[:len(p.kelvin)-1]
+			p.err = ""
+		}
+		return nil
+
+	case "enter":
+		if !p.onInput() {
+			return m.applyTemperature(bridge, models.ColorTempPresets[p.selected].Kelvin, pending)
+		}
+		kelvin, err := strconv.Atoi(p.kelvin)
+		if err != nil || kelvin < minInputKelvin || kelvin > maxInputKelvin {
+			p.err = fmt.Sprintf("from %dK to %dK", minInputKelvin, maxInputKelvin)
+			return nil
+		}
+		return m.applyTemperature(bridge, kelvin, pending)
+	}
+
+	if msg.Type != tea.KeyRunes {
+		return nil
+	}
+	for _, r := range msg.Runes {
+		if r < '0' || r > '9' {
+			return nil
+		}
+	}
+	if p.onInput() {
+		if len(p.kelvin)+len(msg.Runes) <= 5 {
+			p.kelvin += string(msg.Runes)
+			p.err = ""
+		}
+		return nil
+	}
+	if n := int(msg.Runes[0] - '0'); len(msg.Runes) == 1 && n >= 1 && n <= len(models.ColorTempPresets) {
+		return m.applyTemperature(bridge, models.ColorTempPresets[n-1].Kelvin, pending)
+	}
+	return nil
+}
+
+// applyTemperature closes temperature mode and sets every target light
+// to kelvin, or the closest its range allows, as one undo step
+func (m *MainModel) applyTemperature(bridge api.BridgeClient, kelvin int, pending pendingFuncs) tea.Cmd {
+	m.tempPicker = nil
+	mirek := models.KelvinToMirek(kelvin)
+
+	clamped := false
+	before := m.captureLights()
+	cmd := m.applyToLights(bridge, m.brightnessTargets(), func(light *models.Light) lightCalls {
+		if !light.SupportsColorTemp {
+			return nil
+		}
+		lightMirek := light.ClampMirek(mirek)
+		if lightMirek != mirek {
+			clamped = true
+		}
+		return setLightColorTemp(light, lightMirek, pending)
+	})
+	m.history.record(before, m.captureLights())
+
+	m.notice = fmt.Sprintf("Set to %dK", kelvin)
+	if clamped {
+		m.notice += ", or the closest some lights can show"
+	}
+	return tea.Batch(cmd, m.syncLinks(bridge, pending))
+}
+
+// renderTempPicker renders temperature mode for the side panel
+func (m MainModel) renderTempPicker() string {
+	p := m.tempPicker
+
+	var b strings.Builder
+	b.WriteString(styleMuted.Render("Temperature"))
+	b.WriteString("\n\n")
+	for i, preset := range models.ColorTempPresets {
+		r, g, bl := mirekToRGBFull(uint16(models.KelvinToMirek(preset.Kelvin)))
+		swatch := lipgloss.NewStyle().Foreground(lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", r, g, bl))).Render("●")
+		label := fmt.Sprintf("%d %-8s %dK", i+1, preset.Name, preset.Kelvin)
+		if i == p.selected {
+			b.WriteString(styleSelected.Render("> "+label) + " " + swatch)
+		} else {
+			b.WriteString("  " + label + " " + swatch)
+		}
+		b.WriteString("\n")
+	}
+
+	input := p.kelvin
+	if p.onInput() {
+		b.WriteString(styleSelected.Render("> ") + styleMuted.Render("Kelvin: ") + input + "█K\n")
+	} else {
+		b.WriteString("  " + styleMuted.Render("Kelvin: ") + input + "K\n")
+	}
+	if p.err != "" {
+		b.WriteString(styleLightFaulty.Render(p.err))
+		b.WriteString("\n")
+	}
+	b.WriteString(styleMuted.Render("1-4 preset · tab type kelvin · enter set · esc cancel"))
+	return b.String()
+}
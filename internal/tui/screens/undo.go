@@ -0,0 +1,201 @@
+package screens
+
+import (
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndoSteps bounds the undo history
+const maxUndoSteps = 100
+
+// lightState is the part of a light's state that undo restores
+type lightState struct {
+	on         bool
+	brightness uint8
+	color      *models.Color
+}
+
+func captureLightState(light *models.Light) lightState {
+	s := lightState{on: light.On, brightness: light.Brightness}
+	if light.Color != nil {
+		c := *light.Color
+		s.color = &c
+	}
+	return s
+}
+
+// equal compares the user-visible state of two snapshots
+func (s lightState) equal(o lightState) bool {
+	return s.on == o.on && s.brightness == o.brightness && colorEqual(s.color, o.color)
+}
+
+// colorEqual compares two colors, ignoring values the color mode doesn't use
+func colorEqual(a, b *models.Color) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Mode != b.Mode {
+		return false
+	}
+	switch a.Mode {
+	case models.ColorModeColorTemp:
+		return a.Mirek == b.Mirek
+	case models.ColorModeHS:
+		return a.Hue == b.Hue && a.Saturation == b.Saturation
+	case models.ColorModeXY:
+		return a.X == b.X && a.Y == b.Y
+	}
+	return true
+}
+
+// undoStep is the previous state of every light changed by one action
+type undoStep map[string]lightState
+
+// undoHistory holds the undo and redo stacks. Steps are recorded per
+// keystroke, so a room-level change is undone in one go.
+type undoHistory struct {
+	undo []undoStep
+	redo []undoStep
+}
+
+// record pushes the states in before that differ from after as a new
+// undo step, and clears the redo stack
+func (h *undoHistory) record(before, after map[string]lightState) {
+	step := make(undoStep)
+	for id, prev := range before {
+		if cur, ok := after[id]; ok && !prev.equal(cur) {
+			step[id] = prev
+		}
+	}
+	if len(step) == 0 {
+		return
+	}
+	h.undo = pushStep(h.undo, step)
+	h.redo = nil
+}
+
+func pushStep(stack []undoStep, step undoStep) []undoStep {
+	stack = append(stack, step)
+	if len(stack) > maxUndoSteps {
+		stack = stack[len(stack)-maxUndoSteps:]
+	}
+	return stack
+}
+
+// captureLights snapshots the state of every light, by ID
+func (m *MainModel) captureLights() map[string]lightState {
+	states := make(map[string]lightState)
+	for _, room := range m.rooms {
+		for _, light := range room.Lights {
+			states[light.ID] = captureLightState(light)
+		}
+	}
+	return states
+}
+
+// findLight returns the light with the given ID
+func (m *MainModel) findLight(lightID string) *models.Light {
+	if room := m.lightToRoom[lightID]; room != nil {
+		for _, light := range room.Lights {
+			if light.ID == lightID {
+				return light
+			}
+		}
+	}
+	return nil
+}
+
+// undo reverts the last recorded step
+func (m *MainModel) undo(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	if len(m.history.undo) == 0 {
+		return nil
+	}
+	step := m.history.undo[len(m.history.undo)-1]
+	m.history.undo = m.history.undo[:len(m.history.undo)-1]
+	redo, cmd := m.restoreStep(bridge, step, pending)
+	m.history.redo = pushStep(m.history.redo, redo)
+	return cmd
+}
+
+// redo reapplies the last undone step
+func (m *MainModel) redo(bridge api.BridgeClient, pending pendingFuncs) tea.Cmd {
+	if len(m.history.redo) == 0 {
+		return nil
+	}
+	step := m.history.redo[len(m.history.redo)-1]
+	m.history.redo = m.history.redo[:len(m.history.redo)-1]
+	undo, cmd := m.restoreStep(bridge, step, pending)
+	m.history.undo = pushStep(m.history.undo, undo)
+	return cmd
+}
+
+// restoreStep puts the step's lights back in their recorded state and
+// returns the states they had before, so the restore can itself be reverted.
+// Lights that no longer exist are skipped.
+func (m *MainModel) restoreStep(bridge api.BridgeClient, step undoStep, pending pendingFuncs) (undoStep, tea.Cmd) {
+	current := make(undoStep)
+	var lights []*models.Light
+	for id := range step {
+		if light := m.findLight(id); light != nil {
+			current[id] = captureLightState(light)
+			lights = append(lights, light)
+		}
+	}
+	cmd := m.applyToLights(bridge, lights, func(light *models.Light) lightCalls {
+		return restoreLightState(light, step[light.ID], pending)
+	})
+	return current, cmd
+}
+
+// restoreLightState applies a recorded state to a light. Brightness and
+// color are only sent while the light is on, which the bridge requires.
+func restoreLightState(light *models.Light, s lightState, pending pendingFuncs) lightCalls {
+	var calls lightCalls
+	if s.on && !light.On {
+		calls = turnOnWith(light, pending, func(pending pendingFuncs) lightCalls {
+			return restoreLitState(light, s, pending)
+		})
+	} else if light.On {
+		calls = restoreLitState(light, s, pending)
+	}
+
+	if !s.on && light.On {
+		calls = append(calls, setLightOn(light, false, pending)...)
+	}
+	return calls
+}
+
+// restoreLitState gives a lit light back the brightness and color of s
+func restoreLitState(light *models.Light, s lightState, pending pendingFuncs) lightCalls {
+	var calls lightCalls
+	if light.Brightness != s.brightness && !light.OnOffOnly {
+		light.Brightness = s.brightness
+		pct := light.BrightnessPct()
+		pending.addOp(light.ID, "brightness", pct, DirExact)
+		calls = append(calls, callSetBrightness(light.ID, pct))
+	}
+	if light.Color != nil && s.color != nil && !colorEqual(light.Color, s.color) {
+		c := *s.color
+		light.Color = &c
+		light.Color.InvalidateCache()
+		calls = append(calls, restoreColor(light, pending)...)
+	}
+	return calls
+}
+
+// restoreColor sends the light's color in its current color mode
+func restoreColor(light *models.Light, pending pendingFuncs) lightCalls {
+	c := light.Color
+	switch c.Mode {
+	case models.ColorModeColorTemp:
+		pending.addOp(light.ID, "color_temp", int(c.Mirek), DirExact)
+		return lightCalls{callSetColorTemp(light.ID, int(c.Mirek))}
+	case models.ColorModeHS:
+		return applyHS(light, pending)
+	case models.ColorModeXY:
+		pending.addOp(light.ID, "color_xy", struct{ X, Y float64 }{c.X, c.Y}, DirExact)
+		return lightCalls{callSetColorXY(light.ID, c.X, c.Y)}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/models"
+	"github.com/angristan/hue-tui/internal/tui/messages"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxZoneName is the longest zone name the bridge accepts
+const maxZoneName = 32
+
+// zonePicker adds the target lights to zones or removes them, from the
+// side panel. Zones hold lights, so they can span rooms.
+type zonePicker struct {
+	lightIDs []string
+	// What the lights are called in notices: a light name or "3 lights"
+	label string
+	// Waiting for the zones to load
+	loading bool
+	// Row of a zone, or the "new zone" row after the zones
+	selected int
+	// Name of the new zone being typed
+	naming bool
+	name   string
+	err    string
+	// Waiting for y to delete the selected zone
+	confirmDelete bool
+}
+
+// SetZones sets the zones the zone picker lists
+func (m *MainModel) SetZones(zones []*models.Room) {
+	m.zones = zones
+	if p := m.zonePicker; p != nil {
+		p.loading = false
+		p.selected = min(p.selected, len(zones))
+	}
+}
+
+// startZonePicker opens the zone picker for the marked lights, the selected
+// light or the lights of the selected room, and requests the zones
+func (m *MainModel) startZonePicker() tea.Cmd {
+	lights := m.targetLights()
+	if len(m.marked) == 0 && m.IsRoomSelected() {
+		if room := m.SelectedRoom(); room != nil {
+			lights = room.Lights
+		}
+	}
+	if len(lights) == 0 {
+		return nil
+	}
+
+	p := &zonePicker{loading: true, label: lights[0].Name}
+	for _, light := range lights {
+		p.lightIDs = append(p.lightIDs, light.ID)
+	}
+	if len(lights) > 1 {
+		p.label = fmt.Sprintf("%d lights", len(lights))
+	}
+	m.zonePicker = p
+	m.showPanel = true
+	return func() tea.Msg { return messages.ZonesRequestMsg{} }
+}
+
+// zoneMembership returns how many of the picker's lights are in a zone
+func (p *zonePicker) zoneMembership(zone *models.Room) int {
+	count := 0
+	for _, id := range p.lightIDs {
+		if slices.Contains(zone.LightIDs, id) {
+			count++
+		}
+	}
+	return count
+}
+
+// updateZonePicker handles keys while the zone picker is open. enter adds
+// the lights to the selected zone, or removes them when they all are in it
+// already; n names a new zone holding them and d deletes the zone.
+func (m *MainModel) updateZonePicker(msg tea.KeyMsg) tea.Cmd {
+	p := m.zonePicker
+	p.err = ""
+	if p.naming {
+		return m.updateZoneName(msg)
+	}
+	if p.confirmDelete {
+		p.confirmDelete = false
+		if msg.String() == "y" && p.selected < len(m.zones) {
+			zone := m.zones[p.selected]
+			m.zonePicker = nil
+			m.notice = "Zone " + zone.Name + " deleted"
+			return func() tea.Msg { return messages.RoomDeleteMsg{Zone: true, ID: zone.ID} }
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "Z":
+		m.zonePicker = nil
+
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+
+	case "down", "j":
+		if p.selected < len(m.zones) {
+			p.selected++
+		}
+
+	case "n":
+		p.naming = true
+		p.selected = len(m.zones)
+
+	case "d", "delete":
+		if !p.loading && p.selected < len(m.zones) {
+			p.confirmDelete = true
+		}
+
+	case "enter":
+		if p.loading {
+			return nil
+		}
+		if p.selected == len(m.zones) {
+			p.naming = true
+			return nil
+		}
+		zone := m.zones[p.selected]
+		m.zonePicker = nil
+		// Non-nil so that an emptied zone is saved empty
+		lightIDs := []string{}
+		if p.zoneMembership(zone) == len(p.lightIDs) {
+			for _, id := range zone.LightIDs {
+				if !slices.Contains(p.lightIDs, id) {
+					lightIDs = append(lightIDs, id)
+				}
+			}
+			m.notice = p.label + " removed from " + zone.Name
+		} else {
+			lightIDs = append(lightIDs, zone.LightIDs...)
+			for _, id := range p.lightIDs {
+				if !slices.Contains(lightIDs, id) {
+					lightIDs = append(lightIDs, id)
+				}
+			}
+			m.notice = p.label + " added to " + zone.Name
+		}
+		return func() tea.Msg {
+			return messages.RoomSaveMsg{Zone: true, ID: zone.ID, ChildIDs: lightIDs}
+		}
+	}
+	return nil
+}
+
+// updateZoneName handles keys while the name of a new zone is typed
+func (m *MainModel) updateZoneName(msg tea.KeyMsg) tea.Cmd {
+	p := m.zonePicker
+	switch msg.Type {
+	case tea.KeyEsc:
+		p.naming = false
+		p.name = ""
+
+	case tea.KeyBackspace:
+		if p.name != "" {
+			runes := []rune(p.name)
+			p.name = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeyEnter:
+		name := strings.TrimSpace(p.name)
+		if name == "" {
+			p.err = "enter a name"
+			return nil
+		}
+		for _, zone := range m.zones {
+			if strings.EqualFold(zone.Name, name) {
+				p.err = fmt.Sprintf("%q already exists", zone.Name)
+				return nil
+			}
+		}
+		m.zonePicker = nil
+		m.notice = "Zone " + name + " created with " + p.label
+		lightIDs := p.lightIDs
+		return func() tea.Msg { return messages.RoomSaveMsg{Zone: true, Name: name, ChildIDs: lightIDs} }
+
+	case tea.KeyRunes, tea.KeySpace:
+		if len([]rune(p.name))+len(msg.Runes) <= maxZoneName {
+			p.name += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// renderZonePicker renders the open zone picker for the side panel
+func (m MainModel) renderZonePicker() string {
+	p := m.zonePicker
+
+	var b strings.Builder
+	b.WriteString(styleSelected.Render("Zones of " + p.label))
+	b.WriteString("\n\n")
+
+	if p.loading {
+		b.WriteString(m.spinner.View() + " Loading zones...")
+		return b.String()
+	}
+
+	for i, zone := range m.zones {
+		check := "  "
+		switch p.zoneMembership(zone) {
+		case 0:
+		case len(p.lightIDs):
+			check = "✓ "
+		default:
+			check = "~ "
+		}
+		line := check + zone.Name
+		if i == p.selected {
+			b.WriteString(styleSelected.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	newZone := "+ New zone"
+	if p.naming {
+		newZone = "+ " + p.name + "█"
+	}
+	if p.selected == len(m.zones) {
+		b.WriteString(styleSelected.Render("> " + newZone))
+	} else {
+		b.WriteString("  " + styleMuted.Render(newZone))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case p.err != "":
+		b.WriteString(styleLightFaulty.Render(p.err))
+	case p.confirmDelete:
+		b.WriteString(styleLightFaulty.Render("Delete " + m.zones[p.selected].Name + "? (y/n)"))
+	case p.naming:
+		b.WriteString(styleMuted.Render("enter create · esc cancel"))
+	default:
+		b.WriteString(styleMuted.Render("enter add/remove · n new · d delete · esc close"))
+	}
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/tui/components"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// maxVisibleToasts is how many toasts are shown at once, the others
+	// wait for a free spot
+	maxVisibleToasts = 3
+	// maxToastHistory is how many past toasts the notifications screen keeps
+	maxToastHistory = 100
+	// toastTickInterval is how often shown toasts are checked for expiry
+	toastTickInterval = 500 * time.Millisecond
+)
+
+// toastTickMsg expires the toasts that were shown long enough
+type toastTickMsg struct{}
+
+// shownToast is a toast on screen, until expires
+type shownToast struct {
+	components.Toast
+	expires time.Time
+}
+
+// toastQueue holds the toasts on screen, the ones waiting for a spot, and
+// all the recent ones for the notifications screen
+type toastQueue struct {
+	shown   []shownToast
+	waiting []components.Toast
+	history []components.Toast
+	ticking bool
+}
+
+func newToastQueue() *toastQueue {
+	return &toastQueue{}
+}
+
+// push queues a toast, shown right away when there is room
+func (q *toastQueue) push(toast components.Toast) {
+	q.history = append(q.history, toast)
+	if len(q.history) > maxToastHistory {
+		q.history = q.history[len(q.history)-maxToastHistory:]
+	}
+	q.waiting = append(q.waiting, toast)
+	q.expire(toast.At)
+}
+
+// expire drops the toasts shown long enough, and shows waiting ones in
+// their place
+func (q *toastQueue) expire(now time.Time) {
+	kept := q.shown[:0]
+	for _, t := range q.shown {
+		if now.Before(t.expires) {
+			kept = append(kept, t)
+		}
+	}
+	q.shown = kept
+	for len(q.shown) < maxVisibleToasts && len(q.waiting) > 0 {
+		next := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		q.shown = append(q.shown, shownToast{Toast: next, expires: now.Add(next.Duration())})
+	}
+}
+
+// dismiss takes every toast off the screen, they stay in the history
+func (q *toastQueue) dismiss() {
+	q.shown = nil
+	q.waiting = nil
+}
+
+// clear forgets every toast
+func (q *toastQueue) clear() {
+	q.dismiss()
+	q.history = nil
+}
+
+// tick starts the expiry ticks while toasts are shown
+func (q *toastQueue) tick() tea.Cmd {
+	if q.ticking || len(q.shown) == 0 {
+		return nil
+	}
+	q.ticking = true
+	return tea.Tick(toastTickInterval, func(time.Time) tea.Msg { return toastTickMsg{} })
+}
+
+// handleToastTick expires toasts and keeps ticking while some are left
+func (m *Model) handleToastTick(now time.Time) tea.Cmd {
+	m.toasts.ticking = false
+	m.toasts.expire(now)
+	return m.toasts.tick()
+}
+
+// notify shows a toast
+func (m *Model) notify(severity components.ToastSeverity, message string) {
+	message = strings.Join(strings.Fields(message), " ")
+	m.toasts.push(components.Toast{Severity: severity, Message: message, At: time.Now()})
+}
+
+// reportError shows an error that doesn't stop the app as a toast
+func (m *Model) reportError(err error) {
+	tuiLog.Errorf("%v", err)
+	m.notify(components.ToastError, err.Error())
+}
+
+// blockingError reports whether an error needs the error panel and its
+// recovery actions, instead of a toast: the bridge can't be used, or
+// nothing could be loaded yet
+func (m Model) blockingError(err error) bool {
+	switch errorCategory(err) {
+	case errorConnection, errorAuth, errorSetup:
+		return true
+	}
+	return len(m.rooms) == 0
+}
+
+// renderToasts lays the shown toasts over the bottom lines of view
+func (m Model) renderToasts(view string) string {
+	if len(m.toasts.shown) == 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	start := max(0, len(lines)-len(m.toasts.shown))
+	for i, t := range m.toasts.shown {
+		if start+i < len(lines) {
+			lines[start+i] = components.RenderToast(m.width, t.Toast)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
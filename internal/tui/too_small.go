@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angristan/hue-tui/internal/tui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Smallest terminal the UI is laid out for. The modals are 46 columns
+// wide with their border, and the main screen needs room for a few rows
+// between its header and help line.
+const (
+	minWidth  = 48
+	minHeight = 12
+)
+
+// tooSmall reports whether the terminal is below the minimum size. Until
+// the first resize the size is unknown and assumed to fit.
+func (m Model) tooSmall() bool {
+	return m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight)
+}
+
+// handleTooSmallKey only lets q quit while the terminal is too small, so
+// that no key acts on a screen that can't be seen
+func (m *Model) handleTooSmallKey(key string) tea.Cmd {
+	if key == "q" {
+		m.cancel()
+		return tea.Quit
+	}
+	return nil
+}
+
+// renderTooSmall replaces the UI with the size needed, until the terminal
+// is resized back
+func (m Model) renderTooSmall() string {
+	lines := []string{
+		styles.StyleModalTitle.Render("Terminal too small"),
+		fmt.Sprintf("%d×%d, needs %d×%d", m.width, m.height, minWidth, minHeight),
+		styles.StyleTextMuted.Render("Resize to continue, q to quit"),
+	}
+	text := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(strings.Join(lines, "\n"))
+	if rows := strings.Split(text, "\n"); len(rows) > m.height {
+		text = strings.Join(rows[:m.height], "\n")
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, text)
+}
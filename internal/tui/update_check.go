@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/angristan/hue-tui/internal/update"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateAvailableMsg reports a release newer than this build
+type updateAvailableMsg struct {
+	release update.Release
+}
+
+// SetVersion sets the version of this build, for the update check
+func (m *Model) SetVersion(version string) {
+	m.version = version
+}
+
+// updateCheck looks for a newer release in the background, when enabled
+// with update_check. The answer is cached for a week, and a failed check
+// is only logged.
+func (m Model) updateCheck() tea.Cmd {
+	if m.demoMode || m.config == nil || !m.config.UpdateCheck || !update.IsRelease(m.version) {
+		return nil
+	}
+	current := m.version
+	return func() tea.Msg {
+		release, err := update.New().Latest(context.Background(), update.WeeklyCheck)
+		if err != nil {
+			tuiLog.Debugf("Update check failed: %v", err)
+			return nil
+		}
+		if !update.Newer(current, release.Version) {
+			return nil
+		}
+		return updateAvailableMsg{release: release}
+	}
+}
@@ -0,0 +1,211 @@
+// Package update checks GitHub for a newer release of hue-tui, caching the
+// answer so that the check runs at most once per period.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ReleasesURL is the GitHub API endpoint of the latest release
+	ReleasesURL = "https://api.github.com/repos/angristan/hue-tui/releases/latest"
+	// Timeout bounds a check, so that a slow network never holds anything up
+	Timeout = 5 * time.Second
+	// WeeklyCheck is how long the TUI reuses a cached answer
+	WeeklyCheck = 7 * 24 * time.Hour
+
+	cacheFile = "update.json"
+)
+
+// Release is the latest published release
+type Release struct {
+	Version   string    `json:"version"`
+	URL       string    `json:"url"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Checker fetches the latest release, through a cache file
+type Checker struct {
+	// Releases endpoint, ReleasesURL by default
+	URL string
+	// Cache file, update.json in $XDG_CACHE_HOME/hue-cli by default
+	CachePath string
+	Client    *http.Client
+}
+
+// New creates a checker with the default endpoint and cache
+func New() *Checker {
+	return &Checker{URL: ReleasesURL, Client: &http.Client{Timeout: Timeout}}
+}
+
+// DefaultCachePath returns update.json in $XDG_CACHE_HOME/hue-cli, or
+// ~/.cache/hue-cli
+func DefaultCachePath() (string, error) {
+	if xdgCache := os.Getenv("XDG_CACHE_HOME"); xdgCache != "" {
+		return filepath.Join(xdgCache, "hue-cli", cacheFile), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", "hue-cli", cacheFile), nil
+}
+
+// Latest returns the latest release, from the cache when it was checked
+// less than maxAge ago. A zero maxAge always asks GitHub.
+func (c *Checker) Latest(ctx context.Context, maxAge time.Duration) (Release, error) {
+	cachePath := c.CachePath
+	if cachePath == "" {
+		path, err := DefaultCachePath()
+		if err != nil {
+			return Release{}, err
+		}
+		cachePath = path
+	}
+
+	if maxAge > 0 {
+		if cached, err := readCache(cachePath); err == nil && time.Since(cached.CheckedAt) < maxAge {
+			return cached, nil
+		}
+	}
+
+	release, err := c.fetch(ctx)
+	if err != nil {
+		return Release{}, err
+	}
+	// The cache only saves checks, failing to write it isn't an error
+	_ = writeCache(cachePath, release)
+	return release, nil
+}
+
+// fetch asks GitHub for the latest release
+func (c *Checker) fetch(ctx context.Context) (release Release, err error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.URL, nil)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: Timeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close response body: %w", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("failed to check for updates: GitHub returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Release{}, fmt.Errorf("failed to decode release: %w", err)
+	}
+	if body.TagName == "" {
+		return Release{}, fmt.Errorf("failed to check for updates: release has no tag")
+	}
+	return Release{Version: body.TagName, URL: body.HTMLURL, CheckedAt: time.Now()}, nil
+}
+
+func readCache(path string) (Release, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Release{}, err
+	}
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return Release{}, err
+	}
+	return release, nil
+}
+
+func writeCache(path string, release Release) error {
+	data, err := json.Marshal(release)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Newer reports whether latest is a higher version than current. Versions
+// are compared as major.minor.patch, with or without a leading v; anything
+// else, like a "dev" build, is never outdated.
+func Newer(current, latest string) bool {
+	cur, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	next, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range cur {
+		if next[i] != cur[i] {
+			return next[i] > cur[i]
+		}
+	}
+	return false
+}
+
+// IsRelease reports whether v is a released version that can be compared,
+// unlike a "dev" build
+func IsRelease(v string) bool {
+	_, ok := parseVersion(v)
+	return ok
+}
+
+// parseVersion splits "v1.4.2" into its numbers. A pre-release or build
+// suffix is ignored.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// Hint is the one-line notice of a newer release, like "v1.4.0 available:
+// https://github.com/..."
+func (r Release) Hint() string {
+	hint := r.Version + " available"
+	if r.URL != "" {
+		hint += ": " + r.URL
+	}
+	return hint
+}
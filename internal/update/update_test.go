@@ -0,0 +1,91 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.3.0", "v1.4.0", true},
+		{"v1.4.0", "v1.4.0", false},
+		{"v1.4.1", "v1.4.0", false},
+		{"1.9.9", "2.0.0", true},
+		{"1.4", "1.4.1", true},
+		{"v1.4.0-rc1", "v1.4.0", false},
+		{"dev", "v1.4.0", false},
+		{"1.4.0", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestLatestCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"tag_name":"v1.4.0","html_url":"https://github.com/angristan/hue-tui/releases/tag/v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	c := &Checker{URL: server.URL, CachePath: filepath.Join(t.TempDir(), "update.json")}
+	ctx := context.Background()
+
+	release, err := c.Latest(ctx, WeeklyCheck)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if release.Version != "v1.4.0" {
+		t.Errorf("Version = %q, want v1.4.0", release.Version)
+	}
+	if want := "v1.4.0 available: https://github.com/angristan/hue-tui/releases/tag/v1.4.0"; release.Hint() != want {
+		t.Errorf("Hint = %q, want %q", release.Hint(), want)
+	}
+
+	if _, err := c.Latest(ctx, WeeklyCheck); err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("GitHub called %d times, want 1 with a fresh cache", calls)
+	}
+
+	if _, err := c.Latest(ctx, 0); err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("GitHub called %d times, want 2 without a cache", calls)
+	}
+
+	if err := writeCache(c.CachePath, Release{Version: "v1.3.0", CheckedAt: time.Now().Add(-8 * 24 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	release, err = c.Latest(ctx, WeeklyCheck)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if calls != 3 || release.Version != "v1.4.0" {
+		t.Errorf("stale cache: %d calls, version %q, want a new check", calls, release.Version)
+	}
+}
+
+func TestLatestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := &Checker{URL: server.URL, CachePath: filepath.Join(t.TempDir(), "update.json")}
+	if _, err := c.Latest(context.Background(), WeeklyCheck); err == nil {
+		t.Error("Latest succeeded on status 403")
+	}
+}
@@ -0,0 +1,235 @@
+// Package watch turns bridge events into one line per light or room state
+// change, for scripts reading `hue watch`.
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+// Output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Change is the state of a light or room after a change
+type Change struct {
+	Time time.Time `json:"time"`
+	// "light" or "room"
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Room of a light
+	Room string `json:"room,omitempty"`
+	On   bool   `json:"on"`
+	// Brightness in percent
+	Brightness int `json:"brightness"`
+	// Lights only
+	Reachable *bool       `json:"reachable,omitempty"`
+	Mirek     *int        `json:"mirek,omitempty"`
+	XY        *[2]float64 `json:"xy,omitempty"`
+}
+
+// Watcher keeps track of light and room state and writes every change
+type Watcher struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format string
+	now    func() time.Time
+	// How times are shown in text output
+	locale locale.Format
+
+	lights map[string]*models.Light
+	// Room of each light, by light ID
+	lightRooms map[string]*models.Room
+	// Rooms by grouped light ID, with their last known state
+	groups     map[string]*models.Room
+	roomOn     map[string]bool
+	brightness map[string]int
+}
+
+// New creates a watcher writing changes to out, starting from rooms
+func New(out io.Writer, format string, rooms []*models.Room) (*Watcher, error) {
+	if format != FormatJSON && format != FormatText {
+		return nil, fmt.Errorf("unknown format %q (expected %s or %s)", format, FormatJSON, FormatText)
+	}
+	w := &Watcher{
+		out:        out,
+		format:     format,
+		now:        time.Now,
+		lights:     make(map[string]*models.Light),
+		lightRooms: make(map[string]*models.Room),
+		groups:     make(map[string]*models.Room),
+		roomOn:     make(map[string]bool),
+		brightness: make(map[string]int),
+	}
+	for _, room := range rooms {
+		for _, light := range room.Lights {
+			w.lights[light.ID] = light
+			w.lightRooms[light.ID] = room
+		}
+		if room.GroupedLightID != "" {
+			w.groups[room.GroupedLightID] = room
+			w.roomOn[room.ID] = room.AnyOn
+			w.brightness[room.ID] = room.AverageBrightness()
+		}
+	}
+	return w, nil
+}
+
+// SetLocale sets how times are shown in text output
+func (w *Watcher) SetLocale(format locale.Format) {
+	w.locale = format
+}
+
+// Handle applies bridge events and writes the resulting changes. It
+// matches api.EventHandler so it can be passed to NewEventSubscription.
+func (w *Watcher) Handle(events []api.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, event := range events {
+		if event.Type != api.EventTypeUpdate {
+			continue
+		}
+		switch event.Resource {
+		case "light":
+			if update, err := api.ParseLightUpdate(event); err == nil {
+				w.applyLight(update)
+			}
+		case "grouped_light":
+			if update, err := api.ParseGroupedLightUpdate(event); err == nil {
+				w.applyGroup(update)
+			}
+		case "zigbee_connectivity":
+			if update, err := api.ParseConnectivityUpdate(event); err == nil {
+				w.applyConnectivity(update)
+			}
+		}
+	}
+}
+
+func (w *Watcher) applyLight(update *api.LightUpdateEvent) {
+	light, ok := w.lights[update.ID]
+	if !ok {
+		return
+	}
+	if update.On != nil {
+		light.On = *update.On
+	}
+	if update.Brightness != nil {
+		light.SetBrightnessPct(models.RoundPct(*update.Brightness))
+	}
+	// The bridge reports mirek 0 when switching to xy color
+	if update.ColorTemp != nil && *update.ColorTemp > 0 {
+		if light.Color == nil {
+			light.Color = &models.Color{}
+		}
+		light.Color.Mirek = uint16(*update.ColorTemp)
+		light.Color.Mode = models.ColorModeColorTemp
+	} else if update.ColorXY != nil {
+		if light.Color == nil {
+			light.Color = &models.Color{}
+		}
+		light.Color.X, light.Color.Y = update.ColorXY.X, update.ColorXY.Y
+		light.Color.Mode = models.ColorModeXY
+	}
+	w.writeLight(light)
+}
+
+func (w *Watcher) applyGroup(update *api.GroupedLightUpdateEvent) {
+	room, ok := w.groups[update.ID]
+	if !ok {
+		return
+	}
+	if update.On != nil {
+		w.roomOn[room.ID] = *update.On
+	}
+	if update.Brightness != nil {
+		w.brightness[room.ID] = models.RoundPct(*update.Brightness)
+	}
+	w.write(Change{
+		Type:       "room",
+		ID:         room.ID,
+		Name:       room.Name,
+		On:         w.roomOn[room.ID],
+		Brightness: w.brightness[room.ID],
+	})
+}
+
+func (w *Watcher) applyConnectivity(update *api.ConnectivityUpdateEvent) {
+	for _, light := range w.lights {
+		if light.DeviceID == update.DeviceID && light.Reachable != update.Reachable {
+			light.Reachable = update.Reachable
+			w.writeLight(light)
+		}
+	}
+}
+
+func (w *Watcher) writeLight(light *models.Light) {
+	reachable := light.Reachable
+	change := Change{
+		Type:       "light",
+		ID:         light.ID,
+		Name:       light.Name,
+		On:         light.On,
+		Brightness: light.BrightnessPct(),
+		Reachable:  &reachable,
+	}
+	if room, ok := w.lightRooms[light.ID]; ok {
+		change.Room = room.Name
+	}
+	if c := light.Color; c != nil {
+		switch c.Mode {
+		case models.ColorModeColorTemp:
+			mirek := int(c.Mirek)
+			change.Mirek = &mirek
+		case models.ColorModeXY:
+			change.XY = &[2]float64{c.X, c.Y}
+		}
+	}
+	w.write(change)
+}
+
+// write prints a change. Write errors are ignored: a closed stdout ends
+// the process anyway.
+func (w *Watcher) write(change Change) {
+	change.Time = w.now()
+	if w.format == FormatText {
+		_, _ = fmt.Fprintln(w.out, change.text(w.locale))
+		return
+	}
+	data, err := json.Marshal(change)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w.out, "%s\n", data)
+}
+
+// text renders a change as a human-readable line
+func (c Change) text(format locale.Format) string {
+	var b strings.Builder
+	b.WriteString(format.Time(c.Time))
+	b.WriteString(" " + c.Type + " " + c.Name)
+	if c.Room != "" {
+		b.WriteString(" (" + c.Room + ")")
+	}
+	switch {
+	case c.Reachable != nil && !*c.Reachable:
+		b.WriteString(" unreachable")
+	case c.On:
+		fmt.Fprintf(&b, " on %d%%", c.Brightness)
+	default:
+		b.WriteString(" off")
+	}
+	return b.String()
+}
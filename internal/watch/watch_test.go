@@ -0,0 +1,107 @@
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angristan/hue-tui/internal/api"
+	"github.com/angristan/hue-tui/internal/config"
+	"github.com/angristan/hue-tui/internal/locale"
+	"github.com/angristan/hue-tui/internal/models"
+)
+
+func testRooms() []*models.Room {
+	lamp := &models.Light{ID: "l1", Name: "Lamp", DeviceID: "d1", Reachable: true, On: true, Brightness: 127}
+	room := &models.Room{ID: "r1", Name: "Study", GroupedLightID: "g1", Lights: []*models.Light{lamp}}
+	room.UpdateState()
+	return []*models.Room{room}
+}
+
+func event(resource, data string) api.Event {
+	return api.Event{Type: api.EventTypeUpdate, Resource: resource, Data: json.RawMessage(data)}
+}
+
+func TestWatchJSON(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(&out, FormatJSON, testRooms())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	w.now = func() time.Time { return time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC) }
+
+	w.Handle([]api.Event{
+		event("light", `{"id": "l1", "dimming": {"brightness": 75}, "color_temperature": {"mirek": 366}}`),
+		event("grouped_light", `{"id": "g1", "on": {"on": false}}`),
+		// Unknown resources and lights are skipped
+		event("light", `{"id": "unknown", "on": {"on": false}}`),
+		event("motion", `{"id": "m1"}`),
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", out.String())
+	}
+	var light Change
+	if err := json.Unmarshal([]byte(lines[0]), &light); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[0], err)
+	}
+	if light.Type != "light" || light.Name != "Lamp" || light.Room != "Study" || !light.On || light.Brightness != 75 {
+		t.Errorf("Unexpected light change %+v", light)
+	}
+	if light.Mirek == nil || *light.Mirek != 366 || light.XY != nil {
+		t.Errorf("Expected a 366 mirek color temperature, got %+v", light)
+	}
+	if !light.Time.Equal(w.now()) {
+		t.Errorf("Expected the change time, got %v", light.Time)
+	}
+
+	var room Change
+	if err := json.Unmarshal([]byte(lines[1]), &room); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[1], err)
+	}
+	if room.Type != "room" || room.ID != "r1" || room.On || room.Brightness != 50 || room.Reachable != nil {
+		t.Errorf("Unexpected room change %+v", room)
+	}
+}
+
+func TestWatchText(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(&out, FormatText, testRooms())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	w.now = func() time.Time { return time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC) }
+
+	w.Handle([]api.Event{
+		event("light", `{"id": "l1", "on": {"on": false}}`),
+		event("zigbee_connectivity", `{"id": "z1", "owner": {"rid": "d1", "rtype": "device"}, "status": "connectivity_issue"}`),
+		// Unchanged connectivity isn't a change
+		event("zigbee_connectivity", `{"id": "z1", "owner": {"rid": "d1", "rtype": "device"}, "status": "connectivity_issue"}`),
+	})
+
+	want := "20:00:00 light Lamp (Study) off\n20:00:00 light Lamp (Study) unreachable\n"
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	// A 12-hour clock can be configured
+	clock12, err := locale.New(&config.Locale{TimeFormat: "12h"})
+	if err != nil {
+		t.Fatalf("locale.New returned error: %v", err)
+	}
+	w.SetLocale(clock12)
+	out.Reset()
+	w.Handle([]api.Event{event("light", `{"id": "l1", "on": {"on": true}}`)})
+	if !strings.HasPrefix(out.String(), "8:00:00 PM light Lamp") {
+		t.Errorf("Expected a 12-hour time, got %q", out.String())
+	}
+}
+
+func TestWatchUnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "yaml", nil); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}